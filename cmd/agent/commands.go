@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/commands"
+	"github.com/jbetancur/dashboard/internal/pkg/supportbundle"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// defaultListLimit caps one-off lists when the command sets no limit
+const defaultListLimit = 500
+
+// bundleEventLimit caps the warning events in a support bundle
+const bundleEventLimit = 100
+
+// NodeSummary is a node's readiness in a support bundle
+type NodeSummary struct {
+	Name           string `json:"name"`
+	Ready          bool   `json:"ready"`
+	KubeletVersion string `json:"kubeletVersion"`
+	Unschedulable  bool   `json:"unschedulable"`
+}
+
+// SupportBundle is the diagnostic snapshot an agent collects for its cluster
+type SupportBundle struct {
+	Agent         *cluster.AgentInfo `json:"agent"`
+	Nodes         []NodeSummary      `json:"nodes"`
+	UnhealthyPods []string           `json:"unhealthyPods"`
+	Warnings      []corev1.Event     `json:"warnings"`
+	Errors        []string           `json:"errors,omitempty"`
+}
+
+// registerCommands installs the agent's command handlers
+func registerCommands(executor *commands.Executor, clientManager *cluster.ClientManager, managers []*ClusterManagers, token *agentToken, logger *slog.Logger) {
+	byCluster := make(map[string]*ClusterManagers, len(managers))
+	for _, manager := range managers {
+		byCluster[manager.Cluster] = manager
+	}
+
+	executor.Handle(commands.TypeResync, func(ctx context.Context, command commands.Command) (any, error) {
+		manager, ok := byCluster[command.ClusterID]
+		if !ok {
+			return nil, fmt.Errorf("cluster %s is not served by this agent", command.ClusterID)
+		}
+
+		namespaceCount, err := manager.NamespaceManager.Resync()
+		if err != nil {
+			return nil, err
+		}
+
+		podCount, err := manager.PodManager.Resync()
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("Resynced cluster", "cluster", command.ClusterID, "namespaces", namespaceCount, "pods", podCount)
+		return map[string]int{"namespaces": namespaceCount, "pods": podCount}, nil
+	})
+
+	executor.Handle(commands.TypeSupportBundle, func(ctx context.Context, command commands.Command) (any, error) {
+		conn, ok := clientManager.GetClient(command.ClusterID)
+		if !ok {
+			return nil, fmt.Errorf("cluster %s is not served by this agent", command.ClusterID)
+		}
+
+		return collectSupportBundle(ctx, conn), nil
+	})
+
+	executor.Handle(commands.TypeDiagnosticBundle, func(ctx context.Context, command commands.Command) (any, error) {
+		conn, ok := clientManager.GetClient(command.ClusterID)
+		if !ok {
+			return nil, fmt.Errorf("cluster %s is not served by this agent", command.ClusterID)
+		}
+
+		req, err := supportbundle.RequestFromArgs(command.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		// Encoded as base64 in the result
+		return supportbundle.Build(ctx, conn, req)
+	})
+
+	executor.Handle(commands.TypeRotateCredentials, func(ctx context.Context, command commands.Command) (any, error) {
+		if _, ok := byCluster[command.ClusterID]; !ok {
+			return nil, fmt.Errorf("cluster %s is not served by this agent", command.ClusterID)
+		}
+		if command.Args["token"] == "" {
+			return nil, fmt.Errorf("missing token")
+		}
+
+		// The result is sent with the new token, which confirms the rotation
+		if err := token.Set(command.Args["token"]); err != nil {
+			return nil, err
+		}
+
+		logger.Info("Rotated agent token", "cluster", command.ClusterID)
+		return map[string]bool{"rotated": true}, nil
+	})
+
+	executor.Handle(commands.TypeList, func(ctx context.Context, command commands.Command) (any, error) {
+		conn, ok := clientManager.GetClient(command.ClusterID)
+		if !ok {
+			return nil, fmt.Errorf("cluster %s is not served by this agent", command.ClusterID)
+		}
+
+		return listResources(ctx, conn, command.Args)
+	})
+}
+
+// collectSupportBundle gathers node readiness, unhealthy pods and recent
+// warnings. Parts that fail are reported in the bundle instead of failing it.
+func collectSupportBundle(ctx context.Context, conn *cluster.Connection) *SupportBundle {
+	bundle := &SupportBundle{
+		Nodes:         make([]NodeSummary, 0),
+		UnhealthyPods: make([]string, 0),
+		Warnings:      make([]corev1.Event, 0),
+	}
+
+	agent, err := cluster.Handshake(ctx, conn, version, kinds)
+	if err != nil {
+		bundle.Errors = append(bundle.Errors, err.Error())
+	}
+	bundle.Agent = agent
+
+	nodes, err := conn.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		bundle.Errors = append(bundle.Errors, fmt.Sprintf("failed to list nodes: %v", err))
+	} else {
+		for _, node := range nodes.Items {
+			summary := NodeSummary{
+				Name:           node.Name,
+				KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+				Unschedulable:  node.Spec.Unschedulable,
+			}
+			for _, condition := range node.Status.Conditions {
+				if condition.Type == corev1.NodeReady {
+					summary.Ready = condition.Status == corev1.ConditionTrue
+				}
+			}
+			bundle.Nodes = append(bundle.Nodes, summary)
+		}
+	}
+
+	pods, err := conn.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Running,status.phase!=Succeeded",
+	})
+	if err != nil {
+		bundle.Errors = append(bundle.Errors, fmt.Sprintf("failed to list pods: %v", err))
+	} else {
+		for _, pod := range pods.Items {
+			bundle.UnhealthyPods = append(bundle.UnhealthyPods, pod.Namespace+"/"+pod.Name)
+		}
+	}
+
+	events, err := conn.Client.CoreV1().Events("").List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + corev1.EventTypeWarning,
+		Limit:         bundleEventLimit,
+	})
+	if err != nil {
+		bundle.Errors = append(bundle.Errors, fmt.Sprintf("failed to list events: %v", err))
+	} else {
+		bundle.Warnings = events.Items
+	}
+
+	return bundle
+}
+
+// listResources lists a supported kind. Args are kind, and optionally
+// namespace, labelSelector and limit.
+func listResources(ctx context.Context, conn *cluster.Connection, args map[string]string) (any, error) {
+	kind, ok := assets.LookupKind(args["kind"])
+	if !ok {
+		return nil, fmt.Errorf("unsupported kind %q", args["kind"])
+	}
+
+	limit := int64(defaultListLimit)
+	if value := args["limit"]; value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid limit %q", value)
+		}
+		limit = parsed
+	}
+
+	client, err := dynamic.NewForConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	gvr := schema.GroupVersionResource{Group: kind.Group, Version: kind.Version, Resource: kind.Resource}
+	list, err := client.Resource(gvr).Namespace(args["namespace"]).List(ctx, metav1.ListOptions{
+		LabelSelector: args["labelSelector"],
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", kind.Resource, err)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// agentToken holds the token sent with every message to the REST API. It
+// starts from AGENT_TOKEN_FILE or AGENT_TOKEN and is replaced when the API
+// rotates it. Rotated tokens are written back to the file, if there is one,
+// so they survive a restart.
+type agentToken struct {
+	token string
+	file  string
+	mu    sync.RWMutex
+}
+
+// loadAgentToken reads the initial token from the environment
+func loadAgentToken() (*agentToken, error) {
+	t := &agentToken{
+		token: os.Getenv("AGENT_TOKEN"),
+		file:  os.Getenv("AGENT_TOKEN_FILE"),
+	}
+
+	if t.file == "" {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(t.file)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read agent token: %w", err)
+	default:
+		t.token = strings.TrimSpace(string(data))
+	}
+
+	return t, nil
+}
+
+// Get returns the current token
+func (t *agentToken) Get() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.token
+}
+
+// Set replaces the token, saving it first so a failed write keeps the old one
+func (t *agentToken) Set(token string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file != "" {
+		if err := os.WriteFile(t.file, []byte(token+"\n"), 0o600); err != nil {
+			return fmt.Errorf("failed to save agent token: %w", err)
+		}
+	}
+
+	t.token = token
+	return nil
+}
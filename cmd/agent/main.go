@@ -7,31 +7,60 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/jbetancur/dashboard/internal/pkg/assets/deployments"
 	"github.com/jbetancur/dashboard/internal/pkg/assets/namespaces"
 	"github.com/jbetancur/dashboard/internal/pkg/assets/pods"
 	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/commands"
+	"github.com/jbetancur/dashboard/internal/pkg/logging"
 	"github.com/jbetancur/dashboard/internal/pkg/messaging"
 	messagetypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	"github.com/jbetancur/dashboard/internal/pkg/throttle"
+	"github.com/jbetancur/dashboard/internal/pkg/tunnel"
 )
 
+// version is the agent version, set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+// serverStatusInterval is how often the version and control plane health of
+// each cluster is reported
+const serverStatusInterval = time.Minute
+
+// kinds are the resource kinds the agent watches and publishes
+var kinds = []string{"Namespace", "Pod"}
+
 type ClusterManagers struct {
 	Cluster          string
 	NamespaceManager *namespaces.Manager
 	PodManager       *pods.Manager
+	// DeploymentManager only publishes lifecycle events; deployments are
+	// not in kinds because the API does not store them
+	DeploymentManager *deployments.Manager
 	// Add other managers as needed
 }
 
 func main() {
-	// Initialize logger
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	// Initialize logger from AGENT_LOG_FORMAT (text or json) and AGENT_LOG_LEVEL
+	logger, logLevel, err := logging.New(logging.Config{
+		Format: os.Getenv("AGENT_LOG_FORMAT"),
+		Level:  os.Getenv("AGENT_LOG_LEVEL"),
+	}, os.Stdout)
+	if err != nil {
+		slog.Error("Failed to configure logging", "error", err)
+		return
+	}
 	slog.SetDefault(logger)
-	logger.Info("Starting cluster agent")
+	logger.Info("Starting cluster agent", "logFormat", logLevel.Format(), "logLevel", logLevel.Get().String())
 
 	// Create a context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// SIGHUP switches between debug and the configured level
+	logLevel.WatchSignals(ctx, logger)
+
 	// Set up signal handling for graceful shutdown
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
@@ -42,11 +71,28 @@ func main() {
 		cancel()
 	}()
 
+	// Load the token the REST API knows this agent by
+	token, err := loadAgentToken()
+	if err != nil {
+		logger.Error("Failed to load agent token", "error", err)
+		return
+	}
+
+	// Load when the agent sheds load during event storms
+	throttleConfig, err := loadThrottleConfig()
+	if err != nil {
+		logger.Error("Failed to load throttle config", "error", err)
+		return
+	}
+	loadThrottle := throttle.New(throttleConfig, logger)
+	loadThrottle.Start(ctx)
+
 	// Initialize the messaging client
 	messagingConfig := messaging.Config{
 		Type:          messaging.GRPCProvider,
 		ServerAddress: ":50052", // Agent's server address (for receiving)
 		ClientAddress: ":50053", // REST API's server address (for sending)
+		Token:         token.Get,
 	}
 
 	messagingClient, err := messaging.NewClient(messagingConfig, logger)
@@ -92,10 +138,10 @@ func main() {
 	var managers []*ClusterManagers
 
 	for _, kubeClient := range kubeClients {
-		manager, err := setupClusterManagers(messagingClient, kubeClient.Cluster, kubeClient, logger)
+		manager, err := setupClusterManagers(ctx, messagingClient, kubeClient.ID, kubeClient, loadThrottle, logger)
 		if err != nil {
 			logger.Error("Failed to set up managers for cluster",
-				"cluster", kubeClient.Cluster,
+				"cluster", kubeClient.ID,
 				"error", err)
 			continue
 		}
@@ -105,6 +151,22 @@ func main() {
 	// Start all informers
 	startAllInformers(managers, logger)
 
+	// Report server versions and control plane health
+	cluster.ReportServerStatus(ctx, messagingClient, kubeClients, serverStatusInterval, logger)
+
+	// Tell the REST API this agent is alive
+	cluster.SendHeartbeats(ctx, messagingClient, kubeClients, version, loadThrottle.Degraded, cluster.HeartbeatInterval, logger)
+
+	// Accept commands from the REST API
+	executor := commands.NewExecutor(messagingClient, logger)
+	registerCommands(executor, clientManager, managers, token, logger)
+	executor.Start(ctx, messagingClient)
+
+	// Serve log streams the REST API proxies through this agent
+	if streamer, ok := messagingClient.(messagetypes.Streamer); ok {
+		streamer.ServeStreams(tunnel.NewServer(clientManager.GetClient, logger).Serve)
+	}
+
 	// Ensure proper cleanup
 	defer stopAllInformers(managers, logger)
 
@@ -112,17 +174,24 @@ func main() {
 	logger.Info("Context done, shutting down")
 }
 
-func setupClusterManagers(msgClient messagetypes.Publisher, clusterID string, client *cluster.ClusterConfig, logger *slog.Logger) (*ClusterManagers, error) {
+func setupClusterManagers(ctx context.Context, msgClient messagetypes.Publisher, clusterID string, client *cluster.Connection, loadThrottle *throttle.Throttle, logger *slog.Logger) (*ClusterManagers, error) {
+	// Advertise what this agent supports; missing cluster details do not block registration
+	agent, err := cluster.Handshake(ctx, client, version, kinds)
+	if err != nil {
+		logger.Warn("Incomplete agent handshake", "cluster", client.ID, "error", err)
+	}
+
 	// Send cluster registration using the new package
-	err := cluster.PublishConnection(msgClient, client.Cluster, client.Config.Host, logger)
+	err = cluster.PublishConnection(msgClient, client.ID, client.Config.Host, agent, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to publish cluster: %w", err)
 	}
 
 	return &ClusterManagers{
-		Cluster:          client.Cluster,
-		NamespaceManager: namespaces.NewManager(clusterID, msgClient, client.Client, logger),
-		PodManager:       pods.NewManager(clusterID, msgClient, client.Client, logger),
+		Cluster:           client.ID,
+		NamespaceManager:  namespaces.NewManager(clusterID, msgClient, client.Client, loadThrottle, logger),
+		PodManager:        pods.NewManager(clusterID, msgClient, client.Client, loadThrottle, logger),
+		DeploymentManager: deployments.NewManager(clusterID, msgClient, client.Client, loadThrottle, logger),
 	}, nil
 }
 
@@ -141,6 +210,12 @@ func startAllInformers(managers []*ClusterManagers, logger *slog.Logger) {
 				"cluster", manager.Cluster,
 				"error", err)
 		}
+
+		if err := manager.DeploymentManager.StartInformer(); err != nil {
+			logger.Error("Failed to start deployment informer",
+				"cluster", manager.Cluster,
+				"error", err)
+		}
 	}
 }
 
@@ -149,5 +224,6 @@ func stopAllInformers(managers []*ClusterManagers, logger *slog.Logger) {
 		logger.Info("Stopping informers", "cluster", manager.Cluster)
 		manager.NamespaceManager.Stop()
 		manager.PodManager.Stop()
+		manager.DeploymentManager.Stop()
 	}
 }
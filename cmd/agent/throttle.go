@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/throttle"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// loadThrottleConfig reads when the agent degrades from the environment.
+// Unset thresholds are not checked.
+//
+//	AGENT_MAX_EVENTS_PER_SECOND  informer events per second, e.g. 500
+//	AGENT_MAX_CPU                CPU in cores, e.g. 500m or 1.5
+//	AGENT_MAX_MEMORY             Go heap size, e.g. 256Mi
+//	AGENT_SAMPLE_RATE            publish one in this many status updates while degraded
+//	AGENT_DEGRADED_COOLDOWN      how long load must stay low to recover, e.g. 1m
+func loadThrottleConfig() (throttle.Config, error) {
+	var config throttle.Config
+
+	if value := os.Getenv("AGENT_MAX_EVENTS_PER_SECOND"); value != "" {
+		rate, err := strconv.Atoi(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid AGENT_MAX_EVENTS_PER_SECOND: %w", err)
+		}
+		config.MaxEventsPerSecond = rate
+	}
+
+	if value := os.Getenv("AGENT_MAX_CPU"); value != "" {
+		cpu, err := resource.ParseQuantity(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid AGENT_MAX_CPU: %w", err)
+		}
+		config.MaxCPU = cpu.AsApproximateFloat64()
+	}
+
+	if value := os.Getenv("AGENT_MAX_MEMORY"); value != "" {
+		memory, err := resource.ParseQuantity(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid AGENT_MAX_MEMORY: %w", err)
+		}
+		config.MaxMemoryBytes = uint64(memory.Value())
+	}
+
+	if value := os.Getenv("AGENT_SAMPLE_RATE"); value != "" {
+		rate, err := strconv.Atoi(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid AGENT_SAMPLE_RATE: %w", err)
+		}
+		config.SampleRate = rate
+	}
+
+	if value := os.Getenv("AGENT_DEGRADED_COOLDOWN"); value != "" {
+		coolDown, err := time.ParseDuration(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid AGENT_DEGRADED_COOLDOWN: %w", err)
+		}
+		config.CoolDown = coolDown
+	}
+
+	return config, nil
+}
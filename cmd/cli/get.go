@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/apiclient"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// requestTimeout bounds a single API call
+const requestTimeout = 30 * time.Second
+
+// kinds maps the names accepted by get to the kinds the API lists
+var kinds = map[string]string{
+	"ns":         "Namespace",
+	"namespace":  "Namespace",
+	"namespaces": "Namespace",
+	"po":         "Pod",
+	"pod":        "Pod",
+	"pods":       "Pod",
+	"cm":         "ConfigMap",
+	"configmap":  "ConfigMap",
+	"configmaps": "ConfigMap",
+}
+
+// runClusters lists the registered clusters
+func runClusters(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var opts options
+	flags := flag.NewFlagSet("clusters", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	opts.addFlags(flags)
+
+	if _, err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var clusters []cluster.ClusterInfo
+	if err := client.ListClusters(ctx, &clusters); err != nil {
+		return err
+	}
+
+	if opts.output == "json" {
+		return writeJSON(stdout, clusters)
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tAPI URL")
+	for _, c := range clusters {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.ID, c.Name, c.Status, c.APIURL)
+	}
+	return w.Flush()
+}
+
+// runGet lists or gets stored resources of a kind
+func runGet(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var opts options
+	var clusterID, namespace string
+	var allNamespaces bool
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	opts.addFlags(flags)
+	flags.StringVar(&clusterID, "c", "", "cluster ID")
+	flags.StringVar(&namespace, "n", "default", "namespace")
+	flags.BoolVar(&allNamespaces, "A", false, "list across all namespaces")
+
+	positional, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 || len(positional) > 2 {
+		return fmt.Errorf("%w: get takes a kind and an optional name", errUsage)
+	}
+	kind, ok := kinds[strings.ToLower(positional[0])]
+	if !ok {
+		return fmt.Errorf("%w: unsupported kind %q", errUsage, positional[0])
+	}
+	if clusterID == "" {
+		return fmt.Errorf("%w: -c is required", errUsage)
+	}
+	if allNamespaces {
+		namespace = apiclient.AllNamespaces
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var raw []json.RawMessage
+	if len(positional) == 2 {
+		var item json.RawMessage
+		if err := client.Get(ctx, clusterID, namespace, kind, positional[1], &item); err != nil {
+			return err
+		}
+		raw = append(raw, item)
+	} else if err := client.List(ctx, clusterID, namespace, kind, &raw); err != nil {
+		return err
+	}
+
+	if opts.output == "json" {
+		if len(positional) == 2 {
+			return writeJSON(stdout, raw[0])
+		}
+		return writeJSON(stdout, raw)
+	}
+	return writeTable(stdout, kind, raw, allNamespaces)
+}
+
+// writeTable prints resources in kubectl's table layout
+func writeTable(out io.Writer, kind string, raw []json.RawMessage, withNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	prefix := func(meta metav1.ObjectMeta) string {
+		if withNamespace {
+			return meta.Namespace + "\t"
+		}
+		return ""
+	}
+	header := func(columns string) {
+		if withNamespace {
+			columns = "NAMESPACE\t" + columns
+		}
+		fmt.Fprintln(w, columns)
+	}
+
+	switch kind {
+	case "Namespace":
+		fmt.Fprintln(w, "NAME\tSTATUS\tAGE")
+		for _, item := range raw {
+			var ns corev1.Namespace
+			if err := json.Unmarshal(item, &ns); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", ns.Name, ns.Status.Phase, age(ns.CreationTimestamp))
+		}
+	case "Pod":
+		header("NAME\tREADY\tSTATUS\tRESTARTS\tAGE")
+		for _, item := range raw {
+			var pod corev1.Pod
+			if err := json.Unmarshal(item, &pod); err != nil {
+				return err
+			}
+
+			ready, restarts := 0, int32(0)
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.Ready {
+					ready++
+				}
+				restarts += status.RestartCount
+			}
+			fmt.Fprintf(w, "%s%s\t%d/%d\t%s\t%d\t%s\n", prefix(pod.ObjectMeta), pod.Name, ready, len(pod.Spec.Containers),
+				pod.Status.Phase, restarts, age(pod.CreationTimestamp))
+		}
+	case "ConfigMap":
+		header("NAME\tDATA\tAGE")
+		for _, item := range raw {
+			var cm corev1.ConfigMap
+			if err := json.Unmarshal(item, &cm); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s%s\t%d\t%s\n", prefix(cm.ObjectMeta), cm.Name, len(cm.Data)+len(cm.BinaryData), age(cm.CreationTimestamp))
+		}
+	}
+
+	return w.Flush()
+}
+
+// age formats a creation time like kubectl
+func age(created metav1.Time) string {
+	if created.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(created.Time))
+}
+
+// writeJSON prints a value as indented JSON
+func writeJSON(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
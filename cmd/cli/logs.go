@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// runLogs prints a container's logs, following them with -f
+func runLogs(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var opts options
+	var clusterID, namespace, container string
+	var tail int64
+	var follow bool
+	flags := flag.NewFlagSet("logs", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	opts.addFlags(flags)
+	flags.StringVar(&clusterID, "c", "", "cluster ID")
+	flags.StringVar(&namespace, "n", "default", "namespace")
+	flags.StringVar(&container, "container", "", "container, defaults to the pod's first container")
+	flags.Int64Var(&tail, "tail", 100, "number of recent lines to print")
+	flags.BoolVar(&follow, "f", false, "follow the logs until interrupted")
+
+	positional, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("%w: logs takes a pod name", errUsage)
+	}
+	if clusterID == "" {
+		return fmt.Errorf("%w: -c is required", errUsage)
+	}
+	pod := positional[0]
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	if container == "" {
+		lookupCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+
+		var p corev1.Pod
+		if err := client.Get(lookupCtx, clusterID, namespace, "Pod", pod, &p); err != nil {
+			return err
+		}
+		if len(p.Spec.Containers) == 0 {
+			return fmt.Errorf("pod %s has no containers", pod)
+		}
+
+		container = p.Spec.Containers[0].Name
+		if len(p.Spec.Containers) > 1 {
+			fmt.Fprintf(stderr, "Defaulted container %q out of %d, use --container to choose\n", container, len(p.Spec.Containers))
+		}
+	}
+
+	if follow {
+		return client.StreamLogs(ctx, clusterID, namespace, pod, container, tail, stdout)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	logs, err := client.Logs(ctx, clusterID, namespace, pod, container, tail)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(stdout, logs)
+	return err
+}
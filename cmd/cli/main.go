@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jbetancur/dashboard/internal/pkg/apiclient"
+)
+
+const usage = `dashboard is a command line client for the kube-dashboard REST API.
+
+Usage:
+  dashboard clusters [-o table|json]
+  dashboard get <kind> [name] -c <cluster> [-n <namespace> | -A] [-o table|json]
+  dashboard logs <pod> -c <cluster> [-n <namespace>] [--container <name>] [--tail <lines>] [-f]
+
+Kinds: namespaces (ns), pods (po), configmaps (cm)
+
+The API is set with --api-url or KUBE_DASHBOARD_API_URL, and authenticated
+with --token/KUBE_DASHBOARD_TOKEN or --token-command/KUBE_DASHBOARD_TOKEN_COMMAND.
+`
+
+// errUsage reports invalid arguments, which exit with status 2
+var errUsage = errors.New("invalid usage")
+
+// options are the connection settings shared by every command
+type options struct {
+	apiURL       string
+	token        string
+	tokenCommand string
+	output       string
+}
+
+// addFlags registers the shared flags, defaulting to the environment
+func (o *options) addFlags(flags *flag.FlagSet) {
+	flags.StringVar(&o.apiURL, "api-url", os.Getenv("KUBE_DASHBOARD_API_URL"), "REST API URL")
+	flags.StringVar(&o.token, "token", os.Getenv("KUBE_DASHBOARD_TOKEN"), "bearer token")
+	flags.StringVar(&o.tokenCommand, "token-command", os.Getenv("KUBE_DASHBOARD_TOKEN_COMMAND"), "command printing a bearer token")
+	flags.StringVar(&o.output, "o", "table", "output format: table or json")
+}
+
+// client creates the API client after validating the shared flags
+func (o *options) client() (*apiclient.Client, error) {
+	if o.apiURL == "" {
+		return nil, fmt.Errorf("%w: --api-url or KUBE_DASHBOARD_API_URL is required", errUsage)
+	}
+	if o.output != "table" && o.output != "json" {
+		return nil, fmt.Errorf("%w: output must be table or json, got %q", errUsage, o.output)
+	}
+	return apiclient.New(o.apiURL, o.token, o.tokenCommand)
+}
+
+// parseArgs parses flags that may come before, between or after positional
+// arguments, e.g. "get pods -c cluster-1", returning the positional ones
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// run dispatches a command, writing its output to stdout
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return errUsage
+	}
+
+	switch args[0] {
+	case "clusters":
+		return runClusters(ctx, args[1:], stdout, stderr)
+	case "get":
+		return runGet(ctx, args[1:], stdout, stderr)
+	case "logs":
+		return runLogs(ctx, args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	default:
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
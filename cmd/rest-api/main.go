@@ -2,21 +2,63 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"os"
-	"plugin"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/alerting"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/apiresources"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/backup"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/bulk"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/capacity"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/certificates"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/compliance"
 	"github.com/jbetancur/dashboard/internal/pkg/assets/configmaps"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/cost"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/cronjobs"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/deployments"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/diff"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/drift"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/files"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/graph"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/images"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/live"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/metrics"
 	"github.com/jbetancur/dashboard/internal/pkg/assets/namespaces"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/nodes"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/pdbs"
 	"github.com/jbetancur/dashboard/internal/pkg/assets/pods"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/reconcile"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/rightsizing"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/storage"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/timeline"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/topology"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/vulnerabilities"
+	"github.com/jbetancur/dashboard/internal/pkg/audit"
 	"github.com/jbetancur/dashboard/internal/pkg/auth"
 	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/commands"
 	"github.com/jbetancur/dashboard/internal/pkg/config"
+	"github.com/jbetancur/dashboard/internal/pkg/eventqueue"
+	"github.com/jbetancur/dashboard/internal/pkg/features"
+	"github.com/jbetancur/dashboard/internal/pkg/listcache"
+	"github.com/jbetancur/dashboard/internal/pkg/logging"
+	"github.com/jbetancur/dashboard/internal/pkg/objectstore"
+	"github.com/jbetancur/dashboard/internal/pkg/prometheus"
 	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"github.com/jbetancur/dashboard/internal/pkg/providers/uploaded"
+	"github.com/jbetancur/dashboard/internal/pkg/reports"
 	"github.com/jbetancur/dashboard/internal/pkg/router"
 	"github.com/jbetancur/dashboard/internal/pkg/services"
+	"github.com/jbetancur/dashboard/internal/pkg/tunnel"
+	"github.com/jbetancur/dashboard/internal/pkg/webhooks"
+
+	// Built-in cluster providers
+	_ "github.com/jbetancur/dashboard/internal/pkg/providers/aks"
+	_ "github.com/jbetancur/dashboard/internal/pkg/providers/fleet"
+	_ "github.com/jbetancur/dashboard/internal/pkg/providers/gke"
+	_ "github.com/jbetancur/dashboard/internal/pkg/providers/kubeconfig"
+	_ "github.com/jbetancur/dashboard/internal/pkg/providers/static"
 )
 
 func main() {
@@ -24,10 +66,9 @@ func main() {
 
 	defer cancel()
 
-	// Initialize slog with a TextHandler (human-readable logs)
+	// Log as text until the configuration says otherwise
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
-	logger.Info("Starting application")
 
 	// Load configuration
 	appConfig, err := config.LoadConfig("config.yaml")
@@ -36,27 +77,34 @@ func main() {
 		return
 	}
 
-	// Load provider plugins
-	var clusterProvider providers.Provider
+	// Switch to the configured log format; the level can change at runtime
+	logger, logLevel, err := logging.New(appConfig.Logging, os.Stdout)
+	if err != nil {
+		slog.Error("Failed to configure logging", "error", err)
+		return
+	}
+	slog.SetDefault(logger)
+	logLevel.WatchSignals(ctx, logger)
+	logger.Info("Starting application", "logFormat", logLevel.Format(), "logLevel", logLevel.Get().String())
+
+	// Load cluster providers; the router sends each cluster to the provider that owns it
+	clusterProvider := providers.NewRouter(logger)
 
 	for _, providerConfig := range appConfig.Providers {
-		clusterProvider, err = loadProviderPlugin(providerConfig.Path, providerConfig.Config, logger)
+		provider, err := config.LoadProvider(providerConfig, logger)
 		if err != nil {
-			logger.Error("Failed to load provider plugin", "name", providerConfig.Name, "error", err)
+			logger.Error("Failed to load provider", "name", providerConfig.Name, "error", err)
 			return
 		}
 
-		logger.Info("Loaded provider plugin", "name", providerConfig.Name)
-	}
+		if err := clusterProvider.Add(providerConfig.Name, provider); err != nil {
+			logger.Error("Failed to add provider", "name", providerConfig.Name, "error", err)
+			return
+		}
 
-	// // Discover clusters
-	// clusters, err := clusterProvider.DiscoverClusters()
-	// if err != nil {
-	// 	logger.Error("Error discovering clusters", "error", err)
-	// 	return
-	// }
+		logger.Info("Loaded provider", "name", providerConfig.Name)
+	}
 
-	//
 	store, err := config.Store(ctx, logger)
 	if err != nil {
 		logger.Error("Failed to initialize store", "error", err)
@@ -71,8 +119,30 @@ func main() {
 		}
 	}()
 
+	// Large blobs such as log archives and snapshot manifests are kept out of the database
+	objects, err := objectstore.New(appConfig.ObjectStore)
+	if err != nil {
+		logger.Error("Failed to configure object store", "error", err)
+		return
+	}
+
+	// Feature flags gate experimental features per deployment and per cluster
+	featureFlags, err := features.NewManager(appConfig.Features, store, logger)
+	if err != nil {
+		logger.Error("Failed to configure feature flags", "error", err)
+		return
+	}
+	featureService := services.NewFeatureService(featureFlags, logger)
+
+	// Agents authenticate with per-cluster tokens once they are required
+	agentTokens := cluster.NewTokenVerifier(store.AgentTokenKnown)
+	var authenticateAgent func(ctx context.Context, token string) error
+	if appConfig.Agents.RequireToken {
+		authenticateAgent = agentTokens.Authenticate
+	}
+
 	// Initialize the messaging client for bidirectional communication
-	messagingClient, err := config.StartMessageClients(ctx, logger)
+	messagingClient, err := config.StartMessageClients(ctx, authenticateAgent, logger)
 	if err != nil {
 		logger.Error("Failed to start message clients", "error", err)
 
@@ -91,33 +161,271 @@ func main() {
 		}
 	}()
 
-	clusterManager := cluster.NewManager(ctx, logger, clusterProvider)
+	// Clusters registered through the API keep their credentials encrypted in the store
+	credentialCipher, err := uploaded.NewCipher(appConfig.Registration)
+	if err != nil {
+		logger.Error("Failed to configure cluster registration", "error", err)
+		return
+	}
+
+	uploadedProvider := uploaded.NewUploadedProvider(store, credentialCipher, logger)
+	if err := clusterProvider.Add(uploaded.ProviderName, uploadedProvider); err != nil {
+		logger.Error("Failed to add provider", "name", uploaded.ProviderName, "error", err)
+		return
+	}
 
-	// Create authorizer using your cluster manager
-	k8sAuthorizer := auth.NewK8sAuthorizer(clusterManager, logger)
-	config.SetupSubscriptions(ctx, messagingClient, store, clusterManager, logger)
+	clusterManager := cluster.NewManager(ctx, logger, clusterProvider, appConfig.RateLimits)
+
+	// Create the authorizer chain: static policies from config first, then Kubernetes RBAC
+	k8sAuthorizer := auth.NewK8sAuthorizer(clusterManager, appConfig.Authorization.Cache, logger)
+	policyAuthorizer, err := auth.NewPolicyAuthorizer(appConfig.Authorization.Policies, logger)
+	if err != nil {
+		logger.Error("Failed to configure authorization policies", "error", err)
+		return
+	}
+
+	authorizer, err := auth.NewChainAuthorizer(
+		map[string]auth.Authorizer{
+			"static": policyAuthorizer,
+			"rbac":   k8sAuthorizer,
+		},
+		appConfig.Authorization,
+		[]string{"static", "rbac"},
+		logger,
+	)
+	if err != nil {
+		logger.Error("Failed to configure authorizer chain", "error", err)
+		return
+	}
+
+	// Recent list responses are served from memory until an event changes them
+	listCache := listcache.New(appConfig.ListCache)
+
+	// Agent events are handled by a worker pool so store writes don't hold up the agents
+	eventQueue := eventqueue.New(appConfig.Events, messagingClient, logger)
+	eventQueue.Start(ctx)
+
+	config.SetupSubscriptions(ctx, eventQueue, store, listCache, clusterManager, appConfig.Warm, k8sAuthorizer, logger)
+
+	// Post resource lifecycle events to outbound webhooks
+	webhookDispatcher := webhooks.NewDispatcher(store, appConfig.Webhooks, logger)
+	webhookDispatcher.Subscribe(eventQueue)
+	webhookDispatcher.Start(ctx)
+
+	// Periodically rediscover clusters so additions and removals show up without a restart
+	config.StartDiscovery(ctx, appConfig.Discovery, clusterProvider, messagingClient, store, clusterManager, logger)
+
+	// Probe connected clusters and reconnect the ones that fail
+	config.StartHealthMonitor(ctx, appConfig.Health, messagingClient, store, clusterManager, logger)
+
+	// Deliver detected problems to the configured notification sinks
+	alertDispatcher := alerting.NewDispatcher(store, appConfig.Alerting, logger)
+	alertDispatcher.Start(ctx)
+
+	// Continuously check workloads for common problems
+	problemsEngine := config.StartProblemDetection(ctx, appConfig.Problems, alertDispatcher, messagingClient, store, clusterManager, logger)
+
+	// Follow fleet changes for providers that support watching
+	if err := config.WatchProviderClusters(ctx, clusterProvider, messagingClient, store, clusterManager, logger); err != nil {
+		logger.Error("Failed to watch provider clusters", "error", err)
+	}
+
+	// Start the audit log writer
+	auditor := audit.NewAuditor(store, logger)
+	auditor.Start(ctx)
 
 	// Initialize services
 	clusterService := services.NewClusterService(clusterManager, store, logger)
 
 	// Create a multi-cluster namespace provider (no informers)
-	namespaceProvider := namespaces.NewNamespaceProvider(clusterManager)
-	namespaceService := services.NewNamespaceService(namespaceProvider, store, logger)
+	namespaceProvider := namespaces.NewNamespaceProvider(clusterManager, appConfig.Namespaces)
+	namespaceService := services.NewNamespaceService(namespaceProvider, store, listCache, authorizer, logger)
+
+	// Live streams for clusters only their agent can reach are proxied over the messaging link
+	tunnelClient := tunnel.NewClient(messagingClient, appConfig.Tunnel, clusterManager, logger)
 
-	podProvider := pods.NewPodProvider(clusterManager)
-	podService := services.NewPodService(podProvider, store, logger)
+	// Pod informers run per namespace while requests use them, and informer
+	// caches of the least recently used clusters are dropped when over budget
+	namespaceInformers := cluster.NewNamespaceInformers(clusterManager, appConfig.Informers, logger)
+	namespaceInformers.Start(ctx)
+	cluster.NewCacheBudget(clusterManager, namespaceInformers, appConfig.CacheBudget, logger).Start(ctx)
+	podProvider := pods.NewPodProvider(clusterManager, namespaceInformers, tunnelClient)
+	podService := services.NewPodService(podProvider, store, listCache, appConfig.Recording, logger)
+	logArchiveService := services.NewLogArchiveService(podProvider, objects, logger)
+
+	// Config maps and the workloads using them, for rollout awareness after edits
+	topologyProvider := topology.NewTopologyProvider(clusterManager, store)
 
 	configMapProvider := configmaps.NewConfigMapProvider(clusterManager)
-	configMapService := services.NewConfigMapService(configMapProvider, store, logger)
+	configMapService := services.NewConfigMapService(configMapProvider, store, listCache, topologyProvider, logger)
+
+	auditService := services.NewAuditService(store, logger)
+	authzService := services.NewAuthzService(authorizer, k8sAuthorizer, logger)
+	listCacheService := services.NewListCacheService(listCache, logger)
+	eventQueueService := services.NewEventQueueService(eventQueue, logger)
+
+	metricsProvider := metrics.NewMetricsProvider(clusterManager)
+	metricsService := services.NewMetricsService(metricsProvider, store, logger)
 
-	app := fiber.New()
+	prometheusService := services.NewPrometheusService(prometheus.NewClient(appConfig.Prometheus), logger)
+
+	costProvider := cost.NewCostProvider(clusterManager, appConfig.Cost)
+	costService := services.NewCostService(costProvider, logger)
+
+	rightsizingProvider := rightsizing.NewRightsizingProvider(clusterManager, metricsProvider, store, appConfig.Rightsizing)
+	rightsizingService := services.NewRightsizingService(rightsizingProvider, logger)
+
+	capacityProvider := capacity.NewCapacityProvider(clusterManager)
+	capacityService := services.NewCapacityService(capacityProvider, logger)
+
+	problemsService := services.NewProblemsService(problemsEngine, logger)
+	alertingService := services.NewAlertingService(alertDispatcher, store, logger)
+
+	// Scheduled reports are delivered through the alert sinks
+	reportGenerator := reports.NewGenerator(clusterManager, problemsEngine, costProvider, capacityProvider)
+	reportScheduler := reports.NewScheduler(reportGenerator, alertDispatcher, store, appConfig.Reports, logger)
+	reportScheduler.Start(ctx)
+	reportService := services.NewReportService(reportScheduler, store, logger)
+	webhookService := services.NewWebhookService(webhookDispatcher, store, logger)
+
+	timelineService := services.NewTimelineService(timeline.NewTimelineProvider(clusterManager), logger)
+
+	diffService := services.NewDiffService(diff.NewDiffProvider(clusterManager, store), logger)
+
+	reconcileService := services.NewReconcileService(reconcile.NewReconcileProvider(clusterManager, store), listCache, logger)
+
+	graphService := services.NewGraphService(graph.NewGraphProvider(clusterManager, store), logger)
+
+	topologyService := services.NewTopologyService(topologyProvider, logger)
+
+	fleetService := services.NewFleetService(store, authorizer, logger)
+
+	searchService := services.NewSearchService(store, authorizer, logger)
+
+	driftService := services.NewDriftService(drift.NewDriftProvider(clusterManager), logger)
+
+	snapshotService := services.NewSnapshotService(backup.NewBackupProvider(clusterManager, store, objects), store, authorizer, logger)
+
+	// Registry lookups reach out to image registries and are opt-in
+	var registryClient *images.RegistryClient
+	if appConfig.Images.Registry.Enabled {
+		registryClient = images.NewRegistryClient(appConfig.Images.Registry)
+	}
+	imageService := services.NewImageService(images.NewImageProvider(store, registryClient), logger)
+
+	scanner, err := vulnerabilities.NewScanner(appConfig.Vulnerabilities)
+	if err != nil {
+		logger.Error("Failed to configure vulnerability scanner", "error", err)
+		return
+	}
+	scanScheduler := vulnerabilities.NewScheduler(scanner, clusterManager, store, appConfig.Vulnerabilities, logger)
+	scanScheduler.Start(ctx)
+	vulnerabilityService := services.NewVulnerabilityService(scanScheduler, logger)
+
+	complianceService := services.NewComplianceService(compliance.NewComplianceProvider(store, appConfig.Compliance), logger)
+
+	certificateService := services.NewCertificateService(certificates.NewCertificateProvider(clusterManager), clusterManager, authorizer, logger)
+
+	pdbService := services.NewPDBService(pdbs.NewPDBProvider(clusterManager), store, logger)
+
+	storageService := services.NewStorageService(storage.NewStorageProvider(clusterManager), logger)
+
+	registrationService := services.NewRegistrationService(uploadedProvider, clusterProvider, clusterManager, store, logger)
+
+	// Send commands to agents over the messaging link and wait for their results
+	commandDispatcher := commands.NewDispatcher(messagingClient, logger)
+	commandDispatcher.Start()
+	agentService := services.NewAgentService(commandDispatcher, store, agentTokens, featureFlags, logger)
+
+	supportBundleService := services.NewSupportBundleService(clusterManager, tunnelClient, commandDispatcher, objects, logger)
+
+	bulkService := services.NewBulkService(bulk.NewBulkProvider(clusterManager, appConfig.Bulk), authorizer, logger)
+	deploymentService := services.NewDeploymentService(deployments.NewDeploymentProvider(clusterManager), logger)
+	cronJobService := services.NewCronJobService(cronjobs.NewCronJobProvider(clusterManager), logger)
+
+	describeService := services.NewDescribeService(clusterManager, logger)
+
+	noteService := services.NewNoteService(store, authorizer, logger)
+
+	fileProvider := files.NewFileProvider(clusterManager, tunnelClient, appConfig.Files)
+	fileService := services.NewFileService(fileProvider, logger)
+
+	nodeService := services.NewNodeService(nodes.NewNodeProvider(clusterManager, store), logger)
+
+	recordingService := services.NewRecordingService(store, logger)
+
+	preferenceService := services.NewPreferenceService(store, logger)
+
+	loggingService := services.NewLoggingService(logLevel, logger)
+
+	watchService := services.NewWatchService(store, logger)
+
+	liveService := services.NewLiveService(live.NewLiveProvider(clusterManager), logger)
+
+	apiResourceService := services.NewAPIResourceService(apiresources.NewAPIResourceProvider(clusterManager, apiresources.DefaultCacheTTL), featureFlags, logger)
+
+	// Optionally keep short-term usage history for graphs
+	metrics.NewSampler(metricsProvider, clusterManager, store, appConfig.Metrics.Sampling, logger).Start(ctx)
+
+	// Uploads are read whole, so the body limit must allow the largest one
+	app := fiber.New(fiber.Config{
+		BodyLimit: max(fiber.DefaultBodyLimit, int(fileProvider.MaxUploadBytes())+64*1024),
+	})
 	router.SetupRoutes(
 		app,
 		clusterService,
 		namespaceService,
 		podService,
+		logArchiveService,
 		configMapService,
-		k8sAuthorizer,
+		auditService,
+		authzService,
+		listCacheService,
+		eventQueueService,
+		metricsService,
+		prometheusService,
+		costService,
+		rightsizingService,
+		capacityService,
+		problemsService,
+		alertingService,
+		reportService,
+		webhookService,
+		timelineService,
+		diffService,
+		reconcileService,
+		graphService,
+		topologyService,
+		fleetService,
+		searchService,
+		driftService,
+		snapshotService,
+		supportBundleService,
+		bulkService,
+		deploymentService,
+		cronJobService,
+		imageService,
+		vulnerabilityService,
+		complianceService,
+		certificateService,
+		pdbService,
+		storageService,
+		registrationService,
+		agentService,
+		describeService,
+		noteService,
+		fileService,
+		apiResourceService,
+		nodeService,
+		recordingService,
+		featureService,
+		preferenceService,
+		loggingService,
+		watchService,
+		liveService,
+		featureFlags,
+		auditor,
+		authorizer,
 		logger,
 	)
 
@@ -126,22 +434,3 @@ func main() {
 		logger.Error("Failed to start server", "error", err)
 	}
 }
-
-func loadProviderPlugin(path string, config map[string]string, logger *slog.Logger) (providers.Provider, error) {
-	p, err := plugin.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open plugin: %w", err)
-	}
-
-	symbol, err := p.Lookup("New")
-	if err != nil {
-		return nil, fmt.Errorf("failed to find 'New' function in plugin: %w", err)
-	}
-
-	newFunc, ok := symbol.(func(map[string]string, *slog.Logger) providers.Provider)
-	if !ok {
-		return nil, fmt.Errorf("invalid 'New' function signature in plugin")
-	}
-
-	return newFunc(config, logger), nil
-}
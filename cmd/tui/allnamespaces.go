@@ -0,0 +1,96 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// allNamespaces is the namespace the store lists across all namespaces with
+const allNamespaces = "all"
+
+// podColumns returns the pod table columns, with a namespace column when
+// listing pods across all namespaces
+func podColumns(acrossNamespaces bool) []table.Column {
+	if acrossNamespaces {
+		return []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Namespace", Width: 20},
+			{Title: "Ready", Width: 10},
+			{Title: "Status", Width: 10},
+			{Title: "Restarts", Width: 10},
+			{Title: "Problems", Width: 20},
+			{Title: "Age", Width: 10},
+		}
+	}
+
+	return []table.Column{
+		{Title: "Name", Width: 30},
+		{Title: "Ready", Width: 10},
+		{Title: "Status", Width: 10},
+		{Title: "Restarts", Width: 10},
+		{Title: "Problems", Width: 20},
+		{Title: "Age", Width: 10},
+	}
+}
+
+// withNamespace inserts the namespace column after the name
+func withNamespace(row table.Row, namespace string) table.Row {
+	result := make(table.Row, 0, len(row)+1)
+	result = append(result, row[0], namespace)
+	return append(result, row[1:]...)
+}
+
+// podsNamespace returns the namespace the pod table lists
+func (m *Model) podsNamespace() string {
+	if m.allNamespaces {
+		return allNamespaces
+	}
+	return m.selectedNamespace
+}
+
+// selectedPodRow returns the name and namespace of the highlighted pod
+func (m *Model) selectedPodRow() (string, string, bool) {
+	if len(m.podTable.Rows()) == 0 {
+		return "", "", false
+	}
+
+	row := m.podTable.SelectedRow()
+	if m.allNamespaces {
+		return row[0], row[1], true
+	}
+	return row[0], m.selectedNamespace, true
+}
+
+// selectPod makes the highlighted pod the selected one
+func (m *Model) selectPod() bool {
+	name, namespace, ok := m.selectedPodRow()
+	if !ok {
+		return false
+	}
+
+	m.selectedPod = name
+	m.selectedPodNamespace = namespace
+	return true
+}
+
+// setAllNamespaces switches the pod table between one and all namespaces
+func (m *Model) setAllNamespaces(enabled bool) {
+	if m.allNamespaces == enabled {
+		return
+	}
+
+	m.allNamespaces = enabled
+	m.podTable.SetRows(nil)
+	m.podTable.SetColumns(podColumns(enabled))
+}
+
+// showAllNamespaces lists the pods of every namespace in the selected cluster
+func (m *Model) showAllNamespaces() tea.Cmd {
+	m.setAllNamespaces(true)
+	m.selectedResource = "pods"
+	m.selectedPod = ""
+	m.currentView = PodView
+	m.loading = true
+	m.statusMessage = "Loading pods in all namespaces..."
+	return loadPods(m.dbClient, m.selectedCluster, allNamespaces)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+// errAPIMode is returned for features that need a direct connection to the cluster
+var errAPIMode = errors.New("not available through the REST API, run without --api-url for direct cluster access")
+
+// resourceStore is where the TUI reads clusters and stored resources from:
+// MongoDB directly, or the REST API in API mode
+type resourceStore interface {
+	ListClusters(ctx context.Context, results *[]cluster.ClusterInfo) error
+	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
+	Get(ctx context.Context, clusterID, namespace, kind, name string, result interface{}) error
+	Watch(ctx context.Context, clusterID, namespace, kind string) (<-chan store.Change, error)
+	Ping(ctx context.Context) error
+}
+
+// clusterConnection returns the direct connection to a cluster, which API mode does not have
+func clusterConnection(clientManager *cluster.ClientManager, clusterID string) (*cluster.Connection, error) {
+	if clientManager == nil {
+		return nil, errAPIMode
+	}
+
+	conn, exists := clientManager.GetClient(clusterID)
+	if !exists {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	return conn, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clipboardCommands are the clipboard tools tried in order, with their arguments
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// copiedMsg reports text copied to the clipboard, or to a file without one
+type copiedMsg struct {
+	what string
+	path string // File the text was written to instead, if any
+}
+
+// copyToClipboard copies text with the first available clipboard tool, or
+// writes it to a temp file when there is none. It returns the file's path in
+// that case.
+func copyToClipboard(text, pattern string) (string, error) {
+	for _, command := range clipboardCommands {
+		if command[0] == "wl-copy" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			continue
+		}
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err == nil {
+			return "", nil
+		}
+	}
+
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("no clipboard available and failed to create a file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if _, err := file.WriteString(text); err != nil {
+		return "", fmt.Errorf("no clipboard available and failed to write %s: %w", file.Name(), err)
+	}
+	return file.Name(), nil
+}
+
+// copyText copies text in the background
+func copyText(what, text, pattern string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := copyToClipboard(text, pattern)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return copiedMsg{what: what, path: path}
+	}
+}
+
+// copyObjectYAML fetches an object and copies its YAML
+func copyObjectYAML(clientManager *cluster.ClientManager, clusterID, namespace, kind, name string) tea.Cmd {
+	return func() tea.Msg {
+		resource, err := dynamicResource(clientManager, clusterID, kind)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		obj, err := resource.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errorMsg{err: describeActionError("get", kind, name, err)}
+		}
+
+		content, err := toYAML(obj, true)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		return copyText(kind+" YAML", content, "kube-dashboard-*.yaml")()
+	}
+}
+
+// highlighted returns the object the current view is on: the highlighted
+// table row, or the object being shown. Clusters and namespaces have no
+// namespace.
+func (m *Model) highlighted() (kind, namespace, name string, ok bool) {
+	switch m.currentView {
+	case ClusterView:
+		if len(m.clusterTable.Rows()) == 0 {
+			return "", "", "", false
+		}
+		return "Cluster", "", m.clusterTable.SelectedRow()[0], true
+	case NamespaceView:
+		if len(m.namespaceTable.Rows()) == 0 {
+			return "", "", "", false
+		}
+		return "Namespace", "", m.namespaceTable.SelectedRow()[0], true
+	case PodView:
+		podName, podNamespace, ok := m.selectedPodRow()
+		return "Pod", podNamespace, podName, ok
+	case ConfigMapView:
+		if len(m.configMapTable.Rows()) == 0 {
+			return "", "", "", false
+		}
+		return "ConfigMap", m.selectedNamespace, m.configMapTable.SelectedRow()[0], true
+	case ResourceView:
+		if len(m.resourceTable.Rows()) == 0 {
+			return "", "", "", false
+		}
+		return m.resourceType.Kind, m.selectedNamespace, m.resourceTable.SelectedRow()[0], true
+	case DetailView:
+		switch {
+		case m.selectedPod != "":
+			return "Pod", m.selectedPodNamespace, m.selectedPod, true
+		case m.selectedConfigMap != "":
+			return "ConfigMap", m.selectedNamespace, m.selectedConfigMap, true
+		case m.selectedObject != "":
+			return m.resourceType.Kind, m.selectedNamespace, m.selectedObject, true
+		}
+	case LogsView:
+		return "Pod", m.selectedPodNamespace, m.selectedPod, m.selectedPod != ""
+	case YAMLView:
+		if m.yamlObject != nil {
+			return m.yamlObject.GetKind(), m.yamlObject.GetNamespace(), m.yamlObject.GetName(), true
+		}
+	}
+	return "", "", "", false
+}
+
+// copyName copies the highlighted object's namespace/name
+func (m *Model) copyName() tea.Cmd {
+	kind, namespace, name, ok := m.highlighted()
+	if !ok {
+		return nil
+	}
+
+	text := strings.TrimSuffix(name, flashMarker)
+	if namespace != "" {
+		text = namespace + "/" + text
+	}
+	return copyText(strings.ToLower(kind)+" name", text, "kube-dashboard-*.txt")
+}
+
+// copyYAML copies the full YAML of the highlighted object, reusing the
+// object shown in the YAML view
+func (m *Model) copyYAML() tea.Cmd {
+	if m.currentView == YAMLView && m.yamlObject != nil {
+		content, err := toYAML(m.yamlObject, true)
+		if err != nil {
+			m.errorMessage = err.Error()
+			return nil
+		}
+		return copyText(m.yamlObject.GetKind()+" YAML", content, "kube-dashboard-*.yaml")
+	}
+
+	kind, namespace, name, ok := m.highlighted()
+	if !ok || kind == "Cluster" {
+		return nil
+	}
+
+	m.statusMessage = fmt.Sprintf("Copying %s YAML...", kind)
+	return copyObjectYAML(m.clientManager, m.selectedCluster, namespace, kind, strings.TrimSuffix(name, flashMarker))
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// maxCommandHistory caps the commands remembered for up/down recall
+	maxCommandHistory = 50
+	// maxCompletions caps the candidates listed after the prompt
+	maxCompletions = 8
+)
+
+// commandNames are the command mode verbs, besides resource names
+var commandNames = []string{"ctx", "ns", "logs", "describe", "yaml", "events", "delete", "quit"}
+
+// updateCommandPrompt handles a key in command mode: tab completes, up and
+// down recall history and enter runs the command
+func (m *Model) updateCommandPrompt(msg tea.KeyMsg) tea.Cmd {
+	m.completions = ""
+
+	switch msg.Type {
+	case tea.KeyTab:
+		m.completeCommand()
+		return nil
+	case tea.KeyUp:
+		m.recallCommand(-1)
+		return nil
+	case tea.KeyDown:
+		m.recallCommand(1)
+		return nil
+	}
+
+	if submitted, _ := m.commandPrompt.update(msg); submitted {
+		m.errorMessage = ""
+		line := strings.TrimSpace(m.commandPrompt.value)
+		m.rememberCommand(line)
+		return m.runCommand(line)
+	}
+	return nil
+}
+
+// runCommand runs a command line like "ns prod", "ctx cluster-2" or "deploy"
+func (m *Model) runCommand(line string) tea.Cmd {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	verb, arg := strings.ToLower(fields[0]), ""
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+
+	switch verb {
+	case "q", "quit":
+		m.stopWatches()
+		return tea.Quit
+	case "ctx", "context", "cluster", "clusters":
+		if arg == "" {
+			m.stopWatches()
+			m.currentView = ClusterView
+			m.selectedNamespace = ""
+			return nil
+		}
+		return m.switchCluster(arg)
+	case "ns", "namespace", "namespaces":
+		if arg == "" {
+			return m.switchResource(verb)
+		}
+		return m.switchNamespace(arg)
+	case "logs", "describe":
+		if !m.commandPod(arg) {
+			return nil
+		}
+		if verb == "logs" {
+			return m.openPodLogs()
+		}
+		return m.openPodDetails()
+	case "yaml":
+		return m.viewYAML()
+	case "events":
+		return m.showEvents()
+	case "delete":
+		if m.currentView != PodView && m.currentView != NamespaceView {
+			m.errorMessage = "Nothing to delete in this view"
+			return nil
+		}
+		m.confirmDelete()
+		return nil
+	}
+
+	return m.switchResource(verb)
+}
+
+// switchCluster handles :ctx <name>, opening a cluster like enter does in the
+// cluster view
+func (m *Model) switchCluster(name string) tea.Cmd {
+	if !containsName(m.clusterTable.AllRows(), 0, name) {
+		m.errorMessage = fmt.Sprintf("Unknown cluster %q", name)
+		return nil
+	}
+
+	m.stopWatches()
+	m.setAllNamespaces(false)
+	m.selectedNamespace = ""
+	m.selectedPod = ""
+	m.selectedConfigMap = ""
+	m.selectedObject = ""
+	return m.openCluster(name)
+}
+
+// switchNamespace handles :ns <name>, showing the current resource type in
+// another namespace
+func (m *Model) switchNamespace(name string) tea.Cmd {
+	if m.selectedCluster == "" {
+		m.errorMessage = "Select a cluster first"
+		return nil
+	}
+
+	if name == allNamespaces {
+		return m.showAllNamespaces()
+	}
+
+	// The namespace list may not be loaded yet, in which case trust the name
+	if rows := m.namespaceTable.AllRows(); len(rows) > 0 && !containsName(rows, 0, name) {
+		m.errorMessage = fmt.Sprintf("Unknown namespace %q", name)
+		return nil
+	}
+
+	m.selectedNamespace = name
+	switch {
+	case m.currentView == ResourceView && m.resourceType != nil:
+		return m.switchResource(m.resourceType.Kind)
+	case m.currentView == ConfigMapView:
+		return m.switchResource("configmaps")
+	}
+	return m.switchResource("pods")
+}
+
+// commandPod selects the pod a pod command applies to: the named one in the
+// current namespace, or the highlighted one in the pod view
+func (m *Model) commandPod(name string) bool {
+	if name == "" {
+		if m.currentView != PodView || !m.selectPod() {
+			m.errorMessage = "Select a pod first"
+			return false
+		}
+		return true
+	}
+
+	if m.selectedCluster == "" || m.selectedNamespace == "" {
+		m.errorMessage = "Select a namespace first"
+		return false
+	}
+
+	m.selectedPod = name
+	m.selectedPodNamespace = m.selectedNamespace
+	return true
+}
+
+// completeCommand completes the word before the cursor to the longest common
+// prefix of its candidates, listing them after the prompt when ambiguous
+func (m *Model) completeCommand() {
+	value := m.commandPrompt.value
+	words := strings.Fields(value)
+	if len(words) == 0 || strings.HasSuffix(value, " ") {
+		words = append(words, "")
+	}
+
+	partial := words[len(words)-1]
+	var candidates []string
+	if len(words) == 1 {
+		candidates = m.commandCandidates()
+	} else if len(words) == 2 {
+		candidates = m.argumentCandidates(strings.ToLower(words[0]))
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, partial) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	completed := commonPrefix(matches)
+	if len(matches) == 1 {
+		completed += " "
+	} else {
+		shown := matches
+		if len(shown) > maxCompletions {
+			shown = append(shown[:maxCompletions:maxCompletions], "...")
+		}
+		m.completions = strings.Join(shown, "  ")
+	}
+	m.commandPrompt.value = strings.TrimSuffix(value, partial) + completed
+}
+
+// commandCandidates returns the command names and resource aliases
+func (m *Model) commandCandidates() []string {
+	candidates := append([]string{"pods", "configmaps"}, commandNames...)
+	for _, rt := range resourceTypes {
+		candidates = append(candidates, rt.Aliases...)
+	}
+	return candidates
+}
+
+// argumentCandidates returns the names a command's argument can complete to
+func (m *Model) argumentCandidates(verb string) []string {
+	switch verb {
+	case "ctx", "context", "cluster", "clusters":
+		return rowNames(m.clusterTable.AllRows(), 0)
+	case "ns", "namespace", "namespaces":
+		return append(rowNames(m.namespaceTable.AllRows(), 0), allNamespaces)
+	case "logs", "describe":
+		if m.currentView == PodView && !m.allNamespaces {
+			return rowNames(m.podTable.AllRows(), 0)
+		}
+	}
+	return nil
+}
+
+// rememberCommand adds a command to the history, skipping repeats
+func (m *Model) rememberCommand(line string) {
+	if line != "" && (len(m.commandHistory) == 0 || m.commandHistory[len(m.commandHistory)-1] != line) {
+		m.commandHistory = append(m.commandHistory, line)
+		if len(m.commandHistory) > maxCommandHistory {
+			m.commandHistory = m.commandHistory[1:]
+		}
+	}
+	m.historyIndex = len(m.commandHistory)
+}
+
+// recallCommand moves through the command history, where the position past
+// the newest entry is an empty command line
+func (m *Model) recallCommand(delta int) {
+	index := m.historyIndex + delta
+	if index < 0 || index > len(m.commandHistory) {
+		return
+	}
+
+	m.historyIndex = index
+	if index == len(m.commandHistory) {
+		m.commandPrompt.value = ""
+		return
+	}
+	m.commandPrompt.value = m.commandHistory[index]
+}
+
+// rowNames returns a column of table rows without change markers
+func rowNames(rows []table.Row, column int) []string {
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if column < len(row) {
+			names = append(names, strings.TrimSuffix(row[column], flashMarker))
+		}
+	}
+	return names
+}
+
+// containsName reports whether a column of table rows holds a name
+func containsName(rows []table.Row, column int, name string) bool {
+	for _, candidate := range rowNames(rows, column) {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+// commonPrefix returns the longest prefix shared by all strings
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix prefixes the environment variables overriding the config file
+const envPrefix = "KUBE_DASHBOARD_"
+
+// Config holds the TUI settings. Values are read from the config file, then
+// overridden by KUBE_DASHBOARD_* environment variables and finally by flags.
+type Config struct {
+	APIURL       string              `yaml:"apiURL"` // Connect through the REST API instead of MongoDB and kubeconfig
+	Token        string              `yaml:"token"`
+	TokenCommand string              `yaml:"tokenCommand"` // Prints a token, run again when the token is rejected
+	MongoURI     string              `yaml:"mongoURI"`
+	Database     string              `yaml:"database"`
+	Namespace    string              `yaml:"namespace"` // Opened when a cluster is selected, "all" for every namespace
+	Theme        string              `yaml:"theme"`     // A built-in theme or one defined in Themes
+	Themes       map[string]theme    `yaml:"themes"`    // Custom themes by name
+	Keys         map[string][]string `yaml:"keys"`      // Remapped key bindings, e.g. top: [gg]
+	LogLines     int64               `yaml:"logLines"`
+	LogFile      string              `yaml:"logFile"` // Empty disables logging
+	Watch        store.WatchConfig   `yaml:"watch"`
+}
+
+// defaultConfig returns the settings used when nothing overrides them
+func defaultConfig() Config {
+	return Config{
+		MongoURI: "mongodb://localhost:27017",
+		Database: "k8s-starship",
+		Theme:    "default",
+		LogLines: 100,
+	}
+}
+
+// defaultConfigPath returns ~/.config/kube-dashboard/tui.yaml, honoring XDG_CONFIG_HOME
+func defaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "kube-dashboard", "tui.yaml")
+}
+
+// loadConfig builds the config from the config file, environment and command line
+func loadConfig(args []string) (Config, error) {
+	flags := flag.NewFlagSet("tui", flag.ContinueOnError)
+	path := flags.String("config", "", "config file (default "+defaultConfigPath()+")")
+
+	var set Config
+	flags.StringVar(&set.APIURL, "api-url", "", "REST API URL, connects through the API instead of MongoDB and kubeconfig")
+	flags.StringVar(&set.Token, "token", "", "bearer token for the REST API")
+	flags.StringVar(&set.TokenCommand, "token-command", "", "shell command printing a bearer token for the REST API")
+	flags.StringVar(&set.MongoURI, "mongo-uri", "", "MongoDB connection URI")
+	flags.StringVar(&set.Database, "database", "", "MongoDB database name")
+	flags.StringVar(&set.Namespace, "namespace", "", `namespace opened when a cluster is selected, "all" for every namespace`)
+	flags.StringVar(&set.Theme, "theme", "", "color theme")
+	flags.Int64Var(&set.LogLines, "log-lines", 0, "number of pod log lines to tail")
+	flags.StringVar(&set.LogFile, "log-file", "", "file to write the TUI's own logs to")
+
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := defaultConfig()
+
+	file := *path
+	if file == "" {
+		file = defaultConfigPath()
+	}
+	if err := cfg.loadFile(file); err != nil {
+		// Only a config file named explicitly has to exist
+		if *path != "" || !errors.Is(err, fs.ErrNotExist) {
+			return Config{}, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return Config{}, err
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "api-url":
+			cfg.APIURL = set.APIURL
+		case "token":
+			cfg.Token = set.Token
+		case "token-command":
+			cfg.TokenCommand = set.TokenCommand
+		case "mongo-uri":
+			cfg.MongoURI = set.MongoURI
+		case "database":
+			cfg.Database = set.Database
+		case "namespace":
+			cfg.Namespace = set.Namespace
+		case "theme":
+			cfg.Theme = set.Theme
+		case "log-lines":
+			cfg.LogLines = set.LogLines
+		case "log-file":
+			cfg.LogFile = set.LogFile
+		}
+	})
+
+	if cfg.LogLines <= 0 {
+		return Config{}, fmt.Errorf("log lines must be positive, got %d", cfg.LogLines)
+	}
+
+	return cfg, nil
+}
+
+// loadFile overlays the settings of a YAML config file
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv overlays the settings of KUBE_DASHBOARD_* environment variables
+func (c *Config) loadEnv() error {
+	fields := map[string]*string{
+		"API_URL":       &c.APIURL,
+		"TOKEN":         &c.Token,
+		"TOKEN_COMMAND": &c.TokenCommand,
+		"MONGO_URI":     &c.MongoURI,
+		"DATABASE":      &c.Database,
+		"NAMESPACE":     &c.Namespace,
+		"THEME":         &c.Theme,
+		"LOG_FILE":      &c.LogFile,
+	}
+	for name, field := range fields {
+		if value, ok := os.LookupEnv(envPrefix + name); ok {
+			*field = value
+		}
+	}
+
+	if value, ok := os.LookupEnv(envPrefix + "LOG_LINES"); ok {
+		lines, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %sLOG_LINES %q: %w", envPrefix, value, err)
+		}
+		c.LogLines = lines
+	}
+	return nil
+}
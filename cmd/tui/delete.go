@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jbetancur/dashboard/internal/pkg/apiclient"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var confirmStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#FF5F87")).
+	Padding(1, 2)
+
+// confirmation is a modal yes/no prompt guarding a destructive action
+type confirmation struct {
+	message  string
+	action   tea.Cmd
+	progress string // Status shown while the action runs
+	inline   bool   // Show below the content instead of replacing it, e.g. to keep a diff visible
+}
+
+// View renders the confirmation dialog
+func (c confirmation) View() string {
+	return confirmStyle.Render(c.message + "\n\n" + promptStyle.Render("y") + " confirm  " + promptStyle.Render("n/esc") + " cancel")
+}
+
+// resourceDeletedMsg reports a completed deletion
+type resourceDeletedMsg struct {
+	kind string
+	name string
+}
+
+// describeActionError turns API errors into messages that explain RBAC denials
+func describeActionError(verb, kind, name string, err error) error {
+	switch {
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("not allowed to %s %s %s: check your RBAC permissions", verb, kind, name)
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%s %s no longer exists", kind, name)
+	case apierrors.IsConflict(err):
+		return fmt.Errorf("%s %s was changed by someone else, reload and try again", kind, name)
+	default:
+		return fmt.Errorf("failed to %s %s %s: %w", verb, kind, name, err)
+	}
+}
+
+// deletePod deletes a pod from the cluster, through the REST API in API mode
+func deletePod(api *apiclient.Client, clientManager *cluster.ClientManager, clusterID, namespace, podName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if api != nil {
+			if err := api.DeletePod(ctx, clusterID, namespace, podName); err != nil {
+				return errorMsg{err: describeActionError("delete", "pod", podName, err)}
+			}
+			return resourceDeletedMsg{kind: "pod", name: podName}
+		}
+
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		if err := client.Client.CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{}); err != nil {
+			return errorMsg{err: describeActionError("delete", "pod", podName, err)}
+		}
+
+		return resourceDeletedMsg{kind: "pod", name: podName}
+	}
+}
+
+// deleteNamespace deletes a namespace and everything in it from the cluster,
+// through the REST API in API mode
+func deleteNamespace(api *apiclient.Client, clientManager *cluster.ClientManager, clusterID, namespace string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if api != nil {
+			if err := api.DeleteNamespace(ctx, clusterID, namespace); err != nil {
+				return errorMsg{err: describeActionError("delete", "namespace", namespace, err)}
+			}
+			return resourceDeletedMsg{kind: "namespace", name: namespace}
+		}
+
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		if err := client.Client.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{}); err != nil {
+			return errorMsg{err: describeActionError("delete", "namespace", namespace, err)}
+		}
+
+		return resourceDeletedMsg{kind: "namespace", name: namespace}
+	}
+}
+
+// confirmDelete asks for confirmation before deleting the selected pod or namespace
+func (m *Model) confirmDelete() {
+	switch m.currentView {
+	case PodView:
+		podName, namespace, ok := m.selectedPodRow()
+		if !ok {
+			return
+		}
+
+		m.confirm = &confirmation{
+			message:  fmt.Sprintf("Delete pod %s/%s?", namespace, podName),
+			action:   deletePod(m.api, m.clientManager, m.selectedCluster, namespace, podName),
+			progress: "Deleting pod...",
+		}
+	case NamespaceView:
+		if len(m.namespaceTable.Rows()) == 0 {
+			return
+		}
+
+		namespace := m.namespaceTable.SelectedRow()[0]
+		m.confirm = &confirmation{
+			message:  fmt.Sprintf("Delete namespace %s and ALL of its resources?", namespace),
+			action:   deleteNamespace(m.api, m.clientManager, m.selectedCluster, namespace),
+			progress: "Deleting namespace...",
+		}
+	}
+}
+
+// updateConfirm handles keys while the confirmation dialog is open
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	confirm := m.confirm
+
+	switch msg.String() {
+	case "y", "Y":
+		m.confirm = nil
+		m.loading = true
+		m.statusMessage = confirm.progress
+		return m, confirm.action
+	case "n", "N", "esc", "q":
+		m.confirm = nil
+		m.statusMessage = "Cancelled"
+
+		if m.currentView == YAMLView {
+			m.resetYAMLView()
+		}
+	}
+
+	return m, nil
+}
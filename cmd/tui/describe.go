@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jbetancur/dashboard/internal/pkg/apiclient"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/describe"
+	"github.com/jbetancur/dashboard/internal/pkg/restarts"
+)
+
+// detailsLoadedMsg carries the description shown in the detail view
+type detailsLoadedMsg struct {
+	name    string
+	content string
+}
+
+// loadDetails describes an object live from the cluster with the same
+// renderer as the describe endpoint, so every kind matches kubectl describe.
+// In API mode the describe endpoint is called instead. Pods that restarted
+// are headed by the restart summary.
+func loadDetails(api *apiclient.Client, clientManager *cluster.ClientManager, clusterID, kind, namespace, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if api != nil {
+			content, err := api.Describe(ctx, clusterID, kind, namespace, name)
+			if err != nil {
+				return errorMsg{err: describeActionError("describe", kind, name, err)}
+			}
+			if kind == "Pod" {
+				if summary, err := api.RestartSummary(ctx, clusterID, namespace, name); err == nil {
+					content = withRestartSummary(summary, content)
+				}
+			}
+			return detailsLoadedMsg{name: name, content: content}
+		}
+
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		content, err := describe.Describe(ctx, client.Client, kind, namespace, name)
+		if err != nil {
+			return errorMsg{err: describeActionError("describe", kind, name, err)}
+		}
+		if kind == "Pod" {
+			if summary, err := restarts.Load(ctx, client.Client, namespace, name); err == nil {
+				content = withRestartSummary(summary, content)
+			}
+		}
+
+		return detailsLoadedMsg{name: name, content: content}
+	}
+}
+
+// withRestartSummary puts the restart summary of a pod above its description
+func withRestartSummary(summary *restarts.Summary, content string) string {
+	if summary.RestartCount == 0 {
+		return content
+	}
+	return "Restarts:\n" + summary.Text() + "\n" + content
+}
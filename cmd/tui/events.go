@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// maxEvents caps the events shown, most recent first
+const maxEvents = 200
+
+var (
+	eventWarningStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFB000")).
+				Bold(true)
+
+	eventHeaderStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("62")).
+				Bold(true)
+)
+
+// eventsTarget identifies what events are shown for: a namespace, or one
+// object in it when kind and name are set
+type eventsTarget struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+// String describes the target for the title bar
+func (t eventsTarget) String() string {
+	if t.name == "" {
+		return "namespace " + t.namespace
+	}
+	return fmt.Sprintf("%s %s/%s", t.kind, t.namespace, t.name)
+}
+
+// eventsLoadedMsg carries rendered events
+type eventsLoadedMsg struct {
+	content  string
+	count    int
+	warnings int
+}
+
+// loadEvents lists the events of a target from the cluster
+func loadEvents(clientManager *cluster.ClientManager, clusterID string, target eventsTarget) tea.Cmd {
+	return func() tea.Msg {
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		options := metav1.ListOptions{}
+		if target.name != "" {
+			options.FieldSelector = fields.AndSelectors(
+				fields.OneTermEqualSelector("involvedObject.kind", target.kind),
+				fields.OneTermEqualSelector("involvedObject.name", target.name),
+			).String()
+		}
+
+		events, err := client.Client.CoreV1().Events(target.namespace).List(ctx, options)
+		if err != nil {
+			return errorMsg{err: describeActionError("list", "events of", target.String(), err)}
+		}
+
+		items := events.Items
+		sort.Slice(items, func(i, j int) bool {
+			return lastSeen(&items[i]).After(lastSeen(&items[j]))
+		})
+		if len(items) > maxEvents {
+			items = items[:maxEvents]
+		}
+
+		content, warnings := renderEvents(items, target.name == "")
+		return eventsLoadedMsg{content: content, count: len(items), warnings: warnings}
+	}
+}
+
+// lastSeen returns the most recent time an event was observed
+func lastSeen(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// renderEvents formats events one per line, highlighting warnings. The
+// object column is only shown for namespace events.
+func renderEvents(events []corev1.Event, withObject bool) (string, int) {
+	if len(events) == 0 {
+		return "No events found. Events expire after an hour by default.", 0
+	}
+
+	var b strings.Builder
+	header := fmt.Sprintf("%-10s %-8s %-24s %-6s ", "LAST SEEN", "TYPE", "REASON", "COUNT")
+	if withObject {
+		header += fmt.Sprintf("%-40s ", "OBJECT")
+	}
+	b.WriteString(eventHeaderStyle.Render(header+"MESSAGE") + "\n")
+
+	warnings := 0
+	for i := range events {
+		event := &events[i]
+
+		line := fmt.Sprintf("%-10s %-8s %-24s %-6d ",
+			duration.HumanDuration(time.Since(lastSeen(event))), event.Type, event.Reason, max(event.Count, 1))
+		if withObject {
+			line += fmt.Sprintf("%-40s ", strings.ToLower(event.InvolvedObject.Kind)+"/"+event.InvolvedObject.Name)
+		}
+		line += strings.TrimSpace(event.Message)
+
+		if event.Type == corev1.EventTypeWarning {
+			warnings++
+			line = eventWarningStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return b.String(), warnings
+}
+
+// showEvents opens the events view for the selected pod, resource or
+// namespace of the current view
+func (m *Model) showEvents() tea.Cmd {
+	var target eventsTarget
+
+	switch m.currentView {
+	case NamespaceView:
+		if len(m.namespaceTable.Rows()) == 0 {
+			return nil
+		}
+		target = eventsTarget{namespace: m.namespaceTable.SelectedRow()[0]}
+	case PodView:
+		name, namespace, ok := m.selectedPodRow()
+		if !ok {
+			return nil
+		}
+		target = eventsTarget{namespace: namespace, kind: "Pod", name: name}
+	case ConfigMapView:
+		if len(m.configMapTable.Rows()) == 0 {
+			return nil
+		}
+		target = eventsTarget{namespace: m.selectedNamespace, kind: "ConfigMap", name: m.configMapTable.SelectedRow()[0]}
+	case ResourceView:
+		if len(m.resourceTable.Rows()) == 0 {
+			return nil
+		}
+		target = eventsTarget{namespace: m.selectedNamespace, kind: m.resourceType.Kind, name: m.resourceTable.SelectedRow()[0]}
+	case DetailView:
+		switch {
+		case m.selectedPod != "":
+			target = eventsTarget{namespace: m.selectedPodNamespace, kind: "Pod", name: m.selectedPod}
+		case m.selectedConfigMap != "":
+			target = eventsTarget{namespace: m.selectedNamespace, kind: "ConfigMap", name: m.selectedConfigMap}
+		case m.selectedObject != "":
+			target = eventsTarget{namespace: m.selectedNamespace, kind: m.resourceType.Kind, name: m.selectedObject}
+		default:
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	m.eventsTarget = target
+	m.eventsReturnView = m.currentView
+	m.currentView = EventsView
+	m.eventsView.SetContent("")
+	m.loading = true
+	m.statusMessage = "Loading events..."
+	return loadEvents(m.clientManager, m.selectedCluster, target)
+}
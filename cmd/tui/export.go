@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// exportTimeout bounds fetching a container's full logs for export
+const exportTimeout = time.Minute
+
+// logsExportedMsg reports logs saved to a file
+type logsExportedMsg struct {
+	path string
+	size int64
+}
+
+// defaultExportPath names an export after the container and the time
+func defaultExportPath(pod, container string, now time.Time) string {
+	return fmt.Sprintf("%s-%s-%s.log", pod, container, now.Format("20060102-150405"))
+}
+
+// expandHome resolves a leading ~/ to the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// createExport creates the export file. Logs may hold secrets, so only the
+// user can read it.
+func createExport(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// saveLogs writes the loaded logs to a file
+func saveLogs(path, content string) tea.Cmd {
+	return func() tea.Msg {
+		file, err := createExport(path)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		size, err := io.Copy(file, strings.NewReader(content))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to write %s: %w", path, err)}
+		}
+
+		return logsExportedMsg{path: path, size: size}
+	}
+}
+
+// saveFullLogs streams a container's complete logs from the cluster to a file
+func saveFullLogs(clientManager *cluster.ClientManager, clusterID, namespace, podName, containerName, path string) tea.Cmd {
+	return func() tea.Msg {
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+
+		stream, err := client.Client.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{Container: containerName}).Stream(ctx)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to get pod logs: %w", err)}
+		}
+		defer stream.Close()
+
+		file, err := createExport(path)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		size, err := io.Copy(file, stream)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to write %s: %w", path, err)}
+		}
+
+		return logsExportedMsg{path: path, size: size}
+	}
+}
+
+// openExportPrompt asks where to save the logs, defaulting to the loaded lines
+func (m *Model) openExportPrompt() {
+	m.exportFull = false
+	m.exportPrompt.open(m.exportLabel(), defaultExportPath(m.selectedPod, m.selectedContainer, time.Now()))
+}
+
+// exportLabel names what the export prompt saves
+func (m *Model) exportLabel() string {
+	if m.exportFull {
+		return "Save full logs to: "
+	}
+	return "Save loaded logs to: "
+}
+
+// updateExportPrompt handles a key in the export prompt: tab switches between
+// the loaded and the full logs, and enter saves them
+func (m *Model) updateExportPrompt(msg tea.KeyMsg) tea.Cmd {
+	if msg.Type == tea.KeyTab {
+		m.exportFull = !m.exportFull
+		m.exportPrompt.label = m.exportLabel()
+		return nil
+	}
+
+	if submitted, _ := m.exportPrompt.update(msg); !submitted {
+		return nil
+	}
+
+	m.errorMessage = ""
+	path := expandHome(strings.TrimSpace(m.exportPrompt.value))
+	if path == "" {
+		m.errorMessage = "No file to save the logs to"
+		return nil
+	}
+
+	if !m.exportFull {
+		m.statusMessage = "Saving logs..."
+		return saveLogs(path, m.logContent)
+	}
+
+	// The REST API caps log requests, so complete logs need the cluster
+	if m.api != nil {
+		m.errorMessage = "Full logs are " + errAPIMode.Error()
+		return nil
+	}
+
+	m.loading = true
+	m.statusMessage = "Fetching full logs..."
+	return saveFullLogs(m.clientManager, m.selectedCluster, m.selectedPodNamespace, m.selectedPod, m.selectedContainer, path)
+}
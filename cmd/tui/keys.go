@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// namedKey matches key names such as "enter", "ctrl+w" or "f5", which are
+// single keys even though they are longer than one character
+var namedKey = regexp.MustCompile(`^(up|down|left|right|enter|esc|tab|home|end|pgup|pgdown|space|backspace|delete|insert|f\d+)$|\+`)
+
+// isKeySequence reports whether a binding key is typed as a sequence of keys, like vim's gg
+func isKeySequence(k string) bool {
+	return len([]rune(k)) > 1 && !namedKey.MatchString(k)
+}
+
+// bindings returns the remappable bindings by their config name
+func (k *KeyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":             &k.Up,
+		"down":           &k.Down,
+		"top":            &k.Top,
+		"bottom":         &k.Bottom,
+		"enter":          &k.Enter,
+		"back":           &k.Back,
+		"quit":           &k.Quit,
+		"refresh":        &k.Refresh,
+		"delete":         &k.Delete,
+		"describe":       &k.Describe,
+		"logs":           &k.Logs,
+		"help":           &k.Help,
+		"switchResource": &k.SwitchResource,
+		"search":         &k.Search,
+		"nextMatch":      &k.NextMatch,
+		"prevMatch":      &k.PrevMatch,
+		"filterErrors":   &k.FilterErrors,
+		"filterWarnings": &k.FilterWarnings,
+		"yaml":           &k.YAML,
+		"edit":           &k.Edit,
+		"command":        &k.Command,
+		"sortName":       &k.SortName,
+		"sortStatus":     &k.SortStatus,
+		"sortRestarts":   &k.SortRestarts,
+		"sortAge":        &k.SortAge,
+		"allNamespaces":  &k.AllNamespaces,
+		"events":         &k.Events,
+		"split":          &k.Split,
+		"focusPane":      &k.FocusPane,
+		"scale":          &k.Scale,
+		"restart":        &k.Restart,
+		"copy":           &k.Copy,
+		"copyYAML":       &k.CopyYAML,
+		"exportLogs":     &k.ExportLogs,
+	}
+}
+
+// keyMap returns the default bindings with the config's remappings applied
+func (c Config) keyMap() (KeyMap, error) {
+	km := keys
+	bindings := km.bindings()
+
+	for name, remapped := range c.Keys {
+		binding, ok := bindings[name]
+		if !ok {
+			names := make([]string, 0, len(bindings))
+			for n := range bindings {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return KeyMap{}, fmt.Errorf("unknown key binding %q, expected one of %s", name, strings.Join(names, ", "))
+		}
+		if len(remapped) == 0 {
+			return KeyMap{}, fmt.Errorf("key binding %q has no keys", name)
+		}
+
+		binding.SetKeys(remapped...)
+		binding.SetHelp(strings.Join(remapped, "/"), binding.Help().Desc)
+	}
+
+	return km, nil
+}
+
+// sequences returns the multi-key sequences bound in a key map
+func (k KeyMap) sequences() map[string]bool {
+	sequences := make(map[string]bool)
+	for _, binding := range k.bindings() {
+		for _, bound := range binding.Keys() {
+			if isKeySequence(bound) {
+				sequences[bound] = true
+			}
+		}
+	}
+	return sequences
+}
+
+// readSequence buffers keys that start a bound sequence. It returns the key
+// to handle, which is the whole sequence once it is complete, or false while
+// a sequence is still being typed.
+func (m *Model) readSequence(msg tea.KeyMsg) (tea.KeyMsg, bool) {
+	if len(m.sequences) == 0 || msg.Type != tea.KeyRunes || msg.Alt {
+		m.pendingKeys = ""
+		return msg, true
+	}
+
+	typed := m.pendingKeys + string(msg.Runes)
+	if m.sequences[typed] {
+		m.pendingKeys = ""
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(typed)}, true
+	}
+
+	for sequence := range m.sequences {
+		if strings.HasPrefix(sequence, typed) {
+			m.pendingKeys = typed
+			return msg, false
+		}
+	}
+
+	// Not a sequence after all: drop what was buffered and handle this key alone
+	m.pendingKeys = ""
+	return msg, true
+}
+
+// bindNavigation makes the tables and viewports follow remapped movement keys
+func bindNavigation(km KeyMap, tables []*table.Model, viewports []*viewport.Model) {
+	for _, t := range tables {
+		t.KeyMap.LineUp = km.Up
+		t.KeyMap.LineDown = km.Down
+		t.KeyMap.GotoTop = km.Top
+		t.KeyMap.GotoBottom = km.Bottom
+	}
+	for _, vp := range viewports {
+		vp.KeyMap.Up = km.Up
+		vp.KeyMap.Down = km.Down
+	}
+}
+
+// activeViewport returns the viewport that scrolls with the movement keys, if any
+func (m *Model) activeViewport() *viewport.Model {
+	if m.split && m.currentView == PodView {
+		if !m.paneFocused {
+			return nil
+		}
+		if m.splitPane == LogsView {
+			return &m.logsView
+		}
+		return &m.detailView
+	}
+
+	switch m.currentView {
+	case DetailView:
+		return &m.detailView
+	case LogsView:
+		return &m.logsView
+	case YAMLView:
+		return &m.yamlView
+	case EventsView:
+		return &m.eventsView
+	default:
+		return nil
+	}
+}
+
+// jumpViewport scrolls the active viewport to its top or bottom, which
+// viewports have no keys for, reporting whether there was one
+func (m *Model) jumpViewport(msg tea.KeyMsg) bool {
+	vp := m.activeViewport()
+	if vp == nil {
+		return false
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Top):
+		vp.GotoTop()
+	case key.Matches(msg, m.keys.Bottom):
+		vp.GotoBottom()
+	default:
+		return false
+	}
+	return true
+}
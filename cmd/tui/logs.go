@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Log level filters
+const (
+	logLevelAll   = ""
+	logLevelError = "ERROR"
+	logLevelWarn  = "WARN"
+)
+
+var (
+	errorLinePattern = regexp.MustCompile(`(?i)\b(error|err|fatal|panic|critical)\b`)
+	warnLinePattern  = regexp.MustCompile(`(?i)\b(warn|warning)\b`)
+
+	logMatchStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#FFD700"))
+
+	logCurrentMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#000000")).
+				Background(lipgloss.Color("#FF8C00")).
+				Bold(true)
+)
+
+// matchesLevel reports whether a log line passes a level filter. The WARN
+// filter keeps errors too.
+func matchesLevel(line, level string) bool {
+	switch level {
+	case logLevelError:
+		return errorLinePattern.MatchString(line)
+	case logLevelWarn:
+		return errorLinePattern.MatchString(line) || warnLinePattern.MatchString(line)
+	default:
+		return true
+	}
+}
+
+// filterLogs returns the log lines that pass the level filter, and the
+// indexes of the lines containing the query, case-insensitively
+func filterLogs(content, query, level string) ([]string, []int) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	filtered := make([]string, 0, len(lines))
+	matches := make([]int, 0)
+	lowerQuery := strings.ToLower(query)
+
+	for _, line := range lines {
+		if !matchesLevel(line, level) {
+			continue
+		}
+
+		if query != "" && strings.Contains(strings.ToLower(line), lowerQuery) {
+			matches = append(matches, len(filtered))
+		}
+		filtered = append(filtered, line)
+	}
+
+	return filtered, matches
+}
+
+// highlightLine marks every occurrence of the query in a line
+func highlightLine(line, query string, style lipgloss.Style) string {
+	if query == "" {
+		return line
+	}
+
+	lowerLine := strings.ToLower(line)
+	lowerQuery := strings.ToLower(query)
+
+	var builder strings.Builder
+	for {
+		index := strings.Index(lowerLine, lowerQuery)
+		if index < 0 {
+			builder.WriteString(line)
+			return builder.String()
+		}
+
+		builder.WriteString(line[:index])
+		builder.WriteString(style.Render(line[index : index+len(query)]))
+		line = line[index+len(query):]
+		lowerLine = lowerLine[index+len(query):]
+	}
+}
+
+// refreshLogs renders the loaded logs into the viewport with the current
+// level filter and search highlighting, scrolling to the current match
+func (m *Model) refreshLogs() {
+	lines, matches := filterLogs(m.logContent, m.logQuery, m.logLevel)
+	m.logMatches = matches
+
+	if m.logMatch >= len(matches) {
+		m.logMatch = 0
+	}
+
+	current := -1
+	if len(matches) > 0 {
+		current = matches[m.logMatch]
+	}
+
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		style := logMatchStyle
+		if i == current {
+			style = logCurrentMatchStyle
+		}
+		rendered[i] = highlightLine(line, m.logQuery, style)
+	}
+
+	m.logsView.SetContent(strings.Join(rendered, "\n"))
+
+	if current >= 0 {
+		m.logsView.SetYOffset(current)
+	}
+}
+
+// moveLogMatch moves to the next (or previous) search match, wrapping around
+func (m *Model) moveLogMatch(delta int) {
+	if len(m.logMatches) == 0 {
+		return
+	}
+
+	m.logMatch = (m.logMatch + delta + len(m.logMatches)) % len(m.logMatches)
+	m.refreshLogs()
+}
+
+// logStatus describes the active search and filter
+func (m Model) logStatus() string {
+	parts := make([]string, 0, 2)
+
+	if m.logLevel != logLevelAll {
+		parts = append(parts, "filter: "+m.logLevel)
+	}
+
+	if m.logQuery != "" {
+		if len(m.logMatches) == 0 {
+			parts = append(parts, fmt.Sprintf("no matches for %q", m.logQuery))
+		} else {
+			parts = append(parts, fmt.Sprintf("match %d/%d for %q", m.logMatch+1, len(m.logMatches), m.logQuery))
+		}
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+// toggleLogLevel switches a level filter on, or off when it is already active
+func (m *Model) toggleLogLevel(level string) {
+	if m.logLevel == level {
+		m.logLevel = logLevelAll
+	} else {
+		m.logLevel = level
+	}
+
+	m.logMatch = 0
+	m.refreshLogs()
+}
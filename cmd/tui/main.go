@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -17,10 +19,12 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jbetancur/dashboard/internal/pkg/apiclient"
 	"github.com/jbetancur/dashboard/internal/pkg/cluster"
 	"github.com/jbetancur/dashboard/internal/pkg/store"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/duration"
 )
 
@@ -57,13 +61,18 @@ const (
 	PodView
 	DetailView
 	ConfigMapView
-	LogsView // View for pod logs
+	LogsView     // View for pod logs
+	YAMLView     // View for the live YAML of a resource
+	ResourceView // View for other resource types, chosen with :<resource>
+	EventsView   // View for the events of a resource or namespace
 )
 
 // KeyMap defines the keybindings for the application
 type KeyMap struct {
 	Up             key.Binding
 	Down           key.Binding
+	Top            key.Binding
+	Bottom         key.Binding
 	Enter          key.Binding
 	Back           key.Binding
 	Quit           key.Binding
@@ -72,8 +81,28 @@ type KeyMap struct {
 	Describe       key.Binding
 	Logs           key.Binding
 	Help           key.Binding
-	ClusterNS      key.Binding
 	SwitchResource key.Binding
+	Search         key.Binding
+	NextMatch      key.Binding
+	PrevMatch      key.Binding
+	FilterErrors   key.Binding
+	FilterWarnings key.Binding
+	YAML           key.Binding
+	Edit           key.Binding
+	Command        key.Binding
+	SortName       key.Binding
+	SortStatus     key.Binding
+	SortRestarts   key.Binding
+	SortAge        key.Binding
+	AllNamespaces  key.Binding
+	Events         key.Binding
+	Split          key.Binding
+	FocusPane      key.Binding
+	Scale          key.Binding
+	Restart        key.Binding
+	Copy           key.Binding
+	CopyYAML       key.Binding
+	ExportLogs     key.Binding
 }
 
 var keys = KeyMap{
@@ -85,6 +114,14 @@ var keys = KeyMap{
 		key.WithKeys("down", "j"),
 		key.WithHelp("↓/j", "move down"),
 	),
+	Top: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
 	Enter: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "select"),
@@ -117,14 +154,94 @@ var keys = KeyMap{
 		key.WithKeys("?"),
 		key.WithHelp("?", "help"),
 	),
-	ClusterNS: key.NewBinding(
-		key.WithKeys("c"),
-		key.WithHelp("c", "change namespace"),
-	),
 	SwitchResource: key.NewBinding(
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "switch resource"),
 	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter table/search logs"),
+	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n/N", "next/prev match"),
+	),
+	PrevMatch: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	FilterErrors: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "only errors"),
+	),
+	FilterWarnings: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "only warnings"),
+	),
+	YAML: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "view yaml"),
+	),
+	Edit: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit yaml"),
+	),
+	Command: key.NewBinding(
+		key.WithKeys(":"),
+		key.WithHelp(":", "command (:deploy, :ns prod, :ctx c2, :logs; tab completes)"),
+	),
+	SortName: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "sort by name"),
+	),
+	SortStatus: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "sort by status"),
+	),
+	SortRestarts: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "sort by restarts"),
+	),
+	SortAge: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "sort by age"),
+	),
+	AllNamespaces: key.NewBinding(
+		key.WithKeys("0"),
+		key.WithHelp("0", "pods in all namespaces"),
+	),
+	Events: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "events"),
+	),
+	Split: key.NewBinding(
+		key.WithKeys("|"),
+		key.WithHelp("|", "split pods and details/logs"),
+	),
+	FocusPane: key.NewBinding(
+		key.WithKeys("ctrl+w"),
+		key.WithHelp("ctrl+w", "switch pane"),
+	),
+	Scale: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "scale deployment"),
+	),
+	Restart: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "restart deployment"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "copy name"),
+	),
+	CopyYAML: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy yaml"),
+	),
+	ExportLogs: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "save logs to a file"),
+	),
 }
 
 // Update ShortHelp and FullHelp to include the new binding
@@ -135,45 +252,91 @@ func (k KeyMap) ShortHelp() []key.Binding {
 // FullHelp returns keybindings for the expanded help view.
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Enter},
+		{k.Up, k.Down, k.Top, k.Bottom, k.Enter},
 		{k.Back, k.Refresh, k.Quit},
-		{k.Delete, k.Describe, k.Logs},
-		{k.SwitchResource, k.ClusterNS, k.Help},
+		{k.Delete, k.Describe, k.Logs, k.Events},
+		{k.SwitchResource, k.AllNamespaces, k.Help},
+		{k.Search, k.NextMatch, k.FilterErrors, k.FilterWarnings, k.ExportLogs},
+		{k.YAML, k.Edit, k.Command, k.Copy, k.CopyYAML},
+		{k.SortName, k.SortStatus, k.SortRestarts, k.SortAge},
+		{k.Split, k.FocusPane, k.Scale, k.Restart},
 	}
 }
 
 // Model represents the application state
 type Model struct {
-	currentView       ViewType
-	clusterTable      table.Model
-	namespaceTable    table.Model
-	podTable          table.Model
-	detailView        viewport.Model
-	logsView          viewport.Model
-	help              help.Model
-	keys              KeyMap
-	width             int
-	height            int
-	selectedCluster   string
-	selectedNamespace string
-	selectedPod       string
-	selectedContainer string
-	statusMessage     string
-	errorMessage      string
-	clientManager     *cluster.ClientManager
-	dbClient          store.Repository // Database client
-	showHelp          bool
-	loading           bool
-	logLines          int64
-	configMapTable    table.Model
-	selectedResource  string // "pods" or "configmaps"
-	selectedConfigMap string
+	currentView          ViewType
+	clusterTable         listTable
+	namespaceTable       listTable
+	podTable             listTable
+	detailView           viewport.Model
+	logsView             viewport.Model
+	yamlView             viewport.Model
+	help                 help.Model
+	keys                 KeyMap
+	width                int
+	height               int
+	selectedCluster      string
+	selectedNamespace    string
+	selectedPod          string
+	selectedPodNamespace string // Namespace of the selected pod, which differs per pod across all namespaces
+	allNamespaces        bool   // Whether the pod table lists pods across all namespaces
+	selectedContainer    string
+	statusMessage        string
+	errorMessage         string
+	clientManager        *cluster.ClientManager
+	dbClient             resourceStore     // Database client, or the REST API in API mode
+	api                  *apiclient.Client // Set in API mode, where clientManager is nil
+	showHelp             bool
+	loading              bool
+	logLines             int64
+	configMapTable       listTable
+	selectedResource     string // "pods" or "configmaps"
+	selectedConfigMap    string
+	logContent           string // Raw logs before filtering and highlighting
+	logSearch            prompt
+	logQuery             string
+	logMatches           []int // Line offsets of search matches in the filtered logs
+	logMatch             int
+	logLevel             string
+	confirm              *confirmation // Pending confirmation dialog, if any
+	yamlObject           *unstructured.Unstructured
+	yamlReturnView       ViewType          // View to return to when leaving the YAML view
+	watches              map[string]*watch // Live table watches by kind
+	watchSeq             int
+	resourceTable        listTable
+	resourceType         *resourceType // Type listed in the resource view
+	selectedObject       string        // Resource selected in the resource view
+	commandPrompt        prompt
+	commandHistory       []string // Commands run from the command bar, oldest first
+	historyIndex         int      // Position in the history while recalling commands
+	completions          string   // Candidates of an ambiguous completion
+	scalePrompt          prompt
+	scaleTarget          string // Deployment being scaled
+	exportPrompt         prompt
+	exportFull           bool          // Whether the export fetches complete logs rather than the loaded ones
+	healthErr            error         // Result of the last data source ping
+	healthChecked        bool          // Whether the data source has been pinged yet
+	lastRefresh          time.Time     // When the visible data was last loaded or updated
+	streamLag            time.Duration // Delay of the last change seen by a watch
+	filterPrompt         prompt
+	eventsView           viewport.Model
+	eventsTarget         eventsTarget
+	eventsReturnView     ViewType        // View to return to when leaving the events view
+	split                bool            // Whether the pod view shows a pane next to the table
+	splitPane            ViewType        // DetailView or LogsView, shown in the split pane
+	paneFocused          bool            // Whether keys go to the split pane rather than the table
+	splitSeq             int             // Invalidates log tailing when the pane changes
+	sequences            map[string]bool // Multi-key sequences bound in keys, like gg
+	pendingKeys          string          // Start of a key sequence typed so far
+	config               Config
 }
 
 // Message types
 type clientsLoadedMsg struct {
 	clientManager *cluster.ClientManager
-	dbClient      store.Repository
+	dbClient      resourceStore
+	api           *apiclient.Client
 }
 
 type clustersLoadedMsg struct {
@@ -192,11 +355,8 @@ type configMapsLoadedMsg struct {
 	rows []table.Row
 }
 
-type podDetailsLoadedMsg struct {
-	content string
-}
-
 type podLogsLoadedMsg struct {
+	pod     string
 	content string
 }
 
@@ -204,12 +364,13 @@ type errorMsg struct {
 	err error
 }
 
-func initialModel() Model {
+func initialModel(cfg Config, keyMap KeyMap) Model {
 	// Initialize tables with empty data
 	clusterTable := table.New(
 		table.WithColumns([]table.Column{
 			{Title: "Name", Width: 20},
 			{Title: "API URL", Width: 40},
+			{Title: "Status", Width: 10},
 		}),
 		table.WithFocused(true),
 		table.WithHeight(10),
@@ -232,13 +393,7 @@ func initialModel() Model {
 	})
 
 	podTable := table.New(
-		table.WithColumns([]table.Column{
-			{Title: "Name", Width: 30},
-			{Title: "Ready", Width: 10},
-			{Title: "Status", Width: 10},
-			{Title: "Restarts", Width: 10},
-			{Title: "Age", Width: 10},
-		}),
+		table.WithColumns(podColumns(false)),
 		table.WithFocused(true),
 		table.WithHeight(10),
 	)
@@ -259,38 +414,61 @@ func initialModel() Model {
 		Selected: selectedRowStyle,
 	})
 
+	resourceTable := table.New(
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+	resourceTable.SetStyles(table.Styles{
+		Selected: selectedRowStyle,
+	})
+
 	detailView := viewport.New(80, 20)
-	detailView.Style = lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62"))
+	detailView.Style = viewportStyle()
+
+	yamlView := viewport.New(80, 20)
+	yamlView.Style = viewportStyle()
+
+	eventsView := viewport.New(80, 20)
+	eventsView.Style = viewportStyle()
 
 	logsView := viewport.New(80, 20)
-	logsView.Style = lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62"))
+	logsView.Style = viewportStyle()
+
+	bindNavigation(keyMap,
+		[]*table.Model{&clusterTable, &namespaceTable, &podTable, &configMapTable, &resourceTable},
+		[]*viewport.Model{&detailView, &yamlView, &eventsView, &logsView})
+
+	pods := newListTable(podTable)
+	pods.rowStyle = podRowStyle
 
 	return Model{
 		currentView:       ClusterView,
-		clusterTable:      clusterTable,
-		configMapTable:    configMapTable,
+		clusterTable:      newListTable(clusterTable),
+		configMapTable:    newListTable(configMapTable),
+		resourceTable:     newListTable(resourceTable),
 		selectedResource:  "pods", // Default to pods view
-		namespaceTable:    namespaceTable,
-		podTable:          podTable,
+		namespaceTable:    newListTable(namespaceTable),
+		podTable:          pods,
 		detailView:        detailView,
 		logsView:          logsView,
+		watches:           make(map[string]*watch),
+		yamlView:          yamlView,
+		eventsView:        eventsView,
 		help:              help.New(),
-		keys:              keys,
+		keys:              keyMap,
+		sequences:         keyMap.sequences(),
 		statusMessage:     "Loading clients...",
 		loading:           true,
 		showHelp:          false,
-		logLines:          100, // Default to 100 lines
+		logLines:          cfg.LogLines,
+		config:            cfg,
 		selectedContainer: "",
 	}
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
-		initializeClients(),
+		initializeClients(m.config),
 		tea.EnterAltScreen,
 	)
 }
@@ -304,10 +482,23 @@ func formatAge(timestamp metav1.Time) string {
 }
 
 // initializeClients initializes both Kubernetes and database clients
-func initializeClients() tea.Cmd {
+func initializeClients(cfg Config) tea.Cmd {
 	return func() tea.Msg {
-		// Create a logger
-		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		// Log to the TUI's log file rather than over the screen
+		logger := slog.New(slog.NewTextHandler(log.Writer(), nil))
+
+		// In API mode every read goes through the REST API and its RBAC checks
+		if cfg.APIURL != "" {
+			api, err := apiclient.New(cfg.APIURL, cfg.Token, cfg.TokenCommand)
+			if err != nil {
+				return errorMsg{err: fmt.Errorf("failed to initialize API client: %w", err)}
+			}
+
+			return clientsLoadedMsg{
+				dbClient: api,
+				api:      api,
+			}
+		}
 
 		// Create Kubernetes client manager
 		clientManager, err := cluster.NewClientManager(logger)
@@ -317,7 +508,7 @@ func initializeClients() tea.Cmd {
 
 		// Create database client
 		ctx := context.Background()
-		dbClient, err := store.NewStore(ctx, "mongodb://localhost:27017", "k8s-starship", logger)
+		dbClient, err := store.NewStore(ctx, cfg.MongoURI, cfg.Database, logger)
 		if err != nil {
 			return errorMsg{err: fmt.Errorf("failed to initialize database client: %w", err)}
 		}
@@ -330,7 +521,7 @@ func initializeClients() tea.Cmd {
 }
 
 // Load clusters from database
-func loadClusters(dbClient store.Repository) tea.Cmd {
+func loadClusters(dbClient resourceStore) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -344,7 +535,11 @@ func loadClusters(dbClient store.Repository) tea.Cmd {
 
 		rows := make([]table.Row, 0, len(clusters))
 		for _, c := range clusters {
-			rows = append(rows, table.Row{c.Name, c.APIURL})
+			status := c.Status
+			if status == "" {
+				status = cluster.StatusUnknown
+			}
+			rows = append(rows, table.Row{c.Name, c.APIURL, status})
 		}
 
 		return clustersLoadedMsg{rows: rows}
@@ -352,7 +547,7 @@ func loadClusters(dbClient store.Repository) tea.Cmd {
 }
 
 // Load namespaces from database
-func loadNamespaces(dbClient store.Repository, clusterID string) tea.Cmd {
+func loadNamespaces(dbClient resourceStore, clusterID string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -366,8 +561,7 @@ func loadNamespaces(dbClient store.Repository, clusterID string) tea.Cmd {
 
 		rows := make([]table.Row, 0, len(namespaces))
 		for _, ns := range namespaces {
-			age := formatAge(ns.CreationTimestamp)
-			rows = append(rows, table.Row{ns.Name, string(ns.Status.Phase), age})
+			rows = append(rows, namespaceRow(ns))
 		}
 
 		return namespacesLoadedMsg{rows: rows}
@@ -375,7 +569,7 @@ func loadNamespaces(dbClient store.Repository, clusterID string) tea.Cmd {
 }
 
 // Load pods from database
-func loadPods(dbClient store.Repository, clusterID, namespace string) tea.Cmd {
+func loadPods(dbClient resourceStore, clusterID, namespace string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -389,38 +583,53 @@ func loadPods(dbClient store.Repository, clusterID, namespace string) tea.Cmd {
 
 		rows := make([]table.Row, 0, len(pods))
 		for _, pod := range pods {
-			// Calculate readiness
-			ready := 0
-			for _, containerStatus := range pod.Status.ContainerStatuses {
-				if containerStatus.Ready {
-					ready++
-				}
+			if namespace == allNamespaces {
+				rows = append(rows, withNamespace(podRow(pod), pod.Namespace))
+			} else {
+				rows = append(rows, podRow(pod))
 			}
-			readyStr := fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+		}
 
-			// Calculate restarts
-			restarts := 0
-			for _, containerStatus := range pod.Status.ContainerStatuses {
-				restarts += int(containerStatus.RestartCount)
-			}
+		return podsLoadedMsg{rows: rows}
+	}
+}
 
-			age := formatAge(pod.CreationTimestamp)
+// namespaceRow builds the namespace table row for a namespace
+func namespaceRow(ns corev1.Namespace) table.Row {
+	return table.Row{ns.Name, string(ns.Status.Phase), formatAge(ns.CreationTimestamp)}
+}
 
-			rows = append(rows, table.Row{
-				pod.Name,
-				readyStr,
-				string(pod.Status.Phase),
-				fmt.Sprintf("%d", restarts),
-				age,
-			})
+// podRow builds the pod table row for a pod
+func podRow(pod corev1.Pod) table.Row {
+	// Calculate readiness
+	ready := 0
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Ready {
+			ready++
 		}
+	}
+	readyStr := fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
 
-		return podsLoadedMsg{rows: rows}
+	// Calculate restarts
+	restarts := 0
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		restarts += int(containerStatus.RestartCount)
+	}
+
+	age := formatAge(pod.CreationTimestamp)
+
+	return table.Row{
+		pod.Name,
+		readyStr,
+		string(pod.Status.Phase),
+		fmt.Sprintf("%d", restarts),
+		podBadges(pod),
+		age,
 	}
 }
 
 // Load ConfigMaps from database
-func loadConfigMaps(dbClient store.Repository, clusterID, namespace string) tea.Cmd {
+func loadConfigMaps(dbClient resourceStore, clusterID, namespace string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -434,178 +643,56 @@ func loadConfigMaps(dbClient store.Repository, clusterID, namespace string) tea.
 
 		rows := make([]table.Row, 0, len(configMaps))
 		for _, cm := range configMaps {
-			// Count number of keys in the ConfigMap data
-			keyCount := len(cm.Data)
-			keysList := ""
-			if keyCount > 0 {
-				keys := make([]string, 0, keyCount)
-				for k := range cm.Data {
-					keys = append(keys, k)
-				}
-				// Show first few keys with ellipsis if too many
-				if len(keys) > 3 {
-					keysList = fmt.Sprintf("%s, %s, %s... (%d total)", keys[0], keys[1], keys[2], len(keys))
-				} else {
-					keysList = strings.Join(keys, ", ")
-				}
-			} else {
-				keysList = "<empty>"
-			}
-
-			age := formatAge(cm.CreationTimestamp)
-			rows = append(rows, table.Row{cm.Name, keysList, age})
+			rows = append(rows, configMapRow(cm))
 		}
 
 		return configMapsLoadedMsg{rows: rows}
 	}
 }
 
-// Load pod details from database
-func loadPodDetails(dbClient store.Repository, clusterID, namespace, podName string) tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		// Get pod from database
-		var pod corev1.Pod
-		err := dbClient.Get(ctx, clusterID, namespace, "Pod", podName, &pod)
-		if err != nil {
-			return errorMsg{err: fmt.Errorf("failed to get pod from database: %w", err)}
-		}
-
-		// Format detailed info
-		content := fmt.Sprintf("Pod: %s\n", pod.Name)
-		content += fmt.Sprintf("Namespace: %s\n", pod.Namespace)
-		content += fmt.Sprintf("Node: %s\n", pod.Spec.NodeName)
-		content += fmt.Sprintf("Status: %s\n", pod.Status.Phase)
-		content += fmt.Sprintf("IP: %s\n", pod.Status.PodIP)
-		content += fmt.Sprintf("Created: %s\n\n", pod.CreationTimestamp.Format(time.RFC3339))
-
-		content += "Labels:\n"
-		for k, v := range pod.Labels {
-			content += fmt.Sprintf("  %s: %s\n", k, v)
-		}
-		content += "\n"
-
-		content += "Containers:\n"
-		for i, container := range pod.Spec.Containers {
-			content += fmt.Sprintf("  [%d] %s\n", i+1, container.Name)
-			content += fmt.Sprintf("      Image: %s\n", container.Image)
-
-			// Add status if available
-			for _, status := range pod.Status.ContainerStatuses {
-				if status.Name == container.Name {
-					content += fmt.Sprintf("      Ready: %t\n", status.Ready)
-					content += fmt.Sprintf("      Restarts: %d\n", status.RestartCount)
-
-					if status.State.Running != nil {
-						content += fmt.Sprintf("      State: Running (started %s)\n",
-							formatAge(status.State.Running.StartedAt))
-					} else if status.State.Waiting != nil {
-						content += fmt.Sprintf("      State: Waiting (%s)\n", status.State.Waiting.Reason)
-					} else if status.State.Terminated != nil {
-						content += fmt.Sprintf("      State: Terminated (%s)\n", status.State.Terminated.Reason)
-					}
-				}
-			}
-
-			// Add resource requests/limits
-			if container.Resources.Limits != nil || container.Resources.Requests != nil {
-				content += "      Resources:\n"
-				if container.Resources.Requests != nil {
-					cpu := container.Resources.Requests.Cpu()
-					memory := container.Resources.Requests.Memory()
-					content += fmt.Sprintf("        Requests: %s CPU, %s memory\n", cpu.String(), memory.String())
-				}
-				if container.Resources.Limits != nil {
-					cpu := container.Resources.Limits.Cpu()
-					memory := container.Resources.Limits.Memory()
-					content += fmt.Sprintf("        Limits: %s CPU, %s memory\n", cpu.String(), memory.String())
-				}
-			}
-			content += "\n"
+// configMapRow builds the ConfigMap table row for a ConfigMap
+func configMapRow(cm corev1.ConfigMap) table.Row {
+	// Count number of keys in the ConfigMap data
+	keyCount := len(cm.Data)
+	keysList := ""
+	if keyCount > 0 {
+		keys := make([]string, 0, keyCount)
+		for k := range cm.Data {
+			keys = append(keys, k)
 		}
-
-		return podDetailsLoadedMsg{content: content}
+		// Show first few keys with ellipsis if too many
+		if len(keys) > 3 {
+			keysList = fmt.Sprintf("%s, %s, %s... (%d total)", keys[0], keys[1], keys[2], len(keys))
+		} else {
+			keysList = strings.Join(keys, ", ")
+		}
+	} else {
+		keysList = "<empty>"
 	}
+
+	age := formatAge(cm.CreationTimestamp)
+	return table.Row{cm.Name, keysList, age}
 }
 
-// Load ConfigMap details from database
-func loadConfigMapDetails(dbClient store.Repository, clusterID, namespace, configMapName string) tea.Cmd {
+// Keep pod logs fetching directly from K8s API, or from the API's log stream in API mode
+func loadPodLogs(api *apiclient.Client, clientManager *cluster.ClientManager, clusterID, namespace, podName, containerName string, lines int64) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		// Get ConfigMap from database
-		var cm corev1.ConfigMap
-		err := dbClient.Get(ctx, clusterID, namespace, "ConfigMap", configMapName, &cm)
-		if err != nil {
-			return errorMsg{err: fmt.Errorf("failed to get ConfigMap from database: %w", err)}
-		}
-
-		// Format detailed info
-		content := fmt.Sprintf("ConfigMap: %s\n", cm.Name)
-		content += fmt.Sprintf("Namespace: %s\n", cm.Namespace)
-		content += fmt.Sprintf("Created: %s\n\n", cm.CreationTimestamp.Format(time.RFC3339))
-
-		content += "Labels:\n"
-		if len(cm.Labels) == 0 {
-			content += "  <none>\n"
-		} else {
-			for k, v := range cm.Labels {
-				content += fmt.Sprintf("  %s: %s\n", k, v)
-			}
-		}
-		content += "\n"
-
-		content += "Annotations:\n"
-		if len(cm.Annotations) == 0 {
-			content += "  <none>\n"
-		} else {
-			for k, v := range cm.Annotations {
-				content += fmt.Sprintf("  %s: %s\n", k, v)
+		if api != nil {
+			content, err := api.Logs(ctx, clusterID, namespace, podName, containerName, lines)
+			if err != nil {
+				return errorMsg{err: fmt.Errorf("failed to get pod logs: %w", err)}
 			}
+			return podLogsLoadedMsg{pod: podName, content: content}
 		}
-		content += "\n"
-
-		content += "Data:\n"
-		if len(cm.Data) == 0 && len(cm.BinaryData) == 0 {
-			content += "  <empty>\n"
-		} else {
-			content += fmt.Sprintf("  %d items\n\n", len(cm.Data)+len(cm.BinaryData))
-
-			// Regular data (string content)
-			for k, v := range cm.Data {
-				content += fmt.Sprintf("---[ %s ]---\n", k)
-				// Limit content size for display
-				if len(v) > 1000 {
-					content += v[:1000] + "... (truncated)\n\n"
-				} else {
-					content += v + "\n\n"
-				}
-			}
 
-			// Binary data (just show keys)
-			for k := range cm.BinaryData {
-				content += fmt.Sprintf("---[ %s ]--- (binary data)\n\n", k)
-			}
-		}
-
-		return podDetailsLoadedMsg{content: content}
-	}
-}
-
-// Keep pod logs fetching directly from K8s API
-func loadPodLogs(clientManager *cluster.ClientManager, clusterID, namespace, podName, containerName string, lines int64) tea.Cmd {
-	return func() tea.Msg {
-		client, exists := clientManager.GetClient(clusterID)
-		if !exists {
-			return errorMsg{err: fmt.Errorf("cluster %s not found", clusterID)}
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
 		// Set up logs options
 		options := &corev1.PodLogOptions{
 			Container: containerName,
@@ -630,21 +717,31 @@ func loadPodLogs(clientManager *cluster.ClientManager, clusterID, namespace, pod
 			return errorMsg{err: fmt.Errorf("failed to read pod logs: %w", err)}
 		}
 
-		return podLogsLoadedMsg{content: buf.String()}
+		return podLogsLoadedMsg{pod: podName, content: buf.String()}
 	}
 }
 
-// Get pod container information for logs (still uses K8s client)
-func getPodContainers(clientManager *cluster.ClientManager, clusterID, namespace, podName string) tea.Cmd {
+// Get pod container information for logs (still uses K8s client, or the stored pod in API mode)
+func getPodContainers(dbClient resourceStore, clientManager *cluster.ClientManager, clusterID, namespace, podName string) tea.Cmd {
 	return func() tea.Msg {
-		client, exists := clientManager.GetClient(clusterID)
-		if !exists {
-			return errorMsg{err: fmt.Errorf("cluster %s not found", clusterID)}
-		}
-
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		if clientManager == nil {
+			var pod corev1.Pod
+			if err := dbClient.Get(ctx, clusterID, namespace, "Pod", podName, &pod); err != nil {
+				return errorMsg{err: fmt.Errorf("failed to get pod: %w", err)}
+			}
+			return struct {
+				pod *corev1.Pod
+			}{pod: &pod}
+		}
+
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
 		pod, err := client.Client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
 		if err != nil {
 			return errorMsg{err: fmt.Errorf("failed to get pod: %w", err)}
@@ -667,52 +764,130 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 		headerHeight := 6 // Title + status + padding
-		footerHeight := 3 // Help view + padding
+		footerHeight := 4 // Status bar, help view + padding
 		tableHeight := m.height - headerHeight - footerHeight
 
 		m.clusterTable.SetHeight(tableHeight)
 		m.namespaceTable.SetHeight(tableHeight)
 		m.podTable.SetHeight(tableHeight)
 		m.configMapTable.SetHeight(tableHeight)
+		m.resourceTable.SetHeight(tableHeight)
 		m.detailView.Height = tableHeight
 		m.logsView.Height = tableHeight
+		m.yamlView.Height = tableHeight
+		m.eventsView.Height = tableHeight
 		m.detailView.Width = m.width - 4
 		m.logsView.Width = m.width - 4
+		m.yamlView.Width = m.width - 4
+		m.eventsView.Width = m.width - 4
+		m.layoutPanes()
 
 		m.help.Width = m.width
 
 	case clientsLoadedMsg:
 		m.clientManager = msg.clientManager
 		m.dbClient = msg.dbClient
-		return m, loadClusters(m.dbClient)
+		m.api = msg.api
+		return m, tea.Batch(loadClusters(m.dbClient), checkHealth(m.dbClient))
+
+	case healthCheckedMsg:
+		m.healthErr = msg.err
+		m.healthChecked = true
+		return m, scheduleHealthCheck()
+
+	case healthTickMsg:
+		return m, checkHealth(m.dbClient)
 
 	case clustersLoadedMsg:
 		m.clusterTable.SetRows(msg.rows)
 		m.statusMessage = fmt.Sprintf("Loaded %d clusters", len(msg.rows))
+		m.markRefreshed()
 		m.loading = false
 
 	case namespacesLoadedMsg:
 		m.namespaceTable.SetRows(msg.rows)
 		m.statusMessage = fmt.Sprintf("Loaded %d namespaces", len(msg.rows))
+		m.markRefreshed()
 		m.loading = false
+		return m, m.startWatch("Namespace", "")
 
 	case podsLoadedMsg:
 		m.podTable.SetRows(msg.rows)
 		m.statusMessage = fmt.Sprintf("Loaded %d pods", len(msg.rows))
+		m.markRefreshed()
 		m.loading = false
+		return m, m.startWatch("Pod", m.podsNamespace())
 
 	case configMapsLoadedMsg:
 		m.configMapTable.SetRows(msg.rows)
 		m.statusMessage = fmt.Sprintf("Loaded %d configmaps", len(msg.rows))
+		m.markRefreshed()
 		m.loading = false
+		return m, m.startWatch("ConfigMap", m.selectedNamespace)
+
+	case watchStartedMsg:
+		if current, ok := m.watches[msg.kind]; ok && current.id == msg.id {
+			return m, waitForChange(m.dbClient, m.selectedCluster, msg.id, msg.changes)
+		}
+
+	case watchFailedMsg:
+		if current, ok := m.watches[msg.kind]; ok && current.id == msg.id {
+			delete(m.watches, msg.kind)
+			m.statusMessage = "Live updates unavailable, press r to refresh: " + msg.err.Error()
+		}
+
+	case watchEndedMsg:
+		for kind, current := range m.watches {
+			if current.id == msg.id {
+				delete(m.watches, kind)
+				m.statusMessage = "Live updates stopped, press r to refresh"
+			}
+		}
+
+	case resourceChangedMsg:
+		if current, ok := m.watches[msg.change.Kind]; !ok || current.id != msg.id {
+			// Stale change from a replaced watch
+			return m, nil
+		}
+
+		if msg.change.Kind == "Pod" && m.allNamespaces && msg.row != nil {
+			msg.row = withNamespace(msg.row, msg.change.Namespace)
+		}
+
+		cmds = append(cmds, waitForChange(m.dbClient, m.selectedCluster, msg.id, msg.changes))
+		if msg.change.Op == store.ChangeDelete || msg.row != nil {
+			cmds = append(cmds, m.applyChange(msg.change, msg.row))
+			m.markRefreshed()
+		}
+		if !msg.change.Time.IsZero() {
+			m.streamLag = time.Since(msg.change.Time)
+		}
+
+	case splitTailMsg:
+		return m, m.handleSplitTail(msg)
+
+	case flashExpiredMsg:
+		m.clearFlash(msg.kind, msg.namespace, msg.name)
 
-	case podDetailsLoadedMsg:
+	case detailsLoadedMsg:
+		if m.split && m.currentView == PodView && msg.name != m.selectedPod {
+			// A pod that is no longer highlighted in the split view
+			break
+		}
 		m.detailView.SetContent(msg.content)
 		m.statusMessage = "Loaded details"
 		m.loading = false
 
 	case podLogsLoadedMsg:
-		m.logsView.SetContent(msg.content)
+		if m.split && m.currentView == PodView && msg.pod != m.selectedPod {
+			break
+		}
+		m.logContent = msg.content
+		m.refreshLogs()
+		if m.split && m.logQuery == "" {
+			// Follow the tail in the split view
+			m.logsView.GotoBottom()
+		}
 		m.statusMessage = "Loaded pod logs"
 		m.loading = false
 
@@ -727,13 +902,132 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Now load the logs with the selected container
-		return m, loadPodLogs(m.clientManager, m.selectedCluster, m.selectedNamespace, m.selectedPod, m.selectedContainer, m.logLines)
+		return m, loadPodLogs(m.api, m.clientManager, m.selectedCluster, m.selectedPodNamespace, m.selectedPod, m.selectedContainer, m.logLines)
+
+	case resourcesLoadedMsg:
+		if m.resourceType != nil && m.resourceType.Kind == msg.kind {
+			m.resourceTable.SetRows(msg.rows)
+			m.statusMessage = fmt.Sprintf("Loaded %d %s", len(msg.rows), strings.ToLower(m.resourceType.Title))
+			m.markRefreshed()
+		}
+		m.loading = false
+
+	case eventsLoadedMsg:
+		m.eventsView.SetContent(msg.content)
+		m.eventsView.GotoTop()
+		m.statusMessage = fmt.Sprintf("Loaded %d events (%d warnings)", msg.count, msg.warnings)
+		m.loading = false
+
+	case yamlLoadedMsg:
+		content, err := toYAML(msg.object, true)
+		if err != nil {
+			m.errorMessage = err.Error()
+			m.loading = false
+			return m, nil
+		}
+
+		m.yamlObject = msg.object
+		m.yamlView.SetContent(content)
+		m.yamlView.GotoTop()
+		m.currentView = YAMLView
+		m.statusMessage = fmt.Sprintf("Loaded %s %s", msg.object.GetKind(), msg.object.GetName())
+		m.loading = false
+
+	case editorFinishedMsg:
+		m.handleEditorFinished(msg)
+
+	case resourceAppliedMsg:
+		m.statusMessage = fmt.Sprintf("Updated %s %s", msg.kind, msg.name)
+		m.errorMessage = ""
+		return m, loadYAML(m.api, m.clientManager, m.selectedCluster, msg.namespace, msg.kind, msg.name)
+
+	case resourceDeletedMsg:
+		m.statusMessage = fmt.Sprintf("Deleted %s %s", msg.kind, msg.name)
+		m.errorMessage = ""
+
+		// Refresh the table the resource was deleted from
+		switch m.currentView {
+		case PodView:
+			return m, loadPods(m.dbClient, m.selectedCluster, m.podsNamespace())
+		case NamespaceView:
+			return m, loadNamespaces(m.dbClient, m.selectedCluster)
+		}
+		m.loading = false
+
+	case logsExportedMsg:
+		m.statusMessage = fmt.Sprintf("Saved %d bytes of logs to %s", msg.size, msg.path)
+		m.loading = false
+
+	case copiedMsg:
+		if msg.path != "" {
+			m.statusMessage = fmt.Sprintf("No clipboard available, wrote %s to %s", msg.what, msg.path)
+		} else {
+			m.statusMessage = fmt.Sprintf("Copied %s to clipboard", msg.what)
+		}
+
+	case workloadUpdatedMsg:
+		m.statusMessage = msg.status
+		m.errorMessage = ""
+
+		if m.currentView == ResourceView && m.resourceType != nil {
+			return m, loadResources(m.clientManager, m.selectedCluster, m.selectedNamespace, m.resourceType)
+		}
+		m.loading = false
 
 	case errorMsg:
 		m.errorMessage = msg.err.Error()
 		m.loading = false
 
 	case tea.KeyMsg:
+		// Open dialogs and prompts take all keys until they are closed
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+
+		if m.filterPrompt.active {
+			// Filter as the user types; esc clears the filter
+			if t := m.activeTable(); t != nil {
+				if _, cancelled := m.filterPrompt.update(msg); cancelled {
+					t.SetFilter("")
+				} else {
+					t.SetFilter(m.filterPrompt.value)
+				}
+			} else {
+				m.filterPrompt.close()
+			}
+			return m, nil
+		}
+
+		if m.commandPrompt.active {
+			return m, m.updateCommandPrompt(msg)
+		}
+
+		if m.scalePrompt.active {
+			if submitted, _ := m.scalePrompt.update(msg); submitted {
+				m.errorMessage = ""
+				m.confirmScale()
+			}
+			return m, nil
+		}
+
+		if m.exportPrompt.active {
+			return m, m.updateExportPrompt(msg)
+		}
+
+		if m.logSearch.active {
+			if submitted, _ := m.logSearch.update(msg); submitted {
+				m.logQuery = m.logSearch.value
+				m.logMatch = 0
+				m.refreshLogs()
+			}
+			return m, nil
+		}
+
+		var complete bool
+		if msg, complete = m.readSequence(msg); !complete {
+			return m, nil
+		}
+
 		if key.Matches(msg, m.keys.Help) {
 			m.showHelp = !m.showHelp
 			return m, nil
@@ -745,9 +1039,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if key.Matches(msg, m.keys.Quit) {
+			m.stopWatches()
 			return m, tea.Quit
 		}
 
+		if m.jumpViewport(msg) {
+			return m, nil
+		}
+
+		if m.currentView == PodView {
+			if key.Matches(msg, m.keys.Split) && !m.split {
+				return m, m.toggleSplit()
+			}
+			if m.split {
+				if handled, cmd := m.updateSplit(msg); handled {
+					return m, cmd
+				}
+			}
+		}
+
 		if key.Matches(msg, m.keys.SwitchResource) {
 			// Only switch in namespace view
 			if m.currentView == NamespaceView {
@@ -762,6 +1072,67 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if key.Matches(msg, m.keys.Delete) && (m.currentView == PodView || m.currentView == NamespaceView) {
+			m.errorMessage = ""
+			m.confirmDelete()
+			return m, nil
+		}
+
+		if t := m.activeTable(); t != nil {
+			switch {
+			case key.Matches(msg, m.keys.Search):
+				m.filterPrompt.open("/", t.Filter())
+				return m, nil
+			case key.Matches(msg, m.keys.Back) && t.Filter() != "":
+				// Clear the filter before leaving the table
+				t.SetFilter("")
+				return m, nil
+			case key.Matches(msg, m.keys.SortName):
+				m.sortActiveTable("Name")
+				return m, nil
+			case key.Matches(msg, m.keys.SortStatus):
+				m.sortActiveTable("Status")
+				return m, nil
+			case key.Matches(msg, m.keys.SortRestarts):
+				m.sortActiveTable("Restarts")
+				return m, nil
+			case key.Matches(msg, m.keys.SortAge):
+				m.sortActiveTable("Age")
+				return m, nil
+			}
+		}
+
+		if key.Matches(msg, m.keys.AllNamespaces) && (m.currentView == NamespaceView || m.currentView == PodView) {
+			m.errorMessage = ""
+			return m, m.showAllNamespaces()
+		}
+
+		if key.Matches(msg, m.keys.Events) {
+			m.errorMessage = ""
+			return m, m.showEvents()
+		}
+
+		if key.Matches(msg, m.keys.Copy) {
+			m.errorMessage = ""
+			return m, m.copyName()
+		}
+
+		if key.Matches(msg, m.keys.CopyYAML) {
+			m.errorMessage = ""
+			return m, m.copyYAML()
+		}
+
+		if key.Matches(msg, m.keys.Command) {
+			m.commandPrompt.open(":", "")
+			m.historyIndex = len(m.commandHistory)
+			return m, nil
+		}
+
+		if key.Matches(msg, m.keys.YAML) && (m.currentView == PodView || m.currentView == ConfigMapView || m.currentView == ResourceView) {
+			m.errorMessage = ""
+			return m, m.viewYAML()
+		}
+
 		if key.Matches(msg, m.keys.Refresh) {
 			// Refresh the current view
 			switch m.currentView {
@@ -776,7 +1147,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case PodView:
 				m.loading = true
 				m.statusMessage = "Refreshing pods..."
-				return m, loadPods(m.dbClient, m.selectedCluster, m.selectedNamespace)
+				return m, loadPods(m.dbClient, m.selectedCluster, m.podsNamespace())
 			case ConfigMapView:
 				m.loading = true
 				m.statusMessage = "Refreshing ConfigMaps..."
@@ -785,15 +1156,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.loading = true
 				if m.selectedPod != "" {
 					m.statusMessage = "Refreshing pod details..."
-					return m, loadPodDetails(m.dbClient, m.selectedCluster, m.selectedNamespace, m.selectedPod)
+					return m, loadDetails(m.api, m.clientManager, m.selectedCluster, "Pod", m.selectedPodNamespace, m.selectedPod)
 				} else if m.selectedConfigMap != "" {
 					m.statusMessage = "Refreshing ConfigMap details..."
-					return m, loadConfigMapDetails(m.dbClient, m.selectedCluster, m.selectedNamespace, m.selectedConfigMap)
+					return m, loadDetails(m.api, m.clientManager, m.selectedCluster, "ConfigMap", m.selectedNamespace, m.selectedConfigMap)
+				} else if m.selectedObject != "" {
+					m.statusMessage = "Refreshing details..."
+					return m, loadDetails(m.api, m.clientManager, m.selectedCluster, m.resourceType.Kind, m.selectedNamespace, m.selectedObject)
 				}
+			case ResourceView:
+				m.loading = true
+				m.statusMessage = fmt.Sprintf("Refreshing %s...", m.resourceType.Title)
+				return m, loadResources(m.clientManager, m.selectedCluster, m.selectedNamespace, m.resourceType)
+			case EventsView:
+				m.loading = true
+				m.statusMessage = "Refreshing events..."
+				return m, loadEvents(m.clientManager, m.selectedCluster, m.eventsTarget)
+			case YAMLView:
+				m.loading = true
+				m.statusMessage = "Refreshing YAML..."
+				return m, loadYAML(m.api, m.clientManager, m.selectedCluster, m.yamlObject.GetNamespace(), m.yamlObject.GetKind(), m.yamlObject.GetName())
 			case LogsView:
 				m.loading = true
 				m.statusMessage = "Refreshing pod logs..."
-				return m, loadPodLogs(m.clientManager, m.selectedCluster, m.selectedNamespace, m.selectedPod, m.selectedContainer, m.logLines)
+				return m, loadPodLogs(m.api, m.clientManager, m.selectedCluster, m.selectedPodNamespace, m.selectedPod, m.selectedContainer, m.logLines)
 			}
 		}
 
@@ -807,12 +1193,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				selectedRow := m.clusterTable.SelectedRow()
-				m.selectedCluster = selectedRow[0] // Cluster name
-				m.currentView = NamespaceView
-				m.statusMessage = "Loading namespaces..."
-				m.loading = true
-
-				return m, loadNamespaces(m.dbClient, m.selectedCluster)
+				return m, m.openCluster(selectedRow[0]) // Cluster name
 			}
 
 		case NamespaceView:
@@ -820,6 +1201,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case key.Matches(msg, m.keys.Back):
 				m.currentView = ClusterView
 				m.selectedNamespace = ""
+				m.stopWatches()
 				return m, nil
 			case key.Matches(msg, m.keys.Enter):
 				if len(m.namespaceTable.Rows()) == 0 {
@@ -848,32 +1230,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case key.Matches(msg, m.keys.Back):
 				m.currentView = NamespaceView
 				m.selectedPod = ""
+				m.setAllNamespaces(false)
 				return m, nil
 			case key.Matches(msg, m.keys.Enter):
-				if len(m.podTable.Rows()) == 0 {
+				if !m.selectPod() {
 					return m, nil
 				}
-
-				selectedRow := m.podTable.SelectedRow()
-				m.selectedPod = selectedRow[0] // Pod name
-				m.currentView = DetailView
-				m.statusMessage = "Loading pod details..."
-				m.loading = true
-
-				return m, loadPodDetails(m.dbClient, m.selectedCluster, m.selectedNamespace, m.selectedPod)
+				return m, m.openPodDetails()
 			case key.Matches(msg, m.keys.Logs):
-				if len(m.podTable.Rows()) == 0 {
+				if !m.selectPod() {
 					return m, nil
 				}
-
-				selectedRow := m.podTable.SelectedRow()
-				m.selectedPod = selectedRow[0] // Pod name
-				m.currentView = LogsView
-				m.statusMessage = "Loading container info..."
-				m.loading = true
-
-				// First get pod container info, then we'll request logs for the selected container
-				return m, getPodContainers(m.clientManager, m.selectedCluster, m.selectedNamespace, m.selectedPod)
+				return m, m.openPodLogs()
 			}
 
 		case ConfigMapView:
@@ -893,7 +1261,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.statusMessage = "Loading ConfigMap details..."
 				m.loading = true
 
-				return m, loadConfigMapDetails(m.dbClient, m.selectedCluster, m.selectedNamespace, m.selectedConfigMap)
+				return m, loadDetails(m.api, m.clientManager, m.selectedCluster, "ConfigMap", m.selectedNamespace, m.selectedConfigMap)
 			}
 
 		case DetailView:
@@ -904,13 +1272,86 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else if m.selectedConfigMap != "" {
 					m.currentView = ConfigMapView
 					m.selectedConfigMap = ""
+				} else if m.selectedObject != "" {
+					m.currentView = ResourceView
+					m.selectedObject = ""
 				}
 				return m, nil
 			}
 
-		case LogsView:
+		case ResourceView:
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.currentView = NamespaceView
+				return m, nil
+			case key.Matches(msg, m.keys.Scale):
+				m.errorMessage = ""
+				m.openScalePrompt()
+				return m, nil
+			case key.Matches(msg, m.keys.Restart):
+				m.errorMessage = ""
+				m.confirmRestart()
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				if len(m.resourceTable.Rows()) == 0 {
+					return m, nil
+				}
+
+				m.selectedObject = m.resourceTable.SelectedRow()[0]
+				m.currentView = DetailView
+				m.statusMessage = "Loading details..."
+				m.loading = true
+
+				return m, loadDetails(m.api, m.clientManager, m.selectedCluster, m.resourceType.Kind, m.selectedNamespace, m.selectedObject)
+			}
+
+		case EventsView:
 			if key.Matches(msg, m.keys.Back) {
+				m.currentView = m.eventsReturnView
+				return m, nil
+			}
+
+		case YAMLView:
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.currentView = m.yamlReturnView
+				m.yamlObject = nil
+				return m, nil
+			case key.Matches(msg, m.keys.Edit):
+				m.errorMessage = ""
+				return m, editYAML(m.yamlObject)
+			}
+
+		case LogsView:
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				if m.logQuery != "" {
+					// Clear the search before leaving the logs
+					m.logQuery = ""
+					m.refreshLogs()
+					return m, nil
+				}
 				m.currentView = PodView
+				m.logLevel = logLevelAll
+				return m, nil
+			case key.Matches(msg, m.keys.Search):
+				m.logSearch.open("/", m.logQuery)
+				return m, nil
+			case key.Matches(msg, m.keys.ExportLogs):
+				m.errorMessage = ""
+				m.openExportPrompt()
+				return m, nil
+			case key.Matches(msg, m.keys.NextMatch):
+				m.moveLogMatch(1)
+				return m, nil
+			case key.Matches(msg, m.keys.PrevMatch):
+				m.moveLogMatch(-1)
+				return m, nil
+			case key.Matches(msg, m.keys.FilterErrors):
+				m.toggleLogLevel(logLevelError)
+				return m, nil
+			case key.Matches(msg, m.keys.FilterWarnings):
+				m.toggleLogLevel(logLevelWarn)
 				return m, nil
 			}
 		}
@@ -918,16 +1359,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update the appropriate table/viewport based on current view
 		switch m.currentView {
 		case ClusterView:
-			m.clusterTable, cmd = m.clusterTable.Update(msg)
+			m.clusterTable.Model, cmd = m.clusterTable.Update(msg)
 			cmds = append(cmds, cmd)
 		case NamespaceView:
-			m.namespaceTable, cmd = m.namespaceTable.Update(msg)
+			m.namespaceTable.Model, cmd = m.namespaceTable.Update(msg)
 			cmds = append(cmds, cmd)
 		case PodView:
-			m.podTable, cmd = m.podTable.Update(msg)
+			m.podTable.Model, cmd = m.podTable.Update(msg)
 			cmds = append(cmds, cmd)
+			if m.split {
+				// Keep the pane on the highlighted pod
+				cmds = append(cmds, m.loadPane())
+			}
 		case ConfigMapView:
-			m.configMapTable, cmd = m.configMapTable.Update(msg)
+			m.configMapTable.Model, cmd = m.configMapTable.Update(msg)
 			cmds = append(cmds, cmd)
 		case DetailView:
 			m.detailView, cmd = m.detailView.Update(msg)
@@ -935,6 +1380,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case LogsView:
 			m.logsView, cmd = m.logsView.Update(msg)
 			cmds = append(cmds, cmd)
+		case YAMLView:
+			m.yamlView, cmd = m.yamlView.Update(msg)
+			cmds = append(cmds, cmd)
+		case ResourceView:
+			m.resourceTable.Model, cmd = m.resourceTable.Update(msg)
+			cmds = append(cmds, cmd)
+		case EventsView:
+			m.eventsView, cmd = m.eventsView.Update(msg)
+			cmds = append(cmds, cmd)
 		}
 	}
 
@@ -944,6 +1398,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// openCluster selects a cluster and shows its namespaces, or the configured
+// default namespace with the namespace list behind it
+func (m *Model) openCluster(name string) tea.Cmd {
+	m.selectedCluster = name
+
+	switch m.config.Namespace {
+	case "":
+	case allNamespaces:
+		return tea.Batch(loadNamespaces(m.dbClient, m.selectedCluster), m.showAllNamespaces())
+	default:
+		m.selectedNamespace = m.config.Namespace
+		m.selectedResource = "pods"
+		m.currentView = PodView
+		m.statusMessage = "Loading pods..."
+		m.loading = true
+		return tea.Batch(loadNamespaces(m.dbClient, m.selectedCluster), loadPods(m.dbClient, m.selectedCluster, m.selectedNamespace))
+	}
+
+	m.currentView = NamespaceView
+	m.statusMessage = "Loading namespaces..."
+	m.loading = true
+
+	return loadNamespaces(m.dbClient, m.selectedCluster)
+}
+
+// openPodDetails shows the description of the selected pod
+func (m *Model) openPodDetails() tea.Cmd {
+	m.currentView = DetailView
+	m.statusMessage = "Loading pod details..."
+	m.loading = true
+
+	return loadDetails(m.api, m.clientManager, m.selectedCluster, "Pod", m.selectedPodNamespace, m.selectedPod)
+}
+
+// openPodLogs shows the logs of the selected pod
+func (m *Model) openPodLogs() tea.Cmd {
+	m.currentView = LogsView
+	m.logQuery = ""
+	m.logMatch = 0
+	m.statusMessage = "Loading container info..."
+	m.loading = true
+
+	// First get pod container info, then we'll request logs for the selected container
+	return getPodContainers(m.dbClient, m.clientManager, m.selectedCluster, m.selectedPodNamespace, m.selectedPod)
+}
+
 func (m Model) View() string {
 	// Show help or main view
 	if m.showHelp {
@@ -962,7 +1462,11 @@ func (m Model) View() string {
 		title += fmt.Sprintf(" - Namespaces (Cluster: %s) - Press TAB for %s",
 			m.selectedCluster, strings.ToUpper(resourceType[0:1])+resourceType[1:])
 	case PodView:
-		title += fmt.Sprintf(" - Pods (Namespace: %s)", m.selectedNamespace)
+		if m.allNamespaces {
+			title += fmt.Sprintf(" - Pods (Cluster: %s, all namespaces)", m.selectedCluster)
+		} else {
+			title += fmt.Sprintf(" - Pods (Namespace: %s)", m.selectedNamespace)
+		}
 	case ConfigMapView:
 		title += fmt.Sprintf(" - ConfigMaps (Namespace: %s)", m.selectedNamespace)
 	case DetailView:
@@ -970,9 +1474,23 @@ func (m Model) View() string {
 			title += fmt.Sprintf(" - Pod Details: %s", m.selectedPod)
 		} else if m.selectedConfigMap != "" {
 			title += fmt.Sprintf(" - ConfigMap Details: %s", m.selectedConfigMap)
+		} else if m.selectedObject != "" {
+			title += fmt.Sprintf(" - %s Details: %s", m.resourceType.Kind, m.selectedObject)
 		}
+	case ResourceView:
+		title += fmt.Sprintf(" - %s (Namespace: %s)", m.resourceType.Title, m.selectedNamespace)
+	case EventsView:
+		title += fmt.Sprintf(" - Events: %s", m.eventsTarget)
 	case LogsView:
 		title += fmt.Sprintf(" - Logs: %s (Container: %s)", m.selectedPod, m.selectedContainer)
+	case YAMLView:
+		if m.yamlObject != nil {
+			title += fmt.Sprintf(" - %s YAML: %s", m.yamlObject.GetKind(), m.yamlObject.GetName())
+		}
+	}
+
+	if t := m.activeTable(); t != nil && t.Filter() != "" {
+		title += fmt.Sprintf(" [filter: %s, %d/%d]", t.Filter(), len(t.Rows()), len(t.AllRows()))
 	}
 
 	// Show main content based on current view
@@ -983,12 +1501,28 @@ func (m Model) View() string {
 		content = m.namespaceTable.View()
 	case PodView:
 		content = m.podTable.View()
+		if m.split {
+			content = m.splitView()
+		}
 	case ConfigMapView:
 		content = m.configMapTable.View()
 	case DetailView:
 		content = m.detailView.View()
 	case LogsView:
 		content = m.logsView.View()
+	case YAMLView:
+		content = m.yamlView.View()
+	case ResourceView:
+		content = m.resourceTable.View()
+	case EventsView:
+		content = m.eventsView.View()
+	}
+
+	// The confirmation dialog replaces the content while open
+	if m.confirm != nil && m.confirm.inline {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, m.confirm.View())
+	} else if m.confirm != nil {
+		content = lipgloss.Place(m.width, lipgloss.Height(content), lipgloss.Center, lipgloss.Center, m.confirm.View())
 	}
 
 	// Status bar
@@ -1001,10 +1535,35 @@ func (m Model) View() string {
 		status = statusMessageStyle.Render(m.statusMessage)
 	}
 
+	if m.commandPrompt.active {
+		status = m.commandPrompt.View()
+		if m.completions != "" {
+			status += "  " + statusMessageStyle.Render(m.completions)
+		}
+	} else if m.filterPrompt.active {
+		status = m.filterPrompt.View()
+	} else if m.scalePrompt.active {
+		status = m.scalePrompt.View()
+	} else if m.currentView == LogsView || (m.split && m.currentView == PodView && m.splitPane == LogsView) {
+		if m.logSearch.active {
+			status = m.logSearch.View()
+		} else if m.exportPrompt.active {
+			status = m.exportPrompt.View() + statusMessageStyle.Render("  tab: loaded/full logs")
+		} else if logStatus := m.logStatus(); logStatus != "" {
+			status = statusMessageStyle.Render(logStatus)
+		}
+	}
+
 	// Help hint at the bottom
-	helpHint := "Press ? for help | q to quit | r to refresh"
+	helpHint := "Press ? for help | : to go to a resource | q to quit | r to refresh"
 	if m.currentView == NamespaceView {
-		helpHint = "Press ? for help | TAB to switch resource | q to quit | r to refresh"
+		helpHint = "Press ? for help | TAB to switch resource | : to go to a resource | q to quit | r to refresh"
+	} else if m.currentView == LogsView {
+		helpHint = "Press ? for help | / to search | n/N next/prev | e errors | w warnings | ctrl+s save | q to quit"
+	} else if m.currentView == PodView && m.split {
+		helpHint = "Press ? for help | ctrl+w to switch pane | enter/y details | l logs | | to close split | q to quit"
+	} else if m.currentView == YAMLView {
+		helpHint = "Press ? for help | e to edit in $EDITOR | r to reload | esc to go back | q to quit"
 	}
 
 	// Combine all parts
@@ -1015,26 +1574,50 @@ func (m Model) View() string {
 		content,
 		"\n",
 		status,
+		m.statusBar(),
 		helpHint,
 	)
 }
 
 func main() {
-	// Set up logging to a file
-	f, err := os.Create("tui.log")
+	cfg, err := loadConfig(os.Args[1:])
 	if err != nil {
-		fmt.Println("Could not create log file:", err)
-		os.Exit(1)
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Println("Invalid configuration:", err)
+		os.Exit(2)
 	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			log.Println("Error closing log file:", err)
+
+	palette, err := cfg.palette()
+	if err != nil {
+		fmt.Println("Invalid configuration:", err)
+		os.Exit(2)
+	}
+	keyMap, err := cfg.keyMap()
+	if err != nil {
+		fmt.Println("Invalid configuration:", err)
+		os.Exit(2)
+	}
+	applyTheme(palette)
+
+	// Set up logging to the configured file, if any
+	log.SetOutput(io.Discard)
+	if cfg.LogFile != "" {
+		f, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			fmt.Println("Could not open log file:", err)
+			os.Exit(1)
 		}
-	}()
-	log.SetOutput(f)
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.Println("Error closing log file:", err)
+			}
+		}()
+		log.SetOutput(f)
+	}
 
-	// Close database connection when exiting
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+	p := tea.NewProgram(initialModel(cfg, keyMap), tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
 		log.Println("Error running program:", err)
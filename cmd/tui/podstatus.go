@@ -0,0 +1,66 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jbetancur/dashboard/internal/pkg/problems"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// restartWarning is the restart count at which a pod is highlighted
+const restartWarning = 3
+
+// problemBadge is the short label of a problem rule in the pod table
+type problemBadge struct {
+	label    string
+	critical bool
+}
+
+// problemBadges label the pod rules of the problem detector
+var problemBadges = map[string]problemBadge{
+	"CrashLoopBackOff": {label: "CrashLoop", critical: true},
+	"ImagePullBackOff": {label: "ImagePull", critical: true},
+	"OOMKilled":        {label: "OOMKilled"},
+	"FailedProbe":      {label: "Unready"},
+}
+
+// podBadges returns the badges of the problems detected on a pod
+func podBadges(pod corev1.Pod) string {
+	var labels []string
+	for _, problem := range problems.PodProblems(pod, time.Now()) {
+		badge, ok := problemBadges[problem.Rule]
+		if ok && !slices.Contains(labels, badge.label) {
+			labels = append(labels, badge.label)
+		}
+	}
+	return strings.Join(labels, ",")
+}
+
+// podRowStyle colors a pod row by its problems, phase and restart count
+func podRowStyle(row table.Row, columns []table.Column) (lipgloss.Style, bool) {
+	critical, warning := false, false
+	for _, label := range strings.Split(cellByTitle(row, columns, "Problems"), ",") {
+		for _, badge := range problemBadges {
+			if badge.label == label {
+				critical = critical || badge.critical
+				warning = true
+			}
+		}
+	}
+
+	restarts, _ := strconv.Atoi(cellByTitle(row, columns, "Restarts"))
+	switch status := corev1.PodPhase(cellByTitle(row, columns, "Status")); {
+	case critical || status == corev1.PodFailed:
+		return lipgloss.NewStyle().Foreground(activeTheme.Error), true
+	case warning || status == corev1.PodPending || status == corev1.PodUnknown || restarts >= restartWarning:
+		return lipgloss.NewStyle().Foreground(activeTheme.Warning), true
+	case status == corev1.PodSucceeded:
+		return lipgloss.NewStyle().Foreground(activeTheme.Border), true
+	}
+	return lipgloss.Style{}, false
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var promptStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("205")).
+	Bold(true)
+
+// prompt is a single-line text input shown in place of the status bar
+type prompt struct {
+	label  string
+	value  string
+	active bool
+}
+
+// open shows the prompt with a label and initial value
+func (p *prompt) open(label, value string) {
+	p.label = label
+	p.value = value
+	p.active = true
+}
+
+// close hides the prompt
+func (p *prompt) close() {
+	p.active = false
+}
+
+// update applies a key to the prompt, reporting whether the value was
+// submitted with enter or the prompt was cancelled with esc. Either closes it.
+func (p *prompt) update(msg tea.KeyMsg) (submitted, cancelled bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		p.close()
+		return true, false
+	case tea.KeyEsc, tea.KeyCtrlC:
+		p.close()
+		return false, true
+	case tea.KeyBackspace:
+		if runes := []rune(p.value); len(runes) > 0 {
+			p.value = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		p.value = ""
+	case tea.KeySpace:
+		p.value += " "
+	case tea.KeyRunes:
+		p.value += string(msg.Runes)
+	}
+
+	return false, false
+}
+
+// View renders the prompt with a cursor
+func (p prompt) View() string {
+	return promptStyle.Render(p.label) + p.value + "█"
+}
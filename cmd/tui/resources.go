@@ -0,0 +1,474 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// resourceType is a namespaced kind browsable in the generic resource view,
+// read live from the cluster
+type resourceType struct {
+	Kind    string
+	Title   string
+	Aliases []string
+	Columns []table.Column
+	list    func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error)
+}
+
+// resourceTypes are the kinds reachable with :<alias>
+var resourceTypes = []resourceType{
+	{
+		Kind:    "Deployment",
+		Title:   "Deployments",
+		Aliases: []string{"deploy", "deployment", "deployments", "dp"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Ready", Width: 10},
+			{Title: "Up-to-date", Width: 10},
+			{Title: "Available", Width: 10},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, d := range list.Items {
+				rows = append(rows, table.Row{
+					d.Name,
+					fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, replicas(d.Spec.Replicas)),
+					fmt.Sprintf("%d", d.Status.UpdatedReplicas),
+					fmt.Sprintf("%d", d.Status.AvailableReplicas),
+					formatAge(d.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+	{
+		Kind:    "StatefulSet",
+		Title:   "StatefulSets",
+		Aliases: []string{"sts", "statefulset", "statefulsets"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Ready", Width: 10},
+			{Title: "Service", Width: 20},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, s := range list.Items {
+				rows = append(rows, table.Row{
+					s.Name,
+					fmt.Sprintf("%d/%d", s.Status.ReadyReplicas, replicas(s.Spec.Replicas)),
+					s.Spec.ServiceName,
+					formatAge(s.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+	{
+		Kind:    "DaemonSet",
+		Title:   "DaemonSets",
+		Aliases: []string{"ds", "daemonset", "daemonsets"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Desired", Width: 10},
+			{Title: "Current", Width: 10},
+			{Title: "Ready", Width: 10},
+			{Title: "Available", Width: 10},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, d := range list.Items {
+				rows = append(rows, table.Row{
+					d.Name,
+					fmt.Sprintf("%d", d.Status.DesiredNumberScheduled),
+					fmt.Sprintf("%d", d.Status.CurrentNumberScheduled),
+					fmt.Sprintf("%d", d.Status.NumberReady),
+					fmt.Sprintf("%d", d.Status.NumberAvailable),
+					formatAge(d.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+	{
+		Kind:    "ReplicaSet",
+		Title:   "ReplicaSets",
+		Aliases: []string{"rs", "replicaset", "replicasets"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 40},
+			{Title: "Desired", Width: 10},
+			{Title: "Current", Width: 10},
+			{Title: "Ready", Width: 10},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, r := range list.Items {
+				rows = append(rows, table.Row{
+					r.Name,
+					fmt.Sprintf("%d", replicas(r.Spec.Replicas)),
+					fmt.Sprintf("%d", r.Status.Replicas),
+					fmt.Sprintf("%d", r.Status.ReadyReplicas),
+					formatAge(r.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+	{
+		Kind:    "Job",
+		Title:   "Jobs",
+		Aliases: []string{"job", "jobs"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Completions", Width: 12},
+			{Title: "Duration", Width: 10},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, j := range list.Items {
+				rows = append(rows, table.Row{
+					j.Name,
+					fmt.Sprintf("%d/%d", j.Status.Succeeded, replicas(j.Spec.Completions)),
+					jobDuration(j),
+					formatAge(j.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+	{
+		Kind:    "CronJob",
+		Title:   "CronJobs",
+		Aliases: []string{"cj", "cronjob", "cronjobs"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Schedule", Width: 15},
+			{Title: "Suspend", Width: 8},
+			{Title: "Active", Width: 8},
+			{Title: "Last Schedule", Width: 14},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, c := range list.Items {
+				lastSchedule := "<none>"
+				if c.Status.LastScheduleTime != nil {
+					lastSchedule = formatAge(*c.Status.LastScheduleTime)
+				}
+
+				rows = append(rows, table.Row{
+					c.Name,
+					c.Spec.Schedule,
+					fmt.Sprintf("%t", c.Spec.Suspend != nil && *c.Spec.Suspend),
+					fmt.Sprintf("%d", len(c.Status.Active)),
+					lastSchedule,
+					formatAge(c.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+	{
+		Kind:    "Service",
+		Title:   "Services",
+		Aliases: []string{"svc", "service", "services"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Type", Width: 12},
+			{Title: "Cluster IP", Width: 16},
+			{Title: "Ports", Width: 24},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, s := range list.Items {
+				rows = append(rows, table.Row{
+					s.Name,
+					string(s.Spec.Type),
+					s.Spec.ClusterIP,
+					servicePorts(s.Spec.Ports),
+					formatAge(s.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+	{
+		Kind:    "PersistentVolumeClaim",
+		Title:   "PersistentVolumeClaims",
+		Aliases: []string{"pvc", "persistentvolumeclaim", "persistentvolumeclaims"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Status", Width: 10},
+			{Title: "Volume", Width: 30},
+			{Title: "Capacity", Width: 10},
+			{Title: "Storage Class", Width: 15},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, p := range list.Items {
+				storageClass := ""
+				if p.Spec.StorageClassName != nil {
+					storageClass = *p.Spec.StorageClassName
+				}
+
+				rows = append(rows, table.Row{
+					p.Name,
+					string(p.Status.Phase),
+					p.Spec.VolumeName,
+					p.Status.Capacity.Storage().String(),
+					storageClass,
+					formatAge(p.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+	{
+		Kind:    "PodDisruptionBudget",
+		Title:   "PodDisruptionBudgets",
+		Aliases: []string{"pdb", "poddisruptionbudget", "poddisruptionbudgets"},
+		Columns: []table.Column{
+			{Title: "Name", Width: 30},
+			{Title: "Min Available", Width: 14},
+			{Title: "Max Unavailable", Width: 16},
+			{Title: "Allowed", Width: 10},
+			{Title: "Age", Width: 10},
+		},
+		list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]table.Row, error) {
+			list, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]table.Row, 0, len(list.Items))
+			for _, p := range list.Items {
+				rows = append(rows, table.Row{
+					p.Name,
+					pdbBound(p.Spec.MinAvailable),
+					pdbBound(p.Spec.MaxUnavailable),
+					fmt.Sprintf("%d", p.Status.DisruptionsAllowed),
+					formatAge(p.CreationTimestamp),
+				})
+			}
+			return rows, nil
+		},
+	},
+}
+
+// lookupResourceType finds a resource type by alias, kind or title
+func lookupResourceType(name string) (*resourceType, bool) {
+	name = strings.ToLower(name)
+	for i := range resourceTypes {
+		rt := &resourceTypes[i]
+		if strings.ToLower(rt.Kind) == name {
+			return rt, true
+		}
+		for _, alias := range rt.Aliases {
+			if alias == name {
+				return rt, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// resourcesLoadedMsg carries the rows of the generic resource view
+type resourcesLoadedMsg struct {
+	kind string
+	rows []table.Row
+}
+
+// loadResources lists a resource type from the cluster
+func loadResources(clientManager *cluster.ClientManager, clusterID, namespace string, rt *resourceType) tea.Cmd {
+	return func() tea.Msg {
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		rows, err := rt.list(ctx, client.Client, namespace)
+		if err != nil {
+			return errorMsg{err: describeActionError("list", strings.ToLower(rt.Title), namespace, err)}
+		}
+
+		return resourcesLoadedMsg{kind: rt.Kind, rows: rows}
+	}
+}
+
+// replicas dereferences an optional count, which defaults to 1 in the API
+func replicas(count *int32) int32 {
+	if count == nil {
+		return 1
+	}
+	return *count
+}
+
+// jobDuration returns how long a job ran, or has been running
+func jobDuration(job batchv1.Job) string {
+	if job.Status.StartTime == nil {
+		return "<pending>"
+	}
+
+	end := time.Now()
+	if job.Status.CompletionTime != nil {
+		end = job.Status.CompletionTime.Time
+	}
+	return end.Sub(job.Status.StartTime.Time).Round(time.Second).String()
+}
+
+// servicePorts formats service ports like kubectl
+func servicePorts(ports []corev1.ServicePort) string {
+	parts := make([]string, 0, len(ports))
+	for _, port := range ports {
+		if port.NodePort != 0 {
+			parts = append(parts, fmt.Sprintf("%d:%d/%s", port.Port, port.NodePort, port.Protocol))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// pdbBound formats an optional PDB bound
+func pdbBound(value *intstr.IntOrString) string {
+	if value == nil {
+		return "N/A"
+	}
+	return value.String()
+}
+
+// commandNamespace returns the namespace a resource command applies to: the
+// selected namespace, or the highlighted one in the namespace table
+func (m *Model) commandNamespace() string {
+	if m.selectedNamespace != "" {
+		return m.selectedNamespace
+	}
+	if m.currentView == NamespaceView && len(m.namespaceTable.Rows()) > 0 {
+		return m.namespaceTable.SelectedRow()[0]
+	}
+	return ""
+}
+
+// switchResource handles :<resource>, jumping to the table of a resource type
+func (m *Model) switchResource(name string) tea.Cmd {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil
+	}
+
+	if m.selectedCluster == "" {
+		m.errorMessage = "Select a cluster first"
+		return nil
+	}
+
+	if name == "ns" || name == "namespace" || name == "namespaces" {
+		m.currentView = NamespaceView
+		m.selectedNamespace = ""
+		m.loading = true
+		m.statusMessage = "Loading namespaces..."
+		return loadNamespaces(m.dbClient, m.selectedCluster)
+	}
+
+	namespace := m.commandNamespace()
+	if namespace == "" {
+		m.errorMessage = "Select a namespace first"
+		return nil
+	}
+
+	m.selectedNamespace = namespace
+	m.setAllNamespaces(false)
+	m.selectedPod = ""
+	m.selectedConfigMap = ""
+	m.selectedObject = ""
+
+	switch name {
+	case "po", "pod", "pods":
+		m.selectedResource = "pods"
+		m.currentView = PodView
+		m.loading = true
+		m.statusMessage = "Loading pods..."
+		return loadPods(m.dbClient, m.selectedCluster, namespace)
+	case "cm", "configmap", "configmaps":
+		m.selectedResource = "configmaps"
+		m.currentView = ConfigMapView
+		m.loading = true
+		m.statusMessage = "Loading ConfigMaps..."
+		return loadConfigMaps(m.dbClient, m.selectedCluster, namespace)
+	}
+
+	rt, ok := lookupResourceType(name)
+	if !ok {
+		m.errorMessage = fmt.Sprintf("Unknown resource %q", name)
+		return nil
+	}
+
+	// Clear rows before swapping columns so no row outgrows the new columns
+	m.resourceTable.SetRows(nil)
+	m.resourceTable.SetColumns(rt.Columns)
+	m.resourceTable.SetCursor(0)
+	m.resourceType = rt
+	m.currentView = ResourceView
+	m.loading = true
+	m.statusMessage = fmt.Sprintf("Loading %s...", rt.Title)
+	return loadResources(m.clientManager, m.selectedCluster, namespace, rt)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jbetancur/dashboard/internal/pkg/apiclient"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/deployments"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// workloadUpdatedMsg reports a completed scale or restart
+type workloadUpdatedMsg struct {
+	status string
+}
+
+// scaleDeployment sets the replica count of a deployment, through the REST API in API mode
+func scaleDeployment(api *apiclient.Client, clientManager *cluster.ClientManager, clusterID, namespace, name string, count int32) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		status := fmt.Sprintf("Scaled deployment %s to %d replicas", name, count)
+		if api != nil {
+			if err := api.Scale(ctx, clusterID, namespace, name, count); err != nil {
+				return errorMsg{err: describeActionError("scale", "deployment", name, err)}
+			}
+			return workloadUpdatedMsg{status: status}
+		}
+
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		deploymentClient := client.Client.AppsV1().Deployments(namespace)
+		scale, err := deploymentClient.GetScale(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errorMsg{err: describeActionError("scale", "deployment", name, err)}
+		}
+
+		scale.Spec.Replicas = count
+		if _, err := deploymentClient.UpdateScale(ctx, name, scale, metav1.UpdateOptions{}); err != nil {
+			return errorMsg{err: describeActionError("scale", "deployment", name, err)}
+		}
+
+		return workloadUpdatedMsg{status: status}
+	}
+}
+
+// restartDeployment rolls out new pods for a deployment like kubectl rollout
+// restart, through the REST API in API mode
+func restartDeployment(api *apiclient.Client, clientManager *cluster.ClientManager, clusterID, namespace, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		status := fmt.Sprintf("Restarted deployment %s", name)
+		if api != nil {
+			if err := api.RestartDeployment(ctx, clusterID, namespace, name); err != nil {
+				return errorMsg{err: describeActionError("restart", "deployment", name, err)}
+			}
+			return workloadUpdatedMsg{status: status}
+		}
+
+		client, err := clusterConnection(clientManager, clusterID)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+			deployments.RestartedAtAnnotation, time.Now().Format(time.RFC3339))
+		if _, err := client.Client.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+			return errorMsg{err: describeActionError("restart", "deployment", name, err)}
+		}
+
+		return workloadUpdatedMsg{status: status}
+	}
+}
+
+// selectedDeployment returns the highlighted row of the deployments view
+func (m *Model) selectedDeployment() ([]string, bool) {
+	if m.currentView != ResourceView || m.resourceType == nil || m.resourceType.Kind != "Deployment" {
+		return nil, false
+	}
+	if len(m.resourceTable.Rows()) == 0 {
+		return nil, false
+	}
+	return m.resourceTable.SelectedRow(), true
+}
+
+// openScalePrompt asks for the new replica count of the highlighted deployment
+func (m *Model) openScalePrompt() {
+	row, ok := m.selectedDeployment()
+	if !ok {
+		return
+	}
+
+	// The ready column is "ready/desired"
+	current := row[1]
+	if i := strings.Index(current, "/"); i >= 0 {
+		current = current[i+1:]
+	}
+
+	m.scaleTarget = row[0]
+	m.scalePrompt.open(fmt.Sprintf("Scale %s to: ", m.scaleTarget), current)
+}
+
+// confirmScale validates the submitted replica count and asks for confirmation
+func (m *Model) confirmScale() {
+	count, err := strconv.ParseInt(strings.TrimSpace(m.scalePrompt.value), 10, 32)
+	if err != nil || count < 0 {
+		m.errorMessage = fmt.Sprintf("Invalid replica count %q", m.scalePrompt.value)
+		return
+	}
+
+	m.confirm = &confirmation{
+		message:  fmt.Sprintf("Scale deployment %s/%s to %d replicas?", m.selectedNamespace, m.scaleTarget, count),
+		action:   scaleDeployment(m.api, m.clientManager, m.selectedCluster, m.selectedNamespace, m.scaleTarget, int32(count)),
+		progress: "Scaling deployment...",
+	}
+}
+
+// confirmRestart asks for confirmation before restarting the highlighted deployment
+func (m *Model) confirmRestart() {
+	row, ok := m.selectedDeployment()
+	if !ok {
+		return
+	}
+
+	m.confirm = &confirmation{
+		message:  fmt.Sprintf("Restart all pods of deployment %s/%s?", m.selectedNamespace, row[0]),
+		action:   restartDeployment(m.api, m.clientManager, m.selectedCluster, m.selectedNamespace, row[0]),
+		progress: "Restarting deployment...",
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// splitTailInterval is how often the logs pane of the split view is reloaded
+const splitTailInterval = 5 * time.Second
+
+// splitTailMsg reloads the logs pane while it is shown
+type splitTailMsg struct {
+	seq int
+}
+
+// toggleSplit shows or hides the pane next to the pod table
+func (m *Model) toggleSplit() tea.Cmd {
+	m.split = !m.split
+	m.paneFocused = false
+	m.podTable.Focus()
+	m.layoutPanes()
+
+	if !m.split {
+		m.statusMessage = "Closed split view"
+		return nil
+	}
+
+	if m.splitPane != LogsView {
+		m.splitPane = DetailView
+	}
+	m.statusMessage = "Split view: ctrl+w to switch pane"
+	return m.loadPane()
+}
+
+// layoutPanes sizes the pod table for the split or full layout
+func (m *Model) layoutPanes() {
+	if !m.split {
+		m.podTable.SetWidth(m.width)
+		return
+	}
+	m.podTable.SetWidth(m.splitWidth())
+}
+
+// splitWidth returns the width of the table in the split view
+func (m Model) splitWidth() int {
+	return m.width / 2
+}
+
+// showPane switches the pane between details and logs and loads it
+func (m *Model) showPane(pane ViewType) tea.Cmd {
+	m.splitPane = pane
+	m.selectedPod = ""
+	return m.loadPane()
+}
+
+// loadPane loads the pane for the highlighted pod. The table stays usable
+// while it loads, so responses for pods no longer selected are dropped.
+func (m *Model) loadPane() tea.Cmd {
+	name, namespace, ok := m.selectedPodRow()
+	if !ok || (name == m.selectedPod && namespace == m.selectedPodNamespace) {
+		return nil
+	}
+	m.selectPod()
+	m.errorMessage = ""
+
+	if m.splitPane == LogsView {
+		m.logQuery = ""
+		m.logMatch = 0
+		m.splitSeq++
+		return tea.Batch(
+			getPodContainers(m.dbClient, m.clientManager, m.selectedCluster, m.selectedPodNamespace, m.selectedPod),
+			m.tailPane(),
+		)
+	}
+	return loadDetails(m.api, m.clientManager, m.selectedCluster, "Pod", m.selectedPodNamespace, m.selectedPod)
+}
+
+// tailPane schedules the next reload of the logs pane
+func (m *Model) tailPane() tea.Cmd {
+	seq := m.splitSeq
+	return tea.Tick(splitTailInterval, func(time.Time) tea.Msg {
+		return splitTailMsg{seq: seq}
+	})
+}
+
+// handleSplitTail reloads the logs pane if it is still showing the same pod
+func (m *Model) handleSplitTail(msg splitTailMsg) tea.Cmd {
+	if msg.seq != m.splitSeq || !m.split || m.splitPane != LogsView || m.currentView != PodView || m.selectedContainer == "" {
+		return nil
+	}
+
+	return tea.Batch(
+		loadPodLogs(m.api, m.clientManager, m.selectedCluster, m.selectedPodNamespace, m.selectedPod, m.selectedContainer, m.logLines),
+		m.tailPane(),
+	)
+}
+
+// updateSplit handles the keys of the split pod view, reporting whether it used the key
+func (m *Model) updateSplit(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if key.Matches(msg, m.keys.FocusPane) {
+		m.paneFocused = !m.paneFocused
+		if m.paneFocused {
+			m.podTable.Blur()
+		} else {
+			m.podTable.Focus()
+		}
+		return true, nil
+	}
+
+	if !m.paneFocused {
+		switch {
+		case key.Matches(msg, m.keys.Enter), key.Matches(msg, m.keys.Describe):
+			return true, m.showPane(DetailView)
+		case key.Matches(msg, m.keys.Logs):
+			return true, m.showPane(LogsView)
+		case key.Matches(msg, m.keys.Split):
+			return true, m.toggleSplit()
+		}
+		return false, nil
+	}
+
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		if m.splitPane == LogsView && m.logQuery != "" {
+			m.logQuery = ""
+			m.refreshLogs()
+			return true, nil
+		}
+		m.paneFocused = false
+		m.podTable.Focus()
+	case key.Matches(msg, m.keys.Split):
+		cmd = m.toggleSplit()
+	case key.Matches(msg, m.keys.Refresh):
+		m.selectedPod = ""
+		cmd = m.loadPane()
+	case m.splitPane == LogsView && key.Matches(msg, m.keys.Search):
+		m.logSearch.open("/", m.logQuery)
+	case m.splitPane == LogsView && key.Matches(msg, m.keys.NextMatch):
+		m.moveLogMatch(1)
+	case m.splitPane == LogsView && key.Matches(msg, m.keys.PrevMatch):
+		m.moveLogMatch(-1)
+	case m.splitPane == LogsView && key.Matches(msg, m.keys.FilterErrors):
+		m.toggleLogLevel(logLevelError)
+	case m.splitPane == LogsView && key.Matches(msg, m.keys.FilterWarnings):
+		m.toggleLogLevel(logLevelWarn)
+	case m.splitPane == LogsView:
+		m.logsView, cmd = m.logsView.Update(msg)
+	default:
+		m.detailView, cmd = m.detailView.Update(msg)
+	}
+	return true, cmd
+}
+
+// splitView renders the pod table next to the pane, outlining the focused pane
+func (m Model) splitView() string {
+	pane := m.detailView
+	if m.splitPane == LogsView {
+		pane = m.logsView
+	}
+
+	// The pane shares its viewport with the full screen view, so only this copy is narrowed
+	width := m.splitWidth()
+	pane.Width = m.width - width - 4
+	pane.Style = viewportStyle()
+	if m.paneFocused {
+		pane.Style = pane.Style.BorderForeground(activeTheme.Selected)
+	}
+
+	// Columns wider than the table's half are cut off rather than pushing the pane aside
+	table := lipgloss.PlaceHorizontal(width, lipgloss.Left, lipgloss.NewStyle().MaxWidth(width).Render(m.podTable.View()))
+	return lipgloss.JoinHorizontal(lipgloss.Top, table, pane.View())
+}
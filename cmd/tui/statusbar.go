@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// healthInterval is how often the status bar checks the data source
+const healthInterval = 15 * time.Second
+
+var statusBarStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("241"))
+
+// healthCheckedMsg reports whether the data source answered a ping
+type healthCheckedMsg struct {
+	err error
+}
+
+// healthTickMsg schedules the next health check
+type healthTickMsg struct{}
+
+// checkHealth pings MongoDB, or the REST API in API mode
+func checkHealth(dbClient resourceStore) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		return healthCheckedMsg{err: dbClient.Ping(ctx)}
+	}
+}
+
+// scheduleHealthCheck waits for the next health check
+func scheduleHealthCheck() tea.Cmd {
+	return tea.Tick(healthInterval, func(time.Time) tea.Msg {
+		return healthTickMsg{}
+	})
+}
+
+// markRefreshed records that the visible data was just loaded or updated
+func (m *Model) markRefreshed() {
+	m.lastRefresh = time.Now()
+}
+
+// statusBar renders where the data comes from and how fresh it is: the
+// cluster and namespace, data source health, watch state and last refresh
+func (m Model) statusBar() string {
+	var parts []string
+
+	if m.selectedCluster != "" {
+		parts = append(parts, "cluster: "+m.selectedCluster)
+		if namespace := m.podsNamespace(); namespace != "" {
+			parts = append(parts, "ns: "+namespace)
+		}
+	}
+
+	source := "mongo"
+	if m.api != nil {
+		source = "api"
+	}
+	switch {
+	case m.dbClient == nil:
+		parts = append(parts, source+": connecting")
+	case m.healthErr != nil:
+		parts = append(parts, errorMessageStyle.Render(source+": down"))
+	case m.healthChecked:
+		parts = append(parts, source+": ok")
+	}
+
+	if m.selectedCluster != "" {
+		parts = append(parts, m.watchStatus())
+	}
+
+	if !m.lastRefresh.IsZero() {
+		parts = append(parts, "refreshed "+duration.HumanDuration(time.Since(m.lastRefresh))+" ago")
+	}
+
+	return statusBarStyle.Render(strings.Join(parts, " | "))
+}
+
+// watchStatus describes whether tables follow changes and how far behind they are
+func (m Model) watchStatus() string {
+	if len(m.watches) == 0 {
+		return errorMessageStyle.Render("stale (press r)")
+	}
+	if m.streamLag > 0 {
+		return "live (lag " + m.streamLag.Round(100*time.Millisecond).String() + ")"
+	}
+	return "live"
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// agePattern matches ages formatted by formatAge, e.g. 5m, 3d4h or 2y45d
+var agePattern = regexp.MustCompile(`^(\d+[ydhms])+$`)
+
+// ageUnits are the seconds per age unit
+var ageUnits = map[byte]int64{
+	'y': 365 * 24 * 60 * 60,
+	'd': 24 * 60 * 60,
+	'h': 60 * 60,
+	'm': 60,
+	's': 1,
+}
+
+// listTable is a table that keeps all of its rows and shows them filtered
+// and sorted
+type listTable struct {
+	table.Model
+	rows       []table.Row    // All rows, before filtering and sorting
+	columns    []table.Column // Columns without the sort indicator
+	filter     string
+	sortColumn int // Index of the sorted column, or -1
+	sortDesc   bool
+	// rowStyle colors unselected rows, e.g. by pod status; nil leaves them plain
+	rowStyle func(row table.Row, columns []table.Column) (lipgloss.Style, bool)
+}
+
+// newListTable wraps a table for filtering and sorting
+func newListTable(model table.Model) listTable {
+	return listTable{
+		Model:      model,
+		columns:    model.Columns(),
+		sortColumn: -1,
+	}
+}
+
+// SetRows replaces all rows, keeping the filter and sort order
+func (t *listTable) SetRows(rows []table.Row) {
+	t.rows = rows
+	t.refresh()
+}
+
+// SetColumns replaces the columns, resetting the sort order
+func (t *listTable) SetColumns(columns []table.Column) {
+	t.columns = columns
+	t.sortColumn = -1
+	t.sortDesc = false
+	t.Model.SetColumns(columns)
+}
+
+// AllRows returns all rows, including those hidden by the filter
+func (t *listTable) AllRows() []table.Row {
+	return t.rows
+}
+
+// SetFilter shows only the rows matching a query
+func (t *listTable) SetFilter(filter string) {
+	t.filter = filter
+	t.SetCursor(0)
+	t.refresh()
+}
+
+// Filter returns the active filter
+func (t *listTable) Filter() string {
+	return t.filter
+}
+
+// SortBy sorts by the column with a title, toggling the direction when it is
+// already sorted by it. It reports whether the table has the column.
+func (t *listTable) SortBy(title string) bool {
+	for i, column := range t.columns {
+		if column.Title != title {
+			continue
+		}
+
+		if t.sortColumn == i {
+			t.sortDesc = !t.sortDesc
+		} else {
+			t.sortColumn = i
+			t.sortDesc = false
+		}
+
+		t.refresh()
+		return true
+	}
+
+	return false
+}
+
+// refresh renders the filtered and sorted rows and the sort indicator
+func (t *listTable) refresh() {
+	visible := make([]table.Row, 0, len(t.rows))
+	for _, row := range t.rows {
+		if matchesRow(row, t.filter) {
+			visible = append(visible, row)
+		}
+	}
+
+	if t.sortColumn >= 0 {
+		column := t.sortColumn
+		sort.SliceStable(visible, func(i, j int) bool {
+			if column >= len(visible[i]) || column >= len(visible[j]) {
+				return false
+			}
+			if t.sortDesc {
+				return lessCell(visible[j][column], visible[i][column])
+			}
+			return lessCell(visible[i][column], visible[j][column])
+		})
+	}
+
+	// Rows must never have more cells than the columns being rendered
+	columns := make([]table.Column, len(t.columns))
+	copy(columns, t.columns)
+	if t.sortColumn >= 0 && t.sortColumn < len(columns) {
+		if t.sortDesc {
+			columns[t.sortColumn].Title += " ▼"
+		} else {
+			columns[t.sortColumn].Title += " ▲"
+		}
+	}
+	t.Model.SetRows(nil)
+	t.Model.SetColumns(columns)
+	t.Model.SetRows(visible)
+
+	if t.Cursor() >= len(visible) {
+		t.SetCursor(len(visible) - 1)
+	}
+}
+
+// View renders the table, coloring rows with rowStyle. The table renders
+// cells as plain text, so styled rows are found by rendering them the same way,
+// which keeps escape codes out of the cells it truncates, filters and sorts.
+func (t listTable) View() string {
+	view := t.Model.View()
+	if t.rowStyle == nil {
+		return view
+	}
+
+	// Only rows around the cursor can be on screen
+	rows, cursor, height := t.Rows(), t.Cursor(), t.Height()
+	styled := make(map[string]lipgloss.Style)
+	for i := max(cursor-height, 0); i < min(cursor+height+1, len(rows)); i++ {
+		if i == cursor {
+			continue
+		}
+		if style, ok := t.rowStyle(rows[i], t.columns); ok {
+			styled[t.renderRow(rows[i])] = style
+		}
+	}
+	if len(styled) == 0 {
+		return view
+	}
+
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if style, ok := styled[line]; ok {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderRow renders a row like the unstyled table does
+func (t listTable) renderRow(row table.Row) string {
+	columns := t.Model.Columns()
+	cells := make([]string, 0, len(columns))
+	for i, value := range row {
+		if i >= len(columns) || columns[i].Width <= 0 {
+			continue
+		}
+		style := lipgloss.NewStyle().Width(columns[i].Width).MaxWidth(columns[i].Width).Inline(true)
+		cells = append(cells, style.Render(runewidth.Truncate(value, columns[i].Width, "…")))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+}
+
+// cellByTitle returns the cell of a row under the column with a title
+func cellByTitle(row table.Row, columns []table.Column, title string) string {
+	for i, column := range columns {
+		if column.Title == title && i < len(row) {
+			return strings.TrimSuffix(row[i], flashMarker)
+		}
+	}
+	return ""
+}
+
+// matchesRow fuzzy matches a filter against a row's name, where the filter's
+// characters must appear in order, or as a substring of any other cell
+func matchesRow(row table.Row, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	if len(row) == 0 {
+		return false
+	}
+
+	filter = strings.ToLower(filter)
+	if fuzzyMatch(strings.ToLower(row[0]), filter) {
+		return true
+	}
+
+	for _, cell := range row[1:] {
+		if strings.Contains(strings.ToLower(cell), filter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fuzzyMatch reports whether the pattern's characters appear in order in s
+func fuzzyMatch(s, pattern string) bool {
+	remaining := []rune(pattern)
+	for _, r := range s {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// lessCell orders cells as ages, then numbers (including ready counts like
+// 1/2), then text
+func lessCell(a, b string) bool {
+	a = strings.TrimSuffix(a, flashMarker)
+	b = strings.TrimSuffix(b, flashMarker)
+
+	if ageA, ok := parseAge(a); ok {
+		if ageB, ok := parseAge(b); ok {
+			return ageA < ageB
+		}
+	}
+
+	if numberA, ok := parseNumber(a); ok {
+		if numberB, ok := parseNumber(b); ok {
+			return numberA < numberB
+		}
+	}
+
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// parseAge converts an age like 3d4h to seconds
+func parseAge(value string) (int64, bool) {
+	if !agePattern.MatchString(value) {
+		return 0, false
+	}
+
+	var total, current int64
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if unicode.IsDigit(rune(c)) {
+			current = current*10 + int64(c-'0')
+			continue
+		}
+		total += current * ageUnits[c]
+		current = 0
+	}
+
+	return total, true
+}
+
+// parseNumber parses an integer, or the first part of a count like 1/2
+func parseNumber(value string) (int64, bool) {
+	value, _, _ = strings.Cut(value, "/")
+	number, err := strconv.ParseInt(value, 10, 64)
+	return number, err == nil
+}
+
+// activeTable returns the table shown in the current view, if any
+func (m *Model) activeTable() *listTable {
+	switch m.currentView {
+	case ClusterView:
+		return &m.clusterTable
+	case NamespaceView:
+		return &m.namespaceTable
+	case PodView:
+		return &m.podTable
+	case ConfigMapView:
+		return &m.configMapTable
+	case ResourceView:
+		return &m.resourceTable
+	default:
+		return nil
+	}
+}
+
+// sortActiveTable sorts the current table by a column
+func (m *Model) sortActiveTable(title string) {
+	t := m.activeTable()
+	if t == nil {
+		return
+	}
+
+	if !t.SortBy(title) {
+		m.statusMessage = fmt.Sprintf("No %s column to sort by", title)
+		return
+	}
+
+	direction := "ascending"
+	if t.sortDesc {
+		direction = "descending"
+	}
+	m.statusMessage = fmt.Sprintf("Sorted by %s, %s", strings.ToLower(title), direction)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is the palette of the TUI's shared styles. Colors are hex codes or
+// ANSI color numbers.
+type theme struct {
+	Title     lipgloss.Color `yaml:"title"` // Title bar background
+	TitleText lipgloss.Color `yaml:"titleText"`
+	Status    lipgloss.Color `yaml:"status"`
+	Error     lipgloss.Color `yaml:"error"`    // Errors and pods with critical problems
+	Warning   lipgloss.Color `yaml:"warning"`  // Pods that are pending, restarting or have warnings
+	Selected  lipgloss.Color `yaml:"selected"` // Selected table row and focused pane
+	Border    lipgloss.Color `yaml:"border"`   // Viewport borders and section headers
+	Prompt    lipgloss.Color `yaml:"prompt"`
+}
+
+// defaultTheme is the dark palette the TUI started with
+var defaultTheme = theme{
+	Title:     "#25A065",
+	TitleText: "#FFFDF5",
+	Status:    "#04B575",
+	Error:     "#FF0000",
+	Warning:   "#FFAF00",
+	Selected:  "170",
+	Border:    "62",
+	Prompt:    "205",
+}
+
+// themes are the palettes selectable with the theme setting
+var themes = map[string]theme{
+	"default": defaultTheme,
+	"dark":    defaultTheme,
+	"light": {
+		Title:     "#1D7A4C",
+		TitleText: "#FFFFFF",
+		Status:    "#0A7D4F",
+		Error:     "#C00000",
+		Warning:   "#B35C00",
+		Selected:  "#5A2D82",
+		Border:    "#4B5CC4",
+		Prompt:    "#B0306A",
+	},
+	"mono": {
+		Title:     "250",
+		TitleText: "0",
+		Status:    "252",
+		Error:     "255",
+		Warning:   "252",
+		Selected:  "255",
+		Border:    "245",
+		Prompt:    "255",
+	},
+}
+
+// activeTheme is the palette applied by applyTheme
+var activeTheme = defaultTheme
+
+// palette returns the configured theme. Custom themes are defined in the
+// config file and fall back to the default palette for colors they leave out.
+func (c Config) palette() (theme, error) {
+	if custom, ok := c.Themes[c.Theme]; ok {
+		t := defaultTheme
+		overlay := func(color *lipgloss.Color, value lipgloss.Color) {
+			if value != "" {
+				*color = value
+			}
+		}
+		overlay(&t.Title, custom.Title)
+		overlay(&t.TitleText, custom.TitleText)
+		overlay(&t.Status, custom.Status)
+		overlay(&t.Error, custom.Error)
+		overlay(&t.Warning, custom.Warning)
+		overlay(&t.Selected, custom.Selected)
+		overlay(&t.Border, custom.Border)
+		overlay(&t.Prompt, custom.Prompt)
+		return t, nil
+	}
+
+	if t, ok := themes[c.Theme]; ok {
+		return t, nil
+	}
+
+	names := make([]string, 0, len(themes)+len(c.Themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	for name := range c.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return theme{}, fmt.Errorf("unknown theme %q, expected one of %s", c.Theme, strings.Join(names, ", "))
+}
+
+// applyTheme restyles the shared styles with a palette
+func applyTheme(t theme) {
+	activeTheme = t
+
+	titleStyle = titleStyle.Foreground(t.TitleText).Background(t.Title)
+	statusMessageStyle = statusMessageStyle.Foreground(t.Status)
+	errorMessageStyle = errorMessageStyle.Foreground(t.Error)
+	selectedRowStyle = selectedRowStyle.Foreground(t.Selected)
+	eventHeaderStyle = eventHeaderStyle.Foreground(t.Border)
+	promptStyle = promptStyle.Foreground(t.Prompt)
+	statusBarStyle = statusBarStyle.Foreground(t.Border)
+}
+
+// viewportStyle returns the bordered style of the TUI's viewports
+func viewportStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(activeTheme.Border)
+}
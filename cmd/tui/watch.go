@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// flashMarker is appended to the last cell of a row that just changed
+	flashMarker = " ●"
+
+	// flashDuration is how long a changed row stays marked
+	flashDuration = 2 * time.Second
+)
+
+// watch is a running store watch feeding one table
+type watch struct {
+	id     int
+	cancel context.CancelFunc
+}
+
+// watchStartedMsg reports a watch that is ready to deliver changes
+type watchStartedMsg struct {
+	kind    string
+	id      int
+	changes <-chan store.Change
+}
+
+// watchFailedMsg reports a watch that could not be started
+type watchFailedMsg struct {
+	kind string
+	id   int
+	err  error
+}
+
+// resourceChangedMsg carries a change and, for upserts, the refreshed row
+type resourceChangedMsg struct {
+	id      int
+	change  store.Change
+	row     table.Row
+	changes <-chan store.Change
+}
+
+// watchEndedMsg reports a watch whose change stream closed
+type watchEndedMsg struct {
+	id int
+}
+
+// flashExpiredMsg clears the change marker of a row
+type flashExpiredMsg struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// startWatch replaces the watch feeding a kind's table so its rows follow
+// changes in the store without pressing refresh
+func (m *Model) startWatch(kind, namespace string) tea.Cmd {
+	if m.dbClient == nil {
+		return nil
+	}
+
+	if current, ok := m.watches[kind]; ok {
+		current.cancel()
+	}
+
+	m.watchSeq++
+	id := m.watchSeq
+	ctx, cancel := context.WithCancel(context.Background())
+	m.watches[kind] = &watch{id: id, cancel: cancel}
+
+	dbClient, clusterID, window := m.dbClient, m.selectedCluster, m.config.Watch.Window()
+	return func() tea.Msg {
+		changes, err := dbClient.Watch(ctx, clusterID, namespace, kind)
+		if err != nil {
+			return watchFailedMsg{kind: kind, id: id, err: err}
+		}
+		// Rollouts update many objects several times a second; merge them so
+		// each row is reloaded once per window
+		return watchStartedMsg{kind: kind, id: id, changes: store.Coalesce(ctx, changes, window)}
+	}
+}
+
+// stopWatches cancels all running watches
+func (m *Model) stopWatches() {
+	for kind, current := range m.watches {
+		current.cancel()
+		delete(m.watches, kind)
+	}
+}
+
+// waitForChange delivers the next change of a watch, loading the changed
+// object's row from the store
+func waitForChange(dbClient resourceStore, clusterID string, id int, changes <-chan store.Change) tea.Cmd {
+	return func() tea.Msg {
+		change, ok := <-changes
+		if !ok {
+			return watchEndedMsg{id: id}
+		}
+
+		msg := resourceChangedMsg{id: id, change: change, changes: changes}
+		if change.Op == store.ChangeDelete {
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		row, err := loadRow(ctx, dbClient, clusterID, change)
+		if err != nil {
+			// The object may have been deleted since; the next change will tell
+			return msg
+		}
+		msg.row = row
+
+		return msg
+	}
+}
+
+// loadRow loads the table row of a changed object
+func loadRow(ctx context.Context, dbClient resourceStore, clusterID string, change store.Change) (table.Row, error) {
+	switch change.Kind {
+	case "Namespace":
+		var ns corev1.Namespace
+		if err := dbClient.Get(ctx, clusterID, "", change.Kind, change.Name, &ns); err != nil {
+			return nil, err
+		}
+		return namespaceRow(ns), nil
+	case "Pod":
+		var pod corev1.Pod
+		if err := dbClient.Get(ctx, clusterID, change.Namespace, change.Kind, change.Name, &pod); err != nil {
+			return nil, err
+		}
+		return podRow(pod), nil
+	case "ConfigMap":
+		var cm corev1.ConfigMap
+		if err := dbClient.Get(ctx, clusterID, change.Namespace, change.Kind, change.Name, &cm); err != nil {
+			return nil, err
+		}
+		return configMapRow(cm), nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", change.Kind)
+	}
+}
+
+// watchedTable returns the table a kind's watch feeds
+func (m *Model) watchedTable(kind string) *listTable {
+	switch kind {
+	case "Namespace":
+		return &m.namespaceTable
+	case "Pod":
+		return &m.podTable
+	case "ConfigMap":
+		return &m.configMapTable
+	default:
+		return nil
+	}
+}
+
+// applyChange updates a table in place for a change, marking changed rows.
+// It returns a command clearing the marker once it has been visible long enough.
+func (m *Model) applyChange(change store.Change, row table.Row) tea.Cmd {
+	t := m.watchedTable(change.Kind)
+	if t == nil {
+		return nil
+	}
+
+	current := t.AllRows()
+	rows := make([]table.Row, 0, len(current)+1)
+	found := false
+	for _, existing := range current {
+		if !m.isChangedRow(change.Kind, change.Namespace, change.Name, existing) {
+			rows = append(rows, existing)
+			continue
+		}
+
+		found = true
+		if change.Op == store.ChangeUpsert && row != nil {
+			rows = append(rows, flashRow(row))
+		}
+	}
+
+	if !found && change.Op == store.ChangeUpsert && row != nil {
+		rows = append(rows, flashRow(row))
+	}
+
+	t.SetRows(rows)
+
+	if change.Op == store.ChangeDelete {
+		return nil
+	}
+
+	kind, namespace, name := change.Kind, change.Namespace, change.Name
+	return tea.Tick(flashDuration, func(time.Time) tea.Msg {
+		return flashExpiredMsg{kind: kind, namespace: namespace, name: name}
+	})
+}
+
+// flashRow marks a row as just changed
+func flashRow(row table.Row) table.Row {
+	marked := append(table.Row{}, row...)
+	marked[len(marked)-1] += flashMarker
+	return marked
+}
+
+// clearFlash removes the change marker from a row
+func (m *Model) clearFlash(kind, namespace, name string) {
+	t := m.watchedTable(kind)
+	if t == nil {
+		return
+	}
+
+	rows := t.AllRows()
+	for i, row := range rows {
+		if m.isChangedRow(kind, namespace, name, row) {
+			cleared := append(table.Row{}, row...)
+			cleared[len(cleared)-1] = strings.TrimSuffix(cleared[len(cleared)-1], flashMarker)
+			rows[i] = cleared
+		}
+	}
+	t.SetRows(rows)
+}
+
+// isChangedRow reports whether a row shows a changed object. Pod names are
+// only unique per namespace, so rows across all namespaces match both.
+func (m *Model) isChangedRow(kind, namespace, name string, row table.Row) bool {
+	if row[0] != name {
+		return false
+	}
+	if kind == "Pod" && m.allNamespaces {
+		return row[1] == namespace
+	}
+	return true
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jbetancur/dashboard/internal/pkg/apiclient"
+	"github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	yamlv3 "gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+)
+
+// yamlLoadedMsg carries a live object fetched for the YAML view
+type yamlLoadedMsg struct {
+	object *unstructured.Unstructured
+}
+
+// editorFinishedMsg carries the result of editing an object in $EDITOR
+type editorFinishedMsg struct {
+	original string
+	edited   string
+	err      error
+}
+
+// resourceAppliedMsg reports an edit applied to the cluster
+type resourceAppliedMsg struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// dynamicResource returns a dynamic client for a supported kind in a cluster
+func dynamicResource(clientManager *cluster.ClientManager, clusterID, kind string) (dynamic.NamespaceableResourceInterface, error) {
+	info, ok := assets.LookupKind(kind)
+	if !ok {
+		return nil, fmt.Errorf("unsupported kind %q", kind)
+	}
+
+	conn, err := clusterConnection(clientManager, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := dynamic.NewForConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	gvr := schema.GroupVersionResource{Group: info.Group, Version: info.Version, Resource: info.Resource}
+	return client.Resource(gvr), nil
+}
+
+// loadYAML fetches the live object for the YAML view, through the REST API in API mode
+func loadYAML(api *apiclient.Client, clientManager *cluster.ClientManager, clusterID, namespace, kind, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if api != nil {
+			obj, err := api.GetLive(ctx, clusterID, namespace, kind, name)
+			if err != nil {
+				return errorMsg{err: describeActionError("get", kind, name, err)}
+			}
+			return yamlLoadedMsg{object: obj}
+		}
+
+		resource, err := dynamicResource(clientManager, clusterID, kind)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		obj, err := resource.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errorMsg{err: describeActionError("get", kind, name, err)}
+		}
+
+		return yamlLoadedMsg{object: obj}
+	}
+}
+
+// toYAML renders an object as YAML without managed fields, and optionally
+// without status for editing
+func toYAML(obj *unstructured.Unstructured, withStatus bool) (string, error) {
+	content := obj.DeepCopy().Object
+	unstructured.RemoveNestedField(content, "metadata", "managedFields")
+	if !withStatus {
+		unstructured.RemoveNestedField(content, "status")
+	}
+
+	var builder strings.Builder
+	encoder := yamlv3.NewEncoder(&builder)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(content); err != nil {
+		return "", fmt.Errorf("failed to encode yaml: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
+// editorCommand returns the user's editor, preferring KUBE_EDITOR like kubectl
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("KUBE_EDITOR")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Editors are often configured with arguments, e.g. "code --wait"
+	fields := strings.Fields(editor)
+	return exec.Command(fields[0], append(fields[1:], path)...)
+}
+
+// editYAML suspends the TUI and opens the object in the user's editor
+func editYAML(obj *unstructured.Unstructured) tea.Cmd {
+	original, err := toYAML(obj, false)
+	if err != nil {
+		return func() tea.Msg { return errorMsg{err: err} }
+	}
+
+	file, err := os.CreateTemp("", fmt.Sprintf("%s-%s-*.yaml", strings.ToLower(obj.GetKind()), obj.GetName()))
+	if err != nil {
+		return func() tea.Msg { return errorMsg{err: fmt.Errorf("failed to create temp file: %w", err)} }
+	}
+	path := file.Name()
+
+	_, err = file.WriteString(original)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return errorMsg{err: fmt.Errorf("failed to write temp file: %w", err)} }
+	}
+
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		defer os.Remove(path)
+
+		if err != nil {
+			return editorFinishedMsg{err: fmt.Errorf("editor failed: %w", err)}
+		}
+
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return editorFinishedMsg{err: fmt.Errorf("failed to read edited file: %w", err)}
+		}
+
+		return editorFinishedMsg{original: original, edited: string(edited)}
+	})
+}
+
+// validateEdit parses edited YAML and makes sure it still refers to the same object
+func validateEdit(original *unstructured.Unstructured, edited string) (*unstructured.Unstructured, error) {
+	data, err := yaml.YAMLToJSON([]byte(edited))
+	if err != nil {
+		return nil, fmt.Errorf("invalid yaml: %w", err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("invalid object: %w", err)
+	}
+
+	switch {
+	case obj.GetAPIVersion() != original.GetAPIVersion():
+		return nil, fmt.Errorf("apiVersion cannot be changed")
+	case obj.GetKind() != original.GetKind():
+		return nil, fmt.Errorf("kind cannot be changed")
+	case obj.GetName() != original.GetName():
+		return nil, fmt.Errorf("metadata.name cannot be changed")
+	case obj.GetNamespace() != original.GetNamespace():
+		return nil, fmt.Errorf("metadata.namespace cannot be changed")
+	}
+
+	return obj, nil
+}
+
+// applyEdit updates the edited object in the cluster, through the REST API in
+// API mode. The resourceVersion from the edit makes the update fail if the
+// object changed in the meantime.
+func applyEdit(api *apiclient.Client, clientManager *cluster.ClientManager, clusterID string, obj *unstructured.Unstructured) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		applied := resourceAppliedMsg{kind: obj.GetKind(), namespace: obj.GetNamespace(), name: obj.GetName()}
+		if api != nil {
+			if err := api.Update(ctx, clusterID, obj); err != nil {
+				return errorMsg{err: describeActionError("update", obj.GetKind(), obj.GetName(), err)}
+			}
+			return applied
+		}
+
+		resource, err := dynamicResource(clientManager, clusterID, obj.GetKind())
+		if err != nil {
+			return errorMsg{err: err}
+		}
+
+		if _, err := resource.Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+			return errorMsg{err: describeActionError("update", obj.GetKind(), obj.GetName(), err)}
+		}
+
+		return applied
+	}
+}
+
+// diffLines returns a line diff of two texts with added and removed lines
+// colored, based on the longest common subsequence
+func diffLines(before, after string) string {
+	a := strings.Split(strings.TrimRight(before, "\n"), "\n")
+	b := strings.Split(strings.TrimRight(after, "\n"), "\n")
+
+	// lcs[i][j] is the common subsequence length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			lines = append(lines, diffAddedStyle.Render("+ "+b[j]))
+			j++
+		default:
+			lines = append(lines, diffRemovedStyle.Render("- "+a[i]))
+			i++
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// viewYAML opens the YAML view for the resource selected in the current table
+func (m *Model) viewYAML() tea.Cmd {
+	if m.currentView != PodView && m.currentView != ConfigMapView && m.currentView != ResourceView {
+		return nil
+	}
+
+	kind, namespace, name, ok := m.highlighted()
+	if !ok {
+		return nil
+	}
+
+	m.yamlReturnView = m.currentView
+	m.loading = true
+	m.statusMessage = fmt.Sprintf("Loading %s YAML...", kind)
+	return loadYAML(m.api, m.clientManager, m.selectedCluster, namespace, kind, name)
+}
+
+// handleEditorFinished validates an edit and asks for confirmation with a diff
+func (m *Model) handleEditorFinished(msg editorFinishedMsg) {
+	if msg.err != nil {
+		m.errorMessage = msg.err.Error()
+		return
+	}
+
+	if msg.edited == msg.original {
+		m.statusMessage = "Edit cancelled, no changes made"
+		return
+	}
+
+	obj, err := validateEdit(m.yamlObject, msg.edited)
+	if err != nil {
+		m.errorMessage = err.Error()
+		return
+	}
+
+	m.yamlView.SetContent(diffLines(msg.original, msg.edited))
+	m.yamlView.GotoTop()
+	m.confirm = &confirmation{
+		message:  fmt.Sprintf("Apply these changes to %s %s?", obj.GetKind(), obj.GetName()),
+		action:   applyEdit(m.api, m.clientManager, m.selectedCluster, obj),
+		progress: "Applying changes...",
+		inline:   true,
+	}
+}
+
+// resetYAMLView restores the YAML after a cancelled edit
+func (m *Model) resetYAMLView() {
+	if m.yamlObject == nil {
+		return
+	}
+
+	content, err := toYAML(m.yamlObject, true)
+	if err != nil {
+		m.errorMessage = err.Error()
+		return
+	}
+	m.yamlView.SetContent(content)
+}
@@ -0,0 +1,172 @@
+package alerting
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/problems"
+)
+
+// queueSize bounds the number of events waiting for delivery
+const queueSize = 256
+
+// Dispatcher routes problem events to notification sinks, skipping silenced
+// problems and duplicate notifications
+type Dispatcher struct {
+	store  Repository
+	config Config
+	logger *slog.Logger
+	queue  chan problems.Event
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// NewDispatcher creates a new dispatcher, filling in defaults for unset durations
+func NewDispatcher(store Repository, config Config, logger *slog.Logger) *Dispatcher {
+	if config.RepeatInterval <= 0 {
+		config.RepeatInterval = 4 * time.Hour
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.SMTP.Port == 0 {
+		config.SMTP.Port = 587
+	}
+
+	return &Dispatcher{
+		store:  store,
+		config: config,
+		logger: logger,
+		queue:  make(chan problems.Event, queueSize),
+		sent:   make(map[string]time.Time),
+	}
+}
+
+// Start delivers queued events until the context is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case event := <-d.queue:
+				d.dispatch(ctx, event)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Notify queues a problem event for delivery without blocking
+func (d *Dispatcher) Notify(event problems.Event) {
+	select {
+	case d.queue <- event:
+	default:
+		d.logger.Warn("Alert queue full, dropping problem event", "problem", event.Problem.ID)
+	}
+}
+
+// Test sends a sample notification to a sink
+func (d *Dispatcher) Test(ctx context.Context, sink Sink) error {
+	now := time.Now()
+	return d.send(ctx, sink, problems.Event{
+		Type:      problems.EventAppeared,
+		Timestamp: now,
+		Problem: problems.Problem{
+			ID:       "test",
+			Rule:     "Test",
+			Severity: problems.SeverityWarning,
+			Kind:     "Pod",
+			Name:     "test",
+			Message:  "test notification from the dashboard",
+			Since:    now,
+			LastSeen: now,
+		},
+	})
+}
+
+// dispatch sends an event to the sinks of every matching route
+func (d *Dispatcher) dispatch(ctx context.Context, event problems.Event) {
+	var silences []Silence
+	if err := d.store.ListAlertSilences(ctx, &silences); err != nil {
+		d.logger.Error("Failed to load alert silences", "error", err)
+		return
+	}
+
+	for _, silence := range silences {
+		if silence.Mutes(event.Problem, event.Timestamp) {
+			d.logger.Debug("Problem silenced", "problem", event.Problem.ID, "silence", silence.ID)
+			return
+		}
+	}
+
+	var routes []Route
+	if err := d.store.ListAlertRoutes(ctx, &routes); err != nil {
+		d.logger.Error("Failed to load alert routes", "error", err)
+		return
+	}
+
+	var sinks []Sink
+	if err := d.store.ListAlertSinks(ctx, &sinks); err != nil {
+		d.logger.Error("Failed to load alert sinks", "error", err)
+		return
+	}
+
+	sinksByID := make(map[string]Sink, len(sinks))
+	for _, sink := range sinks {
+		sinksByID[sink.ID] = sink
+	}
+
+	// A sink reached by several routes is notified once
+	notified := make(map[string]bool)
+
+	for _, route := range routes {
+		if !route.Matches(event.Problem) {
+			continue
+		}
+		if event.Type == problems.EventResolved && !route.SendResolved {
+			continue
+		}
+
+		for _, sinkID := range route.Sinks {
+			sink, ok := sinksByID[sinkID]
+			if !ok || notified[sinkID] {
+				continue
+			}
+			notified[sinkID] = true
+
+			if d.duplicate(sinkID, event) {
+				continue
+			}
+
+			if err := d.send(ctx, sink, event); err != nil {
+				d.logger.Error("Failed to send alert", "sink", sink.Name, "problem", event.Problem.ID, "error", err)
+			}
+		}
+	}
+}
+
+// duplicate reports whether the same notification already went to a sink
+// within the repeat interval, and records it otherwise
+func (d *Dispatcher) duplicate(sinkID string, event problems.Event) bool {
+	key := sinkID + "|" + event.Type + "|" + event.Problem.ClusterID + "|" + event.Problem.ID
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for k, at := range d.sent {
+		if now.Sub(at) >= d.config.RepeatInterval {
+			delete(d.sent, k)
+		}
+	}
+
+	if _, ok := d.sent[key]; ok {
+		return true
+	}
+
+	d.sent[key] = now
+	return false
+}
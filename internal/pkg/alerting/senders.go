@@ -0,0 +1,133 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/jbetancur/dashboard/internal/pkg/problems"
+)
+
+// send delivers an event to a single sink
+func (d *Dispatcher) send(ctx context.Context, sink Sink, event problems.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	switch sink.Type {
+	case SinkSlack:
+		return d.post(ctx, sink, map[string]string{"text": summary(event)})
+	case SinkWebhook:
+		return d.post(ctx, sink, event)
+	case SinkEmail:
+		return d.mail(sink, summary(event), details(event))
+	default:
+		return fmt.Errorf("unsupported sink type %q", sink.Type)
+	}
+}
+
+// Deliver sends a message that is not a problem event, such as a scheduled
+// report, to a sink. Chat and email sinks get the subject and text, webhooks
+// get payload as JSON.
+func (d *Dispatcher) Deliver(ctx context.Context, sink Sink, subject, text string, payload interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	switch sink.Type {
+	case SinkSlack:
+		return d.post(ctx, sink, map[string]string{"text": "*" + subject + "*\n" + text})
+	case SinkWebhook:
+		return d.post(ctx, sink, payload)
+	case SinkEmail:
+		return d.mail(sink, subject, text)
+	default:
+		return fmt.Errorf("unsupported sink type %q", sink.Type)
+	}
+}
+
+// post sends a JSON payload to a sink URL
+func (d *Dispatcher) post(ctx context.Context, sink Sink, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range sink.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned %s", resp.Status)
+	}
+
+	return nil
+}
+
+// mail sends a plain text message to the recipients of an email sink
+func (d *Dispatcher) mail(sink Sink, subject, body string) error {
+	smtpConfig := d.config.SMTP
+	if smtpConfig.Host == "" {
+		return fmt.Errorf("smtp is not configured")
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", smtpConfig.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(sink.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", subject)
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if smtpConfig.Username != "" {
+		auth = smtp.PlainAuth("", smtpConfig.Username, smtpConfig.Password, smtpConfig.Host)
+	}
+
+	addr := net.JoinHostPort(smtpConfig.Host, strconv.Itoa(smtpConfig.Port))
+	if err := smtp.SendMail(addr, auth, smtpConfig.From, sink.To, []byte(message.String())); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
+// details is the body of an email about an event
+func details(event problems.Event) string {
+	return fmt.Sprintf("Cluster: %s\nNamespace: %s\nResource: %s/%s\nRule: %s\nSeverity: %s\nSince: %s\n\n%s\n",
+		event.Problem.ClusterID, event.Problem.Namespace, event.Problem.Kind, event.Problem.Name,
+		event.Problem.Rule, event.Problem.Severity, event.Problem.Since.Format("2006-01-02 15:04:05 MST"),
+		event.Problem.Message)
+}
+
+// summary is a one-line description of an event
+func summary(event problems.Event) string {
+	state := "[" + strings.ToUpper(event.Problem.Severity) + "]"
+	if event.Type == problems.EventResolved {
+		state = "[RESOLVED]"
+	}
+
+	target := event.Problem.Kind + " " + event.Problem.Name
+	if event.Problem.Namespace != "" {
+		target = event.Problem.Kind + " " + event.Problem.Namespace + "/" + event.Problem.Name
+	}
+
+	return fmt.Sprintf("%s %s: %s on %s in %s", state, event.Problem.Rule, event.Problem.Message, target, event.Problem.ClusterID)
+}
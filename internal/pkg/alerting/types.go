@@ -0,0 +1,114 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/problems"
+)
+
+// Sink types
+const (
+	SinkSlack   = "slack"
+	SinkWebhook = "webhook"
+	SinkEmail   = "email"
+)
+
+// Config holds delivery settings shared by all sinks
+type Config struct {
+	// RepeatInterval suppresses identical notifications sent within the window
+	RepeatInterval time.Duration `yaml:"repeatInterval"`
+	// Timeout bounds a single delivery
+	Timeout time.Duration `yaml:"timeout"`
+	SMTP    SMTPConfig    `yaml:"smtp"`
+}
+
+// SMTPConfig is the mail server used by email sinks
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// Sink is a notification destination
+type Sink struct {
+	ID      string            `json:"id" bson:"_id,omitempty"`
+	Name    string            `json:"name" bson:"name"`
+	Type    string            `json:"type" bson:"type"`
+	URL     string            `json:"url,omitempty" bson:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	To      []string          `json:"to,omitempty" bson:"to,omitempty"`
+}
+
+// Validate checks that a sink has what its type needs
+func (s *Sink) Validate() error {
+	switch s.Type {
+	case SinkSlack, SinkWebhook:
+		if s.URL == "" {
+			return fmt.Errorf("%s sink requires a url", s.Type)
+		}
+	case SinkEmail:
+		if len(s.To) == 0 {
+			return fmt.Errorf("email sink requires at least one recipient")
+		}
+	default:
+		return fmt.Errorf("unsupported sink type %q", s.Type)
+	}
+
+	return nil
+}
+
+// Route sends matching problems to a set of sinks. Empty match lists match everything.
+type Route struct {
+	ID         string   `json:"id" bson:"_id,omitempty"`
+	Name       string   `json:"name" bson:"name"`
+	Clusters   []string `json:"clusters,omitempty" bson:"clusters,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty" bson:"namespaces,omitempty"`
+	Severities []string `json:"severities,omitempty" bson:"severities,omitempty"`
+	Sinks      []string `json:"sinks" bson:"sinks"`
+	// SendResolved also notifies when a problem goes away
+	SendResolved bool `json:"sendResolved" bson:"send_resolved"`
+}
+
+// Matches reports whether a problem is routed by r
+func (r *Route) Matches(problem problems.Problem) bool {
+	return matches(r.Clusters, problem.ClusterID) &&
+		matches(r.Namespaces, problem.Namespace) &&
+		matches(r.Severities, problem.Severity)
+}
+
+// Silence mutes matching problems between StartsAt and EndsAt. Empty match lists match everything.
+type Silence struct {
+	ID         string    `json:"id" bson:"_id,omitempty"`
+	Clusters   []string  `json:"clusters,omitempty" bson:"clusters,omitempty"`
+	Namespaces []string  `json:"namespaces,omitempty" bson:"namespaces,omitempty"`
+	Rules      []string  `json:"rules,omitempty" bson:"rules,omitempty"`
+	StartsAt   time.Time `json:"startsAt" bson:"starts_at"`
+	EndsAt     time.Time `json:"endsAt" bson:"ends_at"`
+	Comment    string    `json:"comment,omitempty" bson:"comment,omitempty"`
+	CreatedBy  string    `json:"createdBy,omitempty" bson:"created_by,omitempty"`
+}
+
+// Mutes reports whether s silences a problem at a point in time
+func (s *Silence) Mutes(problem problems.Problem, at time.Time) bool {
+	return !at.Before(s.StartsAt) && at.Before(s.EndsAt) &&
+		matches(s.Clusters, problem.ClusterID) &&
+		matches(s.Namespaces, problem.Namespace) &&
+		matches(s.Rules, problem.Rule)
+}
+
+// Repository persists alerting configuration
+type Repository interface {
+	ListAlertSinks(ctx context.Context, results *[]Sink) error
+	ListAlertRoutes(ctx context.Context, results *[]Route) error
+	ListAlertSilences(ctx context.Context, results *[]Silence) error
+}
+
+// matches reports whether value is allowed by a match list
+func matches(allowed []string, value string) bool {
+	return len(allowed) == 0 || slices.Contains(allowed, value)
+}
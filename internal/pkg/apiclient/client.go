@@ -0,0 +1,579 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/deployments"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/restarts"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// AllNamespaces lists a kind across every namespace of a cluster
+	AllNamespaces = "all"
+
+	// logIdleTimeout ends a log read once the stream has been quiet this long
+	logIdleTimeout = time.Second
+)
+
+// Client reads clusters and resources through the REST API, so every call
+// is authorized with the user's token and checked against the server's RBAC
+type Client struct {
+	baseURL      *url.URL
+	tokenCommand string
+	client       *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// New creates a client for the REST API at apiURL. A token command
+// is run to get a token, e.g. from an OIDC login helper, and re-run when the
+// token is rejected.
+func New(apiURL, token, tokenCommand string) (*Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(apiURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL %q: %w", apiURL, err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid API URL %q: scheme must be http or https", apiURL)
+	}
+
+	c := &Client{
+		baseURL:      baseURL,
+		tokenCommand: tokenCommand,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		token:        token,
+	}
+
+	if c.token == "" && c.tokenCommand != "" {
+		if _, err := c.refreshToken(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// refreshToken runs the token command, returning whether it produced a new token
+func (c *Client) refreshToken(ctx context.Context) (bool, error) {
+	if c.tokenCommand == "" {
+		return false, nil
+	}
+
+	out, err := exec.CommandContext(ctx, "sh", "-c", c.tokenCommand).Output()
+	if err != nil {
+		return false, fmt.Errorf("token command failed: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous := c.token
+	c.token = strings.TrimSpace(string(out))
+	return c.token != previous, nil
+}
+
+func (c *Client) currentToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// endpoint returns the URL of an API path
+func (c *Client) endpoint(path string, query url.Values) *url.URL {
+	u := *c.baseURL
+	u.Path += "/api/v1" + path
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// get fetches an API path
+func (c *Client) get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	return c.send(ctx, http.MethodGet, path, query, nil)
+}
+
+// send makes a request to an API path, retrying once with a fresh token if it is rejected
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, payload []byte) ([]byte, error) {
+	body, status, err := c.do(ctx, method, path, query, payload)
+	if err == nil && status == http.StatusUnauthorized {
+		if refreshed, refreshErr := c.refreshToken(ctx); refreshErr != nil {
+			return nil, refreshErr
+		} else if refreshed {
+			body, status, err = c.do(ctx, method, path, query, payload)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return nil, responseError(status, method, path, body)
+	}
+	return body, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, payload []byte) ([]byte, int, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint(path, query).String(), reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := c.currentToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("request to %s failed: %w", c.baseURL.Host, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// responseError converts an error response into a Kubernetes status error,
+// so denials read the same as in direct mode
+func responseError(status int, method, path string, body []byte) error {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		message = payload.Error
+	}
+
+	return apierrors.NewGenericServerResponse(status, method, schema.GroupResource{Resource: path}, "", message, 0, false)
+}
+
+// Ping checks that the REST API is up using its unauthenticated health check
+func (c *Client) Ping(ctx context.Context) error {
+	u := *c.baseURL
+	u.Path += "/health"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", c.baseURL.Host, err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned %s", resp.Status)
+	}
+	return nil
+}
+
+// getJSON fetches an API path and decodes its JSON body
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, result interface{}) error {
+	body, err := c.get(ctx, path, query)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// sendJSON sends payload as JSON to an API path, discarding the response
+func (c *Client) sendJSON(ctx context.Context, method, path string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.send(ctx, method, path, nil, data)
+	return err
+}
+
+// resourcePath returns the API path of a namespaced kind
+func resourcePath(clusterID, namespace, kind string) (string, error) {
+	info, ok := assets.LookupKind(kind)
+	if !ok {
+		return "", fmt.Errorf("unsupported kind %q", kind)
+	}
+	return fmt.Sprintf("/clusters/%s/namespaces/%s/%s", url.PathEscape(clusterID), url.PathEscape(namespace), info.Resource), nil
+}
+
+// ListClusters returns the clusters registered with the API
+func (c *Client) ListClusters(ctx context.Context, results *[]cluster.ClusterInfo) error {
+	return c.getJSON(ctx, "/clusters", nil, results)
+}
+
+// List returns the stored resources of a kind. All namespaces are listed
+// through the fleet endpoint, which checks cluster-wide list permission.
+func (c *Client) List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error {
+	if kind == "Namespace" {
+		return c.getJSON(ctx, "/clusters/"+url.PathEscape(clusterID)+"/namespaces", nil, results)
+	}
+
+	if namespace == AllNamespaces {
+		info, ok := assets.LookupKind(kind)
+		if !ok {
+			return fmt.Errorf("unsupported kind %q", kind)
+		}
+
+		var fleet struct {
+			Items []struct {
+				ClusterID string          `json:"clusterID"`
+				Resource  json.RawMessage `json:"resource"`
+			} `json:"items"`
+			Errors map[string]string `json:"errors"`
+		}
+		if err := c.getJSON(ctx, "/"+info.Resource, nil, &fleet); err != nil {
+			return err
+		}
+		if message, failed := fleet.Errors[clusterID]; failed {
+			return fmt.Errorf("failed to list %s in cluster %s: %s", info.Resource, clusterID, message)
+		}
+
+		items := make([]json.RawMessage, 0, len(fleet.Items))
+		for _, item := range fleet.Items {
+			if item.ClusterID == clusterID {
+				items = append(items, item.Resource)
+			}
+		}
+
+		data, err := json.Marshal(items)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, results)
+	}
+
+	path, err := resourcePath(clusterID, namespace, kind)
+	if err != nil {
+		return err
+	}
+	return c.getJSON(ctx, path, nil, results)
+}
+
+// Get returns a stored resource
+func (c *Client) Get(ctx context.Context, clusterID, namespace, kind, name string, result interface{}) error {
+	if kind == "Namespace" {
+		return c.getJSON(ctx, "/clusters/"+url.PathEscape(clusterID)+"/namespaces/"+url.PathEscape(name), nil, result)
+	}
+
+	path, err := resourcePath(clusterID, namespace, kind)
+	if err != nil {
+		return err
+	}
+	return c.getJSON(ctx, path+"/"+url.PathEscape(name), nil, result)
+}
+
+// GetLive returns an object as the cluster currently has it, for editing
+func (c *Client) GetLive(ctx context.Context, clusterID, namespace, kind, name string) (*unstructured.Unstructured, error) {
+	path, err := resourcePath(clusterID, namespace, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := c.getJSON(ctx, path+"/"+url.PathEscape(name)+"/live", nil, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Update replaces an object in the cluster with an edited copy. The copy's
+// resourceVersion makes the update fail if the object changed in the meantime.
+func (c *Client) Update(ctx context.Context, clusterID string, obj *unstructured.Unstructured) error {
+	path, err := resourcePath(clusterID, obj.GetNamespace(), obj.GetKind())
+	if err != nil {
+		return err
+	}
+	return c.sendJSON(ctx, http.MethodPut, path+"/"+url.PathEscape(obj.GetName()), obj)
+}
+
+// DeletePod deletes a pod from the cluster
+func (c *Client) DeletePod(ctx context.Context, clusterID, namespace, name string) error {
+	path, err := resourcePath(clusterID, namespace, "Pod")
+	if err != nil {
+		return err
+	}
+	_, err = c.send(ctx, http.MethodDelete, path+"/"+url.PathEscape(name), nil, nil)
+	return err
+}
+
+// DeleteNamespace deletes a namespace and everything in it from the cluster
+func (c *Client) DeleteNamespace(ctx context.Context, clusterID, namespace string) error {
+	_, err := c.send(ctx, http.MethodDelete, "/clusters/"+url.PathEscape(clusterID)+"/namespaces/"+url.PathEscape(namespace), nil, nil)
+	return err
+}
+
+// watchPath returns the API path of the watch stream of a kind, cluster-wide
+// for namespaces and for all namespaces
+func watchPath(clusterID, namespace, kind string) (string, error) {
+	base := "/clusters/" + url.PathEscape(clusterID) + "/watch"
+	if kind == "Namespace" {
+		return base + "/namespaces", nil
+	}
+
+	info, ok := assets.LookupKind(kind)
+	if !ok {
+		return "", fmt.Errorf("unsupported kind %q", kind)
+	}
+	if namespace == "" || namespace == AllNamespaces {
+		return base + "/" + info.Resource, nil
+	}
+	return base + "/namespaces/" + url.PathEscape(namespace) + "/" + info.Resource, nil
+}
+
+// Watch streams changes to a kind from the watch WebSocket, which follows
+// the store's change stream like a direct watch. The channel closes when the
+// stream ends or the context is done.
+func (c *Client) Watch(ctx context.Context, clusterID, namespace, kind string) (<-chan store.Change, error) {
+	path, err := watchPath(clusterID, namespace, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := c.dial(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan store.Change)
+	go func() {
+		defer close(changes)
+
+		// Closing the connection unblocks the read when the context is done
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-done:
+			}
+			_ = conn.Close()
+		}()
+
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil || streamError(message) != nil {
+				return
+			}
+
+			var change store.Change
+			if err := json.Unmarshal(message, &change); err != nil {
+				continue
+			}
+
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+// Describe returns the kubectl-style description of an object
+func (c *Client) Describe(ctx context.Context, clusterID, kind, namespace, name string) (string, error) {
+	path, err := resourcePath(clusterID, namespace, kind)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := c.get(ctx, path+"/"+url.PathEscape(name)+"/describe", nil)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// RestartSummary explains why the containers of a pod restarted
+func (c *Client) RestartSummary(ctx context.Context, clusterID, namespace, podName string) (*restarts.Summary, error) {
+	path, err := resourcePath(clusterID, namespace, "Pod")
+	if err != nil {
+		return nil, err
+	}
+
+	var summary restarts.Summary
+	if err := c.getJSON(ctx, path+"/"+url.PathEscape(podName)+"/restarts", nil, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Scale sets the replica count of a deployment
+func (c *Client) Scale(ctx context.Context, clusterID, namespace, name string, replicas int32) error {
+	path, err := resourcePath(clusterID, namespace, "Deployment")
+	if err != nil {
+		return err
+	}
+	return c.sendJSON(ctx, http.MethodPost, path+"/"+url.PathEscape(name)+"/scale", deployments.ScaleRequest{Replicas: &replicas})
+}
+
+// RestartDeployment rolls out new pods for a deployment like kubectl rollout restart
+func (c *Client) RestartDeployment(ctx context.Context, clusterID, namespace, name string) error {
+	path, err := resourcePath(clusterID, namespace, "Deployment")
+	if err != nil {
+		return err
+	}
+	return c.sendJSON(ctx, http.MethodPost, path+"/"+url.PathEscape(name)+"/rollout-restart", struct{}{})
+}
+
+// dial opens a WebSocket on an API path
+func (c *Client) dial(ctx context.Context, path string, query url.Values) (*websocket.Conn, error) {
+	u := c.endpoint(path, query)
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+
+	header := http.Header{}
+	if token := c.currentToken(); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		if resp != nil {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, responseError(resp.StatusCode, http.MethodGet, path, body)
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return conn, nil
+}
+
+// dialLogs opens the log stream WebSocket of a container
+func (c *Client) dialLogs(ctx context.Context, clusterID, namespace, podName, containerName string, lines int64) (*websocket.Conn, error) {
+	path, err := resourcePath(clusterID, namespace, "Pod")
+	if err != nil {
+		return nil, err
+	}
+
+	return c.dial(ctx, path+"/"+url.PathEscape(podName)+"/logs/"+url.PathEscape(containerName),
+		url.Values{"tail": {fmt.Sprint(lines)}})
+}
+
+// streamError returns the error a log stream sends as a JSON object before
+// it closes, if the message is one
+func streamError(message []byte) error {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(message, &payload) == nil && payload.Error != "" {
+		return errors.New(payload.Error)
+	}
+	return nil
+}
+
+// Logs reads the last lines of a container's logs from the log stream
+// WebSocket, returning once the stream has been quiet for a moment
+func (c *Client) Logs(ctx context.Context, clusterID, namespace, podName, containerName string, lines int64) (string, error) {
+	conn, err := c.dialLogs(ctx, clusterID, namespace, podName, containerName, lines)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var buf bytes.Buffer
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	for first := true; ; first = false {
+		if !first {
+			deadline = time.Now().Add(logIdleTimeout)
+		}
+		if err := conn.SetReadDeadline(deadline); err != nil {
+			return "", err
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			// The stream follows the logs, so it ends by going quiet or closing
+			break
+		}
+
+		if first {
+			if err := streamError(message); err != nil {
+				return "", err
+			}
+		}
+
+		buf.Write(message)
+	}
+
+	return buf.String(), nil
+}
+
+// StreamLogs copies a container's logs to w as they are written, until the
+// stream closes or the context is done
+func (c *Client) StreamLogs(ctx context.Context, clusterID, namespace, podName, containerName string, lines int64, w io.Writer) error {
+	conn, err := c.dialLogs(ctx, clusterID, namespace, podName, containerName, lines)
+	if err != nil {
+		return err
+	}
+
+	// Closing the connection unblocks the read when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		_ = conn.Close()
+	}()
+
+	for first := true; ; first = false {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			// The server closes the stream when the container's logs end
+			return nil
+		}
+
+		if first {
+			if err := streamError(message); err != nil {
+				return err
+			}
+		}
+
+		if _, err := w.Write(message); err != nil {
+			return err
+		}
+	}
+}
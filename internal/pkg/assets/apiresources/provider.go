@@ -0,0 +1,176 @@
+package apiresources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// DefaultCacheTTL is how long a cluster's discovery result is reused
+const DefaultCacheTTL = 5 * time.Minute
+
+// APIResource is a resource type served by a cluster
+type APIResource struct {
+	Group        string   `json:"group"`
+	Version      string   `json:"version"`
+	Kind         string   `json:"kind"`
+	Name         string   `json:"name"`
+	SingularName string   `json:"singularName,omitempty"`
+	ShortNames   []string `json:"shortNames,omitempty"`
+	Categories   []string `json:"categories,omitempty"`
+	Namespaced   bool     `json:"namespaced"`
+	Verbs        []string `json:"verbs"`
+	// Preferred is set on the version of its group the server prefers
+	Preferred bool `json:"preferred"`
+}
+
+// Discovery is the set of resource types a cluster serves. Groups whose
+// discovery failed, such as an unavailable aggregated API, are listed in
+// FailedGroups and their resources left out.
+type Discovery struct {
+	ClusterID    string        `json:"clusterID"`
+	Resources    []APIResource `json:"resources"`
+	FailedGroups []string      `json:"failedGroups"`
+	DiscoveredAt time.Time     `json:"discoveredAt"`
+}
+
+// entry is a cached discovery
+type entry struct {
+	discovery *Discovery
+	expires   time.Time
+}
+
+// APIResourceProvider discovers the API resources of clusters, caching each
+// result so navigation can be built on every page load
+type APIResourceProvider struct {
+	clusterManager *cluster.Manager
+	ttl            time.Duration
+
+	mu    sync.Mutex
+	cache map[string]entry
+}
+
+// NewAPIResourceProvider creates a new provider. A non-positive ttl uses DefaultCacheTTL.
+func NewAPIResourceProvider(clusterManager *cluster.Manager, ttl time.Duration) *APIResourceProvider {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &APIResourceProvider{
+		clusterManager: clusterManager,
+		ttl:            ttl,
+		cache:          make(map[string]entry),
+	}
+}
+
+// Resources returns the resource types of a cluster from the cache, or
+// discovers them when the cache is stale or refresh is set
+func (p *APIResourceProvider) Resources(ctx context.Context, clusterID string, refresh bool) (*Discovery, error) {
+	p.mu.Lock()
+	cached, ok := p.cache[clusterID]
+	p.mu.Unlock()
+	if ok && !refresh && time.Now().Before(cached.expires) {
+		return cached.discovery, nil
+	}
+
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	result, err := discover(conn.Client.Discovery(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.cache[clusterID] = entry{discovery: result, expires: time.Now().Add(p.ttl)}
+	p.mu.Unlock()
+
+	return result, nil
+}
+
+// discover lists the groups and resources a cluster serves, skipping subresources
+func discover(client discovery.DiscoveryInterface, clusterID string) (*Discovery, error) {
+	groups, lists, err := client.ServerGroupsAndResources()
+
+	result := &Discovery{
+		ClusterID:    clusterID,
+		Resources:    make([]APIResource, 0),
+		FailedGroups: make([]string, 0),
+		DiscoveredAt: time.Now(),
+	}
+
+	var failed *discovery.ErrGroupDiscoveryFailed
+	switch {
+	case errors.As(err, &failed):
+		for gv := range failed.Groups {
+			result.FailedGroups = append(result.FailedGroups, gv.String())
+		}
+		sort.Strings(result.FailedGroups)
+	case err != nil:
+		return nil, fmt.Errorf("failed to discover API resources: %w", err)
+	}
+
+	preferred := make(map[string]string, len(groups))
+	for _, group := range groups {
+		preferred[group.Name] = group.PreferredVersion.Version
+	}
+
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+			result.Resources = append(result.Resources, toAPIResource(gv, resource, preferred[gv.Group] == gv.Version))
+		}
+	}
+
+	sort.Slice(result.Resources, func(i, j int) bool {
+		a, b := result.Resources[i], result.Resources[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+
+	return result, nil
+}
+
+// toAPIResource converts a discovered resource
+func toAPIResource(gv schema.GroupVersion, resource metav1.APIResource, preferred bool) APIResource {
+	verbs := []string(resource.Verbs)
+	if verbs == nil {
+		verbs = make([]string, 0)
+	}
+
+	return APIResource{
+		Group:        gv.Group,
+		Version:      gv.Version,
+		Kind:         resource.Kind,
+		Name:         resource.Name,
+		SingularName: resource.SingularName,
+		ShortNames:   resource.ShortNames,
+		Categories:   resource.Categories,
+		Namespaced:   resource.Namespaced,
+		Verbs:        verbs,
+		Preferred:    preferred,
+	}
+}
@@ -0,0 +1,358 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets/diff"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/export"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/objectstore"
+	"github.com/jbetancur/dashboard/internal/pkg/snapshot"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// rootCAConfigMap is published into every namespace by the cluster and is never restored
+const rootCAConfigMap = "kube-root-ca.crt"
+
+// resources maps the snapshotted kinds to their API resources, in restore order
+var resources = []struct {
+	kind     string
+	resource schema.GroupVersionResource
+}{
+	{"Namespace", schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}},
+	{"ConfigMap", schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}},
+	{"Pod", schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+}
+
+// Allow reports whether the caller may perform verb on an object. Restores
+// write with the dashboard's credentials, so every create and update is
+// checked against the user's own permissions first.
+type Allow func(ctx context.Context, resource, namespace, name, verb string) (bool, error)
+
+// ObjectResult is the outcome of restoring one manifest. Changes list the
+// cluster's current values as stored and the snapshot's as live.
+type ObjectResult struct {
+	Kind    string        `json:"kind"`
+	Name    string        `json:"name"`
+	Action  string        `json:"action"`
+	Changes []diff.Change `json:"changes,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// RestoreResult describes what a restore did, or would do in a dry run
+type RestoreResult struct {
+	SnapshotID string         `json:"snapshotID"`
+	ClusterID  string         `json:"clusterID"`
+	Namespace  string         `json:"namespace"`
+	DryRun     bool           `json:"dryRun"`
+	Objects    []ObjectResult `json:"objects"`
+}
+
+// BackupProvider snapshots namespaces from the store and restores them to clusters
+type BackupProvider struct {
+	clusterManager *cluster.Manager
+	store          store.Repository
+	objects        objectstore.Store
+}
+
+// NewBackupProvider creates a new provider. When objects is set, snapshot
+// manifests are kept there rather than in the database.
+func NewBackupProvider(clusterManager *cluster.Manager, store store.Repository, objects objectstore.Store) *BackupProvider {
+	return &BackupProvider{
+		clusterManager: clusterManager,
+		store:          store,
+		objects:        objects,
+	}
+}
+
+// Snapshot archives the stored manifests of a namespace as its next version.
+// Pods managed by a controller are left out since the controllers are not
+// stored and would recreate them anyway.
+func (p *BackupProvider) Snapshot(ctx context.Context, clusterID, namespace, createdBy string) (*snapshot.Snapshot, error) {
+	objects := make([]runtime.Object, 0)
+
+	var ns corev1.Namespace
+	if err := p.store.Get(ctx, clusterID, "", "Namespace", namespace, &ns); err != nil {
+		return nil, fmt.Errorf("failed to read stored namespace: %w", err)
+	}
+	objects = append(objects, &ns)
+
+	var configMaps []corev1.ConfigMap
+	if err := p.store.List(ctx, clusterID, namespace, "ConfigMap", &configMaps); err != nil {
+		return nil, fmt.Errorf("failed to list stored config maps: %w", err)
+	}
+	for i := range configMaps {
+		if configMaps[i].Name != rootCAConfigMap {
+			objects = append(objects, &configMaps[i])
+		}
+	}
+
+	var pods []corev1.Pod
+	if err := p.store.List(ctx, clusterID, namespace, "Pod", &pods); err != nil {
+		return nil, fmt.Errorf("failed to list stored pods: %w", err)
+	}
+	for i := range pods {
+		if metav1.GetControllerOfNoCopy(&pods[i]) == nil {
+			objects = append(objects, &pods[i])
+		}
+	}
+
+	var previous []snapshot.Snapshot
+	if err := p.store.ListSnapshots(ctx, clusterID, namespace, &previous); err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	snap := &snapshot.Snapshot{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Version:   1,
+		CreatedAt: time.Now(),
+		CreatedBy: createdBy,
+		Manifests: make([]snapshot.Manifest, 0, len(objects)),
+	}
+	if len(previous) > 0 {
+		snap.Version = previous[0].Version + 1
+	}
+
+	for _, obj := range objects {
+		content, err := export.Clean(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode manifest: %w", err)
+		}
+
+		manifest := unstructured.Unstructured{Object: content}
+		snap.Manifests = append(snap.Manifests, snapshot.Manifest{
+			Kind:    manifest.GetKind(),
+			Name:    manifest.GetName(),
+			Content: string(data),
+		})
+	}
+
+	if err := p.save(ctx, snap); err != nil {
+		return nil, err
+	}
+
+	return snap, nil
+}
+
+// save stores a snapshot, moving its manifests to the object store if there is one
+func (p *BackupProvider) save(ctx context.Context, snap *snapshot.Snapshot) error {
+	if p.objects == nil {
+		return p.store.SaveSnapshot(ctx, snap)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(snap.Manifests); err != nil {
+		return fmt.Errorf("failed to encode manifests: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress manifests: %w", err)
+	}
+
+	key := fmt.Sprintf("snapshots/%s/%s/v%d.json.gz", snap.ClusterID, snap.Namespace, snap.Version)
+	if err := p.objects.Put(ctx, key, buf.Bytes(), "application/gzip"); err != nil {
+		return fmt.Errorf("failed to store manifests: %w", err)
+	}
+
+	document := *snap
+	document.Manifests = nil
+	document.ManifestsKey = key
+	if err := p.store.SaveSnapshot(ctx, &document); err != nil {
+		return err
+	}
+	snap.ID = document.ID
+
+	return nil
+}
+
+// GetSnapshot retrieves a snapshot with its manifests, wherever they are kept
+func (p *BackupProvider) GetSnapshot(ctx context.Context, id string) (*snapshot.Snapshot, error) {
+	var snap snapshot.Snapshot
+	if err := p.store.GetSnapshot(ctx, id, &snap); err != nil {
+		return nil, err
+	}
+
+	if snap.ManifestsKey == "" {
+		return &snap, nil
+	}
+
+	if p.objects == nil {
+		return nil, fmt.Errorf("snapshot %s is kept in the object store, which is not configured", id)
+	}
+
+	data, err := p.objects.Get(ctx, snap.ManifestsKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress manifests: %w", err)
+	}
+	if err := json.NewDecoder(gz).Decode(&snap.Manifests); err != nil {
+		return nil, fmt.Errorf("failed to decode manifests: %w", err)
+	}
+
+	return &snap, nil
+}
+
+// Restore applies a snapshot to a namespace of a cluster, which may differ
+// from where it was taken. Objects that do not exist are created and objects
+// that differ are updated; with dryRun the changes are only reported. A
+// failure on one object, including one the caller may not write, is
+// recorded and does not stop the others.
+func (p *BackupProvider) Restore(ctx context.Context, snap *snapshot.Snapshot, clusterID, namespace string, dryRun bool, allow Allow) (*RestoreResult, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	client, err := dynamic.NewForConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	manifests := make([]snapshot.Manifest, len(snap.Manifests))
+	copy(manifests, snap.Manifests)
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return restoreOrder(manifests[i].Kind) < restoreOrder(manifests[j].Kind)
+	})
+
+	result := &RestoreResult{
+		SnapshotID: snap.ID,
+		ClusterID:  clusterID,
+		Namespace:  namespace,
+		DryRun:     dryRun,
+		Objects:    make([]ObjectResult, 0, len(manifests)),
+	}
+
+	for _, manifest := range manifests {
+		result.Objects = append(result.Objects, p.restoreObject(ctx, client, manifest, namespace, dryRun, allow))
+	}
+
+	return result, nil
+}
+
+// restoreObject creates or updates one manifest in the target namespace
+func (p *BackupProvider) restoreObject(ctx context.Context, client dynamic.Interface, manifest snapshot.Manifest, namespace string, dryRun bool, allow Allow) ObjectResult {
+	result := ObjectResult{Kind: manifest.Kind, Name: manifest.Name}
+
+	gvr, ok := resourceFor(manifest.Kind)
+	if !ok {
+		result.Error = fmt.Sprintf("kind %q cannot be restored", manifest.Kind)
+		return result
+	}
+
+	desired := &unstructured.Unstructured{}
+	if err := desired.UnmarshalJSON([]byte(manifest.Content)); err != nil {
+		result.Error = fmt.Sprintf("failed to decode manifest: %v", err)
+		return result
+	}
+
+	var resource dynamic.ResourceInterface
+	objectNamespace := namespace
+	if manifest.Kind == "Namespace" {
+		desired.SetName(namespace)
+		result.Name = namespace
+		resource = client.Resource(gvr)
+		objectNamespace = ""
+	} else {
+		desired.SetNamespace(namespace)
+		resource = client.Resource(gvr).Namespace(namespace)
+	}
+
+	// permitted records an error on the result unless the caller may write the object
+	permitted := func(verb string) bool {
+		allowed, err := allow(ctx, gvr.Resource, objectNamespace, desired.GetName(), verb)
+		switch {
+		case err != nil:
+			result.Error = fmt.Sprintf("failed to check permissions: %v", err)
+		case !allowed:
+			result.Error = fmt.Sprintf("not allowed to %s %s", verb, gvr.Resource)
+		}
+		return err == nil && allowed
+	}
+
+	var options []string
+	if dryRun {
+		options = []string{metav1.DryRunAll}
+	}
+
+	live, err := resource.Get(ctx, desired.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		result.Action = snapshot.ActionCreate
+		if !permitted("create") {
+			return result
+		}
+		if _, err := resource.Create(ctx, desired, metav1.CreateOptions{DryRun: options}); err != nil {
+			result.Error = err.Error()
+		}
+		return result
+	case err != nil:
+		result.Error = err.Error()
+		return result
+	}
+
+	current, err := export.Clean(live)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Changes = diff.Compare(current, desired.Object)
+	if len(result.Changes) == 0 {
+		result.Action = snapshot.ActionUnchanged
+		return result
+	}
+
+	result.Action = snapshot.ActionUpdate
+	if !permitted("update") {
+		return result
+	}
+	desired.SetResourceVersion(live.GetResourceVersion())
+	if _, err := resource.Update(ctx, desired, metav1.UpdateOptions{DryRun: options}); err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// resourceFor returns the API resource of a snapshotted kind
+func resourceFor(kind string) (schema.GroupVersionResource, bool) {
+	for _, entry := range resources {
+		if entry.kind == kind {
+			return entry.resource, true
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// restoreOrder places the namespace before the objects in it
+func restoreOrder(kind string) int {
+	for i, entry := range resources {
+		if entry.kind == kind {
+			return i
+		}
+	}
+	return len(resources)
+}
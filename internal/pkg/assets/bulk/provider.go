@@ -0,0 +1,244 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	resources "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Operations
+const (
+	OperationDelete  = "delete"
+	OperationRestart = "restart"
+)
+
+// Item outcomes
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusForbidden = "forbidden"
+	StatusNotFound  = "notFound"
+)
+
+// RestartableKinds are the kinds a restart rolls out again
+var RestartableKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+
+// Config bounds bulk operations
+type Config struct {
+	// Concurrency is how many targets are worked on at once
+	Concurrency int `yaml:"concurrency"`
+	// MaxTargets is the most targets one request may name
+	MaxTargets int `yaml:"maxTargets"`
+	// WaveTimeout is how long a wave of a wave restart may take by default
+	WaveTimeout time.Duration `yaml:"waveTimeout"`
+}
+
+// Target names one object
+type Target struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Request is an operation to run on a list of targets in one cluster
+type Request struct {
+	Operation string   `json:"operation"`
+	Targets   []Target `json:"targets"`
+	DryRun    bool     `json:"dryRun"`
+}
+
+// ItemResult is the outcome for one target
+type ItemResult struct {
+	Target
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the outcome of a bulk operation, with items in request order
+type Report struct {
+	ClusterID string        `json:"clusterID"`
+	Operation string        `json:"operation"`
+	DryRun    bool          `json:"dryRun"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Duration  time.Duration `json:"duration"`
+	Items     []ItemResult  `json:"items"`
+}
+
+// Allow reports whether the caller may perform verb on an object
+type Allow func(ctx context.Context, resource, namespace, name, verb string) (bool, error)
+
+// BulkProvider runs an operation on many objects with bounded concurrency
+type BulkProvider struct {
+	clusterManager *cluster.Manager
+	config         Config
+}
+
+// NewBulkProvider creates a new provider, filling in defaults for unset limits
+func NewBulkProvider(clusterManager *cluster.Manager, config Config) *BulkProvider {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 5
+	}
+	if config.MaxTargets <= 0 {
+		config.MaxTargets = 100
+	}
+	if config.WaveTimeout <= 0 {
+		config.WaveTimeout = 5 * time.Minute
+	}
+
+	return &BulkProvider{
+		clusterManager: clusterManager,
+		config:         config,
+	}
+}
+
+// Validate checks a request before anything is changed
+func (p *BulkProvider) Validate(req *Request) error {
+	if req.Operation != OperationDelete && req.Operation != OperationRestart {
+		return fmt.Errorf("operation must be %s or %s", OperationDelete, OperationRestart)
+	}
+	if len(req.Targets) == 0 {
+		return fmt.Errorf("at least one target is required")
+	}
+	if len(req.Targets) > p.config.MaxTargets {
+		return fmt.Errorf("at most %d targets are allowed", p.config.MaxTargets)
+	}
+
+	for i, target := range req.Targets {
+		if target.Namespace == "" || target.Name == "" {
+			return fmt.Errorf("target %d requires a namespace and name", i)
+		}
+
+		kind, ok := resources.LookupKind(target.Kind)
+		if !ok {
+			return fmt.Errorf("target %d has unsupported kind %q", i, target.Kind)
+		}
+		if req.Operation == OperationRestart && !slices.Contains(RestartableKinds, kind.Kind) {
+			return fmt.Errorf("target %d: %s can't be restarted", i, kind.Kind)
+		}
+	}
+
+	return nil
+}
+
+// Run applies a validated request to its targets. A failure on one target
+// is recorded and does not stop the others, and targets allow refuses are
+// reported as forbidden without being touched.
+func (p *BulkProvider) Run(ctx context.Context, clusterID string, req *Request, allow Allow) (*Report, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	client, err := dynamic.NewForConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	start := time.Now()
+	report := &Report{
+		ClusterID: clusterID,
+		Operation: req.Operation,
+		DryRun:    req.DryRun,
+		Items:     make([]ItemResult, len(req.Targets)),
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.config.Concurrency)
+	for i, target := range req.Targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				report.Items[i] = ItemResult{Target: target, Status: StatusFailed, Error: ctx.Err().Error()}
+				return
+			}
+
+			report.Items[i] = p.apply(ctx, client, req, target, allow)
+		}()
+	}
+	wg.Wait()
+
+	for _, item := range report.Items {
+		if item.Status == StatusSucceeded {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+	report.Duration = time.Since(start)
+
+	return report, nil
+}
+
+// apply checks permission for one target and runs the operation on it
+func (p *BulkProvider) apply(ctx context.Context, client dynamic.Interface, req *Request, target Target, allow Allow) ItemResult {
+	kind, _ := resources.LookupKind(target.Kind)
+	target.Kind = kind.Kind
+	result := ItemResult{Target: target}
+
+	verb := "delete"
+	if req.Operation == OperationRestart {
+		verb = "patch"
+	}
+
+	allowed, err := allow(ctx, kind.RBACResource(), target.Namespace, target.Name, verb)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = fmt.Sprintf("failed to check permissions: %v", err)
+		return result
+	}
+	if !allowed {
+		result.Status = StatusForbidden
+		result.Error = fmt.Sprintf("not allowed to %s %s", verb, kind.RBACResource())
+		return result
+	}
+
+	gvr := schema.GroupVersionResource{Group: kind.Group, Version: kind.Version, Resource: kind.Resource}
+	resource := client.Resource(gvr).Namespace(target.Namespace)
+
+	var dryRun []string
+	if req.DryRun {
+		dryRun = []string{metav1.DryRunAll}
+	}
+
+	switch req.Operation {
+	case OperationDelete:
+		err = resource.Delete(ctx, target.Name, metav1.DeleteOptions{DryRun: dryRun})
+	case OperationRestart:
+		// Same as kubectl rollout restart: a changed template annotation rolls the pods
+		patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`,
+			time.Now().Format(time.RFC3339))
+		_, err = resource.Patch(ctx, target.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{DryRun: dryRun})
+	}
+
+	switch {
+	case err == nil:
+		result.Status = StatusSucceeded
+	case apierrors.IsNotFound(err):
+		result.Status = StatusNotFound
+		result.Error = err.Error()
+	case apierrors.IsForbidden(err):
+		result.Status = StatusForbidden
+		result.Error = err.Error()
+	default:
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+
+	return result
+}
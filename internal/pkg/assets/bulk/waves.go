@@ -0,0 +1,372 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	resources "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/pdbs"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Phases of a wave restart, in the order they are reported
+const (
+	PhaseStarted   = "started"
+	PhaseWaiting   = "waiting"
+	PhaseEvicted   = "evicted"
+	PhaseWaveDone  = "waveDone"
+	PhaseCompleted = "completed"
+	PhaseAborted   = "aborted"
+)
+
+// wavePollInterval is how often pods and budgets are re-read while waiting
+const wavePollInterval = 2 * time.Second
+
+// ErrAborted is returned when a wave restart stops to protect availability
+var ErrAborted = errors.New("restart aborted")
+
+// WaveRequest restarts the pods of one workload a few at a time
+type WaveRequest struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// WaveSize is the most pods evicted at once. Budgets covering the pods
+	// and MinAvailable can make waves smaller.
+	WaveSize int `json:"waveSize"`
+	// MinAvailable is the percentage of desired pods that must stay ready.
+	// Zero keeps all but one wave ready. The restart aborts below it.
+	MinAvailable int `json:"minAvailable"`
+	// WaveTimeout is how long a wave may take to start and become ready again
+	WaveTimeout time.Duration `json:"waveTimeout"`
+}
+
+// Progress is one step of a wave restart
+type Progress struct {
+	Phase     string    `json:"phase"`
+	Wave      int       `json:"wave,omitempty"`
+	Pods      []string  `json:"pods,omitempty"`
+	Ready     int       `json:"ready"`
+	Desired   int       `json:"desired"`
+	Remaining int       `json:"remaining"`
+	Message   string    `json:"message,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// ValidateWaves checks a wave restart request, filling in defaults
+func (p *BulkProvider) ValidateWaves(req *WaveRequest) error {
+	kind, ok := resources.LookupKind(req.Kind)
+	if !ok || !slices.Contains(RestartableKinds, kind.Kind) {
+		return fmt.Errorf("kind must be one of %v", RestartableKinds)
+	}
+	req.Kind = kind.Kind
+
+	if req.Namespace == "" || req.Name == "" {
+		return fmt.Errorf("namespace and name are required")
+	}
+	if req.WaveSize < 0 {
+		return fmt.Errorf("waveSize must not be negative")
+	}
+	if req.WaveSize == 0 {
+		req.WaveSize = 1
+	}
+	if req.MinAvailable < 0 || req.MinAvailable > 100 {
+		return fmt.Errorf("minAvailable must be a percentage")
+	}
+	if req.WaveTimeout <= 0 {
+		req.WaveTimeout = p.config.WaveTimeout
+	}
+
+	return nil
+}
+
+// RestartInWaves restarts the pods a workload has now by evicting them in
+// waves. The eviction API enforces PodDisruptionBudgets; on top of that each
+// wave is sized to what the budgets allow and to the pods that can be down
+// while MinAvailable stay ready, and the next wave waits until the replaced
+// pods are gone and readiness is back where it was. The restart aborts if
+// readiness falls below the minimum or a wave doesn't finish in time.
+// Pods created during the restart are already new and are left alone.
+func (p *BulkProvider) RestartInWaves(ctx context.Context, clusterID string, req *WaveRequest, emit func(Progress)) error {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("cluster not found: %w", err)
+	}
+
+	obj, err := resources.GetObject(ctx, conn.Client, req.Kind, req.Namespace, req.Name)
+	if err != nil {
+		return err
+	}
+	selector, podLabels, desired, err := workloadPods(obj)
+	if err != nil {
+		return err
+	}
+
+	run := &waveRun{
+		client:    conn.Client,
+		req:       req,
+		selector:  selector,
+		podLabels: podLabels,
+		desired:   desired,
+		emit:      emit,
+	}
+
+	current, ready, err := run.pods(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := make([]types.UID, 0, len(current))
+	for uid := range current {
+		remaining = append(remaining, uid)
+	}
+	sort.Slice(remaining, func(i, j int) bool {
+		return current[remaining[i]].Name < current[remaining[j]].Name
+	})
+
+	run.minReady = max(desired-req.WaveSize, 0)
+	if req.MinAvailable > 0 {
+		run.minReady = int(math.Ceil(float64(desired*req.MinAvailable) / 100))
+	}
+
+	run.send(Progress{Phase: PhaseStarted, Ready: ready, Remaining: len(remaining),
+		Message: fmt.Sprintf("restarting %d pods, keeping at least %d ready", len(remaining), run.minReady)})
+
+	for wave := 1; ; {
+		deadline := time.Now().Add(req.WaveTimeout)
+
+		// Wait until the budgets and readiness leave room to evict
+		var size, readyBefore int
+		for {
+			current, ready, err = run.pods(ctx)
+			if err != nil {
+				return err
+			}
+			remaining = slices.DeleteFunc(remaining, func(uid types.UID) bool {
+				_, ok := current[uid]
+				return !ok
+			})
+			if len(remaining) == 0 {
+				run.send(Progress{Phase: PhaseCompleted, Ready: ready, Message: "all pods restarted"})
+				return nil
+			}
+			if ready < run.minReady {
+				return run.abort(wave, ready, len(remaining), "%d pods are ready, fewer than the %d required", ready, run.minReady)
+			}
+
+			allowed, message, err := run.budgets(ctx)
+			if err != nil {
+				return err
+			}
+			size = min(req.WaveSize, ready-run.minReady, len(remaining), allowed)
+			if size > 0 {
+				readyBefore = ready
+				break
+			}
+			if time.Now().After(deadline) {
+				return run.abort(wave, ready, len(remaining), "no pod could be evicted within %s: %s", req.WaveTimeout, message)
+			}
+
+			run.send(Progress{Phase: PhaseWaiting, Wave: wave, Ready: ready, Remaining: len(remaining), Message: message})
+			if err := sleep(ctx, wavePollInterval); err != nil {
+				return err
+			}
+		}
+
+		// Evict the wave. A budget can still refuse if it changed since it was read.
+		evicted := make(map[types.UID]bool, size)
+		var names []string
+		for _, uid := range remaining[:size] {
+			pod := current[uid]
+			err := run.client.PolicyV1().Evictions(req.Namespace).Evict(ctx, &policyv1.Eviction{
+				ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+				DeleteOptions: &metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}},
+			})
+			switch {
+			case err == nil:
+				evicted[uid] = true
+				names = append(names, pod.Name)
+			case apierrors.IsTooManyRequests(err):
+				// The budget has no disruption to spare, try again next wave
+			case apierrors.IsNotFound(err), apierrors.IsConflict(err):
+				// Gone or replaced already
+				evicted[uid] = true
+			default:
+				return run.abort(wave, ready, len(remaining), "failed to evict %s: %v", pod.Name, err)
+			}
+		}
+		remaining = slices.DeleteFunc(remaining, func(uid types.UID) bool { return evicted[uid] })
+		if len(names) == 0 {
+			if err := sleep(ctx, wavePollInterval); err != nil {
+				return err
+			}
+			continue
+		}
+		run.send(Progress{Phase: PhaseEvicted, Wave: wave, Pods: names, Ready: ready - len(names), Remaining: len(remaining)})
+
+		// Wait for the evicted pods to go and readiness to come back
+		target := min(readyBefore, desired)
+		for {
+			current, ready, err = run.pods(ctx)
+			if err != nil {
+				return err
+			}
+
+			replaced := true
+			for uid := range evicted {
+				if _, ok := current[uid]; ok {
+					replaced = false
+				}
+			}
+			if replaced && ready >= target {
+				run.send(Progress{Phase: PhaseWaveDone, Wave: wave, Pods: names, Ready: ready, Remaining: len(remaining)})
+				wave++
+				break
+			}
+			if ready < run.minReady {
+				return run.abort(wave, ready, len(remaining), "%d pods are ready, fewer than the %d required", ready, run.minReady)
+			}
+			if time.Now().After(deadline) {
+				return run.abort(wave, ready, len(remaining), "wave %d was not ready within %s", wave, req.WaveTimeout)
+			}
+
+			if err := sleep(ctx, wavePollInterval); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// waveRun is the state of one wave restart
+type waveRun struct {
+	client    kubernetes.Interface
+	req       *WaveRequest
+	selector  labels.Selector
+	podLabels map[string]string
+	desired   int
+	minReady  int
+	emit      func(Progress)
+}
+
+// send reports progress, stamping the time and desired pods
+func (r *waveRun) send(progress Progress) {
+	progress.Desired = r.desired
+	progress.Time = time.Now()
+	r.emit(progress)
+}
+
+// abort reports why the restart stopped and returns ErrAborted
+func (r *waveRun) abort(wave, ready, remaining int, format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	r.send(Progress{Phase: PhaseAborted, Wave: wave, Ready: ready, Remaining: remaining, Message: message})
+	return fmt.Errorf("%w: %s", ErrAborted, message)
+}
+
+// pods returns the workload's pods by UID and how many are ready. Pods
+// being deleted don't count as ready.
+func (r *waveRun) pods(ctx context.Context) (map[types.UID]corev1.Pod, int, error) {
+	list, err := r.client.CoreV1().Pods(r.req.Namespace).List(ctx, metav1.ListOptions{LabelSelector: r.selector.String()})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	pods := make(map[types.UID]corev1.Pod, len(list.Items))
+	ready := 0
+	for _, pod := range list.Items {
+		pods[pod.UID] = pod
+		if pod.DeletionTimestamp == nil && podReady(&pod) {
+			ready++
+		}
+	}
+
+	return pods, ready, nil
+}
+
+// budgets returns how many pods the budgets covering the workload allow to
+// be disrupted now, unlimited if none covers it, with the budgets' verdict
+func (r *waveRun) budgets(ctx context.Context) (int, string, error) {
+	list, err := r.client.PolicyV1().PodDisruptionBudgets(r.req.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	disruption := pdbs.Evaluate(list.Items, r.podLabels)
+	allowed := math.MaxInt
+	for _, budget := range disruption.Budgets {
+		allowed = min(allowed, max(int(budget.DisruptionsAllowed), 0))
+	}
+
+	return allowed, disruption.Message, nil
+}
+
+// workloadPods returns the selector, template labels and desired pod count of a workload
+func workloadPods(obj runtime.Object) (labels.Selector, map[string]string, int, error) {
+	var (
+		selector *metav1.LabelSelector
+		template corev1.PodTemplateSpec
+		desired  int
+	)
+
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		selector, template, desired = workload.Spec.Selector, workload.Spec.Template, replicas(workload.Spec.Replicas)
+	case *appsv1.StatefulSet:
+		selector, template, desired = workload.Spec.Selector, workload.Spec.Template, replicas(workload.Spec.Replicas)
+	case *appsv1.DaemonSet:
+		selector, template, desired = workload.Spec.Selector, workload.Spec.Template, int(workload.Status.DesiredNumberScheduled)
+	default:
+		return nil, nil, 0, fmt.Errorf("%T can't be restarted in waves", obj)
+	}
+
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("invalid selector: %w", err)
+	}
+	if parsed.Empty() {
+		return nil, nil, 0, fmt.Errorf("workload has an empty selector")
+	}
+
+	return parsed, template.Labels, desired, nil
+}
+
+// replicas returns a replica count, which defaults to one when unset
+func replicas(count *int32) int {
+	if count == nil {
+		return 1
+	}
+	return int(*count)
+}
+
+// podReady reports whether a pod's Ready condition is true
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,298 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultPool groups nodes that carry none of the known node pool labels
+const defaultPool = "default"
+
+// nodePoolLabels are the well-known labels used by managed offerings and
+// autoscalers to name a node's pool, in order of preference
+var nodePoolLabels = []string{
+	"cloud.google.com/gke-nodepool",
+	"kubernetes.azure.com/agentpool",
+	"eks.amazonaws.com/nodegroup",
+	"karpenter.sh/nodepool",
+	"node.kubernetes.io/instance-type",
+}
+
+// Resources is an amount of CPU in millicores, memory in bytes and pod slots
+type Resources struct {
+	CPUMillicores int64 `json:"cpuMillicores"`
+	MemoryBytes   int64 `json:"memoryBytes"`
+	Pods          int64 `json:"pods"`
+}
+
+// add adds other to r
+func (r *Resources) add(other Resources) {
+	r.CPUMillicores += other.CPUMillicores
+	r.MemoryBytes += other.MemoryBytes
+	r.Pods += other.Pods
+}
+
+// sub returns r minus other, never below zero
+func (r Resources) sub(other Resources) Resources {
+	return Resources{
+		CPUMillicores: max(r.CPUMillicores-other.CPUMillicores, 0),
+		MemoryBytes:   max(r.MemoryBytes-other.MemoryBytes, 0),
+		Pods:          max(r.Pods-other.Pods, 0),
+	}
+}
+
+// fits reports whether r can hold other
+func (r Resources) fits(other Resources) bool {
+	return r.CPUMillicores >= other.CPUMillicores && r.MemoryBytes >= other.MemoryBytes && r.Pods >= other.Pods
+}
+
+// Node is the capacity of a single node
+type Node struct {
+	Name        string    `json:"name"`
+	Schedulable bool      `json:"schedulable"`
+	Allocatable Resources `json:"allocatable"`
+	Requested   Resources `json:"requested"`
+	Free        Resources `json:"free"`
+}
+
+// Pool is the capacity of a node pool. LargestFit lists the free resources
+// of schedulable nodes that no other node beats on both CPU and memory; a pod
+// fits the pool when its requests fit within one of them. Taints and
+// affinity are not taken into account.
+type Pool struct {
+	Name        string      `json:"name"`
+	Nodes       []Node      `json:"nodes"`
+	Allocatable Resources   `json:"allocatable"`
+	Requested   Resources   `json:"requested"`
+	Free        Resources   `json:"free"`
+	LargestFit  []Resources `json:"largestFit"`
+}
+
+// PendingPod is a pod the scheduler could not place for lack of resources
+type PendingPod struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Requests  Resources `json:"requests"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	// FitsPools lists pools with a node that could hold the pod's requests
+	FitsPools []string `json:"fitsPools"`
+}
+
+// Report is the capacity and scheduling headroom of a cluster
+type Report struct {
+	ClusterID   string       `json:"clusterID"`
+	Allocatable Resources    `json:"allocatable"`
+	Requested   Resources    `json:"requested"`
+	Free        Resources    `json:"free"`
+	Pools       []Pool       `json:"pools"`
+	PendingPods []PendingPod `json:"pendingPods"`
+}
+
+// CapacityProvider computes schedulable capacity from node allocatable and pod requests
+type CapacityProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewCapacityProvider creates a new provider
+func NewCapacityProvider(clusterManager *cluster.Manager) *CapacityProvider {
+	return &CapacityProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// Report returns the capacity of every node pool and the pods waiting on resources
+func (p *CapacityProvider) Report(ctx context.Context, clusterID string) (*Report, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	nodes, err := conn.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	pods, err := conn.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	requested := requestedByNode(pods.Items)
+	pending := make([]PendingPod, 0)
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		if reason, message, ok := blockedOnResources(pod); ok {
+			pending = append(pending, PendingPod{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Requests:  podResources(pod),
+				Reason:    reason,
+				Message:   message,
+			})
+		}
+	}
+
+	pools := make(map[string]*Pool)
+	report := &Report{ClusterID: clusterID}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		allocatable := Resources{
+			CPUMillicores: node.Status.Allocatable.Cpu().MilliValue(),
+			MemoryBytes:   node.Status.Allocatable.Memory().Value(),
+			Pods:          node.Status.Allocatable.Pods().Value(),
+		}
+		used := requested[node.Name]
+
+		capacity := Node{
+			Name:        node.Name,
+			Schedulable: schedulable(node),
+			Allocatable: allocatable,
+			Requested:   used,
+			Free:        allocatable.sub(used),
+		}
+
+		name := poolOf(node)
+		pool, ok := pools[name]
+		if !ok {
+			pool = &Pool{Name: name}
+			pools[name] = pool
+		}
+
+		pool.Nodes = append(pool.Nodes, capacity)
+		pool.Allocatable.add(allocatable)
+		pool.Requested.add(used)
+		report.Allocatable.add(allocatable)
+		report.Requested.add(used)
+
+		// Cordoned and not ready nodes offer no headroom
+		if capacity.Schedulable {
+			pool.Free.add(capacity.Free)
+			report.Free.add(capacity.Free)
+		}
+	}
+
+	report.Pools = make([]Pool, 0, len(pools))
+	for _, pool := range pools {
+		sort.Slice(pool.Nodes, func(i, j int) bool {
+			return pool.Nodes[i].Name < pool.Nodes[j].Name
+		})
+		pool.LargestFit = largestFit(pool.Nodes)
+		report.Pools = append(report.Pools, *pool)
+	}
+
+	sort.Slice(report.Pools, func(i, j int) bool {
+		return report.Pools[i].Name < report.Pools[j].Name
+	})
+
+	for i := range pending {
+		pending[i].FitsPools = make([]string, 0)
+		for _, pool := range report.Pools {
+			for _, fit := range pool.LargestFit {
+				if fit.fits(pending[i].Requests) {
+					pending[i].FitsPools = append(pending[i].FitsPools, pool.Name)
+					break
+				}
+			}
+		}
+	}
+	report.PendingPods = pending
+
+	return report, nil
+}
+
+// podResources returns the scheduler view of a pod's requests and its pod slot
+func podResources(pod *corev1.Pod) Resources {
+	requests := assets.PodRequests(pod, false)
+	return Resources{
+		CPUMillicores: requests.Cpu().MilliValue(),
+		MemoryBytes:   requests.Memory().Value(),
+		Pods:          1,
+	}
+}
+
+// blockedOnResources reports whether the scheduler rejected a pod for lack of resources
+func blockedOnResources(pod *corev1.Pod) (reason, message string, ok bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodScheduled || condition.Status != corev1.ConditionFalse {
+			continue
+		}
+
+		if condition.Reason == corev1.PodReasonUnschedulable &&
+			(strings.Contains(condition.Message, "Insufficient") || strings.Contains(condition.Message, "Too many pods")) {
+			return condition.Reason, condition.Message, true
+		}
+	}
+
+	return "", "", false
+}
+
+// schedulable reports whether new pods can land on a node
+func schedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// poolOf returns the name of a node's pool
+func poolOf(node *corev1.Node) string {
+	for _, label := range nodePoolLabels {
+		if pool := node.Labels[label]; pool != "" {
+			return pool
+		}
+	}
+
+	return defaultPool
+}
+
+// largestFit returns the free resources of schedulable nodes that are not
+// dominated by another node, largest CPU first
+func largestFit(nodes []Node) []Resources {
+	candidates := make([]Resources, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Schedulable && node.Free.Pods > 0 {
+			candidates = append(candidates, node.Free)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].CPUMillicores != candidates[j].CPUMillicores {
+			return candidates[i].CPUMillicores > candidates[j].CPUMillicores
+		}
+		return candidates[i].MemoryBytes > candidates[j].MemoryBytes
+	})
+
+	// Walking by descending CPU, a node is kept only if it has more memory than every node before it
+	fits := make([]Resources, 0)
+	var bestMemory int64 = -1
+	for _, candidate := range candidates {
+		if candidate.MemoryBytes > bestMemory {
+			fits = append(fits, candidate)
+			bestMemory = candidate.MemoryBytes
+		}
+	}
+
+	return fits
+}
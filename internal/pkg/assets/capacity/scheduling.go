@@ -0,0 +1,512 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Scheduling reason codes
+const (
+	ReasonInsufficientCPU      = "InsufficientCPU"
+	ReasonInsufficientMemory   = "InsufficientMemory"
+	ReasonTooManyPods          = "TooManyPods"
+	ReasonUntoleratedTaint     = "UntoleratedTaint"
+	ReasonNodeSelectorMismatch = "NodeSelectorMismatch"
+	ReasonNodeAffinityMismatch = "NodeAffinityMismatch"
+	ReasonNodeUnschedulable    = "NodeUnschedulable"
+	ReasonNodeNotReady         = "NodeNotReady"
+	ReasonNoNodes              = "NoNodes"
+	ReasonPVCNotFound          = "PVCNotFound"
+	ReasonPVCUnbound           = "PVCUnbound"
+	ReasonScheduler            = "Scheduler"
+)
+
+// Exclusion is a reason a node can't run a pod
+type Exclusion struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// SchedulingReason is a cause of a pod not being scheduled. Node reasons list
+// the nodes they exclude; a node may be excluded for several reasons.
+type SchedulingReason struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Nodes   []string `json:"nodes,omitempty"`
+}
+
+// SchedulingEvent is a FailedScheduling event recorded for a pod
+type SchedulingEvent struct {
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// PendingDiagnosis explains why a pod is not scheduled. Candidates are the
+// nodes that pass every check made here; when there are some, the pod is
+// held back by something not evaluated, such as inter-pod affinity or
+// topology spread, and the scheduler events say which.
+type PendingDiagnosis struct {
+	ClusterID  string             `json:"clusterID"`
+	Namespace  string             `json:"namespace"`
+	Name       string             `json:"name"`
+	Phase      string             `json:"phase"`
+	Scheduled  bool               `json:"scheduled"`
+	NodeName   string             `json:"nodeName,omitempty"`
+	Requests   Resources          `json:"requests"`
+	Reasons    []SchedulingReason `json:"reasons"`
+	Candidates []string           `json:"candidates"`
+	Events     []SchedulingEvent  `json:"events"`
+}
+
+// DiagnosePending checks a pod against every node and its volume claims to
+// explain why it is not scheduled
+func (p *CapacityProvider) DiagnosePending(ctx context.Context, clusterID, namespace, name string) (*PendingDiagnosis, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	pod, err := conn.Client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	diagnosis := &PendingDiagnosis{
+		ClusterID:  clusterID,
+		Namespace:  namespace,
+		Name:       name,
+		Phase:      string(pod.Status.Phase),
+		Scheduled:  pod.Spec.NodeName != "",
+		NodeName:   pod.Spec.NodeName,
+		Requests:   podResources(pod),
+		Reasons:    make([]SchedulingReason, 0),
+		Candidates: make([]string, 0),
+		Events:     make([]SchedulingEvent, 0),
+	}
+	if diagnosis.Scheduled {
+		return diagnosis, nil
+	}
+
+	events, err := conn.Client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{
+			"involvedObject.kind": "Pod",
+			"involvedObject.name": name,
+			"reason":              "FailedScheduling",
+		}).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	for _, event := range events.Items {
+		if event.InvolvedObject.UID != pod.UID {
+			continue
+		}
+		diagnosis.Events = append(diagnosis.Events, SchedulingEvent{
+			Message:  event.Message,
+			Count:    max(event.Count, 1),
+			LastSeen: eventTime(&event),
+		})
+	}
+	sort.Slice(diagnosis.Events, func(i, j int) bool {
+		return diagnosis.Events[i].LastSeen.After(diagnosis.Events[j].LastSeen)
+	})
+
+	// Claims are checked first: the scheduler waits on unbound claims
+	// before it looks at nodes
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+
+		claimName := volume.PersistentVolumeClaim.ClaimName
+		claim, err := conn.Client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, claimName, metav1.GetOptions{})
+		switch {
+		case err != nil:
+			diagnosis.Reasons = append(diagnosis.Reasons, SchedulingReason{
+				Code:    ReasonPVCNotFound,
+				Message: fmt.Sprintf("persistent volume claim %s can't be read: %v", claimName, err),
+			})
+		case claim.Status.Phase != corev1.ClaimBound && !waitsForConsumer(ctx, conn.Client, claim):
+			diagnosis.Reasons = append(diagnosis.Reasons, SchedulingReason{
+				Code:    ReasonPVCUnbound,
+				Message: fmt.Sprintf("persistent volume claim %s is %s", claimName, claim.Status.Phase),
+			})
+		}
+	}
+
+	nodes, err := conn.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	pods, err := conn.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	requested := requestedByNode(pods.Items)
+
+	if len(nodes.Items) == 0 {
+		diagnosis.Reasons = append(diagnosis.Reasons, SchedulingReason{
+			Code:    ReasonNoNodes,
+			Message: "the cluster has no nodes",
+		})
+	}
+
+	// Group the exclusions of every node by reason, keeping reasons in the
+	// order they were first seen
+	byCode := make(map[string]*SchedulingReason)
+	var codes []string
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		exclusions := NodeExclusions(pod, node, requested[node.Name])
+		if len(exclusions) == 0 {
+			diagnosis.Candidates = append(diagnosis.Candidates, node.Name)
+			continue
+		}
+
+		for _, exclusion := range exclusions {
+			reason, ok := byCode[exclusion.Code]
+			if !ok {
+				reason = &SchedulingReason{Code: exclusion.Code}
+				byCode[exclusion.Code] = reason
+				codes = append(codes, exclusion.Code)
+			}
+			reason.Nodes = append(reason.Nodes, node.Name)
+		}
+	}
+
+	for _, code := range codes {
+		reason := byCode[code]
+		sort.Strings(reason.Nodes)
+		reason.Message = fmt.Sprintf("%d of %d nodes: %s", len(reason.Nodes), len(nodes.Items), reasonSummaries[code])
+		diagnosis.Reasons = append(diagnosis.Reasons, *reason)
+	}
+	sort.Strings(diagnosis.Candidates)
+
+	// Nothing found here, so defer to what the scheduler last said
+	if len(diagnosis.Reasons) == 0 && len(diagnosis.Events) > 0 {
+		diagnosis.Reasons = append(diagnosis.Reasons, SchedulingReason{
+			Code:    ReasonScheduler,
+			Message: diagnosis.Events[0].Message,
+		})
+	}
+
+	return diagnosis, nil
+}
+
+// NodePlacement is whether a pod could run on a node. Preference sums the
+// weights of the pod's preferred node affinity terms the node matches.
+type NodePlacement struct {
+	Node       string      `json:"node"`
+	Fits       bool        `json:"fits"`
+	Preference int32       `json:"preference"`
+	Exclusions []Exclusion `json:"exclusions"`
+}
+
+// Placement is where a pod could be scheduled in a cluster. Nodes that fit
+// come first, most preferred first.
+type Placement struct {
+	ClusterID  string          `json:"clusterID"`
+	Requests   Resources       `json:"requests"`
+	Candidates []string        `json:"candidates"`
+	Nodes      []NodePlacement `json:"nodes"`
+}
+
+// Placement evaluates a pod against every node of a cluster with the same
+// checks as DiagnosePending. The pod doesn't have to exist, so a spec can be
+// tried out before it is applied.
+func (p *CapacityProvider) Placement(ctx context.Context, clusterID string, pod *corev1.Pod) (*Placement, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	nodes, err := conn.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	pods, err := conn.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	requested := requestedByNode(pods.Items)
+
+	placement := &Placement{
+		ClusterID:  clusterID,
+		Requests:   podResources(pod),
+		Candidates: make([]string, 0),
+		Nodes:      make([]NodePlacement, 0, len(nodes.Items)),
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		exclusions := NodeExclusions(pod, node, requested[node.Name])
+		if exclusions == nil {
+			exclusions = make([]Exclusion, 0)
+		}
+		placement.Nodes = append(placement.Nodes, NodePlacement{
+			Node:       node.Name,
+			Fits:       len(exclusions) == 0,
+			Preference: preferredAffinityWeight(pod, node),
+			Exclusions: exclusions,
+		})
+	}
+
+	sort.Slice(placement.Nodes, func(i, j int) bool {
+		a, b := placement.Nodes[i], placement.Nodes[j]
+		if a.Fits != b.Fits {
+			return a.Fits
+		}
+		if a.Preference != b.Preference {
+			return a.Preference > b.Preference
+		}
+		return a.Node < b.Node
+	})
+
+	for _, node := range placement.Nodes {
+		if node.Fits {
+			placement.Candidates = append(placement.Candidates, node.Node)
+		}
+	}
+
+	return placement, nil
+}
+
+// reasonSummaries describe node reasons across nodes
+var reasonSummaries = map[string]string{
+	ReasonInsufficientCPU:      "not enough free CPU for the pod's requests",
+	ReasonInsufficientMemory:   "not enough free memory for the pod's requests",
+	ReasonTooManyPods:          "no free pod slots",
+	ReasonUntoleratedTaint:     "taints the pod doesn't tolerate",
+	ReasonNodeSelectorMismatch: "labels don't match the pod's nodeSelector",
+	ReasonNodeAffinityMismatch: "labels don't match the pod's required node affinity",
+	ReasonNodeUnschedulable:    "cordoned",
+	ReasonNodeNotReady:         "not ready",
+}
+
+// NodeExclusions returns why a node can't run a pod, or nothing when it
+// can. requested is what pods already on the node request. Inter-pod
+// affinity and topology spread constraints are not evaluated.
+func NodeExclusions(pod *corev1.Pod, node *corev1.Node, requested Resources) []Exclusion {
+	var exclusions []Exclusion
+
+	if node.Spec.Unschedulable {
+		exclusions = append(exclusions, Exclusion{Code: ReasonNodeUnschedulable, Message: "node is cordoned"})
+	} else if !schedulable(node) {
+		exclusions = append(exclusions, Exclusion{Code: ReasonNodeNotReady, Message: "node is not ready"})
+	}
+
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || tolerates(pod.Spec.Tolerations, taint) {
+			continue
+		}
+		exclusions = append(exclusions, Exclusion{
+			Code:    ReasonUntoleratedTaint,
+			Message: fmt.Sprintf("taint %s is not tolerated", taint.ToString()),
+		})
+	}
+
+	for key, value := range pod.Spec.NodeSelector {
+		if actual, ok := node.Labels[key]; !ok || actual != value {
+			exclusions = append(exclusions, Exclusion{
+				Code:    ReasonNodeSelectorMismatch,
+				Message: fmt.Sprintf("nodeSelector %s=%s doesn't match", key, value),
+			})
+		}
+	}
+
+	if !matchesRequiredAffinity(pod, node) {
+		exclusions = append(exclusions, Exclusion{
+			Code:    ReasonNodeAffinityMismatch,
+			Message: "no required node affinity term matches",
+		})
+	}
+
+	allocatable := Resources{
+		CPUMillicores: node.Status.Allocatable.Cpu().MilliValue(),
+		MemoryBytes:   node.Status.Allocatable.Memory().Value(),
+		Pods:          node.Status.Allocatable.Pods().Value(),
+	}
+	free := allocatable.sub(requested)
+	needs := podResources(pod)
+
+	if free.CPUMillicores < needs.CPUMillicores {
+		exclusions = append(exclusions, Exclusion{
+			Code:    ReasonInsufficientCPU,
+			Message: fmt.Sprintf("requests %dm CPU, %dm free", needs.CPUMillicores, free.CPUMillicores),
+		})
+	}
+	if free.MemoryBytes < needs.MemoryBytes {
+		exclusions = append(exclusions, Exclusion{
+			Code:    ReasonInsufficientMemory,
+			Message: fmt.Sprintf("requests %d bytes of memory, %d free", needs.MemoryBytes, free.MemoryBytes),
+		})
+	}
+	if free.Pods < needs.Pods {
+		exclusions = append(exclusions, Exclusion{
+			Code:    ReasonTooManyPods,
+			Message: fmt.Sprintf("all %d pod slots are used", allocatable.Pods),
+		})
+	}
+
+	return exclusions
+}
+
+// requestedByNode sums the requests of the pods holding resources on each node
+func requestedByNode(pods []corev1.Pod) map[string]Resources {
+	requested := make(map[string]Resources)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		used := requested[pod.Spec.NodeName]
+		used.add(podResources(pod))
+		requested[pod.Spec.NodeName] = used
+	}
+	return requested
+}
+
+// tolerates reports whether any toleration matches a taint
+func tolerates(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRequiredAffinity reports whether a node satisfies one of the pod's
+// required node affinity terms. Terms are ORed, requirements within a term ANDed.
+func matchesRequiredAffinity(pod *corev1.Pod, node *corev1.Node) bool {
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if matchesTerm(term, node) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesTerm reports whether a node meets every requirement of a node
+// selector term. An empty term matches no nodes.
+func matchesTerm(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+
+	for _, requirement := range term.MatchExpressions {
+		if !matchesRequirement(requirement, node.Labels) {
+			return false
+		}
+	}
+
+	nodeFields := map[string]string{"metadata.name": node.Name}
+	for _, requirement := range term.MatchFields {
+		if !matchesRequirement(requirement, nodeFields) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// preferredAffinityWeight sums the weights of the preferred node affinity
+// terms a node matches
+func preferredAffinityWeight(pod *corev1.Pod, node *corev1.Node) int32 {
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil {
+		return 0
+	}
+
+	var weight int32
+	for _, term := range affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		if matchesTerm(term.Preference, node) {
+			weight += term.Weight
+		}
+	}
+	return weight
+}
+
+// matchesRequirement evaluates a node selector requirement against labels or fields
+func matchesRequirement(requirement corev1.NodeSelectorRequirement, values map[string]string) bool {
+	value, ok := values[requirement.Key]
+
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn:
+		return ok && slices.Contains(requirement.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !ok || !slices.Contains(requirement.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return ok
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !ok
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !ok || len(requirement.Values) != 1 {
+			return false
+		}
+		actual, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		bound, err := strconv.ParseInt(requirement.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if requirement.Operator == corev1.NodeSelectorOpGt {
+			return actual > bound
+		}
+		return actual < bound
+	}
+
+	return false
+}
+
+// waitsForConsumer reports whether a pending claim is only waiting for a pod
+// using it to be scheduled, as claims of WaitForFirstConsumer classes do
+func waitsForConsumer(ctx context.Context, client kubernetes.Interface, claim *corev1.PersistentVolumeClaim) bool {
+	if claim.Status.Phase != corev1.ClaimPending || claim.Spec.StorageClassName == nil || *claim.Spec.StorageClassName == "" {
+		return false
+	}
+
+	class, err := client.StorageV1().StorageClasses().Get(ctx, *claim.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	return class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer
+}
+
+// eventTime returns when an event was last seen
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -0,0 +1,192 @@
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// Certificate describes the leaf certificate of a TLS secret. Secrets
+// referenced by an ingress but missing or unreadable carry an Error instead.
+type Certificate struct {
+	ClusterID     string    `json:"clusterID"`
+	Namespace     string    `json:"namespace"`
+	Secret        string    `json:"secret"`
+	Subject       string    `json:"subject,omitempty"`
+	DNSNames      []string  `json:"dnsNames,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	NotBefore     time.Time `json:"notBefore,omitempty"`
+	NotAfter      time.Time `json:"notAfter,omitempty"`
+	DaysRemaining int       `json:"daysRemaining"`
+	Expired       bool      `json:"expired"`
+	Ingresses     []string  `json:"ingresses"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Report lists certificates, optionally only those expiring soon. Clusters
+// that could not be read are reported in Errors.
+type Report struct {
+	Within       string            `json:"within,omitempty"`
+	Certificates []Certificate     `json:"certificates"`
+	Errors       map[string]string `json:"errors,omitempty"`
+}
+
+// Parse reads the leaf certificate of a kubernetes.io/tls secret
+func Parse(secret *corev1.Secret, now time.Time) (Certificate, error) {
+	cert := Certificate{
+		Namespace: secret.Namespace,
+		Secret:    secret.Name,
+		Ingresses: make([]string, 0),
+	}
+
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return cert, errors.New("secret has no PEM certificate")
+	}
+
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return cert, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	cert.Subject = leaf.Subject.CommonName
+	cert.DNSNames = leaf.DNSNames
+	cert.Issuer = leaf.Issuer.CommonName
+	cert.NotBefore = leaf.NotBefore
+	cert.NotAfter = leaf.NotAfter
+	cert.DaysRemaining = int(leaf.NotAfter.Sub(now).Hours() / 24)
+	cert.Expired = now.After(leaf.NotAfter)
+
+	return cert, nil
+}
+
+// TLSSecretSelector selects kubernetes.io/tls secrets in list calls
+func TLSSecretSelector() string {
+	return fields.OneTermEqualSelector("type", string(corev1.SecretTypeTLS)).String()
+}
+
+// CertificateProvider reads certificates from TLS secrets and ingresses
+type CertificateProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewCertificateProvider creates a new provider
+func NewCertificateProvider(clusterManager *cluster.Manager) *CertificateProvider {
+	return &CertificateProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// List returns the certificates of a cluster, or one namespace, with the
+// ingresses that serve them. With within set only certificates expiring in
+// that window, or already expired, are returned.
+func (p *CertificateProvider) List(ctx context.Context, clusterID, namespace string, within time.Duration) ([]Certificate, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	secrets, err := conn.Client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{FieldSelector: TLSSecretSelector()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	ingresses, err := conn.Client.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	now := time.Now()
+	bySecret := make(map[string]*Certificate, len(secrets.Items))
+	for i := range secrets.Items {
+		cert, err := Parse(&secrets.Items[i], now)
+		if err != nil {
+			cert.Error = err.Error()
+		}
+		cert.ClusterID = clusterID
+		bySecret[cert.Namespace+"/"+cert.Secret] = &cert
+	}
+
+	for _, ingress := range ingresses.Items {
+		for _, tls := range ingress.Spec.TLS {
+			if tls.SecretName == "" {
+				continue
+			}
+
+			key := ingress.Namespace + "/" + tls.SecretName
+			cert, ok := bySecret[key]
+			if !ok {
+				cert = &Certificate{
+					ClusterID: clusterID,
+					Namespace: ingress.Namespace,
+					Secret:    tls.SecretName,
+					Ingresses: make([]string, 0),
+					Error:     "TLS secret not found",
+				}
+				bySecret[key] = cert
+			}
+			cert.Ingresses = append(cert.Ingresses, ingress.Name)
+		}
+	}
+
+	certs := make([]Certificate, 0, len(bySecret))
+	for _, cert := range bySecret {
+		if within > 0 && cert.Error == "" && cert.NotAfter.After(now.Add(within)) {
+			continue
+		}
+		certs = append(certs, *cert)
+	}
+
+	sortCertificates(certs)
+	return certs, nil
+}
+
+// ListAll lists certificates across the given clusters
+func (p *CertificateProvider) ListAll(ctx context.Context, clusterIDs []string, within time.Duration) *Report {
+	report := &Report{
+		Certificates: make([]Certificate, 0),
+	}
+	if within > 0 {
+		report.Within = within.String()
+	}
+
+	for _, clusterID := range clusterIDs {
+		certs, err := p.List(ctx, clusterID, "", within)
+		if err != nil {
+			if report.Errors == nil {
+				report.Errors = make(map[string]string)
+			}
+			report.Errors[clusterID] = err.Error()
+			continue
+		}
+		report.Certificates = append(report.Certificates, certs...)
+	}
+
+	sortCertificates(report.Certificates)
+	return report
+}
+
+// sortCertificates orders problems first, then by expiry
+func sortCertificates(certs []Certificate) {
+	sort.Slice(certs, func(i, j int) bool {
+		a, b := certs[i], certs[j]
+		if (a.Error != "") != (b.Error != "") {
+			return a.Error != ""
+		}
+		if !a.NotAfter.Equal(b.NotAfter) {
+			return a.NotAfter.Before(b.NotAfter)
+		}
+		return strings.Join([]string{a.ClusterID, a.Namespace, a.Secret}, "/") <
+			strings.Join([]string{b.ClusterID, b.Namespace, b.Secret}, "/")
+	})
+}
@@ -0,0 +1,171 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets/images"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Policy severities
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Policy checks one rule against a pod
+type Policy interface {
+	// Name identifies the policy in reports, configuration and exemptions
+	Name() string
+
+	// Severity is the severity of the policy's violations
+	Severity() string
+
+	// Check returns a message per violating container. An empty container
+	// name means the violation applies to the pod as a whole.
+	Check(pod *corev1.Pod) []Finding
+}
+
+// Finding is a single policy violation in a pod
+type Finding struct {
+	Container string
+	Message   string
+}
+
+// DefaultPolicies returns the built-in policy pack
+func DefaultPolicies() []Policy {
+	return []Policy{
+		missingLimitsPolicy{},
+		privilegedPolicy{},
+		hostPathPolicy{},
+		latestTagPolicy{},
+		missingProbesPolicy{},
+	}
+}
+
+// allContainers returns init and regular containers
+func allContainers(pod *corev1.Pod) []corev1.Container {
+	return append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+}
+
+// missingLimitsPolicy flags containers without CPU or memory limits
+type missingLimitsPolicy struct{}
+
+func (missingLimitsPolicy) Name() string     { return "missing-limits" }
+func (missingLimitsPolicy) Severity() string { return SeverityWarning }
+
+func (missingLimitsPolicy) Check(pod *corev1.Pod) []Finding {
+	var findings []Finding
+
+	for _, container := range allContainers(pod) {
+		var missing []string
+		if _, ok := container.Resources.Limits[corev1.ResourceCPU]; !ok {
+			missing = append(missing, "cpu")
+		}
+		if _, ok := container.Resources.Limits[corev1.ResourceMemory]; !ok {
+			missing = append(missing, "memory")
+		}
+
+		if len(missing) > 0 {
+			findings = append(findings, Finding{
+				Container: container.Name,
+				Message:   fmt.Sprintf("container %s has no %s limit", container.Name, strings.Join(missing, " or ")),
+			})
+		}
+	}
+
+	return findings
+}
+
+// privilegedPolicy flags privileged containers
+type privilegedPolicy struct{}
+
+func (privilegedPolicy) Name() string     { return "privileged" }
+func (privilegedPolicy) Severity() string { return SeverityCritical }
+
+func (privilegedPolicy) Check(pod *corev1.Pod) []Finding {
+	var findings []Finding
+
+	for _, container := range allContainers(pod) {
+		if sc := container.SecurityContext; sc != nil && sc.Privileged != nil && *sc.Privileged {
+			findings = append(findings, Finding{
+				Container: container.Name,
+				Message:   fmt.Sprintf("container %s runs privileged", container.Name),
+			})
+		}
+	}
+
+	return findings
+}
+
+// hostPathPolicy flags pods mounting directories from the node
+type hostPathPolicy struct{}
+
+func (hostPathPolicy) Name() string     { return "host-path" }
+func (hostPathPolicy) Severity() string { return SeverityCritical }
+
+func (hostPathPolicy) Check(pod *corev1.Pod) []Finding {
+	var findings []Finding
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			findings = append(findings, Finding{
+				Message: fmt.Sprintf("volume %s mounts host path %s", volume.Name, volume.HostPath.Path),
+			})
+		}
+	}
+
+	return findings
+}
+
+// latestTagPolicy flags images that float on the latest tag
+type latestTagPolicy struct{}
+
+func (latestTagPolicy) Name() string     { return "latest-tag" }
+func (latestTagPolicy) Severity() string { return SeverityWarning }
+
+func (latestTagPolicy) Check(pod *corev1.Pod) []Finding {
+	var findings []Finding
+
+	for _, container := range allContainers(pod) {
+		ref := images.ParseReference(container.Image)
+		if ref.Digest == "" && ref.Tag == "latest" {
+			findings = append(findings, Finding{
+				Container: container.Name,
+				Message:   fmt.Sprintf("container %s uses %s, which is not pinned to a version", container.Name, container.Image),
+			})
+		}
+	}
+
+	return findings
+}
+
+// missingProbesPolicy flags long-running containers without readiness or liveness probes
+type missingProbesPolicy struct{}
+
+func (missingProbesPolicy) Name() string     { return "missing-probes" }
+func (missingProbesPolicy) Severity() string { return SeverityWarning }
+
+func (missingProbesPolicy) Check(pod *corev1.Pod) []Finding {
+	var findings []Finding
+
+	for _, container := range pod.Spec.Containers {
+		var missing []string
+		if container.ReadinessProbe == nil {
+			missing = append(missing, "readiness")
+		}
+		if container.LivenessProbe == nil {
+			missing = append(missing, "liveness")
+		}
+
+		if len(missing) > 0 {
+			findings = append(findings, Finding{
+				Container: container.Name,
+				Message:   fmt.Sprintf("container %s has no %s probe", container.Name, strings.Join(missing, " or ")),
+			})
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,199 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExemptAnnotation lists the policies a pod or namespace is exempt from,
+// comma separated, or "*" for all of them
+const ExemptAnnotation = "compliance.dashboard.jbetancur.io/exempt"
+
+// ClusterConfig overrides the policy pack for one cluster
+type ClusterConfig struct {
+	Disabled         []string `yaml:"disabled"`
+	ExemptNamespaces []string `yaml:"exemptNamespaces"`
+}
+
+// Config selects the policies that run. Cluster entries add to the global settings.
+type Config struct {
+	Disabled         []string                 `yaml:"disabled"`
+	ExemptNamespaces []string                 `yaml:"exemptNamespaces"`
+	Clusters         map[string]ClusterConfig `yaml:"clusters"`
+}
+
+// ResourceLister reads stored resources
+type ResourceLister interface {
+	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
+}
+
+// Violation is a policy violation of a workload. Pods of the same workload
+// are reported once with the number of pods affected.
+type Violation struct {
+	Policy    string `json:"policy"`
+	Severity  string `json:"severity"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+	Pods      int    `json:"pods"`
+}
+
+// Report is the compliance of a cluster or namespace
+type Report struct {
+	ClusterID     string         `json:"clusterID"`
+	Namespace     string         `json:"namespace,omitempty"`
+	Policies      []string       `json:"policies"`
+	PodsChecked   int            `json:"podsChecked"`
+	PodsCompliant int            `json:"podsCompliant"`
+	Summary       map[string]int `json:"summary"`
+	Violations    []Violation    `json:"violations"`
+}
+
+// ComplianceProvider checks stored pods against the policy pack
+type ComplianceProvider struct {
+	store    ResourceLister
+	policies []Policy
+	config   Config
+}
+
+// NewComplianceProvider creates a new provider with the built-in policies
+func NewComplianceProvider(store ResourceLister, config Config) *ComplianceProvider {
+	return &ComplianceProvider{
+		store:    store,
+		policies: DefaultPolicies(),
+		config:   config,
+	}
+}
+
+// Check runs the policies enabled for a cluster against its stored pods, or
+// those of one namespace. Completed job pods are skipped and exemptions are
+// read from pod and namespace annotations.
+func (p *ComplianceProvider) Check(ctx context.Context, clusterID, namespace string) (*Report, error) {
+	var pods []corev1.Pod
+	if err := p.store.List(ctx, clusterID, namespace, "Pod", &pods); err != nil {
+		return nil, fmt.Errorf("failed to list stored pods: %w", err)
+	}
+
+	var namespaces []corev1.Namespace
+	if err := p.store.List(ctx, clusterID, "", "Namespace", &namespaces); err != nil {
+		return nil, fmt.Errorf("failed to list stored namespaces: %w", err)
+	}
+
+	namespaceExemptions := make(map[string][]string, len(namespaces))
+	for _, ns := range namespaces {
+		namespaceExemptions[ns.Name] = exemptions(ns.Annotations)
+	}
+
+	clusterConfig := p.config.Clusters[clusterID]
+	exemptNamespaces := append(append([]string{}, p.config.ExemptNamespaces...), clusterConfig.ExemptNamespaces...)
+
+	policies := make([]Policy, 0, len(p.policies))
+	report := &Report{
+		ClusterID:  clusterID,
+		Namespace:  namespace,
+		Policies:   make([]string, 0, len(p.policies)),
+		Summary:    make(map[string]int),
+		Violations: make([]Violation, 0),
+	}
+	for _, policy := range p.policies {
+		if slices.Contains(p.config.Disabled, policy.Name()) || slices.Contains(clusterConfig.Disabled, policy.Name()) {
+			continue
+		}
+		policies = append(policies, policy)
+		report.Policies = append(report.Policies, policy.Name())
+	}
+
+	byKey := make(map[string]*Violation)
+	for i := range pods {
+		pod := &pods[i]
+		if slices.Contains(exemptNamespaces, pod.Namespace) || finished(pod) {
+			continue
+		}
+
+		report.PodsChecked++
+		exempt := append(exemptions(pod.Annotations), namespaceExemptions[pod.Namespace]...)
+		kind, name := assets.WorkloadOf(pod)
+
+		compliant := true
+		for _, policy := range policies {
+			if slices.Contains(exempt, "*") || slices.Contains(exempt, policy.Name()) {
+				continue
+			}
+
+			for _, finding := range policy.Check(pod) {
+				compliant = false
+
+				key := strings.Join([]string{policy.Name(), kind, pod.Namespace, name, finding.Container, finding.Message}, "/")
+				if violation, ok := byKey[key]; ok {
+					violation.Pods++
+					continue
+				}
+
+				byKey[key] = &Violation{
+					Policy:    policy.Name(),
+					Severity:  policy.Severity(),
+					Kind:      kind,
+					Namespace: pod.Namespace,
+					Name:      name,
+					Container: finding.Container,
+					Message:   finding.Message,
+					Pods:      1,
+				}
+			}
+		}
+
+		if compliant {
+			report.PodsCompliant++
+		}
+	}
+
+	for _, violation := range byKey {
+		report.Summary[violation.Policy]++
+		report.Violations = append(report.Violations, *violation)
+	}
+
+	sort.Slice(report.Violations, func(i, j int) bool {
+		a, b := report.Violations[i], report.Violations[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		return a.Container < b.Container
+	})
+
+	return report, nil
+}
+
+// exemptions parses the exemption annotation
+func exemptions(annotations map[string]string) []string {
+	value, ok := annotations[ExemptAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// finished reports pods that ran to completion, whose spec no longer matters
+func finished(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
@@ -67,6 +67,10 @@ func (pm *Manager) StartInformer() error {
 			}
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			if resources.Unchanged(oldObj, newObj) {
+				return
+			}
+
 			configMap := newObj.(*v1.ConfigMap)
 			payload := resources.ResourcePayload[v1.ConfigMap]{
 				ClusterID: pm.clusterID,
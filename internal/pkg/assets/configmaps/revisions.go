@@ -0,0 +1,107 @@
+package configmaps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/revision"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Key change operations
+const (
+	OpAdded   = "added"
+	OpRemoved = "removed"
+	OpChanged = "changed"
+)
+
+// RevisionStore reads and writes config map revisions
+type RevisionStore interface {
+	SaveRevision(ctx context.Context, rev *revision.Revision) error
+	GetRevision(ctx context.Context, clusterID, namespace, kind, name string, version int, result *revision.Revision) error
+}
+
+// RecordRevision stores the data of a config map as a new revision unless it
+// matches the latest one, so repeated events for the same content are
+// recorded once. It reports whether a revision was added.
+func RecordRevision(ctx context.Context, revisions RevisionStore, clusterID string, configMap *v1.ConfigMap, createdBy string) (bool, error) {
+	var latest revision.Revision
+	err := revisions.GetRevision(ctx, clusterID, configMap.Namespace, "ConfigMap", configMap.Name, 0, &latest)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+	case err != nil:
+		return false, err
+	case maps.Equal(latest.Data, configMap.Data) && maps.EqualFunc(latest.BinaryData, configMap.BinaryData, bytes.Equal):
+		return false, nil
+	}
+
+	rev := &revision.Revision{
+		ClusterID:       clusterID,
+		Namespace:       configMap.Namespace,
+		Kind:            "ConfigMap",
+		Name:            configMap.Name,
+		Version:         latest.Version + 1,
+		ResourceVersion: configMap.ResourceVersion,
+		CreatedAt:       time.Now(),
+		CreatedBy:       createdBy,
+		Data:            configMap.Data,
+		BinaryData:      configMap.BinaryData,
+	}
+	if err := revisions.SaveRevision(ctx, rev); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// KeyChange is a key whose value differs between two revisions. Binary
+// values are summarized by size.
+type KeyChange struct {
+	Key  string `json:"key"`
+	Op   string `json:"op"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// DiffRevisions returns the keys that changed between two revisions, ordered by key
+func DiffRevisions(from, to *revision.Revision) []KeyChange {
+	changes := make([]KeyChange, 0)
+	changes = appendChanges(changes, from.Data, to.Data,
+		func(a, b string) bool { return a == b },
+		func(value string) string { return value })
+	changes = appendChanges(changes, from.BinaryData, to.BinaryData, bytes.Equal,
+		func(value []byte) string { return fmt.Sprintf("<%d bytes>", len(value)) })
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// appendChanges compares one of the data maps of two revisions
+func appendChanges[V any](changes []KeyChange, from, to map[string]V, equal func(a, b V) bool, format func(V) string) []KeyChange {
+	for key, value := range from {
+		if _, ok := to[key]; !ok {
+			changes = append(changes, KeyChange{Key: key, Op: OpRemoved, From: format(value)})
+		}
+	}
+
+	for key, value := range to {
+		previous, ok := from[key]
+		switch {
+		case !ok:
+			changes = append(changes, KeyChange{Key: key, Op: OpAdded, To: format(value)})
+		case !equal(previous, value):
+			changes = append(changes, KeyChange{Key: key, Op: OpChanged, From: format(previous), To: format(value)})
+		}
+	}
+
+	return changes
+}
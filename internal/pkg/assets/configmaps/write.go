@@ -0,0 +1,125 @@
+package configmaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// MaxSize is the most data a config map may hold, the limit etcd enforces on objects
+const MaxSize = 1 << 20
+
+// ErrInvalid is returned for config maps the cluster would reject
+var ErrInvalid = errors.New("invalid config map")
+
+// Validate checks a config map's name, keys and size before it is sent to the cluster
+func Validate(configMap *v1.ConfigMap) error {
+	var problems []string
+	for _, msg := range validation.IsDNS1123Subdomain(configMap.Name) {
+		problems = append(problems, "name: "+msg)
+	}
+
+	size := 0
+	for key, value := range configMap.Data {
+		for _, msg := range validation.IsConfigMapKey(key) {
+			problems = append(problems, fmt.Sprintf("data key %q: %s", key, msg))
+		}
+		size += len(key) + len(value)
+	}
+	for key, value := range configMap.BinaryData {
+		for _, msg := range validation.IsConfigMapKey(key) {
+			problems = append(problems, fmt.Sprintf("binaryData key %q: %s", key, msg))
+		}
+		if _, ok := configMap.Data[key]; ok {
+			problems = append(problems, fmt.Sprintf("key %q is in both data and binaryData", key))
+		}
+		size += len(key) + len(value)
+	}
+
+	if size > MaxSize {
+		problems = append(problems, fmt.Sprintf("data is %d bytes, more than the %d allowed", size, MaxSize))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalid, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CreateConfigMap validates and creates a config map in a namespace. With
+// dryRun the cluster validates and returns it without storing it.
+func (p *ConfigMapProvider) CreateConfigMap(ctx context.Context, clusterID, namespace string, configMap *v1.ConfigMap, dryRun bool) (*v1.ConfigMap, error) {
+	if err := Validate(configMap); err != nil {
+		return nil, err
+	}
+
+	cluster, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	configMap.Namespace = namespace
+	created, err := cluster.Client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{DryRun: dryRunOption(dryRun)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config map: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateConfigMap validates and replaces a config map. The cluster rejects the
+// update with a conflict unless the config map's resourceVersion is current.
+func (p *ConfigMapProvider) UpdateConfigMap(ctx context.Context, clusterID, namespace string, configMap *v1.ConfigMap, dryRun bool) (*v1.ConfigMap, error) {
+	if configMap.ResourceVersion == "" {
+		return nil, fmt.Errorf("%w: metadata.resourceVersion is required to update", ErrInvalid)
+	}
+	if err := Validate(configMap); err != nil {
+		return nil, err
+	}
+
+	cluster, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	configMap.Namespace = namespace
+	updated, err := cluster.Client.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update config map: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteConfigMap deletes a config map. A non-empty resourceVersion makes the
+// delete fail with a conflict if the config map changed since it was read.
+func (p *ConfigMapProvider) DeleteConfigMap(ctx context.Context, clusterID, namespace, name, resourceVersion string, dryRun bool) error {
+	cluster, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("cluster not found: %w", err)
+	}
+
+	opts := metav1.DeleteOptions{DryRun: dryRunOption(dryRun)}
+	if resourceVersion != "" {
+		opts.Preconditions = &metav1.Preconditions{ResourceVersion: &resourceVersion}
+	}
+
+	if err := cluster.Client.CoreV1().ConfigMaps(namespace).Delete(ctx, name, opts); err != nil {
+		return fmt.Errorf("failed to delete config map: %w", err)
+	}
+
+	return nil
+}
+
+// dryRunOption is the DryRun write option for a dry-run flag
+func dryRunOption(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hoursPerMonth is the average number of hours in a month
+const hoursPerMonth = 730
+
+// instanceTypeLabel is the well-known node label holding the machine type
+const instanceTypeLabel = "node.kubernetes.io/instance-type"
+
+// Group by options
+const (
+	GroupByNamespace = "namespace"
+	GroupByWorkload  = "workload"
+)
+
+// Rates are hourly prices for one CPU core and one GiB of memory
+type Rates struct {
+	CPUHourly      float64 `yaml:"cpuHourly" json:"cpuHourly"`
+	MemoryGBHourly float64 `yaml:"memoryGBHourly" json:"memoryGBHourly"`
+}
+
+// PricingConfig holds default rates and overrides per node instance type
+type PricingConfig struct {
+	Currency      string           `yaml:"currency"`
+	Default       Rates            `yaml:"default"`
+	InstanceTypes map[string]Rates `yaml:"instanceTypes"`
+	// UseLimits prices containers without requests by their limits
+	UseLimits bool `yaml:"useLimits"`
+}
+
+// Item is the estimated cost of a namespace or workload
+type Item struct {
+	Namespace    string  `json:"namespace"`
+	WorkloadKind string  `json:"workloadKind,omitempty"`
+	Workload     string  `json:"workload,omitempty"`
+	Pods         int     `json:"pods"`
+	CPUCores     float64 `json:"cpuCores"`
+	MemoryGB     float64 `json:"memoryGB"`
+	Hourly       float64 `json:"hourly"`
+	Monthly      float64 `json:"monthly"`
+}
+
+// Report is a cost estimate for a cluster
+type Report struct {
+	ClusterID string  `json:"clusterID"`
+	Currency  string  `json:"currency"`
+	GroupBy   string  `json:"groupBy"`
+	Hourly    float64 `json:"hourly"`
+	Monthly   float64 `json:"monthly"`
+	Items     []Item  `json:"items"`
+}
+
+// CostProvider estimates cost from pod requests and the rates of the nodes they run on
+type CostProvider struct {
+	clusterManager *cluster.Manager
+	pricing        PricingConfig
+}
+
+// NewCostProvider creates a new provider
+func NewCostProvider(clusterManager *cluster.Manager, pricing PricingConfig) *CostProvider {
+	if pricing.Currency == "" {
+		pricing.Currency = "USD"
+	}
+
+	return &CostProvider{
+		clusterManager: clusterManager,
+		pricing:        pricing,
+	}
+}
+
+// Estimate returns the cost of running pods grouped by namespace or workload,
+// optionally limited to one namespace
+func (p *CostProvider) Estimate(ctx context.Context, clusterID, namespace, groupBy string) (*Report, error) {
+	if groupBy != GroupByNamespace && groupBy != GroupByWorkload {
+		return nil, fmt.Errorf("unsupported groupBy %q", groupBy)
+	}
+
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	nodes, err := conn.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	nodeRates := make(map[string]Rates, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeRates[node.Name] = p.ratesFor(&node)
+	}
+
+	pods, err := conn.Client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	items := make(map[string]*Item)
+	report := &Report{
+		ClusterID: clusterID,
+		Currency:  p.pricing.Currency,
+		GroupBy:   groupBy,
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		rates, ok := nodeRates[pod.Spec.NodeName]
+		if !ok {
+			rates = p.pricing.Default
+		}
+
+		requests := assets.PodRequests(pod, p.pricing.UseLimits)
+		cpu := float64(requests.Cpu().MilliValue()) / 1000
+		memory := float64(requests.Memory().Value()) / (1 << 30)
+		hourly := cpu*rates.CPUHourly + memory*rates.MemoryGBHourly
+
+		key := pod.Namespace
+		item := Item{Namespace: pod.Namespace}
+		if groupBy == GroupByWorkload {
+			item.WorkloadKind, item.Workload = assets.WorkloadOf(pod)
+			key = pod.Namespace + "/" + item.WorkloadKind + "/" + item.Workload
+		}
+
+		existing, ok := items[key]
+		if !ok {
+			existing = &item
+			items[key] = existing
+		}
+
+		existing.Pods++
+		existing.CPUCores += cpu
+		existing.MemoryGB += memory
+		existing.Hourly += hourly
+		report.Hourly += hourly
+	}
+
+	report.Items = make([]Item, 0, len(items))
+	for _, item := range items {
+		item.Monthly = item.Hourly * hoursPerMonth
+		report.Items = append(report.Items, *item)
+	}
+	report.Monthly = report.Hourly * hoursPerMonth
+
+	// Most expensive first
+	sort.Slice(report.Items, func(i, j int) bool {
+		return report.Items[i].Hourly > report.Items[j].Hourly
+	})
+
+	return report, nil
+}
+
+// ratesFor returns the rates of a node's instance type, falling back to the defaults
+func (p *CostProvider) ratesFor(node *corev1.Node) Rates {
+	if rates, ok := p.pricing.InstanceTypes[node.Labels[instanceTypeLabel]]; ok {
+		return rates
+	}
+
+	return p.pricing.Default
+}
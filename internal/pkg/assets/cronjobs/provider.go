@@ -0,0 +1,95 @@
+package cronjobs
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	// instantiateAnnotation marks jobs created by hand, as kubectl create job --from does
+	instantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+	// TriggeredByAnnotation records the dashboard user who triggered a job
+	TriggeredByAnnotation = "jobs.dashboard.jbetancur.io/triggered-by"
+	// maxNameLen is the longest job name, since it becomes a pod label value
+	maxNameLen = 63
+)
+
+// CronJobProvider runs cron jobs in multiple clusters
+type CronJobProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewCronJobProvider creates a new provider
+func NewCronJobProvider(clusterManager *cluster.Manager) *CronJobProvider {
+	return &CronJobProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// TriggerCronJob creates a job from a cron job's template outside of its
+// schedule, like kubectl create job --from=cronjob/<name>. The job is owned
+// by the cron job, so it is cleaned up with it, and is annotated with the
+// user who triggered it. With dryRun the cluster validates the job without
+// creating it.
+func (p *CronJobProvider) TriggerCronJob(ctx context.Context, clusterID, namespace, name, user string, dryRun bool) (*batchv1.Job, error) {
+	cluster, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	cronJob, err := cluster.Client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cron job: %w", err)
+	}
+
+	template := cronJob.Spec.JobTemplate
+	annotations := maps.Clone(template.Annotations)
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[instantiateAnnotation] = "manual"
+	if user != "" {
+		annotations[TriggeredByAnnotation] = user
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        jobName(name),
+			Namespace:   namespace,
+			Labels:      maps.Clone(template.Labels),
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+
+	var opts metav1.CreateOptions
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	created, err := cluster.Client.BatchV1().Jobs(namespace).Create(ctx, job, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return created, nil
+}
+
+// jobName names a manually triggered job the way kubectl does, with a random
+// suffix so the cron job can be triggered again
+func jobName(cronJob string) string {
+	suffix := "-manual-" + utilrand.String(5)
+	if len(cronJob)+len(suffix) > maxNameLen {
+		cronJob = strings.TrimRight(cronJob[:maxNameLen-len(suffix)], "-.")
+	}
+	return cronJob + suffix
+}
@@ -0,0 +1,158 @@
+package deployments
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	resources "github.com/jbetancur/dashboard/internal/pkg/assets"
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	"github.com/jbetancur/dashboard/internal/pkg/throttle"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ImageChangePayload is published when the container images of a
+// deployment's pod template change
+type ImageChangePayload struct {
+	ClusterID string            `json:"cluster_id"`
+	Resource  appsv1.Deployment `json:"resource"`
+	// PreviousImages and Images map container names to images
+	PreviousImages map[string]string `json:"previous_images"`
+	Images         map[string]string `json:"images"`
+}
+
+// Manager publishes deployment lifecycle events. Deployments are not stored
+// by the API; their events feed webhooks.
+type Manager struct {
+	clusterID      string
+	client         *kubernetes.Clientset
+	informer       informers.SharedInformerFactory
+	eventPublisher messagingtypes.Publisher
+	throttle       *throttle.Throttle
+	logger         *slog.Logger
+	stopCh         chan struct{}
+}
+
+// NewManager creates a new Manager
+func NewManager(
+	clusterID string,
+	eventPublisher messagingtypes.Publisher,
+	client *kubernetes.Clientset,
+	throttle *throttle.Throttle,
+	logger *slog.Logger,
+) *Manager {
+	// Create a shared informer factory
+	informer := informers.NewSharedInformerFactory(client, time.Minute*5)
+
+	return &Manager{
+		clusterID:      clusterID,
+		client:         client,
+		informer:       informer,
+		eventPublisher: eventPublisher,
+		throttle:       throttle,
+		logger:         logger,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// StartInformer starts the deployment informer
+func (dm *Manager) StartInformer() error {
+	deploymentInformer := dm.informer.Apps().V1().Deployments().Informer()
+	if _, err := deploymentInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			dm.throttle.Record(obj)
+			dm.publish("deployment_added", obj.(*appsv1.Deployment))
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Resyncs are skipped unless an update of the object was dropped under load
+			if resources.Unchanged(oldObj, newObj) && !dm.throttle.Stale(newObj) {
+				return
+			}
+			if !dm.throttle.AllowUpdate(newObj, resources.StatusOnly(oldObj, newObj)) {
+				return
+			}
+
+			oldDeployment := oldObj.(*appsv1.Deployment)
+			deployment := newObj.(*appsv1.Deployment)
+			dm.publish("deployment_updated", deployment)
+
+			previous, current := Images(oldDeployment), Images(deployment)
+			if maps.Equal(previous, current) {
+				return
+			}
+
+			data, err := json.Marshal(ImageChangePayload{
+				ClusterID:      dm.clusterID,
+				Resource:       *deployment,
+				PreviousImages: previous,
+				Images:         current,
+			})
+			if err != nil {
+				dm.logger.Error("failed to serialize deployment image change", "error", err)
+				return
+			}
+			if err := dm.eventPublisher.Publish("deployment_image_changed", data); err != nil {
+				dm.logger.Error("failed to publish deployment image change", "error", err)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			dm.throttle.Record(obj)
+			dm.publish("deployment_deleted", obj.(*appsv1.Deployment))
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add deployment event handler: %w", err)
+	}
+
+	// Start the informer
+	go deploymentInformer.Run(dm.stopCh)
+
+	// Wait for the cache to sync
+	if !cache.WaitForCacheSync(dm.stopCh, deploymentInformer.HasSynced) {
+		return fmt.Errorf("failed to sync deployment informer")
+	}
+
+	return nil
+}
+
+// Stop stops the deployment manager
+func (dm *Manager) Stop() {
+	close(dm.stopCh)
+}
+
+// publish sends a deployment on a topic
+func (dm *Manager) publish(topic string, deployment *appsv1.Deployment) {
+	payload := resources.ResourcePayload[appsv1.Deployment]{
+		ClusterID: dm.clusterID,
+		Resource:  *deployment,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		dm.logger.Error("failed to serialize deployment", "error", err)
+		return
+	}
+	if err := dm.eventPublisher.Publish(topic, data); err != nil {
+		dm.logger.Error("failed to publish deployment event", "topic", topic, "error", err)
+	}
+}
+
+// Images maps the containers of a deployment's pod template, init
+// containers included, to their images
+func Images(deployment *appsv1.Deployment) map[string]string {
+	spec := deployment.Spec.Template.Spec
+
+	images := make(map[string]string, len(spec.InitContainers)+len(spec.Containers))
+	for _, container := range spec.InitContainers {
+		images[container.Name] = container.Image
+	}
+	for _, container := range spec.Containers {
+		images[container.Name] = container.Image
+	}
+	return images
+}
@@ -0,0 +1,199 @@
+package deployments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets/images"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RestartedAtAnnotation is the pod template annotation kubectl rollout restart sets
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// Errors returned for image changes the provider refuses
+var (
+	ErrInvalid            = errors.New("invalid image change")
+	ErrContainerNotFound  = errors.New("container not found")
+	ErrRepositoryMismatch = errors.New("image repository doesn't match")
+)
+
+// SetImageRequest changes the image of one container of a deployment.
+// Container may be left empty for deployments with a single container.
+type SetImageRequest struct {
+	Container string `json:"container,omitempty"`
+	Image     string `json:"image"`
+	// Force allows an image from another repository than the current one
+	Force bool `json:"force,omitempty"`
+}
+
+// SetImageResult is the deployment after an image change and what changed
+type SetImageResult struct {
+	Container     string             `json:"container"`
+	PreviousImage string             `json:"previousImage"`
+	Image         string             `json:"image"`
+	Deployment    *appsv1.Deployment `json:"deployment"`
+}
+
+// ScaleRequest sets the replica count of a deployment
+type ScaleRequest struct {
+	Replicas *int32 `json:"replicas"`
+}
+
+// ScaleResult is the deployment's scale after a change and the count it replaced
+type ScaleResult struct {
+	PreviousReplicas int32                `json:"previousReplicas"`
+	Replicas         int32                `json:"replicas"`
+	Scale            *autoscalingv1.Scale `json:"scale"`
+}
+
+// DeploymentProvider changes deployments in multiple clusters
+type DeploymentProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewDeploymentProvider creates a new provider
+func NewDeploymentProvider(clusterManager *cluster.Manager) *DeploymentProvider {
+	return &DeploymentProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// GetDeployment gets a deployment from a specific cluster
+func (p *DeploymentProvider) GetDeployment(ctx context.Context, clusterID, namespace, name string) (*appsv1.Deployment, error) {
+	cluster, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	deployment, err := cluster.Client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	return deployment, nil
+}
+
+// SetImage changes the image of a container in a deployment's pod template,
+// rolling out new pods. The new image must come from the same registry and
+// repository as the current one unless the request is forced, so a typo
+// can't swap in an unrelated image. With dryRun the cluster validates the
+// change without storing it.
+func (p *DeploymentProvider) SetImage(ctx context.Context, clusterID, namespace, name string, req SetImageRequest, dryRun bool) (*SetImageResult, error) {
+	if req.Image == "" {
+		return nil, fmt.Errorf("%w: image is required", ErrInvalid)
+	}
+
+	cluster, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	client := cluster.Client.AppsV1().Deployments(namespace)
+	deployment, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	containers := deployment.Spec.Template.Spec.Containers
+	index := -1
+	switch {
+	case req.Container != "":
+		for i := range containers {
+			if containers[i].Name == req.Container {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s has no container %q", ErrContainerNotFound, name, req.Container)
+		}
+	case len(containers) == 1:
+		index = 0
+	default:
+		return nil, fmt.Errorf("%w: %s has %d containers, name the one to change", ErrInvalid, name, len(containers))
+	}
+
+	container := &containers[index]
+	result := &SetImageResult{Container: container.Name, PreviousImage: container.Image, Image: req.Image}
+
+	previous, next := images.ParseReference(container.Image).Name(), images.ParseReference(req.Image).Name()
+	if previous != next && !req.Force {
+		return nil, fmt.Errorf("%w: %s is from %s, not %s; set force to change it", ErrRepositoryMismatch, container.Name, previous, next)
+	}
+
+	// The update carries the resourceVersion read above, so a concurrent
+	// change to the deployment fails with a conflict instead of being lost
+	container.Image = req.Image
+	var opts metav1.UpdateOptions
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	result.Deployment, err = client.Update(ctx, deployment, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update deployment: %w", err)
+	}
+
+	return result, nil
+}
+
+// Scale sets the replica count of a deployment through its scale subresource.
+// With dryRun the cluster validates the change without storing it.
+func (p *DeploymentProvider) Scale(ctx context.Context, clusterID, namespace, name string, req ScaleRequest, dryRun bool) (*ScaleResult, error) {
+	if req.Replicas == nil || *req.Replicas < 0 {
+		return nil, fmt.Errorf("%w: replicas must be zero or more", ErrInvalid)
+	}
+
+	cluster, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	client := cluster.Client.AppsV1().Deployments(namespace)
+	scale, err := client.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment scale: %w", err)
+	}
+
+	result := &ScaleResult{PreviousReplicas: scale.Spec.Replicas, Replicas: *req.Replicas}
+
+	scale.Spec.Replicas = *req.Replicas
+	var opts metav1.UpdateOptions
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	result.Scale, err = client.UpdateScale(ctx, name, scale, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scale deployment: %w", err)
+	}
+
+	return result, nil
+}
+
+// Restart rolls out new pods for a deployment like kubectl rollout restart,
+// by stamping the current time on its pod template. With dryRun the cluster
+// validates the change without storing it.
+func (p *DeploymentProvider) Restart(ctx context.Context, clusterID, namespace, name string, dryRun bool) (*appsv1.Deployment, error) {
+	cluster, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		RestartedAtAnnotation, time.Now().Format(time.RFC3339))
+	var opts metav1.PatchOptions
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	deployment, err := cluster.Client.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restart deployment: %w", err)
+	}
+
+	return deployment, nil
+}
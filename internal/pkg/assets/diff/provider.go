@@ -0,0 +1,262 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Change operations
+const (
+	OpAdded   = "added"
+	OpRemoved = "removed"
+	OpChanged = "changed"
+)
+
+// storedKinds are the kinds the agents mirror into the store
+var storedKinds = map[string]func() runtime.Object{
+	"Pod":       func() runtime.Object { return &corev1.Pod{} },
+	"ConfigMap": func() runtime.Object { return &corev1.ConfigMap{} },
+	"Namespace": func() runtime.Object { return &corev1.Namespace{} },
+}
+
+// Change is a single difference between the stored and live object.
+// Added means the field is only on the live object.
+type Change struct {
+	Path   string      `json:"path"`
+	Op     string      `json:"op"`
+	Stored interface{} `json:"stored,omitempty"`
+	Live   interface{} `json:"live,omitempty"`
+}
+
+// Result compares the stored copy of an object with the cluster
+type Result struct {
+	ClusterID             string   `json:"clusterID"`
+	Kind                  string   `json:"kind"`
+	Namespace             string   `json:"namespace,omitempty"`
+	Name                  string   `json:"name"`
+	Stored                bool     `json:"stored"`
+	Live                  bool     `json:"live"`
+	StoredResourceVersion string   `json:"storedResourceVersion,omitempty"`
+	LiveResourceVersion   string   `json:"liveResourceVersion,omitempty"`
+	Stale                 bool     `json:"stale"`
+	Changes               []Change `json:"changes"`
+}
+
+// DiffProvider compares stored objects with the live cluster
+type DiffProvider struct {
+	clusterManager *cluster.Manager
+	store          store.Repository
+}
+
+// NewDiffProvider creates a new provider
+func NewDiffProvider(clusterManager *cluster.Manager, store store.Repository) *DiffProvider {
+	return &DiffProvider{
+		clusterManager: clusterManager,
+		store:          store,
+	}
+}
+
+// Diff fetches an object from the cluster and the store and returns the
+// differences. Managed fields are ignored since they churn on every write.
+func (p *DiffProvider) Diff(ctx context.Context, clusterID, kind, namespace, name string) (*Result, error) {
+	newObject, ok := storedKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("kind %q is not stored", kind)
+	}
+
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	result := &Result{
+		ClusterID: clusterID,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Changes:   make([]Change, 0),
+	}
+
+	var stored map[string]interface{}
+	storedObj := newObject()
+	err = p.store.Get(ctx, clusterID, namespace, kind, name, storedObj)
+	switch {
+	case err == nil:
+		result.Stored = true
+		if stored, result.StoredResourceVersion, err = content(storedObj); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, store.ErrNotFound):
+		return nil, fmt.Errorf("failed to read stored object: %w", err)
+	}
+
+	var live map[string]interface{}
+	liveObj, err := p.getLive(ctx, conn, kind, namespace, name)
+	switch {
+	case err == nil:
+		result.Live = true
+		if live, result.LiveResourceVersion, err = content(liveObj); err != nil {
+			return nil, err
+		}
+	case !apierrors.IsNotFound(err):
+		return nil, err
+	}
+
+	if !result.Stored && !result.Live {
+		return nil, fmt.Errorf("%s %s not found in store or cluster", kind, name)
+	}
+
+	result.Stale = result.Stored != result.Live || result.StoredResourceVersion != result.LiveResourceVersion
+	result.Changes = Compare(stored, live)
+
+	return result, nil
+}
+
+// getLive reads an object from the cluster
+func (p *DiffProvider) getLive(ctx context.Context, conn *cluster.Connection, kind, namespace, name string) (runtime.Object, error) {
+	if kind == "Namespace" {
+		obj, err := conn.Client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+		return obj, nil
+	}
+
+	return assets.GetObject(ctx, conn.Client, kind, namespace, name)
+}
+
+// content converts an object to a comparable map and returns its resource version
+func content(obj runtime.Object) (map[string]interface{}, string, error) {
+	meta, err := apimeta.Accessor(obj)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read object metadata: %w", err)
+	}
+	meta.SetManagedFields(nil)
+
+	converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to convert object: %w", err)
+	}
+
+	// Both sides are typed objects, so apiVersion and kind are not compared
+	delete(converted, "apiVersion")
+	delete(converted, "kind")
+
+	return converted, meta.GetResourceVersion(), nil
+}
+
+// Compare returns the differences between two unstructured objects, with
+// fields ordered by path. Lists are compared by index.
+func Compare(stored, live map[string]interface{}) []Change {
+	changes := make([]Change, 0)
+	compareValues("", stored, live, &changes)
+	sortChanges(changes)
+
+	return changes
+}
+
+// sortChanges orders changes by path
+func sortChanges(changes []Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+}
+
+// compareValues appends the differences between two values at path
+func compareValues(path string, stored, live interface{}, changes *[]Change) {
+	switch {
+	case stored == nil && live == nil:
+		return
+	case stored == nil:
+		*changes = append(*changes, Change{Path: path, Op: OpAdded, Live: live})
+		return
+	case live == nil:
+		*changes = append(*changes, Change{Path: path, Op: OpRemoved, Stored: stored})
+		return
+	}
+
+	storedMap, storedIsMap := stored.(map[string]interface{})
+	liveMap, liveIsMap := live.(map[string]interface{})
+	if storedIsMap && liveIsMap {
+		keys := make(map[string]bool, len(storedMap)+len(liveMap))
+		for key := range storedMap {
+			keys[key] = true
+		}
+		for key := range liveMap {
+			keys[key] = true
+		}
+
+		for key := range keys {
+			compareValues(join(path, key), storedMap[key], liveMap[key], changes)
+		}
+		return
+	}
+
+	storedList, storedIsList := stored.([]interface{})
+	liveList, liveIsList := live.([]interface{})
+	if storedIsList && liveIsList {
+		for i := 0; i < max(len(storedList), len(liveList)); i++ {
+			var storedItem, liveItem interface{}
+			if i < len(storedList) {
+				storedItem = storedList[i]
+			}
+			if i < len(liveList) {
+				liveItem = liveList[i]
+			}
+			compareValues(path+"["+strconv.Itoa(i)+"]", storedItem, liveItem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(stored, live) {
+		*changes = append(*changes, Change{Path: path, Op: OpChanged, Stored: stored, Live: live})
+	}
+}
+
+// join appends a field to a dotted path
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// Objects returns the differences between two versions of an object, such as
+// the current one and the result of a dry-run write. Stored holds the values
+// of before and Live those of after; a nil before is an object being created.
+func Objects(before, after runtime.Object) ([]Change, error) {
+	var beforeContent, afterContent interface{}
+	if before != nil {
+		converted, _, err := content(before.DeepCopyObject())
+		if err != nil {
+			return nil, err
+		}
+		beforeContent = converted
+	}
+	if after != nil {
+		converted, _, err := content(after.DeepCopyObject())
+		if err != nil {
+			return nil, err
+		}
+		afterContent = converted
+	}
+
+	changes := make([]Change, 0)
+	compareValues("", beforeContent, afterContent, &changes)
+	sortChanges(changes)
+
+	return changes, nil
+}
@@ -0,0 +1,362 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Workload states
+const (
+	StatusInSync     = "in-sync"
+	StatusDrifted    = "drifted"
+	StatusSourceOnly = "source-only"
+	StatusTargetOnly = "target-only"
+)
+
+// Compared fields
+const (
+	FieldContainer = "container"
+	FieldImage     = "image"
+	FieldReplicas  = "replicas"
+	FieldEnv       = "env"
+	FieldRequests  = "requests"
+	FieldLimits    = "limits"
+)
+
+// Difference is a single setting that differs between the two clusters.
+// Key names the env var or resource, and an empty side means the setting is absent.
+type Difference struct {
+	Field     string `json:"field"`
+	Container string `json:"container,omitempty"`
+	Key       string `json:"key,omitempty"`
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+}
+
+// Workload is the comparison of one workload by name
+type Workload struct {
+	Name        string       `json:"name"`
+	Status      string       `json:"status"`
+	Differences []Difference `json:"differences"`
+}
+
+// Report compares a kind in one namespace across two clusters
+type Report struct {
+	SourceClusterID string     `json:"sourceClusterID"`
+	TargetClusterID string     `json:"targetClusterID"`
+	Namespace       string     `json:"namespace"`
+	Kind            string     `json:"kind"`
+	Drifted         int        `json:"drifted"`
+	Workloads       []Workload `json:"workloads"`
+}
+
+// template is the part of a workload that is compared
+type template struct {
+	replicas *int32
+	spec     corev1.PodSpec
+}
+
+// lister reads the comparable workloads of a kind by name
+type lister func(ctx context.Context, client kubernetes.Interface, namespace string) (map[string]template, error)
+
+// listers are the kinds that can be compared
+var listers = map[string]lister{
+	"Deployment": func(ctx context.Context, client kubernetes.Interface, namespace string) (map[string]template, error) {
+		list, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		templates := make(map[string]template, len(list.Items))
+		for _, item := range list.Items {
+			templates[item.Name] = template{replicas: item.Spec.Replicas, spec: item.Spec.Template.Spec}
+		}
+		return templates, nil
+	},
+	"StatefulSet": func(ctx context.Context, client kubernetes.Interface, namespace string) (map[string]template, error) {
+		list, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stateful sets: %w", err)
+		}
+		templates := make(map[string]template, len(list.Items))
+		for _, item := range list.Items {
+			templates[item.Name] = template{replicas: item.Spec.Replicas, spec: item.Spec.Template.Spec}
+		}
+		return templates, nil
+	},
+	"DaemonSet": func(ctx context.Context, client kubernetes.Interface, namespace string) (map[string]template, error) {
+		list, err := client.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list daemon sets: %w", err)
+		}
+		templates := make(map[string]template, len(list.Items))
+		for _, item := range list.Items {
+			templates[item.Name] = template{spec: item.Spec.Template.Spec}
+		}
+		return templates, nil
+	},
+	"CronJob": func(ctx context.Context, client kubernetes.Interface, namespace string) (map[string]template, error) {
+		list, err := client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cron jobs: %w", err)
+		}
+		templates := make(map[string]template, len(list.Items))
+		for _, item := range list.Items {
+			templates[item.Name] = template{spec: item.Spec.JobTemplate.Spec.Template.Spec}
+		}
+		return templates, nil
+	},
+}
+
+// Supported reports whether a kind can be compared
+func Supported(kind string) bool {
+	_, ok := listers[kind]
+	return ok
+}
+
+// DriftProvider compares workloads between clusters
+type DriftProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewDriftProvider creates a new provider
+func NewDriftProvider(clusterManager *cluster.Manager) *DriftProvider {
+	return &DriftProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// Compare lists a kind in a namespace of both clusters and reports, by name,
+// differences in replicas and in each container's image, env vars and
+// resource requests and limits. Workloads are read live since the agents
+// only mirror pods.
+func (p *DriftProvider) Compare(ctx context.Context, sourceClusterID, targetClusterID, namespace, kind string) (*Report, error) {
+	list, ok := listers[kind]
+	if !ok {
+		return nil, fmt.Errorf("kind %q cannot be compared", kind)
+	}
+
+	source, err := p.list(ctx, list, sourceClusterID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := p.list(ctx, list, targetClusterID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(source)+len(target))
+	for name := range source {
+		names[name] = true
+	}
+	for name := range target {
+		names[name] = true
+	}
+
+	report := &Report{
+		SourceClusterID: sourceClusterID,
+		TargetClusterID: targetClusterID,
+		Namespace:       namespace,
+		Kind:            kind,
+		Workloads:       make([]Workload, 0, len(names)),
+	}
+
+	for name := range names {
+		sourceTemplate, inSource := source[name]
+		targetTemplate, inTarget := target[name]
+
+		workload := Workload{
+			Name:        name,
+			Status:      StatusInSync,
+			Differences: make([]Difference, 0),
+		}
+
+		switch {
+		case !inTarget:
+			workload.Status = StatusSourceOnly
+		case !inSource:
+			workload.Status = StatusTargetOnly
+		default:
+			workload.Differences = compareTemplates(sourceTemplate, targetTemplate)
+			if len(workload.Differences) > 0 {
+				workload.Status = StatusDrifted
+			}
+		}
+
+		if workload.Status != StatusInSync {
+			report.Drifted++
+		}
+		report.Workloads = append(report.Workloads, workload)
+	}
+
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		return report.Workloads[i].Name < report.Workloads[j].Name
+	})
+
+	return report, nil
+}
+
+// list reads the workloads of one cluster
+func (p *DriftProvider) list(ctx context.Context, list lister, clusterID, namespace string) (map[string]template, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	templates, err := list(ctx, conn.Client, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %w", clusterID, err)
+	}
+
+	return templates, nil
+}
+
+// compareTemplates returns the differences between two workloads
+func compareTemplates(source, target template) []Difference {
+	differences := make([]Difference, 0)
+
+	if replicas(source.replicas) != replicas(target.replicas) {
+		differences = append(differences, Difference{
+			Field:  FieldReplicas,
+			Source: replicas(source.replicas),
+			Target: replicas(target.replicas),
+		})
+	}
+
+	sourceContainers := containersByName(&source.spec)
+	targetContainers := containersByName(&target.spec)
+
+	for _, name := range unionKeys(sourceContainers, targetContainers) {
+		sourceContainer, inSource := sourceContainers[name]
+		targetContainer, inTarget := targetContainers[name]
+
+		if !inSource || !inTarget {
+			differences = append(differences, Difference{
+				Field:     FieldContainer,
+				Container: name,
+				Source:    present(inSource),
+				Target:    present(inTarget),
+			})
+			continue
+		}
+
+		if sourceContainer.Image != targetContainer.Image {
+			differences = append(differences, Difference{
+				Field:     FieldImage,
+				Container: name,
+				Source:    sourceContainer.Image,
+				Target:    targetContainer.Image,
+			})
+		}
+
+		differences = append(differences, compareMaps(FieldEnv, name, envValues(sourceContainer), envValues(targetContainer))...)
+		differences = append(differences, compareMaps(FieldRequests, name, quantities(sourceContainer.Resources.Requests), quantities(targetContainer.Resources.Requests))...)
+		differences = append(differences, compareMaps(FieldLimits, name, quantities(sourceContainer.Resources.Limits), quantities(targetContainer.Resources.Limits))...)
+	}
+
+	return differences
+}
+
+// compareMaps returns a difference for every key whose value differs
+func compareMaps(field, container string, source, target map[string]string) []Difference {
+	differences := make([]Difference, 0)
+
+	for _, key := range unionKeys(source, target) {
+		if source[key] != target[key] {
+			differences = append(differences, Difference{
+				Field:     field,
+				Container: container,
+				Key:       key,
+				Source:    source[key],
+				Target:    target[key],
+			})
+		}
+	}
+
+	return differences
+}
+
+// containersByName indexes the init and regular containers of a pod spec
+func containersByName(spec *corev1.PodSpec) map[string]corev1.Container {
+	containers := make(map[string]corev1.Container, len(spec.InitContainers)+len(spec.Containers))
+	for _, container := range spec.InitContainers {
+		containers[container.Name] = container
+	}
+	for _, container := range spec.Containers {
+		containers[container.Name] = container
+	}
+	return containers
+}
+
+// envValues returns a container's env vars. Vars read from other objects are
+// described by their source since the values themselves are not compared.
+func envValues(container corev1.Container) map[string]string {
+	values := make(map[string]string, len(container.Env))
+
+	for _, env := range container.Env {
+		switch source := env.ValueFrom; {
+		case source == nil:
+			values[env.Name] = env.Value
+		case source.ConfigMapKeyRef != nil:
+			values[env.Name] = "configmap:" + source.ConfigMapKeyRef.Name + "/" + source.ConfigMapKeyRef.Key
+		case source.SecretKeyRef != nil:
+			values[env.Name] = "secret:" + source.SecretKeyRef.Name + "/" + source.SecretKeyRef.Key
+		case source.FieldRef != nil:
+			values[env.Name] = "field:" + source.FieldRef.FieldPath
+		case source.ResourceFieldRef != nil:
+			values[env.Name] = "resource:" + source.ResourceFieldRef.Resource
+		}
+	}
+
+	return values
+}
+
+// quantities formats a resource list
+func quantities(resources corev1.ResourceList) map[string]string {
+	values := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		values[string(name)] = quantity.String()
+	}
+	return values
+}
+
+// replicas formats a replica count, which is unset for daemon sets and cron jobs
+func replicas(count *int32) string {
+	if count == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*count))
+}
+
+// present describes whether a container exists on one side
+func present(ok bool) string {
+	if ok {
+		return "present"
+	}
+	return ""
+}
+
+// unionKeys returns the sorted keys of both maps
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+
+	for _, m := range []map[string]V{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// lastAppliedAnnotation is written by kubectl apply and goes stale once exported
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// clusterMetadata is metadata assigned by the API server that must not be re-applied
+var clusterMetadata = []string{
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"managedFields",
+	"ownerReferences",
+	"selfLink",
+}
+
+// clusterSpec is spec assigned by the cluster, by kind
+var clusterSpec = map[string][][]string{
+	"Pod":                   {{"spec", "nodeName"}},
+	"Service":               {{"spec", "clusterIP"}, {"spec", "clusterIPs"}},
+	"PersistentVolumeClaim": {{"spec", "volumeName"}},
+}
+
+// Clean returns an object as a map without status and the fields the cluster
+// fills in, so it can be applied to another namespace or cluster
+func Clean(obj runtime.Object) (map[string]interface{}, error) {
+	// Objects read from typed clients or the store may lack apiVersion and kind
+	if obj.GetObjectKind().GroupVersionKind().Empty() {
+		gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+		if err != nil || len(gvks) == 0 {
+			return nil, fmt.Errorf("failed to determine object kind: %w", err)
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object: %w", err)
+	}
+
+	unstructured.RemoveNestedField(content, "status")
+	for _, field := range clusterMetadata {
+		unstructured.RemoveNestedField(content, "metadata", field)
+	}
+	unstructured.RemoveNestedField(content, "metadata", "annotations", lastAppliedAnnotation)
+
+	if annotations, ok, _ := unstructured.NestedMap(content, "metadata", "annotations"); ok && len(annotations) == 0 {
+		unstructured.RemoveNestedField(content, "metadata", "annotations")
+	}
+
+	kind, _, _ := unstructured.NestedString(content, "kind")
+	for _, path := range clusterSpec[kind] {
+		unstructured.RemoveNestedField(content, path...)
+	}
+
+	return content, nil
+}
+
+// YAML returns the cleaned object as YAML
+func YAML(obj runtime.Object) ([]byte, error) {
+	content, err := Clean(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(content); err != nil {
+		return nil, fmt.Errorf("failed to encode yaml: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,304 @@
+package files
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/podexec"
+	"github.com/jbetancur/dashboard/internal/pkg/tunnel"
+)
+
+// File types
+const (
+	TypeFile      = "file"
+	TypeDirectory = "directory"
+	TypeLink      = "link"
+	TypeOther     = "other"
+)
+
+// Default transfer limits
+const (
+	DefaultMaxDownloadBytes = 64 << 20
+	DefaultMaxUploadBytes   = 4 << 20
+)
+
+// Errors returned for transfers that can't be served
+var (
+	ErrTooLarge    = errors.New("transfer exceeds the size limit")
+	ErrInvalidPath = errors.New("invalid path")
+	ErrNotAFile    = errors.New("not a regular file")
+)
+
+// Config limits the size of file transfers
+type Config struct {
+	MaxDownloadBytes int64 `yaml:"maxDownloadBytes"`
+	MaxUploadBytes   int64 `yaml:"maxUploadBytes"`
+}
+
+// withDefaults fills in unset limits
+func (c Config) withDefaults() Config {
+	if c.MaxDownloadBytes <= 0 {
+		c.MaxDownloadBytes = DefaultMaxDownloadBytes
+	}
+	if c.MaxUploadBytes <= 0 {
+		c.MaxUploadBytes = DefaultMaxUploadBytes
+	}
+	return c
+}
+
+// FileInfo describes an entry of a container directory
+type FileInfo struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Type    string    `json:"type"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// FileProvider browses and copies container files by running find, stat
+// and tar in the container, like kubectl cp. Containers without them can't
+// be browsed.
+type FileProvider struct {
+	clusterManager *cluster.Manager
+	tunnel         *tunnel.Client
+	config         Config
+}
+
+// NewFileProvider creates a new provider
+func NewFileProvider(clusterManager *cluster.Manager, tunnelClient *tunnel.Client, config Config) *FileProvider {
+	return &FileProvider{
+		clusterManager: clusterManager,
+		tunnel:         tunnelClient,
+		config:         config.withDefaults(),
+	}
+}
+
+// MaxUploadBytes is the largest file that may be uploaded
+func (p *FileProvider) MaxUploadBytes() int64 {
+	return p.config.MaxUploadBytes
+}
+
+// List returns the entries of a directory in a container, directories first
+func (p *FileProvider) List(ctx context.Context, clusterID, namespace, pod, container, dir string) ([]FileInfo, error) {
+	dir, err := cleanPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = p.exec(ctx, clusterID, namespace, pod, podexec.Options{
+		Container: container,
+		Command:   []string{"find", dir, "-mindepth", "1", "-maxdepth", "1", "-exec", "stat", "-c", "%F|%s|%Y|%A|%n", "{}", "+"},
+		Stdout:    &stdout,
+		Stderr:    &stderr,
+	})
+	if err != nil {
+		return nil, commandError("list files", err, stderr.String())
+	}
+
+	entries := make([]FileInfo, 0)
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if entry, ok := parseStat(line); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if (entries[i].Type == TypeDirectory) != (entries[j].Type == TypeDirectory) {
+			return entries[i].Type == TypeDirectory
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+// Archive returns a tar archive of a file or directory in a container
+func (p *FileProvider) Archive(ctx context.Context, clusterID, namespace, pod, container, filePath string) ([]byte, error) {
+	filePath, err := cleanPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if filePath == "/" {
+		return nil, fmt.Errorf("%w: can't download the root directory", ErrInvalidPath)
+	}
+
+	// Stop the command as soon as the archive grows past the limit
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	archive := &limitedBuffer{limit: p.config.MaxDownloadBytes, exceeded: cancel}
+	var stderr bytes.Buffer
+	// The name may start with a dash, so it follows -- to be read as a path
+	err = p.exec(ctx, clusterID, namespace, pod, podexec.Options{
+		Container: container,
+		Command:   []string{"tar", "cf", "-", "-C", path.Dir(filePath), "--", path.Base(filePath)},
+		Stdout:    archive,
+		Stderr:    &stderr,
+	})
+	if archive.over {
+		return nil, fmt.Errorf("%w of %d bytes", ErrTooLarge, p.config.MaxDownloadBytes)
+	}
+	if err != nil {
+		return nil, commandError("archive "+filePath, err, stderr.String())
+	}
+
+	return archive.Bytes(), nil
+}
+
+// Download returns the content of a regular file in a container
+func (p *FileProvider) Download(ctx context.Context, clusterID, namespace, pod, container, filePath string) ([]byte, error) {
+	archive, err := p.Archive(ctx, clusterID, namespace, pod, container, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := tar.NewReader(bytes.NewReader(archive))
+	header, err := reader.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("%w: %s", ErrNotAFile, filePath)
+	}
+
+	return io.ReadAll(reader)
+}
+
+// Upload writes a file into a directory of a container, replacing any file
+// of the same name
+func (p *FileProvider) Upload(ctx context.Context, clusterID, namespace, pod, container, dir, name string, content []byte) error {
+	dir, err := cleanPath(dir)
+	if err != nil {
+		return err
+	}
+	if name == "" || name != path.Base(name) || name == "." || name == ".." {
+		return fmt.Errorf("%w: invalid file name %q", ErrInvalidPath, name)
+	}
+	if int64(len(content)) > p.config.MaxUploadBytes {
+		return fmt.Errorf("%w of %d bytes", ErrTooLarge, p.config.MaxUploadBytes)
+	}
+
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	if err := writer.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to archive file: %w", err)
+	}
+	if _, err := writer.Write(content); err != nil {
+		return fmt.Errorf("failed to archive file: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to archive file: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	err = p.exec(ctx, clusterID, namespace, pod, podexec.Options{
+		Container: container,
+		Command:   []string{"tar", "xmf", "-", "-C", dir},
+		Stdin:     &archive,
+		Stderr:    &stderr,
+	})
+	if err != nil {
+		return commandError("upload "+name, err, stderr.String())
+	}
+
+	return nil
+}
+
+// exec runs a command in a pod, through the cluster's agent when the API
+// server isn't reachable from here
+func (p *FileProvider) exec(ctx context.Context, clusterID, namespace, pod string, opts podexec.Options) error {
+	if p.tunnel.Proxied(clusterID) {
+		return p.tunnel.Exec(ctx, tunnel.Open{ClusterID: clusterID, Namespace: namespace, Pod: pod}, opts)
+	}
+
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("cluster not found: %w", err)
+	}
+	return podexec.Exec(ctx, conn.Config, namespace, pod, opts)
+}
+
+// cleanPath requires an absolute path and normalizes it. An absolute path
+// starts with a slash, never a dash, so the directories passed to find and
+// tar -C cannot be read as options.
+func cleanPath(p string) (string, error) {
+	if !path.IsAbs(p) {
+		return "", fmt.Errorf("%w: %q must be absolute", ErrInvalidPath, p)
+	}
+	return path.Clean(p), nil
+}
+
+// commandError adds what the command printed to a failed run
+func commandError(action string, err error, stderr string) error {
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		return fmt.Errorf("failed to %s: %w: %s", action, err, stderr)
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// parseStat parses a line of stat -c '%F|%s|%Y|%A|%n'
+func parseStat(line string) (FileInfo, bool) {
+	fields := strings.SplitN(line, "|", 5)
+	if len(fields) != 5 {
+		return FileInfo{}, false
+	}
+
+	size, _ := strconv.ParseInt(fields[1], 10, 64)
+	modified, _ := strconv.ParseInt(fields[2], 10, 64)
+
+	fileType := TypeOther
+	switch {
+	case strings.Contains(fields[0], "regular"):
+		fileType = TypeFile
+	case fields[0] == "directory":
+		fileType = TypeDirectory
+	case fields[0] == "symbolic link":
+		fileType = TypeLink
+	}
+
+	return FileInfo{
+		Name:    path.Base(fields[4]),
+		Path:    fields[4],
+		Type:    fileType,
+		Size:    size,
+		Mode:    fields[3],
+		ModTime: time.Unix(modified, 0).UTC(),
+	}, true
+}
+
+// limitedBuffer collects output up to a limit, calling exceeded once when
+// more is written
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int64
+	over     bool
+	exceeded func()
+}
+
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if int64(b.Len()+len(data)) > b.limit {
+		if !b.over {
+			b.over = true
+			b.exceeded()
+		}
+		return 0, ErrTooLarge
+	}
+	return b.Buffer.Write(data)
+}
@@ -0,0 +1,160 @@
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Repository reads registered clusters and their stored resources
+type Repository interface {
+	ListClusters(ctx context.Context, results *[]cluster.ClusterInfo) error
+	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
+}
+
+// AllowFunc reports whether a cluster may be included in a query
+type AllowFunc func(ctx context.Context, clusterID string) (bool, error)
+
+// Query selects resources across clusters. An empty namespace matches all
+// namespaces and no cluster patterns match all clusters.
+type Query struct {
+	Kind      string
+	Namespace string
+	Selector  labels.Selector
+	// Clusters are cluster names or glob patterns such as "prod-*"
+	Clusters []string
+	// Status matches a pod's phase or a container's waiting or terminated
+	// reason, e.g. CrashLoopBackOff, or a namespace's phase
+	Status string
+	Allow  AllowFunc
+}
+
+// ValidateClusters checks that cluster patterns are valid globs
+func ValidateClusters(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid cluster pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// matchesCluster reports whether a cluster is selected by the query's patterns
+func (q Query) matchesCluster(clusterID string) bool {
+	if len(q.Clusters) == 0 {
+		return true
+	}
+	for _, pattern := range q.Clusters {
+		if ok, _ := path.Match(pattern, clusterID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesStatus reports whether a resource has the query's status. Kinds
+// without a status only match an empty one.
+func (q Query) matchesStatus(obj any) bool {
+	if q.Status == "" {
+		return true
+	}
+
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		if string(o.Status.Phase) == q.Status {
+			return true
+		}
+		for _, status := range slices.Concat(o.Status.InitContainerStatuses, o.Status.ContainerStatuses) {
+			if status.State.Waiting != nil && status.State.Waiting.Reason == q.Status {
+				return true
+			}
+			if status.State.Terminated != nil && status.State.Terminated.Reason == q.Status {
+				return true
+			}
+		}
+	case *corev1.Namespace:
+		return string(o.Status.Phase) == q.Status
+	}
+
+	return false
+}
+
+// Item is a resource annotated with the cluster it was read from
+type Item[T any] struct {
+	ClusterID string `json:"clusterID"`
+	Resource  T      `json:"resource"`
+}
+
+// Result is a fleet-wide listing. Clusters that could not be read are
+// reported in Errors rather than failing the whole query.
+type Result[T any] struct {
+	Clusters []string          `json:"clusters"`
+	Items    []Item[T]         `json:"items"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+// List returns the stored resources of a kind from every registered cluster
+// the query selects and allows, filtered by label selector and status
+func List[T any](ctx context.Context, store Repository, query Query) (*Result[T], error) {
+	var clusters []cluster.ClusterInfo
+	if err := store.ListClusters(ctx, &clusters); err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	selector := query.Selector
+	if selector == nil {
+		selector = labels.Everything()
+	}
+
+	result := &Result[T]{
+		Clusters: make([]string, 0, len(clusters)),
+		Items:    make([]Item[T], 0),
+	}
+
+	for _, info := range clusters {
+		clusterID := info.Name
+		if !query.matchesCluster(clusterID) {
+			continue
+		}
+
+		if query.Allow != nil {
+			allowed, err := query.Allow(ctx, clusterID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to verify permissions for cluster %s: %w", clusterID, err)
+			}
+			if !allowed {
+				continue
+			}
+		}
+
+		var resources []T
+		if err := store.List(ctx, clusterID, query.Namespace, query.Kind, &resources); err != nil {
+			if result.Errors == nil {
+				result.Errors = make(map[string]string)
+			}
+			result.Errors[clusterID] = err.Error()
+			continue
+		}
+
+		result.Clusters = append(result.Clusters, clusterID)
+
+		for i := range resources {
+			meta, err := apimeta.Accessor(&resources[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to read object metadata: %w", err)
+			}
+
+			if selector.Matches(labels.Set(meta.GetLabels())) && query.matchesStatus(&resources[i]) {
+				result.Items = append(result.Items, Item[T]{ClusterID: clusterID, Resource: resources[i]})
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResourceLister reads stored resources
+type ResourceLister interface {
+	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
+}
+
+// Node is an object in the ownership tree
+type Node struct {
+	Kind     string  `json:"kind"`
+	Name     string  `json:"name"`
+	UID      string  `json:"uid"`
+	Children []*Node `json:"children"`
+
+	owner types.UID
+}
+
+// Graph is the ownership forest of a namespace
+type Graph struct {
+	ClusterID string  `json:"clusterID"`
+	Namespace string  `json:"namespace"`
+	Roots     []*Node `json:"roots"`
+}
+
+// GraphProvider builds ownership trees from controller owner references
+type GraphProvider struct {
+	clusterManager *cluster.Manager
+	store          ResourceLister
+}
+
+// NewGraphProvider creates a new provider
+func NewGraphProvider(clusterManager *cluster.Manager, store ResourceLister) *GraphProvider {
+	return &GraphProvider{
+		clusterManager: clusterManager,
+		store:          store,
+	}
+}
+
+// Build returns the ownership tree of a namespace, such as
+// Deployment→ReplicaSets→Pods or CronJob→Jobs→Pods. Pods come from the store;
+// controllers are not mirrored and are listed from the cluster. When root is
+// set as Kind/name only that object's tree is returned.
+func (p *GraphProvider) Build(ctx context.Context, clusterID, namespace, root string) (*Graph, error) {
+	var rootKind, rootName string
+	if root != "" {
+		var ok bool
+		rootKind, rootName, ok = strings.Cut(root, "/")
+		if !ok || rootKind == "" || rootName == "" {
+			return nil, fmt.Errorf("root must be Kind/name")
+		}
+	}
+
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	nodes, err := controllers(ctx, conn.Client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	if err := p.store.List(ctx, clusterID, namespace, "Pod", &pods); err != nil {
+		return nil, fmt.Errorf("failed to list stored pods: %w", err)
+	}
+	for i := range pods {
+		nodes = append(nodes, newNode("Pod", &pods[i]))
+	}
+
+	byUID := make(map[types.UID]*Node, len(nodes))
+	for _, node := range nodes {
+		byUID[types.UID(node.UID)] = node
+	}
+
+	graph := &Graph{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Roots:     make([]*Node, 0),
+	}
+
+	for _, node := range nodes {
+		if owner, ok := byUID[node.owner]; ok {
+			owner.Children = append(owner.Children, node)
+			continue
+		}
+
+		if root == "" || (strings.EqualFold(node.Kind, rootKind) && node.Name == rootName) {
+			graph.Roots = append(graph.Roots, node)
+		}
+	}
+
+	// A root that is owned by something else is still returned on its own
+	if root != "" && len(graph.Roots) == 0 {
+		for _, node := range nodes {
+			if strings.EqualFold(node.Kind, rootKind) && node.Name == rootName {
+				graph.Roots = append(graph.Roots, node)
+			}
+		}
+		if len(graph.Roots) == 0 {
+			return nil, fmt.Errorf("%s not found", root)
+		}
+	}
+
+	sortNodes(graph.Roots)
+	return graph, nil
+}
+
+// controllers lists the workload controllers of a namespace
+func controllers(ctx context.Context, client kubernetes.Interface, namespace string) ([]*Node, error) {
+	nodes := make([]*Node, 0)
+	opts := metav1.ListOptions{}
+
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		nodes = append(nodes, newNode("Deployment", &deployments.Items[i]))
+	}
+
+	replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replica sets: %w", err)
+	}
+	for i := range replicaSets.Items {
+		nodes = append(nodes, newNode("ReplicaSet", &replicaSets.Items[i]))
+	}
+
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stateful sets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		nodes = append(nodes, newNode("StatefulSet", &statefulSets.Items[i]))
+	}
+
+	daemonSets, err := client.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemon sets: %w", err)
+	}
+	for i := range daemonSets.Items {
+		nodes = append(nodes, newNode("DaemonSet", &daemonSets.Items[i]))
+	}
+
+	cronJobs, err := client.BatchV1().CronJobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cron jobs: %w", err)
+	}
+	for i := range cronJobs.Items {
+		nodes = append(nodes, newNode("CronJob", &cronJobs.Items[i]))
+	}
+
+	jobs, err := client.BatchV1().Jobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		nodes = append(nodes, newNode("Job", &jobs.Items[i]))
+	}
+
+	return nodes, nil
+}
+
+// newNode creates a node from an object's metadata
+func newNode(kind string, obj metav1.Object) *Node {
+	node := &Node{
+		Kind:     kind,
+		Name:     obj.GetName(),
+		UID:      string(obj.GetUID()),
+		Children: make([]*Node, 0),
+	}
+
+	if ref := metav1.GetControllerOfNoCopy(obj); ref != nil {
+		node.owner = ref.UID
+	}
+
+	return node
+}
+
+// sortNodes orders a tree by kind and name
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Kind != nodes[j].Kind {
+			return nodes[i].Kind < nodes[j].Kind
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
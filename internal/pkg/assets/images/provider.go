@@ -0,0 +1,203 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResourceLister reads stored resources
+type ResourceLister interface {
+	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
+}
+
+// Image is one image reference running in the cluster
+type Image struct {
+	Image       string        `json:"image"`
+	Reference   Reference     `json:"reference"`
+	Digests     []string      `json:"digests"`
+	Pods        int           `json:"pods"`
+	Containers  int           `json:"containers"`
+	Namespaces  []string      `json:"namespaces"`
+	Registry    *RegistryInfo `json:"registry,omitempty"`
+	LookupError string        `json:"lookupError,omitempty"`
+	// Outdated is set when the registry now serves a different digest for the tag
+	Outdated bool `json:"outdated,omitempty"`
+
+	namespaces map[string]bool
+	digests    map[string]bool
+}
+
+// MixedVersion is a repository running under more than one tag or digest
+type MixedVersion struct {
+	Repository string   `json:"repository"`
+	Images     []string `json:"images"`
+	Digests    []string `json:"digests"`
+}
+
+// Inventory lists the images running in a cluster or namespace
+type Inventory struct {
+	ClusterID string         `json:"clusterID"`
+	Namespace string         `json:"namespace,omitempty"`
+	Images    []*Image       `json:"images"`
+	Mixed     []MixedVersion `json:"mixed"`
+}
+
+// ImageProvider builds image inventories from stored pods
+type ImageProvider struct {
+	store    ResourceLister
+	registry *RegistryClient
+}
+
+// NewImageProvider creates a new provider. Registry lookups are only
+// available when registry is not nil.
+func NewImageProvider(store ResourceLister, registry *RegistryClient) *ImageProvider {
+	return &ImageProvider{
+		store:    store,
+		registry: registry,
+	}
+}
+
+// Inventory aggregates the images of the stored pods of a cluster, or of one
+// namespace, with the digests they resolved to. With lookup the registry is
+// also asked for each image's current digest and creation date.
+func (p *ImageProvider) Inventory(ctx context.Context, clusterID, namespace string, lookup bool) (*Inventory, error) {
+	var pods []corev1.Pod
+	if err := p.store.List(ctx, clusterID, namespace, "Pod", &pods); err != nil {
+		return nil, fmt.Errorf("failed to list stored pods: %w", err)
+	}
+
+	byImage := make(map[string]*Image)
+	for _, pod := range pods {
+		digests := make(map[string]string)
+		for _, status := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+			digests[status.Name] = digestOf(status.ImageID)
+		}
+
+		seen := make(map[string]bool)
+		for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+			image, ok := byImage[container.Image]
+			if !ok {
+				image = &Image{
+					Image:      container.Image,
+					Reference:  ParseReference(container.Image),
+					namespaces: make(map[string]bool),
+					digests:    make(map[string]bool),
+				}
+				byImage[container.Image] = image
+			}
+
+			image.Containers++
+			if !seen[container.Image] {
+				seen[container.Image] = true
+				image.Pods++
+			}
+			image.namespaces[pod.Namespace] = true
+			if digest := digests[container.Name]; digest != "" {
+				image.digests[digest] = true
+			}
+		}
+	}
+
+	inventory := &Inventory{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Images:    make([]*Image, 0, len(byImage)),
+		Mixed:     make([]MixedVersion, 0),
+	}
+
+	for _, image := range byImage {
+		image.Namespaces = sortedKeys(image.namespaces)
+		image.Digests = sortedKeys(image.digests)
+		inventory.Images = append(inventory.Images, image)
+	}
+
+	sort.Slice(inventory.Images, func(i, j int) bool {
+		return inventory.Images[i].Image < inventory.Images[j].Image
+	})
+
+	inventory.Mixed = mixedVersions(inventory.Images)
+
+	if lookup && p.registry != nil {
+		p.lookup(ctx, inventory.Images)
+	}
+
+	return inventory, nil
+}
+
+// lookup fills in registry information, a few images at a time
+func (p *ImageProvider) lookup(ctx context.Context, images []*Image) {
+	sem := make(chan struct{}, p.registry.config.Concurrency)
+	var wg sync.WaitGroup
+
+	for _, image := range images {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(image *Image) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			info, err := p.registry.Lookup(ctx, image.Reference)
+			if err != nil {
+				image.LookupError = err.Error()
+				return
+			}
+
+			image.Registry = info
+			if image.Reference.Digest == "" && info.Digest != "" && len(image.Digests) > 0 {
+				image.Outdated = !image.digests[info.Digest]
+			}
+		}(image)
+	}
+
+	wg.Wait()
+}
+
+// mixedVersions returns the repositories running under several tags or digests
+func mixedVersions(images []*Image) []MixedVersion {
+	byRepository := make(map[string][]*Image)
+	for _, image := range images {
+		name := image.Reference.Name()
+		byRepository[name] = append(byRepository[name], image)
+	}
+
+	mixed := make([]MixedVersion, 0)
+	for name, group := range byRepository {
+		refs := make([]string, 0, len(group))
+		digests := make(map[string]bool)
+		for _, image := range group {
+			refs = append(refs, image.Image)
+			for digest := range image.digests {
+				digests[digest] = true
+			}
+		}
+
+		if len(refs) > 1 || len(digests) > 1 {
+			mixed = append(mixed, MixedVersion{
+				Repository: name,
+				Images:     refs,
+				Digests:    sortedKeys(digests),
+			})
+		}
+	}
+
+	sort.Slice(mixed, func(i, j int) bool {
+		return mixed[i].Repository < mixed[j].Repository
+	})
+
+	return mixed
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package images
+
+import "strings"
+
+// dockerHub is the registry assumed for images without a registry host
+const dockerHub = "docker.io"
+
+// Reference is a parsed container image reference
+type Reference struct {
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+}
+
+// ParseReference splits an image into registry, repository, tag and digest
+// the way container runtimes resolve them: a missing registry means Docker
+// Hub, where single-name images live under library/, and a missing tag
+// means latest unless the image is pinned by digest.
+func ParseReference(image string) Reference {
+	var ref Reference
+
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		ref.Digest = name[at+1:]
+		name = name[:at]
+	}
+
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		ref.Tag = name[colon+1:]
+		name = name[:colon]
+	}
+
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry = first
+		name = rest
+	} else {
+		ref.Registry = dockerHub
+	}
+
+	if ref.Registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	return ref
+}
+
+// Name returns the registry and repository without tag or digest
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// digestOf extracts the digest from a container status imageID, such as
+// docker-pullable://nginx@sha256:... or a bare sha256:...
+func digestOf(imageID string) string {
+	if at := strings.LastIndex(imageID, "@"); at >= 0 {
+		return imageID[at+1:]
+	}
+	if strings.HasPrefix(imageID, "sha256:") {
+		return imageID
+	}
+	return ""
+}
@@ -0,0 +1,297 @@
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// manifestTypes are the manifest media types requested from registries
+var manifestTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Credential authenticates to a registry
+type Credential struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// RegistryConfig configures registry lookups. Credentials are keyed by
+// registry host; registries without one are queried anonymously.
+type RegistryConfig struct {
+	Enabled     bool                  `yaml:"enabled"`
+	Timeout     time.Duration         `yaml:"timeout"`
+	CacheTTL    time.Duration         `yaml:"cacheTTL"`
+	Concurrency int                   `yaml:"concurrency"`
+	Credentials map[string]Credential `yaml:"credentials"`
+}
+
+// Config configures the image inventory
+type Config struct {
+	Registry RegistryConfig `yaml:"registry"`
+}
+
+// RegistryInfo is what a registry reports for an image reference
+type RegistryInfo struct {
+	Digest  string    `json:"digest"`
+	Created time.Time `json:"created,omitempty"`
+}
+
+// registryEntry is a cached lookup
+type registryEntry struct {
+	info    *RegistryInfo
+	err     error
+	expires time.Time
+}
+
+// RegistryClient looks up image digests and creation dates using the
+// registry HTTP API, caching results
+type RegistryClient struct {
+	config RegistryConfig
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]registryEntry
+}
+
+// NewRegistryClient creates a new registry client
+func NewRegistryClient(config RegistryConfig) *RegistryClient {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = time.Hour
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+
+	return &RegistryClient{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		cache:  make(map[string]registryEntry),
+	}
+}
+
+// Lookup returns the digest the registry currently serves for a reference
+// and the creation date recorded in the image config. Failures are cached
+// too so an unreachable registry is not retried on every request.
+func (r *RegistryClient) Lookup(ctx context.Context, ref Reference) (*RegistryInfo, error) {
+	key := ref.Name() + ":" + ref.Tag + "@" + ref.Digest
+
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.info, entry.err
+	}
+
+	info, err := r.lookup(ctx, ref)
+
+	r.mu.Lock()
+	r.cache[key] = registryEntry{info: info, err: err, expires: time.Now().Add(r.config.CacheTTL)}
+	r.mu.Unlock()
+
+	return info, err
+}
+
+// lookup fetches the manifest and image config of a reference
+func (r *RegistryClient) lookup(ctx context.Context, ref Reference) (*RegistryInfo, error) {
+	version := ref.Digest
+	if version == "" {
+		version = ref.Tag
+	}
+
+	var token string
+	body, digest, mediaType, err := r.fetch(ctx, ref, "manifests/"+version, manifestTypes, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest struct {
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+		Manifests []struct {
+			Digest   string `json:"digest"`
+			Platform struct {
+				OS           string `json:"os"`
+				Architecture string `json:"architecture"`
+			} `json:"platform"`
+		} `json:"manifests"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	info := &RegistryInfo{Digest: digest}
+
+	// Multi-platform images point at per-platform manifests; the creation
+	// date is read from the linux/amd64 one, or the first listed
+	if strings.Contains(mediaType, "index") || strings.Contains(mediaType, "list") {
+		if len(manifest.Manifests) == 0 {
+			return info, nil
+		}
+
+		platform := manifest.Manifests[0].Digest
+		for _, m := range manifest.Manifests {
+			if m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+				platform = m.Digest
+				break
+			}
+		}
+
+		body, _, _, err = r.fetch(ctx, ref, "manifests/"+platform, manifestTypes, &token)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(body, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to decode manifest: %w", err)
+		}
+	}
+
+	if manifest.Config.Digest == "" {
+		return info, nil
+	}
+
+	body, _, _, err = r.fetch(ctx, ref, "blobs/"+manifest.Config.Digest, nil, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Created time.Time `json:"created"`
+	}
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode image config: %w", err)
+	}
+	info.Created = config.Created
+
+	return info, nil
+}
+
+// fetch reads a path under a repository, answering a bearer token challenge
+// once. The token is kept for later requests of the same lookup.
+func (r *RegistryClient) fetch(ctx context.Context, ref Reference, path string, accept []string, token *string) ([]byte, string, string, error) {
+	host := ref.Registry
+	if host == dockerHub {
+		host = "registry-1.docker.io"
+	}
+	endpoint := "https://" + host + "/v2/" + ref.Repository + "/" + path
+
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to create request: %w", err)
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if *token != "" {
+			req.Header.Set("Authorization", "Bearer "+*token)
+		} else if cred, ok := r.config.Credentials[ref.Registry]; ok {
+			req.SetBasicAuth(cred.Username, cred.Password)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("registry request failed: %w", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to read registry response: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return body, resp.Header.Get("Docker-Content-Digest"), resp.Header.Get("Content-Type"), nil
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			*token, err = r.token(ctx, ref, resp.Header.Get("WWW-Authenticate"))
+			if err != nil {
+				return nil, "", "", err
+			}
+		default:
+			return nil, "", "", fmt.Errorf("registry returned status %d for %s", resp.StatusCode, ref.Name())
+		}
+	}
+
+	return nil, "", "", fmt.Errorf("registry denied access to %s", ref.Name())
+}
+
+// token requests a bearer token as described by a WWW-Authenticate challenge
+func (r *RegistryClient) token(ctx context.Context, ref Reference, challenge string) (string, error) {
+	scheme, params, ok := strings.Cut(challenge, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", errors.New("registry requires unsupported authentication")
+	}
+
+	values := make(map[string]string)
+	for _, param := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok {
+			values[key] = strings.Trim(value, `"`)
+		}
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Host == "" {
+		return "", errors.New("registry sent an invalid authentication realm")
+	}
+
+	query := realm.Query()
+	if service := values["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := values["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	if cred, ok := r.config.Credentials[ref.Registry]; ok {
+		req.SetBasicAuth(cred.Username, cred.Password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode token: %w", err)
+	}
+
+	if result.Token != "" {
+		return result.Token, nil
+	}
+	return result.AccessToken, nil
+}
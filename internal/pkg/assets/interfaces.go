@@ -1,10 +1,13 @@
-package resources
+package assets
 
 import (
 	"context"
 	"fmt"
 	"sort"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -67,3 +70,55 @@ func ObjectMeta(obj interface{}) metav1.ObjectMeta {
 	}
 	return metav1.ObjectMeta{}
 }
+
+// Unchanged reports whether an informer update carries the object it already
+// had. Periodic resyncs redeliver every cached object with the same resource
+// version, which would otherwise be republished and rewritten for nothing.
+func Unchanged(oldObj, newObj interface{}) bool {
+	previous, ok := oldObj.(metav1.Object)
+	if !ok {
+		return false
+	}
+	current, ok := newObj.(metav1.Object)
+	if !ok {
+		return false
+	}
+	return current.GetResourceVersion() != "" && previous.GetResourceVersion() == current.GetResourceVersion()
+}
+
+// StatusOnly reports whether an informer update only changed the object's
+// status. Rollouts produce a burst of these for every pod and workload, and
+// they can be sampled under load without losing the object's shape.
+func StatusOnly(oldObj, newObj interface{}) bool {
+	previous, ok := oldObj.(metav1.Object)
+	if !ok {
+		return false
+	}
+	current, ok := newObj.(metav1.Object)
+	if !ok || !sameMeta(previous, current) {
+		return false
+	}
+
+	switch current := newObj.(type) {
+	case *corev1.Pod:
+		previous, ok := oldObj.(*corev1.Pod)
+		return ok && equality.Semantic.DeepEqual(previous.Spec, current.Spec)
+	case *corev1.Namespace:
+		previous, ok := oldObj.(*corev1.Namespace)
+		return ok && equality.Semantic.DeepEqual(previous.Spec, current.Spec)
+	case *appsv1.Deployment:
+		previous, ok := oldObj.(*appsv1.Deployment)
+		return ok && equality.Semantic.DeepEqual(previous.Spec, current.Spec)
+	}
+	return false
+}
+
+// sameMeta reports whether the user-facing metadata of two versions of an
+// object is the same
+func sameMeta(previous, current metav1.Object) bool {
+	return equality.Semantic.DeepEqual(previous.GetLabels(), current.GetLabels()) &&
+		equality.Semantic.DeepEqual(previous.GetAnnotations(), current.GetAnnotations()) &&
+		equality.Semantic.DeepEqual(previous.GetOwnerReferences(), current.GetOwnerReferences()) &&
+		equality.Semantic.DeepEqual(previous.GetFinalizers(), current.GetFinalizers()) &&
+		(previous.GetDeletionTimestamp() == nil) == (current.GetDeletionTimestamp() == nil)
+}
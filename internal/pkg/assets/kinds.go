@@ -0,0 +1,130 @@
+package assets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// KindInfo describes a namespaced resource kind the dashboard can read by name
+type KindInfo struct {
+	Kind     string
+	Group    string
+	Version  string
+	Resource string
+}
+
+// RBACResource returns the resource in the resource[.group] form used by authorization
+func (k KindInfo) RBACResource() string {
+	if k.Group == "" {
+		return k.Resource
+	}
+	return k.Resource + "." + k.Group
+}
+
+// GroupVersionKind returns the kind's GVK
+func (k KindInfo) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: k.Group, Version: k.Version, Kind: k.Kind}
+}
+
+// getter fetches one object of a kind
+type getter func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error)
+
+// kindEntry pairs a kind with its typed getter
+type kindEntry struct {
+	info KindInfo
+	get  getter
+}
+
+// kinds are the supported kinds, in display order
+var kinds = []kindEntry{
+	{KindInfo{Kind: "Pod", Version: "v1", Resource: "pods"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "Deployment", Group: "apps", Version: "v1", Resource: "deployments"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "ReplicaSet", Group: "apps", Version: "v1", Resource: "replicasets"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "StatefulSet", Group: "apps", Version: "v1", Resource: "statefulsets"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "DaemonSet", Group: "apps", Version: "v1", Resource: "daemonsets"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "Job", Group: "batch", Version: "v1", Resource: "jobs"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "CronJob", Group: "batch", Version: "v1", Resource: "cronjobs"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "Service", Version: "v1", Resource: "services"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "ConfigMap", Version: "v1", Resource: "configmaps"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "PersistentVolumeClaim", Version: "v1", Resource: "persistentvolumeclaims"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+	{KindInfo{Kind: "PodDisruptionBudget", Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"},
+		func(ctx context.Context, client kubernetes.Interface, namespace, name string) (runtime.Object, error) {
+			return client.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
+		}},
+}
+
+// Kinds returns the supported kinds
+func Kinds() []KindInfo {
+	infos := make([]KindInfo, 0, len(kinds))
+	for _, entry := range kinds {
+		infos = append(infos, entry.info)
+	}
+	return infos
+}
+
+// LookupKind finds a supported kind by kind name or plural resource, case-insensitively
+func LookupKind(name string) (KindInfo, bool) {
+	for _, entry := range kinds {
+		if strings.EqualFold(entry.info.Kind, name) || strings.EqualFold(entry.info.Resource, name) {
+			return entry.info, true
+		}
+	}
+	return KindInfo{}, false
+}
+
+// GetObject fetches a namespaced object of a supported kind, with its
+// apiVersion and kind filled in
+func GetObject(ctx context.Context, client kubernetes.Interface, kind, namespace, name string) (runtime.Object, error) {
+	for _, entry := range kinds {
+		if entry.info.Kind != kind {
+			continue
+		}
+
+		obj, err := entry.get(ctx, client, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %s/%s: %w", kind, namespace, name, err)
+		}
+
+		// Typed clients return objects without TypeMeta
+		obj.GetObjectKind().SetGroupVersionKind(entry.info.GroupVersionKind())
+		return obj, nil
+	}
+
+	return nil, fmt.Errorf("unsupported kind %q", kind)
+}
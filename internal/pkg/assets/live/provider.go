@@ -0,0 +1,96 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ErrInvalid is returned for an update that doesn't match the object it replaces
+var ErrInvalid = errors.New("invalid object")
+
+// LiveProvider reads and replaces objects of the supported kinds in the
+// cluster rather than the store, so they can be edited as YAML
+type LiveProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewLiveProvider creates a new provider
+func NewLiveProvider(clusterManager *cluster.Manager) *LiveProvider {
+	return &LiveProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// resource returns a dynamic client for a kind in a cluster
+func (p *LiveProvider) resource(clusterID string, kind assets.KindInfo) (dynamic.NamespaceableResourceInterface, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	client, err := dynamic.NewForConfig(conn.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	gvr := schema.GroupVersionResource{Group: kind.Group, Version: kind.Version, Resource: kind.Resource}
+	return client.Resource(gvr), nil
+}
+
+// GetObject gets an object as the cluster currently has it
+func (p *LiveProvider) GetObject(ctx context.Context, clusterID string, kind assets.KindInfo, namespace, name string) (*unstructured.Unstructured, error) {
+	resource, err := p.resource(clusterID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := resource.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", kind.Kind, err)
+	}
+
+	return obj, nil
+}
+
+// UpdateObject replaces an object with an edited copy. The copy's
+// resourceVersion makes the update fail with a conflict if the object
+// changed since it was read. With dryRun the cluster validates the change
+// without storing it.
+func (p *LiveProvider) UpdateObject(ctx context.Context, clusterID string, kind assets.KindInfo, namespace, name string, obj *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+
+	switch {
+	case obj.GroupVersionKind() != kind.GroupVersionKind():
+		return nil, fmt.Errorf("%w: expected apiVersion %s and kind %s", ErrInvalid, kind.GroupVersionKind().GroupVersion(), kind.Kind)
+	case obj.GetName() != name:
+		return nil, fmt.Errorf("%w: metadata.name doesn't match the path", ErrInvalid)
+	case obj.GetNamespace() != namespace:
+		return nil, fmt.Errorf("%w: metadata.namespace doesn't match the path", ErrInvalid)
+	}
+
+	resource, err := p.resource(clusterID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts metav1.UpdateOptions
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	updated, err := resource.Namespace(namespace).Update(ctx, obj, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", kind.Kind, err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// metricsAPIPath is the metrics.k8s.io API served by metrics-server
+const metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+// ContainerMetrics is the resource usage of one container
+type ContainerMetrics struct {
+	Name  string              `json:"name"`
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+// PodMetrics is the resource usage of a pod as reported by metrics-server
+type PodMetrics struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Timestamp         metav1.Time        `json:"timestamp"`
+	Window            metav1.Duration    `json:"window"`
+	Containers        []ContainerMetrics `json:"containers"`
+}
+
+// NodeMetrics is the resource usage of a node as reported by metrics-server
+type NodeMetrics struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Timestamp         metav1.Time         `json:"timestamp"`
+	Window            metav1.Duration     `json:"window"`
+	Usage             corev1.ResourceList `json:"usage"`
+}
+
+// MetricsProvider reads pod and node usage from metrics.k8s.io on each cluster
+type MetricsProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewMetricsProvider creates a new provider
+func NewMetricsProvider(clusterManager *cluster.Manager) *MetricsProvider {
+	return &MetricsProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// GetPodMetrics gets the usage of a single pod
+func (p *MetricsProvider) GetPodMetrics(ctx context.Context, clusterID, namespace, podName string) (*PodMetrics, error) {
+	var metrics PodMetrics
+	if err := p.get(ctx, clusterID, fmt.Sprintf("namespaces/%s/pods/%s", namespace, podName), &metrics); err != nil {
+		return nil, err
+	}
+
+	return &metrics, nil
+}
+
+// ListPodMetrics lists pod usage in a namespace, or all namespaces when empty
+func (p *MetricsProvider) ListPodMetrics(ctx context.Context, clusterID, namespace string) ([]PodMetrics, error) {
+	path := "pods"
+	if namespace != "" {
+		path = fmt.Sprintf("namespaces/%s/pods", namespace)
+	}
+
+	var list struct {
+		Items []PodMetrics `json:"items"`
+	}
+	if err := p.get(ctx, clusterID, path, &list); err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// GetNodeMetrics gets the usage of a single node
+func (p *MetricsProvider) GetNodeMetrics(ctx context.Context, clusterID, nodeName string) (*NodeMetrics, error) {
+	var metrics NodeMetrics
+	if err := p.get(ctx, clusterID, "nodes/"+nodeName, &metrics); err != nil {
+		return nil, err
+	}
+
+	return &metrics, nil
+}
+
+// ListNodeMetrics lists the usage of every node
+func (p *MetricsProvider) ListNodeMetrics(ctx context.Context, clusterID string) ([]NodeMetrics, error) {
+	var list struct {
+		Items []NodeMetrics `json:"items"`
+	}
+	if err := p.get(ctx, clusterID, "nodes", &list); err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// get fetches a metrics.k8s.io path through the cluster's REST client
+func (p *MetricsProvider) get(ctx context.Context, clusterID, path string, result interface{}) error {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("cluster not found: %w", err)
+	}
+
+	data, err := conn.Client.RESTClient().Get().
+		AbsPath(metricsAPIPath, path).
+		DoRaw(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get metrics (is metrics-server installed?): %w", err)
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode metrics: %w", err)
+	}
+
+	return nil
+}
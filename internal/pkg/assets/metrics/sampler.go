@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+)
+
+// Sample kinds
+const (
+	KindPod       = "Pod"
+	KindContainer = "Container"
+	KindNode      = "Node"
+)
+
+// SamplingConfig controls periodic metrics sampling
+type SamplingConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Interval  time.Duration `yaml:"interval"`
+	Retention time.Duration `yaml:"retention"`
+}
+
+// Sample is a point-in-time usage reading kept for short-term graphs
+type Sample struct {
+	ClusterID     string    `json:"clusterID" bson:"cluster_id"`
+	Kind          string    `json:"kind" bson:"kind"`
+	Namespace     string    `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Name          string    `json:"name" bson:"name"`
+	Container     string    `json:"container,omitempty" bson:"container,omitempty"`
+	Timestamp     time.Time `json:"timestamp" bson:"timestamp"`
+	CPUMillicores int64     `json:"cpuMillicores" bson:"cpu_millicores"`
+	MemoryBytes   int64     `json:"memoryBytes" bson:"memory_bytes"`
+}
+
+// SampleQuery selects stored samples
+type SampleQuery struct {
+	ClusterID string
+	Kind      string
+	Namespace string
+	Name      string
+	Since     time.Time
+	Until     time.Time
+}
+
+// SampleSink persists samples
+type SampleSink interface {
+	SaveMetricSamples(ctx context.Context, samples []Sample) error
+	DeleteMetricSamplesBefore(ctx context.Context, before time.Time) error
+}
+
+// Sampler periodically records pod and node usage of connected clusters
+type Sampler struct {
+	provider       *MetricsProvider
+	clusterManager *cluster.Manager
+	sink           SampleSink
+	config         SamplingConfig
+	logger         *slog.Logger
+}
+
+// NewSampler creates a new sampler, filling in defaults for unset durations
+func NewSampler(provider *MetricsProvider, clusterManager *cluster.Manager, sink SampleSink,
+	config SamplingConfig, logger *slog.Logger) *Sampler {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.Retention <= 0 {
+		config.Retention = 24 * time.Hour
+	}
+
+	return &Sampler{
+		provider:       provider,
+		clusterManager: clusterManager,
+		sink:           sink,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// Start samples on every interval until the context is cancelled
+func (s *Sampler) Start(ctx context.Context) {
+	if !s.config.Enabled {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.sample(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	s.logger.Info("Metrics sampling started", "interval", s.config.Interval, "retention", s.config.Retention)
+}
+
+// sample records one reading for every connected cluster and prunes old samples
+func (s *Sampler) sample(ctx context.Context) {
+	now := time.Now()
+
+	for clusterID, conn := range s.clusterManager.GetConnections() {
+		// Only sample clusters someone is using; sampling must not connect them
+		if !conn.IsConnected() {
+			continue
+		}
+
+		samples := make([]Sample, 0)
+
+		pods, err := s.provider.ListPodMetrics(ctx, clusterID, "")
+		if err != nil {
+			s.logger.Debug("Failed to sample pod metrics", "clusterID", clusterID, "error", err)
+			continue
+		}
+
+		for _, pod := range pods {
+			sample := Sample{
+				ClusterID: clusterID,
+				Kind:      KindPod,
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Timestamp: now,
+			}
+			for _, container := range pod.Containers {
+				cpu := container.Usage.Cpu().MilliValue()
+				memory := container.Usage.Memory().Value()
+
+				sample.CPUMillicores += cpu
+				sample.MemoryBytes += memory
+
+				// Per-container samples feed right-sizing recommendations
+				samples = append(samples, Sample{
+					ClusterID:     clusterID,
+					Kind:          KindContainer,
+					Namespace:     pod.Namespace,
+					Name:          pod.Name,
+					Container:     container.Name,
+					Timestamp:     now,
+					CPUMillicores: cpu,
+					MemoryBytes:   memory,
+				})
+			}
+			samples = append(samples, sample)
+		}
+
+		nodes, err := s.provider.ListNodeMetrics(ctx, clusterID)
+		if err != nil {
+			s.logger.Debug("Failed to sample node metrics", "clusterID", clusterID, "error", err)
+		}
+
+		for _, node := range nodes {
+			samples = append(samples, Sample{
+				ClusterID:     clusterID,
+				Kind:          KindNode,
+				Name:          node.Name,
+				Timestamp:     now,
+				CPUMillicores: node.Usage.Cpu().MilliValue(),
+				MemoryBytes:   node.Usage.Memory().Value(),
+			})
+		}
+
+		if len(samples) == 0 {
+			continue
+		}
+
+		if err := s.sink.SaveMetricSamples(ctx, samples); err != nil {
+			s.logger.Error("Failed to save metric samples", "clusterID", clusterID, "error", err)
+		}
+	}
+
+	if err := s.sink.DeleteMetricSamplesBefore(ctx, now.Add(-s.config.Retention)); err != nil {
+		s.logger.Error("Failed to prune metric samples", "error", err)
+	}
+}
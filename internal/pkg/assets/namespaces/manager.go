@@ -9,7 +9,9 @@ import (
 
 	resources "github.com/jbetancur/dashboard/internal/pkg/assets"
 	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	"github.com/jbetancur/dashboard/internal/pkg/throttle"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -21,6 +23,7 @@ type Manager struct {
 	client         *kubernetes.Clientset
 	informer       informers.SharedInformerFactory
 	eventPublisher messagingtypes.Publisher
+	throttle       *throttle.Throttle
 	logger         *slog.Logger
 	stopCh         chan struct{}
 }
@@ -30,6 +33,7 @@ func NewManager(
 	clusterID string,
 	eventPublisher messagingtypes.Publisher,
 	client *kubernetes.Clientset,
+	throttle *throttle.Throttle,
 	logger *slog.Logger,
 ) *Manager {
 	informer := informers.NewSharedInformerFactory(client, time.Minute*5)
@@ -39,6 +43,7 @@ func NewManager(
 		client:         client,
 		informer:       informer,
 		eventPublisher: eventPublisher,
+		throttle:       throttle,
 		logger:         logger,
 		stopCh:         make(chan struct{}),
 	}
@@ -50,6 +55,7 @@ func (nm *Manager) StartInformer() error {
 	namespaceInformer := nm.informer.Core().V1().Namespaces().Informer()
 	if _, err := namespaceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
+			nm.throttle.Record(obj)
 			ns := obj.(*v1.Namespace)
 
 			payload := resources.ResourcePayload[v1.Namespace]{
@@ -68,6 +74,14 @@ func (nm *Manager) StartInformer() error {
 			}
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Resyncs are skipped unless an update of the object was dropped under load
+			if resources.Unchanged(oldObj, newObj) && !nm.throttle.Stale(newObj) {
+				return
+			}
+			if !nm.throttle.AllowUpdate(newObj, resources.StatusOnly(oldObj, newObj)) {
+				return
+			}
+
 			ns := newObj.(*v1.Namespace)
 
 			payload := resources.ResourcePayload[v1.Namespace]{
@@ -85,6 +99,7 @@ func (nm *Manager) StartInformer() error {
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
+			nm.throttle.Record(obj)
 			ns := obj.(*v1.Namespace)
 
 			payload := resources.ResourcePayload[v1.Namespace]{
@@ -117,6 +132,33 @@ func (nm *Manager) StartInformer() error {
 	return nil
 }
 
+// Resync publishes every namespace in the informer cache as added so the API can
+// rebuild its copy. It returns the number of namespaces published.
+func (nm *Manager) Resync() (int, error) {
+	items, err := nm.informer.Core().V1().Namespaces().Lister().List(labels.Everything())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list cached namespaces: %w", err)
+	}
+
+	for _, item := range items {
+		payload := resources.ResourcePayload[v1.Namespace]{
+			ClusterID: nm.clusterID,
+			Resource:  *item,
+		}
+
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, fmt.Errorf("failed to serialize namespace: %w", err)
+		}
+
+		if err := nm.eventPublisher.Publish("namespace_added", data); err != nil {
+			return 0, fmt.Errorf("failed to publish namespace: %w", err)
+		}
+	}
+
+	return len(items), nil
+}
+
 // Stop stops the namespace manager
 func (nm *Manager) Stop() {
 	close(nm.stopCh)
@@ -18,33 +18,25 @@ import (
 // NamespaceProvider implements NamespaceProvider for multiple clusters
 type NamespaceProvider struct {
 	clusterManager *cluster.Manager
+	config         Config
 }
 
 // NewNamespaceProvider creates a new provider
-func NewNamespaceProvider(clusterManager *cluster.Manager) *NamespaceProvider {
+func NewNamespaceProvider(clusterManager *cluster.Manager, config Config) *NamespaceProvider {
 	return &NamespaceProvider{
 		clusterManager: clusterManager,
+		config:         config,
 	}
 }
 
 // ListNamespaces lists namespaces from a specific cluster
 func (p *NamespaceProvider) ListNamespaces(ctx context.Context, clusterID string) ([]v1.Namespace, error) {
-	// Get the cluster connection
-	conn, err := p.clusterManager.GetCluster(clusterID)
+	// Get the cluster connection with its namespace informer started and synced
+	conn, err := p.clusterManager.Synced(ctx, clusterID, []string{"Namespace"})
 	if err != nil {
-		return nil, fmt.Errorf("cluster not found: %w", err)
+		return nil, fmt.Errorf("failed to read namespaces of cluster %s: %w", clusterID, err)
 	}
-
-	// Check if the informer factory is initialized
-	if conn.Informer == nil {
-		return nil, fmt.Errorf("informer factory not initialized for cluster %s", clusterID)
-	}
-
-	// Get the namespace informer - ensure it's started
 	namespaceInformer := getNamespaceInformer(conn.Informer, "")
-	if !cache.WaitForCacheSync(ctx.Done(), namespaceInformer.Informer().HasSynced) {
-		return nil, fmt.Errorf("timed out waiting for namespace cache to sync")
-	}
 
 	// Use the lister to get namespaces from cache
 	nsList, err := namespaceInformer.Lister().List(labels.Everything())
@@ -63,22 +55,12 @@ func (p *NamespaceProvider) ListNamespaces(ctx context.Context, clusterID string
 
 // GetNamespace gets a specific namespace from a specific cluster
 func (p *NamespaceProvider) GetNamespace(ctx context.Context, clusterID, namespaceName string) (*v1.Namespace, error) {
-	// Get the cluster connection
-	conn, err := p.clusterManager.GetCluster(clusterID)
+	// Get the cluster connection with its namespace informer started and synced
+	conn, err := p.clusterManager.Synced(ctx, clusterID, []string{"Namespace"})
 	if err != nil {
-		return nil, fmt.Errorf("cluster not found: %w", err)
+		return nil, fmt.Errorf("failed to read namespaces of cluster %s: %w", clusterID, err)
 	}
-
-	// Check if the informer factory is initialized
-	if conn.Informer == nil {
-		return nil, fmt.Errorf("informer factory not initialized for cluster %s", clusterID)
-	}
-
-	// Get the namespace informer - ensure it's started
 	namespaceInformer := getNamespaceInformer(conn.Informer, "")
-	if !cache.WaitForCacheSync(ctx.Done(), namespaceInformer.Informer().HasSynced) {
-		return nil, fmt.Errorf("timed out waiting for namespace cache to sync")
-	}
 
 	// Get from cache
 	ns, err := namespaceInformer.Lister().Get(namespaceName)
@@ -112,28 +94,12 @@ func getNamespaceInformer(factory informers.SharedInformerFactory, namespace str
 
 // EnsureInformersStarted makes sure the informers are started for the given cluster
 func (p *NamespaceProvider) EnsureInformersStarted(clusterID string) error {
-	conn, err := p.clusterManager.GetCluster(clusterID)
-	if err != nil {
-		return fmt.Errorf("cluster not found: %w", err)
-	}
-
-	if conn.Informer == nil {
-		conn.InitializeInformers()
-	}
-
-	// Check if informers are running
-	if !conn.Running {
-		conn.StartInformers()
-
-		// Wait a short time for initial sync
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	// Wait a short time for initial sync
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-		// Wait for namespace informer to sync
-		namespaceInformer := conn.Informer.Core().V1().Namespaces().Informer()
-		if !cache.WaitForCacheSync(ctx.Done(), namespaceInformer.HasSynced) {
-			return fmt.Errorf("timed out waiting for namespace cache to sync")
-		}
+	if _, err := p.clusterManager.Synced(ctx, clusterID, []string{"Namespace"}); err != nil {
+		return fmt.Errorf("failed to start namespace informer: %w", err)
 	}
 
 	return nil
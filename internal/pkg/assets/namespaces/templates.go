@@ -0,0 +1,282 @@
+package namespaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Errors returned for create requests that can't be applied
+var (
+	ErrInvalidName     = errors.New("invalid namespace name")
+	ErrUnknownTemplate = errors.New("unknown namespace template")
+	ErrProtected       = errors.New("namespace is protected")
+)
+
+// protectedNamespaces are never deleted through the API
+var protectedNamespaces = map[string]bool{
+	"default":         true,
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// Config holds the templates applied to namespaces created through the API
+type Config struct {
+	// DefaultTemplate is applied when a create request names no template
+	DefaultTemplate string              `yaml:"defaultTemplate"`
+	Templates       map[string]Template `yaml:"templates"`
+}
+
+// Template is a set of labels, annotations and resource defaults for new
+// namespaces. Quantities use Kubernetes notation, e.g. "500m" or "2Gi".
+type Template struct {
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+	// Quota is the hard limit of the namespace's ResourceQuota, e.g. requests.cpu: "4"
+	Quota map[string]string `yaml:"quota"`
+	// Limits are the container defaults of the namespace's LimitRange
+	Limits LimitDefaults `yaml:"limits"`
+}
+
+// LimitDefaults are the container defaults and bounds of a LimitRange
+type LimitDefaults struct {
+	Default        map[string]string `yaml:"default"`
+	DefaultRequest map[string]string `yaml:"defaultRequest"`
+	Max            map[string]string `yaml:"max"`
+}
+
+// Empty reports whether no LimitRange should be created
+func (l LimitDefaults) Empty() bool {
+	return len(l.Default) == 0 && len(l.DefaultRequest) == 0 && len(l.Max) == 0
+}
+
+// QuotaName and LimitRangeName name the objects created from a template
+const (
+	QuotaName      = "default-quota"
+	LimitRangeName = "default-limits"
+)
+
+// CreateRequest describes a namespace to create. Labels and annotations are
+// added on top of the template's.
+type CreateRequest struct {
+	Name        string            `json:"name"`
+	Template    string            `json:"template"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// CreateResult is the namespace created together with the objects its template added
+type CreateResult struct {
+	DryRun        bool              `json:"dryRun,omitempty"`
+	Namespace     *v1.Namespace     `json:"namespace"`
+	ResourceQuota *v1.ResourceQuota `json:"resourceQuota,omitempty"`
+	LimitRange    *v1.LimitRange    `json:"limitRange,omitempty"`
+}
+
+// Template returns the template a create request uses, if any
+func (p *NamespaceProvider) Template(name string) (Template, bool, error) {
+	if name == "" {
+		name = p.config.DefaultTemplate
+	}
+	if name == "" {
+		return Template{}, false, nil
+	}
+
+	template, ok := p.config.Templates[name]
+	if !ok {
+		return Template{}, false, fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+	return template, true, nil
+}
+
+// CreateNamespace creates a namespace with its template's ResourceQuota and
+// LimitRange. The namespace is removed again if they can't be created, so a
+// namespace never exists without the limits its template promises. With dryRun
+// only the namespace is checked by the cluster, since the quota and limit range
+// can't be dry-run inside a namespace that doesn't exist; they are returned as built.
+func (p *NamespaceProvider) CreateNamespace(ctx context.Context, clusterID string, req CreateRequest, dryRun bool) (*CreateResult, error) {
+	if errs := validation.IsDNS1123Label(req.Name); len(errs) > 0 {
+		return nil, fmt.Errorf("%w %q: %s", ErrInvalidName, req.Name, strings.Join(errs, ", "))
+	}
+
+	template, _, err := p.Template(req.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	quota, err := template.resourceQuota()
+	if err != nil {
+		return nil, err
+	}
+	limitRange, err := template.limitRange()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	ns := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        req.Name,
+			Labels:      merge(template.Labels, req.Labels),
+			Annotations: merge(template.Annotations, req.Annotations),
+		},
+	}
+
+	if dryRun {
+		created, err := conn.Client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create namespace: %w", err)
+		}
+		if quota != nil {
+			quota.Namespace = req.Name
+		}
+		if limitRange != nil {
+			limitRange.Namespace = req.Name
+		}
+		return &CreateResult{DryRun: true, Namespace: created, ResourceQuota: quota, LimitRange: limitRange}, nil
+	}
+
+	created, err := conn.Client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create namespace: %w", err)
+	}
+	result := &CreateResult{Namespace: created}
+
+	rollback := func(cause error) (*CreateResult, error) {
+		if err := conn.Client.CoreV1().Namespaces().Delete(context.WithoutCancel(ctx), req.Name, metav1.DeleteOptions{}); err != nil {
+			return nil, fmt.Errorf("%w; failed to remove namespace %s again: %v", cause, req.Name, err)
+		}
+		return nil, cause
+	}
+
+	if quota != nil {
+		result.ResourceQuota, err = conn.Client.CoreV1().ResourceQuotas(req.Name).Create(ctx, quota, metav1.CreateOptions{})
+		if err != nil {
+			return rollback(fmt.Errorf("failed to create resource quota: %w", err))
+		}
+	}
+
+	if limitRange != nil {
+		result.LimitRange, err = conn.Client.CoreV1().LimitRanges(req.Name).Create(ctx, limitRange, metav1.CreateOptions{})
+		if err != nil {
+			return rollback(fmt.Errorf("failed to create limit range: %w", err))
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteNamespace starts deleting a namespace. It returns the namespace while
+// it is terminating, or nil once it is gone. With dryRun the namespace is left
+// in place and returned as it is.
+func (p *NamespaceProvider) DeleteNamespace(ctx context.Context, clusterID, name string, dryRun bool) (*v1.Namespace, error) {
+	if protectedNamespaces[name] {
+		return nil, fmt.Errorf("%w: %s", ErrProtected, name)
+	}
+
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	opts := metav1.DeleteOptions{}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	if err := conn.Client.CoreV1().Namespaces().Delete(ctx, name, opts); err != nil {
+		return nil, fmt.Errorf("failed to delete namespace: %w", err)
+	}
+
+	ns, err := conn.Client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get deleted namespace: %w", err)
+	}
+
+	return ns, nil
+}
+
+// resourceQuota builds the template's ResourceQuota, or nil without a quota
+func (t Template) resourceQuota() (*v1.ResourceQuota, error) {
+	if len(t.Quota) == 0 {
+		return nil, nil
+	}
+
+	hard, err := resourceList(t.Quota)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quota in namespace template: %w", err)
+	}
+
+	return &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: QuotaName},
+		Spec:       v1.ResourceQuotaSpec{Hard: hard},
+	}, nil
+}
+
+// limitRange builds the template's LimitRange, or nil without limits
+func (t Template) limitRange() (*v1.LimitRange, error) {
+	if t.Limits.Empty() {
+		return nil, nil
+	}
+
+	item := v1.LimitRangeItem{Type: v1.LimitTypeContainer}
+	var err error
+	if item.Default, err = resourceList(t.Limits.Default); err != nil {
+		return nil, fmt.Errorf("invalid default limits in namespace template: %w", err)
+	}
+	if item.DefaultRequest, err = resourceList(t.Limits.DefaultRequest); err != nil {
+		return nil, fmt.Errorf("invalid default requests in namespace template: %w", err)
+	}
+	if item.Max, err = resourceList(t.Limits.Max); err != nil {
+		return nil, fmt.Errorf("invalid max limits in namespace template: %w", err)
+	}
+
+	return &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: LimitRangeName},
+		Spec:       v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{item}},
+	}, nil
+}
+
+// resourceList parses quantities keyed by resource name
+func resourceList(values map[string]string) (v1.ResourceList, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	list := make(v1.ResourceList, len(values))
+	for name, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		list[v1.ResourceName(name)] = quantity
+	}
+	return list, nil
+}
+
+// merge returns the union of two maps, preferring values from overrides
+func merge(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string, len(base)+len(overrides))
+	maps.Copy(merged, base)
+	maps.Copy(merged, overrides)
+	return merged
+}
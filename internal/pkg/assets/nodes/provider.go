@@ -0,0 +1,141 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Resources is an amount of CPU in millicores, memory in bytes and pod slots
+type Resources struct {
+	CPUMillicores int64 `json:"cpuMillicores"`
+	MemoryBytes   int64 `json:"memoryBytes"`
+	Pods          int64 `json:"pods"`
+}
+
+// Pod is a pod scheduled on a node with what it requests and is limited to
+type Pod struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Phase     string    `json:"phase"`
+	Requests  Resources `json:"requests"`
+	Limits    Resources `json:"limits"`
+}
+
+// NodePods lists the pods on a node. Requested sums the pods that still
+// hold resources, those not Succeeded or Failed, so it can be compared with
+// Allocatable.
+type NodePods struct {
+	ClusterID   string    `json:"clusterID"`
+	Node        string    `json:"node"`
+	Allocatable Resources `json:"allocatable"`
+	Requested   Resources `json:"requested"`
+	Limits      Resources `json:"limits"`
+	Pods        []Pod     `json:"pods"`
+}
+
+// NodeProvider reads nodes from the cluster and the pods on them from the store
+type NodeProvider struct {
+	clusterManager *cluster.Manager
+	store          store.Repository
+}
+
+// NewNodeProvider creates a new provider
+func NewNodeProvider(clusterManager *cluster.Manager, store store.Repository) *NodeProvider {
+	return &NodeProvider{
+		clusterManager: clusterManager,
+		store:          store,
+	}
+}
+
+// GetNode returns a node from the cluster
+func (p *NodeProvider) GetNode(ctx context.Context, clusterID, name string) (*corev1.Node, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	node, err := conn.Client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+
+	return node, nil
+}
+
+// Pods returns the pods scheduled on a node with their requests totalled
+// against the node's allocatable resources
+func (p *NodeProvider) Pods(ctx context.Context, clusterID, name string) (*NodePods, error) {
+	node, err := p.GetNode(ctx, clusterID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	if err := p.store.ListByNode(ctx, clusterID, name, &pods); err != nil {
+		return nil, fmt.Errorf("failed to list pods on node: %w", err)
+	}
+
+	result := &NodePods{
+		ClusterID:   clusterID,
+		Node:        name,
+		Allocatable: toResources(node.Status.Allocatable, node.Status.Allocatable.Pods().Value()),
+		Pods:        make([]Pod, 0, len(pods)),
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		entry := Pod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Phase:     string(pod.Status.Phase),
+			Requests:  toResources(assets.PodRequests(pod, false), 1),
+			Limits:    toResources(assets.PodRequests(pod, true), 1),
+		}
+		result.Pods = append(result.Pods, entry)
+
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		result.Requested.add(entry.Requests)
+		result.Limits.add(entry.Limits)
+	}
+
+	sort.Slice(result.Pods, func(i, j int) bool {
+		if result.Pods[i].Namespace != result.Pods[j].Namespace {
+			return result.Pods[i].Namespace < result.Pods[j].Namespace
+		}
+		return result.Pods[i].Name < result.Pods[j].Name
+	})
+
+	return result, nil
+}
+
+// add adds other to r
+func (r *Resources) add(other Resources) {
+	r.CPUMillicores += other.CPUMillicores
+	r.MemoryBytes += other.MemoryBytes
+	r.Pods += other.Pods
+}
+
+// toResources reads CPU and memory from a resource list
+func toResources(list corev1.ResourceList, pods int64) Resources {
+	return Resources{
+		CPUMillicores: quantity(list, corev1.ResourceCPU).MilliValue(),
+		MemoryBytes:   quantity(list, corev1.ResourceMemory).Value(),
+		Pods:          pods,
+	}
+}
+
+// quantity returns a resource of a list, zero when missing
+func quantity(list corev1.ResourceList, name corev1.ResourceName) *resource.Quantity {
+	value := list[name]
+	return &value
+}
@@ -0,0 +1,184 @@
+package pdbs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Budget is the state of a PodDisruptionBudget covering a workload
+type Budget struct {
+	Name               string `json:"name"`
+	MinAvailable       string `json:"minAvailable,omitempty"`
+	MaxUnavailable     string `json:"maxUnavailable,omitempty"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+	ExpectedPods       int32  `json:"expectedPods"`
+}
+
+// Disruption reports whether a voluntary disruption, such as an eviction
+// during a drain, is currently allowed for a set of pods
+type Disruption struct {
+	Allowed bool     `json:"allowed"`
+	Message string   `json:"message"`
+	Budgets []Budget `json:"budgets"`
+}
+
+// PDBProvider reads PodDisruptionBudgets from the cluster
+type PDBProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewPDBProvider creates a new provider
+func NewPDBProvider(clusterManager *cluster.Manager) *PDBProvider {
+	return &PDBProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// ListPDBs lists the pod disruption budgets of a namespace
+func (p *PDBProvider) ListPDBs(ctx context.Context, clusterID, namespace string) ([]policyv1.PodDisruptionBudget, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	list, err := conn.Client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	return list.Items, nil
+}
+
+// GetPDB gets a pod disruption budget
+func (p *PDBProvider) GetPDB(ctx context.Context, clusterID, namespace, name string) (*policyv1.PodDisruptionBudget, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	pdb, err := conn.Client.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod disruption budget: %w", err)
+	}
+
+	return pdb, nil
+}
+
+// Disruption returns the budgets whose selector matches pods with the given
+// labels. A disruption is allowed when no budget covers the pods or every
+// covering budget has a disruption to spare.
+func (p *PDBProvider) Disruption(ctx context.Context, clusterID, namespace string, podLabels map[string]string) (*Disruption, error) {
+	pdbs, err := p.ListPDBs(ctx, clusterID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return Evaluate(pdbs, podLabels), nil
+}
+
+// WorkloadKinds are the controllers whose detail includes a disruption indicator
+var WorkloadKinds = []string{"Deployment", "StatefulSet", "ReplicaSet", "DaemonSet"}
+
+// Workload is a workload controller with its disruption readiness
+type Workload struct {
+	Object     runtime.Object `json:"object"`
+	Disruption *Disruption    `json:"disruption"`
+}
+
+// GetWorkload gets a workload controller and evaluates the budgets covering
+// the pods of its template
+func (p *PDBProvider) GetWorkload(ctx context.Context, clusterID, kind, namespace, name string) (*Workload, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	obj, err := assets.GetObject(ctx, conn.Client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object: %w", err)
+	}
+	podLabels, _, _ := unstructured.NestedStringMap(content, "spec", "template", "metadata", "labels")
+
+	pdbs, err := conn.Client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	return &Workload{
+		Object:     obj,
+		Disruption: Evaluate(pdbs.Items, podLabels),
+	}, nil
+}
+
+// Evaluate checks a set of budgets against pods with the given labels
+func Evaluate(pdbs []policyv1.PodDisruptionBudget, podLabels map[string]string) *Disruption {
+	disruption := &Disruption{
+		Allowed: true,
+		Budgets: make([]Budget, 0),
+	}
+
+	var blocking []string
+	for _, pdb := range pdbs {
+		// A nil selector matches nothing; an empty one matches every pod
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+
+		budget := Budget{
+			Name:               pdb.Name,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			CurrentHealthy:     pdb.Status.CurrentHealthy,
+			DesiredHealthy:     pdb.Status.DesiredHealthy,
+			ExpectedPods:       pdb.Status.ExpectedPods,
+		}
+		if pdb.Spec.MinAvailable != nil {
+			budget.MinAvailable = pdb.Spec.MinAvailable.String()
+		}
+		if pdb.Spec.MaxUnavailable != nil {
+			budget.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+		}
+		disruption.Budgets = append(disruption.Budgets, budget)
+
+		if pdb.Status.DisruptionsAllowed <= 0 {
+			blocking = append(blocking, pdb.Name)
+		}
+	}
+
+	sort.Slice(disruption.Budgets, func(i, j int) bool {
+		return disruption.Budgets[i].Name < disruption.Budgets[j].Name
+	})
+
+	switch {
+	case len(disruption.Budgets) == 0:
+		disruption.Message = "no pod disruption budget covers these pods"
+	case len(blocking) > 0:
+		sort.Strings(blocking)
+		disruption.Allowed = false
+		disruption.Message = fmt.Sprintf("evictions are blocked by %s", strings.Join(blocking, ", "))
+	default:
+		disruption.Message = "evictions are allowed"
+	}
+
+	return disruption
+}
@@ -8,8 +8,10 @@ import (
 
 	resources "github.com/jbetancur/dashboard/internal/pkg/assets"
 	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	"github.com/jbetancur/dashboard/internal/pkg/throttle"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -21,6 +23,7 @@ type Manager struct {
 	client         *kubernetes.Clientset
 	informer       informers.SharedInformerFactory
 	eventPublisher messagingtypes.Publisher
+	throttle       *throttle.Throttle
 	logger         *slog.Logger
 	stopCh         chan struct{}
 }
@@ -30,6 +33,7 @@ func NewManager(
 	clusterID string,
 	eventPublisher messagingtypes.Publisher,
 	client *kubernetes.Clientset,
+	throttle *throttle.Throttle,
 	logger *slog.Logger,
 ) *Manager {
 	// Create a shared informer factory
@@ -40,6 +44,7 @@ func NewManager(
 		client:         client,
 		informer:       informer,
 		eventPublisher: eventPublisher,
+		throttle:       throttle,
 		logger:         logger,
 		stopCh:         make(chan struct{}),
 	}
@@ -51,6 +56,7 @@ func (pm *Manager) StartInformer() error {
 	podInformer := pm.informer.Core().V1().Pods().Informer()
 	if _, err := podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
+			pm.throttle.Record(obj)
 			pod := obj.(*v1.Pod)
 			payload := resources.ResourcePayload[v1.Pod]{
 				ClusterID: pm.clusterID,
@@ -67,6 +73,14 @@ func (pm *Manager) StartInformer() error {
 			}
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Resyncs are skipped unless an update of the object was dropped under load
+			if resources.Unchanged(oldObj, newObj) && !pm.throttle.Stale(newObj) {
+				return
+			}
+			if !pm.throttle.AllowUpdate(newObj, resources.StatusOnly(oldObj, newObj)) {
+				return
+			}
+
 			pod := newObj.(*v1.Pod)
 			payload := resources.ResourcePayload[v1.Pod]{
 				ClusterID: pm.clusterID,
@@ -82,6 +96,7 @@ func (pm *Manager) StartInformer() error {
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
+			pm.throttle.Record(obj)
 			pod := obj.(*v1.Pod)
 			payload := resources.ResourcePayload[v1.Pod]{
 				ClusterID: pm.clusterID,
@@ -111,6 +126,33 @@ func (pm *Manager) StartInformer() error {
 	return nil
 }
 
+// Resync publishes every pod in the informer cache as added so the API can
+// rebuild its copy. It returns the number of pods published.
+func (pm *Manager) Resync() (int, error) {
+	items, err := pm.informer.Core().V1().Pods().Lister().List(labels.Everything())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list cached pods: %w", err)
+	}
+
+	for _, item := range items {
+		payload := resources.ResourcePayload[v1.Pod]{
+			ClusterID: pm.clusterID,
+			Resource:  *item,
+		}
+
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, fmt.Errorf("failed to serialize pod: %w", err)
+		}
+
+		if err := pm.eventPublisher.Publish("pod_added", data); err != nil {
+			return 0, fmt.Errorf("failed to publish pod: %w", err)
+		}
+	}
+
+	return len(items), nil
+}
+
 // Stop stops the pod manager
 func (pm *Manager) Stop() {
 	close(pm.stopCh)
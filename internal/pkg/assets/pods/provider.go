@@ -4,76 +4,68 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"time"
 
 	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/podexec"
+	"github.com/jbetancur/dashboard/internal/pkg/restarts"
+	"github.com/jbetancur/dashboard/internal/pkg/tunnel"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
-	informersv1 "k8s.io/client-go/informers/core/v1"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
 
 // PodProvider implements PodProvider for multiple clusters
 type PodProvider struct {
 	clusterManager *cluster.Manager
+	informers      *cluster.NamespaceInformers
+	tunnel         *tunnel.Client
 }
 
-// NewPodProvider creates a new provider
-func NewPodProvider(clusterManager *cluster.Manager) *PodProvider {
+// NewPodProvider creates a new provider. Pods are read from informers
+// started per namespace on demand. Log streams for clusters the tunnel
+// proxies go through the cluster's agent.
+func NewPodProvider(clusterManager *cluster.Manager, namespaceInformers *cluster.NamespaceInformers, tunnelClient *tunnel.Client) *PodProvider {
 	return &PodProvider{
 		clusterManager: clusterManager,
+		informers:      namespaceInformers,
+		tunnel:         tunnelClient,
 	}
 }
 
-// ListPods lists pods in a specific namespace from a specific cluster
+// ListPods lists pods in a specific namespace from a specific cluster. Pods
+// of all namespaces are listed from the API server, since watching every pod
+// of a large cluster costs more memory than an occasional list.
 func (p *PodProvider) ListPods(ctx context.Context, clusterID, namespace string) ([]v1.Pod, error) {
-	// Get the cluster connection
-	conn, err := p.clusterManager.GetCluster(clusterID)
-	if err != nil {
-		return nil, fmt.Errorf("cluster not found: %w", err)
-	}
-
-	// Check if the informer factory is initialized
-	if conn.Informer == nil {
-		return nil, fmt.Errorf("informer factory not initialized for cluster %s", clusterID)
-	}
-
-	// Get the pod informer - ensure it's started
-	podInformer := getPodInformer(conn.Informer, namespace)
-	if !cache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
-		return nil, fmt.Errorf("timed out waiting for pod cache to sync")
-	}
-
-	// Use the lister to get pods from cache
-	var pods []v1.Pod
-	if namespace != "" {
-		// List from specific namespace
-		nsLister := podInformer.Lister().Pods(namespace)
-		podList, err := nsLister.List(labels.Everything())
+	if namespace == "" {
+		conn, err := p.clusterManager.GetCluster(clusterID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list pods from cache: %w", err)
+			return nil, fmt.Errorf("cluster not found: %w", err)
 		}
 
-		// Convert from *v1.Pod to v1.Pod
-		pods = make([]v1.Pod, 0, len(podList))
-		for _, pod := range podList {
-			pods = append(pods, *pod.DeepCopy())
-		}
-	} else {
-		// List across all namespaces
-		podList, err := podInformer.Lister().List(labels.Everything())
+		podList, err := conn.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
 		if err != nil {
-			return nil, fmt.Errorf("failed to list pods from cache: %w", err)
+			return nil, fmt.Errorf("failed to list pods: %w", err)
 		}
+		return podList.Items, nil
+	}
 
-		// Convert from *v1.Pod to v1.Pod
-		pods = make([]v1.Pod, 0, len(podList))
-		for _, pod := range podList {
-			pods = append(pods, *pod.DeepCopy())
-		}
+	factory, release, err := p.informers.Acquire(ctx, clusterID, namespace, podInformer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start pod informer: %w", err)
+	}
+	defer release()
+
+	podList, err := factory.Core().V1().Pods().Lister().Pods(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods from cache: %w", err)
+	}
+
+	// Convert from *v1.Pod to v1.Pod
+	pods := make([]v1.Pod, 0, len(podList))
+	for _, pod := range podList {
+		pods = append(pods, *pod.DeepCopy())
 	}
 
 	return pods, nil
@@ -81,28 +73,20 @@ func (p *PodProvider) ListPods(ctx context.Context, clusterID, namespace string)
 
 // GetPod gets a specific pod from a specific cluster and namespace
 func (p *PodProvider) GetPod(ctx context.Context, clusterID, namespace, podName string) (*v1.Pod, error) {
-	// Get the cluster connection
-	conn, err := p.clusterManager.GetCluster(clusterID)
+	factory, release, err := p.informers.Acquire(ctx, clusterID, namespace, podInformer)
 	if err != nil {
-		return nil, fmt.Errorf("cluster not found: %w", err)
-	}
-
-	// Check if the informer factory is initialized
-	if conn.Informer == nil {
-		return nil, fmt.Errorf("informer factory not initialized for cluster %s", clusterID)
-	}
-
-	// Get the pod informer - ensure it's started
-	podInformer := getPodInformer(conn.Informer, namespace)
-	if !cache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
-		return nil, fmt.Errorf("timed out waiting for pod cache to sync")
+		return nil, fmt.Errorf("failed to start pod informer: %w", err)
 	}
+	defer release()
 
 	// Get from cache
-	nsLister := podInformer.Lister().Pods(namespace)
-	pod, err := nsLister.Get(podName)
+	pod, err := factory.Core().V1().Pods().Lister().Pods(namespace).Get(podName)
 	if err != nil {
 		// If not found in cache or other error, try direct API call as fallback
+		conn, err := p.clusterManager.GetCluster(clusterID)
+		if err != nil {
+			return nil, fmt.Errorf("cluster not found: %w", err)
+		}
 		return conn.Client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
 	}
 
@@ -110,8 +94,50 @@ func (p *PodProvider) GetPod(ctx context.Context, clusterID, namespace, podName
 	return pod.DeepCopy(), nil
 }
 
+// DeletePod deletes a pod from a specific cluster and namespace. With dryRun
+// the cluster validates the deletion without removing the pod.
+func (p *PodProvider) DeletePod(ctx context.Context, clusterID, namespace, podName string, dryRun bool) error {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("cluster not found: %w", err)
+	}
+
+	var opts metav1.DeleteOptions
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	if err := conn.Client.CoreV1().Pods(namespace).Delete(ctx, podName, opts); err != nil {
+		return fmt.Errorf("failed to delete pod: %w", err)
+	}
+
+	return nil
+}
+
 // GetPodLogs fetches pod logs (we still use direct API call for logs)
 func (p *PodProvider) GetPodLogs(ctx context.Context, clusterID, namespace, podName, containerName string, tailLines int64) (io.ReadCloser, error) {
+	return p.podLogs(ctx, clusterID, namespace, podName, containerName, tailLines, true)
+}
+
+// ReadPodLogs returns the logs a container has written so far, ending once
+// they are read instead of following new lines
+func (p *PodProvider) ReadPodLogs(ctx context.Context, clusterID, namespace, podName, containerName string, tailLines int64) (io.ReadCloser, error) {
+	return p.podLogs(ctx, clusterID, namespace, podName, containerName, tailLines, false)
+}
+
+// podLogs opens the logs of a container
+func (p *PodProvider) podLogs(ctx context.Context, clusterID, namespace, podName, containerName string, tailLines int64, follow bool) (io.ReadCloser, error) {
+	// The API server may not be reachable from here, so ask the agent to stream them
+	if p.tunnel.Proxied(clusterID) {
+		return p.tunnel.Logs(ctx, tunnel.Open{
+			ClusterID: clusterID,
+			Namespace: namespace,
+			Pod:       podName,
+			Container: containerName,
+			TailLines: tailLines,
+			Follow:    follow,
+		})
+	}
+
 	// Get the cluster connection
 	conn, err := p.clusterManager.GetCluster(clusterID)
 	if err != nil {
@@ -121,7 +147,7 @@ func (p *PodProvider) GetPodLogs(ctx context.Context, clusterID, namespace, podN
 	// Prepare log options
 	options := &v1.PodLogOptions{
 		Container: containerName,
-		Follow:    true,
+		Follow:    follow,
 	}
 
 	if tailLines > 0 {
@@ -132,51 +158,32 @@ func (p *PodProvider) GetPodLogs(ctx context.Context, clusterID, namespace, podN
 	return conn.Client.CoreV1().Pods(namespace).GetLogs(podName, options).Stream(ctx)
 }
 
-// Helper function to get or create the pod informer
-func getPodInformer(factory informers.SharedInformerFactory, namespace string) informersv1.PodInformer {
-	if namespace != "" {
-		return factory.InformerFor(
-			&v1.Pod{},
-			func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
-				return informersv1.NewPodInformer(
-					client,
-					namespace,
-					resyncPeriod,
-					cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-				)
-			},
-		).(informersv1.PodInformer)
-	}
-
-	// Use factory's standard pod informer for all namespaces
-	return factory.Core().V1().Pods()
-}
+// Attach connects streams to the running process of a container, through
+// the cluster's agent when the API server isn't reachable from here
+func (p *PodProvider) Attach(ctx context.Context, clusterID, namespace, podName string, opts podexec.Options) error {
+	if p.tunnel.Proxied(clusterID) {
+		return p.tunnel.Attach(ctx, tunnel.Open{ClusterID: clusterID, Namespace: namespace, Pod: podName}, opts)
+	}
 
-// EnsureInformersStarted makes sure the informers are started for the given cluster
-func (p *PodProvider) EnsureInformersStarted(clusterID string) error {
 	conn, err := p.clusterManager.GetCluster(clusterID)
 	if err != nil {
 		return fmt.Errorf("cluster not found: %w", err)
 	}
 
-	if conn.Informer == nil {
-		conn.InitializeInformers()
-	}
-
-	// Check if informers are running
-	if !conn.Running {
-		conn.StartInformers()
-
-		// Wait a short time for initial sync
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	return podexec.Attach(ctx, conn.Config, namespace, podName, opts)
+}
 
-		// Wait for pod informer to sync
-		podInformer := conn.Informer.Core().V1().Pods().Informer()
-		if !cache.WaitForCacheSync(ctx.Done(), podInformer.HasSynced) {
-			return fmt.Errorf("timed out waiting for pod cache to sync")
-		}
+// RestartSummary explains why the containers of a pod restarted
+func (p *PodProvider) RestartSummary(ctx context.Context, clusterID, namespace, podName string) (*restarts.Summary, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
 	}
 
-	return nil
+	return restarts.Load(ctx, conn.Client, namespace, podName)
+}
+
+// podInformer registers the pod informer of a namespace's informer factory
+func podInformer(factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+	return factory.Core().V1().Pods().Informer()
 }
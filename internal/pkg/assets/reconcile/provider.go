@@ -0,0 +1,253 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// KindResult summarizes the fixes applied to one kind
+type KindResult struct {
+	Kind      string `json:"kind"`
+	Live      int    `json:"live"`
+	Stored    int    `json:"stored"`
+	Created   int    `json:"created"`   // Live objects missing from the store
+	Updated   int    `json:"updated"`   // Stored objects behind the live resource version
+	Unchanged int    `json:"unchanged"` // Stored objects already up to date, rewritten anyway
+	Deleted   int    `json:"deleted"`   // Stored objects no longer in the cluster
+	Failed    int    `json:"failed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the outcome of reconciling one cluster
+type Report struct {
+	ClusterID string       `json:"clusterID"`
+	StartedAt time.Time    `json:"startedAt"`
+	Duration  string       `json:"duration"`
+	Fixed     int          `json:"fixed"`
+	Kinds     []KindResult `json:"kinds"`
+}
+
+// kind lists the live and stored objects of a mirrored kind
+type kind struct {
+	live   func(ctx context.Context, client kubernetes.Interface) ([]runtime.Object, error)
+	stored func(ctx context.Context, repo store.Repository, clusterID string) ([]metav1.Object, error)
+}
+
+// kinds are the kinds the agents mirror into the store
+var kinds = map[string]kind{
+	"Namespace": {
+		live: func(ctx context.Context, client kubernetes.Interface) ([]runtime.Object, error) {
+			list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list namespaces: %w", err)
+			}
+			objects := make([]runtime.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		stored: func(ctx context.Context, repo store.Repository, clusterID string) ([]metav1.Object, error) {
+			var items []corev1.Namespace
+			if err := repo.List(ctx, clusterID, "", "Namespace", &items); err != nil {
+				return nil, err
+			}
+			objects := make([]metav1.Object, 0, len(items))
+			for i := range items {
+				objects = append(objects, &items[i])
+			}
+			return objects, nil
+		},
+	},
+	"Pod": {
+		live: func(ctx context.Context, client kubernetes.Interface) ([]runtime.Object, error) {
+			list, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list pods: %w", err)
+			}
+			objects := make([]runtime.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		stored: func(ctx context.Context, repo store.Repository, clusterID string) ([]metav1.Object, error) {
+			var items []corev1.Pod
+			if err := repo.List(ctx, clusterID, "", "Pod", &items); err != nil {
+				return nil, err
+			}
+			objects := make([]metav1.Object, 0, len(items))
+			for i := range items {
+				objects = append(objects, &items[i])
+			}
+			return objects, nil
+		},
+	},
+	"ConfigMap": {
+		live: func(ctx context.Context, client kubernetes.Interface) ([]runtime.Object, error) {
+			list, err := client.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list config maps: %w", err)
+			}
+			objects := make([]runtime.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		stored: func(ctx context.Context, repo store.Repository, clusterID string) ([]metav1.Object, error) {
+			var items []corev1.ConfigMap
+			if err := repo.List(ctx, clusterID, "", "ConfigMap", &items); err != nil {
+				return nil, err
+			}
+			objects := make([]metav1.Object, 0, len(items))
+			for i := range items {
+				objects = append(objects, &items[i])
+			}
+			return objects, nil
+		},
+	},
+}
+
+// defaultKinds are reconciled for clusters whose agent did not say which
+// kinds it mirrors, in the order they are reported
+var defaultKinds = []string{"Namespace", "Pod", "ConfigMap"}
+
+// ReconcileProvider repairs the stored copy of a cluster from the API server
+type ReconcileProvider struct {
+	clusterManager *cluster.Manager
+	store          store.Repository
+}
+
+// NewReconcileProvider creates a new provider
+func NewReconcileProvider(clusterManager *cluster.Manager, store store.Repository) *ReconcileProvider {
+	return &ReconcileProvider{
+		clusterManager: clusterManager,
+		store:          store,
+	}
+}
+
+// Reconcile lists every kind the cluster's agent mirrors from the API
+// server, upserts all live objects and deletes stored objects that no longer
+// exist. A kind that cannot be listed is reported and the others still run.
+func (p *ReconcileProvider) Reconcile(ctx context.Context, clusterID string) (*Report, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	report := &Report{
+		ClusterID: clusterID,
+		StartedAt: time.Now(),
+		Kinds:     make([]KindResult, 0),
+	}
+
+	for _, name := range p.enabledKinds(ctx, clusterID) {
+		result := p.reconcileKind(ctx, conn.Client, clusterID, name, kinds[name])
+		report.Fixed += result.Created + result.Updated + result.Deleted
+		report.Kinds = append(report.Kinds, result)
+	}
+
+	report.Duration = time.Since(report.StartedAt).Round(time.Millisecond).String()
+	return report, nil
+}
+
+// enabledKinds returns the mirrored kinds of a cluster, as advertised by its
+// agent when it registered
+func (p *ReconcileProvider) enabledKinds(ctx context.Context, clusterID string) []string {
+	var info cluster.ClusterInfo
+	if err := p.store.GetCluster(ctx, clusterID, &info); err != nil || info.Agent == nil || len(info.Agent.Kinds) == 0 {
+		return defaultKinds
+	}
+
+	enabled := make([]string, 0, len(info.Agent.Kinds))
+	for _, name := range defaultKinds {
+		if slices.Contains(info.Agent.Kinds, name) {
+			enabled = append(enabled, name)
+		}
+	}
+	return enabled
+}
+
+// reconcileKind brings the stored objects of one kind in line with the cluster
+func (p *ReconcileProvider) reconcileKind(ctx context.Context, client kubernetes.Interface, clusterID, name string, k kind) KindResult {
+	result := KindResult{Kind: name}
+
+	live, err := k.live(ctx, client)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	stored, err := k.stored(ctx, p.store, clusterID)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to list stored objects: %v", err)
+		return result
+	}
+
+	result.Live = len(live)
+	result.Stored = len(stored)
+
+	storedVersions := make(map[string]string, len(stored))
+	for _, obj := range stored {
+		storedVersions[objectKey(obj)] = obj.GetResourceVersion()
+	}
+
+	liveKeys := make(map[string]bool, len(live))
+	for _, obj := range live {
+		meta, ok := obj.(metav1.Object)
+		if !ok {
+			continue
+		}
+		key := objectKey(meta)
+		liveKeys[key] = true
+
+		if err := p.store.Save(ctx, clusterID, obj); err != nil {
+			result.Failed++
+			result.Error = fmt.Sprintf("failed to store %s: %v", key, err)
+			continue
+		}
+
+		version, ok := storedVersions[key]
+		switch {
+		case !ok:
+			result.Created++
+		case version != meta.GetResourceVersion():
+			result.Updated++
+		default:
+			result.Unchanged++
+		}
+	}
+
+	for _, obj := range stored {
+		if liveKeys[objectKey(obj)] {
+			continue
+		}
+
+		if err := p.store.Delete(ctx, clusterID, obj.GetNamespace(), name, obj.GetName()); err != nil {
+			result.Failed++
+			result.Error = fmt.Sprintf("failed to delete %s: %v", objectKey(obj), err)
+			continue
+		}
+		result.Deleted++
+	}
+
+	return result
+}
+
+// objectKey identifies an object within a kind
+func objectKey(obj metav1.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,322 @@
+package rightsizing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/metrics"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Recommendation statuses
+const (
+	StatusOK               = "ok"
+	StatusOverProvisioned  = "over-provisioned"
+	StatusUnderProvisioned = "under-provisioned"
+	StatusNoRequests       = "no-requests"
+)
+
+// Usage sources
+const (
+	SourceHistory = "history"
+	SourceCurrent = "current"
+)
+
+// Policy controls how recommendations are computed
+type Policy struct {
+	// Lookback is how much sampled history to use
+	Lookback time.Duration `yaml:"lookback"`
+	// Percentile of observed usage that requests should cover
+	Percentile float64 `yaml:"percentile"`
+	// CPUHeadroom and MemoryHeadroom are added on top of observed usage, e.g. 0.2 = 20%
+	CPUHeadroom    float64 `yaml:"cpuHeadroom"`
+	MemoryHeadroom float64 `yaml:"memoryHeadroom"`
+	// A request is over-provisioned when the suggestion is below OverThreshold times the request
+	OverThreshold float64 `yaml:"overThreshold"`
+	// A request is under-provisioned when the suggestion is above UnderThreshold times the request
+	UnderThreshold float64 `yaml:"underThreshold"`
+	// Suggestions never go below these floors
+	MinCPUMillicores int64 `yaml:"minCPUMillicores"`
+	MinMemoryBytes   int64 `yaml:"minMemoryBytes"`
+}
+
+// withDefaults fills in unset policy values
+func (p Policy) withDefaults() Policy {
+	if p.Lookback <= 0 {
+		p.Lookback = 24 * time.Hour
+	}
+	if p.Percentile <= 0 || p.Percentile > 100 {
+		p.Percentile = 95
+	}
+	if p.CPUHeadroom <= 0 {
+		p.CPUHeadroom = 0.15
+	}
+	if p.MemoryHeadroom <= 0 {
+		p.MemoryHeadroom = 0.25
+	}
+	if p.OverThreshold <= 0 {
+		p.OverThreshold = 0.5
+	}
+	if p.UnderThreshold <= 0 {
+		p.UnderThreshold = 1.0
+	}
+	if p.MinCPUMillicores <= 0 {
+		p.MinCPUMillicores = 10
+	}
+	if p.MinMemoryBytes <= 0 {
+		p.MinMemoryBytes = 32 << 20
+	}
+	return p
+}
+
+// SampleSource reads stored metric samples
+type SampleSource interface {
+	ListMetricSamples(ctx context.Context, query metrics.SampleQuery, results *[]metrics.Sample) error
+}
+
+// Resource compares observed usage with configured values for one resource
+type Resource struct {
+	Request   int64  `json:"request"`
+	Limit     int64  `json:"limit"`
+	Observed  int64  `json:"observed"`
+	Peak      int64  `json:"peak"`
+	Suggested int64  `json:"suggested"`
+	Status    string `json:"status"`
+}
+
+// Recommendation is the right-sizing result for a container of a workload.
+// CPU values are millicores and memory values are bytes.
+type Recommendation struct {
+	Namespace    string   `json:"namespace"`
+	WorkloadKind string   `json:"workloadKind"`
+	Workload     string   `json:"workload"`
+	Container    string   `json:"container"`
+	Pods         int      `json:"pods"`
+	Samples      int      `json:"samples"`
+	Source       string   `json:"source"`
+	CPU          Resource `json:"cpu"`
+	Memory       Resource `json:"memory"`
+	// SuggestedMemoryLimit covers peak usage plus headroom
+	SuggestedMemoryLimit int64 `json:"suggestedMemoryLimit"`
+}
+
+// RightsizingProvider recommends requests from observed usage
+type RightsizingProvider struct {
+	clusterManager *cluster.Manager
+	metrics        *metrics.MetricsProvider
+	samples        SampleSource
+	policy         Policy
+}
+
+// NewRightsizingProvider creates a new provider
+func NewRightsizingProvider(clusterManager *cluster.Manager, metricsProvider *metrics.MetricsProvider,
+	samples SampleSource, policy Policy) *RightsizingProvider {
+	return &RightsizingProvider{
+		clusterManager: clusterManager,
+		metrics:        metricsProvider,
+		samples:        samples,
+		policy:         policy.withDefaults(),
+	}
+}
+
+// Policy returns the effective policy
+func (p *RightsizingProvider) Policy() Policy {
+	return p.policy
+}
+
+// workloadContainer groups a container across the pods of a workload
+type workloadContainer struct {
+	rec    Recommendation
+	cpu    []int64
+	memory []int64
+}
+
+// Recommend returns recommendations for every container of the running
+// workloads in a namespace. Sampled history is used when available, otherwise
+// the current metrics-server reading.
+func (p *RightsizingProvider) Recommend(ctx context.Context, clusterID, namespace string) ([]Recommendation, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	pods, err := conn.Client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	// Current usage is only fetched when some pod has no history
+	var current map[string]metrics.PodMetrics
+
+	groups := make(map[string]*workloadContainer)
+	since := time.Now().Add(-p.policy.Lookback)
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		kind, workload := assets.WorkloadOf(pod)
+
+		var samples []metrics.Sample
+		if p.samples != nil {
+			if err := p.samples.ListMetricSamples(ctx, metrics.SampleQuery{
+				ClusterID: clusterID,
+				Kind:      metrics.KindContainer,
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Since:     since,
+			}, &samples); err != nil {
+				return nil, fmt.Errorf("failed to read metric samples: %w", err)
+			}
+		}
+
+		source := SourceHistory
+		if len(samples) == 0 {
+			if current == nil {
+				current, err = p.currentUsage(ctx, clusterID, namespace)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			source = SourceCurrent
+			samples = currentSamples(current[pod.Namespace+"/"+pod.Name])
+		}
+
+		for _, container := range pod.Spec.Containers {
+			key := pod.Namespace + "/" + kind + "/" + workload + "/" + container.Name
+
+			group, ok := groups[key]
+			if !ok {
+				group = &workloadContainer{rec: Recommendation{
+					Namespace:    pod.Namespace,
+					WorkloadKind: kind,
+					Workload:     workload,
+					Container:    container.Name,
+					Source:       source,
+					CPU: Resource{
+						Request: container.Resources.Requests.Cpu().MilliValue(),
+						Limit:   container.Resources.Limits.Cpu().MilliValue(),
+					},
+					Memory: Resource{
+						Request: container.Resources.Requests.Memory().Value(),
+						Limit:   container.Resources.Limits.Memory().Value(),
+					},
+				}}
+				groups[key] = group
+			}
+
+			group.rec.Pods++
+			if source == SourceCurrent {
+				group.rec.Source = SourceCurrent
+			}
+
+			for _, sample := range samples {
+				if sample.Container != container.Name {
+					continue
+				}
+				group.cpu = append(group.cpu, sample.CPUMillicores)
+				group.memory = append(group.memory, sample.MemoryBytes)
+			}
+		}
+	}
+
+	recommendations := make([]Recommendation, 0, len(groups))
+	for _, group := range groups {
+		if len(group.cpu) == 0 {
+			continue
+		}
+
+		rec := group.rec
+		rec.Samples = len(group.cpu)
+		p.evaluate(&rec.CPU, group.cpu, p.policy.CPUHeadroom, p.policy.MinCPUMillicores, 5)
+		p.evaluate(&rec.Memory, group.memory, p.policy.MemoryHeadroom, p.policy.MinMemoryBytes, 1<<20)
+		rec.SuggestedMemoryLimit = roundUp(withHeadroom(rec.Memory.Peak, p.policy.MemoryHeadroom), 1<<20)
+
+		recommendations = append(recommendations, rec)
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Workload != b.Workload {
+			return a.Workload < b.Workload
+		}
+		return a.Container < b.Container
+	})
+
+	return recommendations, nil
+}
+
+// evaluate fills in observed, peak, suggested and status for one resource
+func (p *RightsizingProvider) evaluate(resource *Resource, values []int64, headroom float64, floor, step int64) {
+	resource.Observed = percentile(values, p.policy.Percentile)
+	resource.Peak = percentile(values, 100)
+	resource.Suggested = max(roundUp(withHeadroom(resource.Observed, headroom), step), floor)
+
+	switch {
+	case resource.Request == 0:
+		resource.Status = StatusNoRequests
+	case float64(resource.Suggested) < float64(resource.Request)*p.policy.OverThreshold:
+		resource.Status = StatusOverProvisioned
+	case float64(resource.Suggested) > float64(resource.Request)*p.policy.UnderThreshold:
+		resource.Status = StatusUnderProvisioned
+	default:
+		resource.Status = StatusOK
+	}
+}
+
+// currentUsage returns metrics-server readings keyed by namespace/pod
+func (p *RightsizingProvider) currentUsage(ctx context.Context, clusterID, namespace string) (map[string]metrics.PodMetrics, error) {
+	podMetrics, err := p.metrics.ListPodMetrics(ctx, clusterID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]metrics.PodMetrics, len(podMetrics))
+	for _, pod := range podMetrics {
+		usage[pod.Namespace+"/"+pod.Name] = pod
+	}
+
+	return usage, nil
+}
+
+// currentSamples turns a metrics-server reading into container samples
+func currentSamples(pod metrics.PodMetrics) []metrics.Sample {
+	samples := make([]metrics.Sample, 0, len(pod.Containers))
+	for _, container := range pod.Containers {
+		samples = append(samples, metrics.Sample{
+			Container:     container.Name,
+			CPUMillicores: container.Usage.Cpu().MilliValue(),
+			MemoryBytes:   container.Usage.Memory().Value(),
+		})
+	}
+	return samples
+}
+
+// percentile returns the nearest-rank percentile of values
+func percentile(values []int64, pct float64) int64 {
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(pct/100*float64(len(sorted)))) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
+
+// withHeadroom adds a fractional headroom to a value
+func withHeadroom(value int64, headroom float64) int64 {
+	return int64(math.Ceil(float64(value) * (1 + headroom)))
+}
+
+// roundUp rounds a value up to a multiple of step
+func roundUp(value, step int64) int64 {
+	if value%step == 0 {
+		return value
+	}
+	return (value/step + 1) * step
+}
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Default storage class annotations, current and beta
+const (
+	defaultClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// StorageClass summarizes a storage class and the CSI driver behind it
+type StorageClass struct {
+	Name                 string            `json:"name"`
+	Provisioner          string            `json:"provisioner"`
+	Parameters           map[string]string `json:"parameters,omitempty"`
+	ReclaimPolicy        string            `json:"reclaimPolicy,omitempty"`
+	VolumeBindingMode    string            `json:"volumeBindingMode,omitempty"`
+	AllowVolumeExpansion bool              `json:"allowVolumeExpansion"`
+	Default              bool              `json:"default"`
+	// CSIDriver is set when the provisioner is a registered CSI driver
+	CSIDriver string `json:"csiDriver,omitempty"`
+}
+
+// Inventory lists the storage classes and CSI drivers of a cluster
+type Inventory struct {
+	ClusterID      string                `json:"clusterID"`
+	DefaultClass   string                `json:"defaultClass,omitempty"`
+	StorageClasses []StorageClass        `json:"storageClasses"`
+	CSIDrivers     []storagev1.CSIDriver `json:"csiDrivers"`
+	Warnings       []string              `json:"warnings"`
+}
+
+// Claim is a persistent volume claim with its storage class resolved
+type Claim struct {
+	Name         string        `json:"name"`
+	Phase        string        `json:"phase"`
+	Capacity     string        `json:"capacity,omitempty"`
+	VolumeName   string        `json:"volumeName,omitempty"`
+	StorageClass *StorageClass `json:"storageClass,omitempty"`
+	Warning      string        `json:"warning,omitempty"`
+}
+
+// NamespaceStorage lists the claims of a namespace with storage class warnings
+type NamespaceStorage struct {
+	ClusterID    string   `json:"clusterID"`
+	Namespace    string   `json:"namespace"`
+	DefaultClass string   `json:"defaultClass,omitempty"`
+	Claims       []Claim  `json:"claims"`
+	Warnings     []string `json:"warnings"`
+}
+
+// StorageProvider reads storage classes, CSI drivers and claims
+type StorageProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewStorageProvider creates a new provider
+func NewStorageProvider(clusterManager *cluster.Manager) *StorageProvider {
+	return &StorageProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// Inventory returns the storage classes and CSI drivers of a cluster with
+// warnings for a missing or ambiguous default class
+func (p *StorageProvider) Inventory(ctx context.Context, clusterID string) (*Inventory, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	classes, drivers, err := list(ctx, conn.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &Inventory{
+		ClusterID:      clusterID,
+		StorageClasses: classes,
+		CSIDrivers:     drivers,
+		Warnings:       classWarnings(classes),
+	}
+	inventory.DefaultClass = defaultClass(classes)
+
+	return inventory, nil
+}
+
+// GetStorageClass gets a storage class
+func (p *StorageProvider) GetStorageClass(ctx context.Context, clusterID, name string) (*storagev1.StorageClass, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	class, err := conn.Client.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage class: %w", err)
+	}
+
+	return class, nil
+}
+
+// NamespaceStorage returns the claims of a namespace with the storage class
+// each one uses, warning about claims that will not be provisioned
+func (p *StorageProvider) NamespaceStorage(ctx context.Context, clusterID, namespace string) (*NamespaceStorage, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	classes, _, err := list(ctx, conn.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := conn.Client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+
+	byName := make(map[string]*StorageClass, len(classes))
+	for i := range classes {
+		byName[classes[i].Name] = &classes[i]
+	}
+
+	result := &NamespaceStorage{
+		ClusterID:    clusterID,
+		Namespace:    namespace,
+		DefaultClass: defaultClass(classes),
+		Claims:       make([]Claim, 0, len(claims.Items)),
+		Warnings:     classWarnings(classes),
+	}
+
+	for _, pvc := range claims.Items {
+		claim := Claim{
+			Name:       pvc.Name,
+			Phase:      string(pvc.Status.Phase),
+			VolumeName: pvc.Spec.VolumeName,
+		}
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			claim.Capacity = capacity.String()
+		}
+
+		className := ""
+		if pvc.Spec.StorageClassName != nil {
+			className = *pvc.Spec.StorageClassName
+		}
+
+		switch {
+		case pvc.Spec.StorageClassName == nil && result.DefaultClass == "" && pvc.Spec.VolumeName == "":
+			claim.Warning = "claim has no storage class and the cluster has no default"
+		case pvc.Spec.StorageClassName == nil:
+			claim.StorageClass = byName[result.DefaultClass]
+		case className == "":
+			// An explicit empty class binds only to pre-provisioned volumes
+		case byName[className] == nil:
+			claim.Warning = fmt.Sprintf("storage class %s does not exist", className)
+		default:
+			claim.StorageClass = byName[className]
+		}
+
+		result.Claims = append(result.Claims, claim)
+	}
+
+	sort.Slice(result.Claims, func(i, j int) bool {
+		return result.Claims[i].Name < result.Claims[j].Name
+	})
+
+	return result, nil
+}
+
+// list reads the storage classes and CSI drivers of a cluster
+func list(ctx context.Context, client kubernetes.Interface) ([]StorageClass, []storagev1.CSIDriver, error) {
+	classList, err := client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list storage classes: %w", err)
+	}
+
+	driverList, err := client.StorageV1().CSIDrivers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list CSI drivers: %w", err)
+	}
+
+	drivers := make(map[string]bool, len(driverList.Items))
+	for _, driver := range driverList.Items {
+		drivers[driver.Name] = true
+	}
+
+	classes := make([]StorageClass, 0, len(classList.Items))
+	for _, item := range classList.Items {
+		class := StorageClass{
+			Name:        item.Name,
+			Provisioner: item.Provisioner,
+			Parameters:  item.Parameters,
+			Default:     item.Annotations[defaultClassAnnotation] == "true" || item.Annotations[betaDefaultClassAnnotation] == "true",
+		}
+		if item.ReclaimPolicy != nil {
+			class.ReclaimPolicy = string(*item.ReclaimPolicy)
+		}
+		if item.VolumeBindingMode != nil {
+			class.VolumeBindingMode = string(*item.VolumeBindingMode)
+		}
+		if item.AllowVolumeExpansion != nil {
+			class.AllowVolumeExpansion = *item.AllowVolumeExpansion
+		}
+		if drivers[item.Provisioner] {
+			class.CSIDriver = item.Provisioner
+		}
+		classes = append(classes, class)
+	}
+
+	sort.Slice(classes, func(i, j int) bool {
+		return classes[i].Name < classes[j].Name
+	})
+
+	return classes, driverList.Items, nil
+}
+
+// defaultClass returns the default storage class. With several defaults the
+// API server picks the most recently created; the first by name is reported.
+func defaultClass(classes []StorageClass) string {
+	for _, class := range classes {
+		if class.Default {
+			return class.Name
+		}
+	}
+	return ""
+}
+
+// classWarnings reports a missing or ambiguous default storage class
+func classWarnings(classes []StorageClass) []string {
+	warnings := make([]string, 0)
+
+	var defaults []string
+	for _, class := range classes {
+		if class.Default {
+			defaults = append(defaults, class.Name)
+		}
+	}
+
+	switch {
+	case len(classes) == 0:
+		warnings = append(warnings, "cluster has no storage classes")
+	case len(defaults) == 0:
+		warnings = append(warnings, "cluster has no default storage class; claims without a class stay pending")
+	case len(defaults) > 1:
+		warnings = append(warnings, fmt.Sprintf("cluster has several default storage classes: %s", strings.Join(defaults, ", ")))
+	}
+
+	return warnings
+}
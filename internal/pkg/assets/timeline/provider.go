@@ -0,0 +1,275 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// maxOwnerDepth bounds how far up the owner chain the timeline walks
+const maxOwnerDepth = 5
+
+// Entry sources
+const (
+	SourceCreated   = "created"
+	SourceCondition = "condition"
+	SourceContainer = "container"
+	SourceEvent     = "event"
+)
+
+// Entry is a single point on a timeline
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Kind    string    `json:"kind"`
+	Name    string    `json:"name"`
+	Type    string    `json:"type,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Count   int32     `json:"count,omitempty"`
+}
+
+// Reference identifies an object on the owner chain
+type Reference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	UID  string `json:"uid"`
+}
+
+// Timeline is the merged history of an object and its owners, oldest first
+type Timeline struct {
+	ClusterID  string      `json:"clusterID"`
+	Namespace  string      `json:"namespace"`
+	Kind       string      `json:"kind"`
+	Name       string      `json:"name"`
+	OwnerChain []Reference `json:"ownerChain"`
+	Entries    []Entry     `json:"entries"`
+}
+
+// TimelineProvider builds timelines from object status and Kubernetes Events
+type TimelineProvider struct {
+	clusterManager *cluster.Manager
+}
+
+// NewTimelineProvider creates a new provider
+func NewTimelineProvider(clusterManager *cluster.Manager) *TimelineProvider {
+	return &TimelineProvider{
+		clusterManager: clusterManager,
+	}
+}
+
+// Build returns the timeline of an object: its creation, condition and
+// container state transitions and Events, followed up the controller owner
+// chain so a pod's timeline includes its ReplicaSet and Deployment.
+func (p *TimelineProvider) Build(ctx context.Context, clusterID, namespace, kind, name string) (*Timeline, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	timeline := &Timeline{
+		ClusterID:  clusterID,
+		Namespace:  namespace,
+		Kind:       kind,
+		Name:       name,
+		OwnerChain: make([]Reference, 0),
+		Entries:    make([]Entry, 0),
+	}
+
+	obj, err := assets.GetObject(ctx, conn.Client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for depth := 0; obj != nil && depth <= maxOwnerDepth; depth++ {
+		meta, err := apimeta.Accessor(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read object metadata: %w", err)
+		}
+
+		objKind := obj.GetObjectKind().GroupVersionKind().Kind
+		if depth > 0 {
+			timeline.OwnerChain = append(timeline.OwnerChain, Reference{Kind: objKind, Name: meta.GetName(), UID: string(meta.GetUID())})
+		}
+
+		timeline.Entries = append(timeline.Entries, Entry{
+			Time:   meta.GetCreationTimestamp().Time,
+			Source: SourceCreated,
+			Kind:   objKind,
+			Name:   meta.GetName(),
+		})
+
+		conditions, err := conditionEntries(obj, objKind, meta.GetName())
+		if err != nil {
+			return nil, err
+		}
+		timeline.Entries = append(timeline.Entries, conditions...)
+
+		if pod, ok := obj.(*corev1.Pod); ok {
+			timeline.Entries = append(timeline.Entries, containerEntries(pod)...)
+		}
+
+		events, err := p.eventEntries(ctx, conn, namespace, string(meta.GetUID()))
+		if err != nil {
+			return nil, err
+		}
+		timeline.Entries = append(timeline.Entries, events...)
+
+		obj = p.owner(ctx, conn, namespace, meta)
+	}
+
+	sort.SliceStable(timeline.Entries, func(i, j int) bool {
+		return timeline.Entries[i].Time.Before(timeline.Entries[j].Time)
+	})
+
+	return timeline, nil
+}
+
+// owner returns the supported controller of an object, or nil at the top of the chain
+func (p *TimelineProvider) owner(ctx context.Context, conn *cluster.Connection, namespace string, meta metav1.Object) runtime.Object {
+	ref := metav1.GetControllerOfNoCopy(meta)
+	if ref == nil {
+		return nil
+	}
+
+	if _, ok := assets.LookupKind(ref.Kind); !ok {
+		return nil
+	}
+
+	// An owner that is gone or unreadable ends the chain rather than the timeline
+	owner, err := assets.GetObject(ctx, conn.Client, ref.Kind, namespace, ref.Name)
+	if err != nil {
+		return nil
+	}
+
+	return owner
+}
+
+// eventEntries returns the Events whose involved object is uid
+func (p *TimelineProvider) eventEntries(ctx context.Context, conn *cluster.Connection, namespace, uid string) ([]Entry, error) {
+	events, err := conn.Client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", uid).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(events.Items))
+	for _, event := range events.Items {
+		entries = append(entries, Entry{
+			Time:    eventTime(&event),
+			Source:  SourceEvent,
+			Kind:    event.InvolvedObject.Kind,
+			Name:    event.InvolvedObject.Name,
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Message: event.Message,
+			Count:   event.Count,
+		})
+	}
+
+	return entries, nil
+}
+
+// eventTime returns the most recent time an Event was observed
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// conditionEntries returns the last transition of each status condition.
+// Conditions are read generically since every kind declares its own type.
+func conditionEntries(obj runtime.Object, kind, name string) ([]Entry, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object: %w", err)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(content, "status", "conditions")
+
+	entries := make([]Entry, 0, len(conditions))
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		transition, _, _ := unstructured.NestedString(condition, "lastTransitionTime")
+		at, err := time.Parse(time.RFC3339, transition)
+		if err != nil {
+			continue
+		}
+
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+
+		entries = append(entries, Entry{
+			Time:    at,
+			Source:  SourceCondition,
+			Kind:    kind,
+			Name:    name,
+			Type:    conditionType + "=" + status,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+
+	return entries, nil
+}
+
+// containerEntries returns container starts and terminations, including the
+// previous termination of restarted containers
+func containerEntries(pod *corev1.Pod) []Entry {
+	entries := make([]Entry, 0)
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		for _, state := range []corev1.ContainerState{status.LastTerminationState, status.State} {
+			if state.Running != nil {
+				entries = append(entries, Entry{
+					Time:    state.Running.StartedAt.Time,
+					Source:  SourceContainer,
+					Kind:    "Pod",
+					Name:    pod.Name,
+					Type:    "Running",
+					Message: fmt.Sprintf("container %s started", status.Name),
+				})
+			}
+
+			if state.Terminated != nil {
+				entries = append(entries, Entry{
+					Time:    state.Terminated.FinishedAt.Time,
+					Source:  SourceContainer,
+					Kind:    "Pod",
+					Name:    pod.Name,
+					Type:    "Terminated",
+					Reason:  state.Terminated.Reason,
+					Message: fmt.Sprintf("container %s exited with code %d", status.Name, state.Terminated.ExitCode),
+					Count:   status.RestartCount,
+				})
+			}
+		}
+	}
+
+	return entries
+}
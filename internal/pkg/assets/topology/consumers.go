@@ -0,0 +1,133 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Consumer is a workload whose pods use a ConfigMap or Secret
+type Consumer struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Via lists how the pods use it: volume, env, envFrom or imagePullSecret
+	Via  []string `json:"via"`
+	Pods []string `json:"pods"`
+}
+
+// Consumers are the workloads affected by a change to a ConfigMap or Secret
+type Consumers struct {
+	ClusterID string     `json:"clusterID"`
+	Namespace string     `json:"namespace"`
+	Kind      string     `json:"kind"`
+	Name      string     `json:"name"`
+	Consumers []Consumer `json:"consumers"`
+}
+
+// Consumers returns the workloads whose stored pods mount, envFrom or
+// otherwise reference a ConfigMap or Secret of the namespace
+func (p *TopologyProvider) Consumers(ctx context.Context, clusterID, namespace, kind, name string) (*Consumers, error) {
+	return p.consumers(ctx, clusterID, namespace, kind, name, func(*corev1.Pod) bool { return true })
+}
+
+// StaleConsumers returns the consumers of a ConfigMap or Secret with pods
+// running containers started before it changed, which still see the old
+// content in their environment and possibly in subPath mounts. Only those
+// pods are listed.
+func (p *TopologyProvider) StaleConsumers(ctx context.Context, clusterID, namespace, kind, name string, changedAt time.Time) (*Consumers, error) {
+	return p.consumers(ctx, clusterID, namespace, kind, name, func(pod *corev1.Pod) bool {
+		return startedBefore(pod, changedAt)
+	})
+}
+
+// consumers returns the consumers of a ConfigMap or Secret, counting only the pods include accepts
+func (p *TopologyProvider) consumers(ctx context.Context, clusterID, namespace, kind, name string, include func(*corev1.Pod) bool) (*Consumers, error) {
+	var pods []corev1.Pod
+	if err := p.store.List(ctx, clusterID, namespace, "Pod", &pods); err != nil {
+		return nil, fmt.Errorf("failed to list stored pods: %w", err)
+	}
+
+	byWorkload := make(map[string]*Consumer)
+	for i := range pods {
+		pod := &pods[i]
+		if !include(pod) {
+			continue
+		}
+
+		var via []string
+		for _, ref := range references(&pod.Spec) {
+			if ref.kind == kind && ref.name == name && !slices.Contains(via, ref.via) {
+				via = append(via, ref.via)
+			}
+		}
+		if len(via) == 0 {
+			continue
+		}
+
+		workloadKind, workloadName := assets.WorkloadOf(pod)
+		key := workloadKind + "/" + workloadName
+		consumer, ok := byWorkload[key]
+		if !ok {
+			consumer = &Consumer{Kind: workloadKind, Name: workloadName}
+			byWorkload[key] = consumer
+		}
+
+		consumer.Pods = append(consumer.Pods, pod.Name)
+		for _, v := range via {
+			if !slices.Contains(consumer.Via, v) {
+				consumer.Via = append(consumer.Via, v)
+			}
+		}
+	}
+
+	result := &Consumers{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Kind:      kind,
+		Name:      name,
+		Consumers: make([]Consumer, 0, len(byWorkload)),
+	}
+	for _, consumer := range byWorkload {
+		sort.Strings(consumer.Via)
+		sort.Strings(consumer.Pods)
+		result.Consumers = append(result.Consumers, *consumer)
+	}
+
+	sort.Slice(result.Consumers, func(i, j int) bool {
+		a, b := result.Consumers[i], result.Consumers[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	return result, nil
+}
+
+// startedBefore reports whether any running container of a pod started
+// before a time. Pods without container statuses go by their start time.
+func startedBefore(pod *corev1.Pod, t time.Time) bool {
+	running := false
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Running == nil {
+			continue
+		}
+		running = true
+		if status.State.Running.StartedAt.Time.Before(t) {
+			return true
+		}
+	}
+	if running {
+		return false
+	}
+
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time.Before(t)
+	}
+	return pod.CreationTimestamp.Time.Before(t)
+}
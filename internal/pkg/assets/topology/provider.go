@@ -0,0 +1,272 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Edge types
+const (
+	EdgeRoutes     = "routes"
+	EdgeSelects    = "selects"
+	EdgeOwns       = "owns"
+	EdgeReferences = "references"
+)
+
+// ResourceLister reads stored resources
+type ResourceLister interface {
+	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
+}
+
+// Node is an object in the topology
+type Node struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Edge connects two nodes. Label carries detail such as an ingress host and path.
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Type  string `json:"type"`
+	Label string `json:"label,omitempty"`
+}
+
+// Topology is a namespace dependency graph
+type Topology struct {
+	ClusterID string `json:"clusterID"`
+	Namespace string `json:"namespace"`
+	Nodes     []Node `json:"nodes"`
+	Edges     []Edge `json:"edges"`
+}
+
+// TopologyProvider builds namespace topologies
+type TopologyProvider struct {
+	clusterManager *cluster.Manager
+	store          ResourceLister
+}
+
+// NewTopologyProvider creates a new provider
+func NewTopologyProvider(clusterManager *cluster.Manager, store ResourceLister) *TopologyProvider {
+	return &TopologyProvider{
+		clusterManager: clusterManager,
+		store:          store,
+	}
+}
+
+// graphBuilder collects nodes and edges without duplicates
+type graphBuilder struct {
+	nodes map[string]Node
+	edges map[Edge]bool
+}
+
+// node adds a node and returns its ID
+func (b *graphBuilder) node(kind, name string) string {
+	id := kind + "/" + name
+	b.nodes[id] = Node{ID: id, Kind: kind, Name: name}
+	return id
+}
+
+// edge adds an edge
+func (b *graphBuilder) edge(from, to, edgeType, label string) {
+	b.edges[Edge{From: from, To: to, Type: edgeType, Label: label}] = true
+}
+
+// Build returns the topology of a namespace: Ingresses route to Services,
+// Services select Pods, workloads own Pods and reference the ConfigMaps and
+// Secrets their pods use. Pods come from the store; Services and Ingresses
+// are listed from the cluster.
+func (p *TopologyProvider) Build(ctx context.Context, clusterID, namespace string) (*Topology, error) {
+	conn, err := p.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	var pods []corev1.Pod
+	if err := p.store.List(ctx, clusterID, namespace, "Pod", &pods); err != nil {
+		return nil, fmt.Errorf("failed to list stored pods: %w", err)
+	}
+
+	services, err := conn.Client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	ingresses, err := conn.Client.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	builder := &graphBuilder{
+		nodes: make(map[string]Node),
+		edges: make(map[Edge]bool),
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		podID := builder.node("Pod", pod.Name)
+
+		kind, name := assets.WorkloadOf(pod)
+		workloadID := podID
+		if kind != "Pod" {
+			workloadID = builder.node(kind, name)
+			builder.edge(workloadID, podID, EdgeOwns, "")
+		}
+
+		for _, ref := range references(&pod.Spec) {
+			builder.edge(workloadID, builder.node(ref.kind, ref.name), EdgeReferences, ref.via)
+		}
+	}
+
+	for _, service := range services.Items {
+		serviceID := builder.node("Service", service.Name)
+
+		// Services without a selector are backed by manually managed endpoints
+		if len(service.Spec.Selector) == 0 {
+			continue
+		}
+
+		selector := labels.SelectorFromSet(service.Spec.Selector)
+		for _, pod := range pods {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				builder.edge(serviceID, builder.node("Pod", pod.Name), EdgeSelects, "")
+			}
+		}
+	}
+
+	for _, ingress := range ingresses.Items {
+		ingressID := builder.node("Ingress", ingress.Name)
+
+		if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+			builder.edge(ingressID, builder.node("Service", backend.Service.Name), EdgeRoutes, "default")
+		}
+
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service == nil {
+					continue
+				}
+				builder.edge(ingressID, builder.node("Service", path.Backend.Service.Name), EdgeRoutes, routeLabel(rule, path))
+			}
+		}
+	}
+
+	return builder.topology(clusterID, namespace), nil
+}
+
+// topology returns the collected graph in a stable order
+func (b *graphBuilder) topology(clusterID, namespace string) *Topology {
+	topology := &Topology{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Nodes:     make([]Node, 0, len(b.nodes)),
+		Edges:     make([]Edge, 0, len(b.edges)),
+	}
+
+	for _, node := range b.nodes {
+		topology.Nodes = append(topology.Nodes, node)
+	}
+	for edge := range b.edges {
+		topology.Edges = append(topology.Edges, edge)
+	}
+
+	sort.Slice(topology.Nodes, func(i, j int) bool {
+		return topology.Nodes[i].ID < topology.Nodes[j].ID
+	})
+	sort.Slice(topology.Edges, func(i, j int) bool {
+		a, c := topology.Edges[i], topology.Edges[j]
+		if a.From != c.From {
+			return a.From < c.From
+		}
+		if a.To != c.To {
+			return a.To < c.To
+		}
+		return a.Label < c.Label
+	})
+
+	return topology
+}
+
+// routeLabel describes an ingress path as host/path
+func routeLabel(rule networkingv1.IngressRule, path networkingv1.HTTPIngressPath) string {
+	host := rule.Host
+	if host == "" {
+		host = "*"
+	}
+	return host + path.Path
+}
+
+// reference is a ConfigMap or Secret used by a pod
+type reference struct {
+	kind string
+	name string
+	via  string
+}
+
+// references returns the ConfigMaps and Secrets a pod spec uses through
+// volumes, env, envFrom and image pull secrets
+func references(spec *corev1.PodSpec) []reference {
+	refs := make([]reference, 0)
+
+	for _, volume := range spec.Volumes {
+		if volume.ConfigMap != nil {
+			refs = append(refs, reference{"ConfigMap", volume.ConfigMap.Name, "volume"})
+		}
+		if volume.Secret != nil {
+			refs = append(refs, reference{"Secret", volume.Secret.SecretName, "volume"})
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					refs = append(refs, reference{"ConfigMap", source.ConfigMap.Name, "volume"})
+				}
+				if source.Secret != nil {
+					refs = append(refs, reference{"Secret", source.Secret.Name, "volume"})
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				refs = append(refs, reference{"ConfigMap", envFrom.ConfigMapRef.Name, "envFrom"})
+			}
+			if envFrom.SecretRef != nil {
+				refs = append(refs, reference{"Secret", envFrom.SecretRef.Name, "envFrom"})
+			}
+		}
+
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				refs = append(refs, reference{"ConfigMap", ref.Name, "env"})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				refs = append(refs, reference{"Secret", ref.Name, "env"})
+			}
+		}
+	}
+
+	for _, pullSecret := range spec.ImagePullSecrets {
+		refs = append(refs, reference{"Secret", pullSecret.Name, "imagePullSecret"})
+	}
+
+	return refs
+}
@@ -0,0 +1,152 @@
+package vulnerabilities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+)
+
+// NewScanner creates the scanner named in config, or returns nil when scanning is disabled
+func NewScanner(config Config) (Scanner, error) {
+	switch config.Scanner {
+	case "":
+		return nil, nil
+	case ScannerTrivy:
+		path := config.Trivy.Path
+		if path == "" {
+			path = "trivy"
+		}
+		return &TrivyScanner{path: path, server: config.Trivy.Server}, nil
+	case ScannerHTTP:
+		if config.HTTP.URL == "" {
+			return nil, fmt.Errorf("http scanner requires a url")
+		}
+		return &HTTPScanner{url: config.HTTP.URL, token: config.HTTP.BearerToken, client: &http.Client{}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported scanner %q", config.Scanner)
+	}
+}
+
+// TrivyScanner scans images with the trivy CLI. With a server the CLI runs
+// in client mode so the vulnerability database lives on the Trivy server.
+type TrivyScanner struct {
+	path   string
+	server string
+}
+
+// Name returns the scanner name
+func (s *TrivyScanner) Name() string {
+	return ScannerTrivy
+}
+
+// Scan runs trivy against an image and collects the vulnerabilities of every result
+func (s *TrivyScanner) Scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	args := []string{"image", "--quiet", "--format", "json", "--scanners", "vuln"}
+	if s.server != "" {
+		args = append(args, "--server", s.server)
+	}
+	args = append(args, image)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("trivy failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID  string `json:"VulnerabilityID"`
+				PkgName          string `json:"PkgName"`
+				InstalledVersion string `json:"InstalledVersion"`
+				FixedVersion     string `json:"FixedVersion"`
+				Severity         string `json:"Severity"`
+				Title            string `json:"Title"`
+				PrimaryURL       string `json:"PrimaryURL"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		return nil, fmt.Errorf("failed to decode trivy report: %w", err)
+	}
+
+	vulnerabilities := make([]Vulnerability, 0)
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         v.Severity,
+				Title:            v.Title,
+				URL:              v.PrimaryURL,
+			})
+		}
+	}
+
+	return vulnerabilities, nil
+}
+
+// HTTPScanner asks an external API to scan images. It posts {"image": ...}
+// and expects {"vulnerabilities": [...]} in the Vulnerability JSON format.
+type HTTPScanner struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// Name returns the scanner name
+func (s *HTTPScanner) Name() string {
+	return ScannerHTTP
+}
+
+// Scan posts an image to the scanning API
+func (s *HTTPScanner) Scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	body, err := json.Marshal(map[string]string{"image": image})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("scanner request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("scanner returned status %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+
+	var result struct {
+		Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode scanner response: %w", err)
+	}
+
+	if result.Vulnerabilities == nil {
+		result.Vulnerabilities = make([]Vulnerability, 0)
+	}
+
+	return result.Vulnerabilities, nil
+}
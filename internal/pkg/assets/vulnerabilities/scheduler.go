@@ -0,0 +1,211 @@
+package vulnerabilities
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Report lists the known vulnerabilities of the images running in a namespace
+type Report struct {
+	ClusterID string         `json:"clusterID"`
+	Namespace string         `json:"namespace"`
+	Summary   map[string]int `json:"summary"`
+	Images    []ImageScan    `json:"images"`
+	// Pending are running images that have not been scanned yet
+	Pending []string `json:"pending"`
+}
+
+// Scheduler rescans the images running in connected clusters on an interval
+// and serves cached results
+type Scheduler struct {
+	scanner        Scanner
+	clusterManager *cluster.Manager
+	store          ScanStore
+	config         Config
+	logger         *slog.Logger
+}
+
+// NewScheduler creates a new scheduler, filling in defaults for unset durations.
+// scanner may be nil, in which case only cached results are served.
+func NewScheduler(scanner Scanner, clusterManager *cluster.Manager, store ScanStore, config Config, logger *slog.Logger) *Scheduler {
+	if config.Interval <= 0 {
+		config.Interval = 6 * time.Hour
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Minute
+	}
+
+	return &Scheduler{
+		scanner:        scanner,
+		clusterManager: clusterManager,
+		store:          store,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// Start scans on every interval until the context is cancelled. The first
+// pass runs right away so a fresh install has results.
+func (s *Scheduler) Start(ctx context.Context) {
+	if s.scanner == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		s.scanAll(ctx)
+		for {
+			select {
+			case <-ticker.C:
+				s.scanAll(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	s.logger.Info("Vulnerability scanning started", "scanner", s.scanner.Name(), "interval", s.config.Interval)
+}
+
+// scanAll scans the images of every connected cluster that are due
+func (s *Scheduler) scanAll(ctx context.Context) {
+	images := make(map[string]bool)
+
+	for clusterID, conn := range s.clusterManager.GetConnections() {
+		if !conn.IsConnected() {
+			continue
+		}
+
+		running, err := s.runningImages(ctx, clusterID, "")
+		if err != nil {
+			s.logger.Debug("Failed to list images for scanning", "clusterID", clusterID, "error", err)
+			continue
+		}
+		for _, image := range running {
+			images[image] = true
+		}
+	}
+
+	list := make([]string, 0, len(images))
+	for image := range images {
+		list = append(list, image)
+	}
+
+	var scans []ImageScan
+	if err := s.store.ListImageScans(ctx, list, &scans); err != nil {
+		s.logger.Error("Failed to read cached image scans", "error", err)
+		return
+	}
+
+	scanned := make(map[string]time.Time, len(scans))
+	for _, scan := range scans {
+		scanned[scan.Image] = scan.ScannedAt
+	}
+
+	for _, image := range list {
+		if ctx.Err() != nil {
+			return
+		}
+		if at, ok := scanned[image]; ok && time.Since(at) < s.config.Interval {
+			continue
+		}
+		s.scan(ctx, image)
+	}
+}
+
+// scan scans one image and caches the result, including failures
+func (s *Scheduler) scan(ctx context.Context, image string) {
+	scanCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	result := &ImageScan{
+		Image:           image,
+		Scanner:         s.scanner.Name(),
+		ScannedAt:       time.Now(),
+		Vulnerabilities: make([]Vulnerability, 0),
+	}
+
+	vulnerabilities, err := s.scanner.Scan(scanCtx, image)
+	if err != nil {
+		s.logger.Warn("Image scan failed", "image", image, "error", err)
+		result.Error = err.Error()
+	} else {
+		result.Vulnerabilities = vulnerabilities
+	}
+
+	if err := s.store.SaveImageScan(ctx, result); err != nil {
+		s.logger.Error("Failed to save image scan", "image", image, "error", err)
+	}
+}
+
+// Report returns the cached scans of the images running in a namespace
+func (s *Scheduler) Report(ctx context.Context, clusterID, namespace string) (*Report, error) {
+	images, err := s.runningImages(ctx, clusterID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var scans []ImageScan
+	if err := s.store.ListImageScans(ctx, images, &scans); err != nil {
+		return nil, fmt.Errorf("failed to read image scans: %w", err)
+	}
+
+	report := &Report{
+		ClusterID: clusterID,
+		Namespace: namespace,
+		Summary:   make(map[string]int),
+		Images:    make([]ImageScan, 0, len(scans)),
+		Pending:   make([]string, 0),
+	}
+
+	found := make(map[string]bool, len(scans))
+	for _, scan := range scans {
+		found[scan.Image] = true
+		for _, vulnerability := range scan.Vulnerabilities {
+			report.Summary[vulnerability.Severity]++
+		}
+		report.Images = append(report.Images, scan)
+	}
+
+	for _, image := range images {
+		if !found[image] {
+			report.Pending = append(report.Pending, image)
+		}
+	}
+
+	sort.Slice(report.Images, func(i, j int) bool {
+		return report.Images[i].Image < report.Images[j].Image
+	})
+
+	return report, nil
+}
+
+// runningImages returns the distinct images of the stored pods of a cluster or namespace
+func (s *Scheduler) runningImages(ctx context.Context, clusterID, namespace string) ([]string, error) {
+	var pods []corev1.Pod
+	if err := s.store.List(ctx, clusterID, namespace, "Pod", &pods); err != nil {
+		return nil, fmt.Errorf("failed to list stored pods: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	images := make([]string, 0)
+	for _, pod := range pods {
+		for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+			if !seen[container.Image] {
+				seen[container.Image] = true
+				images = append(images, container.Image)
+			}
+		}
+	}
+
+	sort.Strings(images)
+	return images, nil
+}
@@ -0,0 +1,76 @@
+package vulnerabilities
+
+import (
+	"context"
+	"time"
+)
+
+// Scanner types
+const (
+	ScannerTrivy = "trivy"
+	ScannerHTTP  = "http"
+)
+
+// Severities, as reported by scanners
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityHigh     = "HIGH"
+	SeverityMedium   = "MEDIUM"
+	SeverityLow      = "LOW"
+	SeverityUnknown  = "UNKNOWN"
+)
+
+// TrivyConfig runs the trivy CLI, against a Trivy server when Server is set
+type TrivyConfig struct {
+	Path   string `yaml:"path"`
+	Server string `yaml:"server"`
+}
+
+// HTTPConfig posts images to an external scanning API
+type HTTPConfig struct {
+	URL         string `yaml:"url"`
+	BearerToken string `yaml:"bearerToken"`
+}
+
+// Config configures vulnerability scanning. Scanning is disabled when no
+// scanner is set. Images are rescanned once their last scan is older than Interval.
+type Config struct {
+	Scanner  string        `yaml:"scanner"`
+	Interval time.Duration `yaml:"interval"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Trivy    TrivyConfig   `yaml:"trivy"`
+	HTTP     HTTPConfig    `yaml:"http"`
+}
+
+// Vulnerability is a known CVE in an image
+type Vulnerability struct {
+	ID               string `json:"id" bson:"id"`
+	Package          string `json:"package" bson:"package"`
+	InstalledVersion string `json:"installedVersion" bson:"installed_version"`
+	FixedVersion     string `json:"fixedVersion,omitempty" bson:"fixed_version,omitempty"`
+	Severity         string `json:"severity" bson:"severity"`
+	Title            string `json:"title,omitempty" bson:"title,omitempty"`
+	URL              string `json:"url,omitempty" bson:"url,omitempty"`
+}
+
+// ImageScan is the cached result of scanning an image
+type ImageScan struct {
+	Image           string          `json:"image" bson:"_id"`
+	Scanner         string          `json:"scanner" bson:"scanner"`
+	ScannedAt       time.Time       `json:"scannedAt" bson:"scanned_at"`
+	Error           string          `json:"error,omitempty" bson:"error,omitempty"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities" bson:"vulnerabilities"`
+}
+
+// Scanner finds the known vulnerabilities of an image
+type Scanner interface {
+	Name() string
+	Scan(ctx context.Context, image string) ([]Vulnerability, error)
+}
+
+// ScanStore persists scan results
+type ScanStore interface {
+	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
+	SaveImageScan(ctx context.Context, scan *ImageScan) error
+	ListImageScans(ctx context.Context, images []string, results *[]ImageScan) error
+}
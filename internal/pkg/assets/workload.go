@@ -0,0 +1,99 @@
+package assets
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WorkloadOf returns the kind and name of the workload that manages a pod.
+// Pods owned by a ReplicaSet are attributed to its Deployment using the
+// pod-template-hash label; unowned pods are their own workload.
+func WorkloadOf(pod *corev1.Pod) (kind, name string) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+
+		if owner.Kind == "ReplicaSet" {
+			if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+				return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+			}
+		}
+
+		if owner.Kind == "Job" {
+			if cronJob, ok := cronJobOf(owner.Name); ok {
+				return "CronJob", cronJob
+			}
+		}
+
+		return owner.Kind, owner.Name
+	}
+
+	return "Pod", pod.Name
+}
+
+// cronJobOf guesses the CronJob behind a Job named <cronjob>-<scheduled time>
+func cronJobOf(jobName string) (string, bool) {
+	i := strings.LastIndex(jobName, "-")
+	if i <= 0 {
+		return "", false
+	}
+
+	suffix := jobName[i+1:]
+	if len(suffix) < 8 || strings.Trim(suffix, "0123456789") != "" {
+		return "", false
+	}
+
+	return jobName[:i], true
+}
+
+// PodRequests returns the effective requests of a pod the way the scheduler
+// counts them: the sum of its containers, or the largest init container if
+// that is bigger, plus pod overhead. Containers without requests fall back
+// to their limits when useLimits is set.
+func PodRequests(pod *corev1.Pod, useLimits bool) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(total, containerRequests(container, useLimits))
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range containerRequests(container, useLimits) {
+			if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+				total[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	addResources(total, pod.Spec.Overhead)
+	return total
+}
+
+// containerRequests returns a container's requests, optionally falling back to limits
+func containerRequests(container corev1.Container, useLimits bool) corev1.ResourceList {
+	requests := container.Resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+
+	if useLimits {
+		for name, quantity := range container.Resources.Limits {
+			if _, ok := requests[name]; !ok {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	return requests
+}
+
+// addResources adds every quantity in add to total
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		current := total[name]
+		current.Add(quantity)
+		total[name] = current
+	}
+}
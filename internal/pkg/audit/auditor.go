@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Auditor records audit entries asynchronously so request handling is never
+// blocked on the store
+type Auditor struct {
+	sink    Sink
+	logger  *slog.Logger
+	entries chan *Entry
+}
+
+// NewAuditor creates a new auditor that writes entries to the given sink
+func NewAuditor(sink Sink, logger *slog.Logger) *Auditor {
+	return &Auditor{
+		sink:    sink,
+		logger:  logger,
+		entries: make(chan *Entry, 1024),
+	}
+}
+
+// Start begins writing queued entries until the context is cancelled
+func (a *Auditor) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case entry := <-a.entries:
+				a.write(entry)
+			case <-ctx.Done():
+				// Drain whatever is left before exiting
+				for {
+					select {
+					case entry := <-a.entries:
+						a.write(entry)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Record queues an entry for persistence, dropping it if the queue is full
+func (a *Auditor) Record(entry *Entry) {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	select {
+	case a.entries <- entry:
+	default:
+		a.logger.Warn("Audit queue full, dropping entry",
+			"user", entry.User,
+			"verb", entry.Verb,
+			"resource", entry.Resource,
+			"path", entry.Path)
+	}
+}
+
+// write persists a single entry using a short-lived context
+func (a *Auditor) write(entry *Entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.sink.SaveAuditEntry(ctx, entry); err != nil {
+		a.logger.Error("Failed to save audit entry", "error", err, "path", entry.Path)
+	}
+}
@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+)
+
+// Middleware records an audit entry for every request that passes through it.
+// Resource and verb are taken from the permission check when one ran, and are
+// otherwise derived from the route and HTTP method.
+func Middleware(auditor *Auditor) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Skip CORS preflight requests
+		if c.Method() == fiber.MethodOptions {
+			return c.Next()
+		}
+
+		start := time.Now()
+		c.Locals("auditPath", c.Path())
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			if fiberErr, ok := err.(*fiber.Error); ok {
+				status = fiberErr.Code
+			} else {
+				status = fiber.StatusInternalServerError
+			}
+		}
+
+		// Upgraded connections are recorded by the WebSocket wrapper when the session ends
+		if status == fiber.StatusSwitchingProtocols {
+			return err
+		}
+
+		entry := &Entry{
+			Timestamp: start,
+			Kind:      KindHTTP,
+			Method:    c.Method(),
+			Path:      c.Path(),
+			Status:    status,
+			Result:    resultFromStatus(status),
+			Latency:   time.Since(start),
+			SourceIP:  c.IP(),
+			Cluster:   c.Params("clusterID"),
+			Namespace: c.Params("namespaceID"),
+		}
+
+		if user, ok := c.Locals("user").(auth.UserAttributes); ok {
+			entry.User = user.Username
+			entry.Groups = user.Groups
+		}
+
+		entry.Resource, _ = c.Locals("resource").(string)
+		if entry.Resource == "" {
+			entry.Resource = c.Route().Path
+		}
+
+		entry.Verb, _ = c.Locals("verb").(string)
+		if entry.Verb == "" {
+			entry.Verb = verbFromMethod(c.Method())
+		}
+
+		entry.Name, _ = c.Locals("name").(string)
+		entry.Details, _ = c.Locals("auditDetails").(map[string]string)
+
+		auditor.Record(entry)
+
+		return err
+	}
+}
+
+// Annotate adds a detail to the audit entry of the request, for handlers
+// whose changes the method and path alone don't describe
+func Annotate(c *fiber.Ctx, key, value string) {
+	details, ok := c.Locals("auditDetails").(map[string]string)
+	if !ok {
+		details = make(map[string]string)
+		c.Locals("auditDetails", details)
+	}
+	details[key] = value
+}
+
+// WebSocket wraps a WebSocket handler so the whole session is recorded as a
+// single audit entry once the connection closes
+func WebSocket(auditor *Auditor, resource, verb string, handler func(*websocket.Conn)) func(*websocket.Conn) {
+	return func(c *websocket.Conn) {
+		start := time.Now()
+
+		defer func() {
+			entry := &Entry{
+				Timestamp: start,
+				Kind:      KindWebSocket,
+				Method:    fiber.MethodGet,
+				Verb:      verb,
+				Resource:  resource,
+				Cluster:   c.Params("clusterID"),
+				Namespace: c.Params("namespaceID"),
+				Name:      c.Params("podID", c.Params("name")),
+				Status:    fiber.StatusSwitchingProtocols,
+				Result:    ResultSuccess,
+				Latency:   time.Since(start),
+			}
+
+			entry.Path, _ = c.Locals("auditPath").(string)
+
+			if user, ok := c.Locals("user").(auth.UserAttributes); ok {
+				entry.User = user.Username
+				entry.Groups = user.Groups
+			}
+
+			if addr := c.RemoteAddr(); addr != nil {
+				entry.SourceIP = addr.String()
+			}
+
+			auditor.Record(entry)
+		}()
+
+		handler(c)
+	}
+}
+
+// resultFromStatus maps an HTTP status code to an audit result
+func resultFromStatus(status int) string {
+	switch {
+	case status == fiber.StatusUnauthorized || status == fiber.StatusForbidden:
+		return ResultDenied
+	case status >= 400:
+		return ResultFailure
+	default:
+		return ResultSuccess
+	}
+}
+
+// verbFromMethod maps an HTTP method to a Kubernetes-style verb
+func verbFromMethod(method string) string {
+	switch method {
+	case fiber.MethodPost:
+		return "create"
+	case fiber.MethodPut:
+		return "update"
+	case fiber.MethodPatch:
+		return "patch"
+	case fiber.MethodDelete:
+		return "delete"
+	default:
+		return strings.ToLower(method)
+	}
+}
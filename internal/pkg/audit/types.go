@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// Result values recorded on audit entries
+const (
+	ResultSuccess = "success"
+	ResultDenied  = "denied"
+	ResultFailure = "failure"
+)
+
+// Entry kinds
+const (
+	KindHTTP      = "http"
+	KindWebSocket = "websocket"
+)
+
+// Entry represents a single audited API call or WebSocket session
+type Entry struct {
+	ID        string        `json:"id" bson:"_id,omitempty"`
+	Timestamp time.Time     `json:"timestamp" bson:"timestamp"`
+	Kind      string        `json:"kind" bson:"kind"`
+	User      string        `json:"user" bson:"user"`
+	Groups    []string      `json:"groups,omitempty" bson:"groups,omitempty"`
+	Verb      string        `json:"verb" bson:"verb"`
+	Resource  string        `json:"resource" bson:"resource"`
+	Cluster   string        `json:"cluster,omitempty" bson:"cluster,omitempty"`
+	Namespace string        `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Name      string        `json:"name,omitempty" bson:"name,omitempty"`
+	Method    string        `json:"method" bson:"method"`
+	Path      string        `json:"path" bson:"path"`
+	Status    int           `json:"status" bson:"status"`
+	Result    string        `json:"result" bson:"result"`
+	Latency   time.Duration `json:"latency" bson:"latency"`
+	SourceIP  string        `json:"sourceIP,omitempty" bson:"source_ip,omitempty"`
+	// Details are facts a handler added about the change, such as the old and new image
+	Details map[string]string `json:"details,omitempty" bson:"details,omitempty"`
+}
+
+// Query holds the filters used to search audit entries
+type Query struct {
+	User      string
+	Cluster   string
+	Namespace string
+	Resource  string
+	Verb      string
+	Result    string
+	Since     time.Time
+	Until     time.Time
+	Limit     int64
+}
+
+// Sink persists audit entries
+type Sink interface {
+	// SaveAuditEntry stores a single audit entry
+	SaveAuditEntry(ctx context.Context, entry *Entry) error
+}
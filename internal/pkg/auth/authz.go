@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/jbetancur/dashboard/internal/pkg/cluster"
 	authorizationv1 "k8s.io/api/authorization/v1"
@@ -17,18 +15,15 @@ import (
 type K8sAuthorizer struct {
 	clusterManager *cluster.Manager
 	logger         *slog.Logger
-	cache          map[string]CachedDecision
-	cacheMu        sync.RWMutex
-	cacheDuration  time.Duration
+	cache          *decisionCache
 }
 
 // NewK8sAuthorizer creates a new authorizer that uses the cluster manager
-func NewK8sAuthorizer(clusterManager *cluster.Manager, logger *slog.Logger) *K8sAuthorizer {
+func NewK8sAuthorizer(clusterManager *cluster.Manager, cacheConfig CacheConfig, logger *slog.Logger) *K8sAuthorizer {
 	return &K8sAuthorizer{
 		clusterManager: clusterManager,
 		logger:         logger,
-		cache:          make(map[string]CachedDecision),
-		cacheDuration:  30 * time.Second,
+		cache:          newDecisionCache(cacheConfig),
 	}
 }
 
@@ -46,14 +41,9 @@ func (a *K8sAuthorizer) CanAccess(ctx context.Context, clusterID string, user Us
 		strings.Join(user.Groups, ","))
 
 	// Check cache
-	a.cacheMu.RLock()
-	if decision, exists := a.cache[cacheKey]; exists {
-		if time.Since(decision.Timestamp) < a.cacheDuration {
-			a.cacheMu.RUnlock()
-			return decision.Allowed, nil
-		}
+	if allowed, ok := a.cache.get(cacheKey); ok {
+		return allowed, nil
 	}
-	a.cacheMu.RUnlock()
 
 	// Get cluster connection
 	conn, err := a.clusterManager.GetCluster(clusterID)
@@ -65,14 +55,18 @@ func (a *K8sAuthorizer) CanAccess(ctx context.Context, clusterID string, user Us
 		return false, fmt.Errorf("cluster %s not connected", clusterID)
 	}
 
+	group, resourceName, subresource := splitResource(resource)
+
 	// Create SubjectAccessReview
 	sar := &authorizationv1.SubjectAccessReview{
 		Spec: authorizationv1.SubjectAccessReviewSpec{
 			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace: namespace,
-				Verb:      verb,
-				Resource:  resource,
-				Name:      name,
+				Namespace:   namespace,
+				Verb:        verb,
+				Group:       group,
+				Resource:    resourceName,
+				Subresource: subresource,
+				Name:        name,
 			},
 			User:   user.Username,
 			UID:    user.UID,
@@ -88,13 +82,8 @@ func (a *K8sAuthorizer) CanAccess(ctx context.Context, clusterID string, user Us
 		return false, fmt.Errorf("authorization check failed: %w", err)
 	}
 
-	// Cache result
-	a.cacheMu.Lock()
-	a.cache[cacheKey] = CachedDecision{
-		Allowed:   result.Status.Allowed,
-		Timestamp: time.Now(),
-	}
-	a.cacheMu.Unlock()
+	// Cache result, denials included
+	a.cache.put(cacheKey, user.Username, result.Status.Allowed)
 
 	// Log the result
 	a.logger.Debug("Access check",
@@ -109,6 +98,49 @@ func (a *K8sAuthorizer) CanAccess(ctx context.Context, clusterID string, user Us
 	return result.Status.Allowed, nil
 }
 
+// Warm sends a SelfSubjectAccessReview to a cluster so the connection to its
+// authorization API is open before the first user's access is checked. The
+// review asks about the dashboard itself and nothing is cached.
+func (a *K8sAuthorizer) Warm(ctx context.Context, clusterID string) error {
+	conn, err := a.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster connection: %w", err)
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "get",
+				Resource: "namespaces",
+			},
+		},
+	}
+	if _, err := conn.Client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("authorization check failed: %w", err)
+	}
+
+	return nil
+}
+
+// InvalidateUser drops every cached decision for a user
+func (a *K8sAuthorizer) InvalidateUser(username string) int {
+	removed := a.cache.invalidateUser(username)
+	a.logger.Info("Invalidated authorization cache for user", "user", username, "removed", removed)
+	return removed
+}
+
+// InvalidateAll empties the decision cache
+func (a *K8sAuthorizer) InvalidateAll() int {
+	removed := a.cache.invalidateAll()
+	a.logger.Info("Invalidated authorization cache", "removed", removed)
+	return removed
+}
+
+// CacheStats returns the decision cache metrics
+func (a *K8sAuthorizer) CacheStats() CacheStats {
+	return a.cache.snapshot()
+}
+
 // Helper function to convert extra map
 func convertExtra(extra map[string][]string) map[string]authorizationv1.ExtraValue {
 	if extra == nil {
@@ -120,3 +152,11 @@ func convertExtra(extra map[string][]string) map[string]authorizationv1.ExtraVal
 	}
 	return converted
 }
+
+// splitResource splits a resource written as "resource[.group][/subresource]",
+// e.g. "pods/log" or "pods.metrics.k8s.io", into its review attributes
+func splitResource(resource string) (group, name, subresource string) {
+	name, subresource, _ = strings.Cut(resource, "/")
+	name, group, _ = strings.Cut(name, ".")
+	return group, name, subresource
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// CacheConfig bounds the authorizer decision cache
+type CacheConfig struct {
+	MaxEntries int           `yaml:"maxEntries"`
+	AllowTTL   time.Duration `yaml:"allowTTL"`
+	DenyTTL    time.Duration `yaml:"denyTTL"`
+}
+
+// CacheStats reports decision cache activity
+type CacheStats struct {
+	Size        int    `json:"size"`
+	MaxEntries  int    `json:"maxEntries"`
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Expirations uint64 `json:"expirations"`
+	Invalidated uint64 `json:"invalidated"`
+}
+
+// CachingAuthorizer is implemented by authorizers that cache their decisions
+type CachingAuthorizer interface {
+	// InvalidateUser drops every cached decision for a user and returns how many were removed
+	InvalidateUser(username string) int
+
+	// InvalidateAll empties the cache and returns how many entries were removed
+	InvalidateAll() int
+
+	// CacheStats returns the current cache metrics
+	CacheStats() CacheStats
+}
+
+// decisionEntry is a cached decision stored in the LRU list
+type decisionEntry struct {
+	key      string
+	username string
+	decision CachedDecision
+}
+
+// decisionCache is a size-bounded LRU cache of authorization decisions.
+// Denials expire sooner than grants so newly granted access shows up quickly.
+type decisionCache struct {
+	mu      sync.Mutex
+	config  CacheConfig
+	entries map[string]*list.Element
+	order   *list.List
+	stats   CacheStats
+}
+
+// newDecisionCache creates a cache, filling in defaults for unset limits
+func newDecisionCache(config CacheConfig) *decisionCache {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 10000
+	}
+	if config.AllowTTL <= 0 {
+		config.AllowTTL = 30 * time.Second
+	}
+	if config.DenyTTL <= 0 {
+		config.DenyTTL = 5 * time.Second
+	}
+
+	return &decisionCache{
+		config:  config,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns a cached decision if present and not expired
+func (c *decisionCache) get(key string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return false, false
+	}
+
+	entry := elem.Value.(*decisionEntry)
+	ttl := c.config.AllowTTL
+	if !entry.decision.Allowed {
+		ttl = c.config.DenyTTL
+	}
+
+	if time.Since(entry.decision.Timestamp) >= ttl {
+		c.removeElement(elem)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return false, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
+	return entry.decision.Allowed, true
+}
+
+// put stores a decision, evicting the least recently used entry when full
+func (c *decisionCache) put(key, username string, allowed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	decision := CachedDecision{
+		Allowed:   allowed,
+		Timestamp: time.Now(),
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*decisionEntry).decision = decision
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&decisionEntry{
+		key:      key,
+		username: username,
+		decision: decision,
+	})
+
+	for c.order.Len() > c.config.MaxEntries {
+		c.removeElement(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// invalidateUser removes all entries cached for a user
+func (c *decisionCache) invalidateUser(username string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*decisionEntry).username == username {
+			c.removeElement(elem)
+			removed++
+		}
+		elem = next
+	}
+
+	c.stats.Invalidated += uint64(removed)
+	return removed
+}
+
+// invalidateAll removes every entry
+func (c *decisionCache) invalidateAll() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := c.order.Len()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+
+	c.stats.Invalidated += uint64(removed)
+	return removed
+}
+
+// snapshot returns a copy of the cache metrics
+func (c *decisionCache) snapshot() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = c.order.Len()
+	stats.MaxEntries = c.config.MaxEntries
+	return stats
+}
+
+// removeElement unlinks an element; callers must hold the lock
+func (c *decisionCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*decisionEntry).key)
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// ChainAuthorizer consults a list of authorizers in order. Authorizers that
+// implement Decider may abstain, in which case the next one is asked; any other
+// authorizer's answer is final. The chain can be chosen per cluster.
+type ChainAuthorizer struct {
+	authorizers  map[string]Authorizer
+	defaultChain []string
+	clusters     map[string][]string
+	logger       *slog.Logger
+}
+
+// NewChainAuthorizer creates a chain from the named authorizers and config. With
+// no chain configured, every authorizer is consulted in the order of defaultChain.
+func NewChainAuthorizer(authorizers map[string]Authorizer, config AuthorizationConfig,
+	defaultChain []string, logger *slog.Logger) (*ChainAuthorizer, error) {
+	chain := config.Chain
+	if len(chain) == 0 {
+		chain = defaultChain
+	}
+
+	if err := validateChain(authorizers, chain); err != nil {
+		return nil, err
+	}
+
+	for clusterID, clusterChain := range config.Clusters {
+		if err := validateChain(authorizers, clusterChain); err != nil {
+			return nil, fmt.Errorf("cluster %s: %w", clusterID, err)
+		}
+	}
+
+	logger.Info("Authorizer chain configured", "chain", chain, "clusterOverrides", len(config.Clusters))
+
+	return &ChainAuthorizer{
+		authorizers:  authorizers,
+		defaultChain: chain,
+		clusters:     config.Clusters,
+		logger:       logger,
+	}, nil
+}
+
+// GetName returns the name of this authorizer implementation
+func (a *ChainAuthorizer) GetName() string {
+	return "Chain"
+}
+
+// CanAccess checks if a user has permission to perform an action
+func (a *ChainAuthorizer) CanAccess(ctx context.Context, clusterID string, user UserAttributes,
+	resource, namespace, name, verb string) (bool, error) {
+	for _, authorizerName := range a.chainFor(clusterID) {
+		authorizer := a.authorizers[authorizerName]
+
+		decider, ok := authorizer.(Decider)
+		if !ok {
+			return authorizer.CanAccess(ctx, clusterID, user, resource, namespace, name, verb)
+		}
+
+		decision, err := decider.Decide(ctx, clusterID, user, resource, namespace, name, verb)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", authorizer.GetName(), err)
+		}
+
+		switch decision {
+		case DecisionAllow:
+			return true, nil
+		case DecisionDeny:
+			return false, nil
+		}
+	}
+
+	// Every authorizer abstained
+	return false, nil
+}
+
+// chainFor returns the chain to use for a cluster
+func (a *ChainAuthorizer) chainFor(clusterID string) []string {
+	if chain, ok := a.clusters[clusterID]; ok && len(chain) > 0 {
+		return chain
+	}
+	return a.defaultChain
+}
+
+// validateChain ensures every name in a chain refers to a known authorizer
+func validateChain(authorizers map[string]Authorizer, chain []string) error {
+	if len(chain) == 0 {
+		return fmt.Errorf("authorizer chain is empty")
+	}
+
+	for _, name := range chain {
+		if _, ok := authorizers[name]; !ok {
+			return fmt.Errorf("unknown authorizer %q in chain", name)
+		}
+	}
+
+	return nil
+}
@@ -117,8 +117,9 @@ func AuthMiddleware() fiber.Handler {
 	}
 }
 
-// WebSocketAuthMiddleware authenticates WebSocket connections using either query param or header
-func WebSocketAuthMiddleware(authorizer Authorizer) fiber.Handler {
+// WebSocketAuthMiddleware authenticates WebSocket connections using either query param or header,
+// then checks the user may perform the action described by resourceInfo (e.g. PodLogs, PodExec)
+func WebSocketAuthMiddleware(authorizer Authorizer, resourceInfo ResourceInfo) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Skip auth for OPTIONS requests (CORS preflight)
 		if c.Method() == "OPTIONS" {
@@ -160,20 +161,34 @@ func WebSocketAuthMiddleware(authorizer Authorizer) fiber.Handler {
 		// Store user in context
 		c.Locals("user", user)
 
-		// Check if user has permission to view pod logs (only if authorizer is provided)
+		// Check if user has permission for the requested action (only if authorizer is provided)
 		if authorizer != nil {
-			clusterID := c.Params("clusterID")
-			namespace := c.Params("namespaceID")
-			podName := c.Params("podID")
+			clusterID := c.Params(resourceInfo.ClusterParam)
+
+			var namespace string
+			if resourceInfo.NamespaceParam != "" {
+				namespace = c.Params(resourceInfo.NamespaceParam)
+			}
+
+			var name string
+			if resourceInfo.NameParam != "" {
+				name = c.Params(resourceInfo.NameParam)
+			} else if resourceInfo.ResourceName != "" {
+				name = resourceInfo.ResourceName
+			}
+
+			c.Locals("resource", resourceInfo.Resource)
+			c.Locals("verb", resourceInfo.Verb)
+			c.Locals("name", name)
 
 			allowed, err := authorizer.CanAccess(
 				c.Context(),
 				clusterID,
 				user,
-				"pods/log",
+				resourceInfo.Resource,
 				namespace,
-				podName,
-				"get",
+				name,
+				resourceInfo.Verb,
 			)
 
 			if err != nil {
@@ -184,7 +199,8 @@ func WebSocketAuthMiddleware(authorizer Authorizer) fiber.Handler {
 
 			if !allowed {
 				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-					"error": "You don't have permission to view logs for this pod",
+					"error": fmt.Sprintf("You don't have permission to %s %s for this resource",
+						resourceInfo.Verb, resourceInfo.Resource),
 				})
 			}
 		}
@@ -17,6 +17,53 @@ type ResourceInfo struct {
 	NameParam      string
 }
 
+// Pod subresources reached over WebSockets, with the verbs Kubernetes RBAC expects for each
+var (
+	// PodLogs streams container logs
+	PodLogs = ResourceInfo{
+		Resource:       "pods/log",
+		Verb:           "get",
+		ClusterParam:   "clusterID",
+		NamespaceParam: "namespaceID",
+		NameParam:      "podID",
+	}
+
+	// PodExec runs a command in a container
+	PodExec = ResourceInfo{
+		Resource:       "pods/exec",
+		Verb:           "create",
+		ClusterParam:   "clusterID",
+		NamespaceParam: "namespaceID",
+		NameParam:      "podID",
+	}
+
+	// PodAttach connects to a container's running process
+	PodAttach = ResourceInfo{
+		Resource:       "pods/attach",
+		Verb:           "create",
+		ClusterParam:   "clusterID",
+		NamespaceParam: "namespaceID",
+		NameParam:      "podID",
+	}
+
+	// PodPortForward forwards local connections to a pod port
+	PodPortForward = ResourceInfo{
+		Resource:       "pods/portforward",
+		Verb:           "create",
+		ClusterParam:   "clusterID",
+		NamespaceParam: "namespaceID",
+		NameParam:      "podID",
+	}
+
+	// PodEviction evicts any pod of a namespace, as wave restarts do
+	PodEviction = ResourceInfo{
+		Resource:       "pods/eviction",
+		Verb:           "create",
+		ClusterParam:   "clusterID",
+		NamespaceParam: "namespaceID",
+	}
+)
+
 // RequirePermission creates a middleware that checks if the user has permission to access a resource
 func RequirePermission(authorizer Authorizer, logger *slog.Logger, resourceInfo ResourceInfo) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -50,6 +97,11 @@ func RequirePermission(authorizer Authorizer, logger *slog.Logger, resourceInfo
 			name = resourceInfo.ResourceName
 		}
 
+		// Expose the check to downstream handlers such as the audit log
+		c.Locals("resource", resourceInfo.Resource)
+		c.Locals("verb", resourceInfo.Verb)
+		c.Locals("name", name)
+
 		// Check permission
 		allowed, err := authorizer.CanAccess(c.Context(), clusterID, user,
 			resourceInfo.Resource, namespace, name, resourceInfo.Verb)
@@ -74,3 +126,28 @@ func RequirePermission(authorizer Authorizer, logger *slog.Logger, resourceInfo
 		return c.Next()
 	}
 }
+
+// AdminGroup is the group whose members may use administrative endpoints
+const AdminGroup = "system:masters"
+
+// RequireAdmin creates a middleware that only allows members of AdminGroup through
+func RequireAdmin() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(UserAttributes)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "User information not available",
+			})
+		}
+
+		for _, group := range user.Groups {
+			if group == AdminGroup {
+				return c.Next()
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Administrator access required",
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+)
+
+// PolicyAuthorizer evaluates static policy rules from configuration without
+// contacting the cluster, so it keeps working for offline clusters
+type PolicyAuthorizer struct {
+	rules  []PolicyRule
+	logger *slog.Logger
+}
+
+// NewPolicyAuthorizer creates a new authorizer for the given rules. A rule
+// with an unknown effect is rejected rather than treated as an allow, so a
+// typo in a deny rule cannot grant the access it was meant to block.
+func NewPolicyAuthorizer(rules []PolicyRule, logger *slog.Logger) (*PolicyAuthorizer, error) {
+	normalized := make([]PolicyRule, len(rules))
+	for i, rule := range rules {
+		switch effect := strings.ToLower(rule.Effect); effect {
+		case "", string(DecisionAllow), string(DecisionDeny):
+			rule.Effect = effect
+		default:
+			return nil, fmt.Errorf("policy %q has unknown effect %q, expected %q or %q",
+				rule.Name, rule.Effect, DecisionAllow, DecisionDeny)
+		}
+		normalized[i] = rule
+	}
+
+	return &PolicyAuthorizer{
+		rules:  normalized,
+		logger: logger,
+	}, nil
+}
+
+// GetName returns the name of this authorizer implementation
+func (a *PolicyAuthorizer) GetName() string {
+	return "StaticPolicy"
+}
+
+// CanAccess checks if a user has permission to perform an action. Requests no
+// rule matches are denied.
+func (a *PolicyAuthorizer) CanAccess(ctx context.Context, clusterID string, user UserAttributes,
+	resource, namespace, name, verb string) (bool, error) {
+	decision, err := a.Decide(ctx, clusterID, user, resource, namespace, name, verb)
+	if err != nil {
+		return false, err
+	}
+
+	return decision == DecisionAllow, nil
+}
+
+// Decide evaluates the rules. Deny rules take precedence over allow rules, and
+// no opinion is returned when nothing matches.
+func (a *PolicyAuthorizer) Decide(ctx context.Context, clusterID string, user UserAttributes,
+	resource, namespace, name, verb string) (Decision, error) {
+	decision := DecisionNoOpinion
+
+	for _, rule := range a.rules {
+		if !rule.appliesTo(user) ||
+			!matchesAny(rule.Clusters, clusterID) ||
+			!matchesAny(rule.Namespaces, namespace) ||
+			!matchesAny(rule.Resources, resource) ||
+			!matchesAny(rule.Verbs, verb) {
+			continue
+		}
+
+		if rule.Effect == string(DecisionDeny) {
+			a.logger.Debug("Access denied by policy",
+				"rule", rule.Name,
+				"user", user.Username,
+				"resource", resource,
+				"verb", verb,
+				"cluster", clusterID)
+			return DecisionDeny, nil
+		}
+
+		decision = DecisionAllow
+	}
+
+	return decision, nil
+}
+
+// appliesTo reports whether the rule's subjects include the user
+func (r PolicyRule) appliesTo(user UserAttributes) bool {
+	if len(r.Users) == 0 && len(r.Groups) == 0 {
+		return true
+	}
+
+	if len(r.Users) > 0 && matchesAny(r.Users, user.Username) {
+		return true
+	}
+
+	for _, group := range user.Groups {
+		if len(r.Groups) > 0 && matchesAny(r.Groups, group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesAny reports whether value matches one of the patterns. An empty list
+// matches everything.
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == value {
+			return true
+		}
+		if ok, err := path.Match(pattern, value); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
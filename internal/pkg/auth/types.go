@@ -28,3 +28,53 @@ type CachedDecision struct {
 	Allowed   bool
 	Timestamp time.Time
 }
+
+// Decision is the outcome of an authorizer that may abstain
+type Decision string
+
+const (
+	// DecisionAllow grants access
+	DecisionAllow Decision = "allow"
+
+	// DecisionDeny refuses access
+	DecisionDeny Decision = "deny"
+
+	// DecisionNoOpinion defers to the next authorizer in the chain
+	DecisionNoOpinion Decision = ""
+)
+
+// Decider is implemented by authorizers that can abstain, letting a chain
+// fall through to the next authorizer
+type Decider interface {
+	Decide(ctx context.Context, clusterID string, user UserAttributes,
+		resource, namespace, name, verb string) (Decision, error)
+}
+
+// PolicyRule grants or denies access for matching users and groups. A rule
+// without users or groups applies to everyone. The remaining lists support "*"
+// and glob patterns, and match everything when empty.
+type PolicyRule struct {
+	Name       string   `yaml:"name"`
+	Users      []string `yaml:"users"`
+	Groups     []string `yaml:"groups"`
+	Clusters   []string `yaml:"clusters"`
+	Namespaces []string `yaml:"namespaces"`
+	Resources  []string `yaml:"resources"`
+	Verbs      []string `yaml:"verbs"`
+	Effect     string   `yaml:"effect"` // "allow" (default) or "deny"
+}
+
+// AuthorizationConfig configures the authorizer chain
+type AuthorizationConfig struct {
+	// Chain is the default order authorizers are consulted in, e.g. ["static", "rbac"]
+	Chain []string `yaml:"chain"`
+
+	// Clusters overrides the chain for individual clusters
+	Clusters map[string][]string `yaml:"clusters"`
+
+	// Policies are evaluated by the static policy authorizer
+	Policies []PolicyRule `yaml:"policies"`
+
+	// Cache bounds the Kubernetes RBAC decision cache
+	Cache CacheConfig `yaml:"cache"`
+}
@@ -0,0 +1,48 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AgentInfo is what an agent advertises about itself and its cluster when it registers
+type AgentInfo struct {
+	Version       string    `json:"version" bson:"version"`
+	Kinds         []string  `json:"kinds" bson:"kinds"`
+	ServerVersion string    `json:"serverVersion,omitempty" bson:"server_version,omitempty"`
+	NodeCount     int       `json:"nodeCount" bson:"node_count"`
+	RegisteredAt  time.Time `json:"registeredAt" bson:"registered_at"`
+	// LastHeartbeat is when the agent last reported it was alive
+	LastHeartbeat *time.Time `json:"lastHeartbeat,omitempty" bson:"last_heartbeat,omitempty"`
+	// Degraded is set while the agent samples status updates under load, so
+	// pod and workload status may lag behind the cluster
+	Degraded bool `json:"degraded" bson:"degraded"`
+}
+
+// Handshake builds the agent info for a connection. Cluster details that
+// cannot be read are left empty and reported in the error, so the agent can
+// still register with what it has.
+func Handshake(ctx context.Context, conn *Connection, version string, kinds []string) (*AgentInfo, error) {
+	info := &AgentInfo{
+		Version:      version,
+		Kinds:        kinds,
+		RegisteredAt: time.Now(),
+	}
+
+	serverVersion, err := conn.Client.Discovery().ServerVersion()
+	if err != nil {
+		return info, fmt.Errorf("failed to get server version: %w", err)
+	}
+	info.ServerVersion = serverVersion.GitVersion
+
+	nodes, err := conn.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return info, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	info.NodeCount = len(nodes.Items)
+
+	return info, nil
+}
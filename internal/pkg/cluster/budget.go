@@ -0,0 +1,155 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// cacheSampleSize is how many objects of a cache are encoded to estimate its size
+const cacheSampleSize = 16
+
+// CacheBudgetConfig bounds the memory the API's informer caches may hold
+// across all clusters
+type CacheBudgetConfig struct {
+	// MaxBytes is the budget, zero for none. Caches are measured by the
+	// encoded size of their objects, so leave headroom below the memory limit.
+	MaxBytes int64 `yaml:"maxBytes"`
+	// Interval is how often the caches are measured
+	Interval time.Duration `yaml:"interval"`
+}
+
+// CacheBudget keeps informer caches within a memory budget. When they grow
+// past it, the caches of the least recently used clusters are dropped until
+// the rest fit. Evicted clusters stay registered and connected, and their
+// informers start again when a request needs them.
+type CacheBudget struct {
+	manager   *Manager
+	informers *NamespaceInformers
+	config    CacheBudgetConfig
+	logger    *slog.Logger
+}
+
+// NewCacheBudget creates a budget over the cluster-wide informers of the
+// manager's connections and the namespace informers
+func NewCacheBudget(manager *Manager, namespaceInformers *NamespaceInformers, config CacheBudgetConfig, logger *slog.Logger) *CacheBudget {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+
+	return &CacheBudget{
+		manager:   manager,
+		informers: namespaceInformers,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// Start measures the caches on an interval until the context is cancelled.
+// Nothing runs without a budget.
+func (b *CacheBudget) Start(ctx context.Context) {
+	if b.config.MaxBytes <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(b.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				b.enforce()
+			}
+		}
+	}()
+}
+
+// enforce evicts the caches of the least recently used clusters while the
+// caches are over budget
+func (b *CacheBudget) enforce() {
+	type usage struct {
+		clusterID string
+		bytes     int64
+		lastUsed  time.Time
+	}
+
+	var (
+		clusters []usage
+		total    int64
+	)
+	for clusterID, conn := range b.manager.GetConnections() {
+		b.manager.mu.RLock()
+		caches := append([]cache.SharedIndexInformer{}, conn.caches...)
+		b.manager.mu.RUnlock()
+		caches = append(caches, b.informers.clusterCaches(clusterID)...)
+
+		var bytes int64
+		for _, informer := range caches {
+			bytes += cacheSize(informer.GetStore())
+		}
+		if bytes == 0 {
+			continue
+		}
+
+		clusters = append(clusters, usage{clusterID: clusterID, bytes: bytes, lastUsed: b.manager.lastUsed(clusterID)})
+		total += bytes
+	}
+
+	if total <= b.config.MaxBytes {
+		b.logger.Debug("Informer caches within budget", "bytes", total, "maxBytes", b.config.MaxBytes)
+		return
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].lastUsed.Before(clusters[j].lastUsed)
+	})
+
+	for _, cluster := range clusters {
+		if total <= b.config.MaxBytes {
+			break
+		}
+
+		b.manager.evictCaches(cluster.clusterID)
+		b.informers.forget(cluster.clusterID)
+		total -= cluster.bytes
+
+		b.logger.Warn("Evicted informer caches of least recently used cluster",
+			"clusterID", cluster.clusterID,
+			"bytes", cluster.bytes,
+			"lastUsed", cluster.lastUsed,
+			"remaining", total,
+			"maxBytes", b.config.MaxBytes)
+	}
+}
+
+// cacheSize estimates the memory of a cache from the encoded size of a
+// sample of its objects
+func cacheSize(store cache.Store) int64 {
+	objects := store.List()
+	if len(objects) == 0 {
+		return 0
+	}
+
+	step := max(len(objects)/cacheSampleSize, 1)
+	var sampled, bytes int64
+	for i := 0; i < len(objects); i += step {
+		data, err := json.Marshal(objects[i])
+		if err != nil {
+			continue
+		}
+		bytes += int64(len(data))
+		sampled++
+	}
+	if sampled == 0 {
+		return 0
+	}
+
+	return bytes / sampled * int64(len(objects))
+}
@@ -1,54 +1,58 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
-	"sync"
+	"sort"
 
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-// ClusterConfig holds Kubernetes client configuration details
-type ClusterConfig struct {
-	Client     *kubernetes.Clientset
-	Config     *rest.Config
-	Kubeconfig string
-	Cluster    string
-}
+// inClusterID is the cluster ID used when running inside a cluster
+const inClusterID = "in-cluster"
 
-// ClientManager manages Kubernetes clients for multiple clusters
+// ClientManager adapts Manager for the agent and TUI: clusters come from the
+// local kubeconfig (or the in-cluster config), are connected eagerly, and
+// follow kubeconfig changes. Connections are the same ones the REST API uses.
 type ClientManager struct {
-	configs map[string]*ClusterConfig
-	logger  *slog.Logger
+	manager *Manager
 	watcher *KubeConfigWatcher
-	mu      sync.RWMutex
+	logger  *slog.Logger
+	cancel  context.CancelFunc
 }
 
 // NewClientManager creates a new client manager
 func NewClientManager(logger *slog.Logger) (*ClientManager, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	cm := &ClientManager{
-		configs: make(map[string]*ClusterConfig),
-		logger:  logger,
+		logger: logger,
+		cancel: cancel,
 	}
 
 	// Create watcher with callback
 	watcher, err := NewKubeConfigWatcher(logger, cm.handleKubeConfigChange)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to create kubeconfig watcher: %w", err)
 	}
 	cm.watcher = watcher
+	cm.manager = NewManager(ctx, logger, &kubeConfigWatcherProvider{watcher: watcher}, RateLimitConfig{})
 
 	// Initial client creation
 	kubeConfig := watcher.GetConfig()
 	if kubeConfig == nil {
+		cancel()
 		return nil, fmt.Errorf("failed to get initial kubeconfig")
 	}
 
 	// Create clients for all contexts
-	err = cm.createClientsFromConfig(kubeConfig)
-	if err != nil {
+	if err := cm.sync(false); err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to create initial clients: %w", err)
 	}
 
@@ -58,25 +62,37 @@ func NewClientManager(logger *slog.Logger) (*ClientManager, error) {
 	return cm, nil
 }
 
-// GetClients returns all cluster clients
-func (cm *ClientManager) GetClients() []*ClusterConfig {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
+// Manager returns the underlying cluster manager
+func (cm *ClientManager) Manager() *Manager {
+	return cm.manager
+}
+
+// GetClients returns all connected clusters
+func (cm *ClientManager) GetClients() []*Connection {
+	connections := cm.manager.GetConnections()
 
-	clients := make([]*ClusterConfig, 0, len(cm.configs))
-	for _, cfg := range cm.configs {
-		clients = append(clients, cfg)
+	clients := make([]*Connection, 0, len(connections))
+	for _, conn := range connections {
+		if conn.IsConnected() {
+			clients = append(clients, conn)
+		}
 	}
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].ID < clients[j].ID
+	})
+
 	return clients
 }
 
-// GetClient returns a specific cluster client
-func (cm *ClientManager) GetClient(clusterName string) (*ClusterConfig, bool) {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
+// GetClient returns a specific connected cluster
+func (cm *ClientManager) GetClient(clusterName string) (*Connection, bool) {
+	conn, exists := cm.manager.GetConnections()[clusterName]
+	if !exists || !conn.IsConnected() {
+		return nil, false
+	}
 
-	client, exists := cm.configs[clusterName]
-	return client, exists
+	return conn, true
 }
 
 // Stop stops the client manager and releases resources
@@ -85,119 +101,132 @@ func (cm *ClientManager) Stop() {
 		cm.watcher.Stop()
 	}
 
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-	// Clear clients
-	cm.configs = make(map[string]*ClusterConfig)
+	cm.manager.StopAllClusters()
+	cm.cancel()
 }
 
 // handleKubeConfigChange is called when the kubeconfig changes
 func (cm *ClientManager) handleKubeConfigChange(config *KubeConfig) {
 	cm.logger.Info("Kubeconfig changed, updating clients")
 
-	err := cm.createClientsFromConfig(config)
-	if err != nil {
+	if err := cm.sync(true); err != nil {
 		cm.logger.Error("Failed to update clients after kubeconfig change", "error", err)
 	}
 }
 
-// createClientsFromConfig creates clients from a kubeconfig
-func (cm *ClientManager) createClientsFromConfig(config *KubeConfig) error {
-	// Handle in-cluster case
-	if config.Path == "" {
-		inClusterConfig, err := rest.InClusterConfig()
-		if err != nil {
-			return fmt.Errorf("failed to get in-cluster config: %w", err)
+// sync connects every context in the kubeconfig and stops connections for
+// removed contexts. With reconnect set, existing connections authenticate
+// again so changed credentials are picked up.
+func (cm *ClientManager) sync(reconnect bool) error {
+	discovered, err := cm.manager.provider.DiscoverClusters()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(discovered))
+	for _, clusterConfig := range discovered {
+		cm.logger.Info("Processing context", "name", clusterConfig.ID)
+		seen[clusterConfig.ID] = true
+
+		if err := cm.manager.Register(clusterConfig.ID, clusterConfig.APIURL); err != nil {
+			cm.logger.Warn("Failed to register context", "context", clusterConfig.ID, "error", err)
+			continue
 		}
 
-		clientset, err := kubernetes.NewForConfig(inClusterConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create in-cluster client: %w", err)
+		if reconnect {
+			cm.manager.markDisconnected(clusterConfig.ID)
 		}
 
-		cm.mu.Lock()
-		cm.configs["in-cluster"] = &ClusterConfig{
-			Client:     clientset,
-			Config:     inClusterConfig,
-			Kubeconfig: "",
-			Cluster:    "in-cluster",
+		if _, err := cm.manager.GetCluster(clusterConfig.ID); err != nil {
+			cm.logger.Warn("Failed to create client", "context", clusterConfig.ID, "error", err)
 		}
-		cm.mu.Unlock()
+	}
 
-		return nil
+	// Remove contexts that no longer exist
+	for name := range cm.manager.GetConnections() {
+		if !seen[name] {
+			if err := cm.manager.StopCluster(name); err == nil {
+				cm.logger.Info("Removed client for deleted context", "context", name)
+			}
+		}
 	}
 
-	// Handle file-based kubeconfig
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
+	return nil
+}
 
-	// Track contexts to detect removals
-	seen := make(map[string]bool)
+// kubeConfigWatcherProvider serves the contexts of the watched kubeconfig,
+// or the single in-cluster config
+type kubeConfigWatcherProvider struct {
+	watcher *KubeConfigWatcher
+}
 
-	// Create clients for each context
-	for contextName := range config.Contexts {
-		cm.logger.Info("Processing context", "name", contextName)
+// DiscoverClusters returns one cluster per kubeconfig context
+func (p *kubeConfigWatcherProvider) DiscoverClusters() ([]providers.ClusterConfig, error) {
+	config := p.watcher.GetConfig()
+	if config == nil {
+		return nil, fmt.Errorf("kubeconfig not loaded")
+	}
 
-		// Build rest.Config for this context
-		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			&clientcmd.ClientConfigLoadingRules{ExplicitPath: config.Path},
-			&clientcmd.ConfigOverrides{CurrentContext: contextName},
-		)
+	if config.Path == "" {
+		return []providers.ClusterConfig{{ID: inClusterID}}, nil
+	}
 
-		restConfig, err := clientConfig.ClientConfig()
-		if err != nil {
-			cm.logger.Warn("Failed to build client config", "context", contextName, "error", err)
-			continue
+	clusters := make([]providers.ClusterConfig, 0, len(config.Contexts))
+	for contextName, kubeContext := range config.Contexts {
+		clusterConfig := providers.ClusterConfig{
+			ID:             contextName,
+			KubeconfigPath: config.Path,
 		}
 
-		// Create clientset
-		clientset, err := kubernetes.NewForConfig(restConfig)
-		if err != nil {
-			cm.logger.Warn("Failed to create client", "context", contextName, "error", err)
-			continue
+		if cluster, ok := config.RawConfig.Clusters[kubeContext.Cluster]; ok {
+			clusterConfig.APIURL = cluster.Server
 		}
 
-		// Save client
-		cm.configs[contextName] = &ClusterConfig{
-			Client:     clientset,
-			Config:     restConfig,
-			Kubeconfig: config.Path,
-			Cluster:    contextName,
-		}
-		seen[contextName] = true
+		clusters = append(clusters, clusterConfig)
 	}
 
-	// Remove contexts that no longer exist
-	for name := range cm.configs {
-		if !seen[name] && name != "in-cluster" {
-			delete(cm.configs, name)
-			cm.logger.Info("Removed client for deleted context", "context", name)
-		}
+	return clusters, nil
+}
+
+// Authenticate builds the rest.Config for a context
+func (p *kubeConfigWatcherProvider) Authenticate(clusterID string) (*rest.Config, error) {
+	path := ""
+	if config := p.watcher.GetConfig(); config != nil {
+		path = config.Path
 	}
 
-	return nil
+	return restConfigFor(clusterID, path)
 }
 
-// CreateClient creates a new Kubernetes client for the specified context
-func CreateClient(contextName, kubeconfigPath string) (*kubernetes.Clientset, *rest.Config, error) {
-	var config *rest.Config
-	var err error
-
-	if contextName == "in-cluster" || kubeconfigPath == "" {
+// restConfigFor builds a rest.Config for a kubeconfig context, or the
+// in-cluster config when no kubeconfig is used
+func restConfigFor(contextName, kubeconfigPath string) (*rest.Config, error) {
+	if contextName == inClusterID || kubeconfigPath == "" {
 		// In-cluster configuration
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create in-cluster config: %w", err)
-		}
-	} else {
-		// Out-of-cluster configuration
-		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
-			&clientcmd.ConfigOverrides{CurrentContext: contextName},
-		).ClientConfig()
+		config, err := rest.InClusterConfig()
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to build client config: %w", err)
+			return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
 		}
+		return config, nil
+	}
+
+	// Out-of-cluster configuration
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client config: %w", err)
+	}
+
+	return config, nil
+}
+
+// CreateClient creates a new Kubernetes client for the specified context
+func CreateClient(contextName, kubeconfigPath string) (*kubernetes.Clientset, *rest.Config, error) {
+	config, err := restConfigFor(contextName, kubeconfigPath)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Create clientset
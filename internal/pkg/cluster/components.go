@@ -0,0 +1,171 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckStatus is the result of one readyz check or control plane component
+type CheckStatus struct {
+	Name    string `json:"name" bson:"name"`
+	Healthy bool   `json:"healthy" bson:"healthy"`
+	Message string `json:"message,omitempty" bson:"message,omitempty"`
+}
+
+// ServerStatus is the version and control plane health of a cluster as its
+// agent last saw it. Components come from the deprecated componentstatuses
+// API, which many managed clusters leave empty or forbid.
+type ServerStatus struct {
+	GitVersion  string        `json:"gitVersion" bson:"git_version"`
+	Platform    string        `json:"platform" bson:"platform"`
+	GoVersion   string        `json:"goVersion" bson:"go_version"`
+	BuildDate   string        `json:"buildDate" bson:"build_date"`
+	Ready       bool          `json:"ready" bson:"ready"`
+	ReadyChecks []CheckStatus `json:"readyChecks" bson:"ready_checks"`
+	Components  []CheckStatus `json:"components" bson:"components"`
+	// Errors lists what could not be collected
+	Errors      []string  `json:"errors,omitempty" bson:"errors,omitempty"`
+	CollectedAt time.Time `json:"collectedAt" bson:"collected_at"`
+}
+
+// ServerStatusPayload carries a cluster's server status to the REST API
+type ServerStatusPayload struct {
+	ClusterName string        `json:"clusterName"`
+	Status      *ServerStatus `json:"status"`
+}
+
+// CollectServerStatus reads the version, readyz checks and component
+// statuses of a cluster. Parts that can't be read are recorded in Errors.
+func CollectServerStatus(ctx context.Context, conn *Connection) *ServerStatus {
+	status := &ServerStatus{
+		ReadyChecks: make([]CheckStatus, 0),
+		Components:  make([]CheckStatus, 0),
+		CollectedAt: time.Now(),
+	}
+
+	version, err := conn.Client.Discovery().ServerVersion()
+	if err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("version: %v", err))
+	} else {
+		status.GitVersion = version.GitVersion
+		status.Platform = version.Platform
+		status.GoVersion = version.GoVersion
+		status.BuildDate = version.BuildDate
+	}
+
+	// A failing readyz answers 500 with the same verbose body, so the body
+	// is parsed whatever the status code
+	body, err := conn.Client.Discovery().RESTClient().Get().AbsPath("/readyz").Param("verbose", "true").DoRaw(ctx)
+	status.ReadyChecks = parseReadyz(string(body))
+	status.Ready = err == nil
+	if err != nil && len(status.ReadyChecks) == 0 {
+		status.Errors = append(status.Errors, fmt.Sprintf("readyz: %v", err))
+	}
+
+	components, err := conn.Client.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("componentstatuses: %v", err))
+	} else {
+		for _, component := range components.Items {
+			status.Components = append(status.Components, componentCheck(component))
+		}
+	}
+
+	return status
+}
+
+// PublishServerStatus sends a cluster's server status to the REST API
+func PublishServerStatus(messageQueue messagingtypes.Publisher, clusterName string, status *ServerStatus) error {
+	data, err := json.Marshal(ServerStatusPayload{ClusterName: clusterName, Status: status})
+	if err != nil {
+		return fmt.Errorf("failed to marshal server status payload: %w", err)
+	}
+
+	return messageQueue.Publish("cluster_server_status", data)
+}
+
+// ReportServerStatus collects and publishes the server status of every
+// connection right away and then on every interval until ctx is done
+func ReportServerStatus(ctx context.Context, messageQueue messagingtypes.Publisher, connections []*Connection, interval time.Duration, logger *slog.Logger) {
+	report := func() {
+		for _, conn := range connections {
+			checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			status := CollectServerStatus(checkCtx, conn)
+			cancel()
+
+			if len(status.Errors) > 0 {
+				logger.Debug("Incomplete server status", "cluster", conn.ID, "errors", status.Errors)
+			}
+			if err := PublishServerStatus(messageQueue, conn.ID, status); err != nil {
+				logger.Warn("Failed to publish server status", "cluster", conn.ID, "error", err)
+			}
+		}
+	}
+
+	go func() {
+		report()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report()
+			}
+		}
+	}()
+}
+
+// parseReadyz parses the verbose readyz output, one "[+]name ok" or
+// "[-]name failed: reason" line per check
+func parseReadyz(body string) []CheckStatus {
+	checks := make([]CheckStatus, 0)
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+
+		var healthy bool
+		switch {
+		case strings.HasPrefix(line, "[+]"):
+			healthy = true
+		case strings.HasPrefix(line, "[-]"):
+		default:
+			continue
+		}
+
+		name, message, _ := strings.Cut(line[3:], " ")
+		check := CheckStatus{Name: name, Healthy: healthy}
+		if !healthy {
+			check.Message = strings.TrimPrefix(message, "failed: ")
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// componentCheck converts a component status
+func componentCheck(component corev1.ComponentStatus) CheckStatus {
+	check := CheckStatus{Name: component.Name}
+	for _, condition := range component.Conditions {
+		if condition.Type != corev1.ComponentHealthy {
+			continue
+		}
+
+		check.Healthy = condition.Status == corev1.ConditionTrue
+		check.Message = condition.Message
+		if condition.Error != "" {
+			check.Message = condition.Error
+		}
+	}
+	return check
+}
@@ -13,12 +13,15 @@ import (
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 )
 
 // ConnectionPayload represents the payload sent to the REST API
 type ConnectionPayload struct {
-	ClusterName string `json:"clusterName"`
-	APIURL      string `json:"apiURL"`
+	ClusterName string     `json:"clusterName"`
+	APIURL      string     `json:"apiURL"`
+	Provider    string     `json:"provider,omitempty"`
+	Agent       *AgentInfo `json:"agent,omitempty"`
 }
 
 // Connection represents a connection to a Kubernetes cluster
@@ -30,6 +33,8 @@ type Connection struct {
 	StopCh   chan struct{}
 	AuthDone bool
 	Running  bool // Tracks whether informers are running
+	// caches are the started informers of the factory, measured by CacheBudget
+	caches []cache.SharedIndexInformer
 }
 
 // NewConnection creates a new cluster connection
@@ -53,6 +58,11 @@ func (c *Connection) InitializeInformers() {
 
 // Stop stops this cluster's informers
 func (c *Connection) Stop() {
+	if c.StopCh == nil {
+		// Registered but never connected
+		return
+	}
+
 	select {
 	case <-c.StopCh:
 		// Already closed
@@ -62,11 +72,13 @@ func (c *Connection) Stop() {
 	c.Running = false
 }
 
-// PublishConnection sends the cluster connection details via the message queue
-func PublishConnection(messageQueue messagingtypes.Publisher, clusterName, apiServerURL string, logger *slog.Logger) error {
+// PublishConnection sends the cluster connection details and the agent's
+// handshake via the message queue
+func PublishConnection(messageQueue messagingtypes.Publisher, clusterName, apiServerURL string, agent *AgentInfo, logger *slog.Logger) error {
 	payload := ConnectionPayload{
 		ClusterName: clusterName,
 		APIURL:      apiServerURL,
+		Agent:       agent,
 	}
 
 	data, err := json.Marshal(payload)
@@ -105,15 +117,20 @@ func (c *Connection) StartInformers() {
 
 // GetHealthStatus provides health check status for the cluster
 func (c *Connection) GetHealthStatus() (bool, error) {
-	// Basic check: try listing namespaces
-	if c.Client == nil {
-		return false, fmt.Errorf("client not initialized")
-	}
-
-	_, err := c.Client.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{Limit: 1})
-	if err != nil {
+	if err := c.CheckHealth(context.Background()); err != nil {
 		return false, err
 	}
 
 	return true, nil
 }
+
+// CheckHealth verifies the API server answers within the context deadline
+func (c *Connection) CheckHealth(ctx context.Context) error {
+	// Basic check: try listing namespaces
+	if c.Client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+
+	_, err := c.Client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{Limit: 1})
+	return err
+}
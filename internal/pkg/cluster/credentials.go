@@ -0,0 +1,111 @@
+package cluster
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// tokenCacheTTL is how long an accepted agent token is trusted without
+// checking the store again
+const tokenCacheTTL = time.Minute
+
+// Errors returned when an agent's token is refused
+var (
+	ErrMissingToken = errors.New("missing agent token")
+	ErrInvalidToken = errors.New("invalid agent token")
+)
+
+// AgentConfig controls how agents authenticate to the REST API
+type AgentConfig struct {
+	// RequireToken rejects agent messages without a valid token. Rotate
+	// each cluster's credentials before turning it on.
+	RequireToken bool `yaml:"requireToken"`
+}
+
+// AgentCredential is the token the agent serving a cluster sends with its
+// messages. Only hashes are stored. While a rotation is pending, the
+// previous token is still accepted so the agent isn't locked out before it
+// receives the new one.
+type AgentCredential struct {
+	TokenHash    string    `json:"-" bson:"token_hash"`
+	PreviousHash string    `json:"-" bson:"previous_hash,omitempty"`
+	Pending      bool      `json:"pending" bson:"pending"`
+	RotatedAt    time.Time `json:"rotatedAt" bson:"rotated_at"`
+	RotatedBy    string    `json:"rotatedBy,omitempty" bson:"rotated_by,omitempty"`
+}
+
+// NewAgentToken returns a random agent token and its hash
+func NewAgentToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+
+	token := hex.EncodeToString(raw)
+	return token, HashAgentToken(token), nil
+}
+
+// HashAgentToken returns the hash an agent token is stored as
+func HashAgentToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenVerifier checks agent tokens against the stored credentials,
+// remembering accepted tokens for a short while
+type TokenVerifier struct {
+	known    func(ctx context.Context, hash string) (bool, error)
+	accepted map[string]time.Time
+	mu       sync.Mutex
+}
+
+// NewTokenVerifier creates a verifier that looks hashes up with known
+func NewTokenVerifier(known func(ctx context.Context, hash string) (bool, error)) *TokenVerifier {
+	return &TokenVerifier{
+		known:    known,
+		accepted: make(map[string]time.Time),
+	}
+}
+
+// Authenticate accepts the current or pending-rotation token of any cluster
+func (v *TokenVerifier) Authenticate(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrMissingToken
+	}
+
+	hash := HashAgentToken(token)
+
+	v.mu.Lock()
+	expires, ok := v.accepted[hash]
+	v.mu.Unlock()
+	if ok && time.Now().Before(expires) {
+		return nil
+	}
+
+	known, err := v.known(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if !known {
+		return ErrInvalidToken
+	}
+
+	v.mu.Lock()
+	v.accepted[hash] = time.Now().Add(tokenCacheTTL)
+	v.mu.Unlock()
+
+	return nil
+}
+
+// Forget drops the accepted tokens so revoked ones are refused right away
+func (v *TokenVerifier) Forget() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	clear(v.accepted)
+}
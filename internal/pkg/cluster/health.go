@@ -0,0 +1,227 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Cluster health statuses
+const (
+	StatusUnknown   = "unknown"
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
+// HealthConfig controls the background health prober
+type HealthConfig struct {
+	Interval   time.Duration `yaml:"interval"`
+	Timeout    time.Duration `yaml:"timeout"`
+	MaxBackoff time.Duration `yaml:"maxBackoff"`
+}
+
+// HealthEvent reports a cluster status transition
+type HealthEvent struct {
+	ClusterID string    `json:"clusterID"`
+	Status    string    `json:"status"`
+	Previous  string    `json:"previous"`
+	Error     string    `json:"error,omitempty"`
+	Failures  int       `json:"failures"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// clusterHealth is the prober's view of one cluster
+type clusterHealth struct {
+	status    string
+	lastError string
+	failures  int
+	nextProbe time.Time
+}
+
+// StartHealthMonitor probes every connected cluster on an interval until the
+// manager's context is cancelled. Failing clusters are re-authenticated and
+// probed with exponential backoff; onChange is called on status transitions.
+func (m *Manager) StartHealthMonitor(config HealthConfig, onChange func(HealthEvent)) {
+	if config.Interval <= 0 {
+		config.Interval = 30 * time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.probeAll(config, onChange)
+			case <-m.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	m.logger.Info("Cluster health monitor started", "interval", config.Interval)
+}
+
+// Status returns the last probed status of a cluster
+func (m *Manager) Status(clusterID string) string {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+
+	if state, ok := m.health[clusterID]; ok {
+		return state.status
+	}
+
+	return StatusUnknown
+}
+
+// probeAll probes every connected cluster that is due, in parallel
+func (m *Manager) probeAll(config HealthConfig, onChange func(HealthEvent)) {
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for clusterID, conn := range m.GetConnections() {
+		// Clusters nobody has used yet stay lazy; failed ones are retried
+		// through reconnect even though they are no longer connected
+		if !conn.IsConnected() && m.Status(clusterID) != StatusUnhealthy {
+			continue
+		}
+
+		if !m.due(clusterID, now) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(clusterID string) {
+			defer wg.Done()
+			m.probe(clusterID, config, onChange)
+		}(clusterID)
+	}
+
+	wg.Wait()
+}
+
+// due reports whether a cluster's backoff has elapsed
+func (m *Manager) due(clusterID string, now time.Time) bool {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+
+	state, ok := m.health[clusterID]
+	return !ok || !now.Before(state.nextProbe)
+}
+
+// probe checks one cluster, reconnecting it once if the check fails
+func (m *Manager) probe(clusterID string, config HealthConfig, onChange func(HealthEvent)) {
+	err := m.check(clusterID, config.Timeout)
+	if err != nil {
+		m.logger.Warn("Cluster health check failed, reconnecting", "clusterID", clusterID, "error", err)
+
+		m.markDisconnected(clusterID)
+		if _, reconnectErr := m.GetCluster(clusterID); reconnectErr != nil {
+			err = reconnectErr
+		} else {
+			err = m.check(clusterID, config.Timeout)
+		}
+	}
+
+	m.recordHealth(clusterID, err, config, onChange)
+}
+
+// check runs a single health check with a timeout
+func (m *Manager) check(clusterID string, timeout time.Duration) error {
+	m.mu.RLock()
+	conn, exists := m.connections[clusterID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, timeout)
+	defer cancel()
+
+	return conn.CheckHealth(ctx)
+}
+
+// markDisconnected forces the next GetCluster to authenticate again. The
+// connection is replaced by a disconnected one rather than changed, since
+// callers read the one they got without the lock. Its informers are stopped.
+func (m *Manager) markDisconnected(clusterID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conn, exists := m.connections[clusterID]
+	if !exists {
+		return
+	}
+
+	conn.Stop()
+	m.connections[clusterID] = &Connection{
+		ID:     clusterID,
+		Client: conn.Client,
+		Config: conn.Config,
+	}
+}
+
+// recordHealth updates a cluster's state and reports status transitions
+func (m *Manager) recordHealth(clusterID string, err error, config HealthConfig, onChange func(HealthEvent)) {
+	m.healthMu.Lock()
+
+	state, ok := m.health[clusterID]
+	if !ok {
+		state = &clusterHealth{status: StatusUnknown}
+		m.health[clusterID] = state
+	}
+
+	previous := state.status
+	if err != nil {
+		state.status = StatusUnhealthy
+		state.lastError = err.Error()
+		state.failures++
+
+		backoff := config.Interval << min(state.failures-1, 16)
+		if backoff <= 0 || backoff > config.MaxBackoff {
+			backoff = config.MaxBackoff
+		}
+		state.nextProbe = time.Now().Add(backoff)
+	} else {
+		state.status = StatusHealthy
+		state.lastError = ""
+		state.failures = 0
+		state.nextProbe = time.Time{}
+	}
+
+	event := HealthEvent{
+		ClusterID: clusterID,
+		Status:    state.status,
+		Previous:  previous,
+		Error:     state.lastError,
+		Failures:  state.failures,
+		Timestamp: time.Now(),
+	}
+
+	m.healthMu.Unlock()
+
+	if previous == event.Status {
+		return
+	}
+
+	m.logger.Info("Cluster status changed", "clusterID", clusterID, "status", event.Status, "previous", previous)
+	if onChange != nil {
+		onChange(event)
+	}
+}
+
+// forgetHealth drops the health state of a removed cluster
+func (m *Manager) forgetHealth(clusterID string) {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+
+	delete(m.health, clusterID)
+}
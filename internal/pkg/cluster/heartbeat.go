@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+)
+
+// HeartbeatInterval is how often agents report that they are alive
+const HeartbeatInterval = 30 * time.Second
+
+// heartbeatGrace is how long an agent may stay silent before it is shown offline
+const heartbeatGrace = 3 * HeartbeatInterval
+
+// HeartbeatPayload tells the REST API that the agent serving a cluster is alive
+type HeartbeatPayload struct {
+	ClusterName string    `json:"clusterName"`
+	Version     string    `json:"version"`
+	SentAt      time.Time `json:"sentAt"`
+	// Degraded is set while the agent is sampling status updates under load
+	Degraded bool `json:"degraded,omitempty"`
+}
+
+// Online reports whether an agent sent a heartbeat recently
+func (a *AgentInfo) Online(now time.Time) bool {
+	return a != nil && a.LastHeartbeat != nil && now.Sub(*a.LastHeartbeat) <= heartbeatGrace
+}
+
+// PublishHeartbeat sends a heartbeat for a cluster to the REST API
+func PublishHeartbeat(messageQueue messagingtypes.Publisher, clusterName, version string, degraded bool) error {
+	data, err := json.Marshal(HeartbeatPayload{ClusterName: clusterName, Version: version, SentAt: time.Now(), Degraded: degraded})
+	if err != nil {
+		return fmt.Errorf("failed to marshal heartbeat payload: %w", err)
+	}
+
+	return messageQueue.Publish("agent_heartbeat", data)
+}
+
+// SendHeartbeats publishes a heartbeat for every connection right away and
+// then on every interval until ctx is done. degraded reports whether the
+// agent is currently shedding load.
+func SendHeartbeats(ctx context.Context, messageQueue messagingtypes.Publisher, connections []*Connection, version string, degraded func() bool, interval time.Duration, logger *slog.Logger) {
+	beat := func() {
+		shedding := degraded()
+		for _, conn := range connections {
+			if err := PublishHeartbeat(messageQueue, conn.ID, version, shedding); err != nil {
+				logger.Warn("Failed to publish heartbeat", "cluster", conn.ID, "error", err)
+			}
+		}
+	}
+
+	go func() {
+		beat()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				beat()
+			}
+		}
+	}()
+}
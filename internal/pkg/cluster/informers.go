@@ -0,0 +1,179 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// InformerConfig controls informers started per namespace on demand
+type InformerConfig struct {
+	// IdleTimeout is how long a namespace's informers keep running after
+	// their last user released them
+	IdleTimeout time.Duration `yaml:"idleTimeout"`
+}
+
+// informerKey identifies the informers of one namespace of a cluster
+type informerKey struct {
+	clusterID string
+	namespace string
+}
+
+// namespaceInformers is a running informer factory scoped to one namespace
+type namespaceInformers struct {
+	client   *kubernetes.Clientset
+	factory  informers.SharedInformerFactory
+	caches   []cache.SharedIndexInformer
+	stopCh   chan struct{}
+	refs     int
+	released time.Time
+}
+
+// NamespaceInformers starts informers for one namespace at a time, when a
+// request first needs them, instead of watching every object in a cluster.
+// Users are reference counted and informers nobody has used for the idle
+// timeout are stopped, so large clusters only cost memory for the
+// namespaces people look at.
+type NamespaceInformers struct {
+	manager *Manager
+	config  InformerConfig
+	logger  *slog.Logger
+
+	mu      sync.Mutex
+	running map[informerKey]*namespaceInformers
+}
+
+// NewNamespaceInformers creates a pool of namespace informers
+func NewNamespaceInformers(manager *Manager, config InformerConfig, logger *slog.Logger) *NamespaceInformers {
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = 5 * time.Minute
+	}
+
+	return &NamespaceInformers{
+		manager: manager,
+		config:  config,
+		logger:  logger,
+		running: make(map[informerKey]*namespaceInformers),
+	}
+}
+
+// Acquire returns the informer factory of a namespace, starting it on first
+// use. informer registers the informer the caller reads from, which is
+// started and synced before Acquire returns. The caller must call release
+// once it is done reading.
+func (n *NamespaceInformers) Acquire(ctx context.Context, clusterID, namespace string,
+	informer func(informers.SharedInformerFactory) cache.SharedIndexInformer) (informers.SharedInformerFactory, func(), error) {
+	conn, err := n.manager.GetCluster(clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := informerKey{clusterID: clusterID, namespace: namespace}
+
+	n.mu.Lock()
+	entry, ok := n.running[key]
+	if ok && entry.client != conn.Client && entry.refs == 0 {
+		// The cluster reconnected since; informers on the old client are replaced
+		close(entry.stopCh)
+		ok = false
+	}
+	if !ok {
+		entry = &namespaceInformers{
+			client:  conn.Client,
+			factory: informers.NewSharedInformerFactoryWithOptions(conn.Client, 5*time.Minute, informers.WithNamespace(namespace)),
+			stopCh:  make(chan struct{}),
+		}
+		n.running[key] = entry
+		n.logger.Debug("Starting namespace informers", "clusterID", clusterID, "namespace", namespace)
+	}
+	entry.refs++
+
+	// Start only starts informers that aren't running yet
+	started := informer(entry.factory)
+	if !slices.Contains(entry.caches, started) {
+		entry.caches = append(entry.caches, started)
+	}
+	synced := started.HasSynced
+	entry.factory.Start(entry.stopCh)
+	n.mu.Unlock()
+
+	release := sync.OnceFunc(func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		entry.refs--
+		if entry.refs == 0 {
+			entry.released = time.Now()
+		}
+	})
+
+	if !cache.WaitForCacheSync(ctx.Done(), synced) {
+		release()
+		return nil, nil, fmt.Errorf("timed out waiting for informers of namespace %s to sync", namespace)
+	}
+
+	return entry.factory, release, nil
+}
+
+// Start stops idle namespace informers until the context is cancelled, then
+// stops all of them
+func (n *NamespaceInformers) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(n.config.IdleTimeout / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				n.stop(func(informerKey, *namespaceInformers) bool { return true })
+				return
+			case <-ticker.C:
+				cutoff := time.Now().Add(-n.config.IdleTimeout)
+				n.stop(func(_ informerKey, entry *namespaceInformers) bool {
+					return entry.refs == 0 && entry.released.Before(cutoff)
+				})
+			}
+		}
+	}()
+}
+
+// clusterCaches returns the running informers of a cluster's namespaces
+func (n *NamespaceInformers) clusterCaches(clusterID string) []cache.SharedIndexInformer {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var caches []cache.SharedIndexInformer
+	for key, entry := range n.running {
+		if key.clusterID == clusterID {
+			caches = append(caches, entry.caches...)
+		}
+	}
+	return caches
+}
+
+// forget stops the informers of every namespace of a cluster. Readers
+// holding a factory keep reading what it had cached.
+func (n *NamespaceInformers) forget(clusterID string) {
+	n.stop(func(key informerKey, _ *namespaceInformers) bool { return key.clusterID == clusterID })
+}
+
+// stop stops and removes the informers matching done
+func (n *NamespaceInformers) stop(done func(informerKey, *namespaceInformers) bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for key, entry := range n.running {
+		if !done(key, entry) {
+			continue
+		}
+		close(entry.stopCh)
+		delete(n.running, key)
+		n.logger.Debug("Stopped namespace informers", "clusterID", key.clusterID, "namespace", key.namespace)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/jbetancur/dashboard/internal/pkg/providers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/flowcontrol"
 )
 
 // Manager handles multiple Kubernetes cluster connections
@@ -18,27 +19,58 @@ type Manager struct {
 	logger      *slog.Logger
 	provider    providers.Provider
 	ctx         context.Context
+	rateLimits  RateLimitConfig
+	limiters    map[string]flowcontrol.RateLimiter
+	limiterMu   sync.Mutex
+	health      map[string]*clusterHealth
+	healthMu    sync.Mutex
+	usage       map[string]time.Time
+	usageMu     sync.Mutex
 }
 
 // ClusterInfo represents summary information about a cluster
 type ClusterInfo struct {
-	ID        string    `json:"id" bson:"_id,omitempty"`
-	Kind      string    `json:"kind" bson:"kind"`
-	Name      string    `json:"name" bson:"name"`
-	APIURL    string    `json:"apiUrl" bson:"api_url"`
-	Status    string    `json:"status" bson:"status"`
-	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
-	CreatedAt time.Time `json:"created_at" bson:"created_at,omitempty"`
+	ID       string     `json:"id" bson:"_id,omitempty"`
+	Kind     string     `json:"kind" bson:"kind"`
+	Name     string     `json:"name" bson:"name"`
+	APIURL   string     `json:"apiUrl" bson:"api_url"`
+	Provider string     `json:"provider,omitempty" bson:"provider,omitempty"`
+	Status   string     `json:"status" bson:"status"`
+	Agent    *AgentInfo `json:"agent,omitempty" bson:"agent,omitempty"`
+	// Server is the version and control plane health the agent last reported
+	Server *ServerStatus `json:"server,omitempty" bson:"server,omitempty"`
+	// Credential is the token the agent authenticates with
+	Credential *AgentCredential `json:"credential,omitempty" bson:"agent_credential,omitempty"`
+	// Features overrides feature flags for this cluster
+	Features  map[string]bool `json:"features,omitempty" bson:"features,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at" bson:"updated_at"`
+	CreatedAt time.Time       `json:"created_at" bson:"created_at,omitempty"`
 }
 
 // NewManager creates a new ClusterManager
-func NewManager(ctx context.Context, logger *slog.Logger, provider providers.Provider) *Manager {
-	return &Manager{
+func NewManager(ctx context.Context, logger *slog.Logger, provider providers.Provider, rateLimits RateLimitConfig) *Manager {
+	m := &Manager{
 		connections: make(map[string]*Connection),
 		logger:      logger,
 		provider:    provider,
 		ctx:         ctx,
+		rateLimits:  rateLimits,
+		limiters:    make(map[string]flowcontrol.RateLimiter),
+		health:      make(map[string]*clusterHealth),
+		usage:       make(map[string]time.Time),
 	}
+
+	// Stop every connection's informers on shutdown
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for _, conn := range m.connections {
+			conn.Stop()
+		}
+	}()
+
+	return m
 }
 
 // Register adds a new cluster to the ClusterManager
@@ -68,9 +100,11 @@ func (m *Manager) Register(clusterName, apiURL string) error {
 func (m *Manager) GetCluster(clusterID string) (*Connection, error) {
 	m.mu.RLock()
 	cluster, exists := m.connections[clusterID]
+	connected := exists && cluster.IsConnected()
 	m.mu.RUnlock()
 
-	if exists && cluster.IsConnected() {
+	if connected {
+		m.touch(clusterID)
 		return cluster, nil
 	}
 
@@ -81,6 +115,9 @@ func (m *Manager) GetCluster(clusterID string) (*Connection, error) {
 		return nil, fmt.Errorf("failed to authenticate cluster %s: %w", clusterID, err)
 	}
 
+	// Share one token bucket across all clients built for this cluster
+	m.applyRateLimit(clusterID, restConfig)
+
 	// Create Kubernetes client
 	client, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
@@ -93,35 +130,69 @@ func (m *Manager) GetCluster(clusterID string) (*Connection, error) {
 
 	// Check again in case another goroutine initialized it
 	if existing, exists := m.connections[clusterID]; exists && existing.IsConnected() {
+		m.touch(clusterID)
 		return existing, nil
 	}
 
-	// Create or update the connection
-	if cluster == nil {
-		cluster = NewConnection(clusterID, client, restConfig)
-		m.connections[clusterID] = cluster
-	} else {
-		cluster.Client = client
-		cluster.AuthDone = true
+	// Reconnecting replaces the connection rather than changing it, since
+	// callers read the one they got without the lock. Informers built on the
+	// previous client are stopped.
+	if existing, exists := m.connections[clusterID]; exists {
+		existing.Stop()
 	}
 
-	// Initialize informers
+	cluster = NewConnection(clusterID, client, restConfig)
 	cluster.InitializeInformers()
-
-	// Monitor context for cancellation
-	go func() {
-		<-m.ctx.Done()
-		m.mu.Lock()
-		defer m.mu.Unlock()
-		if conn, exists := m.connections[clusterID]; exists {
-			conn.Stop()
-		}
-	}()
+	m.connections[clusterID] = cluster
+	m.touch(clusterID)
 
 	m.logger.Info("Cluster connection initialized", "clusterID", clusterID)
 	return cluster, nil
 }
 
+// touch records that a cluster was used, for evicting the caches of the
+// least recently used clusters. Only clusters with a connection are
+// recorded, so requests for unknown IDs leave no entries behind.
+func (m *Manager) touch(clusterID string) {
+	m.usageMu.Lock()
+	defer m.usageMu.Unlock()
+	m.usage[clusterID] = time.Now()
+}
+
+// forgetUsage drops the usage of a removed cluster
+func (m *Manager) forgetUsage(clusterID string) {
+	m.usageMu.Lock()
+	defer m.usageMu.Unlock()
+	delete(m.usage, clusterID)
+}
+
+// lastUsed returns when a cluster was last used
+func (m *Manager) lastUsed(clusterID string) time.Time {
+	m.usageMu.Lock()
+	defer m.usageMu.Unlock()
+	return m.usage[clusterID]
+}
+
+// evictCaches stops a cluster's informers and drops their caches, keeping
+// the cluster registered and connected. The connection is replaced by one
+// on the same client whose informers are not started; Synced starts them
+// again when a request reads from them.
+func (m *Manager) evictCaches(clusterID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conn, exists := m.connections[clusterID]
+	if !exists || conn.Informer == nil {
+		return
+	}
+
+	conn.Stop()
+	evicted := NewConnection(clusterID, conn.Client, conn.Config)
+	evicted.AuthDone = conn.AuthDone
+	evicted.InitializeInformers()
+	m.connections[clusterID] = evicted
+}
+
 // StopCluster stops a specific cluster connection and its informers
 func (m *Manager) StopCluster(clusterID string) error {
 	m.mu.Lock()
@@ -134,6 +205,8 @@ func (m *Manager) StopCluster(clusterID string) error {
 
 	cluster.Stop()
 	delete(m.connections, clusterID)
+	m.forgetHealth(clusterID)
+	m.forgetUsage(clusterID)
 	m.logger.Info("Cluster connection stopped", "clusterID", clusterID)
 	return nil
 }
@@ -147,6 +220,8 @@ func (m *Manager) StopAllClusters() {
 		cluster.Stop()
 		m.logger.Info("Cluster connection stopped", "clusterID", clusterID)
 		delete(m.connections, clusterID)
+		m.forgetHealth(clusterID)
+		m.forgetUsage(clusterID)
 	}
 }
 
@@ -163,15 +238,29 @@ func (m *Manager) ListClusters() []ClusterInfo {
 		}
 
 		clusters = append(clusters, ClusterInfo{
-			ID:     clusterID,
-			Name:   clusterID, // Using ID as name unless you have custom names stored
-			APIURL: apiUrl,
+			ID:       clusterID,
+			Name:     clusterID, // Using ID as name unless you have custom names stored
+			APIURL:   apiUrl,
+			Provider: m.providerName(clusterID),
+			Status:   m.Status(clusterID),
 		})
 	}
 
 	return clusters
 }
 
+// providerName returns the provider that owns a cluster when the manager is
+// backed by a provider router
+func (m *Manager) providerName(clusterID string) string {
+	router, ok := m.provider.(*providers.Router)
+	if !ok {
+		return ""
+	}
+
+	name, _ := router.Owner(clusterID)
+	return name
+}
+
 // GetConnections returns a copy of all cluster connections
 func (m *Manager) GetConnections() map[string]*Connection {
 	m.mu.RLock()
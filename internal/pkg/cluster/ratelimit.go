@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// Client-side limits used when none are configured. They are above the
+// client-go defaults (5/10) since one connection serves every dashboard user.
+const (
+	DefaultQPS   float32 = 20
+	DefaultBurst int     = 40
+)
+
+// RateLimit is a client-side QPS/burst limit for a cluster's API server
+type RateLimit struct {
+	QPS   float32 `yaml:"qps" json:"qps"`
+	Burst int     `yaml:"burst" json:"burst"`
+}
+
+// RateLimitConfig holds the global limit and per-cluster overrides
+type RateLimitConfig struct {
+	RateLimit `yaml:",inline"`
+	Clusters  map[string]RateLimit `yaml:"clusters"`
+}
+
+// ForCluster returns the effective limit for a cluster. Unset override fields
+// fall back to the global limit, then to the defaults.
+func (c RateLimitConfig) ForCluster(clusterID string) RateLimit {
+	limit := c.RateLimit
+	if override, ok := c.Clusters[clusterID]; ok {
+		if override.QPS > 0 {
+			limit.QPS = override.QPS
+		}
+		if override.Burst > 0 {
+			limit.Burst = override.Burst
+		}
+	}
+
+	if limit.QPS <= 0 {
+		limit.QPS = DefaultQPS
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = DefaultBurst
+	}
+
+	return limit
+}
+
+// rateLimiter returns the limiter shared by every client of a cluster,
+// creating it on first use so reconnects keep the same token bucket
+func (m *Manager) rateLimiter(clusterID string) (flowcontrol.RateLimiter, RateLimit) {
+	m.limiterMu.Lock()
+	defer m.limiterMu.Unlock()
+
+	limit := m.rateLimits.ForCluster(clusterID)
+	if limiter, ok := m.limiters[clusterID]; ok {
+		return limiter, limit
+	}
+
+	limiter := flowcontrol.NewTokenBucketRateLimiter(limit.QPS, limit.Burst)
+	m.limiters[clusterID] = limiter
+	return limiter, limit
+}
+
+// applyRateLimit sets the cluster's shared limiter on a rest.Config
+func (m *Manager) applyRateLimit(clusterID string, config *rest.Config) {
+	limiter, limit := m.rateLimiter(clusterID)
+
+	config.QPS = limit.QPS
+	config.Burst = limit.Burst
+	config.RateLimiter = limiter
+}
+
+// RateLimit returns the effective client-side limit for a cluster
+func (m *Manager) RateLimit(clusterID string) RateLimit {
+	return m.rateLimits.ForCluster(clusterID)
+}
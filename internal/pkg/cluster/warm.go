@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WarmConfig controls warming clusters as soon as they register, so the
+// first request doesn't wait for authentication and informer syncs
+type WarmConfig struct {
+	Disabled bool `yaml:"disabled"`
+	// Timeout bounds connecting, syncing informers and the first access review
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// informerKinds are the kinds the API reads from cluster-wide informers, by
+// kind. Pods are watched per namespace on demand, see NamespaceInformers.
+var informerKinds = map[string]func(informers.SharedInformerFactory) cache.SharedIndexInformer{
+	"Namespace": func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Core().V1().Namespaces().Informer()
+	},
+}
+
+// Warm connects to a cluster and starts the informers of the given kinds,
+// waiting for their first sync. With no kinds, every informer the API reads
+// from is started.
+func (m *Manager) Warm(ctx context.Context, clusterID string, kinds []string) error {
+	_, err := m.Synced(ctx, clusterID, kinds)
+	return err
+}
+
+// Synced returns a cluster's connection once the informers of the given
+// kinds are running and synced, starting them if they aren't, such as after
+// their caches were evicted. Readers of the connection's informer factory
+// go through here.
+func (m *Manager) Synced(ctx context.Context, clusterID string, kinds []string) (*Connection, error) {
+	conn, err := m.GetCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	conn.InitializeInformers()
+	var synced []cache.InformerSynced
+	for kind, informer := range informerKinds {
+		if len(kinds) == 0 || slices.Contains(kinds, kind) {
+			started := informer(conn.Informer)
+			if !slices.Contains(conn.caches, started) {
+				conn.caches = append(conn.caches, started)
+			}
+			synced = append(synced, started.HasSynced)
+		}
+	}
+	// Start skips informers already running, so this also starts the ones
+	// just added on a connection whose informers were started before
+	conn.Informer.Start(conn.StopCh)
+	conn.Running = true
+	m.mu.Unlock()
+
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return nil, fmt.Errorf("timed out waiting for informers of cluster %s to sync", clusterID)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+)
+
+// Dispatcher sends commands to agents and waits for their results
+type Dispatcher struct {
+	queue   messagingtypes.MessageQueue
+	pending map[string]chan Result
+	mu      sync.Mutex
+	logger  *slog.Logger
+}
+
+// NewDispatcher creates a new dispatcher
+func NewDispatcher(queue messagingtypes.MessageQueue, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		queue:   queue,
+		pending: make(map[string]chan Result),
+		logger:  logger,
+	}
+}
+
+// Start subscribes to command results
+func (d *Dispatcher) Start() {
+	d.queue.Subscribe(TopicResult, d.handleResult)
+}
+
+// Send publishes a command for a cluster and waits until its result arrives
+// or the context is done
+func (d *Dispatcher) Send(ctx context.Context, clusterID, commandType string, args map[string]string) (*Result, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate command ID: %w", err)
+	}
+
+	command := Command{
+		ID:        id,
+		ClusterID: clusterID,
+		Type:      commandType,
+		Args:      args,
+		SentAt:    time.Now(),
+	}
+
+	data, err := json.Marshal(command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	// Register before publishing so a fast answer is not missed
+	results := make(chan Result, 1)
+	d.mu.Lock()
+	d.pending[id] = results
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		delete(d.pending, id)
+		d.mu.Unlock()
+	}()
+
+	if err := d.queue.Publish(TopicCommand, data); err != nil {
+		return nil, fmt.Errorf("failed to send command: %w", err)
+	}
+
+	d.logger.Debug("Sent agent command", "id", id, "clusterID", clusterID, "type", commandType)
+
+	select {
+	case result := <-results:
+		return &result, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %s command %s", ErrTimeout, commandType, id)
+	}
+}
+
+// handleResult hands a result to the command waiting for it. Results for
+// commands that already gave up are dropped.
+func (d *Dispatcher) handleResult(message []byte) error {
+	var result Result
+	if err := json.Unmarshal(message, &result); err != nil {
+		d.logger.Error("Failed to unmarshal command result", "error", err)
+		return err
+	}
+
+	d.mu.Lock()
+	results, ok := d.pending[result.ID]
+	d.mu.Unlock()
+
+	if !ok {
+		d.logger.Debug("Dropping result for unknown command", "id", result.ID, "type", result.Type)
+		return nil
+	}
+
+	select {
+	case results <- result:
+	default:
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+)
+
+// Handler performs a command and returns data to send back as the result
+type Handler func(ctx context.Context, command Command) (any, error)
+
+// Executor runs commands on the agent side and publishes their results
+type Executor struct {
+	publisher messagingtypes.Publisher
+	handlers  map[string]Handler
+	mu        sync.RWMutex
+	logger    *slog.Logger
+}
+
+// NewExecutor creates a new executor
+func NewExecutor(publisher messagingtypes.Publisher, logger *slog.Logger) *Executor {
+	return &Executor{
+		publisher: publisher,
+		handlers:  make(map[string]Handler),
+		logger:    logger,
+	}
+}
+
+// Handle registers the handler for a command type
+func (e *Executor) Handle(commandType string, handler Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.handlers[commandType] = handler
+}
+
+// Start subscribes to commands. Each command runs in its own goroutine with
+// the given context so the messaging call that delivered it returns at once.
+func (e *Executor) Start(ctx context.Context, subscriber messagingtypes.Subscriber) {
+	subscriber.Subscribe(TopicCommand, func(message []byte) error {
+		var command Command
+		if err := json.Unmarshal(message, &command); err != nil {
+			e.logger.Error("Failed to unmarshal command", "error", err)
+			return err
+		}
+
+		go e.run(ctx, command)
+		return nil
+	})
+}
+
+// run performs a command and publishes its result
+func (e *Executor) run(ctx context.Context, command Command) {
+	result := Result{
+		ID:        command.ID,
+		ClusterID: command.ClusterID,
+		Type:      command.Type,
+	}
+
+	e.mu.RLock()
+	handler, ok := e.handlers[command.Type]
+	e.mu.RUnlock()
+
+	if !ok {
+		result.Error = fmt.Sprintf("unsupported command %q", command.Type)
+	} else if data, err := handler(ctx, command); err != nil {
+		result.Error = err.Error()
+	} else if result.Data, err = json.Marshal(data); err != nil {
+		result.Error = fmt.Sprintf("failed to marshal result: %v", err)
+	}
+
+	result.CompletedAt = time.Now()
+
+	e.logger.Info("Ran agent command",
+		"id", command.ID,
+		"clusterID", command.ClusterID,
+		"type", command.Type,
+		"error", result.Error)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		e.logger.Error("Failed to marshal command result", "id", command.ID, "error", err)
+		return
+	}
+
+	if err := e.publisher.Publish(TopicResult, data); err != nil {
+		e.logger.Error("Failed to publish command result", "id", command.ID, "error", err)
+	}
+}
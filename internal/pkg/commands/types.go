@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Topics carrying commands to agents and their results back to the API
+const (
+	TopicCommand = "agent_command"
+	TopicResult  = "agent_command_result"
+)
+
+// Command types understood by the agent
+const (
+	TypeResync        = "resync"
+	TypeSupportBundle = "support_bundle"
+	TypeList          = "list"
+	// TypeDiagnosticBundle collects a namespace or workload bundle as a
+	// tar.gz, with the namespace, kind, name and tailLines args
+	TypeDiagnosticBundle = "diagnostic_bundle"
+	// TypeRotateCredentials hands the agent a new token in the "token" arg
+	TypeRotateCredentials = "rotate_credentials"
+)
+
+// ErrTimeout is returned when an agent does not answer a command in time
+var ErrTimeout = errors.New("timed out waiting for agent")
+
+// Command asks the agent serving a cluster to perform a cluster-local action
+type Command struct {
+	ID        string            `json:"id"`
+	ClusterID string            `json:"clusterID"`
+	Type      string            `json:"type"`
+	Args      map[string]string `json:"args,omitempty"`
+	SentAt    time.Time         `json:"sentAt"`
+}
+
+// Result is an agent's answer to a command, correlated by the command ID
+type Result struct {
+	ID          string          `json:"id"`
+	ClusterID   string          `json:"clusterID"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	CompletedAt time.Time       `json:"completedAt"`
+}
+
+// newID returns a random command ID
+func newID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -6,20 +6,61 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
+	"github.com/jbetancur/dashboard/internal/pkg/alerting"
 	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/bulk"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/compliance"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/configmaps"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/cost"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/files"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/images"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/metrics"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/namespaces"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/rightsizing"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/vulnerabilities"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
 	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/eventqueue"
+	"github.com/jbetancur/dashboard/internal/pkg/features"
+	"github.com/jbetancur/dashboard/internal/pkg/listcache"
+	"github.com/jbetancur/dashboard/internal/pkg/logging"
 	"github.com/jbetancur/dashboard/internal/pkg/messaging"
 	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	"github.com/jbetancur/dashboard/internal/pkg/objectstore"
+	"github.com/jbetancur/dashboard/internal/pkg/problems"
+	"github.com/jbetancur/dashboard/internal/pkg/prometheus"
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	execprovider "github.com/jbetancur/dashboard/internal/pkg/providers/exec"
+	staticprovider "github.com/jbetancur/dashboard/internal/pkg/providers/static"
+	"github.com/jbetancur/dashboard/internal/pkg/providers/uploaded"
+	"github.com/jbetancur/dashboard/internal/pkg/recording"
+	"github.com/jbetancur/dashboard/internal/pkg/reports"
 	"github.com/jbetancur/dashboard/internal/pkg/store"
+	"github.com/jbetancur/dashboard/internal/pkg/tunnel"
+	"github.com/jbetancur/dashboard/internal/pkg/webhooks"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 )
 
+// ProviderConfig selects a cluster provider. Type names a built-in provider
+// (defaulting to Name), Command runs an exec provider and Path loads a Go plugin.
+// Clusters lists clusters inline for the static provider.
 type ProviderConfig struct {
-	Name   string            `yaml:"name"`
-	Path   string            `yaml:"path"`
-	Config map[string]string `yaml:"config"`
+	Name     string                       `yaml:"name"`
+	Type     string                       `yaml:"type"`
+	Path     string                       `yaml:"path"`
+	Command  string                       `yaml:"command"`
+	Args     []string                     `yaml:"args"`
+	Env      map[string]string            `yaml:"env"`
+	Config   map[string]string            `yaml:"config"`
+	Clusters []staticprovider.ClusterSpec `yaml:"clusters"`
+}
+
+// MetricsConfig configures the metrics subsystem
+type MetricsConfig struct {
+	Sampling metrics.SamplingConfig `yaml:"sampling"`
 }
 
 type AuthenticatorConfig struct {
@@ -29,8 +70,38 @@ type AuthenticatorConfig struct {
 }
 
 type AppConfig struct {
-	Providers      []ProviderConfig      `yaml:"providers"`
-	Authenticators []AuthenticatorConfig `yaml:"authenticators"`
+	Logging         logging.Config            `yaml:"logging"`
+	Providers       []ProviderConfig          `yaml:"providers"`
+	Authenticators  []AuthenticatorConfig     `yaml:"authenticators"`
+	Authorization   auth.AuthorizationConfig  `yaml:"authorization"`
+	Discovery       DiscoveryConfig           `yaml:"discovery"`
+	RateLimits      cluster.RateLimitConfig   `yaml:"rateLimits"`
+	Health          cluster.HealthConfig      `yaml:"health"`
+	Warm            cluster.WarmConfig        `yaml:"warm"`
+	Informers       cluster.InformerConfig    `yaml:"informers"`
+	CacheBudget     cluster.CacheBudgetConfig `yaml:"cacheBudget"`
+	Metrics         MetricsConfig             `yaml:"metrics"`
+	Prometheus      prometheus.Config         `yaml:"prometheus"`
+	Cost            cost.PricingConfig        `yaml:"cost"`
+	Rightsizing     rightsizing.Policy        `yaml:"rightsizing"`
+	Problems        problems.Config           `yaml:"problems"`
+	Alerting        alerting.Config           `yaml:"alerting"`
+	Reports         reports.Config            `yaml:"reports"`
+	Webhooks        webhooks.Config           `yaml:"webhooks"`
+	Images          images.Config             `yaml:"images"`
+	Vulnerabilities vulnerabilities.Config    `yaml:"vulnerabilities"`
+	Compliance      compliance.Config         `yaml:"compliance"`
+	Registration    uploaded.Config           `yaml:"registration"`
+	Tunnel          tunnel.Config             `yaml:"tunnel"`
+	Namespaces      namespaces.Config         `yaml:"namespaces"`
+	Files           files.Config              `yaml:"files"`
+	Bulk            bulk.Config               `yaml:"bulk"`
+	Recording       recording.Config          `yaml:"recording"`
+	Agents          cluster.AgentConfig       `yaml:"agents"`
+	Features        features.Config           `yaml:"features"`
+	ListCache       listcache.Config          `yaml:"listCache"`
+	Events          eventqueue.Config         `yaml:"events"`
+	ObjectStore     objectstore.Config        `yaml:"objectStore"`
 }
 
 func LoadConfig(filePath string) (*AppConfig, error) {
@@ -51,6 +122,28 @@ func LoadConfig(filePath string) (*AppConfig, error) {
 	return &config, nil
 }
 
+// LoadProvider creates the provider described by a provider config
+func LoadProvider(providerConfig ProviderConfig, logger *slog.Logger) (providers.Provider, error) {
+	switch {
+	case providerConfig.Command != "":
+		return execprovider.NewProvider(providerConfig.Command, providerConfig.Args, providerConfig.Env,
+			providerConfig.Config, logger)
+	case providerConfig.Path != "":
+		return providers.LoadPlugin(providerConfig.Path, providerConfig.Config, logger)
+	}
+
+	providerType := providerConfig.Type
+	if providerType == "" {
+		providerType = providerConfig.Name
+	}
+
+	if providerType == "static" && len(providerConfig.Clusters) > 0 {
+		return staticprovider.NewStaticProvider(providerConfig.Clusters, providerConfig.Config, logger)
+	}
+
+	return providers.New(providerType, providerConfig.Config, logger)
+}
+
 func Store(ctx context.Context, logger *slog.Logger) (store.Repository, error) {
 	store, err := store.NewStore(ctx, "mongodb://localhost:27017", "k8s-starship", logger)
 	if err != nil {
@@ -61,12 +154,13 @@ func Store(ctx context.Context, logger *slog.Logger) (store.Repository, error) {
 	return store, nil
 }
 
-func configMessageClient(logger *slog.Logger) (messagingtypes.MessageQueue, error) {
+func configMessageClient(authenticate func(ctx context.Context, token string) error, logger *slog.Logger) (messagingtypes.MessageQueue, error) {
 	// Initialize the messaging client for bidirectional communication
 	messagingConfig := messaging.Config{
 		Type:          messaging.GRPCProvider,
 		ServerAddress: ":50053", // REST API's server address (for receiving)
 		ClientAddress: ":50052", // Agent's server address (for sending)
+		Authenticate:  authenticate,
 	}
 
 	messagingClient, err := messaging.NewClient(messagingConfig, logger)
@@ -77,8 +171,10 @@ func configMessageClient(logger *slog.Logger) (messagingtypes.MessageQueue, erro
 	return messagingClient, nil
 }
 
-func StartMessageClients(ctx context.Context, logger *slog.Logger) (messagingtypes.MessageQueue, error) {
-	messagingClient, err := configMessageClient(logger)
+// StartMessageClients starts the messaging link to agents. When authenticate
+// is set, agent events must carry a token it accepts.
+func StartMessageClients(ctx context.Context, authenticate func(ctx context.Context, token string) error, logger *slog.Logger) (messagingtypes.MessageQueue, error) {
+	messagingClient, err := configMessageClient(authenticate, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize messaging client: %w", err)
 	}
@@ -101,29 +197,60 @@ func StartMessageClients(ctx context.Context, logger *slog.Logger) (messagingtyp
 // SetupSubscriptions configures all event subscriptions
 func SetupSubscriptions(
 	ctx context.Context,
-	messagingClient messagingtypes.MessageQueue,
+	messagingClient messagingtypes.Subscriber,
 	store store.Repository,
+	lists *listcache.Cache,
 	clusterManager *cluster.Manager,
+	warm cluster.WarmConfig,
+	authorizer *auth.K8sAuthorizer,
 	logger *slog.Logger,
 ) {
 	// Subscribe to cluster registration events
 	messagingClient.Subscribe("cluster_registered", func(message []byte) error {
-		return handleClusterRegistration(ctx, message, clusterManager, store, logger)
+		if err := handleClusterRegistration(ctx, message, clusterManager, store, logger); err != nil {
+			return err
+		}
+		warmCluster(ctx, message, warm, clusterManager, authorizer, logger)
+		return nil
+	})
+
+	// Subscribe to cluster removal events
+	messagingClient.Subscribe("cluster_unregistered", func(message []byte) error {
+		if err := handleClusterUnregistration(ctx, message, clusterManager, store, logger); err != nil {
+			return err
+		}
+		invalidateClusterLists(message, lists)
+		return nil
+	})
+
+	// Subscribe to the version and control plane health agents report
+	messagingClient.Subscribe("cluster_server_status", func(message []byte) error {
+		return handleClusterServerStatus(ctx, message, store, logger)
+	})
+
+	// Subscribe to agent heartbeats
+	messagingClient.Subscribe("agent_heartbeat", func(message []byte) error {
+		return handleAgentHeartbeat(ctx, message, store, logger)
 	})
 
 	// Subscribe to pod events
 	messagingClient.Subscribe("pod_added", func(message []byte) error {
-		return handlePodEvent(ctx, message, store, logger)
+		return handlePodEvent(ctx, message, store, lists, logger)
 	})
 
 	// Subscribe to namespace events
 	messagingClient.Subscribe("namespace_added", func(message []byte) error {
-		return handleNamespaceEvent(ctx, message, store, logger)
+		return handleNamespaceEvent(ctx, message, store, lists, logger)
 	})
 
 	// Subscribe to config map events
 	messagingClient.Subscribe("config_map_added", func(message []byte) error {
-		return handleConfigMapEvent(ctx, message, store, logger)
+		return handleConfigMapEvent(ctx, message, store, lists, logger)
+	})
+
+	// Updates are stored too so config map revisions capture edits made outside the dashboard
+	messagingClient.Subscribe("config_map_updated", func(message []byte) error {
+		return handleConfigMapEvent(ctx, message, store, lists, logger)
 	})
 
 	// Log successful subscription setup
@@ -153,9 +280,11 @@ func handleClusterRegistration(
 	// var payload assets.ResourcePayload[corev1.ClusterInfo]
 	// Create a ClusterInfo object to store in the database
 	clusterInfo := cluster.ClusterInfo{
-		Kind:   "Cluster",
-		Name:   payload.ClusterName,
-		APIURL: payload.APIURL,
+		Kind:     "Cluster",
+		Name:     payload.ClusterName,
+		APIURL:   payload.APIURL,
+		Provider: payload.Provider,
+		Agent:    payload.Agent,
 	}
 
 	// Save the cluster to the database
@@ -164,17 +293,262 @@ func handleClusterRegistration(
 		return err
 	}
 
-	logger.Info("Registered cluster from event",
-		"name", payload.ClusterName,
-		"api_url", payload.APIURL)
+	attrs := []any{"name", payload.ClusterName, "api_url", payload.APIURL}
+	if payload.Agent != nil {
+		attrs = append(attrs,
+			"agent_version", payload.Agent.Version,
+			"server_version", payload.Agent.ServerVersion,
+			"kinds", payload.Agent.Kinds)
+	}
+
+	logger.Info("Registered cluster from event", attrs...)
+	return nil
+}
+
+// warmCluster connects to a newly registered cluster in the background,
+// starting the informers for the kinds its agent watches and opening the
+// authorizer's connection, so the first user request doesn't pay for it
+func warmCluster(
+	ctx context.Context,
+	message []byte,
+	warm cluster.WarmConfig,
+	clusterManager *cluster.Manager,
+	authorizer *auth.K8sAuthorizer,
+	logger *slog.Logger,
+) {
+	if warm.Disabled {
+		return
+	}
+
+	var payload cluster.ConnectionPayload
+	if err := json.Unmarshal(message, &payload); err != nil {
+		return
+	}
+
+	var kinds []string
+	if payload.Agent != nil {
+		kinds = payload.Agent.Kinds
+	}
+
+	timeout := warm.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		start := time.Now()
+		if err := clusterManager.Warm(ctx, payload.ClusterName, kinds); err != nil {
+			logger.Warn("Failed to warm cluster connection", "name", payload.ClusterName, "error", err)
+			return
+		}
+		if err := authorizer.Warm(ctx, payload.ClusterName); err != nil {
+			logger.Warn("Failed to warm authorizer", "name", payload.ClusterName, "error", err)
+			return
+		}
+
+		logger.Info("Warmed cluster", "name", payload.ClusterName, "kinds", kinds, "duration", time.Since(start))
+	}()
+}
+
+// handleClusterServerStatus stores the server status an agent reported
+func handleClusterServerStatus(
+	ctx context.Context,
+	message []byte,
+	store store.Repository,
+	logger *slog.Logger,
+) error {
+	var payload cluster.ServerStatusPayload
+	if err := json.Unmarshal(message, &payload); err != nil {
+		logger.Error("Failed to unmarshal cluster server status event", "error", err)
+		return err
+	}
+
+	if err := store.UpdateClusterServerStatus(ctx, payload.ClusterName, payload.Status); err != nil {
+		logger.Error("Failed to store cluster server status", "name", payload.ClusterName, "error", err)
+		return err
+	}
+
+	logger.Debug("Updated cluster server status", "name", payload.ClusterName)
+	return nil
+}
+
+// handleAgentHeartbeat records that the agent serving a cluster is alive
+func handleAgentHeartbeat(
+	ctx context.Context,
+	message []byte,
+	store store.Repository,
+	logger *slog.Logger,
+) error {
+	var payload cluster.HeartbeatPayload
+	if err := json.Unmarshal(message, &payload); err != nil {
+		logger.Error("Failed to unmarshal agent heartbeat event", "error", err)
+		return err
+	}
+
+	// Use the time it arrived so agent clock skew doesn't mark it offline
+	if err := store.UpdateAgentHeartbeat(ctx, payload.ClusterName, payload.Version, payload.Degraded, time.Now()); err != nil {
+		logger.Error("Failed to store agent heartbeat", "name", payload.ClusterName, "error", err)
+		return err
+	}
+
+	if payload.Degraded {
+		logger.Warn("Agent is degraded, status updates are sampled", "name", payload.ClusterName)
+	}
+
+	return nil
+}
+
+// invalidateClusterLists drops the cached lists of a removed cluster
+func invalidateClusterLists(message []byte, lists *listcache.Cache) {
+	var payload cluster.ConnectionPayload
+	if json.Unmarshal(message, &payload) == nil {
+		lists.InvalidateCluster(payload.ClusterName)
+	}
+}
+
+// handleClusterUnregistration processes cluster removal events
+func handleClusterUnregistration(
+	ctx context.Context,
+	message []byte,
+	clusterManager *cluster.Manager,
+	store store.Repository,
+	logger *slog.Logger,
+) error {
+	var payload cluster.ConnectionPayload
+	if err := json.Unmarshal(message, &payload); err != nil {
+		logger.Error("Failed to unmarshal cluster removal event", "error", err)
+		return err
+	}
+
+	// The cluster may never have been connected, so a missing connection is fine
+	if err := clusterManager.StopCluster(payload.ClusterName); err != nil {
+		logger.Debug("Cluster was not connected", "name", payload.ClusterName, "error", err)
+	}
+
+	if err := store.DeleteCluster(ctx, payload.ClusterName); err != nil {
+		logger.Error("Failed to remove cluster", "error", err)
+		return err
+	}
+
+	logger.Info("Unregistered cluster from event", "name", payload.ClusterName)
 	return nil
 }
 
+// WatchProviderClusters keeps the cluster manager and store in sync with
+// providers that report fleet changes, and republishes each change so agents
+// and other consumers see it too. Providers without watch support are skipped.
+func WatchProviderClusters(
+	ctx context.Context,
+	provider providers.Provider,
+	messagingClient messagingtypes.MessageQueue,
+	store store.Repository,
+	clusterManager *cluster.Manager,
+	logger *slog.Logger,
+) error {
+	watcher, ok := provider.(providers.WatchingProvider)
+	if !ok {
+		return nil
+	}
+
+	return watcher.Watch(ctx, func(event providers.ClusterEvent) {
+		data, err := json.Marshal(cluster.ConnectionPayload{
+			ClusterName: event.Cluster.ID,
+			APIURL:      event.Cluster.APIURL,
+			Provider:    event.Cluster.Provider,
+		})
+		if err != nil {
+			logger.Error("Failed to marshal fleet event", "error", err)
+			return
+		}
+
+		topic := "cluster_registered"
+		handle := handleClusterRegistration
+		if event.Type == providers.ClusterRemoved {
+			topic = "cluster_unregistered"
+			handle = handleClusterUnregistration
+		}
+
+		if err := handle(ctx, data, clusterManager, store, logger); err != nil {
+			return
+		}
+
+		if err := messagingClient.Publish(topic, data); err != nil {
+			logger.Warn("Failed to publish fleet event", "topic", topic, "cluster", event.Cluster.ID, "error", err)
+		}
+	})
+}
+
+// StartHealthMonitor starts probing cluster connections, persisting each
+// status change and publishing it as a cluster_status event
+func StartHealthMonitor(
+	ctx context.Context,
+	healthConfig cluster.HealthConfig,
+	messagingClient messagingtypes.MessageQueue,
+	store store.Repository,
+	clusterManager *cluster.Manager,
+	logger *slog.Logger,
+) {
+	clusterManager.StartHealthMonitor(healthConfig, func(event cluster.HealthEvent) {
+		if err := store.UpdateClusterStatus(ctx, event.ClusterID, event.Status); err != nil {
+			logger.Error("Failed to store cluster status", "clusterID", event.ClusterID, "error", err)
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			logger.Error("Failed to marshal cluster status event", "error", err)
+			return
+		}
+
+		if err := messagingClient.Publish("cluster_status", data); err != nil {
+			logger.Warn("Failed to publish cluster status", "clusterID", event.ClusterID, "error", err)
+		}
+	})
+}
+
+// StartProblemDetection starts evaluating problem rules against connected
+// clusters, publishing problem_detected and problem_resolved events and
+// handing them to the alert dispatcher
+func StartProblemDetection(
+	ctx context.Context,
+	problemsConfig problems.Config,
+	dispatcher *alerting.Dispatcher,
+	messagingClient messagingtypes.MessageQueue,
+	store store.Repository,
+	clusterManager *cluster.Manager,
+	logger *slog.Logger,
+) *problems.Engine {
+	engine := problems.NewEngine(clusterManager, store, problemsConfig, logger)
+	engine.Start(ctx, func(event problems.Event) {
+		dispatcher.Notify(event)
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			logger.Error("Failed to marshal problem event", "error", err)
+			return
+		}
+
+		topic := "problem_detected"
+		if event.Type == problems.EventResolved {
+			topic = "problem_resolved"
+		}
+
+		if err := messagingClient.Publish(topic, data); err != nil {
+			logger.Warn("Failed to publish problem event", "topic", topic, "clusterID", event.Problem.ClusterID, "error", err)
+		}
+	})
+
+	return engine
+}
+
 // handlePodEvent processes pod events
 func handlePodEvent(
 	ctx context.Context,
 	message []byte,
 	store store.Repository,
+	lists *listcache.Cache,
 	logger *slog.Logger,
 ) error {
 	var payload assets.ResourcePayload[corev1.Pod]
@@ -187,6 +561,7 @@ func handlePodEvent(
 		logger.Error("Failed to store pod", "error", err)
 		return err
 	}
+	lists.Invalidate(payload.ClusterID, "Pod", payload.Resource.Namespace)
 
 	logger.Debug("Stored pod from event",
 		"name", payload.Resource.Name,
@@ -200,6 +575,7 @@ func handleNamespaceEvent(
 	ctx context.Context,
 	message []byte,
 	store store.Repository,
+	lists *listcache.Cache,
 	logger *slog.Logger,
 ) error {
 	var payload assets.ResourcePayload[corev1.Namespace]
@@ -212,6 +588,7 @@ func handleNamespaceEvent(
 		logger.Error("Failed to store namespace", "error", err)
 		return err
 	}
+	lists.Invalidate(payload.ClusterID, "Namespace", "")
 
 	logger.Info("Stored namespace from event",
 		"name", payload.Resource.Name,
@@ -224,6 +601,7 @@ func handleConfigMapEvent(
 	ctx context.Context,
 	message []byte,
 	store store.Repository,
+	lists *listcache.Cache,
 	logger *slog.Logger,
 ) error {
 	var payload assets.ResourcePayload[corev1.ConfigMap]
@@ -236,6 +614,11 @@ func handleConfigMapEvent(
 		logger.Error("Failed to store config map", "error", err)
 		return err
 	}
+	lists.Invalidate(payload.ClusterID, "ConfigMap", payload.Resource.Namespace)
+
+	if _, err := configmaps.RecordRevision(ctx, store, payload.ClusterID, &payload.Resource, ""); err != nil {
+		logger.Warn("Failed to record config map revision", "name", payload.Resource.Name, "error", err)
+	}
 
 	logger.Info("Stored config map from event",
 		"name", payload.Resource.Name,
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+// ClusterStatusRemoved marks a cluster that is no longer reported by its provider
+const ClusterStatusRemoved = "removed"
+
+// DiscoveryConfig controls periodic provider discovery
+type DiscoveryConfig struct {
+	Interval time.Duration `yaml:"interval"`
+}
+
+// StartDiscovery runs provider discovery immediately and then on every
+// interval until the context is cancelled. New clusters are registered and
+// removed ones are disconnected and marked, with both published as events.
+func StartDiscovery(
+	ctx context.Context,
+	discoveryConfig DiscoveryConfig,
+	provider providers.Provider,
+	messagingClient messagingtypes.MessageQueue,
+	store store.Repository,
+	clusterManager *cluster.Manager,
+	logger *slog.Logger,
+) {
+	interval := discoveryConfig.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	// Clusters discovered in earlier runs, so ones that disappeared while we
+	// were down still get marked as removed
+	known := make(map[string]cluster.ClusterInfo)
+
+	var stored []cluster.ClusterInfo
+	if err := store.ListClusters(ctx, &stored); err != nil {
+		logger.Warn("Failed to load known clusters", "error", err)
+	}
+
+	for _, clusterInfo := range stored {
+		if clusterInfo.Provider != "" && clusterInfo.Status != ClusterStatusRemoved {
+			known[clusterInfo.Name] = clusterInfo
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			reconcileClusters(ctx, provider, known, messagingClient, store, clusterManager, logger)
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	logger.Info("Cluster discovery started", "interval", interval)
+}
+
+// reconcileClusters compares one discovery pass against the known clusters
+func reconcileClusters(
+	ctx context.Context,
+	provider providers.Provider,
+	known map[string]cluster.ClusterInfo,
+	messagingClient messagingtypes.MessageQueue,
+	store store.Repository,
+	clusterManager *cluster.Manager,
+	logger *slog.Logger,
+) {
+	// When only some providers failed, the clusters they own are kept as they
+	// are; a failed provider tells us nothing about removals
+	discovered, err := provider.DiscoverClusters()
+	var partial *providers.DiscoveryError
+	switch {
+	case errors.As(err, &partial):
+		logger.Warn("Cluster discovery failed for some providers", "error", err)
+	case err != nil:
+		logger.Error("Cluster discovery failed", "error", err)
+		return
+	}
+
+	seen := make(map[string]bool, len(discovered))
+	for _, clusterConfig := range discovered {
+		seen[clusterConfig.ID] = true
+
+		if _, exists := known[clusterConfig.ID]; exists {
+			continue
+		}
+
+		data, err := json.Marshal(cluster.ConnectionPayload{
+			ClusterName: clusterConfig.ID,
+			APIURL:      clusterConfig.APIURL,
+			Provider:    clusterConfig.Provider,
+		})
+		if err != nil {
+			logger.Error("Failed to marshal discovered cluster", "error", err)
+			continue
+		}
+
+		if err := handleClusterRegistration(ctx, data, clusterManager, store, logger); err != nil {
+			continue
+		}
+
+		known[clusterConfig.ID] = cluster.ClusterInfo{
+			Name:     clusterConfig.ID,
+			APIURL:   clusterConfig.APIURL,
+			Provider: clusterConfig.Provider,
+		}
+
+		if err := messagingClient.Publish("cluster_registered", data); err != nil {
+			logger.Warn("Failed to publish discovered cluster", "cluster", clusterConfig.ID, "error", err)
+		}
+	}
+
+	for name, clusterInfo := range known {
+		if seen[name] {
+			continue
+		}
+		if partial != nil {
+			if _, failed := partial.Failed[clusterInfo.Provider]; failed {
+				continue
+			}
+		}
+
+		if err := clusterManager.StopCluster(name); err != nil {
+			logger.Debug("Cluster was not connected", "name", name, "error", err)
+		}
+
+		clusterInfo.Kind = "Cluster"
+		clusterInfo.Status = ClusterStatusRemoved
+		if err := store.SaveCluster(ctx, &clusterInfo); err != nil {
+			logger.Error("Failed to mark cluster removed", "name", name, "error", err)
+			continue
+		}
+
+		delete(known, name)
+		logger.Info("Cluster no longer discovered", "name", name)
+
+		data, err := json.Marshal(cluster.ConnectionPayload{
+			ClusterName: name,
+			APIURL:      clusterInfo.APIURL,
+			Provider:    clusterInfo.Provider,
+		})
+		if err != nil {
+			logger.Error("Failed to marshal removed cluster", "error", err)
+			continue
+		}
+
+		if err := messagingClient.Publish("cluster_unregistered", data); err != nil {
+			logger.Warn("Failed to publish removed cluster", "cluster", name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+)
+
+// none is shown for empty values, as kubectl does
+const none = "<none>"
+
+// Describe renders an object of a supported kind in the layout of kubectl
+// describe, followed by its Events
+func Describe(ctx context.Context, client kubernetes.Interface, kind, namespace, name string) (string, error) {
+	obj, err := assets.GetObject(ctx, client, kind, namespace, name)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	w := newWriter(&b)
+
+	if err := describeObject(w, obj); err != nil {
+		return "", err
+	}
+
+	meta, err := apimeta.Accessor(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to read object metadata: %w", err)
+	}
+
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", string(meta.GetUID())).String(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list events: %w", err)
+	}
+	describeEvents(w, events.Items)
+
+	if err := w.flush(); err != nil {
+		return "", fmt.Errorf("failed to render description: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// describeObject dispatches to the kind's renderer
+func describeObject(w *writer, obj runtime.Object) error {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		describePod(w, o)
+	case *corev1.Service:
+		describeService(w, o)
+	case *corev1.ConfigMap:
+		describeConfigMap(w, o)
+	case *corev1.PersistentVolumeClaim:
+		describePersistentVolumeClaim(w, o)
+	default:
+		if !describeWorkload(w, obj) {
+			return fmt.Errorf("describe is not supported for %T", obj)
+		}
+	}
+	return nil
+}
+
+// writer writes aligned, indented "Label:\tvalue" lines
+type writer struct {
+	out *tabwriter.Writer
+}
+
+func newWriter(b *strings.Builder) *writer {
+	return &writer{out: tabwriter.NewWriter(b, 0, 8, 2, ' ', 0)}
+}
+
+// line writes a line at an indentation level
+func (w *writer) line(level int, format string, args ...any) {
+	fmt.Fprintf(w.out, strings.Repeat("  ", level)+format+"\n", args...)
+}
+
+func (w *writer) flush() error {
+	return w.out.Flush()
+}
+
+// describeMeta writes the name, namespace, labels and annotations
+func describeMeta(w *writer, meta metav1.ObjectMeta) {
+	w.line(0, "Name:\t%s", meta.Name)
+	w.line(0, "Namespace:\t%s", meta.Namespace)
+	describeMap(w, "Labels", meta.Labels, "=")
+	describeMap(w, "Annotations", meta.Annotations, ": ")
+}
+
+// describeCreated writes when an object was created
+func describeCreated(w *writer, meta metav1.ObjectMeta) {
+	w.line(0, "CreationTimestamp:\t%s", meta.CreationTimestamp.Format(time.RFC1123Z))
+}
+
+// describeControlledBy writes the controller owning an object
+func describeControlledBy(w *writer, meta metav1.ObjectMeta) {
+	if ref := metav1.GetControllerOfNoCopy(&meta); ref != nil {
+		w.line(0, "Controlled By:\t%s/%s", ref.Kind, ref.Name)
+	}
+}
+
+// describeMap writes sorted key/value pairs, the first on the label's line
+func describeMap(w *writer, label string, values map[string]string, separator string) {
+	if len(values) == 0 {
+		w.line(0, "%s:\t%s", label, none)
+		return
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		prefix := ""
+		if i == 0 {
+			prefix = label + ":"
+		}
+		w.line(0, "%s\t%s%s%s", prefix, key, separator, values[key])
+	}
+}
+
+// describeSelector writes a label selector
+func describeSelector(w *writer, selector *metav1.LabelSelector) {
+	if selector == nil {
+		w.line(0, "Selector:\t%s", none)
+		return
+	}
+	w.line(0, "Selector:\t%s", metav1.FormatLabelSelector(selector))
+}
+
+// condition is the common shape of status conditions
+type condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// describeConditions writes status conditions as a table
+func describeConditions(w *writer, conditions []condition) {
+	if len(conditions) == 0 {
+		return
+	}
+
+	w.line(0, "Conditions:")
+	w.line(1, "Type\tStatus\tReason\tMessage")
+	w.line(1, "----\t------\t------\t-------")
+	for _, c := range conditions {
+		w.line(1, "%s\t%s\t%s\t%s", c.Type, c.Status, orNone(c.Reason), c.Message)
+	}
+}
+
+// describeEvents writes Events oldest first, like kubectl
+func describeEvents(w *writer, events []corev1.Event) {
+	if len(events) == 0 {
+		w.line(0, "Events:\t%s", none)
+		return
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return lastSeen(&events[i]).Before(lastSeen(&events[j]))
+	})
+
+	w.line(0, "Events:")
+	w.line(1, "Type\tReason\tAge\tFrom\tMessage")
+	w.line(1, "----\t------\t----\t----\t-------")
+	for i := range events {
+		event := &events[i]
+
+		age := duration.HumanDuration(time.Since(lastSeen(event)))
+		if event.Count > 1 {
+			age = fmt.Sprintf("%s (x%d)", age, event.Count)
+		}
+
+		from := event.Source.Component
+		if from == "" {
+			from = event.ReportingController
+		}
+
+		w.line(1, "%s\t%s\t%s\t%s\t%s", event.Type, event.Reason, age, from, strings.TrimSpace(event.Message))
+	}
+}
+
+// lastSeen returns the most recent time an Event was observed
+func lastSeen(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// orNone returns a value, or <none> when it is empty
+func orNone(value string) string {
+	if value == "" {
+		return none
+	}
+	return value
+}
@@ -0,0 +1,365 @@
+package describe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// describePod writes a pod with its containers, conditions, volumes and scheduling
+func describePod(w *writer, pod *corev1.Pod) {
+	describeMetaPod(w, pod)
+
+	if len(pod.Spec.InitContainers) > 0 {
+		w.line(0, "Init Containers:")
+		describeContainers(w, pod.Spec.InitContainers, pod.Status.InitContainerStatuses)
+	}
+	w.line(0, "Containers:")
+	describeContainers(w, pod.Spec.Containers, pod.Status.ContainerStatuses)
+
+	conditions := make([]condition, 0, len(pod.Status.Conditions))
+	for _, c := range pod.Status.Conditions {
+		conditions = append(conditions, condition{string(c.Type), string(c.Status), c.Reason, c.Message})
+	}
+	describeConditions(w, conditions)
+
+	describeVolumes(w, pod.Spec.Volumes)
+	w.line(0, "QoS Class:\t%s", pod.Status.QOSClass)
+	describeMap(w, "Node-Selectors", pod.Spec.NodeSelector, "=")
+	describeTolerations(w, pod.Spec.Tolerations)
+}
+
+// describeMetaPod writes the pod header
+func describeMetaPod(w *writer, pod *corev1.Pod) {
+	w.line(0, "Name:\t%s", pod.Name)
+	w.line(0, "Namespace:\t%s", pod.Namespace)
+	if pod.Spec.Priority != nil {
+		w.line(0, "Priority:\t%d", *pod.Spec.Priority)
+	}
+	if pod.Spec.PriorityClassName != "" {
+		w.line(0, "Priority Class Name:\t%s", pod.Spec.PriorityClassName)
+	}
+	w.line(0, "Service Account:\t%s", pod.Spec.ServiceAccountName)
+
+	node := orNone(pod.Spec.NodeName)
+	if pod.Status.HostIP != "" {
+		node += "/" + pod.Status.HostIP
+	}
+	w.line(0, "Node:\t%s", node)
+
+	if pod.Status.StartTime != nil {
+		w.line(0, "Start Time:\t%s", pod.Status.StartTime.Format(time.RFC1123Z))
+	}
+	describeMap(w, "Labels", pod.Labels, "=")
+	describeMap(w, "Annotations", pod.Annotations, ": ")
+
+	status := string(pod.Status.Phase)
+	if pod.DeletionTimestamp != nil {
+		status = "Terminating (lasts " + time.Since(pod.DeletionTimestamp.Time).Round(time.Second).String() + ")"
+	}
+	w.line(0, "Status:\t%s", status)
+	if pod.Status.Reason != "" {
+		w.line(0, "Reason:\t%s", pod.Status.Reason)
+	}
+	if pod.Status.Message != "" {
+		w.line(0, "Message:\t%s", pod.Status.Message)
+	}
+	w.line(0, "IP:\t%s", pod.Status.PodIP)
+	if len(pod.Status.PodIPs) > 1 {
+		w.line(0, "IPs:")
+		for _, ip := range pod.Status.PodIPs {
+			w.line(1, "IP:\t%s", ip.IP)
+		}
+	}
+	describeControlledBy(w, pod.ObjectMeta)
+}
+
+// describeContainers writes containers with their current and last state
+func describeContainers(w *writer, containers []corev1.Container, statuses []corev1.ContainerStatus) {
+	byName := make(map[string]corev1.ContainerStatus, len(statuses))
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+
+	for _, container := range containers {
+		w.line(1, "%s:", container.Name)
+
+		status, hasStatus := byName[container.Name]
+		if hasStatus && status.ContainerID != "" {
+			w.line(2, "Container ID:\t%s", status.ContainerID)
+		}
+		w.line(2, "Image:\t%s", container.Image)
+		if hasStatus && status.ImageID != "" {
+			w.line(2, "Image ID:\t%s", status.ImageID)
+		}
+
+		ports := make([]string, 0, len(container.Ports))
+		for _, port := range container.Ports {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.ContainerPort, port.Protocol))
+		}
+		w.line(2, "Port:\t%s", orNone(strings.Join(ports, ", ")))
+
+		if len(container.Command) > 0 {
+			w.line(2, "Command:\t%s", strings.Join(container.Command, " "))
+		}
+		if len(container.Args) > 0 {
+			w.line(2, "Args:\t%s", strings.Join(container.Args, " "))
+		}
+
+		if hasStatus {
+			describeContainerState(w, "State", status.State)
+			if status.LastTerminationState != (corev1.ContainerState{}) {
+				describeContainerState(w, "Last State", status.LastTerminationState)
+			}
+			w.line(2, "Ready:\t%t", status.Ready)
+			w.line(2, "Restart Count:\t%d", status.RestartCount)
+		}
+
+		describeResources(w, "Limits", container.Resources.Limits)
+		describeResources(w, "Requests", container.Resources.Requests)
+
+		if container.LivenessProbe != nil {
+			w.line(2, "Liveness:\t%s", describeProbe(container.LivenessProbe))
+		}
+		if container.ReadinessProbe != nil {
+			w.line(2, "Readiness:\t%s", describeProbe(container.ReadinessProbe))
+		}
+		if container.StartupProbe != nil {
+			w.line(2, "Startup:\t%s", describeProbe(container.StartupProbe))
+		}
+
+		describeEnv(w, container)
+
+		if len(container.VolumeMounts) == 0 {
+			w.line(2, "Mounts:\t%s", none)
+		} else {
+			w.line(2, "Mounts:")
+			for _, mount := range container.VolumeMounts {
+				mode := "rw"
+				if mount.ReadOnly {
+					mode = "ro"
+				}
+				w.line(3, "%s from %s (%s)", mount.MountPath, mount.Name, mode)
+			}
+		}
+	}
+}
+
+// describeContainerState writes a running, waiting or terminated state
+func describeContainerState(w *writer, label string, state corev1.ContainerState) {
+	switch {
+	case state.Running != nil:
+		w.line(2, "%s:\tRunning", label)
+		w.line(3, "Started:\t%s", state.Running.StartedAt.Format(time.RFC1123Z))
+	case state.Waiting != nil:
+		w.line(2, "%s:\tWaiting", label)
+		w.line(3, "Reason:\t%s", state.Waiting.Reason)
+		if state.Waiting.Message != "" {
+			w.line(3, "Message:\t%s", state.Waiting.Message)
+		}
+	case state.Terminated != nil:
+		w.line(2, "%s:\tTerminated", label)
+		w.line(3, "Reason:\t%s", state.Terminated.Reason)
+		if state.Terminated.Message != "" {
+			w.line(3, "Message:\t%s", state.Terminated.Message)
+		}
+		w.line(3, "Exit Code:\t%d", state.Terminated.ExitCode)
+		w.line(3, "Started:\t%s", state.Terminated.StartedAt.Format(time.RFC1123Z))
+		w.line(3, "Finished:\t%s", state.Terminated.FinishedAt.Format(time.RFC1123Z))
+	default:
+		w.line(2, "%s:\tWaiting", label)
+	}
+}
+
+// describeResources writes resource limits or requests
+func describeResources(w *writer, label string, resources corev1.ResourceList) {
+	if len(resources) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	w.line(2, "%s:", label)
+	for _, name := range names {
+		quantity := resources[corev1.ResourceName(name)]
+		w.line(3, "%s:\t%s", name, quantity.String())
+	}
+}
+
+// describeProbe formats a probe like kubectl
+func describeProbe(probe *corev1.Probe) string {
+	var action string
+	switch {
+	case probe.HTTPGet != nil:
+		action = fmt.Sprintf("http-get %s://:%s%s", strings.ToLower(string(probe.HTTPGet.Scheme)), probe.HTTPGet.Port.String(), probe.HTTPGet.Path)
+	case probe.TCPSocket != nil:
+		action = fmt.Sprintf("tcp-socket :%s", probe.TCPSocket.Port.String())
+	case probe.Exec != nil:
+		action = fmt.Sprintf("exec %v", probe.Exec.Command)
+	case probe.GRPC != nil:
+		action = fmt.Sprintf("grpc <pod>:%d", probe.GRPC.Port)
+	default:
+		action = "unknown"
+	}
+
+	return fmt.Sprintf("%s delay=%ds timeout=%ds period=%ds #success=%d #failure=%d",
+		action, probe.InitialDelaySeconds, probe.TimeoutSeconds, probe.PeriodSeconds, probe.SuccessThreshold, probe.FailureThreshold)
+}
+
+// describeEnv writes environment variables, naming the source of references
+// rather than resolving secret values
+func describeEnv(w *writer, container corev1.Container) {
+	if len(container.Env) == 0 && len(container.EnvFrom) == 0 {
+		w.line(2, "Environment:\t%s", none)
+		return
+	}
+
+	for _, source := range container.EnvFrom {
+		switch {
+		case source.ConfigMapRef != nil:
+			w.line(2, "Environment Variables from:\t%s\tConfigMap", source.ConfigMapRef.Name)
+		case source.SecretRef != nil:
+			w.line(2, "Environment Variables from:\t%s\tSecret", source.SecretRef.Name)
+		}
+	}
+
+	if len(container.Env) == 0 {
+		return
+	}
+
+	w.line(2, "Environment:")
+	for _, env := range container.Env {
+		value := env.Value
+		if from := env.ValueFrom; from != nil {
+			switch {
+			case from.FieldRef != nil:
+				value = fmt.Sprintf("(%s:%s)", from.FieldRef.APIVersion, from.FieldRef.FieldPath)
+			case from.ResourceFieldRef != nil:
+				value = fmt.Sprintf("%s (%s)", from.ResourceFieldRef.Resource, from.ResourceFieldRef.ContainerName)
+			case from.SecretKeyRef != nil:
+				value = fmt.Sprintf("<set to the key '%s' in secret '%s'>", from.SecretKeyRef.Key, from.SecretKeyRef.Name)
+			case from.ConfigMapKeyRef != nil:
+				value = fmt.Sprintf("<set to the key '%s' of config map '%s'>", from.ConfigMapKeyRef.Key, from.ConfigMapKeyRef.Name)
+			}
+		}
+		w.line(3, "%s:\t%s", env.Name, value)
+	}
+}
+
+// describeVolumes writes pod volumes with their sources
+func describeVolumes(w *writer, volumes []corev1.Volume) {
+	if len(volumes) == 0 {
+		w.line(0, "Volumes:\t%s", none)
+		return
+	}
+
+	w.line(0, "Volumes:")
+	for _, volume := range volumes {
+		w.line(1, "%s:", volume.Name)
+
+		source := volume.VolumeSource
+		switch {
+		case source.ConfigMap != nil:
+			w.line(2, "Type:\tConfigMap (a volume populated by a ConfigMap)")
+			w.line(2, "Name:\t%s", source.ConfigMap.Name)
+			w.line(2, "Optional:\t%t", source.ConfigMap.Optional != nil && *source.ConfigMap.Optional)
+		case source.Secret != nil:
+			w.line(2, "Type:\tSecret (a volume populated by a Secret)")
+			w.line(2, "SecretName:\t%s", source.Secret.SecretName)
+			w.line(2, "Optional:\t%t", source.Secret.Optional != nil && *source.Secret.Optional)
+		case source.PersistentVolumeClaim != nil:
+			w.line(2, "Type:\tPersistentVolumeClaim (a reference to a PersistentVolumeClaim in the same namespace)")
+			w.line(2, "ClaimName:\t%s", source.PersistentVolumeClaim.ClaimName)
+			w.line(2, "ReadOnly:\t%t", source.PersistentVolumeClaim.ReadOnly)
+		case source.EmptyDir != nil:
+			w.line(2, "Type:\tEmptyDir (a temporary directory that shares a pod's lifetime)")
+			w.line(2, "Medium:\t%s", source.EmptyDir.Medium)
+			if source.EmptyDir.SizeLimit != nil {
+				w.line(2, "SizeLimit:\t%s", source.EmptyDir.SizeLimit.String())
+			}
+		case source.HostPath != nil:
+			w.line(2, "Type:\tHostPath (bare host directory volume)")
+			w.line(2, "Path:\t%s", source.HostPath.Path)
+		case source.Projected != nil:
+			w.line(2, "Type:\tProjected (a volume that contains injected data from multiple sources)")
+			for _, projection := range source.Projected.Sources {
+				switch {
+				case projection.ServiceAccountToken != nil:
+					w.line(2, "TokenExpirationSeconds:\t%d", ptrValue(projection.ServiceAccountToken.ExpirationSeconds))
+				case projection.ConfigMap != nil:
+					w.line(2, "ConfigMapName:\t%s", projection.ConfigMap.Name)
+				case projection.Secret != nil:
+					w.line(2, "SecretName:\t%s", projection.Secret.Name)
+				case projection.DownwardAPI != nil:
+					w.line(2, "DownwardAPI:\ttrue")
+				}
+			}
+		case source.DownwardAPI != nil:
+			w.line(2, "Type:\tDownwardAPI (a volume populated by information about the pod)")
+		case source.CSI != nil:
+			w.line(2, "Type:\tCSI (a Container Storage Interface (CSI) volume source)")
+			w.line(2, "Driver:\t%s", source.CSI.Driver)
+		case source.NFS != nil:
+			w.line(2, "Type:\tNFS (an NFS mount that lasts the lifetime of a pod)")
+			w.line(2, "Server:\t%s", source.NFS.Server)
+			w.line(2, "Path:\t%s", source.NFS.Path)
+		default:
+			w.line(2, "Type:\t<unknown>")
+		}
+	}
+}
+
+// describeTolerations writes tolerations like kubectl
+func describeTolerations(w *writer, tolerations []corev1.Toleration) {
+	if len(tolerations) == 0 {
+		w.line(0, "Tolerations:\t%s", none)
+		return
+	}
+
+	for i, toleration := range tolerations {
+		prefix := ""
+		if i == 0 {
+			prefix = "Tolerations:"
+		}
+		w.line(0, "%s\t%s", prefix, formatToleration(toleration))
+	}
+}
+
+// formatToleration formats a toleration as key=value:effect op=Exists for Ns
+func formatToleration(toleration corev1.Toleration) string {
+	var b strings.Builder
+	b.WriteString(toleration.Key)
+	if toleration.Value != "" {
+		b.WriteString("=" + toleration.Value)
+	}
+	if toleration.Effect != "" {
+		b.WriteString(":" + string(toleration.Effect))
+	}
+	if toleration.Operator == corev1.TolerationOpExists && toleration.Value == "" {
+		if toleration.Key == "" {
+			b.WriteString("op=Exists")
+		} else {
+			b.WriteString(" op=Exists")
+		}
+	}
+	if toleration.TolerationSeconds != nil {
+		fmt.Fprintf(&b, " for %ds", *toleration.TolerationSeconds)
+	}
+	return b.String()
+}
+
+// ptrValue dereferences an optional number
+func ptrValue[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
@@ -0,0 +1,129 @@
+package describe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func describeService(w *writer, s *corev1.Service) {
+	describeMeta(w, s.ObjectMeta)
+	describeMap(w, "Selector", s.Spec.Selector, "=")
+	w.line(0, "Type:\t%s", s.Spec.Type)
+	if s.Spec.IPFamilyPolicy != nil {
+		w.line(0, "IP Family Policy:\t%s", *s.Spec.IPFamilyPolicy)
+	}
+	w.line(0, "IP:\t%s", orNone(s.Spec.ClusterIP))
+	if len(s.Spec.ClusterIPs) > 1 {
+		w.line(0, "IPs:\t%s", strings.Join(s.Spec.ClusterIPs, ","))
+	}
+	if s.Spec.ExternalName != "" {
+		w.line(0, "External Name:\t%s", s.Spec.ExternalName)
+	}
+	if len(s.Spec.ExternalIPs) > 0 {
+		w.line(0, "External IPs:\t%s", strings.Join(s.Spec.ExternalIPs, ","))
+	}
+	for _, ingress := range s.Status.LoadBalancer.Ingress {
+		address := ingress.IP
+		if address == "" {
+			address = ingress.Hostname
+		}
+		w.line(0, "LoadBalancer Ingress:\t%s", address)
+	}
+
+	for _, port := range s.Spec.Ports {
+		name := port.Name
+		if name == "" {
+			name = "<unset>"
+		}
+		w.line(0, "Port:\t%s\t%d/%s", name, port.Port, port.Protocol)
+		w.line(0, "TargetPort:\t%s/%s", port.TargetPort.String(), port.Protocol)
+		if port.NodePort != 0 {
+			w.line(0, "NodePort:\t%s\t%d/%s", name, port.NodePort, port.Protocol)
+		}
+	}
+
+	w.line(0, "Session Affinity:\t%s", s.Spec.SessionAffinity)
+	if s.Spec.ExternalTrafficPolicy != "" {
+		w.line(0, "External Traffic Policy:\t%s", s.Spec.ExternalTrafficPolicy)
+	}
+	if s.Spec.InternalTrafficPolicy != nil {
+		w.line(0, "Internal Traffic Policy:\t%s", *s.Spec.InternalTrafficPolicy)
+	}
+}
+
+func describeConfigMap(w *writer, cm *corev1.ConfigMap) {
+	describeMeta(w, cm.ObjectMeta)
+	w.line(0, "")
+	w.line(0, "Data")
+	w.line(0, "====")
+
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		w.line(0, "%s:", key)
+		w.line(0, "----")
+		// Values are written verbatim so tabs in them are not aligned as columns
+		for _, line := range strings.Split(strings.TrimRight(cm.Data[key], "\n"), "\n") {
+			w.line(0, "%s", strings.ReplaceAll(line, "\t", "    "))
+		}
+		w.line(0, "")
+	}
+
+	w.line(0, "")
+	w.line(0, "BinaryData")
+	w.line(0, "====")
+	for key, value := range cm.BinaryData {
+		w.line(0, "%s: %d bytes", key, len(value))
+	}
+	w.line(0, "")
+}
+
+func describePersistentVolumeClaim(w *writer, p *corev1.PersistentVolumeClaim) {
+	describeMeta(w, p.ObjectMeta)
+	w.line(0, "StorageClass:\t%s", orNone(ptrValue(p.Spec.StorageClassName)))
+	w.line(0, "Status:\t%s", p.Status.Phase)
+	w.line(0, "Volume:\t%s", p.Spec.VolumeName)
+	w.line(0, "Capacity:\t%s", p.Status.Capacity.Storage())
+	w.line(0, "Requested:\t%s", p.Spec.Resources.Requests.Storage())
+	w.line(0, "Access Modes:\t%s", formatAccessModes(p.Status.AccessModes))
+	if p.Spec.VolumeMode != nil {
+		w.line(0, "VolumeMode:\t%s", *p.Spec.VolumeMode)
+	}
+}
+
+// formatAccessModes abbreviates access modes like kubectl, e.g. RWO,ROX
+func formatAccessModes(modes []corev1.PersistentVolumeAccessMode) string {
+	short := map[corev1.PersistentVolumeAccessMode]string{
+		corev1.ReadWriteOnce:    "RWO",
+		corev1.ReadOnlyMany:     "ROX",
+		corev1.ReadWriteMany:    "RWX",
+		corev1.ReadWriteOncePod: "RWOP",
+	}
+
+	parts := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		parts = append(parts, short[mode])
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatLabels formats labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return none
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\n\t")
+}
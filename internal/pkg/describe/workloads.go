@@ -0,0 +1,211 @@
+package describe
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// describeWorkload writes a controller kind, reporting whether it is one
+func describeWorkload(w *writer, obj runtime.Object) bool {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		describeDeployment(w, o)
+	case *appsv1.StatefulSet:
+		describeStatefulSet(w, o)
+	case *appsv1.DaemonSet:
+		describeDaemonSet(w, o)
+	case *appsv1.ReplicaSet:
+		describeReplicaSet(w, o)
+	case *batchv1.Job:
+		describeJob(w, o)
+	case *batchv1.CronJob:
+		describeCronJob(w, o)
+	case *policyv1.PodDisruptionBudget:
+		describePodDisruptionBudget(w, o)
+	default:
+		return false
+	}
+	return true
+}
+
+func describeDeployment(w *writer, d *appsv1.Deployment) {
+	describeMeta(w, d.ObjectMeta)
+	describeCreated(w, d.ObjectMeta)
+	describeSelector(w, d.Spec.Selector)
+	w.line(0, "Replicas:\t%d desired | %d updated | %d total | %d available | %d unavailable",
+		ptrValue(d.Spec.Replicas), d.Status.UpdatedReplicas, d.Status.Replicas, d.Status.AvailableReplicas, d.Status.UnavailableReplicas)
+	w.line(0, "StrategyType:\t%s", d.Spec.Strategy.Type)
+	w.line(0, "MinReadySeconds:\t%d", d.Spec.MinReadySeconds)
+	if rolling := d.Spec.Strategy.RollingUpdate; rolling != nil {
+		w.line(0, "RollingUpdateStrategy:\t%s max unavailable, %s max surge", rolling.MaxUnavailable, rolling.MaxSurge)
+	}
+	describePodTemplate(w, d.Spec.Template)
+
+	conditions := make([]condition, 0, len(d.Status.Conditions))
+	for _, c := range d.Status.Conditions {
+		conditions = append(conditions, condition{string(c.Type), string(c.Status), c.Reason, c.Message})
+	}
+	describeConditions(w, conditions)
+}
+
+func describeStatefulSet(w *writer, s *appsv1.StatefulSet) {
+	describeMeta(w, s.ObjectMeta)
+	describeCreated(w, s.ObjectMeta)
+	describeSelector(w, s.Spec.Selector)
+	w.line(0, "Replicas:\t%d desired | %d total", ptrValue(s.Spec.Replicas), s.Status.Replicas)
+	w.line(0, "Update Strategy:\t%s", s.Spec.UpdateStrategy.Type)
+	if rolling := s.Spec.UpdateStrategy.RollingUpdate; rolling != nil && rolling.Partition != nil {
+		w.line(1, "Partition:\t%d", *rolling.Partition)
+	}
+	w.line(0, "Pods Status:\t%d Ready / %d Current / %d Updated", s.Status.ReadyReplicas, s.Status.CurrentReplicas, s.Status.UpdatedReplicas)
+	describePodTemplate(w, s.Spec.Template)
+
+	if len(s.Spec.VolumeClaimTemplates) == 0 {
+		w.line(0, "Volume Claims:\t%s", none)
+		return
+	}
+	w.line(0, "Volume Claims:")
+	for _, claim := range s.Spec.VolumeClaimTemplates {
+		w.line(1, "Name:\t%s", claim.Name)
+		w.line(1, "StorageClass:\t%s", orNone(ptrValue(claim.Spec.StorageClassName)))
+		w.line(1, "Capacity:\t%s", claim.Spec.Resources.Requests.Storage())
+		w.line(1, "Access Modes:\t%v", claim.Spec.AccessModes)
+	}
+}
+
+func describeDaemonSet(w *writer, d *appsv1.DaemonSet) {
+	describeMeta(w, d.ObjectMeta)
+	describeSelector(w, d.Spec.Selector)
+	w.line(0, "Desired Number of Nodes Scheduled:\t%d", d.Status.DesiredNumberScheduled)
+	w.line(0, "Current Number of Nodes Scheduled:\t%d", d.Status.CurrentNumberScheduled)
+	w.line(0, "Number of Nodes Scheduled with Up-to-date Pods:\t%d", d.Status.UpdatedNumberScheduled)
+	w.line(0, "Number of Nodes Scheduled with Available Pods:\t%d", d.Status.NumberAvailable)
+	w.line(0, "Number of Nodes Misscheduled:\t%d", d.Status.NumberMisscheduled)
+	w.line(0, "Pods Status:\t%d Ready / %d Unavailable", d.Status.NumberReady, d.Status.NumberUnavailable)
+	w.line(0, "Update Strategy:\t%s", d.Spec.UpdateStrategy.Type)
+	describePodTemplate(w, d.Spec.Template)
+}
+
+func describeReplicaSet(w *writer, r *appsv1.ReplicaSet) {
+	describeMeta(w, r.ObjectMeta)
+	describeSelector(w, r.Spec.Selector)
+	describeControlledBy(w, r.ObjectMeta)
+	w.line(0, "Replicas:\t%d current / %d desired", r.Status.Replicas, ptrValue(r.Spec.Replicas))
+	w.line(0, "Pods Status:\t%d Ready / %d Available", r.Status.ReadyReplicas, r.Status.AvailableReplicas)
+	describePodTemplate(w, r.Spec.Template)
+
+	conditions := make([]condition, 0, len(r.Status.Conditions))
+	for _, c := range r.Status.Conditions {
+		conditions = append(conditions, condition{string(c.Type), string(c.Status), c.Reason, c.Message})
+	}
+	describeConditions(w, conditions)
+}
+
+func describeJob(w *writer, j *batchv1.Job) {
+	describeMeta(w, j.ObjectMeta)
+	describeSelector(w, j.Spec.Selector)
+	describeControlledBy(w, j.ObjectMeta)
+	w.line(0, "Parallelism:\t%d", ptrValue(j.Spec.Parallelism))
+	if j.Spec.Completions != nil {
+		w.line(0, "Completions:\t%d", *j.Spec.Completions)
+	} else {
+		w.line(0, "Completions:\t<unset>")
+	}
+	if j.Spec.CompletionMode != nil {
+		w.line(0, "Completion Mode:\t%s", *j.Spec.CompletionMode)
+	}
+	if j.Status.StartTime != nil {
+		w.line(0, "Start Time:\t%s", j.Status.StartTime.Format(time.RFC1123Z))
+	}
+	if j.Status.CompletionTime != nil {
+		w.line(0, "Completed At:\t%s", j.Status.CompletionTime.Format(time.RFC1123Z))
+		if j.Status.StartTime != nil {
+			w.line(0, "Duration:\t%s", j.Status.CompletionTime.Sub(j.Status.StartTime.Time).Round(time.Second))
+		}
+	}
+	if j.Spec.ActiveDeadlineSeconds != nil {
+		w.line(0, "Active Deadline Seconds:\t%ds", *j.Spec.ActiveDeadlineSeconds)
+	}
+	w.line(0, "Backoff Limit:\t%d", ptrValue(j.Spec.BackoffLimit))
+	w.line(0, "Pods Statuses:\t%d Active / %d Succeeded / %d Failed", j.Status.Active, j.Status.Succeeded, j.Status.Failed)
+	describePodTemplate(w, j.Spec.Template)
+
+	conditions := make([]condition, 0, len(j.Status.Conditions))
+	for _, c := range j.Status.Conditions {
+		conditions = append(conditions, condition{string(c.Type), string(c.Status), c.Reason, c.Message})
+	}
+	describeConditions(w, conditions)
+}
+
+func describeCronJob(w *writer, c *batchv1.CronJob) {
+	describeMeta(w, c.ObjectMeta)
+	describeCreated(w, c.ObjectMeta)
+	w.line(0, "Schedule:\t%s", c.Spec.Schedule)
+	if c.Spec.TimeZone != nil {
+		w.line(0, "Time Zone:\t%s", *c.Spec.TimeZone)
+	}
+	w.line(0, "Concurrency Policy:\t%s", c.Spec.ConcurrencyPolicy)
+	w.line(0, "Suspend:\t%t", ptrValue(c.Spec.Suspend))
+	w.line(0, "Successful Job History Limit:\t%d", ptrValue(c.Spec.SuccessfulJobsHistoryLimit))
+	w.line(0, "Failed Job History Limit:\t%d", ptrValue(c.Spec.FailedJobsHistoryLimit))
+	if c.Spec.StartingDeadlineSeconds != nil {
+		w.line(0, "Starting Deadline Seconds:\t%ds", *c.Spec.StartingDeadlineSeconds)
+	}
+	describePodTemplate(w, c.Spec.JobTemplate.Spec.Template)
+
+	if c.Status.LastScheduleTime != nil {
+		w.line(0, "Last Schedule Time:\t%s", c.Status.LastScheduleTime.Format(time.RFC1123Z))
+	} else {
+		w.line(0, "Last Schedule Time:\t<unset>")
+	}
+	if len(c.Status.Active) == 0 {
+		w.line(0, "Active Jobs:\t%s", none)
+		return
+	}
+	for i, ref := range c.Status.Active {
+		prefix := ""
+		if i == 0 {
+			prefix = "Active Jobs:"
+		}
+		w.line(0, "%s\t%s", prefix, ref.Name)
+	}
+}
+
+func describePodDisruptionBudget(w *writer, p *policyv1.PodDisruptionBudget) {
+	describeMeta(w, p.ObjectMeta)
+	if p.Spec.MinAvailable != nil {
+		w.line(0, "Min available:\t%s", p.Spec.MinAvailable.String())
+	}
+	if p.Spec.MaxUnavailable != nil {
+		w.line(0, "Max unavailable:\t%s", p.Spec.MaxUnavailable.String())
+	}
+	describeSelector(w, p.Spec.Selector)
+	w.line(0, "Status:")
+	w.line(1, "Allowed disruptions:\t%d", p.Status.DisruptionsAllowed)
+	w.line(1, "Current:\t%d", p.Status.CurrentHealthy)
+	w.line(1, "Desired:\t%d", p.Status.DesiredHealthy)
+	w.line(1, "Total:\t%d", p.Status.ExpectedPods)
+}
+
+// describePodTemplate writes the pod template of a controller
+func describePodTemplate(w *writer, template corev1.PodTemplateSpec) {
+	w.line(0, "Pod Template:")
+	w.line(1, "Labels:\t%s", formatLabels(template.Labels))
+	if template.Spec.ServiceAccountName != "" {
+		w.line(1, "Service Account:\t%s", template.Spec.ServiceAccountName)
+	}
+	if len(template.Spec.InitContainers) > 0 {
+		w.line(1, "Init Containers:")
+		describeContainers(w, template.Spec.InitContainers, nil)
+	}
+	w.line(1, "Containers:")
+	describeContainers(w, template.Spec.Containers, nil)
+	describeVolumes(w, template.Spec.Volumes)
+	describeMap(w, "Node-Selectors", template.Spec.NodeSelector, "=")
+	describeTolerations(w, template.Spec.Tolerations)
+}
@@ -0,0 +1,166 @@
+// Package eventqueue moves agent events off the messaging call path. Events
+// are queued and handled by a fixed set of workers, so a slow store write
+// doesn't block the agent that published the event. When the queue is full
+// new events are refused rather than piling up in memory.
+package eventqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+)
+
+// ErrOverloaded is returned to the publisher of an event that was shed
+// because its worker's queue is full
+var ErrOverloaded = errors.New("event queue is full")
+
+// Config sizes the worker pool
+type Config struct {
+	Workers   int `yaml:"workers"`
+	QueueSize int `yaml:"queueSize"` // Events waiting per worker
+}
+
+// Stats reports queue activity
+type Stats struct {
+	Workers   int    `json:"workers"`
+	Capacity  int    `json:"capacity"`
+	Depth     int    `json:"depth"`
+	Processed uint64 `json:"processed"`
+	Failed    uint64 `json:"failed"`
+	Shed      uint64 `json:"shed"`
+}
+
+// event is a queued message and the handler it is for
+type event struct {
+	topic   string
+	message []byte
+	handler func([]byte) error
+}
+
+// Queue hands subscribed events to a pool of workers. Events about the same
+// object always go to the same worker, so they are handled in the order they
+// were published.
+type Queue struct {
+	subscriber messagingtypes.Subscriber
+	queues     []chan event
+	logger     *slog.Logger
+	once       sync.Once
+
+	processed atomic.Uint64
+	failed    atomic.Uint64
+	shed      atomic.Uint64
+}
+
+// New creates a queue subscribing through subscriber, filling in defaults
+// for unset sizes. Workers run once Start is called.
+func New(config Config, subscriber messagingtypes.Subscriber, logger *slog.Logger) *Queue {
+	if config.Workers <= 0 {
+		config.Workers = 8
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+
+	queues := make([]chan event, config.Workers)
+	for i := range queues {
+		queues[i] = make(chan event, config.QueueSize)
+	}
+
+	return &Queue{
+		subscriber: subscriber,
+		queues:     queues,
+		logger:     logger,
+	}
+}
+
+// Start runs the workers until the context is done
+func (q *Queue) Start(ctx context.Context) {
+	q.once.Do(func() {
+		for _, queue := range q.queues {
+			go q.work(ctx, queue)
+		}
+	})
+}
+
+// Subscribe registers a handler for a topic. Received events are queued and
+// the publisher is answered right away; handler errors are logged.
+func (q *Queue) Subscribe(topic string, handler func([]byte) error) {
+	q.subscriber.Subscribe(topic, func(message []byte) error {
+		return q.enqueue(event{topic: topic, message: message, handler: handler})
+	})
+}
+
+// Stats returns the queue metrics
+func (q *Queue) Stats() Stats {
+	stats := Stats{
+		Workers:   len(q.queues),
+		Processed: q.processed.Load(),
+		Failed:    q.failed.Load(),
+		Shed:      q.shed.Load(),
+	}
+	for _, queue := range q.queues {
+		stats.Capacity += cap(queue)
+		stats.Depth += len(queue)
+	}
+	return stats
+}
+
+// enqueue queues an event on its object's worker, refusing it when that
+// worker is full
+func (q *Queue) enqueue(e event) error {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(eventKey(e.message)))
+	queue := q.queues[h.Sum32()%uint32(len(q.queues))]
+
+	select {
+	case queue <- e:
+		return nil
+	default:
+		q.shed.Add(1)
+		q.logger.Warn("Shedding event, queue is full", "topic", e.topic)
+		return ErrOverloaded
+	}
+}
+
+// work handles the events of one queue
+func (q *Queue) work(ctx context.Context, queue <-chan event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-queue:
+			if err := e.handler(e.message); err != nil {
+				q.failed.Add(1)
+				q.logger.Error("Failed to handle event", "topic", e.topic, "error", err)
+				continue
+			}
+			q.processed.Add(1)
+		}
+	}
+}
+
+// eventKey identifies the object an event is about. Resource events carry
+// the cluster and the object's metadata; cluster events only their cluster.
+func eventKey(message []byte) string {
+	var payload struct {
+		ClusterID   string `json:"cluster_id"`
+		ClusterName string `json:"clusterName"`
+		Resource    struct {
+			Metadata struct {
+				Namespace string `json:"namespace"`
+				Name      string `json:"name"`
+			} `json:"metadata"`
+		} `json:"resource"`
+	}
+	if err := json.Unmarshal(message, &payload); err != nil {
+		return ""
+	}
+
+	return payload.ClusterID + payload.ClusterName + "/" + payload.Resource.Metadata.Namespace + "/" + payload.Resource.Metadata.Name
+}
@@ -0,0 +1,304 @@
+// Package features gates experimental features per deployment and per
+// cluster. A flag's built-in default can be changed in the config, and
+// overridden at runtime for the whole deployment or for one cluster. The
+// readOnly flag freezes a deployment or cluster by turning off every flag that
+// allows changes.
+package features
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+)
+
+// Flags
+const (
+	// WriteOperations allows creating, changing and deleting cluster resources
+	WriteOperations = "writeOperations"
+	// Exec allows running commands in, attaching to and copying files from containers
+	Exec = "exec"
+	// CRDBrowsing lists custom resource types alongside the built-in ones
+	CRDBrowsing = "crdBrowsing"
+	// ReadOnly rejects every mutating operation while reads continue, e.g. during an incident freeze
+	ReadOnly = "readOnly"
+)
+
+// Sources of a flag's state, from lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceConfig  = "config"
+	SourceRuntime = "runtime"
+	SourceCluster = "cluster"
+	// SourceReadOnly marks a flag turned off by read-only mode
+	SourceReadOnly = "readOnly"
+)
+
+// refreshInterval is how long overrides are used before they are read again,
+// so changes made through another replica show up
+const refreshInterval = 30 * time.Second
+
+// Flag is a feature that can be turned off
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
+}
+
+// flags are the known flags. Features default to on, matching the behavior
+// before they could be turned off, while read-only mode defaults to off.
+var flags = []Flag{
+	{Name: WriteOperations, Description: "Create, change and delete cluster resources", Default: true},
+	{Name: Exec, Description: "Run commands in, attach to and copy files from containers", Default: true},
+	{Name: CRDBrowsing, Description: "List custom resource types alongside built-in ones", Default: true},
+	{Name: ReadOnly, Description: "Reject every mutating operation while reads continue", Default: false},
+}
+
+// mutating are the flags read-only mode turns off. Every route that changes a
+// cluster or its stored copy requires one of them.
+var mutating = map[string]bool{
+	WriteOperations: true,
+	Exec:            true,
+}
+
+// Known reports whether a flag exists
+func Known(name string) bool {
+	for _, flag := range flags {
+		if flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Config turns flags on or off for the deployment
+type Config struct {
+	Flags map[string]bool `yaml:"flags"`
+}
+
+// Override is a flag set at runtime for the whole deployment
+type Override struct {
+	Name      string    `json:"name" bson:"_id"`
+	Enabled   bool      `json:"enabled" bson:"enabled"`
+	UpdatedBy string    `json:"updatedBy,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updated_at"`
+}
+
+// State is the effective state of a flag and where it comes from
+type State struct {
+	Flag
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"`
+}
+
+// Store persists runtime overrides and reads the overrides of clusters
+type Store interface {
+	SaveFeatureOverride(ctx context.Context, override *Override) error
+	DeleteFeatureOverride(ctx context.Context, name string) error
+	ListFeatureOverrides(ctx context.Context, results *[]Override) error
+	GetCluster(ctx context.Context, name string, result *cluster.ClusterInfo) error
+}
+
+// clusterEntry caches the overrides of a cluster
+type clusterEntry struct {
+	features map[string]bool
+	expires  time.Time
+}
+
+// Manager answers whether a flag is on
+type Manager struct {
+	config    Config
+	store     Store
+	logger    *slog.Logger
+	mu        sync.Mutex
+	overrides map[string]Override
+	loadedAt  time.Time
+	clusters  map[string]clusterEntry
+}
+
+// NewManager creates a manager, rejecting unknown flags in the config
+func NewManager(config Config, store Store, logger *slog.Logger) (*Manager, error) {
+	for name := range config.Flags {
+		if !Known(name) {
+			return nil, fmt.Errorf("unknown feature flag: %s", name)
+		}
+	}
+
+	return &Manager{
+		config:    config,
+		store:     store,
+		logger:    logger,
+		overrides: make(map[string]Override),
+		clusters:  make(map[string]clusterEntry),
+	}, nil
+}
+
+// Enabled reports whether a flag is on for a cluster. An empty cluster ID
+// checks the deployment-wide state.
+func (m *Manager) Enabled(ctx context.Context, name, clusterID string) bool {
+	return m.state(ctx, name, clusterID).Enabled
+}
+
+// List returns the state of every flag for a cluster, or for the deployment
+// when the cluster ID is empty
+func (m *Manager) List(ctx context.Context, clusterID string) []State {
+	states := make([]State, 0, len(flags))
+	for _, flag := range flags {
+		states = append(states, m.state(ctx, flag.Name, clusterID))
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
+// SetOverride turns a flag on or off for the whole deployment, or removes
+// the runtime override when enabled is nil
+func (m *Manager) SetOverride(ctx context.Context, name string, enabled *bool, updatedBy string) error {
+	if !Known(name) {
+		return fmt.Errorf("unknown feature flag: %s", name)
+	}
+
+	if enabled == nil {
+		if err := m.store.DeleteFeatureOverride(ctx, name); err != nil {
+			return err
+		}
+	} else {
+		override := &Override{Name: name, Enabled: *enabled, UpdatedBy: updatedBy, UpdatedAt: time.Now()}
+		if err := m.store.SaveFeatureOverride(ctx, override); err != nil {
+			return err
+		}
+	}
+
+	// Read them again on the next check
+	m.mu.Lock()
+	m.loadedAt = time.Time{}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// InvalidateCluster drops the cached overrides of a cluster after they change
+func (m *Manager) InvalidateCluster(clusterID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.clusters, clusterID)
+}
+
+// state resolves a flag from its default, the config, the runtime override
+// and the cluster's override, in that order. Read-only mode, wherever it is
+// set, then turns off the flags that allow changes.
+func (m *Manager) state(ctx context.Context, name, clusterID string) State {
+	var state State
+	for _, flag := range flags {
+		if flag.Name == name {
+			state = State{Flag: flag, Enabled: flag.Default, Source: SourceDefault}
+		}
+	}
+	if state.Name == "" {
+		return State{Flag: Flag{Name: name}, Source: SourceDefault}
+	}
+
+	if enabled, ok := m.config.Flags[name]; ok {
+		state.Enabled, state.Source = enabled, SourceConfig
+	}
+
+	if override, ok := m.runtimeOverrides(ctx)[name]; ok {
+		state.Enabled, state.Source = override.Enabled, SourceRuntime
+	}
+
+	if clusterID != "" {
+		if enabled, ok := m.clusterOverrides(ctx, clusterID)[name]; ok {
+			state.Enabled, state.Source = enabled, SourceCluster
+		}
+	}
+
+	if mutating[name] && state.Enabled && m.Enabled(ctx, ReadOnly, clusterID) {
+		state.Enabled, state.Source = false, SourceReadOnly
+	}
+
+	return state
+}
+
+// runtimeOverrides returns the deployment-wide overrides, reading them again
+// once they are stale. The last ones read are kept if the store fails.
+func (m *Manager) runtimeOverrides(ctx context.Context) map[string]Override {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if time.Since(m.loadedAt) < refreshInterval {
+		return m.overrides
+	}
+
+	var overrides []Override
+	if err := m.store.ListFeatureOverrides(ctx, &overrides); err != nil {
+		m.logger.Warn("Failed to load feature flag overrides", "error", err)
+		return m.overrides
+	}
+
+	m.overrides = make(map[string]Override, len(overrides))
+	for _, override := range overrides {
+		m.overrides[override.Name] = override
+	}
+	m.loadedAt = time.Now()
+
+	return m.overrides
+}
+
+// clusterOverrides returns the overrides of a cluster, caching them briefly.
+// Clusters that can't be read have none.
+func (m *Manager) clusterOverrides(ctx context.Context, clusterID string) map[string]bool {
+	m.mu.Lock()
+	entry, ok := m.clusters[clusterID]
+	m.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.features
+	}
+
+	var info cluster.ClusterInfo
+	if err := m.store.GetCluster(ctx, clusterID, &info); err != nil {
+		m.logger.Debug("No feature flag overrides for cluster", "clusterID", clusterID, "error", err)
+	}
+
+	m.mu.Lock()
+	m.clusters[clusterID] = clusterEntry{features: info.Features, expires: time.Now().Add(refreshInterval)}
+	m.mu.Unlock()
+
+	return info.Features
+}
+
+// Require rejects requests when a flag is off for the cluster in the
+// "clusterID" route parameter, or for the deployment on routes without one.
+// Requests refused because of read-only mode get a 423 saying so.
+func Require(m *Manager, name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clusterID := c.Params("clusterID")
+
+		state := m.state(c.Context(), name, clusterID)
+		switch {
+		case state.Enabled:
+			return c.Next()
+		case state.Source == SourceReadOnly && clusterID != "":
+			return c.Status(fiber.StatusLocked).JSON(fiber.Map{
+				"error":    fmt.Sprintf("Cluster %s is in read-only mode, changes are rejected until it is turned off", clusterID),
+				"readOnly": true,
+			})
+		case state.Source == SourceReadOnly:
+			return c.Status(fiber.StatusLocked).JSON(fiber.Map{
+				"error":    "The dashboard is in read-only mode, changes are rejected until it is turned off",
+				"readOnly": true,
+			})
+		default:
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": fmt.Sprintf("Feature %s is disabled", name),
+			})
+		}
+	}
+}
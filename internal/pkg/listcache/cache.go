@@ -0,0 +1,178 @@
+// Package listcache keeps encoded list responses in memory for a few
+// seconds, so dashboards polling the same list don't each hit the store.
+// Entries are dropped as soon as an event changes their kind.
+package listcache
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Config bounds the list cache
+type Config struct {
+	Disabled   bool          `yaml:"disabled"`
+	TTL        time.Duration `yaml:"ttl"`
+	MaxEntries int           `yaml:"maxEntries"`
+}
+
+// Key identifies a list response. Selector holds whatever narrows the list
+// beyond its namespace, such as the request's query string.
+type Key struct {
+	ClusterID string
+	Kind      string
+	Namespace string
+	Selector  string
+}
+
+// Stats reports cache activity
+type Stats struct {
+	Size        int    `json:"size"`
+	MaxEntries  int    `json:"maxEntries"`
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Invalidated uint64 `json:"invalidated"`
+}
+
+// entry is a cached response stored in the LRU list
+type entry struct {
+	key     Key
+	body    []byte
+	expires time.Time
+}
+
+// Cache is a size-bounded LRU cache of encoded list responses
+type Cache struct {
+	mu      sync.Mutex
+	config  Config
+	entries map[Key]*list.Element
+	order   *list.List
+	stats   Stats
+}
+
+// New creates a cache, filling in defaults for unset limits
+func New(config Config) *Cache {
+	if config.TTL <= 0 {
+		config.TTL = 5 * time.Second
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1000
+	}
+
+	return &Cache{
+		config:  config,
+		entries: make(map[Key]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns a cached response if present and not expired
+func (c *Cache) Get(key Key) ([]byte, bool) {
+	if c.config.Disabled {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	cached := elem.Value.(*entry)
+	if time.Now().After(cached.expires) {
+		c.remove(elem)
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
+	return cached.body, true
+}
+
+// Set encodes a list as JSON, caches it and returns the encoding
+func (c *Cache) Set(key Key, value any) ([]byte, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if c.config.Disabled {
+		return body, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, body: body, expires: time.Now().Add(c.config.TTL)})
+
+	for c.order.Len() > c.config.MaxEntries {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+
+	return body, nil
+}
+
+// Invalidate drops the cached lists of a kind that may include an object in
+// a namespace: that namespace's lists and the cluster-wide ones
+func (c *Cache) Invalidate(clusterID, kind, namespace string) int {
+	return c.removeWhere(func(key Key) bool {
+		return key.ClusterID == clusterID && key.Kind == kind &&
+			(key.Namespace == "" || namespace == "" || key.Namespace == namespace)
+	})
+}
+
+// InvalidateCluster drops every cached list of a cluster
+func (c *Cache) InvalidateCluster(clusterID string) int {
+	return c.removeWhere(func(key Key) bool {
+		return key.ClusterID == clusterID
+	})
+}
+
+// InvalidateAll empties the cache and returns how many entries were removed
+func (c *Cache) InvalidateAll() int {
+	return c.removeWhere(func(Key) bool { return true })
+}
+
+// Stats returns the current cache metrics
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = c.order.Len()
+	stats.MaxEntries = c.config.MaxEntries
+	return stats
+}
+
+// removeWhere drops the entries whose key matches and returns how many were removed
+func (c *Cache) removeWhere(match func(Key) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, elem := range c.entries {
+		if match(key) {
+			c.remove(elem)
+			removed++
+		}
+	}
+
+	c.stats.Invalidated += uint64(removed)
+	return removed
+}
+
+// remove unlinks an entry; callers hold the lock
+func (c *Cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key)
+}
@@ -0,0 +1,133 @@
+// Package logging builds the structured loggers of the binaries and lets
+// their level change while they run.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// Formats logs can be written in
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config selects how logs are written
+type Config struct {
+	// Format is "text" (the default) or "json", which log aggregators can parse
+	Format string `yaml:"format"`
+	// Level is the lowest level logged: debug, info (the default), warn or error
+	Level string `yaml:"level"`
+}
+
+// Level is the level of a logger, changeable at runtime
+type Level struct {
+	level      slog.LevelVar
+	configured slog.Level
+	format     string
+}
+
+// New creates a logger writing to w as configured, along with its level
+func New(config Config, w io.Writer) (*slog.Logger, *Level, error) {
+	configured, err := ParseLevel(config.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	level := &Level{configured: configured, format: config.Format}
+	level.level.Set(configured)
+
+	options := &slog.HandlerOptions{Level: &level.level}
+
+	var handler slog.Handler
+	switch strings.ToLower(config.Format) {
+	case "", FormatText:
+		level.format = FormatText
+		handler = slog.NewTextHandler(w, options)
+	case FormatJSON:
+		level.format = FormatJSON
+		handler = slog.NewJSONHandler(w, options)
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q, expected %q or %q", config.Format, FormatText, FormatJSON)
+	}
+
+	return slog.New(handler), level, nil
+}
+
+// ParseLevel parses a level name such as "debug" or "WARN". Empty is info.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if name == "" {
+		return level, nil
+	}
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+	}
+
+	return level, nil
+}
+
+// Get returns the current level
+func (l *Level) Get() slog.Level {
+	return l.level.Level()
+}
+
+// Configured returns the level the logger started with
+func (l *Level) Configured() slog.Level {
+	return l.configured
+}
+
+// Format returns the format logs are written in
+func (l *Level) Format() string {
+	return l.format
+}
+
+// Set changes the level by name
+func (l *Level) Set(name string) (slog.Level, error) {
+	level, err := ParseLevel(name)
+	if err != nil {
+		return l.Get(), err
+	}
+
+	l.level.Set(level)
+	return level, nil
+}
+
+// Reset goes back to the configured level
+func (l *Level) Reset() {
+	l.level.Set(l.configured)
+}
+
+// WatchSignals switches between debug and the configured level on every
+// SIGHUP until the context is cancelled, so a running process can be
+// debugged without a restart
+func (l *Level) WatchSignals(ctx context.Context, logger *slog.Logger) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hangups)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangups:
+				if l.Get() == slog.LevelDebug {
+					l.Reset()
+				} else {
+					l.level.Set(slog.LevelDebug)
+				}
+				// Logged at warn so the change shows at any level
+				logger.Warn("Changed log level on SIGHUP", "level", l.Get().String())
+			}
+		}
+	}()
+}
@@ -17,10 +17,10 @@ type GRPCAdapter struct {
 }
 
 // NewAdapter creates a new adapter that implements MessageQueue
-func NewAdapter(serverAddress, clientAddress string, logger *slog.Logger) (messagingtypes.MessageQueue, error) {
+func NewAdapter(serverAddress, clientAddress string, auth Auth, logger *slog.Logger) (messagingtypes.MessageQueue, error) {
 	return &GRPCAdapter{
-		client:        NewGRPCClient(),
-		server:        NewGRPCServer(),
+		client:        NewGRPCClient(auth.Token),
+		server:        NewGRPCServer(auth.Authenticate),
 		serverAddress: serverAddress,
 		clientAddress: clientAddress,
 		logger:        logger,
@@ -49,6 +49,16 @@ func (a *GRPCAdapter) Subscribe(topic string, handler func([]byte) error) {
 	a.server.Subscribe(topic, handler)
 }
 
+// OpenStream opens a stream to the other side's gRPC server
+func (a *GRPCAdapter) OpenStream(ctx context.Context) (messagingtypes.Stream, error) {
+	return a.client.OpenStream(ctx)
+}
+
+// ServeStreams sets the handler for streams opened by the other side
+func (a *GRPCAdapter) ServeStreams(handler messagingtypes.StreamHandler) {
+	a.server.ServeStreams(handler)
+}
+
 // Close closes the gRPC client connection
 func (a *GRPCAdapter) Close() error {
 	return a.client.Close()
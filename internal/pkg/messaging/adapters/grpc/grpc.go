@@ -6,15 +6,31 @@ import (
 	"net"
 	sync "sync"
 
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
 	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
+// tokenMetadataKey carries the sender's token with each event
+const tokenMetadataKey = "x-agent-token"
+
+// Auth carries credentials over the link. Token is sent with every
+// published event; Authenticate, when set, refuses events whose token it
+// doesn't accept. Streams are not authenticated.
+type Auth struct {
+	Token        func() string
+	Authenticate func(ctx context.Context, token string) error
+}
+
 // GRPCServer handles incoming gRPC requests
 type GRPCServer struct {
-	server   *grpc.Server
-	handlers map[string][]func([]byte) error
-	mu       sync.RWMutex
+	server        *grpc.Server
+	handlers      map[string][]func([]byte) error
+	streamHandler messagingtypes.StreamHandler
+	mu            sync.RWMutex
 	UnimplementedEventServiceServer
 }
 
@@ -22,20 +38,46 @@ type GRPCServer struct {
 type GRPCClient struct {
 	client EventServiceClient
 	conn   *grpc.ClientConn
+	token  func() string
 	mu     sync.RWMutex
 }
 
-// NewGRPCServer creates a new GRPCServer instance
-func NewGRPCServer() *GRPCServer {
+// NewGRPCServer creates a new GRPCServer instance, checking the token of
+// received events with authenticate if it is set
+func NewGRPCServer(authenticate func(ctx context.Context, token string) error) *GRPCServer {
+	var opts []grpc.ServerOption
+	if authenticate != nil {
+		opts = append(opts, grpc.UnaryInterceptor(authInterceptor(authenticate)))
+	}
+
 	return &GRPCServer{
-		server:   grpc.NewServer(),
+		server:   grpc.NewServer(opts...),
 		handlers: make(map[string][]func([]byte) error),
 	}
 }
 
-// NewGRPCClient creates a new GRPCClient instance
-func NewGRPCClient() *GRPCClient {
-	return &GRPCClient{}
+// NewGRPCClient creates a new GRPCClient instance that sends the token
+// returned by token, if set, with every event
+func NewGRPCClient(token func() string) *GRPCClient {
+	return &GRPCClient{token: token}
+}
+
+// authInterceptor refuses calls whose token authenticate doesn't accept
+func authInterceptor(authenticate func(ctx context.Context, token string) error) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var token string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(tokenMetadataKey); len(values) > 0 {
+				token = values[0]
+			}
+		}
+
+		if err := authenticate(ctx, token); err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(ctx, req)
+	}
 }
 
 // Start initializes and starts the gRPC server
@@ -47,6 +89,7 @@ func (s *GRPCServer) Start(ctx context.Context, address string) error {
 
 	// Register the server implementation
 	RegisterEventServiceServer(s.server, s)
+	s.server.RegisterService(&streamServiceDesc, s)
 
 	// Start the server in a goroutine
 	go func() {
@@ -129,7 +172,14 @@ func (c *GRPCClient) Publish(topic string, message []byte) error {
 		return fmt.Errorf("client not connected")
 	}
 
-	_, err := c.client.PublishEvent(context.Background(), &EventRequest{
+	ctx := context.Background()
+	if c.token != nil {
+		if token := c.token(); token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, tokenMetadataKey, token)
+		}
+	}
+
+	_, err := c.client.PublishEvent(ctx, &EventRequest{
 		Topic:   topic,
 		Payload: string(message),
 	})
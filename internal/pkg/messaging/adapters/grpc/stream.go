@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	context "context"
+	"encoding/json"
+	"fmt"
+
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+)
+
+// streamMethod is the full method name of the frame stream
+const streamMethod = "/grpc.StreamService/Stream"
+
+// jsonCodec encodes stream frames as JSON. Streams are not part of
+// message.proto, so frames go through this codec instead of generated code.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// streamServiceDesc describes the bidirectional frame stream served next to EventService
+var streamServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grpc.StreamService",
+	HandlerType: (*any)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       serveStream,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// serveStream hands an incoming stream to the server's stream handler
+func serveStream(srv any, stream grpc.ServerStream) error {
+	s := srv.(*GRPCServer)
+
+	s.mu.RLock()
+	handler := s.streamHandler
+	s.mu.RUnlock()
+
+	if handler == nil {
+		return status.Error(codes.Unimplemented, "streams are not served")
+	}
+
+	return handler(&frameStream{stream: stream})
+}
+
+// frameStream adapts a gRPC client or server stream to messagingtypes.Stream
+type frameStream struct {
+	stream interface {
+		Context() context.Context
+		SendMsg(m any) error
+		RecvMsg(m any) error
+	}
+}
+
+func (f *frameStream) Send(frame *messagingtypes.StreamFrame) error {
+	return f.stream.SendMsg(frame)
+}
+
+func (f *frameStream) Recv() (*messagingtypes.StreamFrame, error) {
+	frame := &messagingtypes.StreamFrame{}
+	if err := f.stream.RecvMsg(frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+func (f *frameStream) Context() context.Context {
+	return f.stream.Context()
+}
+
+// ServeStreams sets the handler for incoming streams
+func (s *GRPCServer) ServeStreams(handler messagingtypes.StreamHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.streamHandler = handler
+}
+
+// OpenStream opens a frame stream to the server
+func (c *GRPCClient) OpenStream(ctx context.Context) (messagingtypes.Stream, error) {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	stream, err := conn.NewStream(ctx, &streamServiceDesc.Streams[0], streamMethod,
+		grpc.CallContentSubtype(jsonCodec{}.Name()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stream: %w", err)
+	}
+
+	return &frameStream{stream: stream}, nil
+}
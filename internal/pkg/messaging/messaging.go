@@ -1,6 +1,7 @@
 package messaging
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 
@@ -24,17 +25,22 @@ type Config struct {
 	Type          ProviderType
 	ServerAddress string // Address for server to listen on
 	ClientAddress string // Address for client to connect to
+
+	// Token returns the credential sent with published events, if any
+	Token func() string
+	// Authenticate rejects received events whose token it refuses, if set
+	Authenticate func(ctx context.Context, token string) error
 }
 
 // NewClient creates a new messaging client based on the provider type
 func NewClient(config Config, logger *slog.Logger) (messagingtypes.MessageQueue, error) {
 	switch config.Type {
 	case GRPCProvider:
-		return grpc.NewAdapter(config.ServerAddress, config.ClientAddress, logger)
+		return grpc.NewAdapter(config.ServerAddress, config.ClientAddress, grpc.Auth{Token: config.Token, Authenticate: config.Authenticate}, logger)
 	case KafkaProvider:
 		// Future implementation
 		logger.Warn("Kafka provider not yet implemented, using gRPC")
-		return grpc.NewAdapter(config.ServerAddress, config.ClientAddress, logger)
+		return grpc.NewAdapter(config.ServerAddress, config.ClientAddress, grpc.Auth{Token: config.Token, Authenticate: config.Authenticate}, logger)
 	default:
 		return nil, fmt.Errorf("unknown provider type: %s", config.Type)
 	}
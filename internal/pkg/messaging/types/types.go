@@ -31,3 +31,29 @@ type MessageQueue interface {
 	// Stop stops the subscriber
 	Stop() error
 }
+
+// StreamFrame is one message on a bidirectional stream
+type StreamFrame struct {
+	Type string `json:"type"`
+	Data []byte `json:"data,omitempty"`
+}
+
+// Stream is a bidirectional stream of frames. It ends when its context is cancelled.
+type Stream interface {
+	Send(frame *StreamFrame) error
+	Recv() (*StreamFrame, error)
+	Context() context.Context
+}
+
+// StreamHandler serves a stream opened by the other side
+type StreamHandler func(stream Stream) error
+
+// Streamer is implemented by message queues that can carry long-lived
+// streams, such as logs, alongside events
+type Streamer interface {
+	// OpenStream opens a stream to the other side
+	OpenStream(ctx context.Context) (Stream, error)
+
+	// ServeStreams sets the handler for streams opened by the other side
+	ServeStreams(handler StreamHandler)
+}
@@ -0,0 +1,68 @@
+// Package notes holds dashboard-local notes and tags on cluster resources.
+// Notes are kept in the store only and never written to the cluster.
+package notes
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits keep notes small
+const (
+	MaxTextLen = 4096
+	MaxTags    = 20
+	maxTagLen  = 63
+)
+
+// Note is a note and tags a user attached to a resource
+type Note struct {
+	ID        string    `json:"id" bson:"_id"`
+	ClusterID string    `json:"clusterID" bson:"cluster_id"`
+	Kind      string    `json:"kind" bson:"kind"`
+	Namespace string    `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Name      string    `json:"name" bson:"name"`
+	Text      string    `json:"text" bson:"text"`
+	Tags      []string  `json:"tags,omitempty" bson:"tags,omitempty"`
+	Author    string    `json:"author" bson:"author"`
+	CreatedAt time.Time `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updated_at"`
+}
+
+// Validate checks the text and tags of a note, dropping repeated tags
+func (n *Note) Validate() error {
+	if n.Text == "" && len(n.Tags) == 0 {
+		return fmt.Errorf("a note needs text or tags")
+	}
+	if len(n.Text) > MaxTextLen {
+		return fmt.Errorf("text must be at most %d characters", MaxTextLen)
+	}
+	if len(n.Tags) > MaxTags {
+		return fmt.Errorf("at most %d tags are allowed", MaxTags)
+	}
+
+	seen := make(map[string]bool, len(n.Tags))
+	tags := make([]string, 0, len(n.Tags))
+	for _, tag := range n.Tags {
+		if tag == "" || len(tag) > maxTagLen {
+			return fmt.Errorf("tags must be 1 to %d characters", maxTagLen)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	n.Tags = tags
+
+	return nil
+}
+
+// Query selects notes. Empty fields match everything except ClusterID,
+// which is required.
+type Query struct {
+	ClusterID string
+	Kind      string
+	Namespace string
+	Name      string
+	Tag       string
+	Author    string
+}
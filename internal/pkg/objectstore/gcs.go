@@ -0,0 +1,256 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	gcsAPI           = "https://storage.googleapis.com/storage/v1"
+	gcsUploadAPI     = "https://storage.googleapis.com/upload/storage/v1"
+	gcsScope         = "https://www.googleapis.com/auth/devstorage.read_write"
+	gcsMetadataToken = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// gcsStore keeps objects in a Google Cloud Storage bucket using the JSON API
+type gcsStore struct {
+	bucket string
+	client *http.Client
+}
+
+// newGCSStore loads credentials from a service account key, falling back to
+// the metadata server when running with workload identity
+func newGCSStore(config Config) (*gcsStore, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+
+	var tokenSource oauth2.TokenSource
+	if credentialsFile := valueOrEnv(config.CredentialsFile, "GOOGLE_APPLICATION_CREDENTIALS"); credentialsFile != "" {
+		ts, err := gcsServiceAccountTokenSource(credentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load GCS credentials: %w", err)
+		}
+		tokenSource = ts
+	} else {
+		tokenSource = oauth2.ReuseTokenSource(nil, &gcsMetadataTokenSource{client: &http.Client{Timeout: 10 * time.Second}})
+	}
+
+	client := oauth2.NewClient(context.Background(), tokenSource)
+	client.Timeout = 5 * time.Minute
+
+	return &gcsStore{bucket: config.Bucket, client: client}, nil
+}
+
+func (s *gcsStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	query := url.Values{"uploadType": {"media"}, "name": {key}}
+	target := gcsUploadAPI + "/b/" + url.PathEscape(s.bucket) + "/o?" + query.Encode()
+
+	resp, err := s.do(ctx, http.MethodPost, target, contentType, data)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return drain(resp)
+}
+
+func (s *gcsStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key)+"?alt=media", "", nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	return io.ReadAll(resp.Body)
+}
+
+func (s *gcsStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	objects := make([]Object, 0)
+
+	query := url.Values{"prefix": {prefix}, "fields": {"items(name,size,updated),nextPageToken"}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, gcsAPI+"/b/"+url.PathEscape(s.bucket)+"/o?"+query.Encode(), "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		var page struct {
+			Items []struct {
+				Name    string    `json:"name"`
+				Size    string    `json:"size"` // uint64 encoded as a string
+				Updated time.Time `json:"updated"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid list response: %w", err)
+		}
+
+		for _, item := range page.Items {
+			size, _ := strconv.ParseInt(item.Size, 10, 64)
+			objects = append(objects, Object{Key: item.Name, Size: size, Modified: item.Updated})
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+
+	return objects, nil
+}
+
+func (s *gcsStore) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(key), "", nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return drain(resp)
+}
+
+// objectURL returns the JSON API URL of an object; names are escaped whole,
+// slashes included
+func (s *gcsStore) objectURL(key string) string {
+	return gcsAPI + "/b/" + url.PathEscape(s.bucket) + "/o/" + url.PathEscape(key)
+}
+
+// do sends a request, returning responses other than 2xx as errors
+func (s *gcsStore) do(ctx context.Context, method, target, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("gcs returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return resp, nil
+}
+
+// gcsServiceAccountTokenSource loads a service account key file
+func gcsServiceAccountTokenSource(path string) (oauth2.TokenSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key struct {
+		ClientEmail  string `json:"client_email"`
+		PrivateKey   string `json:"private_key"`
+		PrivateKeyID string `json:"private_key_id"`
+		TokenURI     string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+
+	tokenURL := key.TokenURI
+	if tokenURL == "" {
+		tokenURL = "https://oauth2.googleapis.com/token"
+	}
+
+	config := &jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       []string{gcsScope},
+		TokenURL:     tokenURL,
+	}
+
+	return config.TokenSource(context.Background()), nil
+}
+
+// gcsMetadataTokenSource fetches tokens for the workload identity service
+// account from the GCE metadata server
+type gcsMetadataTokenSource struct {
+	client *http.Client
+}
+
+// Token requests a new access token from the metadata server
+func (s *gcsMetadataTokenSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest(http.MethodGet, gcsMetadataToken, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach metadata server: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata server returned %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("invalid metadata token response: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
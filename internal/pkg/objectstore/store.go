@@ -0,0 +1,135 @@
+// Package objectstore keeps large blobs such as log archives, snapshot
+// manifests and support bundles out of MongoDB. Blobs are written to a
+// filesystem directory, an S3 (or S3-compatible) bucket or a GCS bucket.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Store types
+const (
+	TypeFilesystem = "filesystem"
+	TypeS3         = "s3"
+	TypeGCS        = "gcs"
+)
+
+// ErrNotFound is returned when a requested object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Config selects and configures the object store. An empty type disables it.
+type Config struct {
+	Type   string `yaml:"type"`
+	Path   string `yaml:"path"`   // Root directory for filesystem
+	Bucket string `yaml:"bucket"` // Bucket for s3 and gcs
+	Prefix string `yaml:"prefix"` // Prepended to every key
+
+	// S3 settings; credentials fall back to the AWS_* environment variables
+	Region          string `yaml:"region"`
+	Endpoint        string `yaml:"endpoint"` // S3-compatible endpoint, addressed path-style
+	AccessKeyID     string `yaml:"accessKeyID"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	SessionToken    string `yaml:"sessionToken"`
+
+	// GCS service account key; falls back to GOOGLE_APPLICATION_CREDENTIALS,
+	// then to the metadata server
+	CredentialsFile string `yaml:"credentialsFile"`
+}
+
+// Object describes a stored object
+type Object struct {
+	Key      string    `json:"key"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// Store reads and writes blobs by key. Keys are slash-separated paths.
+type Store interface {
+	// Put creates or replaces an object
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+
+	// Get returns the content of an object
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// List returns the objects whose keys start with prefix, sorted by key
+	List(ctx context.Context, prefix string) ([]Object, error)
+
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates the configured store, or returns nil when none is configured
+func New(config Config) (Store, error) {
+	var (
+		store Store
+		err   error
+	)
+
+	switch config.Type {
+	case "":
+		return nil, nil
+	case TypeFilesystem:
+		store, err = newFilesystemStore(config)
+	case TypeS3:
+		store, err = newS3Store(config)
+	case TypeGCS:
+		store, err = newGCSStore(config)
+	default:
+		return nil, fmt.Errorf("unknown object store type %q", config.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure %s object store: %w", config.Type, err)
+	}
+
+	if config.Prefix != "" {
+		store = &prefixedStore{store: store, prefix: strings.TrimSuffix(config.Prefix, "/") + "/"}
+	}
+
+	return store, nil
+}
+
+// validKey rejects keys that could escape the store's root
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return nil
+}
+
+// prefixedStore scopes a store to keys under a prefix
+type prefixedStore struct {
+	store  Store
+	prefix string
+}
+
+func (s *prefixedStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	return s.store.Put(ctx, s.prefix+key, data, contentType)
+}
+
+func (s *prefixedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.store.Get(ctx, s.prefix+key)
+}
+
+func (s *prefixedStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	objects, err := s.store.List(ctx, s.prefix+prefix)
+	if err != nil {
+		return nil, err
+	}
+	for i := range objects {
+		objects[i].Key = strings.TrimPrefix(objects[i].Key, s.prefix)
+	}
+	return objects, nil
+}
+
+func (s *prefixedStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, s.prefix+key)
+}
@@ -0,0 +1,110 @@
+// Package output renders JSON API responses as YAML or as compact text
+// tables, chosen with ?output= (or its alias ?format=) or the Accept header,
+// so the API can be used from a terminal without further processing.
+// Kubernetes objects are rendered by their handlers as re-applyable YAML
+// instead, which this package leaves alone.
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Output formats
+const (
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatTable = "table"
+)
+
+// MIMEApplicationYAML is the content type of YAML responses
+const MIMEApplicationYAML = "application/yaml"
+
+// Middleware converts successful JSON responses to the requested format.
+// ?output= takes precedence over the Accept header; responses that are not
+// JSON, such as downloads, logs and streams, are left alone.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, err := Negotiate(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if err := c.Next(); err != nil || format == FormatJSON {
+			return err
+		}
+
+		resp := c.Response()
+		if resp.StatusCode() < 200 || resp.StatusCode() > 299 || resp.IsBodyStream() {
+			return nil
+		}
+		if !strings.HasPrefix(string(resp.Header.ContentType()), fiber.MIMEApplicationJSON) {
+			return nil
+		}
+
+		value, err := decode(resp.Body())
+		if err != nil {
+			// Not valid JSON after all, send it as is
+			return nil
+		}
+
+		var body []byte
+		switch format {
+		case FormatYAML:
+			body, err = toYAML(value)
+			c.Set(fiber.HeaderContentType, MIMEApplicationYAML)
+		case FormatTable:
+			var buf bytes.Buffer
+			err = writeTable(&buf, value)
+			body = buf.Bytes()
+			c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": fmt.Sprintf("failed to render %s: %v", format, err),
+			})
+		}
+
+		resp.SetBody(body)
+		return nil
+	}
+}
+
+// Negotiate returns the format a request asks for: the output query
+// parameter, or else the best match of the Accept header, defaulting to JSON.
+// The format parameter of object exports is accepted as an alias of output;
+// other values of it are left to the handlers that define them, such as CSV
+// audit exports.
+func Negotiate(c *fiber.Ctx) (string, error) {
+	switch output := c.Query("output"); output {
+	case "":
+	case FormatJSON, FormatYAML, FormatTable:
+		return output, nil
+	default:
+		return "", fmt.Errorf("output must be json, yaml or table")
+	}
+
+	switch format := c.Query("format"); format {
+	case FormatJSON, FormatYAML, FormatTable:
+		return format, nil
+	}
+
+	if c.Get(fiber.HeaderAccept) == "" {
+		return FormatJSON, nil
+	}
+
+	// JSON is offered first so wildcards, as sent by browsers and curl, keep it
+	switch c.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationYAML, "application/x-yaml", "text/yaml", fiber.MIMETextPlain) {
+	case MIMEApplicationYAML, "application/x-yaml", "text/yaml":
+		return FormatYAML, nil
+	case fiber.MIMETextPlain:
+		return FormatTable, nil
+	default:
+		return FormatJSON, nil
+	}
+}
@@ -0,0 +1,394 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+)
+
+// maxCell is the longest a table cell gets before it is cut
+const maxCell = 60
+
+// writeTable writes a decoded response as text. Lists of Kubernetes objects
+// get kubectl-like columns, other lists get a column per scalar field and
+// single objects are written as fields followed by their nested lists.
+func writeTable(w io.Writer, value any) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	switch v := value.(type) {
+	case []any:
+		writeList(tw, v)
+	case *object:
+		if isKubernetesObject(v) {
+			writeKubernetesObjects(tw, []*object{v})
+			break
+		}
+		if err := writeObject(tw, v); err != nil {
+			return err
+		}
+	default:
+		fmt.Fprintln(tw, cell(v))
+	}
+
+	return tw.Flush()
+}
+
+// writeList writes a list as a table, or one line per item for scalars
+func writeList(w *tabwriter.Writer, list []any) {
+	if len(list) == 0 {
+		fmt.Fprintln(w, "No resources found.")
+		return
+	}
+
+	objects := make([]*object, 0, len(list))
+	for _, item := range list {
+		obj, ok := item.(*object)
+		if !ok {
+			// Not a list of objects
+			for _, item := range list {
+				fmt.Fprintln(w, cell(item))
+			}
+			return
+		}
+		objects = append(objects, obj)
+	}
+
+	if isKubernetesObject(objects[0]) {
+		writeKubernetesObjects(w, objects)
+		return
+	}
+	writeObjects(w, objects)
+}
+
+// writeObject writes the scalar fields of an object as name and value, then
+// each nested list of objects as its own table
+func writeObject(w *tabwriter.Writer, obj *object) error {
+	nested := make([]string, 0)
+	for _, key := range obj.keys {
+		value := obj.get(key)
+		if isObjectList(value) {
+			nested = append(nested, key)
+			continue
+		}
+		fmt.Fprintf(w, "%s:\t%s\n", header(key), cell(value))
+	}
+
+	for _, key := range nested {
+		// Tables are aligned separately, so flush what came before
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n%s:\n", header(key))
+		writeList(w, obj.get(key).([]any))
+	}
+
+	return nil
+}
+
+// writeObjects writes a column for every field that holds a scalar in at
+// least one object, in the order the fields first appear
+func writeObjects(w *tabwriter.Writer, objects []*object) {
+	columns := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, obj := range objects {
+		for _, key := range obj.keys {
+			if seen[key] || !isScalarish(obj.get(key)) {
+				continue
+			}
+			seen[key] = true
+			columns = append(columns, key)
+		}
+	}
+
+	if len(columns) == 0 {
+		// Nothing tabular, fall back to one compact JSON document per line
+		for _, obj := range objects {
+			fmt.Fprintln(w, cell(obj))
+		}
+		return
+	}
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = header(column)
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, obj := range objects {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = cell(obj.get(column))
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+}
+
+// writeKubernetesObjects writes NAMESPACE, NAME, READY, STATUS, RESTARTS and
+// AGE columns, leaving out the ones no object has
+func writeKubernetesObjects(w *tabwriter.Writer, objects []*object) {
+	type column struct {
+		name  string
+		value func(*object) string
+	}
+
+	all := []column{
+		{"NAMESPACE", func(o *object) string { return text(lookup(o, "metadata", "namespace")) }},
+		{"NAME", func(o *object) string { return text(lookup(o, "metadata", "name")) }},
+		{"READY", ready},
+		{"STATUS", status},
+		{"RESTARTS", restarts},
+		{"AGE", func(o *object) string { return age(text(lookup(o, "metadata", "creationTimestamp"))) }},
+	}
+
+	columns := make([]column, 0, len(all))
+	for _, col := range all {
+		for _, obj := range objects {
+			if col.value(obj) != "" {
+				columns = append(columns, col)
+				break
+			}
+		}
+	}
+
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.name
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, obj := range objects {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = col.value(obj)
+			if cells[i] == "" {
+				cells[i] = "<none>"
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+}
+
+// ready is ready/total containers for pods and ready/desired replicas for workloads
+func ready(obj *object) string {
+	if statuses, ok := lookup(obj, "status", "containerStatuses").([]any); ok {
+		readyCount := 0
+		for _, status := range statuses {
+			if s, ok := status.(*object); ok && s.get("ready") == true {
+				readyCount++
+			}
+		}
+		return fmt.Sprintf("%d/%d", readyCount, len(statuses))
+	}
+
+	if replicas := lookup(obj, "spec", "replicas"); replicas != nil {
+		readyReplicas := text(lookup(obj, "status", "readyReplicas"))
+		if readyReplicas == "" {
+			readyReplicas = "0"
+		}
+		return readyReplicas + "/" + text(replicas)
+	}
+
+	return ""
+}
+
+// status is the waiting or terminated reason of a pod's first unhealthy
+// container, or else the object's phase
+func status(obj *object) string {
+	if statuses, ok := lookup(obj, "status", "containerStatuses").([]any); ok {
+		for _, status := range statuses {
+			s, ok := status.(*object)
+			if !ok {
+				continue
+			}
+			if reason := text(lookup(s, "state", "waiting", "reason")); reason != "" {
+				return reason
+			}
+			if reason := text(lookup(s, "state", "terminated", "reason")); reason != "" {
+				return reason
+			}
+		}
+	}
+
+	if lookup(obj, "metadata", "deletionTimestamp") != nil {
+		return "Terminating"
+	}
+
+	return text(lookup(obj, "status", "phase"))
+}
+
+// restarts is the total restart count of a pod's containers
+func restarts(obj *object) string {
+	statuses, ok := lookup(obj, "status", "containerStatuses").([]any)
+	if !ok {
+		return ""
+	}
+
+	total := int64(0)
+	for _, status := range statuses {
+		if s, ok := status.(*object); ok {
+			if count, ok := s.get("restartCount").(json.Number); ok {
+				n, _ := count.Int64()
+				total += n
+			}
+		}
+	}
+	return fmt.Sprint(total)
+}
+
+// age formats the time since an RFC 3339 timestamp the way kubectl does
+func age(timestamp string) string {
+	created, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return ""
+	}
+
+	d := time.Since(created)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// isKubernetesObject reports whether an object has Kubernetes object metadata
+func isKubernetesObject(obj *object) bool {
+	return text(lookup(obj, "metadata", "name")) != ""
+}
+
+// isObjectList reports whether a value is a non-empty list of objects
+func isObjectList(value any) bool {
+	list, ok := value.([]any)
+	if !ok || len(list) == 0 {
+		return false
+	}
+	_, ok = list[0].(*object)
+	return ok
+}
+
+// isScalarish reports whether a value fits in a cell: a scalar or a list of scalars
+func isScalarish(value any) bool {
+	switch v := value.(type) {
+	case *object:
+		return false
+	case []any:
+		return !isObjectList(v)
+	default:
+		return true
+	}
+}
+
+// lookup follows a path of object keys, returning nil when it leads nowhere
+func lookup(value any, path ...string) any {
+	for _, key := range path {
+		obj, ok := value.(*object)
+		if !ok {
+			return nil
+		}
+		value = obj.get(key)
+	}
+	return value
+}
+
+// text returns a scalar as a string, or "" for anything else
+func text(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		return ""
+	}
+}
+
+// cell formats a value for a table cell: scalars as is, lists of scalars
+// comma separated and anything else as compact JSON, cut to maxCell
+func cell(value any) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return "<none>"
+	case []any:
+		if isObjectList(v) {
+			s = fmt.Sprintf("%d items", len(v))
+			break
+		}
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = cell(item)
+		}
+		s = strings.Join(items, ",")
+	case *object:
+		var buf bytes.Buffer
+		writeCompact(&buf, v)
+		s = buf.String()
+	default:
+		s = text(v)
+	}
+
+	if s == "" {
+		return "<none>"
+	}
+	// Keep each row on one line
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > maxCell {
+		s = s[:maxCell-3] + "..."
+	}
+	return s
+}
+
+// writeCompact writes a decoded value as compact JSON
+func writeCompact(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case *object:
+		buf.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			data, _ := json.Marshal(key)
+			buf.Write(data)
+			buf.WriteByte(':')
+			writeCompact(buf, v.values[key])
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCompact(buf, item)
+		}
+		buf.WriteByte(']')
+	default:
+		data, _ := json.Marshal(v)
+		buf.Write(data)
+	}
+}
+
+// header turns a field name such as lastRunAt or clusterID into LAST RUN AT
+// or CLUSTER ID
+func header(key string) string {
+	var b strings.Builder
+	runes := []rune(key)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,324 @@
+// Package podexec runs commands in and attaches to containers over the API
+// server's WebSocket streaming protocol.
+package podexec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// Channel protocols, newest first. v5 can signal the end of stdin; with v4
+// stdin stays open until the stream ends.
+const (
+	protocolV5 = "v5.channel.k8s.io"
+	protocolV4 = "v4.channel.k8s.io"
+)
+
+// Channels are the first byte of every message
+const (
+	stdinChannel  = 0
+	stdoutChannel = 1
+	stderrChannel = 2
+	errorChannel  = 3
+	resizeChannel = 4
+	closeChannel  = 255
+)
+
+// handshakeTimeout bounds connecting to the API server
+const handshakeTimeout = 30 * time.Second
+
+// Size is a terminal size
+type Size struct {
+	Width  uint16 `json:"Width"`
+	Height uint16 `json:"Height"`
+}
+
+// Options select the container and the streams to connect. Nil streams are
+// not requested. With a TTY, stderr is merged into stdout. Attach ignores
+// Command.
+type Options struct {
+	Container string
+	Command   []string
+	TTY       bool
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+	Resize    <-chan Size
+}
+
+// ExitError reports a command that exited with a non-zero code
+type ExitError struct {
+	Code    int
+	Message string
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command exited with code %d: %s", e.Code, e.Message)
+}
+
+// Exec runs a command in a container, returning once it exits
+func Exec(ctx context.Context, config *rest.Config, namespace, pod string, opts Options) error {
+	if len(opts.Command) == 0 {
+		return errors.New("missing command")
+	}
+	return stream(ctx, config, "exec", namespace, pod, opts)
+}
+
+// Attach connects to the main process of a running container, returning
+// once it exits or detaches
+func Attach(ctx context.Context, config *rest.Config, namespace, pod string, opts Options) error {
+	return stream(ctx, config, "attach", namespace, pod, opts)
+}
+
+// stream connects to a pod subresource and copies the streams until the
+// server closes the connection or the context is done
+func stream(ctx context.Context, config *rest.Config, subresource, namespace, pod string, opts Options) error {
+	target, err := streamURL(config, subresource, namespace, pod, opts)
+	if err != nil {
+		return err
+	}
+
+	headers, err := authHeaders(config, target)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
+	dialer := websocket.Dialer{
+		TLSClientConfig:  tlsConfig,
+		Subprotocols:     []string{protocolV5, protocolV4},
+		HandshakeTimeout: handshakeTimeout,
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, target.String(), headers)
+	if err != nil {
+		return dialError(subresource, resp, err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// Unblock the reader when the caller gives up
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	var mu sync.Mutex
+	write := func(channel byte, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return conn.WriteMessage(websocket.BinaryMessage, append([]byte{channel}, data...))
+	}
+
+	if opts.Stdin != nil {
+		go func() {
+			buf := make([]byte, 32*1024)
+			for {
+				n, err := opts.Stdin.Read(buf)
+				if n > 0 {
+					if write(stdinChannel, buf[:n]) != nil {
+						return
+					}
+				}
+				if err != nil {
+					if conn.Subprotocol() == protocolV5 {
+						_ = write(closeChannel, []byte{stdinChannel})
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	if opts.Resize != nil {
+		go func() {
+			for size := range opts.Resize {
+				data, err := json.Marshal(size)
+				if err != nil {
+					continue
+				}
+				if write(resizeChannel, data) != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	var status bytes.Buffer
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure) && !errors.Is(err, io.EOF) && status.Len() == 0 {
+				return fmt.Errorf("%s stream failed: %w", subresource, err)
+			}
+			return statusError(status.Bytes())
+		}
+		if len(message) == 0 {
+			continue
+		}
+
+		var out io.Writer
+		switch message[0] {
+		case stdoutChannel:
+			out = opts.Stdout
+		case stderrChannel:
+			out = opts.Stderr
+		case errorChannel:
+			out = &status
+		}
+		if out == nil || len(message) == 1 {
+			continue
+		}
+
+		if _, err := out.Write(message[1:]); err != nil {
+			return err
+		}
+	}
+}
+
+// streamURL builds the WebSocket URL of a pod subresource
+func streamURL(config *rest.Config, subresource, namespace, pod string, opts Options) (*url.URL, error) {
+	host, _, err := rest.DefaultServerUrlFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster URL: %w", err)
+	}
+
+	target := *host
+	switch target.Scheme {
+	case "https":
+		target.Scheme = "wss"
+	case "http":
+		target.Scheme = "ws"
+	}
+	target.Path = strings.TrimSuffix(target.Path, "/") +
+		fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/%s", url.PathEscape(namespace), url.PathEscape(pod), subresource)
+
+	query := url.Values{}
+	if opts.Container != "" {
+		query.Set("container", opts.Container)
+	}
+	for _, arg := range opts.Command {
+		query.Add("command", arg)
+	}
+	if opts.Stdin != nil {
+		query.Set("stdin", "true")
+	}
+	if opts.Stdout != nil {
+		query.Set("stdout", "true")
+	}
+	if opts.Stderr != nil && !opts.TTY {
+		query.Set("stderr", "true")
+	}
+	if opts.TTY {
+		query.Set("tty", "true")
+	}
+	target.RawQuery = query.Encode()
+
+	return &target, nil
+}
+
+// authHeaders returns the headers client-go would send to authenticate a
+// request, such as a bearer token, exec plugin credentials or impersonation
+func authHeaders(config *rest.Config, target *url.URL) (http.Header, error) {
+	var headers http.Header
+	capture := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		headers = req.Header.Clone()
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+
+	rt, err := rest.HTTPWrappersForConfig(config, capture)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure authentication: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	// The dialer sets its own WebSocket headers
+	for _, name := range []string{"Connection", "Upgrade", "Sec-Websocket-Key", "Sec-Websocket-Version", "Sec-Websocket-Protocol", "Sec-Websocket-Extensions"} {
+		headers.Del(name)
+	}
+	return headers, nil
+}
+
+// roundTripperFunc adapts a function to an http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// dialError explains a failed handshake with the API server's status message when it sent one
+func dialError(subresource string, resp *http.Response, err error) error {
+	if resp == nil {
+		return fmt.Errorf("failed to open %s stream: %w", subresource, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var status metav1.Status
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(body, &status) == nil && status.Message != "" {
+		return fmt.Errorf("failed to open %s stream: %s", subresource, status.Message)
+	}
+	return fmt.Errorf("failed to open %s stream: %s", subresource, resp.Status)
+}
+
+// statusError converts the status sent on the error channel to an error
+func statusError(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	var status metav1.Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return fmt.Errorf("stream error: %s", data)
+	}
+	if status.Status == metav1.StatusSuccess {
+		return nil
+	}
+
+	if status.Reason == "NonZeroExitCode" && status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			if cause.Type != "ExitCode" {
+				continue
+			}
+
+			var code int
+			if _, err := fmt.Sscanf(cause.Message, "%d", &code); err == nil {
+				return &ExitError{Code: code, Message: status.Message}
+			}
+		}
+	}
+
+	return errors.New(status.Message)
+}
@@ -0,0 +1,81 @@
+// Package preferences holds per-user settings the UIs keep on the server,
+// such as favorite clusters and namespaces, so they follow a user between
+// browsers and the TUI.
+package preferences
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits keep a user's preferences small
+const (
+	MaxFavorites = 100
+	MaxTables    = 50
+	MaxColumns   = 50
+	maxValueLen  = 253
+)
+
+// Favorite is a pinned cluster, or a namespace in it when Namespace is set
+type Favorite struct {
+	ClusterID string `json:"clusterID" bson:"cluster_id"`
+	Namespace string `json:"namespace,omitempty" bson:"namespace,omitempty"`
+}
+
+// Preferences are the settings of one user, keyed by their identity
+type Preferences struct {
+	User      string     `json:"user" bson:"_id"`
+	Favorites []Favorite `json:"favorites" bson:"favorites"`
+	// DefaultView is the view a UI opens on start, e.g. "pods" or "problems"
+	DefaultView string `json:"defaultView,omitempty" bson:"default_view,omitempty"`
+	// Columns are the visible columns of each table, in order, by table name
+	Columns   map[string][]string `json:"columns,omitempty" bson:"columns,omitempty"`
+	UpdatedAt time.Time           `json:"updatedAt" bson:"updated_at"`
+}
+
+// Defaults returns the preferences of a user who hasn't saved any
+func Defaults(user string) Preferences {
+	return Preferences{User: user, Favorites: make([]Favorite, 0)}
+}
+
+// Validate checks preferences against the limits and drops repeated favorites
+func (p *Preferences) Validate() error {
+	if len(p.Favorites) > MaxFavorites {
+		return fmt.Errorf("at most %d favorites are allowed", MaxFavorites)
+	}
+
+	seen := make(map[Favorite]bool, len(p.Favorites))
+	favorites := make([]Favorite, 0, len(p.Favorites))
+	for _, favorite := range p.Favorites {
+		if favorite.ClusterID == "" {
+			return fmt.Errorf("favorites require a clusterID")
+		}
+		if len(favorite.ClusterID) > maxValueLen || len(favorite.Namespace) > maxValueLen {
+			return fmt.Errorf("favorite names must be at most %d characters", maxValueLen)
+		}
+		if seen[favorite] {
+			continue
+		}
+		seen[favorite] = true
+		favorites = append(favorites, favorite)
+	}
+	p.Favorites = favorites
+
+	if len(p.DefaultView) > maxValueLen {
+		return fmt.Errorf("defaultView must be at most %d characters", maxValueLen)
+	}
+
+	if len(p.Columns) > MaxTables {
+		return fmt.Errorf("column settings are allowed for at most %d tables", MaxTables)
+	}
+	for table, columns := range p.Columns {
+		if table == "" || len(table) > maxValueLen {
+			return fmt.Errorf("table names must be 1 to %d characters", maxValueLen)
+		}
+		if len(columns) > MaxColumns {
+			return fmt.Errorf("table %s has more than %d columns", table, MaxColumns)
+		}
+	}
+
+	return nil
+}
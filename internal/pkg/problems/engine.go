@@ -0,0 +1,225 @@
+package problems
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets/certificates"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceLister reads stored resources
+type ResourceLister interface {
+	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
+}
+
+// Engine periodically evaluates rules against cluster resources and keeps
+// the set of open problems per cluster
+type Engine struct {
+	clusterManager *cluster.Manager
+	store          ResourceLister
+	rules          []Rule
+	config         Config
+	logger         *slog.Logger
+
+	mu       sync.Mutex
+	problems map[string]map[string]Problem
+}
+
+// NewEngine creates a new engine with the built-in rules, minus the ones
+// disabled in config, filling in defaults for unset durations
+func NewEngine(clusterManager *cluster.Manager, store ResourceLister, config Config, logger *slog.Logger) *Engine {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.PendingGrace <= 0 {
+		config.PendingGrace = DefaultPendingGrace
+	}
+	if config.CertificateWarning <= 0 {
+		config.CertificateWarning = 30 * 24 * time.Hour
+	}
+
+	rules := make([]Rule, 0)
+	for _, rule := range DefaultRules() {
+		if !slices.Contains(config.Disabled, rule.Name()) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return &Engine{
+		clusterManager: clusterManager,
+		store:          store,
+		rules:          rules,
+		config:         config,
+		logger:         logger,
+		problems:       make(map[string]map[string]Problem),
+	}
+}
+
+// Start evaluates every connected cluster on each interval until the context
+// is cancelled, calling onChange when a problem appears or is resolved
+func (e *Engine) Start(ctx context.Context, onChange func(Event)) {
+	go func() {
+		ticker := time.NewTicker(e.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.evaluateAll(ctx, onChange)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	e.logger.Info("Problem detection started", "interval", e.config.Interval, "rules", len(e.rules))
+}
+
+// Problems returns the open problems of a cluster, evaluating it first if it
+// has not been seen yet
+func (e *Engine) Problems(ctx context.Context, clusterID string) ([]Problem, error) {
+	e.mu.Lock()
+	_, ok := e.problems[clusterID]
+	e.mu.Unlock()
+
+	if !ok {
+		if _, err := e.Evaluate(ctx, clusterID); err != nil {
+			return nil, err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	problems := make([]Problem, 0, len(e.problems[clusterID]))
+	for _, problem := range e.problems[clusterID] {
+		problems = append(problems, problem)
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].ID < problems[j].ID
+	})
+
+	return problems, nil
+}
+
+// Evaluate runs every rule against a cluster, updates its open problems and
+// returns the resulting appear and resolve events
+func (e *Engine) Evaluate(ctx context.Context, clusterID string) ([]Event, error) {
+	snapshot, err := e.snapshot(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]Problem)
+	for _, rule := range e.rules {
+		for _, problem := range rule.Evaluate(snapshot) {
+			problem.ClusterID = clusterID
+			problem.ID = problemID(problem)
+			found[problem.ID] = problem
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	previous := e.problems[clusterID]
+	events := make([]Event, 0)
+
+	for id, problem := range found {
+		if existing, ok := previous[id]; ok {
+			problem.Since = existing.Since
+		} else {
+			problem.Since = snapshot.Now
+			events = append(events, Event{Type: EventAppeared, Problem: problem, Timestamp: snapshot.Now})
+		}
+		problem.LastSeen = snapshot.Now
+		found[id] = problem
+	}
+
+	for id, problem := range previous {
+		if _, ok := found[id]; !ok {
+			events = append(events, Event{Type: EventResolved, Problem: problem, Timestamp: snapshot.Now})
+		}
+	}
+
+	e.problems[clusterID] = found
+	return events, nil
+}
+
+// evaluateAll evaluates every connected cluster
+func (e *Engine) evaluateAll(ctx context.Context, onChange func(Event)) {
+	for clusterID, conn := range e.clusterManager.GetConnections() {
+		// Detection must not connect clusters nobody is using
+		if !conn.IsConnected() {
+			continue
+		}
+
+		events, err := e.Evaluate(ctx, clusterID)
+		if err != nil {
+			e.logger.Debug("Failed to evaluate cluster problems", "clusterID", clusterID, "error", err)
+			continue
+		}
+
+		for _, event := range events {
+			onChange(event)
+		}
+	}
+}
+
+// snapshot gathers the resources rules are evaluated against. Pods come from
+// the store, which the agents keep current; deployments and claims are not
+// mirrored and are listed from the cluster.
+func (e *Engine) snapshot(ctx context.Context, clusterID string) (*Snapshot, error) {
+	conn, err := e.clusterManager.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("cluster not found: %w", err)
+	}
+
+	snapshot := &Snapshot{
+		ClusterID:          clusterID,
+		Now:                time.Now(),
+		PendingGrace:       e.config.PendingGrace,
+		CertificateWarning: e.config.CertificateWarning,
+	}
+
+	if err := e.store.List(ctx, clusterID, "", "Pod", &snapshot.Pods); err != nil {
+		return nil, fmt.Errorf("failed to list stored pods: %w", err)
+	}
+
+	deployments, err := conn.Client.AppsV1().Deployments(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	snapshot.Deployments = deployments.Items
+
+	claims, err := conn.Client.CoreV1().PersistentVolumeClaims(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+	snapshot.PersistentVolumeClaims = claims.Items
+
+	secrets, err := conn.Client.CoreV1().Secrets(corev1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: certificates.TLSSecretSelector(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TLS secrets: %w", err)
+	}
+	snapshot.TLSSecrets = secrets.Items
+
+	return snapshot, nil
+}
+
+// problemID identifies a problem across evaluations
+func problemID(problem Problem) string {
+	return strings.Join([]string{problem.Rule, problem.Kind, problem.Namespace, problem.Name, problem.Container}, "/")
+}
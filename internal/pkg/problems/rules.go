@@ -0,0 +1,297 @@
+package problems
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets/certificates"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultRules returns the built-in rules
+func DefaultRules() []Rule {
+	return []Rule{
+		waitingReasonRule{name: "CrashLoopBackOff", reasons: []string{"CrashLoopBackOff"}, severity: SeverityCritical},
+		waitingReasonRule{name: "ImagePullBackOff", reasons: []string{"ImagePullBackOff", "ErrImagePull", "InvalidImageName"}, severity: SeverityCritical},
+		oomKilledRule{},
+		failedProbeRule{},
+		pendingPVCRule{},
+		deploymentReplicasRule{},
+		certificateExpiryRule{},
+	}
+}
+
+// waitingReasonRule reports containers stuck waiting for one of a set of reasons
+type waitingReasonRule struct {
+	name     string
+	reasons  []string
+	severity string
+}
+
+func (r waitingReasonRule) Name() string {
+	return r.name
+}
+
+func (r waitingReasonRule) Evaluate(snapshot *Snapshot) []Problem {
+	var problems []Problem
+
+	for i := range snapshot.Pods {
+		pod := &snapshot.Pods[i]
+		for _, status := range containerStatuses(pod) {
+			waiting := status.State.Waiting
+			if waiting == nil || !slices.Contains(r.reasons, waiting.Reason) {
+				continue
+			}
+
+			message := waiting.Message
+			if message == "" {
+				message = fmt.Sprintf("container %s is waiting: %s", status.Name, waiting.Reason)
+			}
+
+			problems = append(problems, Problem{
+				Rule:      r.name,
+				Severity:  r.severity,
+				Kind:      "Pod",
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Container: status.Name,
+				Message:   message,
+			})
+		}
+	}
+
+	return problems
+}
+
+// oomKilledRule reports containers whose last run was killed for exceeding
+// their memory limit
+type oomKilledRule struct{}
+
+func (oomKilledRule) Name() string {
+	return "OOMKilled"
+}
+
+func (r oomKilledRule) Evaluate(snapshot *Snapshot) []Problem {
+	var problems []Problem
+
+	for i := range snapshot.Pods {
+		pod := &snapshot.Pods[i]
+		for _, status := range containerStatuses(pod) {
+			terminated := status.LastTerminationState.Terminated
+			if terminated == nil || terminated.Reason != "OOMKilled" {
+				continue
+			}
+
+			problems = append(problems, Problem{
+				Rule:      r.Name(),
+				Severity:  SeverityWarning,
+				Kind:      "Pod",
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Container: status.Name,
+				Message:   fmt.Sprintf("container %s was OOMKilled, exceeding its memory limit", status.Name),
+			})
+		}
+	}
+
+	return problems
+}
+
+// failedProbeRule reports running containers that have not passed their
+// readiness probe within the grace period
+type failedProbeRule struct{}
+
+func (failedProbeRule) Name() string {
+	return "FailedProbe"
+}
+
+func (r failedProbeRule) Evaluate(snapshot *Snapshot) []Problem {
+	var problems []Problem
+
+	for i := range snapshot.Pods {
+		pod := &snapshot.Pods[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			if container.ReadinessProbe == nil {
+				continue
+			}
+
+			status, ok := containerStatus(pod, container.Name)
+			if !ok || status.Ready || status.State.Running == nil {
+				continue
+			}
+
+			if snapshot.Now.Sub(status.State.Running.StartedAt.Time) < snapshot.PendingGrace {
+				continue
+			}
+
+			problems = append(problems, Problem{
+				Rule:      r.Name(),
+				Severity:  SeverityWarning,
+				Kind:      "Pod",
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Container: container.Name,
+				Message:   fmt.Sprintf("container %s is running but failing its readiness probe", container.Name),
+			})
+		}
+	}
+
+	return problems
+}
+
+// pendingPVCRule reports claims that have not been bound within the grace period
+type pendingPVCRule struct{}
+
+func (pendingPVCRule) Name() string {
+	return "PendingPVC"
+}
+
+func (r pendingPVCRule) Evaluate(snapshot *Snapshot) []Problem {
+	var problems []Problem
+
+	for _, claim := range snapshot.PersistentVolumeClaims {
+		if claim.Status.Phase != corev1.ClaimPending {
+			continue
+		}
+
+		if snapshot.Now.Sub(claim.CreationTimestamp.Time) < snapshot.PendingGrace {
+			continue
+		}
+
+		problems = append(problems, Problem{
+			Rule:      r.Name(),
+			Severity:  SeverityWarning,
+			Kind:      "PersistentVolumeClaim",
+			Namespace: claim.Namespace,
+			Name:      claim.Name,
+			Message:   "persistent volume claim is not bound",
+		})
+	}
+
+	return problems
+}
+
+// deploymentReplicasRule reports deployments with fewer available replicas than desired
+type deploymentReplicasRule struct{}
+
+func (deploymentReplicasRule) Name() string {
+	return "DeploymentReplicas"
+}
+
+func (r deploymentReplicasRule) Evaluate(snapshot *Snapshot) []Problem {
+	var problems []Problem
+
+	for _, deployment := range snapshot.Deployments {
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+
+		if desired == 0 || deployment.Status.AvailableReplicas >= desired {
+			continue
+		}
+
+		severity := SeverityWarning
+		if deployment.Status.AvailableReplicas == 0 {
+			severity = SeverityCritical
+		}
+
+		problems = append(problems, Problem{
+			Rule:      r.Name(),
+			Severity:  severity,
+			Kind:      "Deployment",
+			Namespace: deployment.Namespace,
+			Name:      deployment.Name,
+			Message:   fmt.Sprintf("%d of %d replicas available", deployment.Status.AvailableReplicas, desired),
+		})
+	}
+
+	return problems
+}
+
+// certificateCritical is how close to expiry a certificate becomes critical
+const certificateCritical = 7 * 24 * time.Hour
+
+// certificateExpiryRule reports TLS certificates that expire within the warning window
+type certificateExpiryRule struct{}
+
+func (certificateExpiryRule) Name() string {
+	return "CertificateExpiry"
+}
+
+func (r certificateExpiryRule) Evaluate(snapshot *Snapshot) []Problem {
+	var problems []Problem
+
+	for i := range snapshot.TLSSecrets {
+		cert, err := certificates.Parse(&snapshot.TLSSecrets[i], snapshot.Now)
+		if err != nil {
+			continue
+		}
+
+		remaining := cert.NotAfter.Sub(snapshot.Now)
+		if remaining > snapshot.CertificateWarning {
+			continue
+		}
+
+		severity := SeverityWarning
+		message := fmt.Sprintf("certificate %s expires in %d days", cert.Subject, cert.DaysRemaining)
+		switch {
+		case cert.Expired:
+			severity = SeverityCritical
+			message = fmt.Sprintf("certificate %s expired on %s", cert.Subject, cert.NotAfter.Format(time.DateOnly))
+		case remaining <= certificateCritical:
+			severity = SeverityCritical
+		}
+
+		problems = append(problems, Problem{
+			Rule:      r.Name(),
+			Severity:  severity,
+			Kind:      "Secret",
+			Namespace: cert.Namespace,
+			Name:      cert.Secret,
+			Message:   message,
+		})
+	}
+
+	return problems
+}
+
+// PodProblems evaluates the built-in rules against a single pod, for flagging
+// pods without a snapshot of their whole cluster
+func PodProblems(pod corev1.Pod, now time.Time) []Problem {
+	snapshot := &Snapshot{
+		Now:          now,
+		PendingGrace: DefaultPendingGrace,
+		Pods:         []corev1.Pod{pod},
+	}
+
+	var problems []Problem
+	for _, rule := range DefaultRules() {
+		problems = append(problems, rule.Evaluate(snapshot)...)
+	}
+
+	return problems
+}
+
+// containerStatuses returns the statuses of a pod's init and regular containers
+func containerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// containerStatus returns the status of a named container
+func containerStatus(pod *corev1.Pod, name string) (corev1.ContainerStatus, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == name {
+			return status, true
+		}
+	}
+
+	return corev1.ContainerStatus{}, false
+}
@@ -0,0 +1,93 @@
+package problems
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Problem severities
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// DefaultPendingGrace is how long a PVC or unready pod may settle by default
+const DefaultPendingGrace = 5 * time.Minute
+
+// Problem event types
+const (
+	EventAppeared = "appeared"
+	EventResolved = "resolved"
+)
+
+// Config controls the background problem detector
+type Config struct {
+	Interval time.Duration `yaml:"interval"`
+	// Disabled lists rule names that are not evaluated
+	Disabled []string `yaml:"disabled"`
+	// PendingGrace is how long a PVC or unready pod may settle before it is reported
+	PendingGrace time.Duration `yaml:"pendingGrace"`
+	// CertificateWarning is how long before expiry a TLS certificate is reported
+	CertificateWarning time.Duration `yaml:"certificateWarning"`
+}
+
+// Problem is an issue detected on a resource
+type Problem struct {
+	ID        string    `json:"id"`
+	ClusterID string    `json:"clusterID"`
+	Rule      string    `json:"rule"`
+	Severity  string    `json:"severity"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Container string    `json:"container,omitempty"`
+	Message   string    `json:"message"`
+	Since     time.Time `json:"since"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Event reports a problem appearing or being resolved
+type Event struct {
+	Type      string    `json:"type"`
+	Problem   Problem   `json:"problem"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Snapshot is the state of a cluster a rule is evaluated against
+type Snapshot struct {
+	ClusterID              string
+	Now                    time.Time
+	PendingGrace           time.Duration
+	CertificateWarning     time.Duration
+	Pods                   []corev1.Pod
+	Deployments            []appsv1.Deployment
+	PersistentVolumeClaims []corev1.PersistentVolumeClaim
+	TLSSecrets             []corev1.Secret
+}
+
+// Rule detects one kind of problem
+type Rule interface {
+	// Name identifies the rule in problems and configuration
+	Name() string
+
+	// Evaluate returns the problems found in a snapshot
+	Evaluate(snapshot *Snapshot) []Problem
+}
+
+// Score rates the health of a set of resources from 0 to 100, taking
+// points off for every distinct problem by severity
+func Score(problems []Problem) int {
+	score := 100
+	for _, problem := range problems {
+		switch problem.Severity {
+		case SeverityCritical:
+			score -= 10
+		default:
+			score -= 3
+		}
+	}
+
+	return max(score, 0)
+}
@@ -0,0 +1,173 @@
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned for clusters without a Prometheus datasource
+var ErrNotConfigured = errors.New("prometheus not configured for cluster")
+
+// DatasourceConfig describes how to reach a cluster's Prometheus
+type DatasourceConfig struct {
+	URL                   string `yaml:"url"`
+	BearerToken           string `yaml:"bearerToken"`
+	BearerTokenFile       string `yaml:"bearerTokenFile"`
+	InsecureSkipTLSVerify bool   `yaml:"insecureSkipTLSVerify"`
+}
+
+// Config maps cluster IDs to Prometheus datasources. Default is used for
+// clusters without an entry when its URL is set.
+type Config struct {
+	Default  DatasourceConfig            `yaml:"default"`
+	Clusters map[string]DatasourceConfig `yaml:"clusters"`
+	Timeout  time.Duration               `yaml:"timeout"`
+}
+
+// RangeQuery is a PromQL range query
+type RangeQuery struct {
+	Query string
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+}
+
+// Result is the data section of a Prometheus query response
+type Result struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// response is the Prometheus HTTP API envelope
+type response struct {
+	Status    string `json:"status"`
+	Data      Result `json:"data"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+}
+
+// Client runs PromQL queries against the datasource configured for each cluster
+type Client struct {
+	config   Config
+	insecure *http.Client
+	secure   *http.Client
+}
+
+// NewClient creates a new Prometheus client
+func NewClient(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &Client{
+		config: config,
+		secure: &http.Client{Timeout: config.Timeout},
+		insecure: &http.Client{
+			Timeout: config.Timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // opt-in per datasource
+			},
+		},
+	}
+}
+
+// Configured reports whether a cluster has a Prometheus datasource
+func (c *Client) Configured(clusterID string) bool {
+	_, ok := c.datasource(clusterID)
+	return ok
+}
+
+// QueryRange runs a range query against the cluster's Prometheus
+func (c *Client) QueryRange(ctx context.Context, clusterID string, query RangeQuery) (*Result, error) {
+	datasource, ok := c.datasource(clusterID)
+	if !ok {
+		return nil, ErrNotConfigured
+	}
+
+	params := url.Values{}
+	params.Set("query", query.Query)
+	params.Set("start", strconv.FormatInt(query.Start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(query.End.Unix(), 10))
+	params.Set("step", strconv.FormatFloat(query.Step.Seconds(), 'f', -1, 64))
+
+	endpoint := strings.TrimSuffix(datasource.URL, "/") + "/api/v1/query_range"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	token, err := datasource.token()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	httpClient := c.secure
+	if datasource.InsecureSkipTLSVerify {
+		httpClient = c.insecure
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prometheus response: %w", err)
+	}
+
+	var result response
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid prometheus response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if result.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s: %s", result.ErrorType, result.Error)
+	}
+
+	return &result.Data, nil
+}
+
+// datasource returns the datasource for a cluster, falling back to the default
+func (c *Client) datasource(clusterID string) (DatasourceConfig, bool) {
+	if datasource, ok := c.config.Clusters[clusterID]; ok && datasource.URL != "" {
+		return datasource, true
+	}
+
+	if c.config.Default.URL != "" {
+		return c.config.Default, true
+	}
+
+	return DatasourceConfig{}, false
+}
+
+// token returns the bearer token, reading the token file on every call so rotated tokens are used
+func (d DatasourceConfig) token() (string, error) {
+	if d.BearerTokenFile == "" {
+		return d.BearerToken, nil
+	}
+
+	data, err := os.ReadFile(d.BearerTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read prometheus token file: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
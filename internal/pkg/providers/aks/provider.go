@@ -0,0 +1,237 @@
+package aks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// aksServerAppScope is the Entra ID application AKS uses for AAD-enabled clusters
+const aksServerAppScope = "6dae42f8-4368-4678-94ff-3960e28e3630/.default"
+
+// AKSProvider discovers and authenticates AKS clusters in an Azure subscription
+type AKSProvider struct {
+	SubscriptionID string
+	ResourceGroup  string
+	credential     azcore.TokenCredential
+	client         *armcontainerservice.ManagedClustersClient
+	clusters       map[string]*armcontainerservice.ManagedCluster
+	mu             sync.RWMutex
+	logger         *slog.Logger
+}
+
+func init() {
+	providers.Register("aks", New)
+}
+
+// New creates the provider for the registry and plugin shim
+func New(config map[string]string, logger *slog.Logger) (providers.Provider, error) {
+	return NewAKSProvider(config, logger)
+}
+
+// NewAKSProvider creates a new AKSProvider. A clientSecret or
+// federatedTokenFile in the config selects a service principal or workload
+// identity; otherwise the azidentity default chain is used (environment,
+// workload identity, managed identity, then the Azure CLI).
+func NewAKSProvider(config map[string]string, logger *slog.Logger) (*AKSProvider, error) {
+	subscriptionID := valueOrEnv(config, "subscriptionId", "AZURE_SUBSCRIPTION_ID")
+	if subscriptionID == "" {
+		return nil, fmt.Errorf("aks provider requires a subscriptionId")
+	}
+
+	credential, err := newCredential(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AKS credentials: %w", err)
+	}
+
+	client, err := armcontainerservice.NewManagedClustersClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AKS client: %w", err)
+	}
+
+	return &AKSProvider{
+		SubscriptionID: subscriptionID,
+		ResourceGroup:  config["resourceGroup"],
+		credential:     credential,
+		client:         client,
+		clusters:       make(map[string]*armcontainerservice.ManagedCluster),
+		logger:         logger,
+	}, nil
+}
+
+// newCredential picks the Azure credential the config asks for
+func newCredential(config map[string]string) (azcore.TokenCredential, error) {
+	tenantID := valueOrEnv(config, "tenantId", "AZURE_TENANT_ID")
+	clientID := valueOrEnv(config, "clientId", "AZURE_CLIENT_ID")
+
+	switch {
+	case config["clientSecret"] != "":
+		return azidentity.NewClientSecretCredential(tenantID, clientID, config["clientSecret"], nil)
+	case config["federatedTokenFile"] != "":
+		return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			TenantID:      tenantID,
+			ClientID:      clientID,
+			TokenFilePath: config["federatedTokenFile"],
+		})
+	default:
+		return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			TenantID: config["tenantId"],
+		})
+	}
+}
+
+// DiscoverClusters lists managed clusters in the subscription, optionally
+// limited to a single resource group
+func (p *AKSProvider) DiscoverClusters() ([]providers.ClusterConfig, error) {
+	ctx := context.Background()
+
+	var discovered []*armcontainerservice.ManagedCluster
+	if p.ResourceGroup != "" {
+		pager := p.client.NewListByResourceGroupPager(p.ResourceGroup, nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list AKS clusters: %w", err)
+			}
+			discovered = append(discovered, page.Value...)
+		}
+	} else {
+		pager := p.client.NewListPager(nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list AKS clusters: %w", err)
+			}
+			discovered = append(discovered, page.Value...)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clusters := make([]providers.ClusterConfig, 0, len(discovered))
+	for _, cluster := range discovered {
+		if cluster == nil || cluster.Name == nil || cluster.ID == nil {
+			continue
+		}
+
+		resourceID, err := arm.ParseResourceID(*cluster.ID)
+		if err != nil {
+			p.logger.Warn("Skipping cluster with invalid resource ID", "clusterName", *cluster.Name, "error", err)
+			continue
+		}
+
+		// Names are only unique within a resource group
+		id := clusterID(resourceID.SubscriptionID, resourceID.ResourceGroupName, resourceID.Name)
+		p.clusters[id] = cluster
+		clusters = append(clusters, providers.ClusterConfig{ID: id})
+
+		var server string
+		if cluster.Properties != nil && cluster.Properties.Fqdn != nil {
+			server = *cluster.Properties.Fqdn
+		}
+		p.logger.Info("Discovered cluster",
+			"clusterID", id,
+			"clusterName", *cluster.Name,
+			"location", valueOf(cluster.Location),
+			"server", server)
+	}
+
+	return clusters, nil
+}
+
+// Authenticate fetches the cluster user kubeconfig via listClusterUserCredential.
+// For AAD-enabled clusters the kubelogin exec plugin is replaced with an Entra ID
+// token from our own credential.
+func (p *AKSProvider) Authenticate(clusterID string) (*rest.Config, error) {
+	p.mu.RLock()
+	cluster, ok := p.clusters[clusterID]
+	p.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown AKS cluster %s, run discovery first", clusterID)
+	}
+
+	resourceID, err := arm.ParseResourceID(*cluster.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource ID for %s: %w", clusterID, err)
+	}
+
+	response, err := p.client.ListClusterUserCredentials(context.Background(), resourceID.ResourceGroupName, resourceID.Name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials for %s: %w", clusterID, err)
+	}
+
+	if len(response.Kubeconfigs) == 0 || response.Kubeconfigs[0] == nil {
+		return nil, fmt.Errorf("no kubeconfig returned for %s", clusterID)
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(response.Kubeconfigs[0].Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig for %s: %w", clusterID, err)
+	}
+
+	if restConfig.ExecProvider != nil {
+		restConfig.ExecProvider = nil
+		tokens := oauth2.ReuseTokenSource(nil, &credentialTokenSource{credential: p.credential, scope: aksServerAppScope})
+		restConfig.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+			return &oauth2.Transport{Source: tokens, Base: rt}
+		}
+	}
+
+	return restConfig, nil
+}
+
+// credentialTokenSource adapts an Azure credential to an oauth2 token source
+type credentialTokenSource struct {
+	credential azcore.TokenCredential
+	scope      string
+}
+
+// Token requests an access token for the scope from the credential
+func (s *credentialTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.credential.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{s.scope}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.Token,
+		TokenType:   "Bearer",
+		Expiry:      token.ExpiresOn,
+	}, nil
+}
+
+// clusterID builds an ID unique across subscriptions and resource groups
+func clusterID(subscriptionID, resourceGroup, name string) string {
+	return fmt.Sprintf("aks_%s_%s_%s", subscriptionID, resourceGroup, name)
+}
+
+// valueOrEnv returns the config value for key, or the environment variable when unset
+func valueOrEnv(config map[string]string, key, env string) string {
+	if value := config[key]; value != "" {
+		return value
+	}
+	return os.Getenv(env)
+}
+
+// valueOf dereferences an optional string from the SDK
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
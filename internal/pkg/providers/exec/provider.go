@@ -0,0 +1,181 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	osexec "os/exec"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"k8s.io/client-go/rest"
+)
+
+// APIVersion identifies the exec provider protocol
+const APIVersion = "providers.dashboard.jbetancur.io/v1"
+
+// Operations sent to the plugin
+const (
+	OperationDiscover     = "discover"
+	OperationAuthenticate = "authenticate"
+)
+
+// Request is written to the plugin's stdin
+type Request struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Operation  string            `json:"operation"`
+	ClusterID  string            `json:"clusterID,omitempty"`
+	Config     map[string]string `json:"config,omitempty"`
+}
+
+// Response is read from the plugin's stdout
+type Response struct {
+	APIVersion  string       `json:"apiVersion"`
+	Kind        string       `json:"kind"`
+	Clusters    []Cluster    `json:"clusters,omitempty"`
+	Credentials *Credentials `json:"credentials,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// Cluster is a cluster reported by discovery
+type Cluster struct {
+	ID     string `json:"id"`
+	APIURL string `json:"apiURL,omitempty"`
+}
+
+// Credentials describe how to connect to a cluster. Byte fields are base64
+// encoded in JSON.
+type Credentials struct {
+	Server                   string `json:"server"`
+	CertificateAuthorityData []byte `json:"certificateAuthorityData,omitempty"`
+	InsecureSkipTLSVerify    bool   `json:"insecureSkipTLSVerify,omitempty"`
+	Token                    string `json:"token,omitempty"`
+	ClientCertificateData    []byte `json:"clientCertificateData,omitempty"`
+	ClientKeyData            []byte `json:"clientKeyData,omitempty"`
+}
+
+// Provider runs an external command for every discovery and authentication
+// call, exchanging JSON over stdin/stdout in the style of kubectl credential plugins
+type Provider struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+	Timeout time.Duration
+	config  map[string]string
+	logger  *slog.Logger
+}
+
+// NewProvider creates a new exec provider
+func NewProvider(command string, args []string, env map[string]string, config map[string]string,
+	logger *slog.Logger) (*Provider, error) {
+	if command == "" {
+		return nil, fmt.Errorf("exec provider requires a command")
+	}
+
+	if _, err := osexec.LookPath(command); err != nil {
+		return nil, fmt.Errorf("exec provider command %s not found: %w", command, err)
+	}
+
+	return &Provider{
+		Command: command,
+		Args:    args,
+		Env:     env,
+		Timeout: 30 * time.Second,
+		config:  config,
+		logger:  logger,
+	}, nil
+}
+
+// DiscoverClusters asks the plugin for its clusters
+func (p *Provider) DiscoverClusters() ([]providers.ClusterConfig, error) {
+	response, err := p.call(Request{Operation: OperationDiscover})
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]providers.ClusterConfig, 0, len(response.Clusters))
+	for _, cluster := range response.Clusters {
+		clusters = append(clusters, providers.ClusterConfig{
+			ID:     cluster.ID,
+			APIURL: cluster.APIURL,
+		})
+
+		p.logger.Info("Discovered cluster", "clusterName", cluster.ID, "server", cluster.APIURL, "command", p.Command)
+	}
+
+	return clusters, nil
+}
+
+// Authenticate asks the plugin for credentials to a cluster
+func (p *Provider) Authenticate(clusterID string) (*rest.Config, error) {
+	response, err := p.call(Request{Operation: OperationAuthenticate, ClusterID: clusterID})
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := response.Credentials
+	if credentials == nil || credentials.Server == "" {
+		return nil, fmt.Errorf("exec provider returned no credentials for %s", clusterID)
+	}
+
+	return &rest.Config{
+		Host:        credentials.Server,
+		BearerToken: credentials.Token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData:   credentials.CertificateAuthorityData,
+			CertData: credentials.ClientCertificateData,
+			KeyData:  credentials.ClientKeyData,
+			Insecure: credentials.InsecureSkipTLSVerify,
+		},
+	}, nil
+}
+
+// call runs the plugin once with the given request
+func (p *Provider) call(request Request) (*Response, error) {
+	request.APIVersion = APIVersion
+	request.Kind = "ProviderRequest"
+	request.Config = p.config
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal exec provider request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	cmd := osexec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range p.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("exec provider %s %s failed: %w: %s",
+			p.Command, request.Operation, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var response Response
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("invalid exec provider response: %w", err)
+	}
+
+	if response.APIVersion != "" && response.APIVersion != APIVersion {
+		return nil, fmt.Errorf("unsupported exec provider apiVersion %s", response.APIVersion)
+	}
+
+	if response.Error != "" {
+		return nil, fmt.Errorf("exec provider %s: %s", request.Operation, response.Error)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,345 @@
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Fleet management modes
+const (
+	modeClusterAPI = "capi"
+	modeRancher    = "rancher"
+)
+
+var (
+	capiClusterGVR = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Version:  "v1beta1",
+		Resource: "clusters",
+	}
+
+	rancherClusterGVR = schema.GroupVersionResource{
+		Group:    "management.cattle.io",
+		Version:  "v3",
+		Resource: "clusters",
+	}
+)
+
+// FleetProvider discovers member clusters from a management cluster running
+// Cluster API or Rancher, and reports fleet changes as they happen
+type FleetProvider struct {
+	Mode       string
+	Namespace  string
+	RancherURL string
+
+	rancherToken string
+	client       dynamic.Interface
+	clientset    kubernetes.Interface
+	members      map[string]*unstructured.Unstructured
+	mu           sync.RWMutex
+	logger       *slog.Logger
+	resyncPeriod time.Duration
+}
+
+func init() {
+	providers.Register("fleet", New)
+}
+
+// New creates the provider for the registry and plugin shim
+func New(config map[string]string, logger *slog.Logger) (providers.Provider, error) {
+	return NewFleetProvider(config, logger)
+}
+
+// NewFleetProvider creates a new FleetProvider connected to the management
+// cluster given by kubeconfigPath/context, or the in-cluster config when unset
+func NewFleetProvider(config map[string]string, logger *slog.Logger) (*FleetProvider, error) {
+	mode := config["mode"]
+	if mode == "" {
+		mode = modeClusterAPI
+	}
+
+	p := &FleetProvider{
+		Mode:         mode,
+		Namespace:    config["namespace"],
+		RancherURL:   config["rancherURL"],
+		rancherToken: config["rancherToken"],
+		members:      make(map[string]*unstructured.Unstructured),
+		logger:       logger,
+		resyncPeriod: 5 * time.Minute,
+	}
+
+	restConfig, err := managementConfig(config["kubeconfigPath"], config["context"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to load management cluster config: %w", err)
+	}
+
+	if p.client, err = dynamic.NewForConfig(restConfig); err != nil {
+		return nil, fmt.Errorf("failed to create management cluster client: %w", err)
+	}
+
+	if p.clientset, err = kubernetes.NewForConfig(restConfig); err != nil {
+		return nil, fmt.Errorf("failed to create management cluster client: %w", err)
+	}
+
+	return p, nil
+}
+
+// DiscoverClusters lists the ready member clusters
+func (p *FleetProvider) DiscoverClusters() ([]providers.ClusterConfig, error) {
+	list, err := p.resource().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s clusters: %w", p.Mode, err)
+	}
+
+	clusters := make([]providers.ClusterConfig, 0, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if !p.isReady(obj) {
+			continue
+		}
+
+		clusters = append(clusters, p.remember(obj))
+	}
+
+	return clusters, nil
+}
+
+// Watch reports member clusters as they become ready or are deleted
+func (p *FleetProvider) Watch(ctx context.Context, handler func(providers.ClusterEvent)) error {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(p.client, p.resyncPeriod, p.namespace(), nil)
+	informer := factory.ForResource(p.gvr()).Informer()
+
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			p.reconcile(obj, handler)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			p.reconcile(newObj, handler)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			member, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				return
+			}
+
+			p.forget(member, handler)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add fleet event handler: %w", err)
+	}
+
+	go informer.Run(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync fleet informer")
+	}
+
+	p.logger.Info("Watching fleet", "mode", p.Mode, "namespace", p.Namespace)
+	return nil
+}
+
+// Authenticate builds a rest.Config for a member cluster. Cluster API clusters
+// use the generated <name>-kubeconfig secret; Rancher clusters go through the
+// Rancher API proxy with the configured token.
+func (p *FleetProvider) Authenticate(clusterID string) (*rest.Config, error) {
+	p.mu.RLock()
+	member, ok := p.members[clusterID]
+	p.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown fleet cluster %s", clusterID)
+	}
+
+	if p.Mode == modeRancher {
+		if p.RancherURL == "" || p.rancherToken == "" {
+			return nil, fmt.Errorf("rancher mode requires rancherURL and rancherToken")
+		}
+
+		host, err := url.JoinPath(p.RancherURL, "k8s", "clusters", member.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("invalid rancherURL: %w", err)
+		}
+
+		return &rest.Config{
+			Host:        host,
+			BearerToken: p.rancherToken,
+		}, nil
+	}
+
+	secretName := member.GetName() + "-kubeconfig"
+	secret, err := p.clientset.CoreV1().Secrets(member.GetNamespace()).Get(
+		context.Background(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig secret %s: %w", secretName, err)
+	}
+
+	kubeconfig, ok := secret.Data["value"]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig secret %s has no value key", secretName)
+	}
+
+	return clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+}
+
+// reconcile sends an added or removed event when a member's readiness changes
+func (p *FleetProvider) reconcile(obj interface{}, handler func(providers.ClusterEvent)) {
+	member, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	id := p.clusterID(member)
+
+	p.mu.RLock()
+	_, known := p.members[id]
+	p.mu.RUnlock()
+
+	ready := p.isReady(member) && member.GetDeletionTimestamp() == nil
+
+	switch {
+	case ready && !known:
+		handler(providers.ClusterEvent{Type: providers.ClusterAdded, Cluster: p.remember(member)})
+	case ready && known:
+		// Keep the latest object for Authenticate
+		p.remember(member)
+	case !ready && known:
+		p.forget(member, handler)
+	}
+}
+
+// remember records a member cluster and returns its config
+func (p *FleetProvider) remember(member *unstructured.Unstructured) providers.ClusterConfig {
+	id := p.clusterID(member)
+
+	p.mu.Lock()
+	if existing, ok := p.members[id]; ok && existing.GetUID() != member.GetUID() {
+		p.logger.Warn("Fleet cluster name collision, keeping newest",
+			"cluster", id,
+			"namespace", member.GetNamespace())
+	}
+	p.members[id] = member.DeepCopy()
+	p.mu.Unlock()
+
+	return providers.ClusterConfig{
+		ID:     id,
+		APIURL: p.apiURL(member),
+	}
+}
+
+// forget drops a member cluster and sends a removed event if it was known
+func (p *FleetProvider) forget(member *unstructured.Unstructured, handler func(providers.ClusterEvent)) {
+	id := p.clusterID(member)
+
+	p.mu.Lock()
+	_, known := p.members[id]
+	delete(p.members, id)
+	p.mu.Unlock()
+
+	if known {
+		handler(providers.ClusterEvent{
+			Type:    providers.ClusterRemoved,
+			Cluster: providers.ClusterConfig{ID: id},
+		})
+	}
+}
+
+// clusterID returns the ID for a member, using the Rancher display name when set
+func (p *FleetProvider) clusterID(member *unstructured.Unstructured) string {
+	if p.Mode == modeRancher {
+		if displayName, _, _ := unstructured.NestedString(member.Object, "spec", "displayName"); displayName != "" {
+			return displayName
+		}
+	}
+	return member.GetName()
+}
+
+// apiURL returns the member's API server address when the object exposes one
+func (p *FleetProvider) apiURL(member *unstructured.Unstructured) string {
+	if p.Mode == modeRancher {
+		apiURL, _, _ := unstructured.NestedString(member.Object, "status", "apiEndpoint")
+		return apiURL
+	}
+
+	host, _, _ := unstructured.NestedString(member.Object, "spec", "controlPlaneEndpoint", "host")
+	if host == "" {
+		return ""
+	}
+
+	port, _, _ := unstructured.NestedInt64(member.Object, "spec", "controlPlaneEndpoint", "port")
+	if port == 0 {
+		port = 443
+	}
+	return fmt.Sprintf("https://%s:%d", host, port)
+}
+
+// isReady reports whether a member cluster can be connected to
+func (p *FleetProvider) isReady(member *unstructured.Unstructured) bool {
+	if p.Mode == modeRancher {
+		conditions, _, _ := unstructured.NestedSlice(member.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if ok && condition["type"] == "Ready" {
+				return condition["status"] == "True"
+			}
+		}
+		return false
+	}
+
+	phase, _, _ := unstructured.NestedString(member.Object, "status", "phase")
+	return phase == "Provisioned"
+}
+
+// resource returns the dynamic client for the fleet's cluster objects
+func (p *FleetProvider) resource() dynamic.ResourceInterface {
+	if ns := p.namespace(); ns != metav1.NamespaceAll {
+		return p.client.Resource(p.gvr()).Namespace(ns)
+	}
+	return p.client.Resource(p.gvr())
+}
+
+// gvr returns the cluster resource for the configured mode
+func (p *FleetProvider) gvr() schema.GroupVersionResource {
+	if p.Mode == modeRancher {
+		return rancherClusterGVR
+	}
+	return capiClusterGVR
+}
+
+// namespace returns the namespace to watch; Rancher clusters are cluster-scoped
+func (p *FleetProvider) namespace() string {
+	if p.Mode == modeRancher {
+		return metav1.NamespaceAll
+	}
+	return p.Namespace
+}
+
+// managementConfig loads the management cluster config
+func managementConfig(kubeconfigPath, contextName string) (*rest.Config, error) {
+	if kubeconfigPath == "" {
+		return rest.InClusterConfig()
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).ClientConfig()
+}
@@ -0,0 +1,190 @@
+package gke
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	container "google.golang.org/api/container/v1"
+	"google.golang.org/api/option"
+	"k8s.io/client-go/rest"
+)
+
+const cloudPlatform = "https://www.googleapis.com/auth/cloud-platform"
+
+// GKEProvider discovers and authenticates GKE clusters in a Google Cloud project
+type GKEProvider struct {
+	Project     string
+	Location    string
+	tokenSource oauth2.TokenSource
+	service     *container.Service
+	clusters    map[string]*container.Cluster
+	mu          sync.RWMutex
+	logger      *slog.Logger
+}
+
+func init() {
+	providers.Register("gke", New)
+}
+
+// New creates the provider for the registry and plugin shim
+func New(config map[string]string, logger *slog.Logger) (providers.Provider, error) {
+	return NewGKEProvider(config, logger)
+}
+
+// NewGKEProvider creates a new GKEProvider. Credentials come from the service
+// account key in credentialsFile, otherwise from Application Default
+// Credentials: GOOGLE_APPLICATION_CREDENTIALS, the gcloud configuration, or
+// the metadata server when running on Google Cloud with workload identity.
+func NewGKEProvider(config map[string]string, logger *slog.Logger) (*GKEProvider, error) {
+	location := config["location"]
+	if location == "" {
+		location = "-" // All zones and regions
+	}
+
+	ctx := context.Background()
+
+	credentials, err := findCredentials(ctx, config["credentialsFile"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to load GKE credentials: %w", err)
+	}
+
+	service, err := container.NewService(ctx, option.WithCredentials(credentials))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GKE client: %w", err)
+	}
+
+	project := config["project"]
+	if project == "" {
+		project = credentials.ProjectID
+	}
+
+	return &GKEProvider{
+		Project:     project,
+		Location:    location,
+		tokenSource: credentials.TokenSource,
+		service:     service,
+		clusters:    make(map[string]*container.Cluster),
+		logger:      logger,
+	}, nil
+}
+
+// findCredentials loads a service account key file, or Application Default
+// Credentials when no file is given
+func findCredentials(ctx context.Context, path string) (*google.Credentials, error) {
+	if path == "" {
+		return google.FindDefaultCredentials(ctx, cloudPlatform)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return google.CredentialsFromJSON(ctx, data, cloudPlatform)
+}
+
+// DiscoverClusters lists the GKE clusters in the configured project and location
+func (p *GKEProvider) DiscoverClusters() ([]providers.ClusterConfig, error) {
+	if p.Project == "" {
+		return nil, fmt.Errorf("gke provider requires a project")
+	}
+
+	parent := fmt.Sprintf("projects/%s/locations/%s", p.Project, p.Location)
+	response, err := p.service.Projects.Locations.Clusters.List(parent).Context(context.Background()).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GKE clusters: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clusters := make([]providers.ClusterConfig, 0, len(response.Clusters))
+	for _, cluster := range response.Clusters {
+		id := clusterID(p.Project, cluster.Location, cluster.Name)
+		p.clusters[id] = cluster
+
+		clusters = append(clusters, providers.ClusterConfig{ID: id})
+
+		p.logger.Info("Discovered cluster", "clusterName", cluster.Name, "location", cluster.Location, "status", cluster.Status)
+	}
+
+	return clusters, nil
+}
+
+// Authenticate builds a rest.Config that authenticates with a Google OAuth token
+func (p *GKEProvider) Authenticate(clusterID string) (*rest.Config, error) {
+	cluster, err := p.lookupCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.MasterAuth == nil {
+		return nil, fmt.Errorf("no CA certificate returned for %s", clusterID)
+	}
+
+	caData, err := base64.StdEncoding.DecodeString(cluster.MasterAuth.ClusterCaCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode CA certificate for %s: %w", clusterID, err)
+	}
+
+	tokenSource := p.tokenSource
+	return &rest.Config{
+		Host: "https://" + cluster.Endpoint,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: caData,
+		},
+		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+			return &oauth2.Transport{Source: tokenSource, Base: rt}
+		},
+	}, nil
+}
+
+// lookupCluster returns a discovered cluster, fetching it directly when unknown
+func (p *GKEProvider) lookupCluster(id string) (*container.Cluster, error) {
+	p.mu.RLock()
+	cluster, ok := p.clusters[id]
+	p.mu.RUnlock()
+
+	if ok {
+		return cluster, nil
+	}
+
+	project, location, name, err := parseClusterID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("projects/%s/locations/%s/clusters/%s", project, location, name)
+	cluster, err = p.service.Projects.Locations.Clusters.Get(path).Context(context.Background()).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GKE cluster %s: %w", id, err)
+	}
+
+	p.mu.Lock()
+	p.clusters[id] = cluster
+	p.mu.Unlock()
+
+	return cluster, nil
+}
+
+// clusterID builds an ID in the same format gcloud uses for kubeconfig contexts
+func clusterID(project, location, name string) string {
+	return fmt.Sprintf("gke_%s_%s_%s", project, location, name)
+}
+
+// parseClusterID splits an ID created by clusterID
+func parseClusterID(id string) (string, string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(id, "gke_"), "_", 3)
+	if !strings.HasPrefix(id, "gke_") || len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid GKE cluster ID: %s", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"fmt"
+	"log/slog"
+	"plugin"
+	"sort"
+	"sync"
+)
+
+// Factory creates a provider from its config
+type Factory func(config map[string]string, logger *slog.Logger) (Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a built-in provider available by name. It is meant to be
+// called from the provider package's init function and panics on duplicates.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("provider %q already registered", name))
+	}
+	registry[name] = factory
+}
+
+// New creates a registered built-in provider
+func New(name string, config map[string]string, logger *slog.Logger) (Provider, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q, registered providers: %v", name, Registered())
+	}
+
+	return factory(config, logger)
+}
+
+// Registered returns the names of all built-in providers
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadPlugin loads a provider from a Go plugin (.so) exporting a New function.
+// Both the original signature and the error-returning Factory signature are accepted.
+func LoadPlugin(path string, config map[string]string, logger *slog.Logger) (Provider, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin: %w", err)
+	}
+
+	symbol, err := p.Lookup("New")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find 'New' function in plugin: %w", err)
+	}
+
+	switch newFunc := symbol.(type) {
+	case func(map[string]string, *slog.Logger) (Provider, error):
+		return newFunc(config, logger)
+	case func(map[string]string, *slog.Logger) Provider:
+		return newFunc(config, logger), nil
+	default:
+		return nil, fmt.Errorf("invalid 'New' function signature in plugin")
+	}
+}
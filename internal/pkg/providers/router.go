@@ -0,0 +1,217 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+// Router holds the loaded providers by name and remembers which provider owns
+// each cluster so Authenticate reaches the right one. It implements Provider
+// and WatchingProvider, so it can stand in for a single provider.
+type Router struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	order     []string
+	owners    map[string]string
+	logger    *slog.Logger
+}
+
+// NewRouter creates an empty provider router
+func NewRouter(logger *slog.Logger) *Router {
+	return &Router{
+		providers: make(map[string]Provider),
+		owners:    make(map[string]string),
+		logger:    logger,
+	}
+}
+
+// Add registers a loaded provider under a unique name
+func (r *Router) Add(name string, provider Provider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("provider %q already loaded", name)
+	}
+
+	r.providers[name] = provider
+	r.order = append(r.order, name)
+	return nil
+}
+
+// Get returns a loaded provider by name
+func (r *Router) Get(name string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// Names returns the loaded provider names in load order
+func (r *Router) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.order...)
+}
+
+// Owner returns the name of the provider that owns a cluster
+func (r *Router) Owner(clusterID string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name, ok := r.owners[clusterID]
+	return name, ok
+}
+
+// SetOwner records which provider owns a cluster. The first provider to claim
+// a cluster keeps it; later claims are logged and ignored.
+func (r *Router) SetOwner(clusterID, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.setOwnerLocked(clusterID, name)
+}
+
+// RemoveOwner forgets the owner of a cluster
+func (r *Router) RemoveOwner(clusterID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.owners, clusterID)
+}
+
+// setOwnerLocked records an owner; callers must hold the lock
+func (r *Router) setOwnerLocked(clusterID, name string) {
+	if owner, exists := r.owners[clusterID]; exists && owner != name {
+		if _, loaded := r.providers[owner]; loaded {
+			r.logger.Warn("Cluster reported by multiple providers, keeping first owner",
+				"clusterID", clusterID, "owner", owner, "provider", name)
+			return
+		}
+	}
+
+	r.owners[clusterID] = name
+}
+
+// DiscoveryError lists the providers that failed in a discovery pass. The
+// clusters of the other providers are still returned alongside it, but
+// nothing can be concluded about the clusters a failed provider owns.
+type DiscoveryError struct {
+	Failed map[string]error
+}
+
+func (e *DiscoveryError) Error() string {
+	return errors.Join(e.Unwrap()...).Error()
+}
+
+func (e *DiscoveryError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for name, err := range e.Failed {
+		errs = append(errs, fmt.Errorf("provider %s: %w", name, err))
+	}
+	return errs
+}
+
+// DiscoverClusters discovers clusters from every provider and records their owners.
+// A failing provider is logged and skipped so the others still contribute;
+// the failures are returned as a *DiscoveryError with those clusters.
+func (r *Router) DiscoverClusters() ([]ClusterConfig, error) {
+	var clusters []ClusterConfig
+	failed := make(map[string]error)
+
+	for _, name := range r.Names() {
+		provider, _ := r.Get(name)
+
+		discovered, err := provider.DiscoverClusters()
+		if err != nil {
+			r.logger.Error("Failed to discover clusters", "provider", name, "error", err)
+			failed[name] = err
+			continue
+		}
+
+		r.mu.Lock()
+		for i := range discovered {
+			discovered[i].Provider = name
+			r.setOwnerLocked(discovered[i].ID, name)
+		}
+		r.mu.Unlock()
+
+		clusters = append(clusters, discovered...)
+	}
+
+	if len(failed) > 0 {
+		return clusters, &DiscoveryError{Failed: failed}
+	}
+
+	return clusters, nil
+}
+
+// Authenticate routes to the provider that owns the cluster. Unknown clusters
+// trigger a discovery pass to find their owner.
+func (r *Router) Authenticate(clusterID string) (*rest.Config, error) {
+	name, ok := r.Owner(clusterID)
+	if !ok {
+		// The owner may be found even when another provider failed
+		_, err := r.DiscoverClusters()
+
+		name, ok = r.Owner(clusterID)
+		switch {
+		case !ok && err != nil:
+			return nil, fmt.Errorf("failed to find provider for cluster %s: %w", clusterID, err)
+		case !ok:
+			return nil, fmt.Errorf("no provider owns cluster %s", clusterID)
+		}
+	}
+
+	provider, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("provider %s for cluster %s is not loaded", name, clusterID)
+	}
+
+	return provider.Authenticate(clusterID)
+}
+
+// Watch starts watching every provider that supports it, tagging each event
+// with its provider and keeping ownership up to date. Removals of clusters owned
+// by a different provider are dropped.
+func (r *Router) Watch(ctx context.Context, handler func(ClusterEvent)) error {
+	var errs []error
+
+	for _, name := range r.Names() {
+		provider, _ := r.Get(name)
+
+		watcher, ok := provider.(WatchingProvider)
+		if !ok {
+			continue
+		}
+
+		providerName := name
+		err := watcher.Watch(ctx, func(event ClusterEvent) {
+			event.Cluster.Provider = providerName
+
+			if event.Type == ClusterRemoved {
+				if owner, ok := r.Owner(event.Cluster.ID); ok && owner != providerName {
+					return
+				}
+				r.RemoveOwner(event.Cluster.ID)
+			} else {
+				r.SetOwner(event.Cluster.ID, providerName)
+			}
+
+			handler(event)
+		})
+		if err != nil {
+			r.logger.Error("Failed to watch provider", "provider", name, "error", err)
+			errs = append(errs, fmt.Errorf("provider %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
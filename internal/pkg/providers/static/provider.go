@@ -0,0 +1,248 @@
+package static
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// SecretRef points at a key in a Kubernetes Secret
+type SecretRef struct {
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+	Key       string `yaml:"key"`
+}
+
+// Source is a credential value given inline, or read from a file, an
+// environment variable or a Kubernetes Secret. Exactly one should be set.
+type Source struct {
+	Value  string     `yaml:"value"`
+	File   string     `yaml:"file"`
+	Env    string     `yaml:"env"`
+	Secret *SecretRef `yaml:"secret"`
+}
+
+// IsZero reports whether no source is configured
+func (s *Source) IsZero() bool {
+	return s == nil || (s.Value == "" && s.File == "" && s.Env == "" && s.Secret == nil)
+}
+
+// ClusterSpec declares a cluster reachable by API URL and credentials
+type ClusterSpec struct {
+	ID                    string  `yaml:"id"`
+	APIURL                string  `yaml:"apiURL"`
+	InsecureSkipTLSVerify bool    `yaml:"insecureSkipTLSVerify"`
+	CertificateAuthority  *Source `yaml:"certificateAuthority"`
+	Token                 *Source `yaml:"token"`
+	ClientCertificate     *Source `yaml:"clientCertificate"`
+	ClientKey             *Source `yaml:"clientKey"`
+}
+
+// StaticProvider serves a fixed list of clusters. Credentials are resolved on
+// every Authenticate call so rotated files and secrets are picked up.
+type StaticProvider struct {
+	Clusters []ClusterSpec
+
+	secretsKubeconfigPath string
+	secretsContext        string
+	secretsClient         kubernetes.Interface
+	mu                    sync.Mutex
+	logger                *slog.Logger
+}
+
+func init() {
+	providers.Register("static", New)
+}
+
+// New creates the provider for the registry from a clustersFile holding a YAML list of clusters
+func New(config map[string]string, logger *slog.Logger) (providers.Provider, error) {
+	path := config["clustersFile"]
+	if path == "" {
+		return nil, fmt.Errorf("static provider requires clustersFile")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clusters file: %w", err)
+	}
+
+	var clusters []ClusterSpec
+	if err := yaml.Unmarshal(data, &clusters); err != nil {
+		return nil, fmt.Errorf("failed to parse clusters file: %w", err)
+	}
+
+	return NewStaticProvider(clusters, config, logger)
+}
+
+// NewStaticProvider creates a provider for the given clusters. Secrets are read
+// through secretsKubeconfigPath/secretsContext, or the in-cluster config when unset.
+func NewStaticProvider(clusters []ClusterSpec, config map[string]string, logger *slog.Logger) (*StaticProvider, error) {
+	seen := make(map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.ID == "" || cluster.APIURL == "" {
+			return nil, fmt.Errorf("static cluster requires id and apiURL")
+		}
+
+		if seen[cluster.ID] {
+			return nil, fmt.Errorf("duplicate static cluster %s", cluster.ID)
+		}
+		seen[cluster.ID] = true
+
+		if cluster.ClientCertificate.IsZero() != cluster.ClientKey.IsZero() {
+			return nil, fmt.Errorf("static cluster %s requires both clientCertificate and clientKey", cluster.ID)
+		}
+	}
+
+	return &StaticProvider{
+		Clusters:              clusters,
+		secretsKubeconfigPath: config["secretsKubeconfigPath"],
+		secretsContext:        config["secretsContext"],
+		logger:                logger,
+	}, nil
+}
+
+// DiscoverClusters returns the configured clusters
+func (p *StaticProvider) DiscoverClusters() ([]providers.ClusterConfig, error) {
+	clusters := make([]providers.ClusterConfig, 0, len(p.Clusters))
+	for _, cluster := range p.Clusters {
+		clusters = append(clusters, providers.ClusterConfig{
+			ID:     cluster.ID,
+			APIURL: cluster.APIURL,
+		})
+
+		p.logger.Info("Discovered cluster", "clusterName", cluster.ID, "server", cluster.APIURL)
+	}
+
+	return clusters, nil
+}
+
+// Authenticate builds a rest.Config from the cluster's credential sources
+func (p *StaticProvider) Authenticate(clusterID string) (*rest.Config, error) {
+	var spec *ClusterSpec
+	for i := range p.Clusters {
+		if p.Clusters[i].ID == clusterID {
+			spec = &p.Clusters[i]
+			break
+		}
+	}
+
+	if spec == nil {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	config := &rest.Config{
+		Host: spec.APIURL,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure: spec.InsecureSkipTLSVerify,
+		},
+	}
+
+	var err error
+	if config.CAData, err = p.resolve(spec.CertificateAuthority); err != nil {
+		return nil, fmt.Errorf("failed to resolve certificate authority for %s: %w", clusterID, err)
+	}
+
+	token, err := p.resolve(spec.Token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve token for %s: %w", clusterID, err)
+	}
+	config.BearerToken = string(token)
+
+	if config.CertData, err = p.resolve(spec.ClientCertificate); err != nil {
+		return nil, fmt.Errorf("failed to resolve client certificate for %s: %w", clusterID, err)
+	}
+
+	if config.KeyData, err = p.resolve(spec.ClientKey); err != nil {
+		return nil, fmt.Errorf("failed to resolve client key for %s: %w", clusterID, err)
+	}
+
+	if config.BearerToken == "" && len(config.CertData) == 0 {
+		return nil, fmt.Errorf("cluster %s has no token or client certificate", clusterID)
+	}
+
+	return config, nil
+}
+
+// resolve reads a credential from its source; an unset source yields nil
+func (p *StaticProvider) resolve(source *Source) ([]byte, error) {
+	switch {
+	case source.IsZero():
+		return nil, nil
+	case source.Value != "":
+		return []byte(source.Value), nil
+	case source.File != "":
+		return os.ReadFile(source.File)
+	case source.Env != "":
+		value, ok := os.LookupEnv(source.Env)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", source.Env)
+		}
+		return []byte(value), nil
+	default:
+		return p.readSecret(source.Secret)
+	}
+}
+
+// readSecret reads one key from a Kubernetes Secret
+func (p *StaticProvider) readSecret(ref *SecretRef) ([]byte, error) {
+	client, err := p.secrets()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := client.CoreV1().Secrets(ref.Namespace).Get(context.Background(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)
+	}
+
+	return value, nil
+}
+
+// secrets lazily creates the client used to read credential secrets
+func (p *StaticProvider) secrets() (kubernetes.Interface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.secretsClient != nil {
+		return p.secretsClient, nil
+	}
+
+	var (
+		restConfig *rest.Config
+		err        error
+	)
+
+	if p.secretsKubeconfigPath == "" {
+		restConfig, err = rest.InClusterConfig()
+	} else {
+		restConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: p.secretsKubeconfigPath},
+			&clientcmd.ConfigOverrides{CurrentContext: p.secretsContext},
+		).ClientConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config for reading secrets: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for reading secrets: %w", err)
+	}
+
+	p.secretsClient = client
+	return client, nil
+}
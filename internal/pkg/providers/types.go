@@ -1,14 +1,46 @@
 package providers
 
-import "k8s.io/client-go/rest"
+import (
+	"context"
+
+	"k8s.io/client-go/rest"
+)
 
 // ClusterConfig represents the configuration for a cluster
 type ClusterConfig struct {
 	ID             string
 	KubeconfigPath string
+	APIURL         string
+	Provider       string
 }
 
 type Provider interface {
 	DiscoverClusters() ([]ClusterConfig, error)
 	Authenticate(clusterID string) (*rest.Config, error)
 }
+
+// ClusterEventType describes a change in a provider's fleet
+type ClusterEventType string
+
+const (
+	// ClusterAdded is sent when a cluster joins the fleet or becomes ready
+	ClusterAdded ClusterEventType = "added"
+
+	// ClusterRemoved is sent when a cluster leaves the fleet
+	ClusterRemoved ClusterEventType = "removed"
+)
+
+// ClusterEvent is a fleet change reported by a WatchingProvider
+type ClusterEvent struct {
+	Type    ClusterEventType
+	Cluster ClusterConfig
+}
+
+// WatchingProvider is implemented by providers that can report fleet changes
+// as they happen instead of only through DiscoverClusters
+type WatchingProvider interface {
+	Provider
+
+	// Watch calls handler for every fleet change until the context is cancelled
+	Watch(ctx context.Context, handler func(ClusterEvent)) error
+}
@@ -0,0 +1,82 @@
+package uploaded
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+)
+
+// defaultKeyEnv holds the base64 encoded credential encryption key when Config.KeyEnv is unset
+const defaultKeyEnv = "DASHBOARD_CREDENTIALS_KEY"
+
+// Config configures runtime cluster registration
+type Config struct {
+	// KeyEnv names the environment variable holding a base64 encoded AES key
+	// of 16, 24 or 32 bytes. Registration is disabled when it is empty.
+	KeyEnv string `yaml:"keyEnv"`
+}
+
+// Cipher seals credentials with AES-GCM, prefixing each sealed value with its nonce
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from the key in the configured environment
+// variable. It returns nil without an error when no key is set.
+func NewCipher(config Config) (*Cipher, error) {
+	keyEnv := config.KeyEnv
+	if keyEnv == "" {
+		keyEnv = defaultKeyEnv
+	}
+
+	encoded := os.Getenv(keyEnv)
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", keyEnv, err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key in %s: %w", keyEnv, err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts data
+func (c *Cipher) Seal(data []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return c.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// Open decrypts data sealed by Seal
+func (c *Cipher) Open(sealed []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, fmt.Errorf("sealed credentials are too short")
+	}
+
+	data, err := c.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,263 @@
+package uploaded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ProviderName is the name the provider is loaded under in the provider router
+const ProviderName = "uploaded"
+
+// verifyTimeout bounds the connection check made before credentials are stored
+const verifyTimeout = 10 * time.Second
+
+// ErrDisabled is returned when no encryption key is configured
+var ErrDisabled = errors.New("cluster registration is disabled, no encryption key is configured")
+
+// Credentials is a registered cluster with its encrypted kubeconfig
+type Credentials struct {
+	ID         string    `json:"id" bson:"_id"`
+	APIURL     string    `json:"apiUrl" bson:"api_url"`
+	Kubeconfig []byte    `json:"-" bson:"kubeconfig"`
+	CreatedBy  string    `json:"createdBy" bson:"created_by"`
+	CreatedAt  time.Time `json:"createdAt" bson:"created_at"`
+}
+
+// CredentialStore persists registered cluster credentials
+type CredentialStore interface {
+	SaveClusterCredentials(ctx context.Context, credentials *Credentials) error
+	GetClusterCredentials(ctx context.Context, id string, result *Credentials) error
+	ListClusterCredentials(ctx context.Context, results *[]Credentials) error
+	DeleteClusterCredentials(ctx context.Context, id string) error
+}
+
+// Registration describes a cluster to register, either as a kubeconfig or as
+// an API URL with a bearer token and CA certificate
+type Registration struct {
+	ID                    string `json:"id"`
+	Kubeconfig            string `json:"kubeconfig"`
+	Context               string `json:"context"`
+	APIURL                string `json:"apiURL"`
+	Token                 string `json:"token"`
+	CertificateAuthority  string `json:"certificateAuthority"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify"`
+}
+
+// UploadedProvider serves clusters registered at runtime through the API.
+// Their kubeconfigs are kept encrypted in the store and decrypted on every
+// Authenticate call.
+type UploadedProvider struct {
+	store  CredentialStore
+	cipher *Cipher
+	logger *slog.Logger
+}
+
+// NewUploadedProvider creates a new provider. A nil cipher disables registration.
+func NewUploadedProvider(store CredentialStore, cipher *Cipher, logger *slog.Logger) *UploadedProvider {
+	return &UploadedProvider{
+		store:  store,
+		cipher: cipher,
+		logger: logger,
+	}
+}
+
+// Enabled reports whether clusters can be registered
+func (p *UploadedProvider) Enabled() bool {
+	return p.cipher != nil
+}
+
+// Register validates a registration, checks that the cluster is reachable
+// with it and stores the encrypted kubeconfig
+func (p *UploadedProvider) Register(ctx context.Context, registration Registration, createdBy string) (*Credentials, error) {
+	if !p.Enabled() {
+		return nil, ErrDisabled
+	}
+
+	if registration.ID == "" {
+		return nil, fmt.Errorf("cluster ID is required")
+	}
+
+	config, err := kubeconfigFor(registration)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials: %w", err)
+	}
+
+	if err := verify(restConfig); err != nil {
+		return nil, err
+	}
+
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+
+	sealed, err := p.cipher.Seal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := &Credentials{
+		ID:         registration.ID,
+		APIURL:     restConfig.Host,
+		Kubeconfig: sealed,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := p.store.SaveClusterCredentials(ctx, credentials); err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("Registered cluster credentials", "clusterID", registration.ID, "server", restConfig.Host, "createdBy", createdBy)
+	return credentials, nil
+}
+
+// Unregister removes the stored credentials of a cluster
+func (p *UploadedProvider) Unregister(ctx context.Context, id string) error {
+	return p.store.DeleteClusterCredentials(ctx, id)
+}
+
+// List returns the registered clusters without their credentials
+func (p *UploadedProvider) List(ctx context.Context) ([]Credentials, error) {
+	credentials := make([]Credentials, 0)
+	if err := p.store.ListClusterCredentials(ctx, &credentials); err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// DiscoverClusters returns the registered clusters
+func (p *UploadedProvider) DiscoverClusters() ([]providers.ClusterConfig, error) {
+	credentials, err := p.List(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registered clusters: %w", err)
+	}
+
+	clusters := make([]providers.ClusterConfig, 0, len(credentials))
+	for _, item := range credentials {
+		clusters = append(clusters, providers.ClusterConfig{
+			ID:     item.ID,
+			APIURL: item.APIURL,
+		})
+	}
+
+	return clusters, nil
+}
+
+// Authenticate decrypts the stored kubeconfig of a cluster into a rest.Config
+func (p *UploadedProvider) Authenticate(clusterID string) (*rest.Config, error) {
+	if !p.Enabled() {
+		return nil, ErrDisabled
+	}
+
+	var credentials Credentials
+	if err := p.store.GetClusterCredentials(context.Background(), clusterID, &credentials); err != nil {
+		return nil, fmt.Errorf("cluster %s not found: %w", clusterID, err)
+	}
+
+	data, err := p.cipher.Open(credentials.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientcmd.RESTConfigFromKubeConfig(data)
+}
+
+// kubeconfigFor builds a single-context kubeconfig from a registration
+func kubeconfigFor(registration Registration) (*clientcmdapi.Config, error) {
+	if registration.Kubeconfig == "" {
+		if registration.APIURL == "" || registration.Token == "" {
+			return nil, fmt.Errorf("either a kubeconfig or an API URL and token are required")
+		}
+
+		config := clientcmdapi.NewConfig()
+		config.Clusters[registration.ID] = &clientcmdapi.Cluster{
+			Server:                   registration.APIURL,
+			CertificateAuthorityData: []byte(registration.CertificateAuthority),
+			InsecureSkipTLSVerify:    registration.InsecureSkipTLSVerify,
+		}
+		config.AuthInfos[registration.ID] = &clientcmdapi.AuthInfo{Token: registration.Token}
+		config.Contexts[registration.ID] = &clientcmdapi.Context{Cluster: registration.ID, AuthInfo: registration.ID}
+		config.CurrentContext = registration.ID
+
+		return config, nil
+	}
+
+	config, err := clientcmd.Load([]byte(registration.Kubeconfig))
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+
+	if registration.Context != "" {
+		config.CurrentContext = registration.Context
+	}
+	if _, ok := config.Contexts[config.CurrentContext]; !ok {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", config.CurrentContext)
+	}
+
+	// Keep only the selected context so unrelated credentials are not stored
+	if err := clientcmdapi.MinifyConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// validate rejects kubeconfigs that would make the server run commands or
+// read its own files when connecting
+func validate(config *clientcmdapi.Config) error {
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return fmt.Errorf("cluster %s references a file, embed certificate-authority-data instead", name)
+		}
+	}
+
+	for name, authInfo := range config.AuthInfos {
+		switch {
+		case authInfo.Exec != nil:
+			return fmt.Errorf("user %s uses an exec plugin, which is not allowed", name)
+		case authInfo.AuthProvider != nil:
+			return fmt.Errorf("user %s uses an auth provider, which is not allowed", name)
+		case authInfo.ClientCertificate != "", authInfo.ClientKey != "", authInfo.TokenFile != "":
+			return fmt.Errorf("user %s references a file, embed the credentials instead", name)
+		}
+	}
+
+	return nil
+}
+
+// verify checks that the cluster answers with the given credentials
+func verify(config *rest.Config) error {
+	config = rest.CopyConfig(config)
+	config.Timeout = verifyTimeout
+
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("invalid credentials: %w", err)
+	}
+
+	if _, err := client.ServerVersion(); err != nil {
+		return fmt.Errorf("failed to reach cluster: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package recording
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder collects the events of a session as it runs. It is safe for
+// concurrent use by the streams of a session.
+type Recorder struct {
+	config Config
+
+	mu        sync.Mutex
+	recording Recording
+	start     time.Time
+}
+
+// NewRecorder starts recording a session described by meta
+func NewRecorder(config Config, meta Recording) *Recorder {
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultMaxBytes
+	}
+	config.MaxBytes = min(config.MaxBytes, maxBytes)
+
+	now := time.Now()
+	meta.StartedAt = now
+	meta.Events = make([]Event, 0)
+
+	return &Recorder{
+		config:    config,
+		recording: meta,
+		start:     now,
+	}
+}
+
+// Output records data written to the terminal
+func (r *Recorder) Output(data []byte) {
+	r.add(EventOutput, strings.ToValidUTF8(string(data), "\uFFFD"))
+}
+
+// Input records data sent to the container, if input recording is on
+func (r *Recorder) Input(data []byte) {
+	if r.config.RecordInput {
+		r.add(EventInput, strings.ToValidUTF8(string(data), "\uFFFD"))
+	}
+}
+
+// Resize records a change of terminal size. The first size is the size of
+// the recording.
+func (r *Recorder) Resize(width, height uint16) {
+	r.mu.Lock()
+	if r.recording.Width == 0 && r.recording.Height == 0 {
+		r.recording.Width, r.recording.Height = width, height
+	}
+	r.mu.Unlock()
+
+	r.add(EventResize, fmt.Sprintf("%dx%d", width, height))
+}
+
+// Finish ends the session and returns its recording
+func (r *Recorder) Finish() *Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recording := r.recording
+	recording.EndedAt = time.Now()
+	return &recording
+}
+
+// add appends an event unless the size limit was reached
+func (r *Recorder) add(eventType, data string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.recording.Truncated {
+		return
+	}
+	if r.recording.Bytes+int64(len(data)) > r.config.MaxBytes {
+		r.recording.Truncated = true
+		return
+	}
+
+	r.recording.Bytes += int64(len(data))
+	r.recording.Events = append(r.recording.Events, Event{
+		Time: time.Since(r.start).Seconds(),
+		Type: eventType,
+		Data: data,
+	})
+}
@@ -0,0 +1,108 @@
+// Package recording captures interactive container sessions so they can be
+// reviewed later, in the timing format of asciinema.
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Size limits of a session's data. A recording is stored as one MongoDB
+// document, which can't exceed 16MiB.
+const (
+	DefaultMaxBytes = 8 << 20
+	maxBytes        = 12 << 20
+)
+
+// Event types
+const (
+	EventOutput = "o"
+	EventInput  = "i"
+	EventResize = "r"
+)
+
+// Config controls session recording. Input is only recorded when
+// RecordInput is set, as it may contain secrets typed at a prompt.
+type Config struct {
+	Enabled     bool  `yaml:"enabled"`
+	RecordInput bool  `yaml:"recordInput"`
+	MaxBytes    int64 `yaml:"maxBytes"`
+}
+
+// Event is output, input or a resize at a time since the session started
+type Event struct {
+	Time float64 `json:"time" bson:"time"`
+	Type string  `json:"type" bson:"type"`
+	Data string  `json:"data" bson:"data"`
+}
+
+// Recording is a recorded session with the audit details of who opened it
+// where. Data past the size limit is dropped and the recording marked truncated.
+type Recording struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	Session   string    `json:"session" bson:"session"`
+	ClusterID string    `json:"clusterID" bson:"cluster_id"`
+	Namespace string    `json:"namespace" bson:"namespace"`
+	Pod       string    `json:"pod" bson:"pod"`
+	Container string    `json:"container" bson:"container"`
+	User      string    `json:"user" bson:"user"`
+	SourceIP  string    `json:"sourceIP,omitempty" bson:"source_ip,omitempty"`
+	TTY       bool      `json:"tty" bson:"tty"`
+	Width     uint16    `json:"width,omitempty" bson:"width,omitempty"`
+	Height    uint16    `json:"height,omitempty" bson:"height,omitempty"`
+	StartedAt time.Time `json:"startedAt" bson:"started_at"`
+	EndedAt   time.Time `json:"endedAt" bson:"ended_at"`
+	Bytes     int64     `json:"bytes" bson:"bytes"`
+	Truncated bool      `json:"truncated" bson:"truncated"`
+	Events    []Event   `json:"events,omitempty" bson:"events,omitempty"`
+}
+
+// Query holds the filters used to search recordings
+type Query struct {
+	User      string
+	Cluster   string
+	Namespace string
+	Pod       string
+	Since     time.Time
+	Until     time.Time
+	Limit     int64
+}
+
+// castHeader is the first line of an asciinema v2 file
+type castHeader struct {
+	Version   int    `json:"version"`
+	Width     uint16 `json:"width"`
+	Height    uint16 `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title,omitempty"`
+}
+
+// WriteCast writes a recording as an asciinema v2 file, which players such
+// as asciinema-player replay
+func (r *Recording) WriteCast(w io.Writer) error {
+	width, height := r.Width, r.Height
+	if width == 0 || height == 0 {
+		width, height = 80, 24
+	}
+
+	encoder := json.NewEncoder(w)
+	header := castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.StartedAt.Unix(),
+		Title:     fmt.Sprintf("%s %s/%s/%s by %s", r.Session, r.Namespace, r.Pod, r.Container, r.User),
+	}
+	if err := encoder.Encode(header); err != nil {
+		return err
+	}
+
+	for _, event := range r.Events {
+		if err := encoder.Encode([]any{event.Time, event.Type, event.Data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
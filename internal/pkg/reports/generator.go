@@ -0,0 +1,184 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets/capacity"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/cost"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/problems"
+)
+
+// Document is the generated content of a report, one section per cluster
+type Document struct {
+	Name        string    `json:"name"`
+	Team        string    `json:"team,omitempty"`
+	Kind        string    `json:"kind"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Clusters    []Section `json:"clusters"`
+}
+
+// Section is the part of a report covering one cluster. Only the field of
+// the report's kind is set; Error is set instead when the cluster could not
+// be read.
+type Section struct {
+	ClusterID string           `json:"clusterID"`
+	Problems  *ProblemsSummary `json:"problems,omitempty"`
+	Cost      *cost.Report     `json:"cost,omitempty"`
+	Capacity  *capacity.Report `json:"capacity,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// ProblemsSummary is the open problems of a cluster within a report's scope
+type ProblemsSummary struct {
+	Score    int                `json:"score"`
+	Critical int                `json:"critical"`
+	Warning  int                `json:"warning"`
+	Problems []problems.Problem `json:"problems"`
+}
+
+// Generator builds report documents from the problem engine and the cost and
+// capacity providers
+type Generator struct {
+	clusterManager *cluster.Manager
+	problems       *problems.Engine
+	cost           *cost.CostProvider
+	capacity       *capacity.CapacityProvider
+}
+
+// NewGenerator creates a new generator
+func NewGenerator(clusterManager *cluster.Manager, problemsEngine *problems.Engine, costProvider *cost.CostProvider, capacityProvider *capacity.CapacityProvider) *Generator {
+	return &Generator{
+		clusterManager: clusterManager,
+		problems:       problemsEngine,
+		cost:           costProvider,
+		capacity:       capacityProvider,
+	}
+}
+
+// Generate builds the document of a report for the connected clusters in its scope
+func (g *Generator) Generate(ctx context.Context, report *Report) (*Document, error) {
+	clusterIDs := make([]string, 0)
+	for clusterID, conn := range g.clusterManager.GetConnections() {
+		if conn.IsConnected() && report.inScope(clusterID, "") {
+			clusterIDs = append(clusterIDs, clusterID)
+		}
+	}
+	sort.Strings(clusterIDs)
+
+	if len(clusterIDs) == 0 {
+		return nil, fmt.Errorf("no connected clusters in scope")
+	}
+
+	document := &Document{
+		Name:        report.Name,
+		Team:        report.Team,
+		Kind:        report.Kind,
+		GeneratedAt: time.Now().UTC(),
+		Clusters:    make([]Section, 0, len(clusterIDs)),
+	}
+
+	for _, clusterID := range clusterIDs {
+		section := Section{ClusterID: clusterID}
+
+		var err error
+		switch report.Kind {
+		case KindProblems:
+			section.Problems, err = g.problemsSummary(ctx, report, clusterID)
+		case KindCost:
+			section.Cost, err = g.costReport(ctx, report, clusterID)
+		case KindCapacity:
+			section.Capacity, err = g.capacityReport(ctx, report, clusterID)
+		default:
+			return nil, fmt.Errorf("unsupported report kind %q", report.Kind)
+		}
+		if err != nil {
+			section.Error = err.Error()
+		}
+
+		document.Clusters = append(document.Clusters, section)
+	}
+
+	return document, nil
+}
+
+// problemsSummary returns the open problems of the namespaces in scope
+func (g *Generator) problemsSummary(ctx context.Context, report *Report, clusterID string) (*ProblemsSummary, error) {
+	open, err := g.problems.Problems(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ProblemsSummary{Problems: make([]problems.Problem, 0, len(open))}
+	for _, problem := range open {
+		if !report.inScope(clusterID, problem.Namespace) {
+			continue
+		}
+
+		summary.Problems = append(summary.Problems, problem)
+		if problem.Severity == problems.SeverityCritical {
+			summary.Critical++
+		} else {
+			summary.Warning++
+		}
+	}
+	summary.Score = problems.Score(summary.Problems)
+
+	// Critical first, then the longest standing
+	sort.SliceStable(summary.Problems, func(i, j int) bool {
+		a, b := summary.Problems[i], summary.Problems[j]
+		if a.Severity != b.Severity {
+			return a.Severity == problems.SeverityCritical
+		}
+		return a.Since.Before(b.Since)
+	})
+
+	return summary, nil
+}
+
+// costReport returns the cost of the namespaces in scope, by namespace
+func (g *Generator) costReport(ctx context.Context, report *Report, clusterID string) (*cost.Report, error) {
+	estimate, err := g.cost.Estimate(ctx, clusterID, "", cost.GroupByNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Namespaces) == 0 {
+		return estimate, nil
+	}
+
+	scoped := *estimate
+	scoped.Hourly, scoped.Monthly = 0, 0
+	scoped.Items = make([]cost.Item, 0, len(report.Namespaces))
+	for _, item := range estimate.Items {
+		if !report.inScope(clusterID, item.Namespace) {
+			continue
+		}
+		scoped.Items = append(scoped.Items, item)
+		scoped.Hourly += item.Hourly
+		scoped.Monthly += item.Monthly
+	}
+
+	return &scoped, nil
+}
+
+// capacityReport returns the capacity of a cluster. Capacity is shared by
+// every namespace, so only pending pods are limited to the namespaces in scope.
+func (g *Generator) capacityReport(ctx context.Context, report *Report, clusterID string) (*capacity.Report, error) {
+	result, err := g.capacity.Report(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]capacity.PendingPod, 0, len(result.PendingPods))
+	for _, pod := range result.PendingPods {
+		if report.inScope(clusterID, pod.Namespace) {
+			pending = append(pending, pod)
+		}
+	}
+	result.PendingPods = pending
+
+	return result, nil
+}
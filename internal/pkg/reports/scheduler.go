@@ -0,0 +1,182 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/alerting"
+)
+
+// Deliverer sends a rendered report to an alert sink
+type Deliverer interface {
+	Deliver(ctx context.Context, sink alerting.Sink, subject, text string, payload interface{}) error
+}
+
+// SinkLister reads the configured alert sinks
+type SinkLister interface {
+	ListAlertSinks(ctx context.Context, results *[]alerting.Sink) error
+}
+
+// Store persists reports and reads the sinks they are delivered to
+type Store interface {
+	Repository
+	SinkLister
+}
+
+// Payload is what webhook sinks receive
+type Payload struct {
+	Subject  string    `json:"subject"`
+	Text     string    `json:"text"`
+	Document *Document `json:"report"`
+}
+
+// Scheduler sends reports that are due on every interval
+type Scheduler struct {
+	generator *Generator
+	deliverer Deliverer
+	store     Store
+	config    Config
+	logger    *slog.Logger
+}
+
+// NewScheduler creates a new scheduler, filling in defaults for unset durations
+func NewScheduler(generator *Generator, deliverer Deliverer, store Store, config Config, logger *slog.Logger) *Scheduler {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 2 * time.Minute
+	}
+
+	return &Scheduler{
+		generator: generator,
+		deliverer: deliverer,
+		store:     store,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// Start checks for due reports on every interval until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runDue(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	s.logger.Info("Report scheduler started", "interval", s.config.Interval)
+}
+
+// Preview generates and renders a report without delivering it
+func (s *Scheduler) Preview(ctx context.Context, report *Report) (*Payload, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	return s.render(ctx, report)
+}
+
+// Run generates a report, delivers it to its sinks and records the outcome
+// on the report
+func (s *Scheduler) Run(ctx context.Context, report *Report) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	err := s.deliver(ctx, report)
+
+	report.LastRunAt = time.Now().UTC()
+	report.LastError = ""
+	if err != nil {
+		report.LastError = err.Error()
+	}
+
+	if saveErr := s.store.SaveReport(ctx, report); saveErr != nil {
+		s.logger.Error("Failed to record report run", "report", report.Name, "error", saveErr)
+	}
+
+	return err
+}
+
+// runDue runs every report whose scheduled time has passed
+func (s *Scheduler) runDue(ctx context.Context) {
+	var reports []Report
+	if err := s.store.ListReports(ctx, &reports); err != nil {
+		s.logger.Error("Failed to load reports", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range reports {
+		if ctx.Err() != nil {
+			return
+		}
+		if !reports[i].Due(now) {
+			continue
+		}
+
+		if err := s.Run(ctx, &reports[i]); err != nil {
+			s.logger.Error("Failed to send report", "report", reports[i].Name, "team", reports[i].Team, "error", err)
+			continue
+		}
+		s.logger.Debug("Report sent", "report", reports[i].Name, "team", reports[i].Team)
+	}
+}
+
+// deliver renders a report and sends it to each of its sinks
+func (s *Scheduler) deliver(ctx context.Context, report *Report) error {
+	payload, err := s.render(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	var sinks []alerting.Sink
+	if err := s.store.ListAlertSinks(ctx, &sinks); err != nil {
+		return fmt.Errorf("failed to load alert sinks: %w", err)
+	}
+
+	sinksByID := make(map[string]alerting.Sink, len(sinks))
+	for _, sink := range sinks {
+		sinksByID[sink.ID] = sink
+	}
+
+	var errs []error
+	for _, sinkID := range report.Sinks {
+		sink, ok := sinksByID[sinkID]
+		if !ok {
+			errs = append(errs, fmt.Errorf("sink %s not found", sinkID))
+			continue
+		}
+
+		if err := s.deliverer.Deliver(ctx, sink, payload.Subject, payload.Text, payload); err != nil {
+			errs = append(errs, fmt.Errorf("sink %s: %w", sink.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// render generates a report and renders it with its template
+func (s *Scheduler) render(ctx context.Context, report *Report) (*Payload, error) {
+	document, err := s.generator.Generate(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, text, err := Render(report, document)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Payload{Subject: subject, Text: text, Document: document}, nil
+}
@@ -0,0 +1,80 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are available to built-in and custom templates
+var templateFuncs = template.FuncMap{
+	"cores": func(millicores int64) string {
+		return fmt.Sprintf("%.1f", float64(millicores)/1000)
+	},
+	"gib": func(bytes int64) string {
+		return fmt.Sprintf("%.1f", float64(bytes)/(1<<30))
+	},
+	"money": func(amount float64) string {
+		return fmt.Sprintf("%.2f", amount)
+	},
+	"upper": strings.ToUpper,
+}
+
+// defaultTemplates are the built-in templates of each kind, executed against a Document
+var defaultTemplates = map[string]string{
+	KindProblems: `{{range .Clusters}}Cluster {{.ClusterID}}
+{{- if .Error}}: unavailable ({{.Error}})
+{{else}}: health {{.Problems.Score}}/100, {{.Problems.Critical}} critical, {{.Problems.Warning}} warning
+{{range .Problems.Problems}}  [{{upper .Severity}}] {{.Rule}} {{.Kind}} {{if .Namespace}}{{.Namespace}}/{{end}}{{.Name}}: {{.Message}}
+{{end}}{{end}}
+{{end}}`,
+
+	KindCost: `{{range .Clusters}}Cluster {{.ClusterID}}
+{{- if .Error}}: unavailable ({{.Error}})
+{{else}}{{$currency := .Cost.Currency}}: {{money .Cost.Monthly}} {{$currency}}/month
+{{range .Cost.Items}}  {{.Namespace}}: {{money .Monthly}} {{$currency}}/month, {{.Pods}} pods, {{printf "%.1f" .CPUCores}} cores, {{printf "%.1f" .MemoryGB}} GB
+{{end}}{{end}}
+{{end}}`,
+
+	KindCapacity: `{{range .Clusters}}Cluster {{.ClusterID}}
+{{- if .Error}}: unavailable ({{.Error}})
+{{else}}: {{cores .Capacity.Free.CPUMillicores}} cores and {{gib .Capacity.Free.MemoryBytes}} GiB free of {{cores .Capacity.Allocatable.CPUMillicores}} cores and {{gib .Capacity.Allocatable.MemoryBytes}} GiB
+{{range .Capacity.Pools}}  Pool {{.Name}}: {{len .Nodes}} nodes, {{cores .Free.CPUMillicores}} cores and {{gib .Free.MemoryBytes}} GiB free
+{{end}}{{if .Capacity.PendingPods}}  Pending on resources:
+{{range .Capacity.PendingPods}}    {{.Namespace}}/{{.Name}}: {{.Message}}
+{{end}}{{end}}{{end}}
+{{end}}`,
+}
+
+// Render returns the subject and text of a report document, using the
+// report's own template when it has one
+func Render(report *Report, document *Document) (subject, text string, err error) {
+	source := report.Template
+	if source == "" {
+		source = defaultTemplates[report.Kind]
+	}
+
+	tmpl, err := template.New(report.Name).Funcs(templateFuncs).Parse(source)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var body strings.Builder
+	if err := tmpl.Execute(&body, document); err != nil {
+		return "", "", fmt.Errorf("failed to render report: %w", err)
+	}
+
+	subject = fmt.Sprintf("%s report: %s (%s)", titles[report.Kind], report.Name, document.GeneratedAt.Format("2006-01-02 15:04 MST"))
+	if report.Team != "" {
+		subject = "[" + report.Team + "] " + subject
+	}
+
+	return subject, body.String(), nil
+}
+
+// titles name each kind in subjects
+var titles = map[string]string{
+	KindProblems: "Problems",
+	KindCost:     "Cost",
+	KindCapacity: "Capacity",
+}
@@ -0,0 +1,145 @@
+// Package reports generates periodic summaries of cluster state, such as
+// open problems, cost and capacity, and delivers them to alert sinks.
+package reports
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"text/template"
+	"time"
+)
+
+// Report kinds
+const (
+	KindProblems = "problems"
+	KindCost     = "cost"
+	KindCapacity = "capacity"
+)
+
+// Schedule frequencies
+const (
+	FrequencyHourly = "hourly"
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// Config controls the report scheduler
+type Config struct {
+	// Interval is how often the scheduler looks for reports that are due
+	Interval time.Duration `yaml:"interval"`
+	// Timeout bounds generating and delivering a single report
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Schedule is when a report is sent. Hour and Weekday are in UTC; Hour is
+// ignored for hourly reports and Weekday is only used by weekly ones.
+type Schedule struct {
+	Frequency string       `json:"frequency" bson:"frequency"`
+	Hour      int          `json:"hour" bson:"hour"`
+	Weekday   time.Weekday `json:"weekday" bson:"weekday"`
+}
+
+// Report is a scheduled report definition. A report belongs to a team and is
+// scoped to its clusters and namespaces; empty lists cover everything.
+type Report struct {
+	ID         string   `json:"id" bson:"_id,omitempty"`
+	Name       string   `json:"name" bson:"name"`
+	Team       string   `json:"team,omitempty" bson:"team,omitempty"`
+	Kind       string   `json:"kind" bson:"kind"`
+	Schedule   Schedule `json:"schedule" bson:"schedule"`
+	Clusters   []string `json:"clusters,omitempty" bson:"clusters,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty" bson:"namespaces,omitempty"`
+	// Sinks are the IDs of the alert sinks the report is delivered to
+	Sinks []string `json:"sinks" bson:"sinks"`
+	// Template overrides the built-in text template of the kind. It is
+	// executed against a Document.
+	Template string `json:"template,omitempty" bson:"template,omitempty"`
+	Disabled bool   `json:"disabled,omitempty" bson:"disabled,omitempty"`
+
+	LastRunAt time.Time `json:"lastRunAt,omitempty" bson:"last_run_at,omitempty"`
+	LastError string    `json:"lastError,omitempty" bson:"last_error,omitempty"`
+}
+
+// Validate checks that a report can be generated and delivered
+func (r *Report) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("report requires a name")
+	}
+
+	switch r.Kind {
+	case KindProblems, KindCost, KindCapacity:
+	default:
+		return fmt.Errorf("unsupported report kind %q", r.Kind)
+	}
+
+	switch r.Schedule.Frequency {
+	case FrequencyHourly, FrequencyDaily, FrequencyWeekly:
+	default:
+		return fmt.Errorf("unsupported frequency %q", r.Schedule.Frequency)
+	}
+	if r.Schedule.Hour < 0 || r.Schedule.Hour > 23 {
+		return fmt.Errorf("hour must be between 0 and 23")
+	}
+	if r.Schedule.Weekday < time.Sunday || r.Schedule.Weekday > time.Saturday {
+		return fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+
+	if len(r.Sinks) == 0 {
+		return fmt.Errorf("report requires at least one sink")
+	}
+
+	if r.Template != "" {
+		if _, err := template.New(r.Name).Funcs(templateFuncs).Parse(r.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Due reports whether the report should be sent at now: the most recent
+// scheduled time has passed and the report has not run since
+func (r *Report) Due(now time.Time) bool {
+	if r.Disabled {
+		return false
+	}
+	return r.LastRunAt.Before(r.previous(now.UTC()))
+}
+
+// previous returns the most recent scheduled time at or before now
+func (r *Report) previous(now time.Time) time.Time {
+	switch r.Schedule.Frequency {
+	case FrequencyHourly:
+		return now.Truncate(time.Hour)
+	case FrequencyWeekly:
+		at := time.Date(now.Year(), now.Month(), now.Day(), r.Schedule.Hour, 0, 0, 0, time.UTC)
+		at = at.AddDate(0, 0, -int((7+now.Weekday()-r.Schedule.Weekday)%7))
+		if at.After(now) {
+			at = at.AddDate(0, 0, -7)
+		}
+		return at
+	default:
+		at := time.Date(now.Year(), now.Month(), now.Day(), r.Schedule.Hour, 0, 0, 0, time.UTC)
+		if at.After(now) {
+			at = at.AddDate(0, 0, -1)
+		}
+		return at
+	}
+}
+
+// inScope reports whether a cluster and namespace are covered by the report
+func (r *Report) inScope(clusterID, namespace string) bool {
+	return matches(r.Clusters, clusterID) && (namespace == "" || matches(r.Namespaces, namespace))
+}
+
+// Repository persists report definitions
+type Repository interface {
+	ListReports(ctx context.Context, results *[]Report) error
+	SaveReport(ctx context.Context, report *Report) error
+}
+
+// matches reports whether value is allowed by a match list
+func matches(allowed []string, value string) bool {
+	return len(allowed) == 0 || slices.Contains(allowed, value)
+}
@@ -0,0 +1,335 @@
+// Package restarts explains why the containers of a pod restarted from
+// their last termination state and the pod's probe events.
+package restarts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Restart causes
+const (
+	CauseOOMKilled     = "OOMKilled"
+	CauseLivenessProbe = "LivenessProbe"
+	CauseStartupProbe  = "StartupProbe"
+	CauseError         = "Error"
+	CauseSignal        = "Signal"
+	CauseCompleted     = "Completed"
+	CauseUnknown       = "Unknown"
+)
+
+// probeWindow is how close to a termination a probe kill must be seen to be
+// taken as its cause
+const probeWindow = 2 * time.Minute
+
+// ProbeFailure is a probe that failed on a container, from the pod's events
+type ProbeFailure struct {
+	Probe    string    `json:"probe"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// Container explains the last restart of a container
+type Container struct {
+	Name          string         `json:"name"`
+	Init          bool           `json:"init,omitempty"`
+	RestartCount  int32          `json:"restartCount"`
+	Cause         string         `json:"cause"`
+	Reason        string         `json:"reason,omitempty"`
+	ExitCode      int32          `json:"exitCode"`
+	Signal        int32          `json:"signal,omitempty"`
+	Message       string         `json:"message,omitempty"`
+	StartedAt     *time.Time     `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time     `json:"finishedAt,omitempty"`
+	CrashLooping  bool           `json:"crashLooping"`
+	ProbeFailures []ProbeFailure `json:"probeFailures"`
+	Summary       string         `json:"summary"`
+}
+
+// Summary explains the restarts of a pod's containers. Only containers that
+// restarted are listed.
+type Summary struct {
+	Namespace    string      `json:"namespace"`
+	Name         string      `json:"name"`
+	RestartCount int32       `json:"restartCount"`
+	Containers   []Container `json:"containers"`
+	Summary      string      `json:"summary"`
+}
+
+// Load reads a pod and its events and summarizes its restarts
+func Load(ctx context.Context, client kubernetes.Interface, namespace, name string) (*Summary, error) {
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", string(pod.UID)).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	return Summarize(pod, events.Items), nil
+}
+
+// Summarize explains the restarts of a pod from its container statuses and events
+func Summarize(pod *corev1.Pod, events []corev1.Event) *Summary {
+	summary := &Summary{
+		Namespace:  pod.Namespace,
+		Name:       pod.Name,
+		Containers: make([]Container, 0),
+	}
+
+	probes := probeFailures(events)
+	kills := probeKills(events)
+
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for i, status := range statuses {
+		if status.RestartCount == 0 {
+			continue
+		}
+
+		container := explain(status, probes[status.Name], kills[status.Name])
+		container.Init = i < len(pod.Status.InitContainerStatuses)
+		summary.RestartCount += status.RestartCount
+		summary.Containers = append(summary.Containers, container)
+	}
+
+	switch len(summary.Containers) {
+	case 0:
+		summary.Summary = "No containers restarted"
+	case 1:
+		summary.Summary = summary.Containers[0].Summary
+	default:
+		summary.Summary = fmt.Sprintf("%d containers restarted %d times", len(summary.Containers), summary.RestartCount)
+	}
+
+	return summary
+}
+
+// Text renders a summary for display, one paragraph per container
+func (s *Summary) Text() string {
+	var b strings.Builder
+	b.WriteString(s.Summary)
+	b.WriteString("\n")
+
+	for _, container := range s.Containers {
+		if len(s.Containers) > 1 {
+			fmt.Fprintf(&b, "\n%s: %s\n", container.Name, container.Summary)
+		}
+		if container.Message != "" {
+			fmt.Fprintf(&b, "  Message: %s\n", container.Message)
+		}
+		for _, probe := range container.ProbeFailures {
+			fmt.Fprintf(&b, "  %s probe failed %d times, last %s: %s\n",
+				probe.Probe, probe.Count, probe.LastSeen.Format(time.RFC3339), probe.Message)
+		}
+	}
+
+	return b.String()
+}
+
+// explain finds the cause of a container's last restart
+func explain(status corev1.ContainerStatus, probes []ProbeFailure, kills []probeKill) Container {
+	container := Container{
+		Name:          status.Name,
+		RestartCount:  status.RestartCount,
+		Cause:         CauseUnknown,
+		CrashLooping:  status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff",
+		ProbeFailures: probes,
+	}
+	if container.ProbeFailures == nil {
+		container.ProbeFailures = make([]ProbeFailure, 0)
+	}
+
+	terminated := status.LastTerminationState.Terminated
+	if terminated == nil {
+		container.Summary = withRestarts(container, "restarted, but its last termination was not recorded")
+		return container
+	}
+
+	container.Reason = terminated.Reason
+	container.ExitCode = terminated.ExitCode
+	container.Signal = terminated.Signal
+	container.Message = strings.TrimSpace(terminated.Message)
+	if !terminated.StartedAt.IsZero() {
+		container.StartedAt = &terminated.StartedAt.Time
+	}
+	if !terminated.FinishedAt.IsZero() {
+		container.FinishedAt = &terminated.FinishedAt.Time
+	}
+
+	var cause string
+	switch {
+	case terminated.Reason == "OOMKilled":
+		container.Cause = CauseOOMKilled
+		cause = "was killed for exceeding its memory limit (OOMKilled)"
+	case killedByProbe(kills, terminated.FinishedAt.Time) != "":
+		probe := killedByProbe(kills, terminated.FinishedAt.Time)
+		container.Cause = CauseLivenessProbe
+		if probe == "startup" {
+			container.Cause = CauseStartupProbe
+		}
+		cause = fmt.Sprintf("was restarted by the kubelet after failing its %s probe", probe)
+	case terminated.ExitCode == 0:
+		container.Cause = CauseCompleted
+		cause = "exited successfully and was started again by the pod's restart policy"
+	case terminated.ExitCode > 128 || terminated.Signal != 0:
+		container.Cause = CauseSignal
+		cause = fmt.Sprintf("exited with code %d: %s", terminated.ExitCode, exitCodeMeaning(terminated.ExitCode))
+	default:
+		container.Cause = CauseError
+		cause = fmt.Sprintf("exited with code %d: %s", terminated.ExitCode, exitCodeMeaning(terminated.ExitCode))
+	}
+
+	if container.CrashLooping {
+		cause += "; it is in CrashLoopBackOff"
+	}
+	container.Summary = withRestarts(container, cause)
+	return container
+}
+
+// withRestarts prefixes a cause with the container's name and restart count
+func withRestarts(container Container, cause string) string {
+	times := "times"
+	if container.RestartCount == 1 {
+		times = "time"
+	}
+	return fmt.Sprintf("Container %s restarted %d %s and last %s", container.Name, container.RestartCount, times, cause)
+}
+
+// exitCodeMeaning describes common exit codes, including 128+n for signal n
+func exitCodeMeaning(code int32) string {
+	switch code {
+	case 1:
+		return "the application failed"
+	case 2:
+		return "invalid arguments or shell usage"
+	case 126:
+		return "the command is not executable"
+	case 127:
+		return "the command was not found"
+	case 128 + 2:
+		return "interrupted (SIGINT)"
+	case 128 + 6:
+		return "aborted (SIGABRT)"
+	case 128 + 9:
+		return "killed (SIGKILL), often by the kubelet or the kernel OOM killer"
+	case 128 + 11:
+		return "segmentation fault (SIGSEGV)"
+	case 128 + 15:
+		return "terminated (SIGTERM)"
+	}
+
+	if code > 128 && code < 128+65 {
+		return fmt.Sprintf("killed by signal %d", code-128)
+	}
+	return "the application failed"
+}
+
+// probeKill is a kubelet event restarting a container for a failed probe
+type probeKill struct {
+	probe string
+	at    time.Time
+}
+
+// killedByProbe returns the probe whose kill was seen close to a termination
+func killedByProbe(kills []probeKill, finishedAt time.Time) string {
+	for _, kill := range kills {
+		if finishedAt.IsZero() || kill.at.Sub(finishedAt).Abs() <= probeWindow {
+			return kill.probe
+		}
+	}
+	return ""
+}
+
+// probeFailures groups liveness and startup probe failures by container.
+// Readiness failures don't restart containers and are left out.
+func probeFailures(events []corev1.Event) map[string][]ProbeFailure {
+	failures := make(map[string][]ProbeFailure)
+	for _, event := range events {
+		if event.Reason != "Unhealthy" {
+			continue
+		}
+
+		probe, message, ok := strings.Cut(event.Message, " probe failed: ")
+		if !ok || (probe != "Liveness" && probe != "Startup") {
+			continue
+		}
+
+		container := containerOf(event.InvolvedObject.FieldPath)
+		failures[container] = append(failures[container], ProbeFailure{
+			Probe:    strings.ToLower(probe),
+			Message:  strings.TrimSpace(message),
+			Count:    max(event.Count, 1),
+			LastSeen: lastSeen(&event),
+		})
+	}
+
+	for _, list := range failures {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].LastSeen.After(list[j].LastSeen)
+		})
+	}
+	return failures
+}
+
+// probeKills finds the kubelet's "failed liveness probe, will be restarted"
+// events by container
+func probeKills(events []corev1.Event) map[string][]probeKill {
+	kills := make(map[string][]probeKill)
+	for _, event := range events {
+		if event.Reason != "Killing" {
+			continue
+		}
+
+		var probe string
+		switch {
+		case strings.Contains(event.Message, "failed liveness probe"):
+			probe = "liveness"
+		case strings.Contains(event.Message, "failed startup probe"):
+			probe = "startup"
+		default:
+			continue
+		}
+
+		container := containerOf(event.InvolvedObject.FieldPath)
+		kills[container] = append(kills[container], probeKill{probe: probe, at: lastSeen(&event)})
+	}
+	return kills
+}
+
+// containerOf extracts the container name from a field path such as spec.containers{app}
+func containerOf(fieldPath string) string {
+	_, name, ok := strings.Cut(fieldPath, "{")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSuffix(name, "}")
+}
+
+// lastSeen returns when an event was last seen
+func lastSeen(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -0,0 +1,18 @@
+package revision
+
+import "time"
+
+// Revision is a recorded version of the data of a ConfigMap
+type Revision struct {
+	ID              string            `json:"id" bson:"_id,omitempty"`
+	ClusterID       string            `json:"clusterID" bson:"cluster_id"`
+	Namespace       string            `json:"namespace" bson:"namespace"`
+	Kind            string            `json:"kind" bson:"kind"`
+	Name            string            `json:"name" bson:"name"`
+	Version         int               `json:"version" bson:"version"`
+	ResourceVersion string            `json:"resourceVersion,omitempty" bson:"resource_version,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt" bson:"created_at"`
+	CreatedBy       string            `json:"createdBy,omitempty" bson:"created_by,omitempty"`
+	Data            map[string]string `json:"data,omitempty" bson:"data,omitempty"`
+	BinaryData      map[string][]byte `json:"binaryData,omitempty" bson:"binary_data,omitempty"`
+}
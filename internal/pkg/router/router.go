@@ -2,10 +2,18 @@ package router
 
 import (
 	"log/slog"
+	"slices"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/bulk"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/drift"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/pdbs"
+	"github.com/jbetancur/dashboard/internal/pkg/audit"
 	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/features"
+	"github.com/jbetancur/dashboard/internal/pkg/output"
 	"github.com/jbetancur/dashboard/internal/pkg/services"
 )
 
@@ -14,20 +22,131 @@ func SetupRoutes(
 	clusterService *services.ClusterService,
 	namespaceService *services.NamespaceService,
 	podService *services.PodService,
+	logArchiveService *services.LogArchiveService,
 	configMapService *services.ConfigMapService,
+	auditService *services.AuditService,
+	authzService *services.AuthzService,
+	listCacheService *services.ListCacheService,
+	eventQueueService *services.EventQueueService,
+	metricsService *services.MetricsService,
+	prometheusService *services.PrometheusService,
+	costService *services.CostService,
+	rightsizingService *services.RightsizingService,
+	capacityService *services.CapacityService,
+	problemsService *services.ProblemsService,
+	alertingService *services.AlertingService,
+	reportService *services.ReportService,
+	webhookService *services.WebhookService,
+	timelineService *services.TimelineService,
+	diffService *services.DiffService,
+	reconcileService *services.ReconcileService,
+	graphService *services.GraphService,
+	topologyService *services.TopologyService,
+	fleetService *services.FleetService,
+	searchService *services.SearchService,
+	driftService *services.DriftService,
+	snapshotService *services.SnapshotService,
+	supportBundleService *services.SupportBundleService,
+	bulkService *services.BulkService,
+	deploymentService *services.DeploymentService,
+	cronJobService *services.CronJobService,
+	imageService *services.ImageService,
+	vulnerabilityService *services.VulnerabilityService,
+	complianceService *services.ComplianceService,
+	certificateService *services.CertificateService,
+	pdbService *services.PDBService,
+	storageService *services.StorageService,
+	registrationService *services.RegistrationService,
+	agentService *services.AgentService,
+	describeService *services.DescribeService,
+	noteService *services.NoteService,
+	fileService *services.FileService,
+	apiResourceService *services.APIResourceService,
+	nodeService *services.NodeService,
+	recordingService *services.RecordingService,
+	featureService *services.FeatureService,
+	preferenceService *services.PreferenceService,
+	loggingService *services.LoggingService,
+	watchService *services.WatchService,
+	liveService *services.LiveService,
+	flags *features.Manager,
+	auditor *audit.Auditor,
 	authorizer auth.Authorizer,
 	logger *slog.Logger) {
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.SendString("OK")
 	})
 
-	// API group with versioning
-	api := app.Group("/api/v1")
+	// API group with versioning, every call is recorded in the audit log.
+	// Responses are rendered as JSON, YAML or a text table as requested.
+	api := app.Group("/api/v1", audit.Middleware(auditor), output.Middleware())
+
+	// Audit routes
+	api.Get("/audit",
+		auth.AuthMiddleware(),
+		auth.RequireAdmin(),
+		auditService.ListAuditEntries)
+
+	api.Get("/audit/export",
+		auth.AuthMiddleware(),
+		auth.RequireAdmin(),
+		auditService.ExportAuditEntries)
+
+	// The signed-in user's favorites, default view and table columns
+	me := api.Group("/me", auth.AuthMiddleware())
+	me.Get("/preferences", preferenceService.GetPreferences)
+	me.Put("/preferences", preferenceService.SavePreferences)
+	me.Delete("/preferences", preferenceService.DeletePreferences)
+
+	// Admin routes
+	admin := api.Group("/admin", auth.AuthMiddleware(), auth.RequireAdmin())
+	admin.Get("/authz/cache", authzService.GetCacheStats)
+	admin.Delete("/authz/cache", authzService.InvalidateCache)
+	admin.Get("/lists/cache", listCacheService.GetCacheStats)
+	admin.Delete("/lists/cache", listCacheService.InvalidateCache)
+	admin.Get("/events/queue", eventQueueService.GetQueueStats)
+
+	// Log level, changeable without a restart
+	admin.Get("/logging", loggingService.GetLogging)
+	admin.Put("/logging", loggingService.SetLogLevel)
+
+	// Clusters registered at runtime from uploaded credentials
+	admin.Get("/clusters", registrationService.ListRegisteredClusters)
+	admin.Post("/clusters", registrationService.RegisterCluster)
+	admin.Delete("/clusters/:clusterID", registrationService.UnregisterCluster)
+
+	// Deployment-wide feature flags; per-cluster overrides are set below
+	api.Get("/features", auth.AuthMiddleware(), featureService.ListFeatures)
+	admin.Put("/features/:feature", featureService.SetFeature)
+
+	// Agents serving the clusters, their credentials and per-cluster feature flags
+	admin.Get("/agents", agentService.ListAgents)
+	// Resyncs and reconciliations rewrite the stored cluster, so read-only mode stops them too
+	admin.Post("/clusters/:clusterID/resync", features.Require(flags, features.WriteOperations), agentService.ResyncCluster)
+	admin.Post("/clusters/:clusterID/reconcile", features.Require(flags, features.WriteOperations), reconcileService.ReconcileCluster)
+	admin.Post("/clusters/:clusterID/agent/credentials", agentService.RotateCredentials)
+	admin.Get("/clusters/:clusterID/features", agentService.GetClusterFeatures)
+	admin.Put("/clusters/:clusterID/features/:feature", agentService.SetClusterFeature)
+
+	// Recorded attach sessions, for review and replay
+	admin.Get("/recordings", recordingService.ListRecordings)
+	admin.Get("/recordings/:recordingID", recordingService.GetRecording)
+	admin.Get("/recordings/:recordingID/cast", recordingService.GetRecordingCast)
 
 	// Cluster routes
 	api.Get("/clusters", clusterService.ListClusters)
 	api.Get("/clusters/:clusterID", clusterService.GetCluster)
 
+	// Resource types the cluster serves, for building navigation
+	api.Get("/clusters/:clusterID/api-resources",
+		auth.AuthMiddleware(),
+		apiResourceService.ListAPIResources)
+
+	// Permission preview for the current user
+	api.Get("/clusters/:clusterID/can-i",
+		auth.AuthMiddleware(),
+		authzService.CanI)
+
 	// Namespace routes
 	api.Get("/clusters/:clusterID/namespaces",
 		auth.AuthMiddleware(),
@@ -48,6 +167,29 @@ func SetupRoutes(
 		}),
 		namespaceService.GetNamespace)
 
+	// Namespaces created from a template also get its ResourceQuota and
+	// LimitRange, which the service checks separately
+	api.Post("/clusters/:clusterID/namespaces",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "namespaces",
+			Verb:         "create",
+			ClusterParam: "clusterID",
+		}),
+		namespaceService.CreateNamespace)
+
+	api.Delete("/clusters/:clusterID/namespaces/:namespaceID",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "namespaces",
+			Verb:         "delete",
+			ClusterParam: "clusterID",
+			NameParam:    "namespaceID",
+		}),
+		namespaceService.DeleteNamespace)
+
 	// Pod routes
 	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods",
 		auth.AuthMiddleware(),
@@ -70,10 +212,350 @@ func SetupRoutes(
 		}),
 		podService.GetPod)
 
+	api.Delete("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "delete",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "podID",
+		}),
+		podService.DeletePod)
+
+	// Pod metrics from metrics-server, with sampled history
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/metrics/pods",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods.metrics.k8s.io",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		metricsService.ListPodMetrics)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/metrics",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods.metrics.k8s.io",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "podID",
+		}),
+		metricsService.GetPodMetrics)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/metrics/history",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods.metrics.k8s.io",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "podID",
+		}),
+		metricsService.GetPodMetricsHistory)
+
+	// Node metrics
+	api.Get("/clusters/:clusterID/metrics/nodes",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "nodes.metrics.k8s.io",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		metricsService.ListNodeMetrics)
+
+	// Nodes and the pods scheduled on them
+	api.Get("/clusters/:clusterID/nodes/:nodeID",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "nodes",
+			Verb:         "get",
+			ClusterParam: "clusterID",
+			NameParam:    "nodeID",
+		}),
+		nodeService.GetNode)
+
+	api.Get("/clusters/:clusterID/nodes/:nodeID/pods",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "nodes",
+			Verb:         "get",
+			ClusterParam: "clusterID",
+			NameParam:    "nodeID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "pods",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		nodeService.ListNodePods)
+
+	api.Get("/clusters/:clusterID/nodes/:nodeID/metrics",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "nodes.metrics.k8s.io",
+			Verb:         "get",
+			ClusterParam: "clusterID",
+			NameParam:    "nodeID",
+		}),
+		metricsService.GetNodeMetrics)
+
+	api.Get("/clusters/:clusterID/nodes/:nodeID/metrics/history",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "nodes.metrics.k8s.io",
+			Verb:         "get",
+			ClusterParam: "clusterID",
+			NameParam:    "nodeID",
+		}),
+		metricsService.GetNodeMetricsHistory)
+
+	// Historical graphs from Prometheus
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/history",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "podID",
+		}),
+		prometheusService.GetPodHistory)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/deployments/:deploymentID/history",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "deployments.apps",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "deploymentID",
+		}),
+		prometheusService.GetDeploymentHistory)
+
+	// Raw PromQL can read any namespace, so it is limited to admins
+	api.Get("/clusters/:clusterID/prometheus/query_range",
+		auth.AuthMiddleware(),
+		auth.RequireAdmin(),
+		prometheusService.QueryRange)
+
+	// Cost showback
+	api.Get("/clusters/:clusterID/cost",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "pods",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		costService.GetClusterCost)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/cost",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		costService.GetNamespaceCost)
+
+	// Right-sizing recommendations from observed usage
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/recommendations",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		rightsizingService.ListRecommendations)
+
+	// Capacity and scheduling headroom
+	api.Get("/clusters/:clusterID/capacity",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "nodes",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		capacityService.GetCapacity)
+
+	// Why a pod's containers restarted
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/restarts",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "podID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "events",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		podService.GetRestartSummary)
+
+	// Which nodes a pod spec could be scheduled on, and why not the others
+	api.Post("/clusters/:clusterID/placement",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "nodes",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "pods",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		capacityService.ExplainPlacement)
+
+	// Why a pending pod is not scheduled
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/scheduling",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "podID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "nodes",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		capacityService.DiagnosePending)
+
+	// Problems detected by the rules engine
+	api.Get("/clusters/:clusterID/problems",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "pods",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		problemsService.ListProblems)
+
+	// Alerting configuration is global, so it is limited to admins
+	alerts := api.Group("/alerts", auth.AuthMiddleware(), auth.RequireAdmin())
+	alerts.Get("/sinks", alertingService.ListSinks)
+	alerts.Post("/sinks", alertingService.SaveSink)
+	alerts.Put("/sinks/:sinkID", alertingService.SaveSink)
+	alerts.Delete("/sinks/:sinkID", alertingService.DeleteSink)
+	alerts.Post("/sinks/:sinkID/test", alertingService.TestSink)
+	alerts.Get("/routes", alertingService.ListRoutes)
+	alerts.Post("/routes", alertingService.SaveRoute)
+	alerts.Put("/routes/:routeID", alertingService.SaveRoute)
+	alerts.Delete("/routes/:routeID", alertingService.DeleteRoute)
+	alerts.Get("/silences", alertingService.ListSilences)
+	alerts.Post("/silences", alertingService.SaveSilence)
+	alerts.Put("/silences/:silenceID", alertingService.SaveSilence)
+	alerts.Delete("/silences/:silenceID", alertingService.DeleteSilence)
+
+	// Scheduled reports, delivered to alert sinks
+	reportRoutes := api.Group("/reports", auth.AuthMiddleware(), auth.RequireAdmin())
+	reportRoutes.Get("/", reportService.ListReports)
+	reportRoutes.Post("/", reportService.SaveReport)
+	reportRoutes.Post("/preview", reportService.PreviewReport)
+	reportRoutes.Get("/:reportID", reportService.GetReport)
+	reportRoutes.Put("/:reportID", reportService.SaveReport)
+	reportRoutes.Delete("/:reportID", reportService.DeleteReport)
+	reportRoutes.Post("/:reportID/run", reportService.RunReport)
+
+	// Outbound webhooks for resource lifecycle events
+	webhookRoutes := api.Group("/webhooks", auth.AuthMiddleware(), auth.RequireAdmin())
+	webhookRoutes.Get("/", webhookService.ListWebhooks)
+	webhookRoutes.Post("/", webhookService.SaveWebhook)
+	webhookRoutes.Get("/events", webhookService.ListEventTypes)
+	webhookRoutes.Get("/:webhookID", webhookService.GetWebhook)
+	webhookRoutes.Put("/:webhookID", webhookService.SaveWebhook)
+	webhookRoutes.Delete("/:webhookID", webhookService.DeleteWebhook)
+	webhookRoutes.Post("/:webhookID/test", webhookService.TestWebhook)
+	webhookRoutes.Get("/:webhookID/deliveries", webhookService.ListDeliveries)
+	webhookRoutes.Post("/:webhookID/deliveries/:deliveryID/redeliver", webhookService.Redeliver)
+
+	// Incident timelines: one route per supported kind so RBAC is checked against the right resource
+	for _, kind := range assets.Kinds() {
+		api.Get("/clusters/:clusterID/namespaces/:namespaceID/"+kind.Resource+"/:name/timeline",
+			auth.AuthMiddleware(),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       kind.RBACResource(),
+				Verb:           "get",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+				NameParam:      "name",
+			}),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       "events",
+				Verb:           "list",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+			}),
+			timelineService.GetTimeline(kind))
+	}
+
 	// Pod logs via WebSocket
 	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/logs/:containerName",
-		auth.WebSocketAuthMiddleware(authorizer),
-		websocket.New(podService.StreamPodLogs))
+		auth.WebSocketAuthMiddleware(authorizer, auth.PodLogs),
+		websocket.New(audit.WebSocket(auditor, auth.PodLogs.Resource, auth.PodLogs.Verb, podService.StreamPodLogs)))
+
+	// Changes to stored resources via WebSocket, for clients following a table.
+	// Without a namespace in the path the kind is watched cluster-wide.
+	api.Get("/clusters/:clusterID/watch/namespaces",
+		auth.WebSocketAuthMiddleware(authorizer, auth.ResourceInfo{
+			Resource:     "namespaces",
+			Verb:         "watch",
+			ClusterParam: "clusterID",
+		}),
+		websocket.New(audit.WebSocket(auditor, "namespaces", "watch", watchService.Watch("Namespace"))))
+
+	for _, kind := range assets.Kinds() {
+		api.Get("/clusters/:clusterID/watch/namespaces/:namespaceID/"+kind.Resource,
+			auth.WebSocketAuthMiddleware(authorizer, auth.ResourceInfo{
+				Resource:       kind.RBACResource(),
+				Verb:           "watch",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+			}),
+			websocket.New(audit.WebSocket(auditor, kind.RBACResource(), "watch", watchService.Watch(kind.Kind))))
+
+		api.Get("/clusters/:clusterID/watch/"+kind.Resource,
+			auth.WebSocketAuthMiddleware(authorizer, auth.ResourceInfo{
+				Resource:     kind.RBACResource(),
+				Verb:         "watch",
+				ClusterParam: "clusterID",
+			}),
+			websocket.New(audit.WebSocket(auditor, kind.RBACResource(), "watch", watchService.Watch(kind.Kind))))
+	}
+
+	// Archived copies of container logs, kept in the object store
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/logs/:containerName/archives",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.PodLogs),
+		logArchiveService.ArchivePodLogs)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/logs/:containerName/archives",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.PodLogs),
+		logArchiveService.ListPodLogArchives)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/logs/:containerName/archives/:archive",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.PodLogs),
+		logArchiveService.DownloadPodLogArchive)
+
+	// Attach to a container's running process via WebSocket
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/attach",
+		features.Require(flags, features.Exec),
+		auth.WebSocketAuthMiddleware(authorizer, auth.PodAttach),
+		websocket.New(audit.WebSocket(auditor, auth.PodAttach.Resource, auth.PodAttach.Verb, podService.AttachPod)))
 
 	api.Get("/clusters/:clusterID/namespaces/:namespaceID/configmaps",
 		auth.AuthMiddleware(),
@@ -106,4 +588,723 @@ func SetupRoutes(
 			NameParam:      "configMapID",
 		}),
 		configMapService.GetConfigMap)
+
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/configmaps",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "create",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		configMapService.CreateConfigMap)
+
+	api.Put("/clusters/:clusterID/namespaces/:namespaceID/configmaps/:configMapID",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "update",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "configMapID",
+		}),
+		configMapService.UpdateConfigMap)
+
+	api.Delete("/clusters/:clusterID/namespaces/:namespaceID/configmaps/:configMapID",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "delete",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "configMapID",
+		}),
+		configMapService.DeleteConfigMap)
+
+	// Stored vs live diffs, to spot stale cache entries and out-of-band changes
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/diff",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "namespaces",
+			Verb:         "get",
+			ClusterParam: "clusterID",
+			NameParam:    "namespaceID",
+		}),
+		diffService.DiffNamespace)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/diff",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "podID",
+		}),
+		diffService.DiffPod)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/configmaps/:configMapID/diff",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "configMapID",
+		}),
+		diffService.DiffConfigMap)
+
+	// Recorded config map revisions, with diffs flagging workloads still on old content
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/configmaps/:configMapID/revisions",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "configMapID",
+		}),
+		configMapService.ListRevisions)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/configmaps/:configMapID/revisions/diff",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "configMapID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		configMapService.DiffRevisions)
+
+	// Ownership graph for topology views. Controllers are listed live with the
+	// dashboard's credentials, so the user must be able to list every kind shown.
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/graph",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "deployments.apps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "replicasets.apps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "statefulsets.apps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "daemonsets.apps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "jobs.batch",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "cronjobs.batch",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		graphService.GetGraph)
+
+	// Service, ingress and config dependencies for topology views. Services and
+	// ingresses are listed live with the dashboard's credentials, and the
+	// ConfigMap and Secret names come from pod specs, so each is checked.
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/topology",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "services",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "ingresses.networking.k8s.io",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "secrets",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		topologyService.GetTopology)
+
+	// Workloads whose pods use a ConfigMap or Secret, computed from stored pods
+	for _, kind := range []struct{ kind, resource string }{{"ConfigMap", "configmaps"}, {"Secret", "secrets"}} {
+		api.Get("/clusters/:clusterID/namespaces/:namespaceID/"+kind.resource+"/:name/consumers",
+			auth.AuthMiddleware(),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       kind.resource,
+				Verb:           "get",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+				NameParam:      "name",
+			}),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       "pods",
+				Verb:           "list",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+			}),
+			topologyService.GetConsumers(kind.kind))
+	}
+
+	// Fleet-wide views over every registered cluster, filtered per cluster by permission
+	api.Get("/pods", auth.AuthMiddleware(), fleetService.ListPods)
+	api.Get("/configmaps", auth.AuthMiddleware(), fleetService.ListConfigMaps)
+	api.Get("/namespaces", auth.AuthMiddleware(), fleetService.ListNamespaces)
+
+	// Saved fleet searches, private to their owner unless shared
+	searchRoutes := api.Group("/searches", auth.AuthMiddleware())
+	searchRoutes.Get("/", searchService.ListSearches)
+	searchRoutes.Post("/", searchService.SaveSearch)
+	searchRoutes.Get("/:searchID", searchService.GetSearch)
+	searchRoutes.Put("/:searchID", searchService.SaveSearch)
+	searchRoutes.Delete("/:searchID", searchService.DeleteSearch)
+	searchRoutes.Get("/:searchID/results", searchService.RunSearch)
+
+	// Drift reports comparing a workload kind between two clusters, which both need list access
+	for _, kind := range assets.Kinds() {
+		if !drift.Supported(kind.Kind) {
+			continue
+		}
+
+		api.Get("/clusters/:clusterID/drift/:targetClusterID/namespaces/:namespaceID/"+kind.Resource,
+			auth.AuthMiddleware(),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       kind.RBACResource(),
+				Verb:           "list",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+			}),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       kind.RBACResource(),
+				Verb:           "list",
+				ClusterParam:   "targetClusterID",
+				NamespaceParam: "namespaceID",
+			}),
+			driftService.GetDrift(kind))
+	}
+
+	// Namespace snapshots of stored manifests, which hold config maps and pods
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/snapshots",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		snapshotService.CreateSnapshot)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/snapshots",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		snapshotService.ListSnapshots)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/snapshots/:snapshotID",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		snapshotService.GetSnapshot)
+
+	// Restoring writes config maps and pods into the target namespace, and the
+	// namespace itself; each object is also checked for create or update as it
+	// is written, since the restore runs with the dashboard's credentials
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/restore",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "create",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "create",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		snapshotService.RestoreSnapshot)
+
+	// Diagnostic bundles hold manifests, including config maps, and container logs
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/bundles",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods/log",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		supportBundleService.CreateSupportBundle)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/bundles",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods/log",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		supportBundleService.ListSupportBundles)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/bundles/:bundle",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "configmaps",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods/log",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		supportBundleService.DownloadSupportBundle)
+
+	// Bulk delete and restart, each target is authorized by the service
+	api.Post("/clusters/:clusterID/bulk",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		bulkService.RunBulk)
+
+	// Restart a workload's pods in waves that respect disruption budgets and
+	// readiness, with progress streamed over a WebSocket
+	for _, kind := range assets.Kinds() {
+		if !slices.Contains(bulk.RestartableKinds, kind.Kind) {
+			continue
+		}
+
+		api.Get("/clusters/:clusterID/namespaces/:namespaceID/"+kind.Resource+"/:name/restart",
+			features.Require(flags, features.WriteOperations),
+			auth.WebSocketAuthMiddleware(authorizer, auth.PodEviction),
+			websocket.New(audit.WebSocket(auditor, auth.PodEviction.Resource, auth.PodEviction.Verb, bulkService.RestartInWaves(kind.Kind))))
+	}
+
+	// Change a deployment's container image
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/deployments/:deploymentID/set-image",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "deployments.apps",
+			Verb:           "update",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "deploymentID",
+		}),
+		deploymentService.SetImage)
+
+	// Scale a deployment through its scale subresource
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/deployments/:deploymentID/scale",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "deployments.apps/scale",
+			Verb:           "update",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "deploymentID",
+		}),
+		deploymentService.Scale)
+
+	// Roll out new pods for a deployment, like kubectl rollout restart
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/deployments/:deploymentID/rollout-restart",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "deployments.apps",
+			Verb:           "patch",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "deploymentID",
+		}),
+		deploymentService.Restart)
+
+	// Run a cron job now, like kubectl create job --from
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/cronjobs/:cronJobID/trigger",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "cronjobs.batch",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "cronJobID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "jobs.batch",
+			Verb:           "create",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		cronJobService.TriggerCronJob)
+
+	// Image inventory across a cluster or a namespace
+	api.Get("/clusters/:clusterID/images",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "pods",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		imageService.GetInventory)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/images",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		imageService.GetInventory)
+
+	// Known vulnerabilities of the images running in a namespace
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/vulnerabilities",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		vulnerabilityService.GetReport)
+
+	// Policy compliance of a cluster or a namespace
+	api.Get("/clusters/:clusterID/compliance",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "pods",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		complianceService.GetReport)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/compliance",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		complianceService.GetReport)
+
+	// TLS certificate expiry, read from kubernetes.io/tls secrets
+	api.Get("/certificates", auth.AuthMiddleware(), certificateService.ListAllCertificates)
+
+	api.Get("/clusters/:clusterID/certificates",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "secrets",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		certificateService.ListCertificates)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/certificates",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "secrets",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		certificateService.ListCertificates)
+
+	// Pod disruption budgets
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/poddisruptionbudgets",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "poddisruptionbudgets.policy",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		pdbService.ListPDBs)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/poddisruptionbudgets/:pdbID",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "poddisruptionbudgets.policy",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "pdbID",
+		}),
+		pdbService.GetPDB)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/disruption",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "pods",
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "podID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "poddisruptionbudgets.policy",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		pdbService.GetPodDisruption)
+
+	// Workload details with a disruption readiness indicator for drains
+	for _, kind := range assets.Kinds() {
+		if !slices.Contains(pdbs.WorkloadKinds, kind.Kind) {
+			continue
+		}
+
+		api.Get("/clusters/:clusterID/namespaces/:namespaceID/"+kind.Resource+"/:name",
+			auth.AuthMiddleware(),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       kind.RBACResource(),
+				Verb:           "get",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+				NameParam:      "name",
+			}),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       "poddisruptionbudgets.policy",
+				Verb:           "list",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+			}),
+			pdbService.GetWorkload(kind.Kind))
+	}
+
+	// Storage classes, CSI drivers and claims
+	api.Get("/clusters/:clusterID/storageclasses",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "storageclasses.storage.k8s.io",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "csidrivers.storage.k8s.io",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		storageService.GetInventory)
+
+	api.Get("/clusters/:clusterID/storageclasses/:storageClassID",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "storageclasses.storage.k8s.io",
+			Verb:         "get",
+			ClusterParam: "clusterID",
+			NameParam:    "storageClassID",
+		}),
+		storageService.GetStorageClass)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/storage",
+		auth.AuthMiddleware(),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       "persistentvolumeclaims",
+			Verb:           "list",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+		}),
+		auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:     "storageclasses.storage.k8s.io",
+			Verb:         "list",
+			ClusterParam: "clusterID",
+		}),
+		storageService.GetNamespaceStorage)
+
+	// Commands run by the cluster's agent act with the agent's permissions, so they are limited to admins
+	api.Post("/clusters/:clusterID/agent/commands",
+		auth.AuthMiddleware(),
+		auth.RequireAdmin(),
+		features.Require(flags, features.WriteOperations),
+		agentService.SendCommand)
+
+	// kubectl-style descriptions, checked against the same resources as timelines
+	for _, kind := range assets.Kinds() {
+		api.Get("/clusters/:clusterID/namespaces/:namespaceID/"+kind.Resource+"/:name/describe",
+			auth.AuthMiddleware(),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       kind.RBACResource(),
+				Verb:           "get",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+				NameParam:      "name",
+			}),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       "events",
+				Verb:           "list",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+			}),
+			describeService.GetDescription(kind))
+	}
+
+	// Objects as the cluster has them, read and replaced when editing YAML.
+	// Config maps are replaced through their own route, which keeps revisions.
+	for _, kind := range assets.Kinds() {
+		object := "/clusters/:clusterID/namespaces/:namespaceID/" + kind.Resource + "/:name"
+		api.Get(object+"/live",
+			auth.AuthMiddleware(),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       kind.RBACResource(),
+				Verb:           "get",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+				NameParam:      "name",
+			}),
+			liveService.GetObject(kind))
+
+		if kind.Kind == "ConfigMap" {
+			continue
+		}
+
+		api.Put(object,
+			auth.AuthMiddleware(),
+			features.Require(flags, features.WriteOperations),
+			auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+				Resource:       kind.RBACResource(),
+				Verb:           "update",
+				ClusterParam:   "clusterID",
+				NamespaceParam: "namespaceID",
+				NameParam:      "name",
+			}),
+			liveService.UpdateObject(kind))
+	}
+
+	// Dashboard-local notes and tags, visible to whoever can read the resource.
+	// They are kept in the store and never written to the cluster.
+	for _, kind := range assets.Kinds() {
+		notes := "/clusters/:clusterID/namespaces/:namespaceID/" + kind.Resource + "/:name/notes"
+		readable := auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+			Resource:       kind.RBACResource(),
+			Verb:           "get",
+			ClusterParam:   "clusterID",
+			NamespaceParam: "namespaceID",
+			NameParam:      "name",
+		})
+		api.Get(notes, auth.AuthMiddleware(), readable, noteService.ListResourceNotes(kind.Kind))
+		api.Post(notes, auth.AuthMiddleware(), readable, noteService.CreateNote(kind.Kind))
+	}
+
+	namespaceReadable := auth.RequirePermission(authorizer, logger, auth.ResourceInfo{
+		Resource:     "namespaces",
+		Verb:         "get",
+		ClusterParam: "clusterID",
+		NameParam:    "namespaceID",
+	})
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/notes",
+		auth.AuthMiddleware(), namespaceReadable, noteService.ListResourceNotes("Namespace"))
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/notes",
+		auth.AuthMiddleware(), namespaceReadable, noteService.CreateNote("Namespace"))
+
+	api.Get("/clusters/:clusterID/notes", auth.AuthMiddleware(), noteService.ListNotes)
+	api.Put("/clusters/:clusterID/notes/:noteID", auth.AuthMiddleware(), noteService.UpdateNote)
+	api.Delete("/clusters/:clusterID/notes/:noteID", auth.AuthMiddleware(), noteService.DeleteNote)
+
+	// Container files are read and written with tar in the container, which needs exec access
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/files",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.Exec),
+		auth.RequirePermission(authorizer, logger, auth.PodExec),
+		fileService.ListFiles)
+
+	api.Get("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/files/download",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.Exec),
+		auth.RequirePermission(authorizer, logger, auth.PodExec),
+		fileService.DownloadFile)
+
+	api.Post("/clusters/:clusterID/namespaces/:namespaceID/pods/:podID/files/upload",
+		auth.AuthMiddleware(),
+		features.Require(flags, features.Exec),
+		features.Require(flags, features.WriteOperations),
+		auth.RequirePermission(authorizer, logger, auth.PodExec),
+		fileService.UploadFile)
 }
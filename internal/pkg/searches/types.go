@@ -0,0 +1,85 @@
+// Package searches holds saved fleet queries, so common filters such as all
+// crash-looping pods in the prod clusters can be run again by ID.
+package searches
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets/fleet"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Kinds are the kinds a search can list, those the store mirrors
+var Kinds = []string{"Pod", "ConfigMap", "Namespace"}
+
+// Search is a named fleet query. Searches are private to their owner unless
+// shared, in which case every user can list and run them.
+type Search struct {
+	ID          string `json:"id" bson:"_id"`
+	Name        string `json:"name" bson:"name"`
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+	Owner       string `json:"owner" bson:"owner"`
+	Shared      bool   `json:"shared" bson:"shared"`
+	Kind        string `json:"kind" bson:"kind"`
+	// Namespace limits the search to one namespace, empty for all
+	Namespace     string `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty" bson:"label_selector,omitempty"`
+	// Clusters are cluster names or glob patterns such as "prod-*", empty for all
+	Clusters []string `json:"clusters,omitempty" bson:"clusters,omitempty"`
+	// Status matches a pod's phase or container reason, or a namespace's phase
+	Status    string    `json:"status,omitempty" bson:"status,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updated_at"`
+}
+
+// Validate checks that a search can be run
+func (s *Search) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	known := false
+	for _, kind := range Kinds {
+		if s.Kind == kind {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("kind must be one of %v", Kinds)
+	}
+
+	if s.Kind == "Namespace" && s.Namespace != "" {
+		return fmt.Errorf("namespace searches can't be limited to a namespace")
+	}
+	if s.Kind == "ConfigMap" && s.Status != "" {
+		return fmt.Errorf("config maps have no status to search by")
+	}
+
+	if _, err := labels.Parse(s.LabelSelector); err != nil {
+		return fmt.Errorf("invalid labelSelector: %w", err)
+	}
+
+	return fleet.ValidateClusters(s.Clusters)
+}
+
+// VisibleTo reports whether a user may see and run a search
+func (s *Search) VisibleTo(user string) bool {
+	return s.Shared || s.Owner == user
+}
+
+// Query builds the fleet query a search runs
+func (s *Search) Query() (fleet.Query, error) {
+	selector, err := labels.Parse(s.LabelSelector)
+	if err != nil {
+		return fleet.Query{}, fmt.Errorf("invalid labelSelector: %w", err)
+	}
+
+	return fleet.Query{
+		Kind:      s.Kind,
+		Namespace: s.Namespace,
+		Selector:  selector,
+		Clusters:  s.Clusters,
+		Status:    s.Status,
+	}, nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/commands"
+	"github.com/jbetancur/dashboard/internal/pkg/features"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+// Agent command timeouts
+const (
+	defaultCommandTimeout = 30 * time.Second
+	maxCommandTimeout     = 5 * time.Minute
+)
+
+type AgentService struct {
+	BaseService
+	dispatcher *commands.Dispatcher
+	store      store.Repository
+	tokens     *cluster.TokenVerifier
+	flags      *features.Manager
+}
+
+// NewAgentService creates a new service for managing cluster agents and
+// sending them commands
+func NewAgentService(dispatcher *commands.Dispatcher, store store.Repository, tokens *cluster.TokenVerifier, flags *features.Manager, logger *slog.Logger) *AgentService {
+	return &AgentService{
+		BaseService: BaseService{Logger: logger},
+		dispatcher:  dispatcher,
+		store:       store,
+		tokens:      tokens,
+		flags:       flags,
+	}
+}
+
+// agentSummary is a registered cluster and the state of its agent
+type agentSummary struct {
+	Cluster    string                   `json:"cluster"`
+	Provider   string                   `json:"provider,omitempty"`
+	Status     string                   `json:"status"`
+	Online     bool                     `json:"online"`
+	Agent      *cluster.AgentInfo       `json:"agent,omitempty"`
+	Credential *cluster.AgentCredential `json:"credential,omitempty"`
+	Features   map[string]bool          `json:"features"`
+}
+
+// credentialResponse returns a rotated token once, so it can also be stored
+// where the agent reads it on restart
+type credentialResponse struct {
+	Cluster string `json:"cluster"`
+	Token   string `json:"token"`
+	Pending bool   `json:"pending"`
+	Message string `json:"message,omitempty"`
+}
+
+// featureRequest sets or clears a cluster's feature flag override
+type featureRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// ListAgents returns every stored cluster with its agent's version, last
+// heartbeat and credential state
+func (s *AgentService) ListAgents(c *fiber.Ctx) error {
+	var clusters []cluster.ClusterInfo
+	if err := s.store.ListClusters(c.Context(), &clusters); err != nil {
+		return s.InternalServerError(c, "Failed to list agents", err)
+	}
+
+	now := time.Now()
+	agents := make([]agentSummary, 0, len(clusters))
+	for _, info := range clusters {
+		summary := agentSummary{
+			Cluster:    info.Name,
+			Provider:   info.Provider,
+			Status:     info.Status,
+			Online:     info.Agent.Online(now),
+			Agent:      info.Agent,
+			Credential: info.Credential,
+			Features:   info.Features,
+		}
+		if summary.Features == nil {
+			summary.Features = make(map[string]bool)
+		}
+		agents = append(agents, summary)
+	}
+
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].Cluster < agents[j].Cluster
+	})
+
+	return c.JSON(agents)
+}
+
+// ResyncCluster asks a cluster's agent to republish all of its objects
+func (s *AgentService) ResyncCluster(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+
+	ctx, cancel := context.WithTimeout(context.Background(), maxCommandTimeout)
+	defer cancel()
+
+	result, err := s.dispatcher.Send(ctx, clusterID, commands.TypeResync, nil)
+	if errors.Is(err, commands.ErrTimeout) {
+		return s.Error(c, fiber.StatusGatewayTimeout, "%v", err)
+	}
+	if err != nil {
+		return s.InternalServerError(c, "Failed to resync cluster", err)
+	}
+
+	if result.Error != "" {
+		return c.Status(fiber.StatusBadGateway).JSON(result)
+	}
+
+	s.Logger.Info("Resynced cluster", "clusterID", clusterID)
+	return c.JSON(result)
+}
+
+// RotateCredentials issues a new agent token for a cluster and sends it to
+// the agent. The previous token stays valid until the agent confirms it
+// switched, so an agent that can't be reached is not locked out.
+func (s *AgentService) RotateCredentials(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+
+	token, hash, err := cluster.NewAgentToken()
+	if err != nil {
+		return s.InternalServerError(c, "Failed to generate agent token", err)
+	}
+
+	user, _ := c.Locals("user").(auth.UserAttributes)
+	if err := s.store.RotateAgentCredential(c.Context(), clusterID, hash, user.Username); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return s.NotFound(c, "Cluster", clusterID)
+		}
+		return s.InternalServerError(c, "Failed to rotate agent credentials", err)
+	}
+
+	response := credentialResponse{Cluster: clusterID, Token: token}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCommandTimeout)
+	defer cancel()
+
+	result, err := s.dispatcher.Send(ctx, clusterID, commands.TypeRotateCredentials, map[string]string{"token": token})
+	switch {
+	case err != nil:
+		response.Pending = true
+		response.Message = fmt.Sprintf("agent did not receive the new token, the previous one stays valid: %v", err)
+	case result.Error != "":
+		response.Pending = true
+		response.Message = fmt.Sprintf("agent refused the new token, the previous one stays valid: %s", result.Error)
+	default:
+		if err := s.store.ConfirmAgentCredential(c.Context(), clusterID, hash); err != nil {
+			return s.InternalServerError(c, "Failed to confirm agent credentials", err)
+		}
+		s.tokens.Forget()
+	}
+
+	s.Logger.Info("Rotated agent credentials",
+		"clusterID", clusterID,
+		"user", user.Username,
+		"pending", response.Pending)
+
+	if response.Pending {
+		return c.Status(fiber.StatusAccepted).JSON(response)
+	}
+	return c.JSON(response)
+}
+
+// GetClusterFeatures returns the feature flag overrides of a cluster
+func (s *AgentService) GetClusterFeatures(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+
+	var info cluster.ClusterInfo
+	if err := s.store.GetCluster(c.Context(), clusterID, &info); err != nil {
+		return s.NotFound(c, "Cluster", clusterID)
+	}
+
+	features := info.Features
+	if features == nil {
+		features = make(map[string]bool)
+	}
+
+	return c.JSON(features)
+}
+
+// SetClusterFeature overrides a feature flag for a cluster. A null
+// "enabled" removes the override.
+func (s *AgentService) SetClusterFeature(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	feature := c.Params("feature")
+
+	if !features.Known(feature) {
+		return s.NotFound(c, "Feature", feature)
+	}
+
+	var request featureRequest
+	if err := c.BodyParser(&request); err != nil {
+		return s.BadRequest(c, "invalid feature request")
+	}
+
+	if err := s.store.SetClusterFeature(c.Context(), clusterID, feature, request.Enabled); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return s.NotFound(c, "Cluster", clusterID)
+		}
+		return s.InternalServerError(c, "Failed to set cluster feature", err)
+	}
+	s.flags.InvalidateCluster(clusterID)
+
+	s.Logger.Info("Set cluster feature", "clusterID", clusterID, "feature", feature, "enabled", request.Enabled)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// commandRequest is the body of a command sent to an agent
+type commandRequest struct {
+	Type string            `json:"type"`
+	Args map[string]string `json:"args"`
+}
+
+// SendCommand asks the agent serving a cluster to run a command and returns
+// its result. The "timeout" query sets how long to wait (default 30s).
+func (s *AgentService) SendCommand(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+
+	var request commandRequest
+	if err := c.BodyParser(&request); err != nil || request.Type == "" {
+		return s.BadRequest(c, "invalid command")
+	}
+
+	timeout := defaultCommandTimeout
+	if value := c.Query("timeout"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > maxCommandTimeout {
+			return s.BadRequest(c, fmt.Sprintf("invalid timeout: %s", value))
+		}
+		timeout = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := s.dispatcher.Send(ctx, clusterID, request.Type, request.Args)
+	if errors.Is(err, commands.ErrTimeout) {
+		return s.Error(c, fiber.StatusGatewayTimeout, "%v", err)
+	}
+	if err != nil {
+		return s.InternalServerError(c, "Failed to send agent command", err)
+	}
+
+	if result.Error != "" {
+		return c.Status(fiber.StatusBadGateway).JSON(result)
+	}
+
+	return c.JSON(result)
+}
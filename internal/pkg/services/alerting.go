@@ -0,0 +1,188 @@
+package services
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/alerting"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+type AlertingService struct {
+	BaseService
+	dispatcher *alerting.Dispatcher
+	store      store.Repository
+}
+
+// NewAlertingService creates a new service for managing alert sinks, routes and silences
+func NewAlertingService(dispatcher *alerting.Dispatcher, store store.Repository, logger *slog.Logger) *AlertingService {
+	return &AlertingService{
+		BaseService: BaseService{Logger: logger},
+		dispatcher:  dispatcher,
+		store:       store,
+	}
+}
+
+// ListSinks returns all notification sinks
+func (s *AlertingService) ListSinks(c *fiber.Ctx) error {
+	var sinks []alerting.Sink
+	if err := s.store.ListAlertSinks(c.Context(), &sinks); err != nil {
+		return s.InternalServerError(c, "Failed to list alert sinks", err)
+	}
+
+	return c.JSON(sinks)
+}
+
+// SaveSink creates a sink, or replaces the one named by the sinkID parameter
+func (s *AlertingService) SaveSink(c *fiber.Ctx) error {
+	var sink alerting.Sink
+	if err := c.BodyParser(&sink); err != nil {
+		return s.BadRequest(c, "invalid sink")
+	}
+
+	sink.ID = c.Params("sinkID")
+	if err := sink.Validate(); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	if err := s.store.SaveAlertSink(c.Context(), &sink); err != nil {
+		return s.InternalServerError(c, "Failed to save alert sink", err)
+	}
+
+	return c.JSON(sink)
+}
+
+// DeleteSink removes a notification sink
+func (s *AlertingService) DeleteSink(c *fiber.Ctx) error {
+	sinkID := c.Params("sinkID")
+	if err := s.store.DeleteAlertSink(c.Context(), sinkID); err != nil {
+		return s.NotFound(c, "alert sink", sinkID)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// TestSink sends a sample notification to a sink
+func (s *AlertingService) TestSink(c *fiber.Ctx) error {
+	sinkID := c.Params("sinkID")
+
+	var sinks []alerting.Sink
+	if err := s.store.ListAlertSinks(c.Context(), &sinks); err != nil {
+		return s.InternalServerError(c, "Failed to list alert sinks", err)
+	}
+
+	for _, sink := range sinks {
+		if sink.ID != sinkID {
+			continue
+		}
+
+		if err := s.dispatcher.Test(c.Context(), sink); err != nil {
+			return s.Error(c, fiber.StatusBadGateway, "Failed to deliver test notification: %v", err)
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	return s.NotFound(c, "alert sink", sinkID)
+}
+
+// ListRoutes returns all alert routing rules
+func (s *AlertingService) ListRoutes(c *fiber.Ctx) error {
+	var routes []alerting.Route
+	if err := s.store.ListAlertRoutes(c.Context(), &routes); err != nil {
+		return s.InternalServerError(c, "Failed to list alert routes", err)
+	}
+
+	return c.JSON(routes)
+}
+
+// SaveRoute creates a route, or replaces the one named by the routeID parameter
+func (s *AlertingService) SaveRoute(c *fiber.Ctx) error {
+	var route alerting.Route
+	if err := c.BodyParser(&route); err != nil {
+		return s.BadRequest(c, "invalid route")
+	}
+
+	route.ID = c.Params("routeID")
+	if len(route.Sinks) == 0 {
+		return s.BadRequest(c, "route requires at least one sink")
+	}
+
+	if err := s.store.SaveAlertRoute(c.Context(), &route); err != nil {
+		return s.InternalServerError(c, "Failed to save alert route", err)
+	}
+
+	return c.JSON(route)
+}
+
+// DeleteRoute removes an alert routing rule
+func (s *AlertingService) DeleteRoute(c *fiber.Ctx) error {
+	routeID := c.Params("routeID")
+	if err := s.store.DeleteAlertRoute(c.Context(), routeID); err != nil {
+		return s.NotFound(c, "alert route", routeID)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListSilences returns silences, only active and upcoming ones unless all=true
+func (s *AlertingService) ListSilences(c *fiber.Ctx) error {
+	var silences []alerting.Silence
+	if err := s.store.ListAlertSilences(c.Context(), &silences); err != nil {
+		return s.InternalServerError(c, "Failed to list alert silences", err)
+	}
+
+	if c.Query("all") != "true" {
+		now := time.Now()
+		current := make([]alerting.Silence, 0, len(silences))
+		for _, silence := range silences {
+			if silence.EndsAt.After(now) {
+				current = append(current, silence)
+			}
+		}
+		silences = current
+	}
+
+	return c.JSON(silences)
+}
+
+// SaveSilence creates a silence, or replaces the one named by the silenceID parameter.
+// StartsAt defaults to now.
+func (s *AlertingService) SaveSilence(c *fiber.Ctx) error {
+	var silence alerting.Silence
+	if err := c.BodyParser(&silence); err != nil {
+		return s.BadRequest(c, "invalid silence")
+	}
+
+	silence.ID = c.Params("silenceID")
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = time.Now()
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return s.BadRequest(c, "endsAt must be after startsAt")
+	}
+
+	if user, ok := c.Locals("user").(auth.UserAttributes); ok {
+		silence.CreatedBy = user.Username
+	}
+	silence.Comment = strings.TrimSpace(silence.Comment)
+
+	if err := s.store.SaveAlertSilence(c.Context(), &silence); err != nil {
+		return s.InternalServerError(c, "Failed to save alert silence", err)
+	}
+
+	return c.JSON(silence)
+}
+
+// DeleteSilence removes a silence
+func (s *AlertingService) DeleteSilence(c *fiber.Ctx) error {
+	silenceID := c.Params("silenceID")
+	if err := s.store.DeleteAlertSilence(c.Context(), silenceID); err != nil {
+		return s.NotFound(c, "alert silence", silenceID)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/apiresources"
+	"github.com/jbetancur/dashboard/internal/pkg/features"
+)
+
+type APIResourceService struct {
+	BaseService
+	provider *apiresources.APIResourceProvider
+	flags    *features.Manager
+}
+
+// NewAPIResourceService creates a new service for API resource discovery
+func NewAPIResourceService(provider *apiresources.APIResourceProvider, flags *features.Manager, logger *slog.Logger) *APIResourceService {
+	return &APIResourceService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+		flags:       flags,
+	}
+}
+
+// ListAPIResources returns the resource types a cluster serves, with their
+// verbs and whether they are namespaced. ?refresh=true skips the cache.
+// Custom resource types are left out unless CRD browsing is enabled.
+func (s *APIResourceService) ListAPIResources(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	result, err := s.provider.Resources(c.Context(), clusterID, c.QueryBool("refresh"))
+	if err != nil {
+		return s.InternalServerError(c, "Failed to discover API resources", err)
+	}
+
+	if s.flags.Enabled(c.Context(), features.CRDBrowsing, clusterID) {
+		return c.JSON(result)
+	}
+
+	// Copy, as the discovery is shared with the cache
+	builtIn := *result
+	builtIn.Resources = make([]apiresources.APIResource, 0, len(result.Resources))
+	for _, resource := range result.Resources {
+		if isBuiltInGroup(resource.Group) {
+			builtIn.Resources = append(builtIn.Resources, resource)
+		}
+	}
+
+	return c.JSON(builtIn)
+}
+
+// isBuiltInGroup reports whether an API group ships with Kubernetes: the
+// core group, groups without a domain such as apps, and *.k8s.io groups.
+// A few add-ons also use *.k8s.io groups and are counted as built in.
+func isBuiltInGroup(group string) bool {
+	return !strings.Contains(group, ".") || strings.HasSuffix(group, ".k8s.io")
+}
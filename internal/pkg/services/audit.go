@@ -0,0 +1,153 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/audit"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 10000
+)
+
+type AuditService struct {
+	BaseService
+	store store.Repository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(store store.Repository, logger *slog.Logger) *AuditService {
+	return &AuditService{
+		BaseService: BaseService{Logger: logger},
+		store:       store,
+	}
+}
+
+// ListAuditEntries returns audit entries matching the query string filters
+func (s *AuditService) ListAuditEntries(c *fiber.Ctx) error {
+	query, err := parseAuditQuery(c, defaultAuditLimit)
+	if err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	var entries []audit.Entry
+	if err := s.store.ListAuditEntries(c.Context(), query, &entries); err != nil {
+		return s.InternalServerError(c, "Failed to list audit entries", err)
+	}
+
+	return c.JSON(entries)
+}
+
+// ExportAuditEntries returns audit entries as a downloadable JSON or CSV file
+func (s *AuditService) ExportAuditEntries(c *fiber.Ctx) error {
+	query, err := parseAuditQuery(c, maxAuditLimit)
+	if err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return s.BadRequest(c, "format must be json or csv")
+	}
+
+	var entries []audit.Entry
+	if err := s.store.ListAuditEntries(c.Context(), query, &entries); err != nil {
+		return s.InternalServerError(c, "Failed to export audit entries", err)
+	}
+
+	filename := fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+	c.Attachment(filename)
+
+	if format == "json" {
+		return c.JSON(entries)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+
+	writer := csv.NewWriter(c.Response().BodyWriter())
+	if err := writer.Write([]string{
+		"timestamp", "kind", "user", "groups", "verb", "resource", "cluster",
+		"namespace", "name", "method", "path", "status", "result", "latency_ms", "source_ip",
+	}); err != nil {
+		return s.InternalServerError(c, "Failed to write audit export", err)
+	}
+
+	for _, entry := range entries {
+		if err := writer.Write([]string{
+			entry.Timestamp.UTC().Format(time.RFC3339),
+			entry.Kind,
+			entry.User,
+			strings.Join(entry.Groups, ";"),
+			entry.Verb,
+			entry.Resource,
+			entry.Cluster,
+			entry.Namespace,
+			entry.Name,
+			entry.Method,
+			entry.Path,
+			strconv.Itoa(entry.Status),
+			entry.Result,
+			strconv.FormatInt(entry.Latency.Milliseconds(), 10),
+			entry.SourceIP,
+		}); err != nil {
+			return s.InternalServerError(c, "Failed to write audit export", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return s.InternalServerError(c, "Failed to write audit export", err)
+	}
+
+	return nil
+}
+
+// parseAuditQuery builds an audit query from the request's query string
+func parseAuditQuery(c *fiber.Ctx, defaultLimit int64) (audit.Query, error) {
+	query := audit.Query{
+		User:      c.Query("user"),
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Query("namespace"),
+		Resource:  c.Query("resource"),
+		Verb:      c.Query("verb"),
+		Result:    c.Query("result"),
+		Limit:     defaultLimit,
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return query, fmt.Errorf("invalid since parameter, expected RFC3339: %w", err)
+		}
+		query.Since = t
+	}
+
+	if until := c.Query("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return query, fmt.Errorf("invalid until parameter, expected RFC3339: %w", err)
+		}
+		query.Until = t
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		val, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || val <= 0 {
+			return query, fmt.Errorf("invalid limit parameter")
+		}
+		if val > maxAuditLimit {
+			val = maxAuditLimit
+		}
+		query.Limit = val
+	}
+
+	return query, nil
+}
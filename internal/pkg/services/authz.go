@@ -0,0 +1,104 @@
+package services
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+)
+
+type AuthzService struct {
+	BaseService
+	authorizer auth.Authorizer
+	cache      auth.CachingAuthorizer
+}
+
+// NewAuthzService creates a new service for permission previews and managing the authorizer cache
+func NewAuthzService(authorizer auth.Authorizer, cache auth.CachingAuthorizer, logger *slog.Logger) *AuthzService {
+	return &AuthzService{
+		BaseService: BaseService{Logger: logger},
+		authorizer:  authorizer,
+		cache:       cache,
+	}
+}
+
+// CanI reports whether the current user may perform one or more comma-separated
+// verbs on a resource, so clients can hide actions up front
+func (s *AuthzService) CanI(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	resource := c.Query("resource")
+	if resource == "" {
+		return s.BadRequest(c, "missing resource parameter")
+	}
+
+	verbParam := c.Query("verb")
+	if verbParam == "" {
+		return s.BadRequest(c, "missing verb parameter")
+	}
+
+	namespace := c.Query("namespace")
+	name := c.Query("name")
+
+	results := make(map[string]bool)
+	allowedAll := true
+	for _, verb := range strings.Split(verbParam, ",") {
+		verb = strings.TrimSpace(verb)
+		if verb == "" {
+			continue
+		}
+
+		allowed, err := s.authorizer.CanAccess(c.Context(), clusterID, user, resource, namespace, name, verb)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to verify permissions", err)
+		}
+
+		results[verb] = allowed
+		allowedAll = allowedAll && allowed
+	}
+
+	if len(results) == 0 {
+		return s.BadRequest(c, "missing verb parameter")
+	}
+
+	return c.JSON(fiber.Map{
+		"cluster":   clusterID,
+		"resource":  resource,
+		"namespace": namespace,
+		"name":      name,
+		"allowed":   allowedAll,
+		"verbs":     results,
+	})
+}
+
+// GetCacheStats returns the authorizer decision cache metrics
+func (s *AuthzService) GetCacheStats(c *fiber.Ctx) error {
+	return c.JSON(s.cache.CacheStats())
+}
+
+// InvalidateCache drops cached decisions for the user given in the query
+// string, or the whole cache when no user is given
+func (s *AuthzService) InvalidateCache(c *fiber.Ctx) error {
+	username := c.Query("user")
+
+	var removed int
+	if username != "" {
+		removed = s.cache.InvalidateUser(username)
+	} else {
+		removed = s.cache.InvalidateAll()
+	}
+
+	return c.JSON(fiber.Map{
+		"user":    username,
+		"removed": removed,
+	})
+}
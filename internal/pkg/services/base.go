@@ -5,7 +5,10 @@ import (
 	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/export"
 	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/output"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // BaseService provides common functionality for all services
@@ -60,6 +63,27 @@ func (s *BaseService) InternalServerError(c *fiber.Ctx, message string, err erro
 	})
 }
 
+// SendObject writes a Kubernetes object as JSON, or as re-applyable YAML
+// stripped of status and cluster-assigned fields when output.Negotiate picks
+// YAML. Tables are rendered from the JSON by the output middleware.
+func (s *BaseService) SendObject(c *fiber.Ctx, obj runtime.Object) error {
+	format, err := output.Negotiate(c)
+	if err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+	if format != output.FormatYAML {
+		return c.JSON(obj)
+	}
+
+	data, err := export.YAML(obj)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to export object", err)
+	}
+
+	c.Set(fiber.HeaderContentType, output.MIMEApplicationYAML)
+	return c.Send(data)
+}
+
 // CheckResourcePermission checks if a user has permission to access a resource
 func (s *BaseService) CheckResourcePermission(c *fiber.Ctx, authorizer auth.Authorizer,
 	resource, namespace, name, verb string) (auth.UserAttributes, error) {
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/bulk"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+)
+
+type BulkService struct {
+	BaseService
+	provider   *bulk.BulkProvider
+	authorizer auth.Authorizer
+}
+
+// NewBulkService creates a new service for operations on many objects at once
+func NewBulkService(provider *bulk.BulkProvider, authorizer auth.Authorizer, logger *slog.Logger) *BulkService {
+	return &BulkService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+		authorizer:  authorizer,
+	}
+}
+
+// RunBulk deletes or restarts a list of objects in a cluster. Each target is
+// authorized on its own, so the report can mix successes with forbidden items.
+func (s *BulkService) RunBulk(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	var req bulk.Request
+	if err := c.BodyParser(&req); err != nil {
+		return s.BadRequest(c, "invalid bulk request")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+	req.DryRun = req.DryRun || dryRun
+
+	if err := s.provider.Validate(&req); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	report, err := s.provider.Run(c.Context(), clusterID, &req, func(ctx context.Context, resource, namespace, name, verb string) (bool, error) {
+		return s.authorizer.CanAccess(ctx, clusterID, user, resource, namespace, name, verb)
+	})
+	if err != nil {
+		return s.InternalServerError(c, "Failed to run bulk "+req.Operation, err)
+	}
+
+	s.Logger.Info("Bulk operation finished",
+		"cluster", clusterID,
+		"operation", req.Operation,
+		"user", user.Username,
+		"succeeded", report.Succeeded,
+		"failed", report.Failed)
+
+	return c.JSON(report)
+}
+
+// RestartInWaves returns a WebSocket handler restarting the pods of a workload
+// of the given kind a few at a time, streaming each step as JSON. The waveSize,
+// minAvailable (percent) and waveTimeout query parameters tune the waves.
+// Closing the socket stops the restart before its next step.
+func (s *BulkService) RestartInWaves(kind string) func(*websocket.Conn) {
+	return func(c *websocket.Conn) {
+		clusterID := c.Params("clusterID")
+		req := bulk.WaveRequest{Kind: kind, Namespace: c.Params("namespaceID"), Name: c.Params("name")}
+
+		var err error
+		if value := c.Query("waveSize"); value != "" {
+			if req.WaveSize, err = strconv.Atoi(value); err != nil {
+				s.sendWaveError(c, "waveSize must be a number")
+				return
+			}
+		}
+		if value := c.Query("minAvailable"); value != "" {
+			if req.MinAvailable, err = strconv.Atoi(value); err != nil {
+				s.sendWaveError(c, "minAvailable must be a number")
+				return
+			}
+		}
+		if value := c.Query("waveTimeout"); value != "" {
+			if req.WaveTimeout, err = time.ParseDuration(value); err != nil {
+				s.sendWaveError(c, "waveTimeout must be a duration such as 5m")
+				return
+			}
+		}
+		if err := s.provider.ValidateWaves(&req); err != nil {
+			s.sendWaveError(c, err.Error())
+			return
+		}
+
+		// The middleware checked pods/eviction; the workload must be readable too
+		user, _ := c.Locals("user").(auth.UserAttributes)
+		kindInfo, _ := assets.LookupKind(req.Kind)
+		allowed, err := s.authorizer.CanAccess(context.Background(), clusterID, user, kindInfo.RBACResource(), req.Namespace, req.Name, "get")
+		if err != nil || !allowed {
+			s.sendWaveError(c, fmt.Sprintf("not allowed to get %s %s", kindInfo.RBACResource(), req.Name))
+			return
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// The client only listens; a failed read means it went away
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := c.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		s.Logger.Info("Wave restart started", "cluster", clusterID, "kind", req.Kind,
+			"namespace", req.Namespace, "name", req.Name, "user", user.Username)
+
+		err = s.provider.RestartInWaves(ctx, clusterID, &req, func(progress bulk.Progress) {
+			if err := c.WriteJSON(progress); err != nil {
+				s.Logger.Warn("Failed to send wave restart progress", "error", err)
+				cancel()
+			}
+		})
+		switch {
+		case err == nil:
+			s.Logger.Info("Wave restart completed", "cluster", clusterID, "kind", req.Kind, "namespace", req.Namespace, "name", req.Name)
+		case errors.Is(err, bulk.ErrAborted):
+			s.Logger.Warn("Wave restart aborted", "cluster", clusterID, "kind", req.Kind, "namespace", req.Namespace, "name", req.Name, "error", err)
+		case errors.Is(err, context.Canceled):
+			s.Logger.Warn("Wave restart stopped by the client", "cluster", clusterID, "kind", req.Kind, "namespace", req.Namespace, "name", req.Name)
+		default:
+			s.sendWaveError(c, fmt.Sprintf("Failed to restart %s: %v", req.Name, err))
+			return
+		}
+
+		if err := c.Close(); err != nil {
+			s.Logger.Debug("Failed to close websocket connection", "error", err)
+		}
+	}
+}
+
+// sendWaveError reports a failed wave restart and closes the socket
+func (s *BulkService) sendWaveError(c *websocket.Conn, message string) {
+	if err := c.WriteJSON(map[string]string{"error": message}); err != nil {
+		s.Logger.Error("Failed to send error message over websocket", "error", err)
+	}
+
+	time.Sleep(100 * time.Millisecond) // Give time for the message to be sent
+
+	if err := c.Close(); err != nil {
+		s.Logger.Error("Failed to close websocket connection", "error", err)
+	}
+}
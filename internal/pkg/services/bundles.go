@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/commands"
+	"github.com/jbetancur/dashboard/internal/pkg/objectstore"
+	"github.com/jbetancur/dashboard/internal/pkg/supportbundle"
+	"github.com/jbetancur/dashboard/internal/pkg/tunnel"
+)
+
+type SupportBundleService struct {
+	BaseService
+	clusterManager *cluster.Manager
+	tunnel         *tunnel.Client
+	dispatcher     *commands.Dispatcher
+	objects        objectstore.Store
+}
+
+// SupportBundle is a stored diagnostic bundle
+type SupportBundle struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewSupportBundleService creates a new service collecting diagnostic bundles into the object store
+func NewSupportBundleService(clusterManager *cluster.Manager, tunnelClient *tunnel.Client, dispatcher *commands.Dispatcher, objects objectstore.Store, logger *slog.Logger) *SupportBundleService {
+	return &SupportBundleService{
+		BaseService:    BaseService{Logger: logger},
+		clusterManager: clusterManager,
+		tunnel:         tunnelClient,
+		dispatcher:     dispatcher,
+		objects:        objects,
+	}
+}
+
+// CreateSupportBundle collects a bundle for the namespace, or for the
+// workload given by the kind and name query parameters, and stores it. The
+// last tail lines of each container's logs are included.
+func (s *SupportBundleService) CreateSupportBundle(c *fiber.Ctx) error {
+	if s.objects == nil {
+		return s.Error(c, fiber.StatusServiceUnavailable, "object store is not configured")
+	}
+
+	clusterID := c.Params("clusterID")
+	req := supportbundle.Request{
+		Namespace: c.Params("namespaceID"),
+		Kind:      c.Query("kind"),
+		Name:      c.Query("name"),
+		TailLines: int64(c.QueryInt("tail", 0)),
+	}
+	if err := req.Validate(); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), maxCommandTimeout)
+	defer cancel()
+
+	data, err := s.build(ctx, clusterID, req)
+	if errors.Is(err, commands.ErrTimeout) {
+		return s.Error(c, fiber.StatusGatewayTimeout, "%v", err)
+	}
+	if err != nil {
+		return s.InternalServerError(c, "Failed to collect support bundle", err)
+	}
+
+	name := time.Now().UTC().Format("20060102T150405Z")
+	if req.Kind != "" {
+		name += "-" + strings.ToLower(req.Kind) + "-" + req.Name
+	}
+	name += ".tar.gz"
+
+	key := supportBundlePrefix(clusterID, req.Namespace) + name
+	if err := s.objects.Put(c.Context(), key, data, "application/gzip"); err != nil {
+		return s.InternalServerError(c, "Failed to store support bundle", err)
+	}
+
+	s.Logger.Info("Collected support bundle", "clusterID", clusterID, "namespace", req.Namespace, "name", name)
+	return c.Status(fiber.StatusCreated).JSON(SupportBundle{Name: name, Size: int64(len(data)), CreatedAt: time.Now()})
+}
+
+// ListSupportBundles returns the stored bundles of a namespace, oldest first
+func (s *SupportBundleService) ListSupportBundles(c *fiber.Ctx) error {
+	if s.objects == nil {
+		return s.Error(c, fiber.StatusServiceUnavailable, "object store is not configured")
+	}
+
+	prefix := supportBundlePrefix(c.Params("clusterID"), c.Params("namespaceID"))
+
+	objects, err := s.objects.List(c.Context(), prefix)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to list support bundles", err)
+	}
+
+	bundles := make([]SupportBundle, 0, len(objects))
+	for _, object := range objects {
+		bundles = append(bundles, SupportBundle{
+			Name:      strings.TrimPrefix(object.Key, prefix),
+			Size:      object.Size,
+			CreatedAt: object.Modified,
+		})
+	}
+
+	return c.JSON(bundles)
+}
+
+// DownloadSupportBundle sends a stored bundle
+func (s *SupportBundleService) DownloadSupportBundle(c *fiber.Ctx) error {
+	if s.objects == nil {
+		return s.Error(c, fiber.StatusServiceUnavailable, "object store is not configured")
+	}
+
+	name := c.Params("bundle")
+	if name == "" || path.Base(name) != name {
+		return s.BadRequest(c, "invalid bundle name")
+	}
+
+	data, err := s.objects.Get(c.Context(), supportBundlePrefix(c.Params("clusterID"), c.Params("namespaceID"))+name)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return s.NotFound(c, "support bundle", name)
+	}
+	if err != nil {
+		return s.InternalServerError(c, "Failed to read support bundle", err)
+	}
+
+	c.Attachment(fmt.Sprintf("%s-%s-%s", c.Params("clusterID"), c.Params("namespaceID"), name))
+	c.Set(fiber.HeaderContentType, "application/gzip")
+	return c.Send(data)
+}
+
+// build collects a bundle directly, or through the cluster's agent when the
+// API server is not reachable from here
+func (s *SupportBundleService) build(ctx context.Context, clusterID string, req supportbundle.Request) ([]byte, error) {
+	if !s.tunnel.Proxied(clusterID) {
+		if conn, err := s.clusterManager.GetCluster(clusterID); err == nil {
+			return supportbundle.Build(ctx, conn, req)
+		}
+	}
+
+	result, err := s.dispatcher.Send(ctx, clusterID, commands.TypeDiagnosticBundle, req.Args())
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("agent: %s", result.Error)
+	}
+
+	var data []byte
+	if err := json.Unmarshal(result.Data, &data); err != nil {
+		return nil, fmt.Errorf("invalid bundle from agent: %w", err)
+	}
+	return data, nil
+}
+
+// supportBundlePrefix is where the bundles of a namespace are kept
+func supportBundlePrefix(clusterID, namespace string) string {
+	return fmt.Sprintf("bundles/%s/%s/", clusterID, namespace)
+}
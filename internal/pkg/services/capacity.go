@@ -0,0 +1,85 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/capacity"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type CapacityService struct {
+	BaseService
+	provider *capacity.CapacityProvider
+}
+
+// NewCapacityService creates a new service for capacity and scheduling headroom
+func NewCapacityService(provider *capacity.CapacityProvider, logger *slog.Logger) *CapacityService {
+	return &CapacityService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetCapacity returns schedulable capacity per node pool and pods pending on resources
+func (s *CapacityService) GetCapacity(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	report, err := s.provider.Report(c.Context(), clusterID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to compute capacity", err)
+	}
+
+	return c.JSON(report)
+}
+
+// DiagnosePending explains why a pod is not scheduled: insufficient
+// resources, taints, node selection or unbound volume claims
+func (s *CapacityService) DiagnosePending(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or pod ID")
+	}
+
+	diagnosis, err := s.provider.DiagnosePending(c.Context(), clusterID, namespaceID, podID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return s.NotFound(c, "Pod", podID)
+		}
+		return s.InternalServerError(c, "Failed to diagnose pod scheduling", err)
+	}
+
+	return c.JSON(diagnosis)
+}
+
+// ExplainPlacement evaluates the pod in the request body against every node
+// of a cluster: which nodes it fits on and why the others are excluded by
+// taints, node selection or free resources
+func (s *CapacityService) ExplainPlacement(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	var pod corev1.Pod
+	if err := c.BodyParser(&pod); err != nil {
+		return s.BadRequest(c, "invalid pod: "+err.Error())
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return s.BadRequest(c, "pod spec has no containers")
+	}
+
+	placement, err := s.provider.Placement(c.Context(), clusterID, &pod)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to evaluate pod placement", err)
+	}
+
+	return c.JSON(placement)
+}
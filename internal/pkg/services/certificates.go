@@ -0,0 +1,86 @@
+package services
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/certificates"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+)
+
+type CertificateService struct {
+	BaseService
+	provider       *certificates.CertificateProvider
+	clusterManager *cluster.Manager
+	authorizer     auth.Authorizer
+}
+
+// NewCertificateService creates a new service for TLS certificate expiry
+func NewCertificateService(provider *certificates.CertificateProvider, clusterManager *cluster.Manager,
+	authorizer auth.Authorizer, logger *slog.Logger) *CertificateService {
+	return &CertificateService{
+		BaseService:    BaseService{Logger: logger},
+		provider:       provider,
+		clusterManager: clusterManager,
+		authorizer:     authorizer,
+	}
+}
+
+// expiryWindow reads the days query parameter as an expiry window, where zero means all certificates
+func expiryWindow(c *fiber.Ctx) (time.Duration, bool) {
+	days := c.QueryInt("days", 0)
+	if days < 0 {
+		return 0, false
+	}
+	return time.Duration(days) * 24 * time.Hour, true
+}
+
+// ListCertificates returns the certificates of a cluster, or of the namespace
+// when one is in the path, optionally only those expiring within ?days=N
+func (s *CertificateService) ListCertificates(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	window, ok := expiryWindow(c)
+	if !ok {
+		return s.BadRequest(c, "days must not be negative")
+	}
+
+	certs, err := s.provider.List(c.Context(), clusterID, c.Params("namespaceID"), window)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to list certificates", err)
+	}
+
+	return c.JSON(certs)
+}
+
+// ListAllCertificates returns certificates from every cluster where the user
+// may list secrets, optionally only those expiring within ?days=N
+func (s *CertificateService) ListAllCertificates(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	window, ok := expiryWindow(c)
+	if !ok {
+		return s.BadRequest(c, "days must not be negative")
+	}
+
+	clusterIDs := make([]string, 0)
+	for _, info := range s.clusterManager.ListClusters() {
+		allowed, err := s.authorizer.CanAccess(c.Context(), info.ID, user, "secrets", "", "", "list")
+		if err != nil {
+			return s.InternalServerError(c, "Failed to verify permissions", err)
+		}
+		if allowed {
+			clusterIDs = append(clusterIDs, info.ID)
+		}
+	}
+
+	return c.JSON(s.provider.ListAll(c.Context(), clusterIDs, window))
+}
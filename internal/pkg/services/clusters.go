@@ -49,14 +49,13 @@ func (s *ClusterService) GetCluster(c *fiber.Ctx) error {
 		return s.NotFound(c, "Cluster", clusterID)
 	}
 
-	// Get cluster health status
-	healthy, err := conn.GetHealthStatus()
-	healthStatus := "unknown"
-	if err == nil {
-		if healthy {
-			healthStatus = "healthy"
-		} else {
-			healthStatus = "unhealthy"
+	// Use the monitor's status, probing directly for clusters it has not seen yet
+	healthStatus := s.manager.Status(clusterID)
+	if healthStatus == cluster.StatusUnknown {
+		if healthy, err := conn.GetHealthStatus(); err == nil && healthy {
+			healthStatus = cluster.StatusHealthy
+		} else if err != nil {
+			healthStatus = cluster.StatusUnhealthy
 		}
 	}
 
@@ -74,5 +73,13 @@ func (s *ClusterService) GetCluster(c *fiber.Ctx) error {
 		Status: healthStatus, // Additional field for single cluster view
 	}
 
+	// Include what the cluster's agent advertised when it registered
+	var stored cluster.ClusterInfo
+	if err := s.store.GetCluster(c.Context(), clusterID, &stored); err == nil {
+		response.Provider = stored.Provider
+		response.Agent = stored.Agent
+		response.Server = stored.Server
+	}
+
 	return c.JSON(response)
 }
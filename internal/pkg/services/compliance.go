@@ -0,0 +1,37 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/compliance"
+)
+
+type ComplianceService struct {
+	BaseService
+	provider *compliance.ComplianceProvider
+}
+
+// NewComplianceService creates a new service for policy compliance reports
+func NewComplianceService(provider *compliance.ComplianceProvider, logger *slog.Logger) *ComplianceService {
+	return &ComplianceService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetReport returns the policy violations of a cluster, or of the namespace
+// when one is in the path
+func (s *ComplianceService) GetReport(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	result, err := s.provider.Check(c.Context(), clusterID, c.Params("namespaceID"))
+	if err != nil {
+		return s.InternalServerError(c, "Failed to check compliance", err)
+	}
+
+	return c.JSON(result)
+}
@@ -1,29 +1,40 @@
 package services
 
 import (
+	"errors"
 	"log/slog"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jbetancur/dashboard/internal/pkg/assets/configmaps"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/topology"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/listcache"
+	"github.com/jbetancur/dashboard/internal/pkg/revision"
 
 	"github.com/jbetancur/dashboard/internal/pkg/store"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 type ConfigMapService struct {
 	BaseService
 	provider *configmaps.ConfigMapProvider
 	store    store.Repository
+	lists    *listcache.Cache
+	topology *topology.TopologyProvider
 }
 
 // NewConfigMapService creates a new config map service
-func NewConfigMapService(provider *configmaps.ConfigMapProvider, store store.Repository,
-	logger *slog.Logger) *ConfigMapService {
+func NewConfigMapService(provider *configmaps.ConfigMapProvider, store store.Repository, lists *listcache.Cache,
+	topology *topology.TopologyProvider, logger *slog.Logger) *ConfigMapService {
 	return &ConfigMapService{
 		BaseService: BaseService{Logger: logger},
 		provider:    provider,
 		store:       store,
+		lists:       lists,
+		topology:    topology,
 	}
 }
 
@@ -33,6 +44,11 @@ func (s *ConfigMapService) ListConfigMaps(c *fiber.Ctx) error {
 		return s.BadRequest(c, "missing cluster ID")
 	}
 
+	key := listKey(c, clusterID, "ConfigMap", "")
+	if sendCachedList(c, s.lists, key) {
+		return nil
+	}
+
 	s.Logger.Debug("Listing config maps fom data store", "clusterID", clusterID)
 
 	// Use MongoDB to list config maps instead of the provider
@@ -46,9 +62,10 @@ func (s *ConfigMapService) ListConfigMaps(c *fiber.Ctx) error {
 		// 	return s.Error(c, fiber.StatusInternalServerError, "failed to list config maps: %v", err)
 		// }
 		// return c.JSON(directConfigMaps)
+		return c.JSON(configMaps)
 	}
 
-	return c.JSON(configMaps)
+	return sendList(c, s.lists, key, configMaps)
 }
 
 func (s *ConfigMapService) GetConfigMap(c *fiber.Ctx) error {
@@ -84,5 +101,274 @@ func (s *ConfigMapService) GetConfigMap(c *fiber.Ctx) error {
 		// return c.JSON(directNamespace)
 	}
 
-	return c.JSON(&configMap)
+	return sendWithNotes(c, &s.BaseService, s.store, clusterID, "ConfigMap", &configMap)
+}
+
+// CreateConfigMap creates a config map from the request body in the namespace
+// in the path. With ?dryRun=true the cluster only validates it.
+func (s *ConfigMapService) CreateConfigMap(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	var configMap corev1.ConfigMap
+	if err := c.BodyParser(&configMap); err != nil {
+		return s.BadRequest(c, "request body must be a config map")
+	}
+
+	created, err := s.provider.CreateConfigMap(c.Context(), clusterID, namespaceID, &configMap, dryRun)
+	switch {
+	case errors.Is(err, configmaps.ErrInvalid):
+		return s.BadRequest(c, err.Error())
+	case apierrors.IsAlreadyExists(err):
+		return s.Error(c, fiber.StatusConflict, "config map %s already exists", configMap.Name)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to create config map", err)
+	}
+
+	if dryRun {
+		return s.SendDryRun(c, DryRunCreate, nil, created)
+	}
+
+	s.storeConfigMap(c, clusterID, created)
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// UpdateConfigMap replaces a config map with the request body. The body must
+// carry the resourceVersion it was based on; if the config map changed since,
+// the response is a 409 with the current version so clients can re-read and retry.
+// With ?dryRun=true the response previews the changes instead.
+func (s *ConfigMapService) UpdateConfigMap(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	configMapID := c.Params("configMapID")
+	if clusterID == "" || namespaceID == "" || configMapID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or config map ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	var configMap corev1.ConfigMap
+	if err := c.BodyParser(&configMap); err != nil {
+		return s.BadRequest(c, "request body must be a config map")
+	}
+	if configMap.Name == "" {
+		configMap.Name = configMapID
+	}
+	if configMap.Name != configMapID {
+		return s.BadRequest(c, "config map name doesn't match the path")
+	}
+
+	updated, err := s.provider.UpdateConfigMap(c.Context(), clusterID, namespaceID, &configMap, dryRun)
+	switch {
+	case errors.Is(err, configmaps.ErrInvalid):
+		return s.BadRequest(c, err.Error())
+	case apierrors.IsNotFound(err):
+		return s.NotFound(c, "ConfigMap", configMapID)
+	case apierrors.IsConflict(err):
+		return s.conflict(c, clusterID, namespaceID, configMapID, configMap.ResourceVersion)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to update config map", err)
+	}
+
+	if dryRun {
+		current, err := s.provider.GetConfigMap(c.Context(), clusterID, namespaceID, configMapID)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to get config map", err)
+		}
+		return s.SendDryRun(c, DryRunUpdate, current, updated)
+	}
+
+	s.storeConfigMap(c, clusterID, updated)
+	return c.JSON(updated)
+}
+
+// DeleteConfigMap deletes a config map. An optional resourceVersion query
+// parameter makes the delete fail with a 409 if the config map has changed,
+// and ?dryRun=true returns the config map that would be deleted.
+func (s *ConfigMapService) DeleteConfigMap(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	configMapID := c.Params("configMapID")
+	if clusterID == "" || namespaceID == "" || configMapID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or config map ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	resourceVersion := c.Query("resourceVersion")
+	err = s.provider.DeleteConfigMap(c.Context(), clusterID, namespaceID, configMapID, resourceVersion, dryRun)
+	switch {
+	case apierrors.IsNotFound(err):
+		return s.NotFound(c, "ConfigMap", configMapID)
+	case apierrors.IsConflict(err):
+		return s.conflict(c, clusterID, namespaceID, configMapID, resourceVersion)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to delete config map", err)
+	}
+
+	if dryRun {
+		current, err := s.provider.GetConfigMap(c.Context(), clusterID, namespaceID, configMapID)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to get config map", err)
+		}
+		return s.SendDryRun(c, DryRunDelete, current, nil)
+	}
+
+	if err := s.store.Delete(c.Context(), clusterID, namespaceID, "ConfigMap", configMapID); err != nil {
+		s.Logger.Warn("Failed to remove deleted config map from store",
+			"clusterID", clusterID,
+			"namespaceID", namespaceID,
+			"configMapID", configMapID,
+			"error", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// conflict reports a write based on an outdated resourceVersion, including
+// the current version when the config map can still be read
+func (s *ConfigMapService) conflict(c *fiber.Ctx, clusterID, namespaceID, configMapID, resourceVersion string) error {
+	response := fiber.Map{
+		"error":           "config map " + configMapID + " was changed by someone else, reload it and try again",
+		"resourceVersion": resourceVersion,
+	}
+
+	if current, err := s.provider.GetConfigMap(c.Context(), clusterID, namespaceID, configMapID); err == nil {
+		response["currentResourceVersion"] = current.ResourceVersion
+	}
+
+	s.Logger.Warn("Config map write conflict",
+		"clusterID", clusterID,
+		"namespaceID", namespaceID,
+		"configMapID", configMapID,
+		"resourceVersion", resourceVersion)
+	return c.Status(fiber.StatusConflict).JSON(response)
+}
+
+// storeConfigMap saves a written config map so reads reflect it before the
+// agent reports the change, and records its data as a revision by the user
+func (s *ConfigMapService) storeConfigMap(c *fiber.Ctx, clusterID string, configMap *corev1.ConfigMap) {
+	if err := s.store.Save(c.Context(), clusterID, configMap); err != nil {
+		s.Logger.Warn("Failed to store written config map",
+			"clusterID", clusterID,
+			"namespace", configMap.Namespace,
+			"name", configMap.Name,
+			"error", err)
+	}
+
+	user, _ := c.Locals("user").(auth.UserAttributes)
+	if _, err := configmaps.RecordRevision(c.Context(), s.store, clusterID, configMap, user.Username); err != nil {
+		s.Logger.Warn("Failed to record config map revision",
+			"clusterID", clusterID,
+			"namespace", configMap.Namespace,
+			"name", configMap.Name,
+			"error", err)
+	}
+}
+
+// ListRevisions returns the recorded revisions of a config map, newest first
+func (s *ConfigMapService) ListRevisions(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	configMapID := c.Params("configMapID")
+	if clusterID == "" || namespaceID == "" || configMapID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or config map ID")
+	}
+
+	revisions := make([]revision.Revision, 0)
+	if err := s.store.ListRevisions(c.Context(), clusterID, namespaceID, "ConfigMap", configMapID, &revisions); err != nil {
+		return s.InternalServerError(c, "Failed to list config map revisions", err)
+	}
+
+	return c.JSON(revisions)
+}
+
+// DiffRevisions compares two revisions of a config map, given by the from and
+// to query parameters. They default to the latest revision and the one before
+// it. When comparing against the latest revision, the response also lists the
+// workloads with pods started before that change, which still run the old
+// configuration until they restart.
+func (s *ConfigMapService) DiffRevisions(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	configMapID := c.Params("configMapID")
+	if clusterID == "" || namespaceID == "" || configMapID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or config map ID")
+	}
+
+	fromVersion, err := strconv.Atoi(c.Query("from", "0"))
+	if err != nil || fromVersion < 0 {
+		return s.BadRequest(c, "from must be a revision number")
+	}
+	toVersion, err := strconv.Atoi(c.Query("to", "0"))
+	if err != nil || toVersion < 0 {
+		return s.BadRequest(c, "to must be a revision number")
+	}
+
+	var latest revision.Revision
+	if err := s.store.GetRevision(c.Context(), clusterID, namespaceID, "ConfigMap", configMapID, 0, &latest); err != nil {
+		return s.revisionError(c, configMapID, err)
+	}
+
+	to := latest
+	if toVersion > 0 && toVersion != latest.Version {
+		if err := s.store.GetRevision(c.Context(), clusterID, namespaceID, "ConfigMap", configMapID, toVersion, &to); err != nil {
+			return s.revisionError(c, configMapID, err)
+		}
+	}
+
+	if fromVersion == 0 {
+		fromVersion = to.Version - 1
+	}
+
+	// Without an earlier revision every key counts as added
+	from := revision.Revision{Version: fromVersion}
+	if fromVersion > 0 {
+		if err := s.store.GetRevision(c.Context(), clusterID, namespaceID, "ConfigMap", configMapID, fromVersion, &from); err != nil {
+			return s.revisionError(c, configMapID, err)
+		}
+	}
+
+	response := fiber.Map{
+		"clusterID": clusterID,
+		"namespace": namespaceID,
+		"name":      configMapID,
+		"from":      from.Version,
+		"to":        to.Version,
+		"changedAt": to.CreatedAt,
+		"changedBy": to.CreatedBy,
+		"changes":   configmaps.DiffRevisions(&from, &to),
+	}
+
+	if to.Version == latest.Version {
+		stale, err := s.topology.StaleConsumers(c.Context(), clusterID, namespaceID, "ConfigMap", configMapID, latest.CreatedAt)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to find workloads using the config map", err)
+		}
+		response["staleWorkloads"] = stale.Consumers
+	}
+
+	return c.JSON(response)
+}
+
+// revisionError responds to a failed revision lookup
+func (s *ConfigMapService) revisionError(c *fiber.Ctx, configMapID string, err error) error {
+	if errors.Is(err, store.ErrNotFound) {
+		return s.NotFound(c, "ConfigMap revision", configMapID)
+	}
+	return s.InternalServerError(c, "Failed to get config map revision", err)
 }
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/cost"
+)
+
+type CostService struct {
+	BaseService
+	provider *cost.CostProvider
+}
+
+// NewCostService creates a new service for cost showback
+func NewCostService(provider *cost.CostProvider, logger *slog.Logger) *CostService {
+	return &CostService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetClusterCost estimates cost for the whole cluster
+func (s *CostService) GetClusterCost(c *fiber.Ctx) error {
+	return s.estimate(c, "")
+}
+
+// GetNamespaceCost estimates cost for the workloads of one namespace
+func (s *CostService) GetNamespaceCost(c *fiber.Ctx) error {
+	namespaceID := c.Params("namespaceID")
+	if namespaceID == "" {
+		return s.BadRequest(c, "missing namespace ID")
+	}
+
+	return s.estimate(c, namespaceID)
+}
+
+// estimate runs the estimate grouped by the groupBy query parameter
+func (s *CostService) estimate(c *fiber.Ctx, namespace string) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	defaultGroupBy := cost.GroupByNamespace
+	if namespace != "" {
+		defaultGroupBy = cost.GroupByWorkload
+	}
+
+	groupBy := c.Query("groupBy", defaultGroupBy)
+	if groupBy != cost.GroupByNamespace && groupBy != cost.GroupByWorkload {
+		return s.BadRequest(c, "groupBy must be namespace or workload")
+	}
+
+	report, err := s.provider.Estimate(c.Context(), clusterID, namespace, groupBy)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to estimate cost", err)
+	}
+
+	return c.JSON(report)
+}
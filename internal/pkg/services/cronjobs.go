@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/cronjobs"
+	"github.com/jbetancur/dashboard/internal/pkg/audit"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type CronJobService struct {
+	BaseService
+	provider *cronjobs.CronJobProvider
+}
+
+// NewCronJobService creates a new service for cron job actions
+func NewCronJobService(provider *cronjobs.CronJobProvider, logger *slog.Logger) *CronJobService {
+	return &CronJobService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// TriggerResult is a manually triggered job and where to follow it
+type TriggerResult struct {
+	Job *batchv1.Job `json:"job"`
+	// Pods lists the job's pods; Logs is the WebSocket that streams the logs
+	// of one of them, with {pod} and {container} left to fill in
+	Links struct {
+		Pods string `json:"pods"`
+		Logs string `json:"logs"`
+	} `json:"links"`
+}
+
+// TriggerCronJob runs a cron job now by creating a job from its template.
+// With ?dryRun=true the job is validated but not created.
+func (s *CronJobService) TriggerCronJob(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	cronJobID := c.Params("cronJobID")
+	if clusterID == "" || namespaceID == "" || cronJobID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or cron job ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	user, _ := c.Locals("user").(auth.UserAttributes)
+	job, err := s.provider.TriggerCronJob(c.Context(), clusterID, namespaceID, cronJobID, user.Username, dryRun)
+	switch {
+	case apierrors.IsNotFound(err):
+		return s.NotFound(c, "CronJob", cronJobID)
+	case apierrors.IsInvalid(err):
+		return s.BadRequest(c, err.Error())
+	case err != nil:
+		return s.InternalServerError(c, "Failed to trigger cron job", err)
+	}
+
+	if dryRun {
+		return s.SendDryRun(c, DryRunCreate, nil, job)
+	}
+
+	audit.Annotate(c, "job", job.Name)
+
+	result := TriggerResult{Job: job}
+	result.Links.Pods = "/api/v1/pods?" + url.Values{
+		"clusters":      {clusterID},
+		"namespace":     {namespaceID},
+		"labelSelector": {batchv1.JobNameLabel + "=" + job.Name},
+	}.Encode()
+	result.Links.Logs = fmt.Sprintf("/api/v1/clusters/%s/namespaces/%s/pods/{pod}/logs/{container}",
+		url.PathEscape(clusterID), url.PathEscape(namespaceID))
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
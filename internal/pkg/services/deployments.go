@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/deployments"
+	"github.com/jbetancur/dashboard/internal/pkg/audit"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type DeploymentService struct {
+	BaseService
+	provider *deployments.DeploymentProvider
+}
+
+// NewDeploymentService creates a new service for changing deployments
+func NewDeploymentService(provider *deployments.DeploymentProvider, logger *slog.Logger) *DeploymentService {
+	return &DeploymentService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// SetImage changes the image of a deployment's container. The audit entry
+// records the container and its previous and new image. With ?dryRun=true
+// the response previews the changes instead.
+func (s *DeploymentService) SetImage(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	deploymentID := c.Params("deploymentID")
+	if clusterID == "" || namespaceID == "" || deploymentID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or deployment ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	var req deployments.SetImageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return s.BadRequest(c, "invalid set-image request")
+	}
+
+	result, err := s.provider.SetImage(c.Context(), clusterID, namespaceID, deploymentID, req, dryRun)
+	switch {
+	case errors.Is(err, deployments.ErrInvalid):
+		return s.BadRequest(c, err.Error())
+	case errors.Is(err, deployments.ErrContainerNotFound):
+		return s.Error(c, fiber.StatusNotFound, "%s", err)
+	case errors.Is(err, deployments.ErrRepositoryMismatch):
+		return s.Error(c, fiber.StatusConflict, "%s", err)
+	case apierrors.IsNotFound(err):
+		return s.NotFound(c, "Deployment", deploymentID)
+	case apierrors.IsConflict(err):
+		return s.Error(c, fiber.StatusConflict, "Deployment %s changed while setting its image, retry", deploymentID)
+	case apierrors.IsInvalid(err):
+		return s.BadRequest(c, err.Error())
+	case err != nil:
+		return s.InternalServerError(c, "Failed to set image", err)
+	}
+
+	if dryRun {
+		current, err := s.provider.GetDeployment(c.Context(), clusterID, namespaceID, deploymentID)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to get deployment", err)
+		}
+		return s.SendDryRun(c, DryRunUpdate, current, result.Deployment)
+	}
+
+	audit.Annotate(c, "container", result.Container)
+	audit.Annotate(c, "previousImage", result.PreviousImage)
+	audit.Annotate(c, "image", result.Image)
+	s.Logger.Info("Deployment image changed", "clusterID", clusterID, "namespace", namespaceID,
+		"deployment", deploymentID, "container", result.Container, "previousImage", result.PreviousImage, "image", result.Image)
+
+	return c.JSON(result)
+}
+
+// Scale sets the replica count of a deployment. The audit entry records the
+// previous and new count. With ?dryRun=true the response previews the change instead.
+func (s *DeploymentService) Scale(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	deploymentID := c.Params("deploymentID")
+	if clusterID == "" || namespaceID == "" || deploymentID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or deployment ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	var req deployments.ScaleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return s.BadRequest(c, "invalid scale request")
+	}
+
+	result, err := s.provider.Scale(c.Context(), clusterID, namespaceID, deploymentID, req, dryRun)
+	switch {
+	case errors.Is(err, deployments.ErrInvalid):
+		return s.BadRequest(c, err.Error())
+	case apierrors.IsNotFound(err):
+		return s.NotFound(c, "Deployment", deploymentID)
+	case apierrors.IsConflict(err):
+		return s.Error(c, fiber.StatusConflict, "Deployment %s changed while scaling it, retry", deploymentID)
+	case apierrors.IsInvalid(err):
+		return s.BadRequest(c, err.Error())
+	case err != nil:
+		return s.InternalServerError(c, "Failed to scale deployment", err)
+	}
+
+	if dryRun {
+		// A dry run stores nothing, so the current scale is the preview with the old count
+		current := result.Scale.DeepCopy()
+		current.Spec.Replicas = result.PreviousReplicas
+		return s.SendDryRun(c, DryRunUpdate, current, result.Scale)
+	}
+
+	audit.Annotate(c, "previousReplicas", strconv.Itoa(int(result.PreviousReplicas)))
+	audit.Annotate(c, "replicas", strconv.Itoa(int(result.Replicas)))
+	s.Logger.Info("Deployment scaled", "clusterID", clusterID, "namespace", namespaceID,
+		"deployment", deploymentID, "previousReplicas", result.PreviousReplicas, "replicas", result.Replicas)
+
+	return c.JSON(result)
+}
+
+// Restart rolls out new pods for a deployment like kubectl rollout restart.
+// With ?dryRun=true the response previews the change instead.
+func (s *DeploymentService) Restart(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	deploymentID := c.Params("deploymentID")
+	if clusterID == "" || namespaceID == "" || deploymentID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or deployment ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	deployment, err := s.provider.Restart(c.Context(), clusterID, namespaceID, deploymentID, dryRun)
+	switch {
+	case apierrors.IsNotFound(err):
+		return s.NotFound(c, "Deployment", deploymentID)
+	case apierrors.IsInvalid(err):
+		return s.BadRequest(c, err.Error())
+	case err != nil:
+		return s.InternalServerError(c, "Failed to restart deployment", err)
+	}
+
+	if dryRun {
+		current, err := s.provider.GetDeployment(c.Context(), clusterID, namespaceID, deploymentID)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to get deployment", err)
+		}
+		return s.SendDryRun(c, DryRunUpdate, current, deployment)
+	}
+
+	s.Logger.Info("Deployment restarted", "clusterID", clusterID, "namespace", namespaceID, "deployment", deploymentID)
+
+	return c.JSON(deployment)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/describe"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type DescribeService struct {
+	BaseService
+	clusterManager *cluster.Manager
+}
+
+// NewDescribeService creates a new service for kubectl-style descriptions
+func NewDescribeService(clusterManager *cluster.Manager, logger *slog.Logger) *DescribeService {
+	return &DescribeService{
+		BaseService:    BaseService{Logger: logger},
+		clusterManager: clusterManager,
+	}
+}
+
+// GetDescription returns a handler serving the plain text description of an object of the given kind
+func (s *DescribeService) GetDescription(kind assets.KindInfo) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clusterID := c.Params("clusterID")
+		namespaceID := c.Params("namespaceID")
+		name := c.Params("name")
+
+		if clusterID == "" || namespaceID == "" || name == "" {
+			return s.BadRequest(c, "missing cluster, namespace or name")
+		}
+
+		conn, err := s.clusterManager.GetCluster(clusterID)
+		if err != nil {
+			return s.NotFound(c, "Cluster", clusterID)
+		}
+
+		text, err := describe.Describe(c.Context(), conn.Client, kind.Kind, namespaceID, name)
+		if apierrors.IsNotFound(err) {
+			return s.NotFound(c, kind.Kind, namespaceID+"/"+name)
+		}
+		if err != nil {
+			return s.InternalServerError(c, "Failed to describe "+kind.Kind, err)
+		}
+
+		c.Type("txt", "utf-8")
+		return c.SendString(text)
+	}
+}
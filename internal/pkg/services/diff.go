@@ -0,0 +1,51 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/diff"
+)
+
+type DiffService struct {
+	BaseService
+	provider *diff.DiffProvider
+}
+
+// NewDiffService creates a new service comparing stored objects with the live cluster
+func NewDiffService(provider *diff.DiffProvider, logger *slog.Logger) *DiffService {
+	return &DiffService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// DiffPod compares the stored copy of a pod with the cluster
+func (s *DiffService) DiffPod(c *fiber.Ctx) error {
+	return s.diff(c, "Pod", c.Params("namespaceID"), c.Params("podID"))
+}
+
+// DiffConfigMap compares the stored copy of a config map with the cluster
+func (s *DiffService) DiffConfigMap(c *fiber.Ctx) error {
+	return s.diff(c, "ConfigMap", c.Params("namespaceID"), c.Params("configMapID"))
+}
+
+// DiffNamespace compares the stored copy of a namespace with the cluster
+func (s *DiffService) DiffNamespace(c *fiber.Ctx) error {
+	return s.diff(c, "Namespace", "", c.Params("namespaceID"))
+}
+
+// diff runs the comparison for one object
+func (s *DiffService) diff(c *fiber.Ctx, kind, namespace, name string) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" || name == "" {
+		return s.BadRequest(c, "missing cluster ID or name")
+	}
+
+	result, err := s.provider.Diff(c.Context(), clusterID, kind, namespace, name)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to diff object", err)
+	}
+
+	return c.JSON(result)
+}
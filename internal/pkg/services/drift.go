@@ -0,0 +1,43 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/drift"
+)
+
+type DriftService struct {
+	BaseService
+	provider *drift.DriftProvider
+}
+
+// NewDriftService creates a new service for cross-cluster drift reports
+func NewDriftService(provider *drift.DriftProvider, logger *slog.Logger) *DriftService {
+	return &DriftService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetDrift returns a handler comparing workloads of the given kind between
+// the source and target clusters
+func (s *DriftService) GetDrift(kind assets.KindInfo) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clusterID := c.Params("clusterID")
+		targetClusterID := c.Params("targetClusterID")
+		namespaceID := c.Params("namespaceID")
+
+		if clusterID == "" || targetClusterID == "" || namespaceID == "" {
+			return s.BadRequest(c, "missing cluster, target cluster or namespace")
+		}
+
+		result, err := s.provider.Compare(c.Context(), clusterID, targetClusterID, namespaceID, kind.Kind)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to build drift report", err)
+		}
+
+		return c.JSON(result)
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/diff"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Dry-run write operations
+const (
+	DryRunCreate = "create"
+	DryRunUpdate = "update"
+	DryRunDelete = "delete"
+)
+
+// DryRunResult previews a write the cluster accepted without persisting it:
+// the object as it would be stored, or as it is now for a delete, and the
+// fields the write would change. Stored holds current values, Live predicted ones.
+type DryRunResult struct {
+	DryRun    bool           `json:"dryRun"`
+	Operation string         `json:"operation"`
+	Object    runtime.Object `json:"object,omitempty"`
+	Changes   []diff.Change  `json:"changes"`
+}
+
+// dryRunQuery reads the dryRun query parameter every write endpoint accepts
+func dryRunQuery(c *fiber.Ctx) (bool, error) {
+	value := c.Query("dryRun")
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// SendDryRun responds with the preview of a dry-run write. current is nil for
+// creates and predicted is nil for deletes.
+func (s *BaseService) SendDryRun(c *fiber.Ctx, operation string, current, predicted runtime.Object) error {
+	result := DryRunResult{DryRun: true, Operation: operation, Object: predicted, Changes: make([]diff.Change, 0)}
+	if operation == DryRunDelete {
+		result.Object = current
+	} else {
+		changes, err := diff.Objects(current, predicted)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to compare dry-run result", err)
+		}
+		result.Changes = changes
+	}
+
+	return c.JSON(result)
+}
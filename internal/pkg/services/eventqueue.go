@@ -0,0 +1,26 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/eventqueue"
+)
+
+type EventQueueService struct {
+	BaseService
+	queue *eventqueue.Queue
+}
+
+// NewEventQueueService creates a new service reporting on the agent event queue
+func NewEventQueueService(queue *eventqueue.Queue, logger *slog.Logger) *EventQueueService {
+	return &EventQueueService{
+		BaseService: BaseService{Logger: logger},
+		queue:       queue,
+	}
+}
+
+// GetQueueStats returns the event queue depth and throughput
+func (s *EventQueueService) GetQueueStats(c *fiber.Ctx) error {
+	return c.JSON(s.queue.Stats())
+}
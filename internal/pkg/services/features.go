@@ -0,0 +1,50 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/features"
+)
+
+type FeatureService struct {
+	BaseService
+	flags *features.Manager
+}
+
+// NewFeatureService creates a new service for reading and overriding feature flags
+func NewFeatureService(flags *features.Manager, logger *slog.Logger) *FeatureService {
+	return &FeatureService{
+		BaseService: BaseService{Logger: logger},
+		flags:       flags,
+	}
+}
+
+// ListFeatures returns the state of every feature flag for the deployment,
+// or for the cluster in the "cluster" query
+func (s *FeatureService) ListFeatures(c *fiber.Ctx) error {
+	return c.JSON(s.flags.List(c.Context(), c.Query("cluster")))
+}
+
+// SetFeature turns a feature flag on or off for the whole deployment until
+// changed again. A null "enabled" goes back to the configured state.
+func (s *FeatureService) SetFeature(c *fiber.Ctx) error {
+	feature := c.Params("feature")
+	if !features.Known(feature) {
+		return s.NotFound(c, "Feature", feature)
+	}
+
+	var request featureRequest
+	if err := c.BodyParser(&request); err != nil {
+		return s.BadRequest(c, "invalid feature request")
+	}
+
+	user, _ := c.Locals("user").(auth.UserAttributes)
+	if err := s.flags.SetOverride(c.Context(), feature, request.Enabled, user.Username); err != nil {
+		return s.InternalServerError(c, "Failed to set feature", err)
+	}
+
+	s.Logger.Info("Set feature", "feature", feature, "enabled", request.Enabled, "user", user.Username)
+	return c.JSON(s.flags.List(c.Context(), ""))
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"path"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/files"
+	"github.com/jbetancur/dashboard/internal/pkg/podexec"
+)
+
+type FileService struct {
+	BaseService
+	provider *files.FileProvider
+}
+
+// NewFileService creates a new service for browsing and copying container files
+func NewFileService(provider *files.FileProvider, logger *slog.Logger) *FileService {
+	return &FileService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// ListFiles returns the entries of the directory given by the path query
+// parameter, defaulting to the container's root
+func (s *FileService) ListFiles(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or pod ID")
+	}
+
+	entries, err := s.provider.List(c.Context(), clusterID, namespaceID, podID, c.Query("container"), c.Query("path", "/"))
+	if err != nil {
+		return s.fileError(c, "Failed to list files", err)
+	}
+
+	return c.JSON(entries)
+}
+
+// DownloadFile sends the file given by the path query parameter. With
+// archive=true it sends a tar archive instead, which also works for directories.
+func (s *FileService) DownloadFile(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+	filePath := c.Query("path")
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or pod ID")
+	}
+	if filePath == "" {
+		return s.BadRequest(c, "missing path parameter")
+	}
+
+	if c.QueryBool("archive") {
+		archive, err := s.provider.Archive(c.Context(), clusterID, namespaceID, podID, c.Query("container"), filePath)
+		if err != nil {
+			return s.fileError(c, "Failed to download files", err)
+		}
+
+		c.Attachment(path.Base(filePath) + ".tar")
+		c.Set(fiber.HeaderContentType, "application/x-tar")
+		return c.Send(archive)
+	}
+
+	content, err := s.provider.Download(c.Context(), clusterID, namespaceID, podID, c.Query("container"), filePath)
+	if err != nil {
+		return s.fileError(c, "Failed to download file", err)
+	}
+
+	c.Attachment(path.Base(filePath))
+	c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	return c.Send(content)
+}
+
+// UploadFile writes the multipart form file "file" into the directory given
+// by the path query parameter
+func (s *FileService) UploadFile(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+	dir := c.Query("path")
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or pod ID")
+	}
+	if dir == "" {
+		return s.BadRequest(c, "missing path parameter")
+	}
+
+	// Files are written by tar inside the container, which has no dry run
+	if dryRun, err := dryRunQuery(c); err != nil || dryRun {
+		return s.BadRequest(c, "dry run is not supported for file uploads")
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return s.BadRequest(c, "request must include a file form field")
+	}
+	if header.Size > s.provider.MaxUploadBytes() {
+		return s.Error(c, fiber.StatusRequestEntityTooLarge, "file is larger than %d bytes", s.provider.MaxUploadBytes())
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return s.InternalServerError(c, "Failed to read uploaded file", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to read uploaded file", err)
+	}
+
+	if err := s.provider.Upload(c.Context(), clusterID, namespaceID, podID, c.Query("container"), dir, header.Filename, content); err != nil {
+		return s.fileError(c, "Failed to upload file", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"path": path.Join(dir, header.Filename),
+		"size": len(content),
+	})
+}
+
+// fileError responds to a failed file operation
+func (s *FileService) fileError(c *fiber.Ctx, message string, err error) error {
+	var exitErr *podexec.ExitError
+	switch {
+	case errors.Is(err, files.ErrTooLarge):
+		return s.Error(c, fiber.StatusRequestEntityTooLarge, "%v", err)
+	case errors.Is(err, files.ErrInvalidPath), errors.Is(err, files.ErrNotAFile), errors.As(err, &exitErr):
+		return s.BadRequest(c, err.Error())
+	default:
+		return s.InternalServerError(c, message, err)
+	}
+}
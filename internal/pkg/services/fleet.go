@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/fleet"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type FleetService struct {
+	BaseService
+	store      store.Repository
+	authorizer auth.Authorizer
+}
+
+// NewFleetService creates a new service for queries across all clusters
+func NewFleetService(store store.Repository, authorizer auth.Authorizer, logger *slog.Logger) *FleetService {
+	return &FleetService{
+		BaseService: BaseService{Logger: logger},
+		store:       store,
+		authorizer:  authorizer,
+	}
+}
+
+// ListPods returns pods from every cluster the user may list them in
+func (s *FleetService) ListPods(c *fiber.Ctx) error {
+	return listFleet[corev1.Pod](s, c, "Pod", "pods")
+}
+
+// ListConfigMaps returns config maps from every cluster the user may list them in
+func (s *FleetService) ListConfigMaps(c *fiber.Ctx) error {
+	return listFleet[corev1.ConfigMap](s, c, "ConfigMap", "configmaps")
+}
+
+// ListNamespaces returns namespaces from every cluster the user may list them in
+func (s *FleetService) ListNamespaces(c *fiber.Ctx) error {
+	return listFleet[corev1.Namespace](s, c, "Namespace", "namespaces")
+}
+
+// listFleet runs a fleet query from the namespace, labelSelector, clusters
+// and status query parameters, so a filtered view can be shared as a link
+func listFleet[T any](s *FleetService, c *fiber.Ctx, kind, resource string) error {
+	selector, err := labels.Parse(c.Query("labelSelector"))
+	if err != nil {
+		return s.BadRequest(c, "invalid labelSelector: "+err.Error())
+	}
+
+	var clusters []string
+	if value := c.Query("clusters"); value != "" {
+		clusters = strings.Split(value, ",")
+	}
+	if err := fleet.ValidateClusters(clusters); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	namespace := c.Query("namespace")
+	if kind == "Namespace" {
+		namespace = ""
+	}
+
+	return runFleet[T](c, &s.BaseService, s.store, s.authorizer, fleet.Query{
+		Kind:      kind,
+		Namespace: namespace,
+		Selector:  selector,
+		Clusters:  clusters,
+		Status:    c.Query("status"),
+	}, resource)
+}
+
+// runFleet runs a fleet query for the user. Clusters where they lack list
+// permission are left out.
+func runFleet[T any](c *fiber.Ctx, s *BaseService, store store.Repository, authorizer auth.Authorizer,
+	query fleet.Query, resource string) error {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	query.Allow = func(ctx context.Context, clusterID string) (bool, error) {
+		return authorizer.CanAccess(ctx, clusterID, user, resource, query.Namespace, "", "list")
+	}
+
+	result, err := fleet.List[T](c.Context(), store, query)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to list "+resource+" across clusters", err)
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/graph"
+)
+
+type GraphService struct {
+	BaseService
+	provider *graph.GraphProvider
+}
+
+// NewGraphService creates a new service for ownership graphs
+func NewGraphService(provider *graph.GraphProvider, logger *slog.Logger) *GraphService {
+	return &GraphService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetGraph returns the ownership tree of a namespace, or of the object named
+// by the root query parameter as Kind/name
+func (s *GraphService) GetGraph(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	result, err := s.provider.Build(c.Context(), clusterID, namespaceID, c.Query("root"))
+	if err != nil {
+		return s.InternalServerError(c, "Failed to build ownership graph", err)
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/images"
+)
+
+type ImageService struct {
+	BaseService
+	provider *images.ImageProvider
+}
+
+// NewImageService creates a new service for image inventories
+func NewImageService(provider *images.ImageProvider, logger *slog.Logger) *ImageService {
+	return &ImageService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetInventory returns the images running in a cluster, or in the namespace
+// when one is in the path. With ?registry=true each image is also looked up
+// in its registry when lookups are enabled.
+func (s *ImageService) GetInventory(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	result, err := s.provider.Inventory(c.Context(), clusterID, c.Params("namespaceID"), c.QueryBool("registry"))
+	if err != nil {
+		return s.InternalServerError(c, "Failed to build image inventory", err)
+	}
+
+	return c.JSON(result)
+}
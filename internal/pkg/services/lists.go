@@ -0,0 +1,77 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/listcache"
+)
+
+type ListCacheService struct {
+	BaseService
+	lists *listcache.Cache
+}
+
+// NewListCacheService creates a new service for managing the list cache
+func NewListCacheService(lists *listcache.Cache, logger *slog.Logger) *ListCacheService {
+	return &ListCacheService{
+		BaseService: BaseService{Logger: logger},
+		lists:       lists,
+	}
+}
+
+// GetCacheStats returns the list cache metrics
+func (s *ListCacheService) GetCacheStats(c *fiber.Ctx) error {
+	return c.JSON(s.lists.Stats())
+}
+
+// InvalidateCache drops cached lists of the cluster given in the query
+// string, or the whole cache when no cluster is given
+func (s *ListCacheService) InvalidateCache(c *fiber.Ctx) error {
+	clusterID := c.Query("cluster")
+
+	var removed int
+	if clusterID != "" {
+		removed = s.lists.InvalidateCluster(clusterID)
+	} else {
+		removed = s.lists.InvalidateAll()
+	}
+
+	return c.JSON(fiber.Map{
+		"cluster": clusterID,
+		"removed": removed,
+	})
+}
+
+// listKey identifies a list request by its cluster, kind, namespace and query string
+func listKey(c *fiber.Ctx, clusterID, kind, namespace string) listcache.Key {
+	return listcache.Key{
+		ClusterID: clusterID,
+		Kind:      kind,
+		Namespace: namespace,
+		Selector:  string(c.Request().URI().QueryString()),
+	}
+}
+
+// sendCachedList writes a cached list, reporting whether there was one
+func sendCachedList(c *fiber.Ctx, lists *listcache.Cache, key listcache.Key) bool {
+	body, ok := lists.Get(key)
+	if !ok {
+		return false
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	_ = c.Send(body)
+	return true
+}
+
+// sendList caches a list loaded from the store and writes it
+func sendList(c *fiber.Ctx, lists *listcache.Cache, key listcache.Key, list any) error {
+	body, err := lists.Set(key, list)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(body)
+}
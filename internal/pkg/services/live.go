@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/live"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type LiveService struct {
+	BaseService
+	provider *live.LiveProvider
+}
+
+// NewLiveService creates a new service for reading and editing objects in the cluster
+func NewLiveService(provider *live.LiveProvider, logger *slog.Logger) *LiveService {
+	return &LiveService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetObject returns a handler serving an object of the given kind as the
+// cluster has it, with the resourceVersion an edit must be based on
+func (s *LiveService) GetObject(kind assets.KindInfo) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clusterID := c.Params("clusterID")
+		namespaceID := c.Params("namespaceID")
+		name := c.Params("name")
+		if clusterID == "" || namespaceID == "" || name == "" {
+			return s.BadRequest(c, "missing cluster, namespace or name")
+		}
+
+		obj, err := s.provider.GetObject(c.Context(), clusterID, kind, namespaceID, name)
+		switch {
+		case apierrors.IsNotFound(err):
+			return s.NotFound(c, kind.Kind, namespaceID+"/"+name)
+		case err != nil:
+			return s.InternalServerError(c, "Failed to get "+kind.Kind, err)
+		}
+
+		return s.SendObject(c, obj)
+	}
+}
+
+// UpdateObject returns a handler replacing an object of the given kind with
+// the one in the request body. With ?dryRun=true the response previews the
+// changes instead.
+func (s *LiveService) UpdateObject(kind assets.KindInfo) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clusterID := c.Params("clusterID")
+		namespaceID := c.Params("namespaceID")
+		name := c.Params("name")
+		if clusterID == "" || namespaceID == "" || name == "" {
+			return s.BadRequest(c, "missing cluster, namespace or name")
+		}
+
+		dryRun, err := dryRunQuery(c)
+		if err != nil {
+			return s.BadRequest(c, "dryRun must be true or false")
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(c.Body()); err != nil {
+			return s.BadRequest(c, "request body must be a "+kind.Kind)
+		}
+
+		updated, err := s.provider.UpdateObject(c.Context(), clusterID, kind, namespaceID, name, obj, dryRun)
+		switch {
+		case errors.Is(err, live.ErrInvalid):
+			return s.BadRequest(c, err.Error())
+		case apierrors.IsNotFound(err):
+			return s.NotFound(c, kind.Kind, namespaceID+"/"+name)
+		case apierrors.IsConflict(err):
+			return s.Error(c, fiber.StatusConflict, "%s %s changed since it was read, reload and retry", kind.Kind, name)
+		case apierrors.IsInvalid(err):
+			return s.BadRequest(c, err.Error())
+		case err != nil:
+			return s.InternalServerError(c, "Failed to update "+kind.Kind, err)
+		}
+
+		if dryRun {
+			current, err := s.provider.GetObject(c.Context(), clusterID, kind, namespaceID, name)
+			if err != nil {
+				return s.InternalServerError(c, "Failed to get "+kind.Kind, err)
+			}
+			return s.SendDryRun(c, DryRunUpdate, current, updated)
+		}
+
+		s.Logger.Info("Object updated", "clusterID", clusterID, "namespace", namespaceID, "kind", kind.Kind, "name", name)
+
+		return c.JSON(updated)
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/pods"
+	"github.com/jbetancur/dashboard/internal/pkg/objectstore"
+)
+
+const (
+	// maxArchivedLogBytes bounds the logs kept in one archive
+	maxArchivedLogBytes = 64 << 20
+
+	// logArchiveTimeout bounds reading logs for an archive
+	logArchiveTimeout = 2 * time.Minute
+)
+
+type LogArchiveService struct {
+	BaseService
+	provider *pods.PodProvider
+	objects  objectstore.Store
+}
+
+// LogArchive is an archived copy of a container's logs
+type LogArchive struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// NewLogArchiveService creates a new service archiving container logs to the object store
+func NewLogArchiveService(provider *pods.PodProvider, objects objectstore.Store, logger *slog.Logger) *LogArchiveService {
+	return &LogArchiveService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+		objects:     objects,
+	}
+}
+
+// ArchivePodLogs saves the current logs of a container, or the last tail
+// lines of them, as a compressed archive in the object store
+func (s *LogArchiveService) ArchivePodLogs(c *fiber.Ctx) error {
+	if s.objects == nil {
+		return s.Error(c, fiber.StatusServiceUnavailable, "object store is not configured")
+	}
+
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+	containerName := c.Params("containerName")
+
+	tailLines := int64(c.QueryInt("tail", 0))
+	if tailLines < 0 {
+		return s.BadRequest(c, "tail must not be negative")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), logArchiveTimeout)
+	defer cancel()
+
+	logs, err := s.provider.ReadPodLogs(ctx, clusterID, namespaceID, podID, containerName, tailLines)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to read pod logs", err)
+	}
+	defer func() {
+		_ = logs.Close()
+	}()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	written, err := io.Copy(gz, io.LimitReader(logs, maxArchivedLogBytes+1))
+	if err != nil {
+		return s.InternalServerError(c, "Failed to read pod logs", err)
+	}
+	if err := gz.Close(); err != nil {
+		return s.InternalServerError(c, "Failed to compress pod logs", err)
+	}
+
+	archive := LogArchive{
+		Name:      time.Now().UTC().Format("20060102T150405Z") + ".log.gz",
+		Size:      int64(buf.Len()),
+		CreatedAt: time.Now(),
+		Truncated: written > maxArchivedLogBytes,
+	}
+
+	key := logArchivePrefix(clusterID, namespaceID, podID, containerName) + archive.Name
+	if err := s.objects.Put(c.Context(), key, buf.Bytes(), "application/gzip"); err != nil {
+		return s.InternalServerError(c, "Failed to store log archive", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(archive)
+}
+
+// ListPodLogArchives returns the archived logs of a container, oldest first
+func (s *LogArchiveService) ListPodLogArchives(c *fiber.Ctx) error {
+	if s.objects == nil {
+		return s.Error(c, fiber.StatusServiceUnavailable, "object store is not configured")
+	}
+
+	prefix := logArchivePrefix(c.Params("clusterID"), c.Params("namespaceID"), c.Params("podID"), c.Params("containerName"))
+
+	objects, err := s.objects.List(c.Context(), prefix)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to list log archives", err)
+	}
+
+	archives := make([]LogArchive, 0, len(objects))
+	for _, object := range objects {
+		archives = append(archives, LogArchive{
+			Name:      strings.TrimPrefix(object.Key, prefix),
+			Size:      object.Size,
+			CreatedAt: object.Modified,
+		})
+	}
+
+	return c.JSON(archives)
+}
+
+// DownloadPodLogArchive sends an archived copy of a container's logs
+func (s *LogArchiveService) DownloadPodLogArchive(c *fiber.Ctx) error {
+	if s.objects == nil {
+		return s.Error(c, fiber.StatusServiceUnavailable, "object store is not configured")
+	}
+
+	name := c.Params("archive")
+	if name == "" || path.Base(name) != name {
+		return s.BadRequest(c, "invalid archive name")
+	}
+
+	key := logArchivePrefix(c.Params("clusterID"), c.Params("namespaceID"), c.Params("podID"), c.Params("containerName")) + name
+
+	data, err := s.objects.Get(c.Context(), key)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return s.NotFound(c, "log archive", name)
+	}
+	if err != nil {
+		return s.InternalServerError(c, "Failed to read log archive", err)
+	}
+
+	c.Attachment(fmt.Sprintf("%s-%s-%s", c.Params("podID"), c.Params("containerName"), name))
+	c.Set(fiber.HeaderContentType, "application/gzip")
+	return c.Send(data)
+}
+
+// logArchivePrefix is where the log archives of a container are kept
+func logArchivePrefix(clusterID, namespace, pod, container string) string {
+	return fmt.Sprintf("logs/%s/%s/%s/%s/", clusterID, namespace, pod, container)
+}
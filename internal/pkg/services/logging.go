@@ -0,0 +1,68 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/logging"
+)
+
+type LoggingService struct {
+	BaseService
+	level *logging.Level
+}
+
+// NewLoggingService creates a new service for reading and changing the log level
+func NewLoggingService(level *logging.Level, logger *slog.Logger) *LoggingService {
+	return &LoggingService{
+		BaseService: BaseService{Logger: logger},
+		level:       level,
+	}
+}
+
+// loggingResponse is the logging configuration in effect
+type loggingResponse struct {
+	Format     string `json:"format"`
+	Level      string `json:"level"`
+	Configured string `json:"configured"`
+}
+
+// loggingRequest changes the log level. An empty level goes back to the
+// configured one.
+type loggingRequest struct {
+	Level string `json:"level"`
+}
+
+// GetLogging returns the log format and level
+func (s *LoggingService) GetLogging(c *fiber.Ctx) error {
+	return c.JSON(s.response())
+}
+
+// SetLogLevel changes the log level until the process restarts or it is
+// changed again
+func (s *LoggingService) SetLogLevel(c *fiber.Ctx) error {
+	var request loggingRequest
+	if err := c.BodyParser(&request); err != nil {
+		return s.BadRequest(c, "invalid logging request")
+	}
+
+	if request.Level == "" {
+		s.level.Reset()
+	} else if _, err := s.level.Set(request.Level); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	user, _ := c.Locals("user").(auth.UserAttributes)
+	// Logged at warn so the change shows at any level
+	s.Logger.Warn("Changed log level", "level", s.level.Get().String(), "user", user.Username)
+	return c.JSON(s.response())
+}
+
+func (s *LoggingService) response() loggingResponse {
+	return loggingResponse{
+		Format:     s.level.Format(),
+		Level:      s.level.Get().String(),
+		Configured: s.level.Configured().String(),
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/metrics"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+// defaultMetricsHistory is how far back history queries look without a since parameter
+const defaultMetricsHistory = time.Hour
+
+type MetricsService struct {
+	BaseService
+	provider *metrics.MetricsProvider
+	store    store.Repository
+}
+
+// NewMetricsService creates a new service for metrics-server usage and sampled history
+func NewMetricsService(provider *metrics.MetricsProvider, store store.Repository, logger *slog.Logger) *MetricsService {
+	return &MetricsService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+		store:       store,
+	}
+}
+
+// ListPodMetrics returns the current usage of all pods in a namespace
+func (s *MetricsService) ListPodMetrics(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	if namespaceID == "" {
+		return s.BadRequest(c, "missing namespace ID")
+	}
+
+	podMetrics, err := s.provider.ListPodMetrics(c.Context(), clusterID, namespaceID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to get pod metrics", err)
+	}
+
+	return c.JSON(podMetrics)
+}
+
+// GetPodMetrics returns the current usage of a pod
+func (s *MetricsService) GetPodMetrics(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	if namespaceID == "" {
+		return s.BadRequest(c, "missing namespace ID")
+	}
+
+	if podID == "" {
+		return s.BadRequest(c, "missing pod ID")
+	}
+
+	podMetrics, err := s.provider.GetPodMetrics(c.Context(), clusterID, namespaceID, podID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to get pod metrics", err)
+	}
+
+	return c.JSON(podMetrics)
+}
+
+// GetPodMetricsHistory returns sampled usage of a pod
+func (s *MetricsService) GetPodMetricsHistory(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or pod ID")
+	}
+
+	return s.history(c, metrics.SampleQuery{
+		ClusterID: clusterID,
+		Kind:      metrics.KindPod,
+		Namespace: namespaceID,
+		Name:      podID,
+	})
+}
+
+// ListNodeMetrics returns the current usage of all nodes
+func (s *MetricsService) ListNodeMetrics(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	nodeMetrics, err := s.provider.ListNodeMetrics(c.Context(), clusterID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to get node metrics", err)
+	}
+
+	return c.JSON(nodeMetrics)
+}
+
+// GetNodeMetrics returns the current usage of a node
+func (s *MetricsService) GetNodeMetrics(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	nodeID := c.Params("nodeID")
+
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	if nodeID == "" {
+		return s.BadRequest(c, "missing node ID")
+	}
+
+	nodeMetrics, err := s.provider.GetNodeMetrics(c.Context(), clusterID, nodeID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to get node metrics", err)
+	}
+
+	return c.JSON(nodeMetrics)
+}
+
+// GetNodeMetricsHistory returns sampled usage of a node
+func (s *MetricsService) GetNodeMetricsHistory(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	nodeID := c.Params("nodeID")
+
+	if clusterID == "" || nodeID == "" {
+		return s.BadRequest(c, "missing cluster or node ID")
+	}
+
+	return s.history(c, metrics.SampleQuery{
+		ClusterID: clusterID,
+		Kind:      metrics.KindNode,
+		Name:      nodeID,
+	})
+}
+
+// history returns stored samples from the "since" duration (default 1h) until now
+func (s *MetricsService) history(c *fiber.Ctx, query metrics.SampleQuery) error {
+	since := defaultMetricsHistory
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return s.BadRequest(c, fmt.Sprintf("invalid since duration: %s", value))
+		}
+		since = parsed
+	}
+
+	query.Since = time.Now().Add(-since)
+
+	var samples []metrics.Sample
+	if err := s.store.ListMetricSamples(c.Context(), query, &samples); err != nil {
+		return s.InternalServerError(c, "Failed to list metric samples", err)
+	}
+
+	return c.JSON(samples)
+}
@@ -1,29 +1,37 @@
 package services
 
 import (
+	"errors"
 	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jbetancur/dashboard/internal/pkg/assets/namespaces"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/listcache"
 
 	"github.com/jbetancur/dashboard/internal/pkg/store"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 type NamespaceService struct {
 	BaseService
-	provider *namespaces.NamespaceProvider
-	store    store.Repository
+	provider   *namespaces.NamespaceProvider
+	store      store.Repository
+	lists      *listcache.Cache
+	authorizer auth.Authorizer
 }
 
 // NewNamespaceService creates a new namespace service
-func NewNamespaceService(provider *namespaces.NamespaceProvider, store store.Repository,
-	logger *slog.Logger) *NamespaceService {
+func NewNamespaceService(provider *namespaces.NamespaceProvider, store store.Repository, lists *listcache.Cache,
+	authorizer auth.Authorizer, logger *slog.Logger) *NamespaceService {
 	return &NamespaceService{
 		BaseService: BaseService{Logger: logger},
 		provider:    provider,
 		store:       store,
+		lists:       lists,
+		authorizer:  authorizer,
 	}
 }
 
@@ -33,6 +41,11 @@ func (s *NamespaceService) ListNamespaces(c *fiber.Ctx) error {
 		return s.BadRequest(c, "missing cluster ID")
 	}
 
+	key := listKey(c, clusterID, "Namespace", "")
+	if sendCachedList(c, s.lists, key) {
+		return nil
+	}
+
 	s.Logger.Debug("Listing namespaces fom data store", "clusterID", clusterID)
 
 	// Use MongoDB to list namespaces instead of the provider
@@ -46,9 +59,10 @@ func (s *NamespaceService) ListNamespaces(c *fiber.Ctx) error {
 		// 	return s.Error(c, fiber.StatusInternalServerError, "failed to list namespaces: %v", err)
 		// }
 		// return c.JSON(directNamespaces)
+		return c.JSON(namespaces)
 	}
 
-	return c.JSON(namespaces)
+	return sendList(c, s.lists, key, namespaces)
 }
 
 func (s *NamespaceService) GetNamespace(c *fiber.Ctx) error {
@@ -84,5 +98,107 @@ func (s *NamespaceService) GetNamespace(c *fiber.Ctx) error {
 		// return c.JSON(directNamespace)
 	}
 
-	return c.JSON(&namespace)
+	return sendWithNotes(c, &s.BaseService, s.store, clusterID, "Namespace", &namespace)
+}
+
+// CreateNamespace creates a namespace from the request body, applying its
+// template, and stores it right away so lists show it before the agent
+// reports it. With ?dryRun=true nothing is created or stored.
+func (s *NamespaceService) CreateNamespace(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	var req namespaces.CreateRequest
+	if err := c.BodyParser(&req); err != nil || req.Name == "" {
+		return s.BadRequest(c, "request must name the namespace")
+	}
+
+	template, ok, err := s.provider.Template(req.Template)
+	if err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	// The template's quota and limits are created inside the new namespace
+	if ok && len(template.Quota) > 0 {
+		if _, err := s.CheckResourcePermission(c, s.authorizer, "resourcequotas", req.Name, "", "create"); err != nil {
+			return err
+		}
+	}
+	if ok && !template.Limits.Empty() {
+		if _, err := s.CheckResourcePermission(c, s.authorizer, "limitranges", req.Name, "", "create"); err != nil {
+			return err
+		}
+	}
+
+	result, err := s.provider.CreateNamespace(c.Context(), clusterID, req, dryRun)
+	switch {
+	case errors.Is(err, namespaces.ErrInvalidName):
+		return s.BadRequest(c, err.Error())
+	case apierrors.IsAlreadyExists(err):
+		return s.Error(c, fiber.StatusConflict, "namespace %s already exists", req.Name)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to create namespace", err)
+	}
+
+	// The preview includes the quota and limit range the template would add
+	if dryRun {
+		return c.JSON(result)
+	}
+
+	if err := s.store.Save(c.Context(), clusterID, result.Namespace); err != nil {
+		s.Logger.Warn("Failed to store created namespace", "clusterID", clusterID, "namespace", req.Name, "error", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// DeleteNamespace deletes a namespace and updates the stored copy, which
+// shows it terminating until the cluster finishes removing its contents.
+// With ?dryRun=true it returns the namespace that would be deleted.
+func (s *NamespaceService) DeleteNamespace(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	ns, err := s.provider.DeleteNamespace(c.Context(), clusterID, namespaceID, dryRun)
+	switch {
+	case errors.Is(err, namespaces.ErrProtected):
+		return s.Error(c, fiber.StatusForbidden, "namespace %s can't be deleted", namespaceID)
+	case apierrors.IsNotFound(err):
+		return s.NotFound(c, "Namespace", namespaceID)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to delete namespace", err)
+	}
+
+	if dryRun {
+		return s.SendDryRun(c, DryRunDelete, ns, nil)
+	}
+
+	if ns == nil {
+		err = s.store.Delete(c.Context(), clusterID, "", "Namespace", namespaceID)
+	} else {
+		err = s.store.Save(c.Context(), clusterID, ns)
+	}
+	if err != nil {
+		s.Logger.Warn("Failed to store deleted namespace", "clusterID", clusterID, "namespace", namespaceID, "error", err)
+	}
+
+	if ns == nil {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(ns)
 }
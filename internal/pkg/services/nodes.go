@@ -0,0 +1,61 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/nodes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type NodeService struct {
+	BaseService
+	provider *nodes.NodeProvider
+}
+
+// NewNodeService creates a new service for nodes and the pods on them
+func NewNodeService(provider *nodes.NodeProvider, logger *slog.Logger) *NodeService {
+	return &NodeService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetNode returns a node
+func (s *NodeService) GetNode(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	nodeID := c.Params("nodeID")
+	if clusterID == "" || nodeID == "" {
+		return s.BadRequest(c, "missing cluster ID or node")
+	}
+
+	node, err := s.provider.GetNode(c.Context(), clusterID, nodeID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return s.NotFound(c, "Node", nodeID)
+		}
+		return s.InternalServerError(c, "Failed to get node", err)
+	}
+
+	return s.SendObject(c, node)
+}
+
+// ListNodePods returns the pods scheduled on a node with their requests
+// totalled against the node's allocatable resources
+func (s *NodeService) ListNodePods(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	nodeID := c.Params("nodeID")
+	if clusterID == "" || nodeID == "" {
+		return s.BadRequest(c, "missing cluster ID or node")
+	}
+
+	result, err := s.provider.Pods(c.Context(), clusterID, nodeID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return s.NotFound(c, "Node", nodeID)
+		}
+		return s.InternalServerError(c, "Failed to list pods on node", err)
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/notes"
+	"github.com/jbetancur/dashboard/internal/pkg/output"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type NoteService struct {
+	BaseService
+	store      store.Repository
+	authorizer auth.Authorizer
+}
+
+// NewNoteService creates a new service for dashboard-local notes on resources
+func NewNoteService(store store.Repository, authorizer auth.Authorizer, logger *slog.Logger) *NoteService {
+	return &NoteService{
+		BaseService: BaseService{Logger: logger},
+		store:       store,
+		authorizer:  authorizer,
+	}
+}
+
+// ListResourceNotes returns a handler listing the notes on an object of the
+// given kind, or on the namespace itself for the "Namespace" kind
+func (s *NoteService) ListResourceNotes(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		query := notes.Query{ClusterID: c.Params("clusterID"), Kind: kind}
+		query.Namespace, query.Name = noteTarget(c, kind)
+
+		list := make([]notes.Note, 0)
+		if err := s.store.ListNotes(c.Context(), query, &list); err != nil {
+			return s.InternalServerError(c, "Failed to list notes", err)
+		}
+
+		return c.JSON(list)
+	}
+}
+
+// CreateNote returns a handler attaching a note from the request body to an
+// object of the given kind
+func (s *NoteService) CreateNote(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(auth.UserAttributes)
+		if !ok {
+			return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+		}
+
+		var note notes.Note
+		if err := c.BodyParser(&note); err != nil {
+			return s.BadRequest(c, "invalid note")
+		}
+		if err := note.Validate(); err != nil {
+			return s.BadRequest(c, err.Error())
+		}
+
+		note.ID = ""
+		note.ClusterID = c.Params("clusterID")
+		note.Kind = kind
+		note.Namespace, note.Name = noteTarget(c, kind)
+		note.Author = user.Username
+		note.CreatedAt = time.Now()
+		note.UpdatedAt = note.CreatedAt
+
+		if err := s.store.SaveNote(c.Context(), &note); err != nil {
+			return s.InternalServerError(c, "Failed to save note", err)
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(note)
+	}
+}
+
+// ListNotes returns the notes of a cluster the user may read the resources
+// of, optionally filtered by the kind, namespace, tag and author query
+// parameters, e.g. ?tag=bookmark for bookmarked resources
+func (s *NoteService) ListNotes(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	query := notes.Query{
+		ClusterID: c.Params("clusterID"),
+		Kind:      c.Query("kind"),
+		Namespace: c.Query("namespace"),
+		Tag:       c.Query("tag"),
+		Author:    c.Query("author"),
+	}
+	if query.Kind != "" && query.Kind != "Namespace" {
+		kind, ok := assets.LookupKind(query.Kind)
+		if !ok {
+			return s.BadRequest(c, "unsupported kind "+query.Kind)
+		}
+		query.Kind = kind.Kind
+	}
+
+	var list []notes.Note
+	if err := s.store.ListNotes(c.Context(), query, &list); err != nil {
+		return s.InternalServerError(c, "Failed to list notes", err)
+	}
+
+	visible := make([]notes.Note, 0, len(list))
+	for _, note := range list {
+		allowed, err := s.canRead(c, user, note)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to verify permissions", err)
+		}
+		if allowed {
+			visible = append(visible, note)
+		}
+	}
+
+	return c.JSON(visible)
+}
+
+// UpdateNote replaces the text and tags of a note. Only its author can.
+func (s *NoteService) UpdateNote(c *fiber.Ctx) error {
+	user, existing, err := s.ownNote(c)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	if existing.Author != user.Username {
+		return s.Error(c, fiber.StatusForbidden, "Only the author can change note %s", existing.ID)
+	}
+
+	var note notes.Note
+	if err := c.BodyParser(&note); err != nil {
+		return s.BadRequest(c, "invalid note")
+	}
+	if err := note.Validate(); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	existing.Text = note.Text
+	existing.Tags = note.Tags
+	existing.UpdatedAt = time.Now()
+	if err := s.store.SaveNote(c.Context(), existing); err != nil {
+		return s.InternalServerError(c, "Failed to save note", err)
+	}
+
+	return c.JSON(existing)
+}
+
+// DeleteNote removes a note. Its author and admins can.
+func (s *NoteService) DeleteNote(c *fiber.Ctx) error {
+	user, note, err := s.ownNote(c)
+	if err != nil || note == nil {
+		return err
+	}
+
+	if note.Author != user.Username && !slices.Contains(user.Groups, auth.AdminGroup) {
+		return s.Error(c, fiber.StatusForbidden, "Only the author can delete note %s", note.ID)
+	}
+
+	if err := s.store.DeleteNote(c.Context(), note.ID); err != nil {
+		return s.InternalServerError(c, "Failed to delete note", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ownNote loads the note named by the noteID parameter in the cluster of
+// the path. A nil note means a response has already been sent.
+func (s *NoteService) ownNote(c *fiber.Ctx) (auth.UserAttributes, *notes.Note, error) {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return user, nil, s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	noteID := c.Params("noteID")
+
+	var note notes.Note
+	err := s.store.GetNote(c.Context(), noteID, &note)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && note.ClusterID != c.Params("clusterID")) {
+		return user, nil, s.NotFound(c, "note", noteID)
+	}
+	if err != nil {
+		return user, nil, s.InternalServerError(c, "Failed to get note", err)
+	}
+
+	return user, &note, nil
+}
+
+// canRead reports whether a user may read the resource a note is on
+func (s *NoteService) canRead(c *fiber.Ctx, user auth.UserAttributes, note notes.Note) (bool, error) {
+	resource := "namespaces"
+	namespace := note.Namespace
+	if note.Kind != "Namespace" {
+		kind, ok := assets.LookupKind(note.Kind)
+		if !ok {
+			return false, nil
+		}
+		resource = kind.RBACResource()
+	}
+
+	return s.authorizer.CanAccess(c.Context(), note.ClusterID, user, resource, namespace, note.Name, "get")
+}
+
+// noteTarget returns the namespace and name of the object in the path. A
+// namespace's notes are kept under its name, without a namespace.
+func noteTarget(c *fiber.Ctx, kind string) (string, string) {
+	if kind == "Namespace" {
+		return "", c.Params("namespaceID")
+	}
+	return c.Params("namespaceID"), c.Params("name")
+}
+
+// sendWithNotes writes a stored object like SendObject, adding the notes on
+// it under "notes" in JSON responses. YAML exports are left re-applyable,
+// however YAML was asked for.
+func sendWithNotes(c *fiber.Ctx, s *BaseService, repo store.Repository, clusterID, kind string, obj runtime.Object) error {
+	if format, err := output.Negotiate(c); err != nil || format != output.FormatJSON {
+		return s.SendObject(c, obj)
+	}
+
+	meta, err := apimeta.Accessor(obj)
+	if err != nil || meta.GetName() == "" {
+		return s.SendObject(c, obj)
+	}
+
+	query := notes.Query{ClusterID: clusterID, Kind: kind, Namespace: meta.GetNamespace(), Name: meta.GetName()}
+	if kind == "Namespace" {
+		query.Namespace = ""
+	}
+
+	var list []notes.Note
+	if err := repo.ListNotes(c.Context(), query, &list); err != nil {
+		s.Logger.Warn("Failed to list notes", "clusterID", clusterID, "kind", kind, "name", meta.GetName(), "error", err)
+		return s.SendObject(c, obj)
+	}
+	if len(list) == 0 {
+		return s.SendObject(c, obj)
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to encode object", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return s.InternalServerError(c, "Failed to encode object", err)
+	}
+	if fields["notes"], err = json.Marshal(list); err != nil {
+		return s.InternalServerError(c, "Failed to encode notes", err)
+	}
+
+	return c.JSON(fields)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/pdbs"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type PDBService struct {
+	BaseService
+	provider *pdbs.PDBProvider
+	store    store.Repository
+}
+
+// NewPDBService creates a new service for pod disruption budgets
+func NewPDBService(provider *pdbs.PDBProvider, store store.Repository, logger *slog.Logger) *PDBService {
+	return &PDBService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+		store:       store,
+	}
+}
+
+// ListPDBs returns the pod disruption budgets of a namespace
+func (s *PDBService) ListPDBs(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	result, err := s.provider.ListPDBs(c.Context(), clusterID, namespaceID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to list pod disruption budgets", err)
+	}
+
+	return c.JSON(result)
+}
+
+// GetPDB returns a pod disruption budget
+func (s *PDBService) GetPDB(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	name := c.Params("pdbID")
+	if clusterID == "" || namespaceID == "" || name == "" {
+		return s.BadRequest(c, "missing cluster, namespace or name")
+	}
+
+	pdb, err := s.provider.GetPDB(c.Context(), clusterID, namespaceID, name)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to get pod disruption budget", err)
+	}
+
+	return s.SendObject(c, pdb)
+}
+
+// GetWorkload returns a handler serving a workload controller of the given
+// kind together with whether a voluntary disruption is currently allowed
+func (s *PDBService) GetWorkload(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clusterID := c.Params("clusterID")
+		namespaceID := c.Params("namespaceID")
+		name := c.Params("name")
+		if clusterID == "" || namespaceID == "" || name == "" {
+			return s.BadRequest(c, "missing cluster, namespace or name")
+		}
+
+		result, err := s.provider.GetWorkload(c.Context(), clusterID, kind, namespaceID, name)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to get workload", err)
+		}
+
+		return c.JSON(result)
+	}
+}
+
+// GetPodDisruption returns whether the pod may currently be evicted
+func (s *PDBService) GetPodDisruption(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or pod ID")
+	}
+
+	var pod corev1.Pod
+	err := s.store.Get(c.Context(), clusterID, namespaceID, "Pod", podID, &pod)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return s.NotFound(c, "pod", podID)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to get pod", err)
+	}
+
+	result, err := s.provider.Disruption(c.Context(), clusterID, namespaceID, pod.Labels)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to evaluate pod disruption budgets", err)
+	}
+
+	return c.JSON(result)
+}
@@ -3,29 +3,41 @@ package services
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 	"github.com/jbetancur/dashboard/internal/pkg/assets/pods"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/listcache"
+	"github.com/jbetancur/dashboard/internal/pkg/podexec"
+	"github.com/jbetancur/dashboard/internal/pkg/recording"
 	"github.com/jbetancur/dashboard/internal/pkg/store"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 type PodService struct {
 	BaseService
-	provider *pods.PodProvider
-	store    store.Repository
+	provider  *pods.PodProvider
+	store     store.Repository
+	lists     *listcache.Cache
+	recording recording.Config
 }
 
-func NewPodService(provider *pods.PodProvider, store store.Repository, logger *slog.Logger) *PodService {
+func NewPodService(provider *pods.PodProvider, store store.Repository, lists *listcache.Cache, recordingConfig recording.Config, logger *slog.Logger) *PodService {
 	return &PodService{
 		BaseService: BaseService{Logger: logger},
 		provider:    provider,
 		store:       store,
+		lists:       lists,
+		recording:   recordingConfig,
 	}
 }
 
@@ -41,6 +53,12 @@ func (s *PodService) ListPods(c *fiber.Ctx) error {
 		return s.BadRequest(c, "missing namespace ID")
 	}
 
+	// Polling clients share recent lists instead of each reading the store
+	key := listKey(c, clusterID, "Pod", namespaceID)
+	if sendCachedList(c, s.lists, key) {
+		return nil
+	}
+
 	s.Logger.Info("Debug pods fom data store",
 		"clusterID", clusterID,
 		"namespaceID", namespaceID)
@@ -59,9 +77,10 @@ func (s *PodService) ListPods(c *fiber.Ctx) error {
 		// 	return s.Error(c, fiber.StatusInternalServerError, "failed to list pods: %v", err)
 		// }
 		// return c.JSON(directPods)
+		return c.JSON(pods)
 	}
 
-	return c.JSON(pods)
+	return sendList(c, s.lists, key, pods)
 }
 
 func (s *PodService) GetPod(c *fiber.Ctx) error {
@@ -106,7 +125,65 @@ func (s *PodService) GetPod(c *fiber.Ctx) error {
 		// return c.JSON(directPod)
 	}
 
-	return c.JSON(&pod)
+	return sendWithNotes(c, &s.BaseService, s.store, clusterID, "Pod", &pod)
+}
+
+// DeletePod deletes a pod from the cluster. With ?dryRun=true the response
+// previews the deletion instead.
+func (s *PodService) DeletePod(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or pod ID")
+	}
+
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+
+	err = s.provider.DeletePod(c.Context(), clusterID, namespaceID, podID, dryRun)
+	switch {
+	case apierrors.IsNotFound(err):
+		return s.NotFound(c, "Pod", podID)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to delete pod", err)
+	}
+
+	if dryRun {
+		pod, err := s.provider.GetPod(c.Context(), clusterID, namespaceID, podID)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to get pod", err)
+		}
+		return s.SendDryRun(c, DryRunDelete, pod, nil)
+	}
+
+	s.Logger.Info("Pod deleted", "clusterID", clusterID, "namespace", namespaceID, "pod", podID)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetRestartSummary explains the last restart of each container of a pod
+// from its termination state and probe events
+func (s *PodService) GetRestartSummary(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		return s.BadRequest(c, "missing cluster, namespace or pod ID")
+	}
+
+	summary, err := s.provider.RestartSummary(c.Context(), clusterID, namespaceID, podID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return s.NotFound(c, "Pod", podID)
+		}
+		return s.InternalServerError(c, "Failed to summarize pod restarts", err)
+	}
+
+	return c.JSON(summary)
 }
 
 // StreamPodLogs still needs to use the direct API as we can't stream logs fom data store
@@ -166,6 +243,230 @@ func (s *PodService) StreamPodLogs(c *websocket.Conn) {
 	}
 }
 
+// Attach channels are the first byte of binary messages, matching the
+// Kubernetes streaming protocol
+const (
+	attachStdin  = 0
+	attachStdout = 1
+	attachStderr = 2
+	attachResize = 4
+)
+
+// attachSession is sent as the first message of an attach stream. Recorded
+// tells the user the session is being recorded.
+type attachSession struct {
+	Container string `json:"container"`
+	TTY       bool   `json:"tty"`
+	Stdin     bool   `json:"stdin"`
+	Recorded  bool   `json:"recorded"`
+}
+
+// AttachPod connects a WebSocket to the running process of a container, like
+// kubectl attach. A TTY and stdin are only used when the client asks for a
+// TTY (?tty=true) and the container was started with them. The first message
+// is a JSON attachSession; after that, binary messages carry a channel byte:
+// clients send stdin (0) and resize (4, {"Width":80,"Height":24}), and
+// receive stdout (1) and stderr (2). Sessions are recorded when recording
+// is enabled.
+func (s *PodService) AttachPod(c *websocket.Conn) {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+
+	if clusterID == "" || namespaceID == "" || podID == "" {
+		s.sendLogError(c, "Missing required parameters")
+		return
+	}
+
+	var pod corev1.Pod
+	if err := s.store.Get(context.Background(), clusterID, namespaceID, "Pod", podID, &pod); err != nil {
+		s.sendLogError(c, fmt.Sprintf("Pod %s not found", podID))
+		return
+	}
+
+	container := attachContainer(&pod, c.Query("container"))
+	if container == nil {
+		s.sendLogError(c, "Container not found")
+		return
+	}
+
+	session := attachSession{
+		Container: container.Name,
+		TTY:       c.Query("tty") == "true" && container.TTY,
+		Stdin:     container.Stdin,
+		Recorded:  s.recording.Enabled,
+	}
+
+	var recorder *recording.Recorder
+	if s.recording.Enabled {
+		meta := recording.Recording{
+			Session:   "attach",
+			ClusterID: clusterID,
+			Namespace: namespaceID,
+			Pod:       podID,
+			Container: container.Name,
+			TTY:       session.TTY,
+		}
+		if user, ok := c.Locals("user").(auth.UserAttributes); ok {
+			meta.User = user.Username
+		}
+		if addr := c.RemoteAddr(); addr != nil {
+			meta.SourceIP = addr.String()
+		}
+
+		recorder = recording.NewRecorder(s.recording, meta)
+		defer s.saveRecording(recorder)
+	}
+
+	if err := c.WriteJSON(session); err != nil {
+		s.Logger.Error("Failed to send attach session", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	opts := podexec.Options{
+		Container: container.Name,
+		TTY:       session.TTY,
+		Stdout:    &attachWriter{conn: c, mu: &mu, channel: attachStdout, recorder: recorder},
+		Stderr:    &attachWriter{conn: c, mu: &mu, channel: attachStderr, recorder: recorder},
+	}
+
+	stdin, stdinWriter := io.Pipe()
+	if session.Stdin {
+		opts.Stdin = stdin
+	}
+	resize := make(chan podexec.Size, 1)
+	if session.TTY {
+		opts.Resize = resize
+	}
+
+	// Forward client input until the client goes away
+	go func() {
+		defer cancel()
+		defer func() {
+			_ = stdinWriter.Close()
+		}()
+
+		for {
+			messageType, message, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if messageType != websocket.BinaryMessage || len(message) < 2 {
+				continue
+			}
+
+			switch message[0] {
+			case attachStdin:
+				if session.Stdin {
+					if recorder != nil {
+						recorder.Input(message[1:])
+					}
+					if _, err := stdinWriter.Write(message[1:]); err != nil {
+						return
+					}
+				}
+			case attachResize:
+				var size podexec.Size
+				if session.TTY && json.Unmarshal(message[1:], &size) == nil {
+					if recorder != nil {
+						recorder.Resize(size.Width, size.Height)
+					}
+					select {
+					case resize <- size:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	s.Logger.Info("Attaching to container",
+		"clusterID", clusterID,
+		"namespaceID", namespaceID,
+		"podID", podID,
+		"container", container.Name,
+		"tty", session.TTY)
+
+	err := s.provider.Attach(ctx, clusterID, namespaceID, podID, opts)
+	if err != nil && ctx.Err() == nil {
+		mu.Lock()
+		defer mu.Unlock()
+		s.sendLogError(c, fmt.Sprintf("Attach failed: %v", err))
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := c.Close(); err != nil {
+		s.Logger.Debug("Failed to close websocket connection", "error", err)
+	}
+}
+
+// attachContainer returns the named container, or the pod's default
+// container when no name is given
+func attachContainer(pod *corev1.Pod, name string) *corev1.Container {
+	if name == "" {
+		name = pod.Annotations["kubectl.kubernetes.io/default-container"]
+	}
+	for i := range pod.Spec.Containers {
+		if name == "" || pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// attachWriter sends output as binary messages prefixed with its channel,
+// adding it to the session's recording if there is one
+type attachWriter struct {
+	conn     *websocket.Conn
+	mu       *sync.Mutex
+	channel  byte
+	recorder *recording.Recorder
+}
+
+func (w *attachWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.conn.WriteMessage(websocket.BinaryMessage, append([]byte{w.channel}, data...)); err != nil {
+		return 0, err
+	}
+	if w.recorder != nil {
+		w.recorder.Output(data)
+	}
+	return len(data), nil
+}
+
+// saveRecording stores the recording of a finished session
+func (s *PodService) saveRecording(recorder *recording.Recorder) {
+	rec := recorder.Finish()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.store.SaveRecording(ctx, rec); err != nil {
+		s.Logger.Error("Failed to save session recording",
+			"clusterID", rec.ClusterID,
+			"namespaceID", rec.Namespace,
+			"podID", rec.Pod,
+			"user", rec.User,
+			"error", err)
+		return
+	}
+
+	s.Logger.Info("Saved session recording",
+		"id", rec.ID,
+		"podID", rec.Pod,
+		"user", rec.User,
+		"bytes", rec.Bytes,
+		"truncated", rec.Truncated)
+}
+
 // Helper method to send errors over the websocket
 func (s *PodService) sendLogError(c *websocket.Conn, message string) {
 	if err := c.WriteJSON(map[string]string{"error": message}); err != nil {
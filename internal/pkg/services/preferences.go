@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/preferences"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+type PreferenceService struct {
+	BaseService
+	store store.Repository
+}
+
+// NewPreferenceService creates a new service for the signed-in user's preferences
+func NewPreferenceService(store store.Repository, logger *slog.Logger) *PreferenceService {
+	return &PreferenceService{
+		BaseService: BaseService{Logger: logger},
+		store:       store,
+	}
+}
+
+// GetPreferences returns the user's preferences, or the defaults if they
+// haven't saved any
+func (s *PreferenceService) GetPreferences(c *fiber.Ctx) error {
+	user, ok := preferenceUser(c)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	prefs := preferences.Defaults(user)
+	err := s.store.GetPreferences(c.Context(), user, &prefs)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return s.InternalServerError(c, "Failed to get preferences", err)
+	}
+
+	return c.JSON(prefs)
+}
+
+// SavePreferences replaces the user's preferences with the request body
+func (s *PreferenceService) SavePreferences(c *fiber.Ctx) error {
+	user, ok := preferenceUser(c)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	prefs := preferences.Defaults(user)
+	if err := c.BodyParser(&prefs); err != nil {
+		return s.BadRequest(c, "invalid preferences")
+	}
+	if err := prefs.Validate(); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	// Preferences are always saved for the caller, whatever the body says
+	prefs.User = user
+	prefs.UpdatedAt = time.Now()
+	if err := s.store.SavePreferences(c.Context(), &prefs); err != nil {
+		return s.InternalServerError(c, "Failed to save preferences", err)
+	}
+
+	return c.JSON(prefs)
+}
+
+// DeletePreferences resets the user's preferences to the defaults
+func (s *PreferenceService) DeletePreferences(c *fiber.Ctx) error {
+	user, ok := preferenceUser(c)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	if err := s.store.DeletePreferences(c.Context(), user); err != nil {
+		return s.InternalServerError(c, "Failed to delete preferences", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// preferenceUser returns the identity preferences are kept under: the user's
+// UID when the authenticator provides one, since usernames can be reused
+func preferenceUser(c *fiber.Ctx) (string, bool) {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return "", false
+	}
+	if user.UID != "" {
+		return user.UID, true
+	}
+	return user.Username, user.Username != ""
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/problems"
+)
+
+type ProblemsService struct {
+	BaseService
+	engine *problems.Engine
+}
+
+// NewProblemsService creates a new service for detected problems
+func NewProblemsService(engine *problems.Engine, logger *slog.Logger) *ProblemsService {
+	return &ProblemsService{
+		BaseService: BaseService{Logger: logger},
+		engine:      engine,
+	}
+}
+
+// ListProblems returns the open problems of a cluster and a health score,
+// optionally filtered by namespace and severity
+func (s *ProblemsService) ListProblems(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	namespace := c.Query("namespace")
+	severity := c.Query("severity")
+	if severity != "" && severity != problems.SeverityWarning && severity != problems.SeverityCritical {
+		return s.BadRequest(c, "severity must be warning or critical")
+	}
+
+	open, err := s.engine.Problems(c.Context(), clusterID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to evaluate problems", err)
+	}
+
+	filtered := make([]problems.Problem, 0, len(open))
+	for _, problem := range open {
+		if namespace != "" && problem.Namespace != namespace {
+			continue
+		}
+		if severity != "" && problem.Severity != severity {
+			continue
+		}
+		filtered = append(filtered, problem)
+	}
+
+	return c.JSON(fiber.Map{
+		"clusterID": clusterID,
+		"score":     problems.Score(filtered),
+		"problems":  filtered,
+	})
+}
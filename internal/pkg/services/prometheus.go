@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/prometheus"
+)
+
+const (
+	defaultPrometheusRange = time.Hour
+	maxPrometheusPoints    = 11000
+)
+
+// namePattern matches Kubernetes object names, which are safe inside PromQL label matchers
+var namePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+// podQueries are PromQL templates taking a namespace and a pod matcher
+var podQueries = map[string]string{
+	"cpu":              `sum by (pod, container) (rate(container_cpu_usage_seconds_total{namespace="%s",pod%s,container!=""}[5m]))`,
+	"memory":           `sum by (pod, container) (container_memory_working_set_bytes{namespace="%s",pod%s,container!=""})`,
+	"network_receive":  `sum by (pod) (rate(container_network_receive_bytes_total{namespace="%s",pod%s}[5m]))`,
+	"network_transmit": `sum by (pod) (rate(container_network_transmit_bytes_total{namespace="%s",pod%s}[5m]))`,
+}
+
+type PrometheusService struct {
+	BaseService
+	client *prometheus.Client
+}
+
+// NewPrometheusService creates a new service for historical resource graphs
+func NewPrometheusService(client *prometheus.Client, logger *slog.Logger) *PrometheusService {
+	return &PrometheusService{
+		BaseService: BaseService{Logger: logger},
+		client:      client,
+	}
+}
+
+// GetPodHistory returns a metric series for a pod
+func (s *PrometheusService) GetPodHistory(c *fiber.Ctx) error {
+	namespaceID := c.Params("namespaceID")
+	podID := c.Params("podID")
+
+	if !namePattern.MatchString(namespaceID) || !namePattern.MatchString(podID) {
+		return s.BadRequest(c, "invalid namespace or pod ID")
+	}
+
+	return s.queryWorkload(c, namespaceID, fmt.Sprintf(`="%s"`, podID))
+}
+
+// GetDeploymentHistory returns a metric series for every pod of a deployment
+func (s *PrometheusService) GetDeploymentHistory(c *fiber.Ctx) error {
+	namespaceID := c.Params("namespaceID")
+	deploymentID := c.Params("deploymentID")
+
+	if !namePattern.MatchString(namespaceID) || !namePattern.MatchString(deploymentID) {
+		return s.BadRequest(c, "invalid namespace or deployment ID")
+	}
+
+	// Deployment pods are named <deployment>-<replicaset hash>-<pod hash>
+	return s.queryWorkload(c, namespaceID, fmt.Sprintf(`=~"%s-[a-z0-9]+-[a-z0-9]+"`, regexp.QuoteMeta(deploymentID)))
+}
+
+// QueryRange proxies an arbitrary PromQL range query
+func (s *PrometheusService) QueryRange(c *fiber.Ctx) error {
+	query := c.Query("query")
+	if query == "" {
+		return s.BadRequest(c, "missing query parameter")
+	}
+
+	return s.rangeQuery(c, query)
+}
+
+// queryWorkload renders the requested metric template for a pod matcher
+func (s *PrometheusService) queryWorkload(c *fiber.Ctx, namespace, podMatcher string) error {
+	metric := c.Query("metric", "cpu")
+	template, ok := podQueries[metric]
+	if !ok {
+		return s.BadRequest(c, "metric must be one of cpu, memory, network_receive, network_transmit")
+	}
+
+	return s.rangeQuery(c, fmt.Sprintf(template, namespace, podMatcher))
+}
+
+// rangeQuery parses range/step and runs the query for the cluster in the path
+func (s *PrometheusService) rangeQuery(c *fiber.Ctx, query string) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	window := defaultPrometheusRange
+	if value := c.Query("range"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return s.BadRequest(c, fmt.Sprintf("invalid range duration: %s", value))
+		}
+		window = parsed
+	}
+
+	// Aim for ~300 points unless a step is given
+	step := window / 300
+	if value := c.Query("step"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return s.BadRequest(c, fmt.Sprintf("invalid step duration: %s", value))
+		}
+		step = parsed
+	}
+	if step < time.Second {
+		step = time.Second
+	}
+
+	if window/step > maxPrometheusPoints {
+		return s.BadRequest(c, "range and step would return too many points")
+	}
+
+	end := time.Now()
+	result, err := s.client.QueryRange(c.Context(), clusterID, prometheus.RangeQuery{
+		Query: query,
+		Start: end.Add(-window),
+		End:   end,
+		Step:  step,
+	})
+	if errors.Is(err, prometheus.ErrNotConfigured) {
+		return s.NotFound(c, "Prometheus datasource", clusterID)
+	}
+	if err != nil {
+		return s.Error(c, fiber.StatusBadGateway, "Prometheus query failed: %v", err)
+	}
+
+	return c.JSON(fiber.Map{
+		"query": query,
+		"start": end.Add(-window),
+		"end":   end,
+		"step":  step.String(),
+		"data":  result,
+	})
+}
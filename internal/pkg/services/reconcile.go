@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/reconcile"
+	"github.com/jbetancur/dashboard/internal/pkg/listcache"
+)
+
+// reconcileTimeout bounds a reconciliation, which lists whole clusters
+const reconcileTimeout = 5 * time.Minute
+
+type ReconcileService struct {
+	BaseService
+	provider *reconcile.ReconcileProvider
+	lists    *listcache.Cache
+}
+
+// NewReconcileService creates a new service repairing stored clusters
+func NewReconcileService(provider *reconcile.ReconcileProvider, lists *listcache.Cache, logger *slog.Logger) *ReconcileService {
+	return &ReconcileService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+		lists:       lists,
+	}
+}
+
+// ReconcileCluster rebuilds the stored copy of a cluster from its API server
+// and reports the fixes applied
+func (s *ReconcileService) ReconcileCluster(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	report, err := s.provider.Reconcile(ctx, clusterID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to reconcile cluster", err)
+	}
+
+	// Cached lists may predate the fixes
+	s.lists.InvalidateCluster(clusterID)
+
+	s.Logger.Info("Reconciled cluster", "clusterID", clusterID, "fixed", report.Fixed, "duration", report.Duration)
+	return c.JSON(report)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/recording"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+const (
+	defaultRecordingLimit = 50
+	maxRecordingLimit     = 1000
+)
+
+// RecordingService lets admins review recorded terminal sessions
+type RecordingService struct {
+	BaseService
+	store store.Repository
+}
+
+// NewRecordingService creates a new recording service
+func NewRecordingService(store store.Repository, logger *slog.Logger) *RecordingService {
+	return &RecordingService{
+		BaseService: BaseService{Logger: logger},
+		store:       store,
+	}
+}
+
+// ListRecordings returns recordings matching the query string filters, without their events
+func (s *RecordingService) ListRecordings(c *fiber.Ctx) error {
+	query, err := parseRecordingQuery(c)
+	if err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	recordings := make([]recording.Recording, 0)
+	if err := s.store.ListRecordings(c.Context(), query, &recordings); err != nil {
+		return s.InternalServerError(c, "Failed to list recordings", err)
+	}
+
+	return c.JSON(recordings)
+}
+
+// GetRecording returns a recording with its events
+func (s *RecordingService) GetRecording(c *fiber.Ctx) error {
+	recordingID := c.Params("recordingID")
+
+	var rec recording.Recording
+	if err := s.store.GetRecording(c.Context(), recordingID, &rec); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return s.NotFound(c, "Recording", recordingID)
+		}
+		return s.InternalServerError(c, "Failed to get recording", err)
+	}
+
+	return c.JSON(rec)
+}
+
+// GetRecordingCast downloads a recording as an asciinema v2 file for replay
+func (s *RecordingService) GetRecordingCast(c *fiber.Ctx) error {
+	recordingID := c.Params("recordingID")
+
+	var rec recording.Recording
+	if err := s.store.GetRecording(c.Context(), recordingID, &rec); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return s.NotFound(c, "Recording", recordingID)
+		}
+		return s.InternalServerError(c, "Failed to get recording", err)
+	}
+
+	c.Attachment(recordingID + ".cast")
+	c.Set(fiber.HeaderContentType, "application/x-asciicast")
+
+	if err := rec.WriteCast(c.Response().BodyWriter()); err != nil {
+		return s.InternalServerError(c, "Failed to write recording", err)
+	}
+
+	return nil
+}
+
+// parseRecordingQuery builds a recording query from the request's query string
+func parseRecordingQuery(c *fiber.Ctx) (recording.Query, error) {
+	query := recording.Query{
+		User:      c.Query("user"),
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Query("namespace"),
+		Pod:       c.Query("pod"),
+		Limit:     defaultRecordingLimit,
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return query, fmt.Errorf("invalid since parameter, expected RFC3339: %w", err)
+		}
+		query.Since = t
+	}
+
+	if until := c.Query("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return query, fmt.Errorf("invalid until parameter, expected RFC3339: %w", err)
+		}
+		query.Until = t
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		val, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || val <= 0 {
+			return query, fmt.Errorf("invalid limit parameter")
+		}
+		query.Limit = min(val, maxRecordingLimit)
+	}
+
+	return query, nil
+}
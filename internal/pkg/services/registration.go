@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"github.com/jbetancur/dashboard/internal/pkg/providers/uploaded"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+// maxKubeconfigSize bounds uploaded kubeconfig files
+const maxKubeconfigSize = 1 << 20
+
+type RegistrationService struct {
+	BaseService
+	provider       *uploaded.UploadedProvider
+	router         *providers.Router
+	clusterManager *cluster.Manager
+	store          store.Repository
+}
+
+// NewRegistrationService creates a new service for registering clusters at runtime
+func NewRegistrationService(
+	provider *uploaded.UploadedProvider,
+	router *providers.Router,
+	clusterManager *cluster.Manager,
+	store store.Repository,
+	logger *slog.Logger,
+) *RegistrationService {
+	return &RegistrationService{
+		BaseService:    BaseService{Logger: logger},
+		provider:       provider,
+		router:         router,
+		clusterManager: clusterManager,
+		store:          store,
+	}
+}
+
+// ListRegisteredClusters returns the clusters registered through the API
+func (s *RegistrationService) ListRegisteredClusters(c *fiber.Ctx) error {
+	clusters, err := s.provider.List(c.Context())
+	if err != nil {
+		return s.InternalServerError(c, "Failed to list registered clusters", err)
+	}
+
+	return c.JSON(clusters)
+}
+
+// RegisterCluster registers a cluster from a JSON body, or from a multipart
+// form with the kubeconfig as a file and id and context as fields
+func (s *RegistrationService) RegisterCluster(c *fiber.Ctx) error {
+	if !s.provider.Enabled() {
+		return s.Error(c, fiber.StatusServiceUnavailable, "%v", uploaded.ErrDisabled)
+	}
+
+	registration, err := parseRegistration(c)
+	if err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	if owner, ok := s.router.Owner(registration.ID); ok && owner != uploaded.ProviderName {
+		return s.Error(c, fiber.StatusConflict, "cluster %s is already provided by %s", registration.ID, owner)
+	}
+
+	user, _ := c.Locals("user").(auth.UserAttributes)
+
+	credentials, err := s.provider.Register(c.Context(), registration, user.Username)
+	if err != nil {
+		return s.Error(c, fiber.StatusBadRequest, "Failed to register cluster: %v", err)
+	}
+
+	s.router.SetOwner(credentials.ID, uploaded.ProviderName)
+
+	// Drop an existing connection so replaced credentials take effect
+	if err := s.clusterManager.StopCluster(credentials.ID); err == nil {
+		s.Logger.Info("Reconnecting cluster with new credentials", "clusterID", credentials.ID)
+	}
+
+	if err := s.clusterManager.Register(credentials.ID, credentials.APIURL); err != nil {
+		return s.InternalServerError(c, "Failed to register cluster", err)
+	}
+
+	clusterInfo := cluster.ClusterInfo{
+		Kind:     "Cluster",
+		Name:     credentials.ID,
+		APIURL:   credentials.APIURL,
+		Provider: uploaded.ProviderName,
+	}
+	if err := s.store.SaveCluster(c.Context(), &clusterInfo); err != nil {
+		return s.InternalServerError(c, "Failed to store cluster", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(credentials)
+}
+
+// UnregisterCluster disconnects a cluster registered through the API and removes its credentials
+func (s *RegistrationService) UnregisterCluster(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+
+	if owner, ok := s.router.Owner(clusterID); ok && owner != uploaded.ProviderName {
+		return s.Error(c, fiber.StatusConflict, "cluster %s is provided by %s and cannot be unregistered", clusterID, owner)
+	}
+
+	if err := s.provider.Unregister(c.Context(), clusterID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return s.NotFound(c, "registered cluster", clusterID)
+		}
+		return s.InternalServerError(c, "Failed to remove cluster credentials", err)
+	}
+
+	if err := s.clusterManager.StopCluster(clusterID); err != nil {
+		s.Logger.Debug("Cluster was not connected", "clusterID", clusterID, "error", err)
+	}
+	s.router.RemoveOwner(clusterID)
+
+	if err := s.store.DeleteCluster(c.Context(), clusterID); err != nil {
+		return s.InternalServerError(c, "Failed to remove cluster", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// parseRegistration reads a registration from a JSON body or a multipart upload
+func parseRegistration(c *fiber.Ctx) (uploaded.Registration, error) {
+	var registration uploaded.Registration
+
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		if err := c.BodyParser(&registration); err != nil {
+			return registration, errors.New("invalid registration")
+		}
+		return registration, nil
+	}
+
+	registration.ID = c.FormValue("id")
+	registration.Context = c.FormValue("context")
+
+	header, err := c.FormFile("kubeconfig")
+	if err != nil {
+		return registration, errors.New("missing kubeconfig file")
+	}
+	if header.Size > maxKubeconfigSize {
+		return registration, errors.New("kubeconfig file is too large")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return registration, errors.New("failed to read kubeconfig file")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxKubeconfigSize))
+	if err != nil {
+		return registration, errors.New("failed to read kubeconfig file")
+	}
+	registration.Kubeconfig = string(data)
+
+	return registration, nil
+}
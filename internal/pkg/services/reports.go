@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/reports"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+type ReportService struct {
+	BaseService
+	scheduler *reports.Scheduler
+	store     store.Repository
+}
+
+// NewReportService creates a new service for managing scheduled reports
+func NewReportService(scheduler *reports.Scheduler, store store.Repository, logger *slog.Logger) *ReportService {
+	return &ReportService{
+		BaseService: BaseService{Logger: logger},
+		scheduler:   scheduler,
+		store:       store,
+	}
+}
+
+// ListReports returns scheduled reports, optionally only those of a team
+func (s *ReportService) ListReports(c *fiber.Ctx) error {
+	var list []reports.Report
+	if err := s.store.ListReports(c.Context(), &list); err != nil {
+		return s.InternalServerError(c, "Failed to list reports", err)
+	}
+
+	if team := c.Query("team"); team != "" {
+		filtered := make([]reports.Report, 0, len(list))
+		for _, report := range list {
+			if report.Team == team {
+				filtered = append(filtered, report)
+			}
+		}
+		list = filtered
+	}
+
+	return c.JSON(list)
+}
+
+// GetReport returns a scheduled report
+func (s *ReportService) GetReport(c *fiber.Ctx) error {
+	reportID := c.Params("reportID")
+
+	var report reports.Report
+	if err := s.store.GetReport(c.Context(), reportID, &report); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return s.NotFound(c, "report", reportID)
+		}
+		return s.InternalServerError(c, "Failed to get report", err)
+	}
+
+	return c.JSON(report)
+}
+
+// SaveReport creates a report, or replaces the one named by the reportID
+// parameter. Saved reports are first sent at their next scheduled time.
+func (s *ReportService) SaveReport(c *fiber.Ctx) error {
+	var report reports.Report
+	if err := c.BodyParser(&report); err != nil {
+		return s.BadRequest(c, "invalid report")
+	}
+
+	report.ID = c.Params("reportID")
+	if err := report.Validate(); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	report.LastRunAt = time.Now().UTC()
+	report.LastError = ""
+
+	if err := s.store.SaveReport(c.Context(), &report); err != nil {
+		return s.InternalServerError(c, "Failed to save report", err)
+	}
+
+	return c.JSON(report)
+}
+
+// DeleteReport removes a scheduled report
+func (s *ReportService) DeleteReport(c *fiber.Ctx) error {
+	reportID := c.Params("reportID")
+	if err := s.store.DeleteReport(c.Context(), reportID); err != nil {
+		return s.NotFound(c, "report", reportID)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// PreviewReport renders a report without delivering it. The report in the
+// request body is previewed, so templates can be tried before saving.
+func (s *ReportService) PreviewReport(c *fiber.Ctx) error {
+	var report reports.Report
+	if err := c.BodyParser(&report); err != nil {
+		return s.BadRequest(c, "invalid report")
+	}
+	if report.Name == "" {
+		report.Name = "preview"
+	}
+
+	payload, err := s.scheduler.Preview(c.Context(), &report)
+	if err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	return c.JSON(payload)
+}
+
+// RunReport sends a scheduled report now
+func (s *ReportService) RunReport(c *fiber.Ctx) error {
+	reportID := c.Params("reportID")
+
+	var report reports.Report
+	if err := s.store.GetReport(c.Context(), reportID, &report); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return s.NotFound(c, "report", reportID)
+		}
+		return s.InternalServerError(c, "Failed to get report", err)
+	}
+
+	if err := s.scheduler.Run(c.Context(), &report); err != nil {
+		return s.Error(c, fiber.StatusBadGateway, "Failed to send report: %v", err)
+	}
+
+	return c.JSON(report)
+}
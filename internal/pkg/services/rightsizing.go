@@ -0,0 +1,59 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/rightsizing"
+)
+
+type RightsizingService struct {
+	BaseService
+	provider *rightsizing.RightsizingProvider
+}
+
+// NewRightsizingService creates a new service for resource recommendations
+func NewRightsizingService(provider *rightsizing.RightsizingProvider, logger *slog.Logger) *RightsizingService {
+	return &RightsizingService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// ListRecommendations returns right-sizing recommendations for the containers
+// of a namespace, optionally filtered by CPU or memory status
+func (s *RightsizingService) ListRecommendations(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", rightsizing.StatusOK, rightsizing.StatusOverProvisioned,
+		rightsizing.StatusUnderProvisioned, rightsizing.StatusNoRequests:
+	default:
+		return s.BadRequest(c, "unsupported status filter")
+	}
+
+	recommendations, err := s.provider.Recommend(c.Context(), clusterID, namespaceID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to compute recommendations", err)
+	}
+
+	if status != "" {
+		filtered := make([]rightsizing.Recommendation, 0, len(recommendations))
+		for _, rec := range recommendations {
+			if rec.CPU.Status == status || rec.Memory.Status == status {
+				filtered = append(filtered, rec)
+			}
+		}
+		recommendations = filtered
+	}
+
+	return c.JSON(fiber.Map{
+		"policy":          s.provider.Policy(),
+		"recommendations": recommendations,
+	})
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/searches"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type SearchService struct {
+	BaseService
+	store      store.Repository
+	authorizer auth.Authorizer
+}
+
+// NewSearchService creates a new service for saved fleet searches
+func NewSearchService(store store.Repository, authorizer auth.Authorizer, logger *slog.Logger) *SearchService {
+	return &SearchService{
+		BaseService: BaseService{Logger: logger},
+		store:       store,
+		authorizer:  authorizer,
+	}
+}
+
+// ListSearches returns the user's searches and those shared with everyone
+func (s *SearchService) ListSearches(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	list := make([]searches.Search, 0)
+	if err := s.store.ListSearches(c.Context(), user.Username, &list); err != nil {
+		return s.InternalServerError(c, "Failed to list searches", err)
+	}
+
+	return c.JSON(list)
+}
+
+// GetSearch returns a search the user owns or that is shared
+func (s *SearchService) GetSearch(c *fiber.Ctx) error {
+	search, err := s.search(c)
+	if err != nil {
+		return s.searchError(c, err)
+	}
+
+	return c.JSON(search)
+}
+
+// SaveSearch creates a search owned by the user, or replaces the one named
+// by the searchID parameter if the user owns it
+func (s *SearchService) SaveSearch(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	var search searches.Search
+	if err := c.BodyParser(&search); err != nil {
+		return s.BadRequest(c, "invalid search")
+	}
+	if err := search.Validate(); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	search.ID = c.Params("searchID")
+	search.Owner = user.Username
+	search.CreatedAt = time.Now()
+	search.UpdatedAt = search.CreatedAt
+
+	if search.ID != "" {
+		existing, err := s.search(c)
+		if err != nil {
+			return s.searchError(c, err)
+		}
+		if existing.Owner != user.Username {
+			return s.Error(c, fiber.StatusForbidden, "Only the owner can change search %s", search.ID)
+		}
+		search.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.store.SaveSearch(c.Context(), &search); err != nil {
+		return s.InternalServerError(c, "Failed to save search", err)
+	}
+
+	if c.Params("searchID") == "" {
+		return c.Status(fiber.StatusCreated).JSON(search)
+	}
+	return c.JSON(search)
+}
+
+// DeleteSearch removes a search the user owns. Admins can remove any search.
+func (s *SearchService) DeleteSearch(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	search, err := s.search(c)
+	if err != nil {
+		return s.searchError(c, err)
+	}
+	if search.Owner != user.Username && !slices.Contains(user.Groups, auth.AdminGroup) {
+		return s.Error(c, fiber.StatusForbidden, "Only the owner can delete search %s", search.ID)
+	}
+
+	if err := s.store.DeleteSearch(c.Context(), search.ID); err != nil {
+		return s.searchError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RunSearch runs a search and returns what it finds in the clusters the
+// user may list the kind in
+func (s *SearchService) RunSearch(c *fiber.Ctx) error {
+	search, err := s.search(c)
+	if err != nil {
+		return s.searchError(c, err)
+	}
+
+	query, err := search.Query()
+	if err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	switch search.Kind {
+	case "Pod":
+		return runFleet[corev1.Pod](c, &s.BaseService, s.store, s.authorizer, query, "pods")
+	case "ConfigMap":
+		return runFleet[corev1.ConfigMap](c, &s.BaseService, s.store, s.authorizer, query, "configmaps")
+	case "Namespace":
+		return runFleet[corev1.Namespace](c, &s.BaseService, s.store, s.authorizer, query, "namespaces")
+	default:
+		return s.BadRequest(c, "search has unsupported kind "+search.Kind)
+	}
+}
+
+// search loads the search named by the searchID parameter. Private searches
+// of other users are reported as not found.
+func (s *SearchService) search(c *fiber.Ctx) (*searches.Search, error) {
+	var search searches.Search
+	if err := s.store.GetSearch(c.Context(), c.Params("searchID"), &search); err != nil {
+		return nil, err
+	}
+
+	user, _ := c.Locals("user").(auth.UserAttributes)
+	if !search.VisibleTo(user.Username) {
+		return nil, store.ErrNotFound
+	}
+
+	return &search, nil
+}
+
+// searchError responds to a failure to load a search
+func (s *SearchService) searchError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, store.ErrNotFound) {
+		return s.NotFound(c, "search", c.Params("searchID"))
+	}
+	return s.InternalServerError(c, "Failed to get search", err)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/backup"
+	"github.com/jbetancur/dashboard/internal/pkg/auth"
+	"github.com/jbetancur/dashboard/internal/pkg/snapshot"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+type SnapshotService struct {
+	BaseService
+	provider   *backup.BackupProvider
+	store      store.Repository
+	authorizer auth.Authorizer
+}
+
+// NewSnapshotService creates a new service for namespace snapshots
+func NewSnapshotService(provider *backup.BackupProvider, store store.Repository, authorizer auth.Authorizer, logger *slog.Logger) *SnapshotService {
+	return &SnapshotService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+		store:       store,
+		authorizer:  authorizer,
+	}
+}
+
+// CreateSnapshot archives the stored manifests of a namespace as a new version
+func (s *SnapshotService) CreateSnapshot(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	user, _ := c.Locals("user").(auth.UserAttributes)
+
+	snap, err := s.provider.Snapshot(c.Context(), clusterID, namespaceID, user.Username)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to snapshot namespace", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(snap)
+}
+
+// ListSnapshots returns the snapshots of a namespace, newest first
+func (s *SnapshotService) ListSnapshots(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	snapshots := make([]snapshot.Snapshot, 0)
+	if err := s.store.ListSnapshots(c.Context(), clusterID, namespaceID, &snapshots); err != nil {
+		return s.InternalServerError(c, "Failed to list snapshots", err)
+	}
+
+	return c.JSON(snapshots)
+}
+
+// GetSnapshot returns a snapshot of the namespace with its manifests
+func (s *SnapshotService) GetSnapshot(c *fiber.Ctx) error {
+	snapshotID := c.Params("snapshotID")
+
+	snap, err := s.provider.GetSnapshot(c.Context(), snapshotID)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return s.NotFound(c, "snapshot", snapshotID)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to get snapshot", err)
+	}
+
+	// Snapshots are only served under the namespace they were taken from
+	if snap.ClusterID != c.Params("clusterID") || snap.Namespace != c.Params("namespaceID") {
+		return s.NotFound(c, "snapshot", snapshotID)
+	}
+
+	return c.JSON(snap)
+}
+
+// RestoreRequest selects the snapshot to apply to the namespace in the path
+type RestoreRequest struct {
+	SnapshotID string `json:"snapshotID"`
+	DryRun     bool   `json:"dryRun"`
+}
+
+// RestoreSnapshot applies a snapshot, possibly taken from another cluster or
+// namespace, to the namespace in the path. The user must be able to read
+// config maps and pods where the snapshot was taken, and is checked for
+// every object the restore creates or updates, including the namespace.
+func (s *SnapshotService) RestoreSnapshot(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	var req RestoreRequest
+	if err := c.BodyParser(&req); err != nil || req.SnapshotID == "" {
+		return s.BadRequest(c, "request must name a snapshotID")
+	}
+
+	// Like every write endpoint, a restore can be previewed with ?dryRun=true
+	dryRun, err := dryRunQuery(c)
+	if err != nil {
+		return s.BadRequest(c, "dryRun must be true or false")
+	}
+	req.DryRun = req.DryRun || dryRun
+
+	snap, err := s.provider.GetSnapshot(c.Context(), req.SnapshotID)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return s.NotFound(c, "snapshot", req.SnapshotID)
+	case err != nil:
+		return s.InternalServerError(c, "Failed to get snapshot", err)
+	}
+
+	user, ok := c.Locals("user").(auth.UserAttributes)
+	if !ok {
+		return s.Error(c, fiber.StatusUnauthorized, "User information not available")
+	}
+
+	// Snapshots hold config map and pod manifests, env values included
+	for _, resource := range []string{"configmaps", "pods"} {
+		allowed, err := s.authorizer.CanAccess(c.Context(), snap.ClusterID, user, resource, snap.Namespace, "", "list")
+		if err != nil {
+			return s.InternalServerError(c, "Failed to verify permissions", err)
+		}
+		if !allowed {
+			return s.Error(c, fiber.StatusForbidden, "You don't have permission to read the source of this snapshot")
+		}
+	}
+
+	result, err := s.provider.Restore(c.Context(), snap, clusterID, namespaceID, req.DryRun,
+		func(ctx context.Context, resource, namespace, name, verb string) (bool, error) {
+			return s.authorizer.CanAccess(ctx, clusterID, user, resource, namespace, name, verb)
+		})
+	if err != nil {
+		return s.InternalServerError(c, "Failed to restore snapshot", err)
+	}
+
+	return c.JSON(result)
+}
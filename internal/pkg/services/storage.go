@@ -0,0 +1,68 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/storage"
+)
+
+type StorageService struct {
+	BaseService
+	provider *storage.StorageProvider
+}
+
+// NewStorageService creates a new service for storage classes and CSI drivers
+func NewStorageService(provider *storage.StorageProvider, logger *slog.Logger) *StorageService {
+	return &StorageService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetInventory returns the storage classes and CSI drivers of a cluster
+func (s *StorageService) GetInventory(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	if clusterID == "" {
+		return s.BadRequest(c, "missing cluster ID")
+	}
+
+	result, err := s.provider.Inventory(c.Context(), clusterID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to list storage classes", err)
+	}
+
+	return c.JSON(result)
+}
+
+// GetStorageClass returns a storage class
+func (s *StorageService) GetStorageClass(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	name := c.Params("storageClassID")
+	if clusterID == "" || name == "" {
+		return s.BadRequest(c, "missing cluster ID or storage class")
+	}
+
+	class, err := s.provider.GetStorageClass(c.Context(), clusterID, name)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to get storage class", err)
+	}
+
+	return s.SendObject(c, class)
+}
+
+// GetNamespaceStorage returns the claims of a namespace with their storage classes and warnings
+func (s *StorageService) GetNamespaceStorage(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	result, err := s.provider.NamespaceStorage(c.Context(), clusterID, namespaceID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to list namespace storage", err)
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	assets "github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/timeline"
+)
+
+type TimelineService struct {
+	BaseService
+	provider *timeline.TimelineProvider
+}
+
+// NewTimelineService creates a new service for resource timelines
+func NewTimelineService(provider *timeline.TimelineProvider, logger *slog.Logger) *TimelineService {
+	return &TimelineService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetTimeline returns a handler serving the timeline of an object of the given kind
+func (s *TimelineService) GetTimeline(kind assets.KindInfo) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clusterID := c.Params("clusterID")
+		namespaceID := c.Params("namespaceID")
+		name := c.Params("name")
+
+		if clusterID == "" || namespaceID == "" || name == "" {
+			return s.BadRequest(c, "missing cluster, namespace or name")
+		}
+
+		result, err := s.provider.Build(c.Context(), clusterID, namespaceID, kind.Kind, name)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to build timeline", err)
+		}
+
+		return c.JSON(result)
+	}
+}
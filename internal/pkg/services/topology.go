@@ -0,0 +1,58 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/topology"
+)
+
+type TopologyService struct {
+	BaseService
+	provider *topology.TopologyProvider
+}
+
+// NewTopologyService creates a new service for namespace topologies
+func NewTopologyService(provider *topology.TopologyProvider, logger *slog.Logger) *TopologyService {
+	return &TopologyService{
+		BaseService: BaseService{Logger: logger},
+		provider:    provider,
+	}
+}
+
+// GetTopology returns the Services, Ingresses, workloads and their
+// ConfigMap and Secret references of a namespace as a nodes/edges graph
+func (s *TopologyService) GetTopology(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	result, err := s.provider.Build(c.Context(), clusterID, namespaceID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to build topology", err)
+	}
+
+	return c.JSON(result)
+}
+
+// GetConsumers returns a handler listing the workloads that use a ConfigMap
+// or Secret of the given kind, to show what an edit would affect
+func (s *TopologyService) GetConsumers(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clusterID := c.Params("clusterID")
+		namespaceID := c.Params("namespaceID")
+		name := c.Params("name")
+		if clusterID == "" || namespaceID == "" || name == "" {
+			return s.BadRequest(c, "missing cluster, namespace or name")
+		}
+
+		result, err := s.provider.Consumers(c.Context(), clusterID, namespaceID, kind, name)
+		if err != nil {
+			return s.InternalServerError(c, "Failed to find consumers", err)
+		}
+
+		return c.JSON(result)
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/vulnerabilities"
+)
+
+type VulnerabilityService struct {
+	BaseService
+	scheduler *vulnerabilities.Scheduler
+}
+
+// NewVulnerabilityService creates a new service for image vulnerability reports
+func NewVulnerabilityService(scheduler *vulnerabilities.Scheduler, logger *slog.Logger) *VulnerabilityService {
+	return &VulnerabilityService{
+		BaseService: BaseService{Logger: logger},
+		scheduler:   scheduler,
+	}
+}
+
+// GetReport returns the cached vulnerabilities of the images running in a namespace
+func (s *VulnerabilityService) GetReport(c *fiber.Ctx) error {
+	clusterID := c.Params("clusterID")
+	namespaceID := c.Params("namespaceID")
+	if clusterID == "" || namespaceID == "" {
+		return s.BadRequest(c, "missing cluster or namespace ID")
+	}
+
+	result, err := s.scheduler.Report(c.Context(), clusterID, namespaceID)
+	if err != nil {
+		return s.InternalServerError(c, "Failed to build vulnerability report", err)
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+)
+
+type WatchService struct {
+	BaseService
+	store store.Repository
+}
+
+// NewWatchService creates a new service streaming changes to stored resources
+func NewWatchService(store store.Repository, logger *slog.Logger) *WatchService {
+	return &WatchService{
+		BaseService: BaseService{Logger: logger},
+		store:       store,
+	}
+}
+
+// Watch returns a WebSocket handler that sends a JSON message for every
+// stored object of the kind that is created, updated or deleted, in the
+// path's namespace or in all namespaces without one. The stream ends when
+// the client disconnects or the store's change stream closes.
+func (s *WatchService) Watch(kind string) func(*websocket.Conn) {
+	return func(c *websocket.Conn) {
+		clusterID := c.Params("clusterID")
+		namespaceID := c.Params("namespaceID")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Clients only read, so a failed read means they went away
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := c.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		changes, err := s.store.Watch(ctx, clusterID, namespaceID, kind)
+		if err != nil {
+			s.Logger.Error("Failed to watch resources", "clusterID", clusterID, "namespace", namespaceID, "kind", kind, "error", err)
+			s.sendWatchError(c, "Failed to watch resources")
+			return
+		}
+
+		s.Logger.Debug("Watching resources", "clusterID", clusterID, "namespace", namespaceID, "kind", kind)
+
+		for change := range changes {
+			if err := c.WriteJSON(change); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *WatchService) sendWatchError(c *websocket.Conn, message string) {
+	if err := c.WriteJSON(map[string]string{"error": message}); err != nil {
+		s.Logger.Error("Failed to send error message over websocket", "error", err)
+	}
+
+	time.Sleep(100 * time.Millisecond) // Give time for the message to be sent
+
+	if err := c.Close(); err != nil {
+		s.Logger.Error("Failed to close websocket connection", "error", err)
+	}
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jbetancur/dashboard/internal/pkg/store"
+	"github.com/jbetancur/dashboard/internal/pkg/webhooks"
+)
+
+// defaultDeliveryLimit is how many deliveries are listed when no limit is given
+const defaultDeliveryLimit = 100
+
+// WebhookResponse is a webhook as returned by the API, without its secret
+type WebhookResponse struct {
+	webhooks.Webhook
+	HasSecret bool `json:"hasSecret"`
+}
+
+type WebhookService struct {
+	BaseService
+	dispatcher *webhooks.Dispatcher
+	store      store.Repository
+}
+
+// NewWebhookService creates a new service for managing outbound webhooks
+func NewWebhookService(dispatcher *webhooks.Dispatcher, store store.Repository, logger *slog.Logger) *WebhookService {
+	return &WebhookService{
+		BaseService: BaseService{Logger: logger},
+		dispatcher:  dispatcher,
+		store:       store,
+	}
+}
+
+// ListEventTypes returns the event types webhooks can subscribe to
+func (s *WebhookService) ListEventTypes(c *fiber.Ctx) error {
+	return c.JSON(webhooks.EventTypes)
+}
+
+// ListWebhooks returns all webhooks
+func (s *WebhookService) ListWebhooks(c *fiber.Ctx) error {
+	var list []webhooks.Webhook
+	if err := s.store.ListWebhooks(c.Context(), &list); err != nil {
+		return s.InternalServerError(c, "Failed to list webhooks", err)
+	}
+
+	responses := make([]WebhookResponse, 0, len(list))
+	for _, webhook := range list {
+		responses = append(responses, webhookResponse(webhook))
+	}
+
+	return c.JSON(responses)
+}
+
+// GetWebhook returns a webhook
+func (s *WebhookService) GetWebhook(c *fiber.Ctx) error {
+	webhook, err := s.webhook(c)
+	if err != nil {
+		return s.webhookError(c, err)
+	}
+
+	return c.JSON(webhookResponse(*webhook))
+}
+
+// SaveWebhook creates a webhook, or replaces the one named by the webhookID
+// parameter. Replacing a webhook without a secret keeps its current one.
+func (s *WebhookService) SaveWebhook(c *fiber.Ctx) error {
+	var webhook webhooks.Webhook
+	if err := c.BodyParser(&webhook); err != nil {
+		return s.BadRequest(c, "invalid webhook")
+	}
+
+	webhook.ID = c.Params("webhookID")
+	if err := webhook.Validate(); err != nil {
+		return s.BadRequest(c, err.Error())
+	}
+
+	if webhook.ID != "" && webhook.Secret == "" {
+		var existing webhooks.Webhook
+		if err := s.store.GetWebhook(c.Context(), webhook.ID, &existing); err == nil {
+			webhook.Secret = existing.Secret
+		}
+	}
+
+	if err := s.store.SaveWebhook(c.Context(), &webhook); err != nil {
+		return s.InternalServerError(c, "Failed to save webhook", err)
+	}
+	s.reload(c)
+
+	return c.JSON(webhookResponse(webhook))
+}
+
+// DeleteWebhook removes a webhook and its delivery history
+func (s *WebhookService) DeleteWebhook(c *fiber.Ctx) error {
+	webhookID := c.Params("webhookID")
+	if err := s.store.DeleteWebhook(c.Context(), webhookID); err != nil {
+		return s.NotFound(c, "webhook", webhookID)
+	}
+	s.reload(c)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// TestWebhook posts a signed ping event to a webhook
+func (s *WebhookService) TestWebhook(c *fiber.Ctx) error {
+	webhook, err := s.webhook(c)
+	if err != nil {
+		return s.webhookError(c, err)
+	}
+
+	if err := s.dispatcher.Test(c.Context(), *webhook); err != nil {
+		return s.Error(c, fiber.StatusBadGateway, "Failed to deliver test event: %v", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListDeliveries returns the recent deliveries of a webhook, optionally
+// filtered by status, newest first
+func (s *WebhookService) ListDeliveries(c *fiber.Ctx) error {
+	webhookID := c.Params("webhookID")
+
+	status := c.Query("status")
+	switch status {
+	case "", webhooks.StatusPending, webhooks.StatusDelivered, webhooks.StatusFailed:
+	default:
+		return s.BadRequest(c, "status must be pending, delivered or failed")
+	}
+
+	limit := int64(defaultDeliveryLimit)
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 1 {
+			return s.BadRequest(c, "limit must be a positive integer")
+		}
+		limit = parsed
+	}
+
+	var deliveries []webhooks.Delivery
+	if err := s.store.ListWebhookDeliveries(c.Context(), webhookID, status, limit, &deliveries); err != nil {
+		return s.InternalServerError(c, "Failed to list webhook deliveries", err)
+	}
+
+	return c.JSON(deliveries)
+}
+
+// Redeliver sends a delivery again and returns its updated record
+func (s *WebhookService) Redeliver(c *fiber.Ctx) error {
+	webhookID := c.Params("webhookID")
+	deliveryID := c.Params("deliveryID")
+
+	var delivery webhooks.Delivery
+	err := s.store.GetWebhookDelivery(c.Context(), deliveryID, &delivery)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && delivery.WebhookID != webhookID) {
+		return s.NotFound(c, "webhook delivery", deliveryID)
+	}
+	if err != nil {
+		return s.InternalServerError(c, "Failed to get webhook delivery", err)
+	}
+
+	if err := s.dispatcher.Redeliver(c.Context(), &delivery); err != nil {
+		s.Logger.Warn("Webhook redelivery failed", "delivery", deliveryID, "error", err)
+	}
+
+	return c.JSON(delivery)
+}
+
+// webhook loads the webhook named by the webhookID parameter
+func (s *WebhookService) webhook(c *fiber.Ctx) (*webhooks.Webhook, error) {
+	var webhook webhooks.Webhook
+	if err := s.store.GetWebhook(c.Context(), c.Params("webhookID"), &webhook); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// webhookError responds to a failure to load a webhook
+func (s *WebhookService) webhookError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, store.ErrNotFound) {
+		return s.NotFound(c, "webhook", c.Params("webhookID"))
+	}
+	return s.InternalServerError(c, "Failed to get webhook", err)
+}
+
+// reload makes the dispatcher pick up a webhook change right away
+func (s *WebhookService) reload(c *fiber.Ctx) {
+	if err := s.dispatcher.Reload(c.Context()); err != nil {
+		s.Logger.Warn("Failed to reload webhooks", "error", err)
+	}
+}
+
+// webhookResponse hides the secret of a webhook
+func webhookResponse(webhook webhooks.Webhook) WebhookResponse {
+	response := WebhookResponse{Webhook: webhook, HasSecret: webhook.Secret != ""}
+	response.Secret = ""
+	return response
+}
@@ -0,0 +1,32 @@
+package snapshot
+
+import "time"
+
+// Restore actions
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+// Manifest is one object of a snapshot. Content is the object as JSON with
+// status and cluster-assigned fields removed.
+type Manifest struct {
+	Kind    string `json:"kind" bson:"kind"`
+	Name    string `json:"name" bson:"name"`
+	Content string `json:"content" bson:"content"`
+}
+
+// Snapshot is a versioned archive of the manifests of a namespace
+type Snapshot struct {
+	ID        string     `json:"id" bson:"_id,omitempty"`
+	ClusterID string     `json:"clusterID" bson:"cluster_id"`
+	Namespace string     `json:"namespace" bson:"namespace"`
+	Version   int        `json:"version" bson:"version"`
+	CreatedAt time.Time  `json:"createdAt" bson:"created_at"`
+	CreatedBy string     `json:"createdBy,omitempty" bson:"created_by,omitempty"`
+	Manifests []Manifest `json:"manifests,omitempty" bson:"manifests,omitempty"`
+	// ManifestsKey locates the manifests in the object store when they are
+	// kept there instead of in the snapshot document
+	ManifestsKey string `json:"-" bson:"manifests_key,omitempty"`
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultCoalesceWindow is how long changes are held to merge rapid updates
+// of the same object
+const DefaultCoalesceWindow = 250 * time.Millisecond
+
+// WatchConfig controls live watches
+type WatchConfig struct {
+	// CoalesceWindow holds changes this long, sending only the last one per
+	// object. Zero uses the default; negative sends every change.
+	CoalesceWindow time.Duration `yaml:"coalesceWindow"`
+}
+
+// Window returns the configured coalescing window, filling in the default
+func (c WatchConfig) Window() time.Duration {
+	if c.CoalesceWindow == 0 {
+		return DefaultCoalesceWindow
+	}
+	return max(c.CoalesceWindow, 0)
+}
+
+// changeKey identifies an object. Stored objects are keyed by name, so a
+// name stands in for the object's UID: a deleted and recreated object
+// collapses into its latest state.
+type changeKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// Coalesce merges rapid changes to the same object. Once a change arrives,
+// changes are collected for the window and then sent in the order objects
+// first changed, each with only its latest operation. A window of zero or
+// less passes changes through unchanged. The returned channel closes when
+// changes closes or ctx is done.
+func Coalesce(ctx context.Context, changes <-chan Change, window time.Duration) <-chan Change {
+	if window <= 0 {
+		return changes
+	}
+
+	out := make(chan Change)
+	go func() {
+		defer close(out)
+
+		var (
+			order   []changeKey
+			latest  = make(map[changeKey]Change)
+			timer   *time.Timer
+			flushCh <-chan time.Time
+		)
+
+		flush := func() bool {
+			for _, key := range order {
+				select {
+				case out <- latest[key]:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			order = order[:0]
+			clear(latest)
+			flushCh = nil
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change, ok := <-changes:
+				if !ok {
+					flush()
+					return
+				}
+
+				key := changeKey{kind: change.Kind, namespace: change.Namespace, name: change.Name}
+				if _, pending := latest[key]; !pending {
+					order = append(order, key)
+				}
+				latest[key] = change
+
+				if flushCh == nil {
+					if timer == nil {
+						timer = time.NewTimer(window)
+						defer timer.Stop()
+					} else {
+						timer.Reset(window)
+					}
+					flushCh = timer.C
+				}
+			case <-flushCh:
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
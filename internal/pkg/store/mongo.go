@@ -5,11 +5,27 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/jbetancur/dashboard/internal/pkg/alerting"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/metrics"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/vulnerabilities"
+	"github.com/jbetancur/dashboard/internal/pkg/audit"
 	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/features"
+	"github.com/jbetancur/dashboard/internal/pkg/notes"
+	"github.com/jbetancur/dashboard/internal/pkg/preferences"
+	"github.com/jbetancur/dashboard/internal/pkg/providers/uploaded"
+	"github.com/jbetancur/dashboard/internal/pkg/recording"
+	"github.com/jbetancur/dashboard/internal/pkg/reports"
+	"github.com/jbetancur/dashboard/internal/pkg/revision"
+	"github.com/jbetancur/dashboard/internal/pkg/searches"
+	"github.com/jbetancur/dashboard/internal/pkg/snapshot"
+	"github.com/jbetancur/dashboard/internal/pkg/webhooks"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -17,12 +33,31 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// nodeNameField is where a stored pod's spec.nodeName ends up. Resources are
+// stored with the default BSON codec, which lowercases field names.
+const nodeNameField = "resource.spec.nodename"
+
 // Store is a simplified MongoDB client for storing Kubernetes resources
 type Store struct {
-	client            *mongo.Client
-	clusterCollection *mongo.Collection
-	assetCollection   *mongo.Collection
-	logger            *slog.Logger
+	client                *mongo.Client
+	clusterCollection     *mongo.Collection
+	assetCollection       *mongo.Collection
+	auditCollection       *mongo.Collection
+	metricsCollection     *mongo.Collection
+	alertsCollection      *mongo.Collection
+	snapshotsCollection   *mongo.Collection
+	scansCollection       *mongo.Collection
+	credsCollection       *mongo.Collection
+	revisionsCollection   *mongo.Collection
+	recordingsCollection  *mongo.Collection
+	featuresCollection    *mongo.Collection
+	reportsCollection     *mongo.Collection
+	webhooksCollection    *mongo.Collection
+	deliveriesCollection  *mongo.Collection
+	preferencesCollection *mongo.Collection
+	searchesCollection    *mongo.Collection
+	notesCollection       *mongo.Collection
+	logger                *slog.Logger
 }
 
 // ResourceMetadata contains common Kubernetes resource metadata
@@ -50,6 +85,21 @@ func NewStore(ctx context.Context, uri, database string, logger *slog.Logger) (R
 	// Create the collections
 	clusterCollection := client.Database(database).Collection("clusters")
 	assetCollection := client.Database(database).Collection("assets")
+	auditCollection := client.Database(database).Collection("audit")
+	metricsCollection := client.Database(database).Collection("metrics")
+	alertsCollection := client.Database(database).Collection("alerts")
+	snapshotsCollection := client.Database(database).Collection("snapshots")
+	scansCollection := client.Database(database).Collection("vulnerabilities")
+	credsCollection := client.Database(database).Collection("credentials")
+	revisionsCollection := client.Database(database).Collection("revisions")
+	recordingsCollection := client.Database(database).Collection("recordings")
+	featuresCollection := client.Database(database).Collection("feature_flags")
+	reportsCollection := client.Database(database).Collection("reports")
+	webhooksCollection := client.Database(database).Collection("webhooks")
+	deliveriesCollection := client.Database(database).Collection("webhook_deliveries")
+	preferencesCollection := client.Database(database).Collection("preferences")
+	searchesCollection := client.Database(database).Collection("searches")
+	notesCollection := client.Database(database).Collection("notes")
 
 	// Drop any existing problematic indexes to avoid conflicts
 	_, err = assetCollection.Indexes().DropAll(ctx)
@@ -73,16 +123,126 @@ func NewStore(ctx context.Context, uri, database string, logger *slog.Logger) (R
 			Keys:    bson.D{{Key: "uid", Value: 1}},
 			Options: options.Index(), // No uniqueness constraint on UID
 		},
+		{
+			// Pods by the node they are scheduled on
+			Keys: bson.D{
+				{Key: "cluster_id", Value: 1},
+				{Key: nodeNameField, Value: 1},
+			},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"kind": "Pod"}),
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create indexes: %w", err)
 	}
 
+	// Audit entries are always queried newest first, usually scoped to a user or cluster
+	_, err = auditCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "cluster", Value: 1}, {Key: "timestamp", Value: -1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit indexes: %w", err)
+	}
+
+	// Metric samples are read as a time series per object and pruned by age
+	_, err = metricsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{
+			{Key: "cluster_id", Value: 1},
+			{Key: "kind", Value: 1},
+			{Key: "namespace", Value: 1},
+			{Key: "name", Value: 1},
+			{Key: "timestamp", Value: 1},
+		}},
+		{Keys: bson.D{{Key: "timestamp", Value: 1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics indexes: %w", err)
+	}
+
+	// Snapshots are listed per namespace by version
+	_, err = snapshotsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "cluster_id", Value: 1},
+			{Key: "namespace", Value: 1},
+			{Key: "version", Value: -1},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot indexes: %w", err)
+	}
+
+	// Revisions are listed per object by version
+	_, err = revisionsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "cluster_id", Value: 1},
+			{Key: "namespace", Value: 1},
+			{Key: "kind", Value: 1},
+			{Key: "name", Value: 1},
+			{Key: "version", Value: -1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create revision indexes: %w", err)
+	}
+
+	// Notes are read per resource, or per cluster by tag
+	_, err = notesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{
+			{Key: "cluster_id", Value: 1},
+			{Key: "kind", Value: 1},
+			{Key: "namespace", Value: 1},
+			{Key: "name", Value: 1},
+		}},
+		{Keys: bson.D{{Key: "cluster_id", Value: 1}, {Key: "tags", Value: 1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create note indexes: %w", err)
+	}
+
+	// Recordings are reviewed newest first, usually for a user or cluster
+	_, err = recordingsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "started_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user", Value: 1}, {Key: "started_at", Value: -1}}},
+		{Keys: bson.D{{Key: "cluster_id", Value: 1}, {Key: "started_at", Value: -1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording indexes: %w", err)
+	}
+
+	// Webhook deliveries are listed per webhook newest first, polled for due
+	// retries and pruned by age
+	_, err = deliveriesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery indexes: %w", err)
+	}
+
 	return &Store{
-		client:            client,
-		clusterCollection: clusterCollection,
-		assetCollection:   assetCollection,
-		logger:            logger,
+		client:                client,
+		clusterCollection:     clusterCollection,
+		assetCollection:       assetCollection,
+		auditCollection:       auditCollection,
+		metricsCollection:     metricsCollection,
+		alertsCollection:      alertsCollection,
+		snapshotsCollection:   snapshotsCollection,
+		scansCollection:       scansCollection,
+		credsCollection:       credsCollection,
+		revisionsCollection:   revisionsCollection,
+		recordingsCollection:  recordingsCollection,
+		featuresCollection:    featuresCollection,
+		reportsCollection:     reportsCollection,
+		webhooksCollection:    webhooksCollection,
+		deliveriesCollection:  deliveriesCollection,
+		preferencesCollection: preferencesCollection,
+		searchesCollection:    searchesCollection,
+		notesCollection:       notesCollection,
+		logger:                logger,
 	}, nil
 }
 
@@ -214,6 +374,158 @@ func (s *Store) SaveCluster(ctx context.Context, clusterInfo *cluster.ClusterInf
 	return nil
 }
 
+// DeleteCluster removes a cluster by name
+func (s *Store) DeleteCluster(ctx context.Context, name string) error {
+	id := fmt.Sprintf("cluster:%s", name)
+
+	if _, err := s.clusterCollection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("failed to delete cluster: %w", err)
+	}
+
+	s.logger.Info("Removed cluster from database", "id", id, "name", name)
+	return nil
+}
+
+// UpdateClusterStatus sets the status of a stored cluster
+func (s *Store) UpdateClusterStatus(ctx context.Context, name, status string) error {
+	id := fmt.Sprintf("cluster:%s", name)
+
+	_, err := s.clusterCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": status, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update cluster status: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateClusterServerStatus sets the server status of a stored cluster
+func (s *Store) UpdateClusterServerStatus(ctx context.Context, name string, status *cluster.ServerStatus) error {
+	id := fmt.Sprintf("cluster:%s", name)
+
+	_, err := s.clusterCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"server": status, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update cluster server status: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAgentHeartbeat records that the agent serving a cluster is alive
+func (s *Store) UpdateAgentHeartbeat(ctx context.Context, name, version string, degraded bool, at time.Time) error {
+	id := fmt.Sprintf("cluster:%s", name)
+
+	_, err := s.clusterCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"agent.version": version, "agent.degraded": degraded, "agent.last_heartbeat": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update agent heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// RotateAgentCredential replaces the agent token hash of a cluster. The
+// token the agent last confirmed stays valid until the rotation is
+// confirmed, even across repeated rotations.
+func (s *Store) RotateAgentCredential(ctx context.Context, name, hash, rotatedBy string) error {
+	id := fmt.Sprintf("cluster:%s", name)
+
+	previous := bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$agent_credential.pending", true}},
+		"$agent_credential.previous_hash",
+		"$agent_credential.token_hash",
+	}}
+
+	result, err := s.clusterCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"agent_credential.previous_hash": previous,
+			"agent_credential.token_hash":    hash,
+			"agent_credential.pending":       true,
+			"agent_credential.rotated_at":    time.Now(),
+			"agent_credential.rotated_by":    rotatedBy,
+		}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rotate agent credential: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: cluster %s", ErrNotFound, name)
+	}
+
+	return nil
+}
+
+// ConfirmAgentCredential drops the previous token of a cluster once its
+// agent uses the one with the given hash
+func (s *Store) ConfirmAgentCredential(ctx context.Context, name, hash string) error {
+	id := fmt.Sprintf("cluster:%s", name)
+
+	result, err := s.clusterCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "agent_credential.token_hash": hash},
+		bson.M{
+			"$set":   bson.M{"agent_credential.pending": false},
+			"$unset": bson.M{"agent_credential.previous_hash": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to confirm agent credential: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: cluster %s with a pending agent credential", ErrNotFound, name)
+	}
+
+	return nil
+}
+
+// AgentTokenKnown reports whether a hash is the current or pending-rotation
+// token of any cluster
+func (s *Store) AgentTokenKnown(ctx context.Context, hash string) (bool, error) {
+	count, err := s.clusterCollection.CountDocuments(ctx, bson.M{"$or": bson.A{
+		bson.M{"agent_credential.token_hash": hash},
+		bson.M{"agent_credential.previous_hash": hash},
+	}})
+	if err != nil {
+		return false, fmt.Errorf("failed to look up agent token: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// SetClusterFeature overrides a feature flag for a cluster, or removes the
+// override when enabled is nil
+func (s *Store) SetClusterFeature(ctx context.Context, name, feature string, enabled *bool) error {
+	id := fmt.Sprintf("cluster:%s", name)
+	field := "features." + feature
+
+	update := bson.M{"$unset": bson.M{field: ""}}
+	if enabled != nil {
+		update = bson.M{"$set": bson.M{field: *enabled}}
+	}
+
+	result, err := s.clusterCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set cluster feature: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: cluster %s", ErrNotFound, name)
+	}
+
+	return nil
+}
+
 // Get retrieves a Kubernetes resource by its identifying information
 func (s *Store) Get(ctx context.Context, clusterID, namespace, kind, name string, result interface{}) error {
 	// Generate the correct ID based on resource type
@@ -229,7 +541,7 @@ func (s *Store) Get(ctx context.Context, clusterID, namespace, kind, name string
 	err := s.assetCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return fmt.Errorf("resource not found: %s", id)
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
 		}
 		return fmt.Errorf("database error: %w", err)
 	}
@@ -286,6 +598,44 @@ func (s *Store) List(ctx context.Context, clusterID, namespace, kind string, res
 		return fmt.Errorf("failed to read cursor: %w", err)
 	}
 
+	s.decodeResources(docs, resultsVal)
+	return nil
+}
+
+// ListByNode returns the stored pods of a cluster scheduled on a node
+func (s *Store) ListByNode(ctx context.Context, clusterID, nodeName string, results interface{}) error {
+	resultsVal := reflect.ValueOf(results)
+	if resultsVal.Kind() != reflect.Ptr || resultsVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results must be a pointer to slice")
+	}
+
+	cursor, err := s.assetCollection.Find(ctx, bson.M{
+		"cluster_id":  clusterID,
+		"kind":        "Pod",
+		nodeNameField: nodeName,
+	})
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	s.decodeResources(docs, resultsVal)
+	return nil
+}
+
+// decodeResources decodes the resource of each document into the slice
+// resultsVal points to, skipping documents that can't be decoded
+func (s *Store) decodeResources(docs []bson.M, resultsVal reflect.Value) {
 	// Create a slice of the correct type
 	sliceType := resultsVal.Elem().Type()
 	elemType := sliceType.Elem()
@@ -320,7 +670,6 @@ func (s *Store) List(ctx context.Context, clusterID, namespace, kind string, res
 
 	// Assign the new slice to the results pointer
 	resultsVal.Elem().Set(slice)
-	return nil
 }
 
 // GetCluster retrieves a cluster by name
@@ -423,6 +772,1059 @@ func (s *Store) DeleteByFilter(ctx context.Context, filter map[string]interface{
 	return nil
 }
 
+// SaveAuditEntry stores an audit entry
+func (s *Store) SaveAuditEntry(ctx context.Context, entry *audit.Entry) error {
+	if _, err := s.auditCollection.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEntries returns audit entries matching the query, newest first
+func (s *Store) ListAuditEntries(ctx context.Context, query audit.Query, results *[]audit.Entry) error {
+	filter := bson.M{}
+
+	if query.User != "" {
+		filter["user"] = query.User
+	}
+	if query.Cluster != "" {
+		filter["cluster"] = query.Cluster
+	}
+	if query.Namespace != "" {
+		filter["namespace"] = query.Namespace
+	}
+	if query.Resource != "" {
+		filter["resource"] = query.Resource
+	}
+	if query.Verb != "" {
+		filter["verb"] = query.Verb
+	}
+	if query.Result != "" {
+		filter["result"] = query.Result
+	}
+
+	// Time range
+	timeRange := bson.M{}
+	if !query.Since.IsZero() {
+		timeRange["$gte"] = query.Since
+	}
+	if !query.Until.IsZero() {
+		timeRange["$lte"] = query.Until
+	}
+	if len(timeRange) > 0 {
+		filter["timestamp"] = timeRange
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+
+	cursor, err := s.auditCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	entries := make([]audit.Entry, 0)
+	if err := cursor.All(ctx, &entries); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	*results = entries
+	return nil
+}
+
+// SaveMetricSamples stores a batch of metric samples
+func (s *Store) SaveMetricSamples(ctx context.Context, samples []metrics.Sample) error {
+	documents := make([]interface{}, 0, len(samples))
+	for _, sample := range samples {
+		documents = append(documents, sample)
+	}
+
+	if _, err := s.metricsCollection.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("failed to save metric samples: %w", err)
+	}
+	return nil
+}
+
+// ListMetricSamples returns samples matching the query, oldest first
+func (s *Store) ListMetricSamples(ctx context.Context, query metrics.SampleQuery, results *[]metrics.Sample) error {
+	filter := bson.M{
+		"cluster_id": query.ClusterID,
+		"kind":       query.Kind,
+		"name":       query.Name,
+	}
+	if query.Namespace != "" {
+		filter["namespace"] = query.Namespace
+	}
+
+	timeRange := bson.M{}
+	if !query.Since.IsZero() {
+		timeRange["$gte"] = query.Since
+	}
+	if !query.Until.IsZero() {
+		timeRange["$lte"] = query.Until
+	}
+	if len(timeRange) > 0 {
+		filter["timestamp"] = timeRange
+	}
+
+	cursor, err := s.metricsCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	samples := make([]metrics.Sample, 0)
+	if err := cursor.All(ctx, &samples); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	*results = samples
+	return nil
+}
+
+// DeleteMetricSamplesBefore removes samples older than the given time
+func (s *Store) DeleteMetricSamplesBefore(ctx context.Context, before time.Time) error {
+	if _, err := s.metricsCollection.DeleteMany(ctx, bson.M{"timestamp": bson.M{"$lt": before}}); err != nil {
+		return fmt.Errorf("failed to delete metric samples: %w", err)
+	}
+	return nil
+}
+
+// Alerting documents share one collection and are told apart by kind
+const (
+	alertKindSink    = "sink"
+	alertKindRoute   = "route"
+	alertKindSilence = "silence"
+)
+
+// SaveAlertSink creates or replaces a notification sink, assigning an ID to new sinks
+func (s *Store) SaveAlertSink(ctx context.Context, sink *alerting.Sink) error {
+	return s.saveAlertDocument(ctx, alertKindSink, &sink.ID, sink)
+}
+
+// ListAlertSinks returns all notification sinks
+func (s *Store) ListAlertSinks(ctx context.Context, results *[]alerting.Sink) error {
+	sinks := make([]alerting.Sink, 0)
+	if err := s.listAlertDocuments(ctx, alertKindSink, &sinks); err != nil {
+		return err
+	}
+
+	*results = sinks
+	return nil
+}
+
+// DeleteAlertSink removes a notification sink
+func (s *Store) DeleteAlertSink(ctx context.Context, id string) error {
+	return s.deleteAlertDocument(ctx, alertKindSink, id)
+}
+
+// SaveAlertRoute creates or replaces a routing rule, assigning an ID to new routes
+func (s *Store) SaveAlertRoute(ctx context.Context, route *alerting.Route) error {
+	return s.saveAlertDocument(ctx, alertKindRoute, &route.ID, route)
+}
+
+// ListAlertRoutes returns all routing rules
+func (s *Store) ListAlertRoutes(ctx context.Context, results *[]alerting.Route) error {
+	routes := make([]alerting.Route, 0)
+	if err := s.listAlertDocuments(ctx, alertKindRoute, &routes); err != nil {
+		return err
+	}
+
+	*results = routes
+	return nil
+}
+
+// DeleteAlertRoute removes a routing rule
+func (s *Store) DeleteAlertRoute(ctx context.Context, id string) error {
+	return s.deleteAlertDocument(ctx, alertKindRoute, id)
+}
+
+// SaveAlertSilence creates or replaces a silence, assigning an ID to new silences
+func (s *Store) SaveAlertSilence(ctx context.Context, silence *alerting.Silence) error {
+	return s.saveAlertDocument(ctx, alertKindSilence, &silence.ID, silence)
+}
+
+// ListAlertSilences returns all silences, including expired ones
+func (s *Store) ListAlertSilences(ctx context.Context, results *[]alerting.Silence) error {
+	silences := make([]alerting.Silence, 0)
+	if err := s.listAlertDocuments(ctx, alertKindSilence, &silences); err != nil {
+		return err
+	}
+
+	*results = silences
+	return nil
+}
+
+// DeleteAlertSilence removes a silence
+func (s *Store) DeleteAlertSilence(ctx context.Context, id string) error {
+	return s.deleteAlertDocument(ctx, alertKindSilence, id)
+}
+
+// saveAlertDocument upserts an alerting document tagged with its kind
+func (s *Store) saveAlertDocument(ctx context.Context, kind string, id *string, document interface{}) error {
+	if *id == "" {
+		*id = primitive.NewObjectID().Hex()
+	}
+
+	data, err := bson.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", kind, err)
+	}
+
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to convert %s: %w", kind, err)
+	}
+	doc["alert_kind"] = kind
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.alertsCollection.ReplaceOne(ctx, bson.M{"_id": *id}, doc, opts); err != nil {
+		return fmt.Errorf("failed to save %s: %w", kind, err)
+	}
+
+	return nil
+}
+
+// listAlertDocuments decodes every alerting document of a kind into results
+func (s *Store) listAlertDocuments(ctx context.Context, kind string, results interface{}) error {
+	cursor, err := s.alertsCollection.Find(ctx, bson.M{"alert_kind": kind})
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// deleteAlertDocument removes an alerting document of a kind
+func (s *Store) deleteAlertDocument(ctx context.Context, kind, id string) error {
+	result, err := s.alertsCollection.DeleteOne(ctx, bson.M{"_id": id, "alert_kind": kind})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", kind, err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: %s %s", ErrNotFound, kind, id)
+	}
+
+	return nil
+}
+
+// SaveReport creates or replaces a scheduled report, assigning an ID to new reports
+func (s *Store) SaveReport(ctx context.Context, report *reports.Report) error {
+	if report.ID == "" {
+		report.ID = primitive.NewObjectID().Hex()
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.reportsCollection.ReplaceOne(ctx, bson.M{"_id": report.ID}, report, opts); err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+
+	return nil
+}
+
+// GetReport retrieves a scheduled report
+func (s *Store) GetReport(ctx context.Context, id string, result *reports.Report) error {
+	err := s.reportsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: report %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return nil
+}
+
+// ListReports returns all scheduled reports ordered by team and name
+func (s *Store) ListReports(ctx context.Context, results *[]reports.Report) error {
+	opts := options.Find().SetSort(bson.D{{Key: "team", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := s.reportsCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	list := make([]reports.Report, 0)
+	if err := cursor.All(ctx, &list); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	*results = list
+	return nil
+}
+
+// DeleteReport removes a scheduled report
+func (s *Store) DeleteReport(ctx context.Context, id string) error {
+	result, err := s.reportsCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete report: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: report %s", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// SaveWebhook creates or replaces a webhook, assigning an ID to new webhooks
+func (s *Store) SaveWebhook(ctx context.Context, webhook *webhooks.Webhook) error {
+	if webhook.ID == "" {
+		webhook.ID = primitive.NewObjectID().Hex()
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.webhooksCollection.ReplaceOne(ctx, bson.M{"_id": webhook.ID}, webhook, opts); err != nil {
+		return fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhook retrieves a webhook
+func (s *Store) GetWebhook(ctx context.Context, id string, result *webhooks.Webhook) error {
+	err := s.webhooksCollection.FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: webhook %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhooks returns all webhooks ordered by name
+func (s *Store) ListWebhooks(ctx context.Context, results *[]webhooks.Webhook) error {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := s.webhooksCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	list := make([]webhooks.Webhook, 0)
+	if err := cursor.All(ctx, &list); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	*results = list
+	return nil
+}
+
+// DeleteWebhook removes a webhook and its delivery records
+func (s *Store) DeleteWebhook(ctx context.Context, id string) error {
+	result, err := s.webhooksCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: webhook %s", ErrNotFound, id)
+	}
+
+	if _, err := s.deliveriesCollection.DeleteMany(ctx, bson.M{"webhook_id": id}); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// SaveWebhookDelivery creates or replaces a delivery record, assigning an ID to new ones
+func (s *Store) SaveWebhookDelivery(ctx context.Context, delivery *webhooks.Delivery) error {
+	if delivery.ID == "" {
+		delivery.ID = primitive.NewObjectID().Hex()
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.deliveriesCollection.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery, opts); err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhookDelivery retrieves a delivery record
+func (s *Store) GetWebhookDelivery(ctx context.Context, id string, result *webhooks.Delivery) error {
+	err := s.deliveriesCollection.FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: webhook delivery %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries returns the deliveries of a webhook newest first,
+// optionally only those with a status
+func (s *Store) ListWebhookDeliveries(ctx context.Context, webhookID, status string, limit int64, results *[]webhooks.Delivery) error {
+	filter := bson.M{"webhook_id": webhookID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	return s.findWebhookDeliveries(ctx, filter, opts, results)
+}
+
+// ListDueWebhookDeliveries returns pending deliveries whose next attempt is at or before now
+func (s *Store) ListDueWebhookDeliveries(ctx context.Context, now time.Time, results *[]webhooks.Delivery) error {
+	filter := bson.M{
+		"status":          webhooks.StatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}})
+
+	return s.findWebhookDeliveries(ctx, filter, opts, results)
+}
+
+// DeleteWebhookDeliveriesBefore removes delivery records created before the given time
+func (s *Store) DeleteWebhookDeliveriesBefore(ctx context.Context, before time.Time) error {
+	if _, err := s.deliveriesCollection.DeleteMany(ctx, bson.M{"created_at": bson.M{"$lt": before}}); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// findWebhookDeliveries decodes the delivery records matching a filter into results
+func (s *Store) findWebhookDeliveries(ctx context.Context, filter bson.M, opts *options.FindOptions, results *[]webhooks.Delivery) error {
+	cursor, err := s.deliveriesCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	deliveries := make([]webhooks.Delivery, 0)
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	*results = deliveries
+	return nil
+}
+
+// SaveSnapshot stores a namespace snapshot
+func (s *Store) SaveSnapshot(ctx context.Context, snap *snapshot.Snapshot) error {
+	if snap.ID == "" {
+		snap.ID = primitive.NewObjectID().Hex()
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.snapshotsCollection.ReplaceOne(ctx, bson.M{"_id": snap.ID}, snap, opts); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetSnapshot retrieves a namespace snapshot
+func (s *Store) GetSnapshot(ctx context.Context, id string, result *snapshot.Snapshot) error {
+	err := s.snapshotsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: snapshot %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ListSnapshots returns the snapshots of a namespace without their manifests
+func (s *Store) ListSnapshots(ctx context.Context, clusterID, namespace string, results *[]snapshot.Snapshot) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"manifests": 0})
+
+	cursor, err := s.snapshotsCollection.Find(ctx, bson.M{"cluster_id": clusterID, "namespace": namespace}, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// SaveRevision stores a revision of an object. Versions are unique per
+// object, so a concurrent save of the same version fails.
+func (s *Store) SaveRevision(ctx context.Context, rev *revision.Revision) error {
+	if rev.ID == "" {
+		rev.ID = primitive.NewObjectID().Hex()
+	}
+
+	if _, err := s.revisionsCollection.InsertOne(ctx, rev); err != nil {
+		return fmt.Errorf("failed to save revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevision retrieves a revision of an object, or its latest revision when version is 0
+func (s *Store) GetRevision(ctx context.Context, clusterID, namespace, kind, name string, version int, result *revision.Revision) error {
+	filter := bson.M{"cluster_id": clusterID, "namespace": namespace, "kind": kind, "name": name}
+	if version > 0 {
+		filter["version"] = version
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	err := s.revisionsCollection.FindOne(ctx, filter, opts).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: revision %d of %s %s/%s", ErrNotFound, version, kind, namespace, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return nil
+}
+
+// ListRevisions returns the revisions of an object, newest first, without their data
+func (s *Store) ListRevisions(ctx context.Context, clusterID, namespace, kind, name string, results *[]revision.Revision) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"data": 0, "binary_data": 0})
+
+	filter := bson.M{"cluster_id": clusterID, "namespace": namespace, "kind": kind, "name": name}
+	cursor, err := s.revisionsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// SaveRecording stores a session recording
+func (s *Store) SaveRecording(ctx context.Context, rec *recording.Recording) error {
+	if rec.ID == "" {
+		rec.ID = primitive.NewObjectID().Hex()
+	}
+
+	if _, err := s.recordingsCollection.InsertOne(ctx, rec); err != nil {
+		return fmt.Errorf("failed to save recording: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecording retrieves a session recording with its events
+func (s *Store) GetRecording(ctx context.Context, id string, result *recording.Recording) error {
+	err := s.recordingsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: recording %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get recording: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecordings returns session recordings matching a query without their events
+func (s *Store) ListRecordings(ctx context.Context, query recording.Query, results *[]recording.Recording) error {
+	filter := bson.M{}
+
+	if query.User != "" {
+		filter["user"] = query.User
+	}
+	if query.Cluster != "" {
+		filter["cluster_id"] = query.Cluster
+	}
+	if query.Namespace != "" {
+		filter["namespace"] = query.Namespace
+	}
+	if query.Pod != "" {
+		filter["pod"] = query.Pod
+	}
+
+	timeRange := bson.M{}
+	if !query.Since.IsZero() {
+		timeRange["$gte"] = query.Since
+	}
+	if !query.Until.IsZero() {
+		timeRange["$lte"] = query.Until
+	}
+	if len(timeRange) > 0 {
+		filter["started_at"] = timeRange
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: -1}}).
+		SetProjection(bson.M{"events": 0})
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+
+	cursor, err := s.recordingsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// SaveImageScan stores the scan of an image, keyed by image
+func (s *Store) SaveImageScan(ctx context.Context, scan *vulnerabilities.ImageScan) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.scansCollection.ReplaceOne(ctx, bson.M{"_id": scan.Image}, scan, opts); err != nil {
+		return fmt.Errorf("failed to save image scan: %w", err)
+	}
+
+	return nil
+}
+
+// ListImageScans returns the stored scans of the given images
+func (s *Store) ListImageScans(ctx context.Context, images []string, results *[]vulnerabilities.ImageScan) error {
+	cursor, err := s.scansCollection.Find(ctx, bson.M{"_id": bson.M{"$in": images}})
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// SaveClusterCredentials stores the credentials of a registered cluster, keyed by cluster ID
+func (s *Store) SaveClusterCredentials(ctx context.Context, credentials *uploaded.Credentials) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.credsCollection.ReplaceOne(ctx, bson.M{"_id": credentials.ID}, credentials, opts); err != nil {
+		return fmt.Errorf("failed to save cluster credentials: %w", err)
+	}
+
+	return nil
+}
+
+// GetClusterCredentials retrieves the credentials of a registered cluster
+func (s *Store) GetClusterCredentials(ctx context.Context, id string, result *uploaded.Credentials) error {
+	err := s.credsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: cluster credentials %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get cluster credentials: %w", err)
+	}
+
+	return nil
+}
+
+// ListClusterCredentials returns the registered clusters without their kubeconfigs
+func (s *Store) ListClusterCredentials(ctx context.Context, results *[]uploaded.Credentials) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"kubeconfig": 0})
+
+	cursor, err := s.credsCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteClusterCredentials removes the credentials of a registered cluster
+func (s *Store) DeleteClusterCredentials(ctx context.Context, id string) error {
+	result, err := s.credsCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete cluster credentials: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: cluster credentials %s", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// SaveFeatureOverride creates or replaces a deployment-wide feature flag override
+func (s *Store) SaveFeatureOverride(ctx context.Context, override *features.Override) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.featuresCollection.ReplaceOne(ctx, bson.M{"_id": override.Name}, override, opts); err != nil {
+		return fmt.Errorf("failed to save feature flag override: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFeatureOverride removes a deployment-wide feature flag override. Removing
+// one that isn't set is not an error.
+func (s *Store) DeleteFeatureOverride(ctx context.Context, name string) error {
+	if _, err := s.featuresCollection.DeleteOne(ctx, bson.M{"_id": name}); err != nil {
+		return fmt.Errorf("failed to delete feature flag override: %w", err)
+	}
+
+	return nil
+}
+
+// ListFeatureOverrides returns the deployment-wide feature flag overrides
+func (s *Store) ListFeatureOverrides(ctx context.Context, results *[]features.Override) error {
+	cursor, err := s.featuresCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// SavePreferences creates or replaces the preferences of a user
+func (s *Store) SavePreferences(ctx context.Context, prefs *preferences.Preferences) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.preferencesCollection.ReplaceOne(ctx, bson.M{"_id": prefs.User}, prefs, opts); err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+
+	return nil
+}
+
+// GetPreferences retrieves the preferences of a user
+func (s *Store) GetPreferences(ctx context.Context, user string, result *preferences.Preferences) error {
+	err := s.preferencesCollection.FindOne(ctx, bson.M{"_id": user}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: preferences of %s", ErrNotFound, user)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePreferences removes the preferences of a user. Removing preferences
+// that were never saved is not an error.
+func (s *Store) DeletePreferences(ctx context.Context, user string) error {
+	if _, err := s.preferencesCollection.DeleteOne(ctx, bson.M{"_id": user}); err != nil {
+		return fmt.Errorf("failed to delete preferences: %w", err)
+	}
+
+	return nil
+}
+
+// SaveSearch creates or replaces a saved search, assigning an ID to new ones
+func (s *Store) SaveSearch(ctx context.Context, search *searches.Search) error {
+	if search.ID == "" {
+		search.ID = primitive.NewObjectID().Hex()
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.searchesCollection.ReplaceOne(ctx, bson.M{"_id": search.ID}, search, opts); err != nil {
+		return fmt.Errorf("failed to save search: %w", err)
+	}
+
+	return nil
+}
+
+// GetSearch retrieves a saved search
+func (s *Store) GetSearch(ctx context.Context, id string, result *searches.Search) error {
+	err := s.searchesCollection.FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: search %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get search: %w", err)
+	}
+
+	return nil
+}
+
+// ListSearches returns the searches a user owns and those shared with
+// everyone, ordered by name
+func (s *Store) ListSearches(ctx context.Context, user string, results *[]searches.Search) error {
+	filter := bson.M{"$or": bson.A{bson.M{"owner": user}, bson.M{"shared": true}}}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := s.searchesCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSearch removes a saved search
+func (s *Store) DeleteSearch(ctx context.Context, id string) error {
+	result, err := s.searchesCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete search: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: search %s", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// SaveNote creates or replaces a note, assigning an ID to new ones
+func (s *Store) SaveNote(ctx context.Context, note *notes.Note) error {
+	if note.ID == "" {
+		note.ID = primitive.NewObjectID().Hex()
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.notesCollection.ReplaceOne(ctx, bson.M{"_id": note.ID}, note, opts); err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+
+	return nil
+}
+
+// GetNote retrieves a note
+func (s *Store) GetNote(ctx context.Context, id string, result *notes.Note) error {
+	err := s.notesCollection.FindOne(ctx, bson.M{"_id": id}).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return fmt.Errorf("%w: note %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get note: %w", err)
+	}
+
+	return nil
+}
+
+// ListNotes returns the notes matching a query, oldest first
+func (s *Store) ListNotes(ctx context.Context, query notes.Query, results *[]notes.Note) error {
+	filter := bson.M{"cluster_id": query.ClusterID}
+
+	if query.Kind != "" {
+		filter["kind"] = query.Kind
+	}
+	if query.Namespace != "" {
+		filter["namespace"] = query.Namespace
+	}
+	if query.Name != "" {
+		filter["name"] = query.Name
+	}
+	if query.Tag != "" {
+		filter["tags"] = query.Tag
+	}
+	if query.Author != "" {
+		filter["author"] = query.Author
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := s.notesCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("database query error: %w", err)
+	}
+
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			s.logger.Warn("Failed to close cursor", "error", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("failed to read cursor: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteNote removes a note
+func (s *Store) DeleteNote(ctx context.Context, id string) error {
+	result, err := s.notesCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: note %s", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// Watch streams changes to resources of a kind using a change stream on the
+// asset collection. Change streams require MongoDB to run as a replica set.
+func (s *Store) Watch(ctx context.Context, clusterID, namespace, kind string) (<-chan Change, error) {
+	// Document IDs are cluster:namespace:kind:name, or cluster:Namespace:name
+	namespacePattern := "[^:]*"
+	if namespace != "" && namespace != "all" {
+		namespacePattern = regexp.QuoteMeta(namespace)
+	}
+
+	idPattern := fmt.Sprintf("^%s:(%s):%s:(.+)$", regexp.QuoteMeta(clusterID), namespacePattern, regexp.QuoteMeta(kind))
+	if kind == "Namespace" {
+		idPattern = fmt.Sprintf("^%s:()Namespace:(.+)$", regexp.QuoteMeta(clusterID))
+	}
+	idRegexp := regexp.MustCompile(idPattern)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"documentKey._id": primitive.Regex{Pattern: idPattern},
+			"operationType":   bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}},
+		}}},
+	}
+
+	stream, err := s.assetCollection.Watch(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch resources: %w", err)
+	}
+
+	changes := make(chan Change)
+	go func() {
+		defer close(changes)
+		defer func() {
+			if err := stream.Close(context.Background()); err != nil {
+				s.logger.Warn("Failed to close change stream", "error", err)
+			}
+		}()
+
+		for stream.Next(ctx) {
+			var event struct {
+				OperationType string              `bson:"operationType"`
+				ClusterTime   primitive.Timestamp `bson:"clusterTime"`
+				DocumentKey   struct {
+					ID string `bson:"_id"`
+				} `bson:"documentKey"`
+			}
+			if err := stream.Decode(&event); err != nil {
+				s.logger.Warn("Failed to decode change event", "error", err)
+				continue
+			}
+
+			match := idRegexp.FindStringSubmatch(event.DocumentKey.ID)
+			if match == nil {
+				continue
+			}
+
+			change := Change{Op: ChangeUpsert, Kind: kind, Namespace: match[1], Name: match[2]}
+			if event.ClusterTime.T != 0 {
+				change.Time = time.Unix(int64(event.ClusterTime.T), 0)
+			}
+			if event.OperationType == "delete" {
+				change.Op = ChangeDelete
+			}
+
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			s.logger.Warn("Change stream ended", "error", err)
+		}
+	}()
+
+	return changes, nil
+}
+
+// Ping checks that MongoDB is reachable
+func (s *Store) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx, nil)
+}
+
 // Close closes the MongoDB connection
 func (s *Store) Close(ctx context.Context) error {
 	return s.client.Disconnect(ctx)
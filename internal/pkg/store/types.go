@@ -2,11 +2,30 @@ package store
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/jbetancur/dashboard/internal/pkg/alerting"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/metrics"
+	"github.com/jbetancur/dashboard/internal/pkg/assets/vulnerabilities"
+	"github.com/jbetancur/dashboard/internal/pkg/audit"
 	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/features"
+	"github.com/jbetancur/dashboard/internal/pkg/notes"
+	"github.com/jbetancur/dashboard/internal/pkg/preferences"
+	"github.com/jbetancur/dashboard/internal/pkg/providers/uploaded"
+	"github.com/jbetancur/dashboard/internal/pkg/recording"
+	"github.com/jbetancur/dashboard/internal/pkg/reports"
+	"github.com/jbetancur/dashboard/internal/pkg/revision"
+	"github.com/jbetancur/dashboard/internal/pkg/searches"
+	"github.com/jbetancur/dashboard/internal/pkg/snapshot"
+	"github.com/jbetancur/dashboard/internal/pkg/webhooks"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// ErrNotFound is returned when a requested document does not exist
+var ErrNotFound = errors.New("not found")
+
 // Repository defines the interface for storage operations
 type Repository interface {
 	// Save stores a Kubernetes resource
@@ -15,6 +34,32 @@ type Repository interface {
 	// SaveCluster stores cluster information
 	SaveCluster(ctx context.Context, clusterInfo *cluster.ClusterInfo) error
 
+	// DeleteCluster removes cluster information
+	DeleteCluster(ctx context.Context, name string) error
+
+	// UpdateClusterStatus sets the status of a stored cluster
+	UpdateClusterStatus(ctx context.Context, name, status string) error
+
+	// UpdateClusterServerStatus sets the server status of a stored cluster
+	UpdateClusterServerStatus(ctx context.Context, name string, status *cluster.ServerStatus) error
+
+	// UpdateAgentHeartbeat records that the agent serving a cluster is alive
+	// and whether it is degraded
+	UpdateAgentHeartbeat(ctx context.Context, name, version string, degraded bool, at time.Time) error
+
+	// RotateAgentCredential replaces the agent token hash of a cluster, keeping
+	// the previous one until ConfirmAgentCredential is called
+	RotateAgentCredential(ctx context.Context, name, hash, rotatedBy string) error
+
+	// ConfirmAgentCredential ends a rotation once the agent uses the new token
+	ConfirmAgentCredential(ctx context.Context, name, hash string) error
+
+	// AgentTokenKnown reports whether a hash is the current or pending-rotation token of a cluster
+	AgentTokenKnown(ctx context.Context, hash string) (bool, error)
+
+	// SetClusterFeature overrides a feature flag for a cluster, or removes the override when enabled is nil
+	SetClusterFeature(ctx context.Context, name, feature string, enabled *bool) error
+
 	// Get retrieves a Kubernetes resource
 	Get(ctx context.Context, clusterID, namespace, kind, name string, result interface{}) error
 
@@ -24,6 +69,9 @@ type Repository interface {
 	// List returns resources matching criteria
 	List(ctx context.Context, clusterID, namespace, kind string, results interface{}) error
 
+	// ListByNode returns the pods of a cluster scheduled on a node
+	ListByNode(ctx context.Context, clusterID, nodeName string, results interface{}) error
+
 	//ListCluster returns all clusters
 	ListClusters(ctx context.Context, results *[]cluster.ClusterInfo) error
 
@@ -33,6 +81,197 @@ type Repository interface {
 	// DeleteByFilter removes resources matching a filter
 	DeleteByFilter(ctx context.Context, filter map[string]interface{}) error
 
+	// SaveAuditEntry stores an audit entry
+	SaveAuditEntry(ctx context.Context, entry *audit.Entry) error
+
+	// ListAuditEntries returns audit entries matching a query
+	ListAuditEntries(ctx context.Context, query audit.Query, results *[]audit.Entry) error
+
+	// SaveMetricSamples stores metric samples
+	SaveMetricSamples(ctx context.Context, samples []metrics.Sample) error
+
+	// ListMetricSamples returns metric samples matching a query
+	ListMetricSamples(ctx context.Context, query metrics.SampleQuery, results *[]metrics.Sample) error
+
+	// DeleteMetricSamplesBefore removes metric samples older than a time
+	DeleteMetricSamplesBefore(ctx context.Context, before time.Time) error
+
+	// SaveAlertSink creates or replaces a notification sink
+	SaveAlertSink(ctx context.Context, sink *alerting.Sink) error
+
+	// ListAlertSinks returns all notification sinks
+	ListAlertSinks(ctx context.Context, results *[]alerting.Sink) error
+
+	// DeleteAlertSink removes a notification sink
+	DeleteAlertSink(ctx context.Context, id string) error
+
+	// SaveAlertRoute creates or replaces an alert routing rule
+	SaveAlertRoute(ctx context.Context, route *alerting.Route) error
+
+	// ListAlertRoutes returns all alert routing rules
+	ListAlertRoutes(ctx context.Context, results *[]alerting.Route) error
+
+	// DeleteAlertRoute removes an alert routing rule
+	DeleteAlertRoute(ctx context.Context, id string) error
+
+	// SaveAlertSilence creates or replaces an alert silence
+	SaveAlertSilence(ctx context.Context, silence *alerting.Silence) error
+
+	// ListAlertSilences returns all alert silences
+	ListAlertSilences(ctx context.Context, results *[]alerting.Silence) error
+
+	// DeleteAlertSilence removes an alert silence
+	DeleteAlertSilence(ctx context.Context, id string) error
+
+	// SaveReport creates or replaces a scheduled report
+	SaveReport(ctx context.Context, report *reports.Report) error
+
+	// GetReport retrieves a scheduled report
+	GetReport(ctx context.Context, id string, result *reports.Report) error
+
+	// ListReports returns all scheduled reports
+	ListReports(ctx context.Context, results *[]reports.Report) error
+
+	// DeleteReport removes a scheduled report
+	DeleteReport(ctx context.Context, id string) error
+
+	// SaveWebhook creates or replaces an outbound webhook
+	SaveWebhook(ctx context.Context, webhook *webhooks.Webhook) error
+
+	// GetWebhook retrieves an outbound webhook
+	GetWebhook(ctx context.Context, id string, result *webhooks.Webhook) error
+
+	// ListWebhooks returns all outbound webhooks
+	ListWebhooks(ctx context.Context, results *[]webhooks.Webhook) error
+
+	// DeleteWebhook removes an outbound webhook and its delivery records
+	DeleteWebhook(ctx context.Context, id string) error
+
+	// SaveWebhookDelivery creates or replaces a webhook delivery record
+	SaveWebhookDelivery(ctx context.Context, delivery *webhooks.Delivery) error
+
+	// GetWebhookDelivery retrieves a webhook delivery record
+	GetWebhookDelivery(ctx context.Context, id string, result *webhooks.Delivery) error
+
+	// ListWebhookDeliveries returns the deliveries of a webhook newest first,
+	// optionally only those with a status. A limit of 0 returns all of them.
+	ListWebhookDeliveries(ctx context.Context, webhookID, status string, limit int64, results *[]webhooks.Delivery) error
+
+	// ListDueWebhookDeliveries returns pending deliveries whose next attempt is at or before now
+	ListDueWebhookDeliveries(ctx context.Context, now time.Time, results *[]webhooks.Delivery) error
+
+	// DeleteWebhookDeliveriesBefore removes webhook delivery records created before the given time
+	DeleteWebhookDeliveriesBefore(ctx context.Context, before time.Time) error
+
+	// SaveSnapshot stores a namespace snapshot, assigning its ID
+	SaveSnapshot(ctx context.Context, snap *snapshot.Snapshot) error
+
+	// GetSnapshot retrieves a namespace snapshot with its manifests
+	GetSnapshot(ctx context.Context, id string, result *snapshot.Snapshot) error
+
+	// ListSnapshots returns the snapshots of a namespace, newest first, without manifests
+	ListSnapshots(ctx context.Context, clusterID, namespace string, results *[]snapshot.Snapshot) error
+
+	// SaveRevision stores a new revision of an object, assigning its ID
+	SaveRevision(ctx context.Context, rev *revision.Revision) error
+
+	// GetRevision retrieves a revision of an object with its data, or the latest when version is 0
+	GetRevision(ctx context.Context, clusterID, namespace, kind, name string, version int, result *revision.Revision) error
+
+	// ListRevisions returns the revisions of an object, newest first, without data
+	ListRevisions(ctx context.Context, clusterID, namespace, kind, name string, results *[]revision.Revision) error
+
+	// SaveFeatureOverride creates or replaces a deployment-wide feature flag override
+	SaveFeatureOverride(ctx context.Context, override *features.Override) error
+
+	// DeleteFeatureOverride removes a deployment-wide feature flag override
+	DeleteFeatureOverride(ctx context.Context, name string) error
+
+	// ListFeatureOverrides returns the deployment-wide feature flag overrides
+	ListFeatureOverrides(ctx context.Context, results *[]features.Override) error
+
+	// SavePreferences creates or replaces the preferences of a user
+	SavePreferences(ctx context.Context, prefs *preferences.Preferences) error
+
+	// GetPreferences retrieves the preferences of a user
+	GetPreferences(ctx context.Context, user string, result *preferences.Preferences) error
+
+	// DeletePreferences removes the preferences of a user
+	DeletePreferences(ctx context.Context, user string) error
+
+	// SaveSearch creates or replaces a saved search
+	SaveSearch(ctx context.Context, search *searches.Search) error
+
+	// GetSearch retrieves a saved search
+	GetSearch(ctx context.Context, id string, result *searches.Search) error
+
+	// ListSearches returns the searches a user owns and those shared with everyone
+	ListSearches(ctx context.Context, user string, results *[]searches.Search) error
+
+	// DeleteSearch removes a saved search
+	DeleteSearch(ctx context.Context, id string) error
+
+	// SaveNote creates or replaces a note on a resource
+	SaveNote(ctx context.Context, note *notes.Note) error
+
+	// GetNote retrieves a note
+	GetNote(ctx context.Context, id string, result *notes.Note) error
+
+	// ListNotes returns the notes matching a query, oldest first
+	ListNotes(ctx context.Context, query notes.Query, results *[]notes.Note) error
+
+	// DeleteNote removes a note
+	DeleteNote(ctx context.Context, id string) error
+
+	// SaveRecording stores a session recording, assigning its ID
+	SaveRecording(ctx context.Context, rec *recording.Recording) error
+
+	// GetRecording retrieves a session recording with its events
+	GetRecording(ctx context.Context, id string, result *recording.Recording) error
+
+	// ListRecordings returns session recordings matching a query, newest first, without events
+	ListRecordings(ctx context.Context, query recording.Query, results *[]recording.Recording) error
+
+	// SaveImageScan creates or replaces the cached scan of an image
+	SaveImageScan(ctx context.Context, scan *vulnerabilities.ImageScan) error
+
+	// ListImageScans returns the cached scans of the given images
+	ListImageScans(ctx context.Context, images []string, results *[]vulnerabilities.ImageScan) error
+
+	// SaveClusterCredentials creates or replaces the credentials of a registered cluster
+	SaveClusterCredentials(ctx context.Context, credentials *uploaded.Credentials) error
+
+	// GetClusterCredentials retrieves the credentials of a registered cluster
+	GetClusterCredentials(ctx context.Context, id string, result *uploaded.Credentials) error
+
+	// ListClusterCredentials returns all registered clusters without their kubeconfigs
+	ListClusterCredentials(ctx context.Context, results *[]uploaded.Credentials) error
+
+	// DeleteClusterCredentials removes the credentials of a registered cluster
+	DeleteClusterCredentials(ctx context.Context, id string) error
+
+	// Watch streams changes to resources of a kind until the context is done.
+	// An empty namespace or "all" watches every namespace.
+	Watch(ctx context.Context, clusterID, namespace, kind string) (<-chan Change, error)
+
+	// Ping checks that the database is reachable
+	Ping(ctx context.Context) error
+
 	// Close shuts down the repository
 	Close(ctx context.Context) error
 }
+
+// Change operations
+const (
+	ChangeUpsert = "upsert"
+	ChangeDelete = "delete"
+)
+
+// Change describes a stored resource that was created, updated or deleted
+type Change struct {
+	Op        string    `json:"op"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Time      time.Time `json:"time"` // When the store recorded the change, zero if unknown
+}
@@ -0,0 +1,387 @@
+// Package supportbundle assembles a diagnostic archive for a namespace or one
+// of its workloads: manifests, describe output, recent events and the last
+// lines of each container's logs, packaged as a tar.gz for support tickets.
+// Parts that cannot be read are listed in errors.txt instead of failing the
+// bundle.
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jbetancur/dashboard/internal/pkg/assets"
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	"github.com/jbetancur/dashboard/internal/pkg/describe"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultTailLines is how many log lines are kept per container
+	DefaultTailLines = 200
+
+	// maxObjectsPerKind caps the objects of one kind in a namespace bundle
+	maxObjectsPerKind = 200
+
+	// maxLogBytes caps the logs kept per container
+	maxLogBytes = 1 << 20
+
+	// maxEvents caps the events in a bundle
+	maxEvents = 500
+)
+
+// Request selects what a bundle covers. Without a kind and name it covers
+// the whole namespace; with them, the workload and the pods it manages.
+type Request struct {
+	Namespace string
+	Kind      string
+	Name      string
+	TailLines int64
+}
+
+// Args encodes a request as agent command arguments
+func (r Request) Args() map[string]string {
+	return map[string]string{
+		"namespace": r.Namespace,
+		"kind":      r.Kind,
+		"name":      r.Name,
+		"tailLines": fmt.Sprint(r.TailLines),
+	}
+}
+
+// RequestFromArgs decodes a request from agent command arguments
+func RequestFromArgs(args map[string]string) (Request, error) {
+	req := Request{Namespace: args["namespace"], Kind: args["kind"], Name: args["name"]}
+	if value := args["tailLines"]; value != "" {
+		if _, err := fmt.Sscan(value, &req.TailLines); err != nil {
+			return Request{}, fmt.Errorf("invalid tailLines %q", value)
+		}
+	}
+	return req, req.Validate()
+}
+
+// Validate checks the request names a namespace and, if scoped to a
+// workload, a supported kind
+func (r Request) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("namespace is required")
+	}
+	if (r.Kind == "") != (r.Name == "") {
+		return fmt.Errorf("kind and name must be given together")
+	}
+	if r.Kind != "" {
+		if _, ok := assets.LookupKind(r.Kind); !ok {
+			return fmt.Errorf("unsupported kind %q", r.Kind)
+		}
+	}
+	if r.TailLines < 0 {
+		return fmt.Errorf("tailLines must not be negative")
+	}
+	return nil
+}
+
+// collector gathers the files of a bundle
+type collector struct {
+	conn    *cluster.Connection
+	req     Request
+	files   map[string][]byte
+	errors  []string
+	objects map[string]bool // kind/name of every included object, for filtering events
+	pods    []corev1.Pod
+}
+
+// Build collects a bundle from the cluster and returns it as a tar.gz
+func Build(ctx context.Context, conn *cluster.Connection, req Request) ([]byte, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Kind != "" {
+		kind, _ := assets.LookupKind(req.Kind)
+		req.Kind = kind.Kind
+	}
+	if req.TailLines == 0 {
+		req.TailLines = DefaultTailLines
+	}
+
+	c := &collector{
+		conn:    conn,
+		req:     req,
+		files:   make(map[string][]byte),
+		objects: make(map[string]bool),
+	}
+
+	if req.Kind == "" {
+		c.collectNamespace(ctx)
+	} else {
+		c.collectWorkload(ctx)
+	}
+	c.collectEvents(ctx)
+	c.collectLogs(ctx)
+
+	return c.archive()
+}
+
+// collectNamespace adds every object of the supported kinds in the namespace
+func (c *collector) collectNamespace(ctx context.Context) {
+	client, err := dynamic.NewForConfig(c.conn.Config)
+	if err != nil {
+		c.fail("failed to create dynamic client: %v", err)
+		return
+	}
+
+	for _, kind := range assets.Kinds() {
+		gvr := schema.GroupVersionResource{Group: kind.Group, Version: kind.Version, Resource: kind.Resource}
+		list, err := client.Resource(gvr).Namespace(c.req.Namespace).List(ctx, metav1.ListOptions{Limit: maxObjectsPerKind})
+		if err != nil {
+			c.fail("failed to list %s: %v", kind.Resource, err)
+			continue
+		}
+
+		for i := range list.Items {
+			c.addObject(ctx, kind.Kind, &list.Items[i])
+		}
+	}
+
+	pods, err := c.conn.Client.CoreV1().Pods(c.req.Namespace).List(ctx, metav1.ListOptions{Limit: maxObjectsPerKind})
+	if err != nil {
+		c.fail("failed to list pods: %v", err)
+		return
+	}
+	c.pods = pods.Items
+}
+
+// collectWorkload adds the requested object and the pods it manages
+func (c *collector) collectWorkload(ctx context.Context) {
+	kind, _ := assets.LookupKind(c.req.Kind)
+
+	client, err := dynamic.NewForConfig(c.conn.Config)
+	if err != nil {
+		c.fail("failed to create dynamic client: %v", err)
+		return
+	}
+
+	gvr := schema.GroupVersionResource{Group: kind.Group, Version: kind.Version, Resource: kind.Resource}
+	obj, err := client.Resource(gvr).Namespace(c.req.Namespace).Get(ctx, c.req.Name, metav1.GetOptions{})
+	if err != nil {
+		c.fail("failed to get %s %s: %v", kind.Kind, c.req.Name, err)
+		return
+	}
+	c.addObject(ctx, kind.Kind, obj)
+
+	pods, err := c.conn.Client.CoreV1().Pods(c.req.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		c.fail("failed to list pods: %v", err)
+		return
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if kind.Kind == "Pod" {
+			if pod.Name == c.req.Name {
+				c.pods = append(c.pods, *pod)
+			}
+			continue
+		}
+
+		workloadKind, workloadName := assets.WorkloadOf(pod)
+		if workloadKind != kind.Kind || workloadName != c.req.Name {
+			continue
+		}
+		c.addPod(ctx, pod)
+		c.pods = append(c.pods, *pod)
+	}
+}
+
+// addPod adds a pod managed by the requested workload
+func (c *collector) addPod(ctx context.Context, pod *corev1.Pod) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		c.fail("failed to convert pod %s: %v", pod.Name, err)
+		return
+	}
+
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	c.addObject(ctx, "Pod", obj)
+}
+
+// addObject adds the manifest and description of an object
+func (c *collector) addObject(ctx context.Context, kind string, obj *unstructured.Unstructured) {
+	name := obj.GetName()
+	c.objects[kind+"/"+name] = true
+
+	content := obj.DeepCopy()
+	unstructured.RemoveNestedField(content.Object, "metadata", "managedFields")
+
+	data, err := yaml.Marshal(content.Object)
+	if err != nil {
+		c.fail("failed to encode %s %s: %v", kind, name, err)
+	} else {
+		c.files[fmt.Sprintf("manifests/%s/%s.yaml", kind, name)] = data
+	}
+
+	text, err := describe.Describe(ctx, c.conn.Client, kind, c.req.Namespace, name)
+	if err != nil {
+		c.fail("failed to describe %s %s: %v", kind, name, err)
+		return
+	}
+	c.files[fmt.Sprintf("describe/%s/%s.txt", kind, name)] = []byte(text)
+}
+
+// collectEvents adds the namespace's recent events, only those about the
+// included objects when the bundle covers a workload
+func (c *collector) collectEvents(ctx context.Context) {
+	events, err := c.conn.Client.CoreV1().Events(c.req.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		c.fail("failed to list events: %v", err)
+		return
+	}
+
+	items := make([]corev1.Event, 0, len(events.Items))
+	for _, event := range events.Items {
+		if c.req.Kind != "" && !c.objects[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name] {
+			continue
+		}
+		items = append(items, event)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return eventTime(items[i]).After(eventTime(items[j]))
+	})
+	if len(items) > maxEvents {
+		items = items[:maxEvents]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-25s %-8s %-25s %-40s %s\n", "LAST SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE")
+	for _, event := range items {
+		fmt.Fprintf(&b, "%-25s %-8s %-25s %-40s %s\n",
+			eventTime(event).UTC().Format(time.RFC3339),
+			event.Type,
+			event.Reason,
+			event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name,
+			strings.TrimSpace(event.Message))
+	}
+	c.files["events.txt"] = []byte(b.String())
+}
+
+// collectLogs adds the last lines of every container of the included pods,
+// and of the previous instance of containers that restarted
+func (c *collector) collectLogs(ctx context.Context) {
+	for _, pod := range c.pods {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			c.addLogs(ctx, pod.Name, status.Name, false)
+			if status.RestartCount > 0 {
+				c.addLogs(ctx, pod.Name, status.Name, true)
+			}
+		}
+	}
+}
+
+// addLogs adds the logs of one container
+func (c *collector) addLogs(ctx context.Context, pod, container string, previous bool) {
+	tailLines := c.req.TailLines
+	stream, err := c.conn.Client.CoreV1().Pods(c.req.Namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+		Previous:  previous,
+	}).Stream(ctx)
+	if err != nil {
+		c.fail("failed to read logs of %s/%s: %v", pod, container, err)
+		return
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(stream, maxLogBytes))
+	if err != nil {
+		c.fail("failed to read logs of %s/%s: %v", pod, container, err)
+	}
+
+	name := fmt.Sprintf("logs/%s/%s.log", pod, container)
+	if previous {
+		name = fmt.Sprintf("logs/%s/%s.previous.log", pod, container)
+	}
+	c.files[name] = data
+}
+
+// archive packages the collected files, adding errors.txt if anything failed
+func (c *collector) archive() ([]byte, error) {
+	if len(c.errors) > 0 {
+		c.files["errors.txt"] = []byte(strings.Join(c.errors, "\n") + "\n")
+	}
+
+	names := make([]string, 0, len(c.files))
+	for name := range c.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	root := c.req.Namespace
+	if c.req.Kind != "" {
+		root += "-" + strings.ToLower(c.req.Kind) + "-" + c.req.Name
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	for _, name := range names {
+		data := c.files[name]
+		header := &tar.Header{
+			Name:    root + "/" + name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress bundle: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// fail records a part of the bundle that could not be collected
+func (c *collector) fail(format string, args ...any) {
+	c.errors = append(c.errors, fmt.Sprintf(format, args...))
+}
+
+// eventTime returns when an event last happened
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
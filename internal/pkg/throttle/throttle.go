@@ -0,0 +1,228 @@
+// Package throttle lets the agent shed load during event storms, such as a
+// mass rollout. When events arrive faster than configured, or the agent's own
+// CPU or memory use is too high, it enters a degraded mode where adds and
+// deletes are still published but updates that only change an object's
+// status are sampled. Objects whose updates were dropped are published again
+// on their next informer resync, so their status catches up after the storm.
+package throttle
+
+import (
+	"context"
+	"log/slog"
+	"runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Config sets when the agent degrades and how much it sheds. Zero
+// thresholds are not checked.
+type Config struct {
+	// MaxEventsPerSecond is the informer event rate above which the agent degrades
+	MaxEventsPerSecond int
+	// MaxCPU is the CPU, in cores, above which the agent degrades
+	MaxCPU float64
+	// MaxMemoryBytes is the Go heap size above which the agent degrades
+	MaxMemoryBytes uint64
+	// SampleRate publishes one in this many status-only updates while degraded
+	SampleRate int
+	// CoolDown is how long load must stay below every threshold before the
+	// agent leaves degraded mode
+	CoolDown time.Duration
+}
+
+// Throttle measures the agent's load and decides which events to publish
+type Throttle struct {
+	config Config
+	logger *slog.Logger
+
+	events   atomic.Int64
+	updates  atomic.Int64
+	dropped  atomic.Int64
+	degraded atomic.Bool
+
+	// stale holds the objects whose latest update was dropped
+	staleMu sync.Mutex
+	stale   map[types.UID]struct{}
+
+	mu        sync.Mutex
+	calmSince time.Time
+	cpuTotal  float64
+	sampledAt time.Time
+}
+
+// New creates a throttle, filling in defaults
+func New(config Config, logger *slog.Logger) *Throttle {
+	if config.SampleRate <= 0 {
+		config.SampleRate = 10
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = time.Minute
+	}
+
+	return &Throttle{
+		config: config,
+		logger: logger,
+		stale:  make(map[types.UID]struct{}),
+	}
+}
+
+// Start checks the load every second until the context is cancelled
+func (t *Throttle) Start(ctx context.Context) {
+	t.cpuTotal, _ = readMetrics()
+	t.sampledAt = time.Now()
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				t.check(now)
+			}
+		}
+	}()
+}
+
+// Degraded reports whether the agent is shedding status updates
+func (t *Throttle) Degraded() bool {
+	return t.degraded.Load()
+}
+
+// Record counts an add or delete event of an object. They are always
+// published, and a deleted object has nothing left to catch up on.
+func (t *Throttle) Record(obj interface{}) {
+	t.events.Add(1)
+	t.setStale(obj, false)
+}
+
+// AllowUpdate counts an update event of an object and reports whether to
+// publish it. While degraded, only one in SampleRate status-only updates is
+// published. An object whose update is dropped is marked stale until one of
+// its updates, or its next resync, goes out.
+func (t *Throttle) AllowUpdate(obj interface{}, statusOnly bool) bool {
+	t.events.Add(1)
+	if !statusOnly || !t.degraded.Load() || t.updates.Add(1)%int64(t.config.SampleRate) == 0 {
+		t.setStale(obj, false)
+		return true
+	}
+
+	t.dropped.Add(1)
+	t.setStale(obj, true)
+	return false
+}
+
+// Stale reports whether an object's latest update was dropped. Informer
+// resyncs redeliver such objects unchanged; managers publish them anyway
+// so the last status of a storm is not lost.
+func (t *Throttle) Stale(obj interface{}) bool {
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		return false
+	}
+
+	t.staleMu.Lock()
+	defer t.staleMu.Unlock()
+
+	_, stale := t.stale[object.GetUID()]
+	return stale
+}
+
+// setStale marks or clears an object as having a dropped update
+func (t *Throttle) setStale(obj interface{}, stale bool) {
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+
+	t.staleMu.Lock()
+	defer t.staleMu.Unlock()
+
+	if stale {
+		t.stale[object.GetUID()] = struct{}{}
+	} else {
+		delete(t.stale, object.GetUID())
+	}
+}
+
+// check compares the last second's load with the thresholds and switches
+// between normal and degraded mode
+func (t *Throttle) check(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elapsed := now.Sub(t.sampledAt).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	cpuTotal, heap := readMetrics()
+	rate := float64(t.events.Swap(0)) / elapsed
+	cpu := (cpuTotal - t.cpuTotal) / elapsed
+	t.cpuTotal, t.sampledAt = cpuTotal, now
+
+	var reason string
+	switch {
+	case t.config.MaxEventsPerSecond > 0 && rate > float64(t.config.MaxEventsPerSecond):
+		reason = "event rate"
+	case t.config.MaxCPU > 0 && cpu > t.config.MaxCPU:
+		reason = "cpu"
+	case t.config.MaxMemoryBytes > 0 && heap > t.config.MaxMemoryBytes:
+		reason = "memory"
+	}
+
+	if reason != "" {
+		t.calmSince = time.Time{}
+		if !t.degraded.Swap(true) {
+			t.logger.Warn("Agent overloaded, sampling status updates",
+				"reason", reason,
+				"eventsPerSecond", int(rate),
+				"cpu", cpu,
+				"heapBytes", heap,
+				"sampleRate", t.config.SampleRate)
+		}
+		return
+	}
+
+	if !t.degraded.Load() {
+		return
+	}
+	if t.calmSince.IsZero() {
+		t.calmSince = now
+	}
+	if now.Sub(t.calmSince) >= t.config.CoolDown {
+		t.degraded.Store(false)
+		t.calmSince = time.Time{}
+		t.staleMu.Lock()
+		stale := len(t.stale)
+		t.staleMu.Unlock()
+		t.logger.Info("Agent load back to normal, publishing every update",
+			"droppedUpdates", t.dropped.Swap(0),
+			"staleObjects", stale)
+	}
+}
+
+// readMetrics returns the CPU seconds the process has used and its heap size
+func readMetrics() (float64, uint64) {
+	samples := []metrics.Sample{
+		{Name: "/cpu/classes/total:cpu-seconds"},
+		{Name: "/memory/classes/heap/objects:bytes"},
+	}
+	metrics.Read(samples)
+
+	var cpu float64
+	var heap uint64
+	if samples[0].Value.Kind() == metrics.KindFloat64 {
+		cpu = samples[0].Value.Float64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		heap = samples[1].Value.Uint64()
+	}
+	return cpu, heap
+}
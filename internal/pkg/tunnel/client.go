@@ -0,0 +1,269 @@
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	"github.com/jbetancur/dashboard/internal/pkg/podexec"
+)
+
+// Client opens streams through the agent for clusters the API cannot reach
+type Client struct {
+	streamer       messagingtypes.Streamer
+	config         Config
+	clusterManager *cluster.Manager
+	logger         *slog.Logger
+}
+
+// NewClient creates a tunnel client. Streams are never proxied when the
+// message queue cannot carry them.
+func NewClient(queue messagingtypes.MessageQueue, config Config, clusterManager *cluster.Manager, logger *slog.Logger) *Client {
+	streamer, _ := queue.(messagingtypes.Streamer)
+
+	return &Client{
+		streamer:       streamer,
+		config:         config,
+		clusterManager: clusterManager,
+		logger:         logger,
+	}
+}
+
+// Proxied reports whether streams for a cluster should go through its agent
+func (c *Client) Proxied(clusterID string) bool {
+	if c == nil || c.streamer == nil {
+		return false
+	}
+
+	if slices.Contains(c.config.Clusters, clusterID) {
+		return true
+	}
+
+	return c.clusterManager.Status(clusterID) == cluster.StatusUnhealthy
+}
+
+// Logs streams a container's logs through the agent. It returns once the
+// agent has opened the upstream stream; closing the reader ends it.
+func (c *Client) Logs(ctx context.Context, open Open) (io.ReadCloser, error) {
+	if c.streamer == nil {
+		return nil, fmt.Errorf("streams are not supported by the message queue")
+	}
+
+	open.Kind = KindLogs
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := c.open(ctx, open)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		defer cancel()
+
+		for {
+			frame, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				_ = writer.Close()
+				return
+			}
+			if err != nil {
+				_ = writer.CloseWithError(err)
+				return
+			}
+
+			switch frame.Type {
+			case FrameData:
+				if _, err := writer.Write(frame.Data); err != nil {
+					return
+				}
+			case FrameError:
+				_ = writer.CloseWithError(errors.New(string(frame.Data)))
+				return
+			}
+		}
+	}()
+
+	return &streamReader{PipeReader: reader, cancel: cancel}, nil
+}
+
+// Exec runs a command in a container through the agent, returning once it
+// exits. A non-zero exit is returned as a *podexec.ExitError, as with a
+// direct connection.
+func (c *Client) Exec(ctx context.Context, open Open, opts podexec.Options) error {
+	if len(opts.Command) == 0 {
+		return errors.New("missing command")
+	}
+
+	open.Kind = KindExec
+	return c.interactive(ctx, open, opts)
+}
+
+// Attach connects to the main process of a container through the agent,
+// returning once it exits or detaches
+func (c *Client) Attach(ctx context.Context, open Open, opts podexec.Options) error {
+	open.Kind = KindAttach
+	return c.interactive(ctx, open, opts)
+}
+
+// interactive relays the streams of an exec or attach session over a
+// stream to the agent, which connects to the API server for us
+func (c *Client) interactive(ctx context.Context, open Open, opts podexec.Options) error {
+	if c.streamer == nil {
+		return fmt.Errorf("streams are not supported by the message queue")
+	}
+
+	open.Container = opts.Container
+	open.Command = opts.Command
+	open.TTY = opts.TTY
+	open.Stdin = opts.Stdin != nil
+	open.Stdout = opts.Stdout != nil
+	open.Stderr = opts.Stderr != nil
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.open(ctx, open)
+	if err != nil {
+		return err
+	}
+
+	// Input and resizes are sent from their own goroutines
+	var mu sync.Mutex
+	send := func(frame *messagingtypes.StreamFrame) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return stream.Send(frame)
+	}
+
+	if opts.Stdin != nil {
+		go func() {
+			buf := make([]byte, chunkSize)
+			for {
+				n, err := opts.Stdin.Read(buf)
+				if n > 0 {
+					data := append([]byte(nil), buf[:n]...)
+					if send(&messagingtypes.StreamFrame{Type: FrameStdin, Data: data}) != nil {
+						return
+					}
+				}
+				if err != nil {
+					_ = send(&messagingtypes.StreamFrame{Type: FrameClose})
+					return
+				}
+			}
+		}()
+	}
+
+	if opts.Resize != nil {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case size, ok := <-opts.Resize:
+					if !ok {
+						return
+					}
+					data, err := json.Marshal(size)
+					if err != nil {
+						continue
+					}
+					if send(&messagingtypes.StreamFrame{Type: FrameResize, Data: data}) != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for {
+		frame, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("%s stream failed: %w", open.Kind, err)
+		}
+
+		var out io.Writer
+		switch frame.Type {
+		case FrameStdout:
+			out = opts.Stdout
+		case FrameStderr:
+			out = opts.Stderr
+		case FrameExit:
+			var exit Exit
+			if err := json.Unmarshal(frame.Data, &exit); err != nil {
+				return fmt.Errorf("invalid exit frame: %w", err)
+			}
+			return &podexec.ExitError{Code: exit.Code, Message: exit.Message}
+		case FrameError:
+			return errors.New(string(frame.Data))
+		}
+		if out == nil {
+			continue
+		}
+
+		if _, err := out.Write(frame.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// open sends the open frame and waits for the agent to be ready
+func (c *Client) open(ctx context.Context, open Open) (messagingtypes.Stream, error) {
+	data, err := json.Marshal(open)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal open frame: %w", err)
+	}
+
+	stream, err := c.streamer.OpenStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.Send(&messagingtypes.StreamFrame{Type: FrameOpen, Data: data}); err != nil {
+		return nil, fmt.Errorf("failed to open %s stream: %w", open.Kind, err)
+	}
+
+	frame, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s stream: %w", open.Kind, err)
+	}
+
+	switch frame.Type {
+	case FrameReady:
+	case FrameError:
+		return nil, fmt.Errorf("agent failed to open %s stream: %s", open.Kind, frame.Data)
+	default:
+		return nil, fmt.Errorf("unexpected %s frame from agent", frame.Type)
+	}
+
+	c.logger.Info("Opened proxied stream", "kind", open.Kind, "clusterID", open.ClusterID, "pod", open.Pod)
+	return stream, nil
+}
+
+// streamReader ends the stream when the reader is closed
+type streamReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (r *streamReader) Close() error {
+	r.cancel()
+	return r.PipeReader.Close()
+}
@@ -0,0 +1,231 @@
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/jbetancur/dashboard/internal/pkg/cluster"
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+	"github.com/jbetancur/dashboard/internal/pkg/podexec"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// chunkSize is the largest data frame the agent sends
+const chunkSize = 32 * 1024
+
+// Server serves streams on the agent from the clusters it is connected to
+type Server struct {
+	lookup func(clusterID string) (*cluster.Connection, bool)
+	logger *slog.Logger
+}
+
+// NewServer creates a stream server. lookup returns the agent's connection to a cluster.
+func NewServer(lookup func(clusterID string) (*cluster.Connection, bool), logger *slog.Logger) *Server {
+	return &Server{
+		lookup: lookup,
+		logger: logger,
+	}
+}
+
+// Serve handles one stream opened by the API. Failures are reported to the
+// API as an error frame.
+func (s *Server) Serve(stream messagingtypes.Stream) error {
+	frame, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	if frame.Type != FrameOpen {
+		return sendError(stream, fmt.Errorf("expected %s frame, got %s", FrameOpen, frame.Type))
+	}
+
+	var open Open
+	if err := json.Unmarshal(frame.Data, &open); err != nil {
+		return sendError(stream, fmt.Errorf("invalid open frame: %w", err))
+	}
+
+	conn, ok := s.lookup(open.ClusterID)
+	if !ok {
+		return sendError(stream, fmt.Errorf("cluster %s is not served by this agent", open.ClusterID))
+	}
+
+	s.logger.Info("Serving proxied stream",
+		"kind", open.Kind,
+		"clusterID", open.ClusterID,
+		"namespace", open.Namespace,
+		"pod", open.Pod,
+		"container", open.Container)
+
+	switch open.Kind {
+	case KindLogs:
+		err = s.serveLogs(stream, conn, open)
+	case KindExec, KindAttach:
+		err = s.serveInteractive(stream, conn, open)
+	default:
+		err = fmt.Errorf("unsupported stream kind %q", open.Kind)
+	}
+
+	if err != nil {
+		return sendError(stream, err)
+	}
+
+	return nil
+}
+
+// serveLogs copies a container's log stream to the API
+func (s *Server) serveLogs(stream messagingtypes.Stream, conn *cluster.Connection, open Open) error {
+	options := &corev1.PodLogOptions{
+		Container: open.Container,
+		Follow:    open.Follow,
+	}
+	if open.TailLines > 0 {
+		options.TailLines = &open.TailLines
+	}
+
+	logs, err := conn.Client.CoreV1().Pods(open.Namespace).GetLogs(open.Pod, options).Stream(stream.Context())
+	if err != nil {
+		return fmt.Errorf("failed to stream logs: %w", err)
+	}
+
+	defer func() {
+		if err := logs.Close(); err != nil {
+			s.logger.Debug("Failed to close log stream", "error", err)
+		}
+	}()
+
+	if err := stream.Send(&messagingtypes.StreamFrame{Type: FrameReady}); err != nil {
+		return err
+	}
+
+	buffer := make([]byte, chunkSize)
+	for {
+		n, err := logs.Read(buffer)
+		if n > 0 {
+			if sendErr := stream.Send(&messagingtypes.StreamFrame{Type: FrameData, Data: buffer[:n]}); sendErr != nil {
+				return sendErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read logs: %w", err)
+		}
+	}
+}
+
+// serveInteractive runs an exec or attach session against the cluster,
+// relaying input from the API and output back to it. Ready is sent before
+// connecting; a failed connection is reported as an error frame.
+func (s *Server) serveInteractive(stream messagingtypes.Stream, conn *cluster.Connection, open Open) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	opts := podexec.Options{
+		Container: open.Container,
+		Command:   open.Command,
+		TTY:       open.TTY,
+	}
+	if open.Stdout {
+		opts.Stdout = &frameWriter{stream: stream, frameType: FrameStdout}
+	}
+	if open.Stderr {
+		opts.Stderr = &frameWriter{stream: stream, frameType: FrameStderr}
+	}
+
+	stdin, stdinWriter := io.Pipe()
+	if open.Stdin {
+		opts.Stdin = stdin
+	}
+	resize := make(chan podexec.Size, 1)
+	if open.TTY {
+		opts.Resize = resize
+	}
+
+	if err := stream.Send(&messagingtypes.StreamFrame{Type: FrameReady}); err != nil {
+		return err
+	}
+
+	// Relay input until the API closes the stream
+	go func() {
+		defer cancel()
+		defer func() {
+			_ = stdinWriter.Close()
+		}()
+
+		for {
+			frame, err := stream.Recv()
+			if err != nil {
+				return
+			}
+
+			switch frame.Type {
+			case FrameStdin:
+				if _, err := stdinWriter.Write(frame.Data); err != nil {
+					return
+				}
+			case FrameClose:
+				_ = stdinWriter.Close()
+			case FrameResize:
+				var size podexec.Size
+				if json.Unmarshal(frame.Data, &size) != nil {
+					continue
+				}
+				select {
+				case resize <- size:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var err error
+	if open.Kind == KindExec {
+		err = podexec.Exec(ctx, conn.Config, open.Namespace, open.Pod, opts)
+	} else {
+		err = podexec.Attach(ctx, conn.Config, open.Namespace, open.Pod, opts)
+	}
+
+	var exitErr *podexec.ExitError
+	if errors.As(err, &exitErr) {
+		data, err := json.Marshal(Exit{Code: exitErr.Code, Message: exitErr.Message})
+		if err != nil {
+			return err
+		}
+		return stream.Send(&messagingtypes.StreamFrame{Type: FrameExit, Data: data})
+	}
+
+	return err
+}
+
+// frameWriter sends what is written to it as frames of one type. Output is
+// written from a single goroutine, so sends don't overlap.
+type frameWriter struct {
+	stream    messagingtypes.Stream
+	frameType string
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	for start := 0; start < len(p); start += chunkSize {
+		end := min(start+chunkSize, len(p))
+		data := append([]byte(nil), p[start:end]...)
+		if err := w.stream.Send(&messagingtypes.StreamFrame{Type: w.frameType, Data: data}); err != nil {
+			return start, err
+		}
+	}
+	return len(p), nil
+}
+
+// sendError reports a failure to the API and ends the stream
+func sendError(stream messagingtypes.Stream, err error) error {
+	if sendErr := stream.Send(&messagingtypes.StreamFrame{Type: FrameError, Data: []byte(err.Error())}); sendErr != nil {
+		return sendErr
+	}
+	return nil
+}
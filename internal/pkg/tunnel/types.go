@@ -0,0 +1,59 @@
+package tunnel
+
+// Frame types. The API opens a stream with an open frame; the agent answers
+// with ready once the upstream stream is established, then data frames, and
+// an error frame if it fails. A stream ends when the agent returns.
+//
+// Exec and attach streams carry stdout and stderr frames instead of data.
+// The API sends stdin, close (end of stdin) and resize frames, and the agent
+// sends an exit frame when the command exits with a non-zero code.
+const (
+	FrameOpen   = "open"
+	FrameReady  = "ready"
+	FrameData   = "data"
+	FrameError  = "error"
+	FrameStdin  = "stdin"
+	FrameClose  = "close"
+	FrameResize = "resize"
+	FrameStdout = "stdout"
+	FrameStderr = "stderr"
+	FrameExit   = "exit"
+)
+
+// Stream kinds
+const (
+	KindLogs   = "logs"
+	KindExec   = "exec"
+	KindAttach = "attach"
+)
+
+// Config selects the clusters whose streams go through their agent
+type Config struct {
+	// Clusters always have their log, exec and attach streams proxied.
+	// Other clusters are proxied only while
+	// the health monitor reports them unhealthy.
+	Clusters []string `yaml:"clusters"`
+}
+
+// Open describes the stream the API asks the agent for
+type Open struct {
+	Kind      string `json:"kind"`
+	ClusterID string `json:"clusterID"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	TailLines int64  `json:"tailLines,omitempty"`
+	Follow    bool   `json:"follow"`
+	// Command, TTY and the requested streams apply to exec and attach
+	Command []string `json:"command,omitempty"`
+	TTY     bool     `json:"tty,omitempty"`
+	Stdin   bool     `json:"stdin,omitempty"`
+	Stdout  bool     `json:"stdout,omitempty"`
+	Stderr  bool     `json:"stderr,omitempty"`
+}
+
+// Exit is the payload of an exit frame
+type Exit struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,430 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	messagingtypes "github.com/jbetancur/dashboard/internal/pkg/messaging/types"
+)
+
+const (
+	// workers is the number of concurrent deliveries
+	workers = 4
+	// queueSize bounds the deliveries waiting for a worker; the rest are
+	// picked up by the retry loop
+	queueSize = 256
+	// pollInterval is how often due retries and webhook changes are loaded
+	pollInterval = 15 * time.Second
+)
+
+// Signature headers. The signature is the hex HMAC-SHA256 of the timestamp,
+// a dot and the body, keyed with the webhook's secret.
+const (
+	HeaderEvent     = "X-Dashboard-Event"
+	HeaderDelivery  = "X-Dashboard-Delivery"
+	HeaderTimestamp = "X-Dashboard-Timestamp"
+	HeaderSignature = "X-Dashboard-Signature"
+)
+
+// Dispatcher turns agent events into webhook deliveries and sends them
+type Dispatcher struct {
+	store  Repository
+	config Config
+	client *http.Client
+	logger *slog.Logger
+	queue  chan Delivery
+
+	mu       sync.RWMutex
+	webhooks map[string]Webhook
+	inflight map[string]bool
+}
+
+// NewDispatcher creates a new dispatcher, filling in defaults for unset settings
+func NewDispatcher(store Repository, config Config, logger *slog.Logger) *Dispatcher {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = 30 * time.Second
+	}
+	if config.Retention <= 0 {
+		config.Retention = 7 * 24 * time.Hour
+	}
+
+	return &Dispatcher{
+		store:    store,
+		config:   config,
+		client:   &http.Client{Timeout: config.Timeout},
+		logger:   logger,
+		queue:    make(chan Delivery, queueSize),
+		webhooks: make(map[string]Webhook),
+		inflight: make(map[string]bool),
+	}
+}
+
+// Subscribe registers a handler for every event type webhooks can receive
+func (d *Dispatcher) Subscribe(subscriber messagingtypes.Subscriber) {
+	for _, eventType := range EventTypes {
+		subscriber.Subscribe(eventType, func(message []byte) error {
+			return d.handle(eventType, message)
+		})
+	}
+}
+
+// Start sends queued deliveries, retries due ones and prunes old records
+// until the context is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	if err := d.Reload(ctx); err != nil {
+		d.logger.Error("Failed to load webhooks", "error", err)
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case delivery := <-d.queue:
+					d.attempt(ctx, &delivery)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		lastPrune := time.Time{}
+		for {
+			select {
+			case <-ticker.C:
+				if err := d.Reload(ctx); err != nil {
+					d.logger.Error("Failed to load webhooks", "error", err)
+				}
+				d.retryDue(ctx)
+
+				if time.Since(lastPrune) >= time.Hour {
+					lastPrune = time.Now()
+					if err := d.store.DeleteWebhookDeliveriesBefore(ctx, lastPrune.Add(-d.config.Retention)); err != nil {
+						d.logger.Error("Failed to prune webhook deliveries", "error", err)
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	d.logger.Info("Webhook delivery started", "maxAttempts", d.config.MaxAttempts, "retryBackoff", d.config.RetryBackoff)
+}
+
+// Reload refreshes the webhooks events are matched against
+func (d *Dispatcher) Reload(ctx context.Context) error {
+	var list []Webhook
+	if err := d.store.ListWebhooks(ctx, &list); err != nil {
+		return err
+	}
+
+	webhooks := make(map[string]Webhook, len(list))
+	for _, webhook := range list {
+		webhooks[webhook.ID] = webhook
+	}
+
+	d.mu.Lock()
+	d.webhooks = webhooks
+	d.mu.Unlock()
+
+	return nil
+}
+
+// Redeliver sends a delivery again right away, whatever its status, and
+// records the outcome
+func (d *Dispatcher) Redeliver(ctx context.Context, delivery *Delivery) error {
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Time{}
+
+	if !d.claim(delivery.ID) {
+		return fmt.Errorf("delivery %s is already being sent", delivery.ID)
+	}
+	d.send(ctx, delivery)
+
+	if delivery.Status != StatusDelivered {
+		return fmt.Errorf("%s", delivery.Error)
+	}
+	return nil
+}
+
+// Test posts a sample event to a webhook without recording a delivery
+func (d *Dispatcher) Test(ctx context.Context, webhook Webhook) error {
+	event := Event{
+		ID:        newID(),
+		Type:      "ping",
+		Timestamp: time.Now().UTC(),
+		Name:      webhook.Name,
+		Resource:  json.RawMessage("{}"),
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.post(ctx, webhook, event.Type, event.ID, payload)
+	return err
+}
+
+// handle records a delivery of an event for every webhook that subscribes to it
+func (d *Dispatcher) handle(eventType string, message []byte) error {
+	event, err := parseEvent(eventType, message)
+	if err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	matched := make([]Webhook, 0)
+	for _, webhook := range d.webhooks {
+		if webhook.Matches(event) {
+			matched = append(matched, webhook)
+		}
+	}
+	d.mu.RUnlock()
+
+	if len(matched) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.Timeout)
+	defer cancel()
+
+	for _, webhook := range matched {
+		delivery := Delivery{
+			WebhookID:     webhook.ID,
+			Event:         event.Type,
+			ClusterID:     event.ClusterID,
+			Namespace:     event.Namespace,
+			Name:          event.Name,
+			Payload:       string(payload),
+			Status:        StatusPending,
+			CreatedAt:     event.Timestamp,
+			NextAttemptAt: event.Timestamp,
+		}
+		if err := d.store.SaveWebhookDelivery(ctx, &delivery); err != nil {
+			d.logger.Error("Failed to record webhook delivery", "webhook", webhook.Name, "event", event.Type, "error", err)
+			continue
+		}
+
+		d.enqueue(delivery)
+	}
+
+	return nil
+}
+
+// enqueue hands a delivery to a worker without blocking. Deliveries that do
+// not fit are sent by the retry loop.
+func (d *Dispatcher) enqueue(delivery Delivery) {
+	if !d.claim(delivery.ID) {
+		return
+	}
+
+	select {
+	case d.queue <- delivery:
+	default:
+		d.release(delivery.ID)
+		d.logger.Debug("Webhook queue full, deferring delivery", "delivery", delivery.ID)
+	}
+}
+
+// retryDue queues pending deliveries whose next attempt has come
+func (d *Dispatcher) retryDue(ctx context.Context) {
+	var due []Delivery
+	if err := d.store.ListDueWebhookDeliveries(ctx, time.Now(), &due); err != nil {
+		d.logger.Error("Failed to load due webhook deliveries", "error", err)
+		return
+	}
+
+	for _, delivery := range due {
+		d.enqueue(delivery)
+	}
+}
+
+// attempt sends a claimed delivery from the queue
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery) {
+	d.send(ctx, delivery)
+	if delivery.Status == StatusFailed {
+		d.logger.Warn("Webhook delivery failed", "delivery", delivery.ID, "webhook", delivery.WebhookID, "event", delivery.Event, "attempts", delivery.Attempts, "error", delivery.Error)
+	}
+}
+
+// send posts a claimed delivery, schedules a retry or marks it failed, saves
+// the outcome and releases the claim
+func (d *Dispatcher) send(ctx context.Context, delivery *Delivery) {
+	defer d.release(delivery.ID)
+
+	d.mu.RLock()
+	webhook, ok := d.webhooks[delivery.WebhookID]
+	d.mu.RUnlock()
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastAttemptAt = now
+
+	var (
+		statusCode int
+		err        error
+	)
+	if ok {
+		statusCode, err = d.post(ctx, webhook, delivery.Event, delivery.ID, []byte(delivery.Payload))
+	} else {
+		err = fmt.Errorf("webhook %s no longer exists", delivery.WebhookID)
+		delivery.Attempts = d.config.MaxAttempts
+	}
+
+	delivery.StatusCode = statusCode
+	delivery.Error = ""
+	delivery.NextAttemptAt = time.Time{}
+
+	switch {
+	case err == nil:
+		delivery.Status = StatusDelivered
+	case delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = StatusFailed
+		delivery.Error = err.Error()
+	default:
+		delivery.Status = StatusPending
+		delivery.Error = err.Error()
+		delivery.NextAttemptAt = now.Add(d.config.RetryBackoff << (delivery.Attempts - 1))
+	}
+
+	if err := d.store.SaveWebhookDelivery(ctx, delivery); err != nil {
+		d.logger.Error("Failed to record webhook delivery", "delivery", delivery.ID, "error", err)
+	}
+}
+
+// post sends a signed payload and returns the response status code
+func (d *Dispatcher) post(ctx context.Context, webhook Webhook, eventType, deliveryID string, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if webhook.Secret != "" {
+		req.Header.Set(HeaderSignature, "sha256="+Sign(webhook.Secret, timestamp, payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post event: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return resp.StatusCode, nil
+}
+
+// claim marks a delivery as being sent, returning false when it already is
+func (d *Dispatcher) claim(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.inflight[id] {
+		return false
+	}
+	d.inflight[id] = true
+	return true
+}
+
+// release clears the claim on a delivery
+func (d *Dispatcher) release(id string) {
+	d.mu.Lock()
+	delete(d.inflight, id)
+	d.mu.Unlock()
+}
+
+// Sign returns the hex HMAC-SHA256 of a timestamp and body, which receivers
+// compare with the signature header
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseEvent builds an event from a message agents publish
+func parseEvent(eventType string, message []byte) (*Event, error) {
+	var payload struct {
+		ClusterID      string            `json:"cluster_id"`
+		Resource       json.RawMessage   `json:"resource"`
+		PreviousImages map[string]string `json:"previous_images"`
+		Images         map[string]string `json:"images"`
+	}
+	if err := json.Unmarshal(message, &payload); err != nil {
+		return nil, fmt.Errorf("invalid %s event: %w", eventType, err)
+	}
+
+	var resource struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(payload.Resource, &resource); err != nil {
+		return nil, fmt.Errorf("invalid %s resource: %w", eventType, err)
+	}
+
+	return &Event{
+		ID:             newID(),
+		Type:           eventType,
+		Timestamp:      time.Now().UTC(),
+		ClusterID:      payload.ClusterID,
+		Namespace:      resource.Metadata.Namespace,
+		Name:           resource.Metadata.Name,
+		Resource:       payload.Resource,
+		PreviousImages: payload.PreviousImages,
+		Images:         payload.Images,
+	}, nil
+}
+
+// newID returns a random event ID
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,151 @@
+// Package webhooks posts signed resource lifecycle events to user-defined
+// URLs, retrying failed deliveries and keeping their status.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Event types a webhook can subscribe to. They are the topics agents
+// publish resource changes on.
+const (
+	EventPodAdded               = "pod_added"
+	EventPodDeleted             = "pod_deleted"
+	EventNamespaceAdded         = "namespace_added"
+	EventNamespaceDeleted       = "namespace_deleted"
+	EventConfigMapUpdated       = "config_map_updated"
+	EventConfigMapDeleted       = "config_map_deleted"
+	EventDeploymentAdded        = "deployment_added"
+	EventDeploymentDeleted      = "deployment_deleted"
+	EventDeploymentImageChanged = "deployment_image_changed"
+)
+
+// EventTypes lists every event type webhooks can subscribe to
+var EventTypes = []string{
+	EventPodAdded,
+	EventPodDeleted,
+	EventNamespaceAdded,
+	EventNamespaceDeleted,
+	EventConfigMapUpdated,
+	EventConfigMapDeleted,
+	EventDeploymentAdded,
+	EventDeploymentDeleted,
+	EventDeploymentImageChanged,
+}
+
+// Delivery states
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Config controls webhook delivery
+type Config struct {
+	// Timeout bounds a single delivery attempt
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxAttempts is how many times a delivery is tried before it is failed
+	MaxAttempts int `yaml:"maxAttempts"`
+	// RetryBackoff is the wait before the first retry, doubled after each attempt
+	RetryBackoff time.Duration `yaml:"retryBackoff"`
+	// Retention is how long delivery records are kept
+	Retention time.Duration `yaml:"retention"`
+}
+
+// Webhook posts matching events to a URL. Empty match lists match everything.
+type Webhook struct {
+	ID         string            `json:"id" bson:"_id,omitempty"`
+	Name       string            `json:"name" bson:"name"`
+	URL        string            `json:"url" bson:"url"`
+	Events     []string          `json:"events" bson:"events"`
+	Clusters   []string          `json:"clusters,omitempty" bson:"clusters,omitempty"`
+	Namespaces []string          `json:"namespaces,omitempty" bson:"namespaces,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	// Secret signs payloads. It is never returned by the API.
+	Secret   string `json:"secret,omitempty" bson:"secret,omitempty"`
+	Disabled bool   `json:"disabled,omitempty" bson:"disabled,omitempty"`
+}
+
+// Validate checks that a webhook has a URL and known event types
+func (w *Webhook) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("webhook requires a name")
+	}
+
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("webhook requires an http or https url")
+	}
+
+	if len(w.Events) == 0 {
+		return fmt.Errorf("webhook requires at least one event type")
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(EventTypes, event) {
+			return fmt.Errorf("unsupported event type %q", event)
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether w subscribes to an event
+func (w *Webhook) Matches(event *Event) bool {
+	return !w.Disabled &&
+		slices.Contains(w.Events, event.Type) &&
+		matches(w.Clusters, event.ClusterID) &&
+		matches(w.Namespaces, event.Namespace)
+}
+
+// Event is the JSON payload posted to webhooks
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	ClusterID string    `json:"clusterID"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	// Resource is the object as the agent reported it
+	Resource json.RawMessage `json:"resource"`
+	// PreviousImages and Images are set for deployment image changes
+	PreviousImages map[string]string `json:"previousImages,omitempty"`
+	Images         map[string]string `json:"images,omitempty"`
+}
+
+// Delivery is the record of posting an event to a webhook
+type Delivery struct {
+	ID        string `json:"id" bson:"_id,omitempty"`
+	WebhookID string `json:"webhookID" bson:"webhook_id"`
+	Event     string `json:"event" bson:"event"`
+	ClusterID string `json:"clusterID" bson:"cluster_id"`
+	Namespace string `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Name      string `json:"name" bson:"name"`
+	// Payload is the exact body posted, so retries carry the same signature input
+	Payload       string    `json:"payload" bson:"payload"`
+	Status        string    `json:"status" bson:"status"`
+	Attempts      int       `json:"attempts" bson:"attempts"`
+	StatusCode    int       `json:"statusCode,omitempty" bson:"status_code,omitempty"`
+	Error         string    `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt     time.Time `json:"createdAt" bson:"created_at"`
+	LastAttemptAt time.Time `json:"lastAttemptAt,omitempty" bson:"last_attempt_at,omitempty"`
+	NextAttemptAt time.Time `json:"nextAttemptAt,omitempty" bson:"next_attempt_at,omitempty"`
+}
+
+// Repository persists webhooks and their deliveries
+type Repository interface {
+	ListWebhooks(ctx context.Context, results *[]Webhook) error
+	SaveWebhookDelivery(ctx context.Context, delivery *Delivery) error
+	// ListDueWebhookDeliveries returns pending deliveries whose next attempt is at or before now
+	ListDueWebhookDeliveries(ctx context.Context, now time.Time, results *[]Delivery) error
+	DeleteWebhookDeliveriesBefore(ctx context.Context, before time.Time) error
+}
+
+// matches reports whether value is allowed by a match list
+func matches(allowed []string, value string) bool {
+	return len(allowed) == 0 || slices.Contains(allowed, value)
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"github.com/jbetancur/dashboard/internal/pkg/providers/aks"
+)
+
+// New is the exported function required by the plugin system
+func New(config map[string]string, logger *slog.Logger) (providers.Provider, error) {
+	return aks.New(config, logger)
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/jbetancur/dashboard/internal/pkg/providers"
+	"github.com/jbetancur/dashboard/internal/pkg/providers/fleet"
+)
+
+// New is the exported function required by the plugin system
+func New(config map[string]string, logger *slog.Logger) (providers.Provider, error) {
+	return fleet.New(config, logger)
+}